@@ -80,3 +80,9 @@ type AESCBCPKCS7ModeOpts struct {
 	// It is used only if different from nil.
 	PRNG io.Reader
 }
+
+// AESGCMModeOpts contains options for AES encryption in GCM mode, which
+// authenticates the ciphertext along with encrypting it.
+// The nonce is sampled using a cryptographic secure PRNG and prepended
+// to the ciphertext.
+type AESGCMModeOpts struct{}
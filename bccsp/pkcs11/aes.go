@@ -0,0 +1,31 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pkcs11
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/bccsp"
+)
+
+func (csp *impl) encryptAES(k *aesPrivateKey, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	switch opts.(type) {
+	case *bccsp.AESGCMModeOpts, bccsp.AESGCMModeOpts:
+		return csp.encryptP11AESGCM(k.ski, plaintext)
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
+
+func (csp *impl) decryptAES(k *aesPrivateKey, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+	switch opts.(type) {
+	case *bccsp.AESGCMModeOpts, bccsp.AESGCMModeOpts:
+		return csp.decryptP11AESGCM(k.ski, ciphertext)
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+}
@@ -107,6 +107,14 @@ func (csp *impl) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err error) {
 
 		k = &ecdsaPrivateKey{ski, ecdsaPublicKey{ski, pub}}
 
+	case *bccsp.AES256KeyGenOpts:
+		ski, err := csp.generateAESKey(opts.Ephemeral())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed generating AES 256 key")
+		}
+
+		k = &aesPrivateKey{ski}
+
 	default:
 		return csp.BCCSP.KeyGen(opts)
 	}
@@ -161,6 +169,9 @@ func (csp *impl) GetKey(ski []byte) (bccsp.Key, error) {
 		}
 		return &ecdsaPublicKey{ski, pubKey}, nil
 	}
+	if err := csp.getAESKey(ski); err == nil {
+		return &aesPrivateKey{ski}, nil
+	}
 	return csp.BCCSP.GetKey(ski)
 }
 
@@ -215,14 +226,35 @@ func (csp *impl) Verify(k bccsp.Key, signature, digest []byte, opts bccsp.Signer
 // Encrypt encrypts plaintext using key k.
 // The opts argument should be appropriate for the primitive used.
 func (csp *impl) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
-	// TODO: Add PKCS11 support for encryption, when fabric starts requiring it
-	return csp.BCCSP.Encrypt(k, plaintext, opts)
+	// Validate arguments
+	if k == nil {
+		return nil, errors.New("Invalid Key. It must not be nil")
+	}
+
+	// Check key type
+	switch k.(type) {
+	case *aesPrivateKey:
+		return csp.encryptAES(k.(*aesPrivateKey), plaintext, opts)
+	default:
+		return csp.BCCSP.Encrypt(k, plaintext, opts)
+	}
 }
 
 // Decrypt decrypts ciphertext using key k.
 // The opts argument should be appropriate for the primitive used.
 func (csp *impl) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
-	return csp.BCCSP.Decrypt(k, ciphertext, opts)
+	// Validate arguments
+	if k == nil {
+		return nil, errors.New("Invalid Key. It must not be nil")
+	}
+
+	// Check key type
+	switch k.(type) {
+	case *aesPrivateKey:
+		return csp.decryptAES(k.(*aesPrivateKey), ciphertext, opts)
+	default:
+		return csp.BCCSP.Decrypt(k, ciphertext, opts)
+	}
 }
 
 // FindPKCS11Lib IS ONLY USED FOR TESTING
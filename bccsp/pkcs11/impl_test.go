@@ -1290,6 +1290,56 @@ func TestAES256KeyGenSKI(t *testing.T) {
 
 }
 
+func TestP11AESKeyGen(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping TestP11AESKeyGen")
+	}
+	k, err := currentBCCSP.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: false})
+	assert.NoError(t, err)
+	assert.IsType(t, &aesPrivateKey{}, k)
+	assert.True(t, k.Private())
+	assert.True(t, k.Symmetric())
+	_, err = k.Bytes()
+	assert.Error(t, err, "the AES keys of the HSM cannot be exported")
+
+	k2, err := currentBCCSP.GetKey(k.SKI())
+	assert.NoError(t, err)
+	assert.IsType(t, &aesPrivateKey{}, k2)
+	assert.Equal(t, k.SKI(), k2.SKI())
+
+	k, err = currentBCCSP.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: true})
+	assert.NoError(t, err)
+	assert.IsType(t, &aesPrivateKey{}, k)
+	assert.NotEqual(t, k.SKI(), k2.SKI())
+}
+
+func TestP11AESGCMEncryptDecrypt(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping TestP11AESGCMEncryptDecrypt")
+	}
+	k, err := currentBCCSP.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: false})
+	assert.NoError(t, err)
+
+	msg := []byte("Hello World")
+	ct, err := currentBCCSP.Encrypt(k, msg, &bccsp.AESGCMModeOpts{})
+	assert.NoError(t, err)
+	assert.Len(t, ct, gcmNonceSize+len(msg)+gcmTagSize)
+
+	pt, err := currentBCCSP.Decrypt(k, ct, &bccsp.AESGCMModeOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, msg, pt)
+
+	ct[len(ct)-1] ^= 1
+	_, err = currentBCCSP.Decrypt(k, ct, &bccsp.AESGCMModeOpts{})
+	assert.Error(t, err, "the tampered ciphertext should not be authenticated")
+
+	_, err = currentBCCSP.Decrypt(k, ct[:gcmNonceSize], &bccsp.AESGCMModeOpts{})
+	assert.EqualError(t, err, "Invalid ciphertext. It is too short")
+
+	_, err = currentBCCSP.Encrypt(k, msg, &bccsp.AESCBCPKCS7ModeOpts{})
+	assert.Error(t, err, "only the GCM mode is supported with the AES keys of the HSM")
+}
+
 func TestSHA(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping TestSHA")
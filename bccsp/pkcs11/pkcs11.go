@@ -9,6 +9,7 @@ package pkcs11
 import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/asn1"
 	"encoding/hex"
@@ -390,6 +391,123 @@ func (csp *impl) verifyP11ECDSA(ski []byte, msg []byte, R, S *big.Int, byteSize
 	return true, nil
 }
 
+// generateAESKey generates a 256 bit AES key in the HSM, which cannot be extracted from it.
+// As its value cannot be hashed, the SKI of the key is random
+func (csp *impl) generateAESKey(ephemeral bool) (ski []byte, err error) {
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	ski = make([]byte, 32)
+	if _, err := rand.Read(ski); err != nil {
+		return nil, fmt.Errorf("Could not generate SKI [%s]", err)
+	}
+
+	keyT := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+
+		pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, hex.EncodeToString(ski)),
+
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_MODIFIABLE, !csp.immutable),
+	}
+
+	_, err = p11lib.GenerateKey(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)},
+		keyT)
+	if err != nil {
+		return nil, fmt.Errorf("P11: AES key generate failed [%s]", err)
+	}
+
+	logger.Infof("Generated new P11 AES key, SKI %x\n", ski)
+	return ski, nil
+}
+
+// Look for an AES key by SKI, stored in CKA_ID
+func (csp *impl) getAESKey(ski []byte) error {
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	_, err := findSecretKeyFromSKI(p11lib, session, ski)
+	return err
+}
+
+// encryptP11AESGCM encrypts and authenticates msg in GCM mode with an AES key of the HSM.
+// As with the SW BCCSP, the randomly sampled nonce is prepended to the ciphertext
+func (csp *impl) encryptP11AESGCM(ski []byte, msg []byte) ([]byte, error) {
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	secretKey, err := findSecretKeyFromSKI(p11lib, session, ski)
+	if err != nil {
+		return nil, fmt.Errorf("Secret key not found [%s]", err)
+	}
+
+	nonce := make([]byte, gcmNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("Could not generate nonce [%s]", err)
+	}
+	params := pkcs11.NewGCMParams(nonce, nil, gcmTagSize*8)
+	defer params.Free()
+
+	err = p11lib.EncryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}, *secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("PKCS11: Encrypt-initialize [%s]", err)
+	}
+	ciphertext, err := p11lib.Encrypt(session, msg)
+	if err != nil {
+		return nil, fmt.Errorf("PKCS11: Encrypt failed [%s]", err)
+	}
+
+	return append(nonce, ciphertext...), nil
+}
+
+// decryptP11AESGCM authenticates and decrypts msg, which is prefixed by its nonce, with
+// an AES key of the HSM
+func (csp *impl) decryptP11AESGCM(ski []byte, msg []byte) ([]byte, error) {
+	if len(msg) < gcmNonceSize+gcmTagSize {
+		return nil, fmt.Errorf("Invalid ciphertext. It is too short")
+	}
+
+	p11lib := csp.ctx
+	session := csp.getSession()
+	defer csp.returnSession(session)
+
+	secretKey, err := findSecretKeyFromSKI(p11lib, session, ski)
+	if err != nil {
+		return nil, fmt.Errorf("Secret key not found [%s]", err)
+	}
+
+	params := pkcs11.NewGCMParams(msg[:gcmNonceSize], nil, gcmTagSize*8)
+	defer params.Free()
+
+	err = p11lib.DecryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_GCM, params)}, *secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("PKCS11: Decrypt-initialize [%s]", err)
+	}
+	plaintext, err := p11lib.Decrypt(session, msg[gcmNonceSize:])
+	if err != nil {
+		return nil, fmt.Errorf("PKCS11: Decrypt failed [%s]", err)
+	}
+
+	return plaintext, nil
+}
+
+const (
+	gcmNonceSize = 12
+	gcmTagSize   = 16
+)
+
 const (
 	privateKeyFlag = true
 	publicKeyFlag  = false
@@ -425,6 +543,30 @@ func findKeyPairFromSKI(mod *pkcs11.Ctx, session pkcs11.SessionHandle, ski []byt
 	return &objs[0], nil
 }
 
+func findSecretKeyFromSKI(mod *pkcs11.Ctx, session pkcs11.SessionHandle, ski []byte) (*pkcs11.ObjectHandle, error) {
+	template := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_ID, ski),
+	}
+	if err := mod.FindObjectsInit(session, template); err != nil {
+		return nil, err
+	}
+
+	objs, _, err := mod.FindObjects(session, 1)
+	if err != nil {
+		return nil, err
+	}
+	if err = mod.FindObjectsFinal(session); err != nil {
+		return nil, err
+	}
+
+	if len(objs) == 0 {
+		return nil, fmt.Errorf("Key not found [%s]", hex.Dump(ski))
+	}
+
+	return &objs[0], nil
+}
+
 // Fairly straightforward EC-point query, other than opencryptoki
 // mis-reporting length, including the 04 Tag of the field following
 // the SPKI in EP11-returned MACed publickeys:
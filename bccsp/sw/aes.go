@@ -180,6 +180,44 @@ func AESCBCPKCS7Decrypt(key, src []byte) ([]byte, error) {
 	return nil, err
 }
 
+// AESGCMEncrypt encrypts and authenticates src in GCM mode, prepending the
+// randomly sampled nonce to the ciphertext
+func AESGCMEncrypt(key, src []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(src)+gcm.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, src, nil), nil
+}
+
+// AESGCMDecrypt authenticates and decrypts src, which is prefixed by its nonce
+func AESGCMDecrypt(key, src []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(src) < gcm.NonceSize()+gcm.Overhead() {
+		return nil, errors.New("Invalid ciphertext. It is shorter than the nonce and the tag")
+	}
+
+	return gcm.Open(nil, src[:gcm.NonceSize()], src[gcm.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
 type aescbcpkcs7Encryptor struct{}
 
 func (e *aescbcpkcs7Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
@@ -202,6 +240,9 @@ func (e *aescbcpkcs7Encryptor) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp
 		return AESCBCPKCS7Encrypt(k.(*aesPrivateKey).privKey, plaintext)
 	case bccsp.AESCBCPKCS7ModeOpts:
 		return e.Encrypt(k, plaintext, &o)
+	case *bccsp.AESGCMModeOpts, bccsp.AESGCMModeOpts:
+		// AES in GCM mode
+		return AESGCMEncrypt(k.(*aesPrivateKey).privKey, plaintext)
 	default:
 		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
 	}
@@ -215,6 +256,9 @@ func (*aescbcpkcs7Decryptor) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.
 	case *bccsp.AESCBCPKCS7ModeOpts, bccsp.AESCBCPKCS7ModeOpts:
 		// AES in CBC mode with PKCS7 padding
 		return AESCBCPKCS7Decrypt(k.(*aesPrivateKey).privKey, ciphertext)
+	case *bccsp.AESGCMModeOpts, bccsp.AESGCMModeOpts:
+		// AES in GCM mode
+		return AESGCMDecrypt(k.(*aesPrivateKey).privKey, ciphertext)
 	default:
 		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
 	}
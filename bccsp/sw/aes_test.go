@@ -677,3 +677,41 @@ func TestAESCBCPKCS7EncryptorWithRandSameCiphertext(t *testing.T) {
 
 	assert.Equal(t, ct, ct2)
 }
+
+// TestAESGCMEncryptorDecrypt tests the encryption and decryption in GCM mode
+// of aescbcpkcs7Encryptor and aescbcpkcs7Decryptor
+func TestAESGCMEncryptorDecrypt(t *testing.T) {
+	t.Parallel()
+
+	raw, err := GetRandomBytes(32)
+	assert.NoError(t, err)
+
+	k := &aesPrivateKey{privKey: raw, exportable: false}
+
+	msg := []byte("Hello World")
+	encryptor := &aescbcpkcs7Encryptor{}
+	decryptor := &aescbcpkcs7Decryptor{}
+
+	ct, err := encryptor.Encrypt(k, msg, bccsp.AESGCMModeOpts{})
+	assert.NoError(t, err)
+
+	ct2, err := encryptor.Encrypt(k, msg, &bccsp.AESGCMModeOpts{})
+	assert.NoError(t, err)
+	assert.NotEqual(t, ct, ct2)
+
+	msg2, err := decryptor.Decrypt(k, ct, &bccsp.AESGCMModeOpts{})
+	assert.NoError(t, err)
+	assert.Equal(t, msg, msg2)
+
+	// A tampered ciphertext is not authenticated
+	ct[len(ct)-1] ^= 1
+	_, err = decryptor.Decrypt(k, ct, &bccsp.AESGCMModeOpts{})
+	assert.EqualError(t, err, "cipher: message authentication failed")
+
+	_, err = decryptor.Decrypt(k, ct[:10], &bccsp.AESGCMModeOpts{})
+	assert.EqualError(t, err, "Invalid ciphertext. It is shorter than the nonce and the tag")
+
+	other := &aesPrivateKey{privKey: make([]byte, 32), exportable: false}
+	_, err = decryptor.Decrypt(other, ct2, &bccsp.AESGCMModeOpts{})
+	assert.Error(t, err)
+}
@@ -22,14 +22,15 @@ const (
 	// A Kafka-based Ordering Service Node requires this in order to receive and process a config update with
 	// consensus-type migration commands. Migration is supported from Kafka to Raft only.
 	// If not present, these config updates will be rejected.
+	// It also defines whether the orderer supports configuring how blocks are cut.
 	OrdererV2_0 = "V2_0"
 )
 
 // OrdererProvider provides capabilities information for orderer level config.
 type OrdererProvider struct {
 	*registry
-	v11BugFixes   bool
-	kafka2RaftMig bool
+	v11BugFixes bool
+	v20         bool
 }
 
 // NewOrdererProvider creates an orderer capabilities provider.
@@ -37,7 +38,7 @@ func NewOrdererProvider(capabilities map[string]*cb.Capability) *OrdererProvider
 	cp := &OrdererProvider{}
 	cp.registry = newRegistry(cp, capabilities)
 	_, cp.v11BugFixes = capabilities[OrdererV1_1]
-	_, cp.kafka2RaftMig = capabilities[OrdererV2_0]
+	_, cp.v20 = capabilities[OrdererV2_0]
 	return cp
 }
 
@@ -79,5 +80,10 @@ func (cp *OrdererProvider) ExpirationCheck() bool {
 
 // Kafka2RaftMigration checks whether the orderer permits a Kafka to Raft migration.
 func (cp *OrdererProvider) Kafka2RaftMigration() bool {
-	return cp.kafka2RaftMig
+	return cp.v20
+}
+
+// FairBlockCutting checks whether the orderer permits to configure how blocks are cut.
+func (cp *OrdererProvider) FairBlockCutting() bool {
+	return cp.v20
 }
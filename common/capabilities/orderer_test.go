@@ -21,6 +21,7 @@ func TestOrdererV10(t *testing.T) {
 	assert.False(t, op.Resubmission())
 	assert.False(t, op.ExpirationCheck())
 	assert.False(t, op.Kafka2RaftMigration())
+	assert.False(t, op.FairBlockCutting())
 }
 
 func TestOrdererV11(t *testing.T) {
@@ -32,6 +33,7 @@ func TestOrdererV11(t *testing.T) {
 	assert.True(t, op.Resubmission())
 	assert.True(t, op.ExpirationCheck())
 	assert.False(t, op.Kafka2RaftMigration())
+	assert.False(t, op.FairBlockCutting())
 }
 
 func TestOrdererV20(t *testing.T) {
@@ -43,6 +45,7 @@ func TestOrdererV20(t *testing.T) {
 	assert.True(t, op.Resubmission())
 	assert.True(t, op.ExpirationCheck())
 	assert.True(t, op.Kafka2RaftMigration())
+	assert.True(t, op.FairBlockCutting())
 }
 
 func TestNotSuported(t *testing.T) {
@@ -106,6 +106,9 @@ type Orderer interface {
 	// used for ordering
 	KafkaBrokers() []string
 
+	// BlockCutting returns how the pending transactions are cut into blocks
+	BlockCutting() *ab.BlockCutting
+
 	// Organizations returns the organizations for the ordering service
 	Organizations() map[string]Org
 
@@ -190,6 +193,9 @@ type OrdererCapabilities interface {
 
 	// Kafka2RaftMigration checks whether the orderer permits a Kafka to Raft migration.
 	Kafka2RaftMigration() bool
+
+	// FairBlockCutting checks whether the orderer permits to configure how blocks are cut.
+	FairBlockCutting() bool
 }
 
 // PolicyMapper is an interface for
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/capabilities"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -39,6 +40,9 @@ const (
 
 	// KafkaBrokersKey is the cb.ConfigItem type key name for the KafkaBrokers message.
 	KafkaBrokersKey = "KafkaBrokers"
+
+	// BlockCuttingKey is the cb.ConfigItem type key name for the BlockCutting message.
+	BlockCuttingKey = "BlockCutting"
 )
 
 // OrdererProtos is used as the source of the OrdererConfig.
@@ -48,6 +52,7 @@ type OrdererProtos struct {
 	BatchTimeout        *ab.BatchTimeout
 	KafkaBrokers        *ab.KafkaBrokers
 	ChannelRestrictions *ab.ChannelRestrictions
+	BlockCutting        *ab.BlockCutting
 	Capabilities        *cb.Capabilities
 }
 
@@ -125,6 +130,11 @@ func (oc *OrdererConfig) MaxChannelsCount() uint64 {
 	return oc.protos.ChannelRestrictions.MaxCount
 }
 
+// BlockCutting returns how the pending transactions are cut into blocks.
+func (oc *OrdererConfig) BlockCutting() *ab.BlockCutting {
+	return oc.protos.BlockCutting
+}
+
 // Organizations returns a map of the orgs in the channel.
 func (oc *OrdererConfig) Organizations() map[string]Org {
 	return oc.orgs
@@ -140,6 +150,7 @@ func (oc *OrdererConfig) Validate() error {
 		oc.validateBatchSize,
 		oc.validateBatchTimeout,
 		oc.validateKafkaBrokers,
+		oc.validateBlockCutting,
 	} {
 		if err := validator(); err != nil {
 			return err
@@ -186,6 +197,28 @@ func (oc *OrdererConfig) validateKafkaBrokers() error {
 	return nil
 }
 
+func (oc *OrdererConfig) validateBlockCutting() error {
+	blockCutting := oc.protos.BlockCutting
+	if proto.Equal(blockCutting, &ab.BlockCutting{}) {
+		return nil
+	}
+	if !oc.Capabilities().FairBlockCutting() {
+		return errors.New("Attempted to configure block cutting without the V2_0 orderer capability")
+	}
+	if _, ok := ab.BlockCutting_Mode_name[int32(blockCutting.Mode)]; !ok {
+		return errors.Errorf("Attempted to set the block cutting mode to an invalid value: %d", blockCutting.Mode)
+	}
+	if blockCutting.Mode == ab.BlockCutting_FAIR && oc.protos.ConsensusType.Type == "kafka" {
+		return errors.New("Attempted to set the block cutting mode to FAIR, which is not supported by consensus type kafka")
+	}
+	for mspID, weight := range blockCutting.OrgWeights {
+		if weight == 0 {
+			return errors.Errorf("Attempted to set the block cutting weight of %s to an invalid value: 0", mspID)
+		}
+	}
+	return nil
+}
+
 // This does just a barebones sanity check.
 func brokerEntrySeemsValid(broker string) bool {
 	if !strings.Contains(broker, ":") {
@@ -9,6 +9,8 @@ package channelconfig
 import (
 	"testing"
 
+	"github.com/hyperledger/fabric/common/capabilities"
+	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/stretchr/testify/assert"
 )
@@ -49,3 +51,32 @@ func TestKafkaBrokers(t *testing.T) {
 	oc = &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"127.0.0.1", "foo.bar", "127.0.0.1:-1", "localhost:65536", "foo.bar.:9092", ".127.0.0.1:9092", "-foo.bar:9092"}}}}
 	assert.Error(t, oc.validateKafkaBrokers(), "Invalid kafka brokers")
 }
+
+func TestBlockCutting(t *testing.T) {
+	v20 := &cb.Capabilities{Capabilities: map[string]*cb.Capability{capabilities.OrdererV2_0: {}}}
+	newConfig := func(consensusType string, blockCutting *ab.BlockCutting, caps *cb.Capabilities) *OrdererConfig {
+		return &OrdererConfig{protos: &OrdererProtos{
+			ConsensusType: &ab.ConsensusType{Type: consensusType},
+			BlockCutting:  blockCutting,
+			Capabilities:  caps,
+		}}
+	}
+
+	oc := newConfig("kafka", &ab.BlockCutting{}, &cb.Capabilities{})
+	assert.NoError(t, oc.validateBlockCutting(), "Default block cutting")
+
+	oc = newConfig("etcdraft", &ab.BlockCutting{Mode: ab.BlockCutting_FAIR, OrgWeights: map[string]uint32{"Org1MSP": 2}}, v20)
+	assert.NoError(t, oc.validateBlockCutting(), "Valid block cutting")
+
+	oc = newConfig("etcdraft", &ab.BlockCutting{Mode: ab.BlockCutting_FAIR}, &cb.Capabilities{})
+	assert.EqualError(t, oc.validateBlockCutting(), "Attempted to configure block cutting without the V2_0 orderer capability")
+
+	oc = newConfig("etcdraft", &ab.BlockCutting{Mode: 2}, v20)
+	assert.EqualError(t, oc.validateBlockCutting(), "Attempted to set the block cutting mode to an invalid value: 2")
+
+	oc = newConfig("kafka", &ab.BlockCutting{Mode: ab.BlockCutting_FAIR}, v20)
+	assert.EqualError(t, oc.validateBlockCutting(), "Attempted to set the block cutting mode to FAIR, which is not supported by consensus type kafka")
+
+	oc = newConfig("etcdraft", &ab.BlockCutting{Mode: ab.BlockCutting_FAIR, OrgWeights: map[string]uint32{"Org1MSP": 0}}, v20)
+	assert.EqualError(t, oc.validateBlockCutting(), "Attempted to set the block cutting weight of Org1MSP to an invalid value: 0")
+}
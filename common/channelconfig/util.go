@@ -148,6 +148,19 @@ func ChannelRestrictionsValue(maxChannelCount uint64) *StandardConfigValue {
 	}
 }
 
+// BlockCuttingValue returns the config definition for how the orderer cuts blocks.
+// It is a value for the /Channel/Orderer group.
+func BlockCuttingValue(mode ab.BlockCutting_Mode, orgWeights map[string]uint32, pendingBatches uint32) *StandardConfigValue {
+	return &StandardConfigValue{
+		key: BlockCuttingKey,
+		value: &ab.BlockCutting{
+			Mode:           mode,
+			OrgWeights:     orgWeights,
+			PendingBatches: pendingBatches,
+		},
+	}
+}
+
 // KafkaBrokersValue returns the config definition for the addresses of the ordering service's Kafka brokers.
 // It is a value for the /Channel/Orderer group.
 func KafkaBrokersValue(brokers []string) *StandardConfigValue {
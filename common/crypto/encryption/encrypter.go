@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+// encryptedDataMarker is the first byte of the data encrypted by an Encrypter. It is
+// followed by the length of the subject key identifier of the key the data was encrypted
+// with, the subject key identifier itself, and the ciphertext.
+const encryptedDataMarker = 0
+
+// Encrypter encrypts and authenticates the data at rest with AES in GCM mode, using
+// the keys of a KeyProvider. The encrypted data records the key it was encrypted with, so that it
+// can still be decrypted once the current key of the KeyProvider is rotated.
+type Encrypter struct {
+	csp        bccsp.BCCSP
+	keys       KeyProvider
+	currentKey bccsp.Key
+	currentSKI []byte
+}
+
+// NewEncrypter returns an Encrypter which encrypts the data with the current key of
+// the given KeyProvider.
+func NewEncrypter(csp bccsp.BCCSP, keys KeyProvider) (*Encrypter, error) {
+	currentKey, err := keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	currentSKI := currentKey.SKI()
+	if len(currentSKI) == 0 || len(currentSKI) > 255 {
+		return nil, errors.Errorf("invalid subject key identifier [%x] of the encryption key", currentSKI)
+	}
+	return &Encrypter{
+		csp:        csp,
+		keys:       keys,
+		currentKey: currentKey,
+		currentSKI: currentSKI,
+	}, nil
+}
+
+// Encrypt encrypts the given data with the current key.
+func (e *Encrypter) Encrypt(plaintext []byte) ([]byte, error) {
+	ciphertext, err := e.csp.Encrypt(e.currentKey, plaintext, &bccsp.AESGCMModeOpts{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error encrypting data")
+	}
+	encrypted := make([]byte, 0, 2+len(e.currentSKI)+len(ciphertext))
+	encrypted = append(encrypted, encryptedDataMarker, byte(len(e.currentSKI)))
+	encrypted = append(encrypted, e.currentSKI...)
+	return append(encrypted, ciphertext...), nil
+}
+
+// Decrypt decrypts the given data, which was encrypted with the current key or any
+// key the KeyProvider still provides.
+func (e *Encrypter) Decrypt(encrypted []byte) ([]byte, error) {
+	ski, ciphertext, err := splitEncrypted(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	key := e.currentKey
+	if !bytes.Equal(ski, e.currentSKI) {
+		if key, err = e.keys.GetKey(ski); err != nil {
+			return nil, err
+		}
+	}
+	plaintext, err := e.csp.Decrypt(key, ciphertext, &bccsp.AESGCMModeOpts{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error decrypting data")
+	}
+	return plaintext, nil
+}
+
+// IsCurrent returns whether the given data was encrypted with the current key, as
+// opposed to a key which was rotated since.
+func (e *Encrypter) IsCurrent(encrypted []byte) bool {
+	ski, _, err := splitEncrypted(encrypted)
+	return err == nil && bytes.Equal(ski, e.currentSKI)
+}
+
+// RecordKeys adds the current key to the list of the keys the data of a store may be
+// encrypted with, which is kept in the file at the given path, and returns an error if
+// any key of the list is no longer provided by the KeyProvider. Each store keeps its own
+// list, which it replaces with RetireKeys once it re-encrypted all its data with the
+// current key, so that the previous keys can then be removed from the keystore.
+func (e *Encrypter) RecordKeys(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "error reading the list of encryption keys")
+	}
+	keys, err := e.AddCurrentKey(content)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(keys, content) {
+		return nil
+	}
+	return errors.WithMessage(writeFile(path, keys), "error writing the list of encryption keys")
+}
+
+// RetireKeys replaces the list of keys in the file at the given path with the current key,
+// once the data of the store is no longer encrypted with any other key.
+func (e *Encrypter) RetireKeys(path string) error {
+	return errors.WithMessage(writeFile(path, e.CurrentKeys()), "error writing the list of encryption keys")
+}
+
+// AddCurrentKey does what RecordKeys does for a list of keys which is not kept in a file:
+// it returns the given list, as returned by AddCurrentKey or CurrentKeys, with the current
+// key added, or an error if any key of the list is no longer provided by the KeyProvider.
+func (e *Encrypter) AddCurrentKey(keys []byte) ([]byte, error) {
+	recorded := false
+	scanner := bufio.NewScanner(bytes.NewReader(keys))
+	for scanner.Scan() {
+		ski, err := hex.DecodeString(scanner.Text())
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid subject key identifier [%s] in the list of encryption keys", scanner.Text())
+		}
+		if bytes.Equal(ski, e.currentSKI) {
+			recorded = true
+			continue
+		}
+		if _, err := e.keys.GetKey(ski); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("encryption key [%x] was rotated but the data at rest is still encrypted with it, it must remain in the keystore", ski))
+		}
+	}
+	if recorded {
+		return keys, nil
+	}
+	return append(append([]byte{}, keys...), e.CurrentKeys()...), nil
+}
+
+// CurrentKeys returns the list of keys which holds the current key only.
+func (e *Encrypter) CurrentKeys() []byte {
+	return []byte(hex.EncodeToString(e.currentSKI) + "\n")
+}
+
+// OnlyCurrentKey returns whether the given list of keys holds the current key only, in
+// which case none of the data needs to be re-encrypted.
+func (e *Encrypter) OnlyCurrentKey(keys []byte) bool {
+	return bytes.Equal(keys, e.CurrentKeys())
+}
+
+// writeFile replaces the content of the file at the given path, creating its directory
+// if needed, so that the file is never left partially written.
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return errors.Wrapf(err, "error creating directory [%s]", filepath.Dir(path))
+	}
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, content, 0644); err != nil {
+		return errors.Wrapf(err, "error writing file [%s]", tmpPath)
+	}
+	return errors.Wrapf(os.Rename(tmpPath, path), "error renaming file [%s]", tmpPath)
+}
+
+// IsEncrypted returns whether the given data looks like data encrypted by an Encrypter.
+// Encrypted data begins with a zero byte, so only the data which never does, such as
+// marshaled protobuf messages, can be told apart from encrypted data.
+func IsEncrypted(data []byte) bool {
+	return len(data) > 0 && data[0] == encryptedDataMarker
+}
+
+func splitEncrypted(encrypted []byte) (ski []byte, ciphertext []byte, err error) {
+	if !IsEncrypted(encrypted) || len(encrypted) < 2 || len(encrypted) < 2+int(encrypted[1]) {
+		return nil, nil, errors.New("data is not encrypted")
+	}
+	skiLength := int(encrypted[1])
+	return encrypted[2 : 2+skiLength], encrypted[2+skiLength:], nil
+}
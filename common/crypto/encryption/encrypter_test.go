@@ -0,0 +1,178 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCSP(t *testing.T) bccsp.BCCSP {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	require.NoError(t, err)
+	return csp
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	csp := newTestCSP(t)
+	ski, err := GenerateKey(csp)
+	require.NoError(t, err)
+	e, err := NewEncrypter(csp, NewKeyProvider(csp, ski))
+	require.NoError(t, err)
+
+	plaintext := []byte("private data")
+	encrypted, err := e.Encrypt(plaintext)
+	assert.NoError(t, err)
+	assert.True(t, IsEncrypted(encrypted))
+	assert.True(t, e.IsCurrent(encrypted))
+	assert.NotContains(t, string(encrypted), string(plaintext))
+	decrypted, err := e.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+
+	encrypted, err = e.Encrypt(nil)
+	assert.NoError(t, err)
+	decrypted, err = e.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Empty(t, decrypted)
+
+	assert.False(t, IsEncrypted([]byte{0x0a, 0x00}))
+	assert.False(t, IsEncrypted(nil))
+	_, err = e.Decrypt([]byte{0x0a, 0x00})
+	assert.EqualError(t, err, "data is not encrypted")
+	_, err = e.Decrypt([]byte{encryptedDataMarker, 32, 1})
+	assert.EqualError(t, err, "data is not encrypted")
+}
+
+func TestKeyRotation(t *testing.T) {
+	csp := newTestCSP(t)
+	oldSKI, err := GenerateKey(csp)
+	require.NoError(t, err)
+	oldEncrypter, err := NewEncrypter(csp, NewKeyProvider(csp, oldSKI))
+	require.NoError(t, err)
+	encrypted, err := oldEncrypter.Encrypt([]byte("private data"))
+	require.NoError(t, err)
+
+	newSKI, err := GenerateKey(csp)
+	require.NoError(t, err)
+	newEncrypter, err := NewEncrypter(csp, NewKeyProvider(csp, newSKI))
+	require.NoError(t, err)
+	assert.False(t, newEncrypter.IsCurrent(encrypted))
+	decrypted, err := newEncrypter.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("private data"), decrypted)
+
+	reencrypted, err := newEncrypter.Encrypt(decrypted)
+	require.NoError(t, err)
+	assert.True(t, newEncrypter.IsCurrent(reencrypted))
+
+	// The data encrypted with a key which is no longer in the keystore cannot be decrypted
+	otherCSP := newTestCSP(t)
+	otherSKI, err := GenerateKey(otherCSP)
+	require.NoError(t, err)
+	otherEncrypter, err := NewEncrypter(otherCSP, NewKeyProvider(otherCSP, otherSKI))
+	require.NoError(t, err)
+	_, err = otherEncrypter.Decrypt(encrypted)
+	assert.Contains(t, err.Error(), "error getting encryption key")
+}
+
+func TestNewEncrypterInvalidKey(t *testing.T) {
+	csp := newTestCSP(t)
+	_, err := NewEncrypter(csp, NewKeyProvider(csp, []byte{1, 2, 3}))
+	assert.Contains(t, err.Error(), "error getting encryption key")
+
+	ecdsaKey, err := csp.KeyGen(&bccsp.ECDSAP256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	_, err = NewEncrypter(csp, NewKeyProvider(csp, ecdsaKey.SKI()))
+	assert.EqualError(t, err, fmt.Sprintf("encryption key [%x] is not a symmetric key", ecdsaKey.SKI()))
+}
+
+// retiringKeyProvider no longer provides a retired key
+type retiringKeyProvider struct {
+	KeyProvider
+	retiredSKI []byte
+}
+
+func (kp *retiringKeyProvider) GetKey(ski []byte) (bccsp.Key, error) {
+	if bytes.Equal(ski, kp.retiredSKI) {
+		return nil, errors.New("key not found")
+	}
+	return kp.KeyProvider.GetKey(ski)
+}
+
+func TestRecordKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "keys", "encryptionKeys")
+
+	csp := newTestCSP(t)
+	oldSKI, err := GenerateKey(csp)
+	require.NoError(t, err)
+	oldEncrypter, err := NewEncrypter(csp, NewKeyProvider(csp, oldSKI))
+	require.NoError(t, err)
+	assert.NoError(t, oldEncrypter.RecordKeys(path))
+	assert.NoError(t, oldEncrypter.RecordKeys(path))
+
+	newSKI, err := GenerateKey(csp)
+	require.NoError(t, err)
+	newEncrypter, err := NewEncrypter(csp, NewKeyProvider(csp, newSKI))
+	require.NoError(t, err)
+	assert.NoError(t, newEncrypter.RecordKeys(path))
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%x\n%x\n", oldSKI, newSKI), string(content))
+
+	// The previous key cannot be retired
+	retiringEncrypter, err := NewEncrypter(csp, &retiringKeyProvider{KeyProvider: NewKeyProvider(csp, newSKI), retiredSKI: oldSKI})
+	require.NoError(t, err)
+	err = retiringEncrypter.RecordKeys(path)
+	assert.EqualError(t, err, fmt.Sprintf("encryption key [%x] was rotated but the data at rest is still encrypted with it, it must remain in the keystore: key not found", oldSKI))
+
+	// Once the data is re-encrypted, only the current key is needed
+	assert.False(t, newEncrypter.OnlyCurrentKey(content))
+	assert.NoError(t, retiringEncrypter.RetireKeys(path))
+	assert.NoError(t, retiringEncrypter.RecordKeys(path))
+	content, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%x\n", newSKI), string(content))
+	assert.True(t, newEncrypter.OnlyCurrentKey(content))
+
+	keys, err := oldEncrypter.AddCurrentKey(content)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%x\n%x\n", newSKI, oldSKI), string(keys))
+	assert.Equal(t, []byte(fmt.Sprintf("%x\n", oldSKI)), oldEncrypter.CurrentKeys())
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("not-hex\n"), 0644))
+	err = newEncrypter.RecordKeys(path)
+	assert.Contains(t, err.Error(), "invalid subject key identifier [not-hex] in the list of encryption keys")
+}
+
+func TestDecryptTampered(t *testing.T) {
+	csp := newTestCSP(t)
+	ski, err := GenerateKey(csp)
+	require.NoError(t, err)
+	e, err := NewEncrypter(csp, NewKeyProvider(csp, ski))
+	require.NoError(t, err)
+
+	encrypted, err := e.Encrypt([]byte("private data"))
+	require.NoError(t, err)
+	encrypted[len(encrypted)-1] ^= 1
+	_, err = e.Decrypt(encrypted)
+	assert.Contains(t, err.Error(), "cipher: message authentication failed")
+}
@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+// KeyProvider provides the keys which the data at rest is encrypted with.
+type KeyProvider interface {
+	// CurrentKey returns the key which the data written from now on is encrypted with.
+	CurrentKey() (bccsp.Key, error)
+	// GetKey returns the key with the given subject key identifier, to decrypt the
+	// data which was encrypted with it.
+	GetKey(ski []byte) (bccsp.Key, error)
+}
+
+// NewKeyProvider returns a KeyProvider which looks the keys up in the keystore of
+// the given BCCSP, the current key being the one with the given subject key identifier.
+// Where the keys live, on the file system or in an HSM, is up to the BCCSP.
+func NewKeyProvider(csp bccsp.BCCSP, currentSKI []byte) KeyProvider {
+	return &bccspKeyProvider{
+		csp:        csp,
+		currentSKI: currentSKI,
+		keys:       make(map[string]bccsp.Key),
+	}
+}
+
+type bccspKeyProvider struct {
+	csp        bccsp.BCCSP
+	currentSKI []byte
+
+	mutex sync.RWMutex
+	keys  map[string]bccsp.Key
+}
+
+func (kp *bccspKeyProvider) CurrentKey() (bccsp.Key, error) {
+	return kp.GetKey(kp.currentSKI)
+}
+
+func (kp *bccspKeyProvider) GetKey(ski []byte) (bccsp.Key, error) {
+	kp.mutex.RLock()
+	key, ok := kp.keys[string(ski)]
+	kp.mutex.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	key, err := kp.csp.GetKey(ski)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting encryption key")
+	}
+	if !key.Symmetric() {
+		return nil, errors.Errorf("encryption key [%x] is not a symmetric key", ski)
+	}
+
+	kp.mutex.Lock()
+	kp.keys[string(ski)] = key
+	kp.mutex.Unlock()
+	return key, nil
+}
+
+// GenerateKey generates a 256 bit AES key, stores it in the keystore of the given
+// BCCSP and returns its subject key identifier.
+func GenerateKey(csp bccsp.BCCSP) ([]byte, error) {
+	key, err := csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: false})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error generating encryption key")
+	}
+	return key.SKI(), nil
+}
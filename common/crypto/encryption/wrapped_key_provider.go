@@ -0,0 +1,198 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+// NewWrappedKeyProvider returns a KeyProvider which implements envelope encryption: the data
+// is encrypted in software with AES data keys, which are kept in the file at the given path
+// wrapped by the keys of the keystore of the given BCCSP. This is how the data is encrypted
+// with the keys of an HSM, which never leave it, without a round trip to the HSM for every
+// encryption: the HSM only unwraps each data key once. The current data key is the one wrapped
+// by the key with the given subject key identifier, and is generated when it is first needed.
+func NewWrappedKeyProvider(csp bccsp.BCCSP, wrappingSKI []byte, path string) KeyProvider {
+	return &wrappedKeyProvider{
+		csp:         csp,
+		wrappingSKI: wrappingSKI,
+		path:        path,
+		keys:        make(map[string]bccsp.Key),
+	}
+}
+
+type wrappedKeyProvider struct {
+	csp         bccsp.BCCSP
+	wrappingSKI []byte
+	path        string
+
+	mutex      sync.Mutex
+	keys       map[string]bccsp.Key
+	currentKey bccsp.Key
+}
+
+// wrappedKey is a data key, as recorded in the file of the data keys: the subject key
+// identifier of the data key, the subject key identifier of the key it is wrapped by,
+// and the data key wrapped with AES in GCM mode.
+type wrappedKey struct {
+	ski         []byte
+	wrappingSKI []byte
+	wrapped     []byte
+}
+
+func (kp *wrappedKeyProvider) CurrentKey() (bccsp.Key, error) {
+	kp.mutex.Lock()
+	defer kp.mutex.Unlock()
+	if kp.currentKey != nil {
+		return kp.currentKey, nil
+	}
+
+	wrappedKeys, err := kp.readWrappedKeys()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(wrappedKeys) - 1; i >= 0; i-- {
+		if bytes.Equal(wrappedKeys[i].wrappingSKI, kp.wrappingSKI) {
+			key, err := kp.unwrap(wrappedKeys[i])
+			if err != nil {
+				return nil, err
+			}
+			kp.currentKey = key
+			return key, nil
+		}
+	}
+
+	key, err := kp.generate(wrappedKeys)
+	if err != nil {
+		return nil, err
+	}
+	kp.currentKey = key
+	return key, nil
+}
+
+func (kp *wrappedKeyProvider) GetKey(ski []byte) (bccsp.Key, error) {
+	kp.mutex.Lock()
+	defer kp.mutex.Unlock()
+	if key, ok := kp.keys[string(ski)]; ok {
+		return key, nil
+	}
+
+	wrappedKeys, err := kp.readWrappedKeys()
+	if err != nil {
+		return nil, err
+	}
+	for _, wk := range wrappedKeys {
+		if bytes.Equal(wk.ski, ski) {
+			return kp.unwrap(wk)
+		}
+	}
+	return nil, errors.Errorf("error getting encryption key: data key [%x] not found in [%s]", ski, kp.path)
+}
+
+// generate generates a data key, wraps it with the current wrapping key and adds it to the
+// file of the data keys, before it encrypts any data.
+func (kp *wrappedKeyProvider) generate(wrappedKeys []*wrappedKey) (bccsp.Key, error) {
+	wrappingKey, err := kp.getWrappingKey(kp.wrappingSKI)
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, errors.Wrap(err, "error generating data key")
+	}
+	key, err := kp.csp.KeyImport(raw, &bccsp.AES256ImportKeyOpts{Temporary: true})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error generating data key")
+	}
+	wrapped, err := kp.csp.Encrypt(wrappingKey, raw, &bccsp.AESGCMModeOpts{})
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error wrapping data key with key [%x]", kp.wrappingSKI))
+	}
+
+	wk := &wrappedKey{ski: key.SKI(), wrappingSKI: kp.wrappingSKI, wrapped: wrapped}
+	var content bytes.Buffer
+	for _, w := range append(wrappedKeys, wk) {
+		fmt.Fprintf(&content, "%x %x %x\n", w.ski, w.wrappingSKI, w.wrapped)
+	}
+	if err := writeFile(kp.path, content.Bytes()); err != nil {
+		return nil, errors.WithMessage(err, "error writing the data keys")
+	}
+
+	kp.keys[string(wk.ski)] = key
+	return key, nil
+}
+
+// unwrap unwraps a data key with the key of the BCCSP it was wrapped by.
+func (kp *wrappedKeyProvider) unwrap(wk *wrappedKey) (bccsp.Key, error) {
+	wrappingKey, err := kp.getWrappingKey(wk.wrappingSKI)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := kp.csp.Decrypt(wrappingKey, wk.wrapped, &bccsp.AESGCMModeOpts{})
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error unwrapping data key [%x] with key [%x]", wk.ski, wk.wrappingSKI))
+	}
+	key, err := kp.csp.KeyImport(raw, &bccsp.AES256ImportKeyOpts{Temporary: true})
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error unwrapping data key [%x]", wk.ski))
+	}
+	if !bytes.Equal(key.SKI(), wk.ski) {
+		return nil, errors.Errorf("data key unwrapped with key [%x] does not match its subject key identifier [%x]", wk.wrappingSKI, wk.ski)
+	}
+
+	kp.keys[string(wk.ski)] = key
+	return key, nil
+}
+
+func (kp *wrappedKeyProvider) getWrappingKey(ski []byte) (bccsp.Key, error) {
+	key, err := kp.csp.GetKey(ski)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting encryption key")
+	}
+	if !key.Symmetric() {
+		return nil, errors.Errorf("encryption key [%x] is not a symmetric key", ski)
+	}
+	return key, nil
+}
+
+func (kp *wrappedKeyProvider) readWrappedKeys() ([]*wrappedKey, error) {
+	content, err := ioutil.ReadFile(kp.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading the data keys")
+	}
+
+	var wrappedKeys []*wrappedKey
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			return nil, errors.Errorf("invalid entry [%s] in the data keys", scanner.Text())
+		}
+		decoded := make([][]byte, len(fields))
+		for i, field := range fields {
+			if decoded[i], err = hex.DecodeString(field); err != nil {
+				return nil, errors.Wrapf(err, "invalid entry [%s] in the data keys", scanner.Text())
+			}
+		}
+		wrappedKeys = append(wrappedKeys, &wrappedKey{ski: decoded[0], wrappingSKI: decoded[1], wrapped: decoded[2]})
+	}
+	return wrappedKeys, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrappedKeyProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dataKeys")
+
+	csp := newTestCSP(t)
+	wrappingSKI, err := GenerateKey(csp)
+	require.NoError(t, err)
+	e, err := NewEncrypter(csp, NewWrappedKeyProvider(csp, wrappingSKI, path))
+	require.NoError(t, err)
+	encrypted, err := e.Encrypt([]byte("private data"))
+	require.NoError(t, err)
+
+	// The data key is recorded wrapped, and is not the wrapping key
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	fields := strings.Fields(string(content))
+	require.Len(t, fields, 3)
+	assert.NotEqual(t, fmt.Sprintf("%x", wrappingSKI), fields[0])
+	assert.Equal(t, fmt.Sprintf("%x", wrappingSKI), fields[1])
+	assert.True(t, e.IsCurrent(encrypted))
+	assert.Equal(t, fields[0], fmt.Sprintf("%x", e.currentSKI))
+
+	// The data key is unwrapped rather than generated again
+	e, err = NewEncrypter(csp, NewWrappedKeyProvider(csp, wrappingSKI, path))
+	require.NoError(t, err)
+	assert.True(t, e.IsCurrent(encrypted))
+	decrypted, err := e.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("private data"), decrypted)
+
+	// Rotating the wrapping key generates a new data key, and the previous one is still unwrapped
+	newWrappingSKI, err := GenerateKey(csp)
+	require.NoError(t, err)
+	e, err = NewEncrypter(csp, NewWrappedKeyProvider(csp, newWrappingSKI, path))
+	require.NoError(t, err)
+	assert.False(t, e.IsCurrent(encrypted))
+	decrypted, err = e.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("private data"), decrypted)
+	content, err = ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 2)
+
+	// The data keys cannot be unwrapped without their wrapping key
+	otherCSP := newTestCSP(t)
+	otherSKI, err := GenerateKey(otherCSP)
+	require.NoError(t, err)
+	e, err = NewEncrypter(otherCSP, NewWrappedKeyProvider(otherCSP, otherSKI, path))
+	require.NoError(t, err)
+	_, err = e.Decrypt(encrypted)
+	assert.Contains(t, err.Error(), "error getting encryption key")
+
+	// A data key which is not recorded cannot be found
+	_, err = NewWrappedKeyProvider(csp, wrappingSKI, path).GetKey([]byte{1, 2, 3})
+	assert.EqualError(t, err, fmt.Sprintf("error getting encryption key: data key [010203] not found in [%s]", path))
+}
+
+func TestWrappedKeyProviderErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "encryption")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "dataKeys")
+
+	csp := newTestCSP(t)
+	_, err = NewWrappedKeyProvider(csp, []byte{1, 2, 3}, path).CurrentKey()
+	assert.Contains(t, err.Error(), "error getting encryption key")
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "no data key should be recorded without a wrapping key")
+
+	wrappingSKI, err := GenerateKey(csp)
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(path, []byte("0102 0304\n"), 0644))
+	_, err = NewWrappedKeyProvider(csp, wrappingSKI, path).CurrentKey()
+	assert.EqualError(t, err, "invalid entry [0102 0304] in the data keys")
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(fmt.Sprintf("0102 %x 0506\n", wrappingSKI)), 0644))
+	_, err = NewWrappedKeyProvider(csp, wrappingSKI, path).CurrentKey()
+	assert.Contains(t, err.Error(), "error unwrapping data key [0102]")
+
+	// A data key wrapped under another subject key identifier is rejected
+	kp := NewWrappedKeyProvider(csp, wrappingSKI, filepath.Join(dir, "otherDataKeys"))
+	key, err := kp.CurrentKey()
+	require.NoError(t, err)
+	content, err := ioutil.ReadFile(filepath.Join(dir, "otherDataKeys"))
+	require.NoError(t, err)
+	tampered := strings.Replace(string(content), fmt.Sprintf("%x", key.SKI()), "0102", 1)
+	require.NoError(t, ioutil.WriteFile(path, []byte(tampered), 0644))
+	_, err = NewWrappedKeyProvider(csp, wrappingSKI, path).CurrentKey()
+	assert.EqualError(t, err, fmt.Sprintf("data key unwrapped with key [%x] does not match its subject key identifier [0102]", wrappingSKI))
+}
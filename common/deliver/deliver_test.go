@@ -20,9 +20,12 @@ import (
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/common/util"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 )
@@ -488,6 +491,106 @@ var _ = Describe("Deliver", func() {
 			})
 		})
 
+		Context("when the seek info has a transaction filter", func() {
+			var block *cb.Block
+
+			// setFilter sets the filter of the seek info of the envelope already built
+			setFilter := func(filter *ab.TransactionFilter) {
+				seekInfo.Filter = filter
+				payload, err := utils.UnmarshalPayload(envelope.Payload)
+				Expect(err).NotTo(HaveOccurred())
+				payload.Data = utils.MarshalOrPanic(seekInfo)
+				envelope.Payload = utils.MarshalOrPanic(payload)
+			}
+
+			BeforeEach(func() {
+				block = &cb.Block{
+					Header: &cb.BlockHeader{Number: 100},
+					Data: &cb.BlockData{
+						Data: [][]byte{
+							filterTestTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "mycc", "transfer"),
+							filterTestTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP", "mycc", "mint"),
+							filterTestTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "othercc", ""),
+							filterTestTx(cb.HeaderType_CONFIG, "Org1MSP", "", ""),
+						},
+					},
+					Metadata: &cb.BlockMetadata{
+						Metadata: [][]byte{{}, {}, {
+							byte(pb.TxValidationCode_VALID),
+							byte(pb.TxValidationCode_VALID),
+							byte(pb.TxValidationCode_MVCC_READ_CONFLICT),
+							byte(pb.TxValidationCode_VALID),
+						}},
+					},
+				}
+				fakeBlockIterator.NextReturns(block, cb.Status_SUCCESS)
+			})
+
+			DescribeTable("sends the block with the transactions which do not match replaced by empty entries",
+				func(filter *ab.TransactionFilter, matching ...int) {
+					setFilter(filter)
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
+					sent := fakeResponseSender.SendBlockResponseArgsForCall(0)
+					Expect(sent.Header).To(Equal(block.Header))
+					Expect(sent.Metadata).To(Equal(block.Metadata))
+					expected := make([][]byte, len(block.Data.Data))
+					for _, i := range matching {
+						expected[i] = block.Data.Data[i]
+					}
+					Expect(sent.Data.Data).To(Equal(expected))
+				},
+				Entry("empty filter", &ab.TransactionFilter{}, 0, 1, 2, 3),
+				Entry("chaincode name", &ab.TransactionFilter{ChaincodeName: "mycc"}, 0, 1),
+				Entry("chaincode event name", &ab.TransactionFilter{ChaincodeEventName: "^(transfer|burn)$"}, 0),
+				Entry("validation codes", &ab.TransactionFilter{ValidationCodes: []int32{int32(pb.TxValidationCode_MVCC_READ_CONFLICT)}}, 2),
+				Entry("creator MSP ID", &ab.TransactionFilter{CreatorMspId: "Org1MSP"}, 0, 2, 3),
+				Entry("all criteria", &ab.TransactionFilter{ChaincodeName: "mycc", ChaincodeEventName: "mint", ValidationCodes: []int32{0}, CreatorMspId: "Org2MSP"}, 1),
+				Entry("no match", &ab.TransactionFilter{ChaincodeName: "unknowncc"}),
+			)
+
+			It("does not modify the block read from the ledger", func() {
+				original := proto.Clone(block)
+				setFilter(&ab.TransactionFilter{ChaincodeName: "othercc"})
+				err := handler.Handle(context.Background(), server)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(block, original)).To(BeTrue())
+			})
+
+			Context("when the block has no transaction validation flags", func() {
+				BeforeEach(func() {
+					block.Metadata = nil
+				})
+
+				It("considers the transactions not validated", func() {
+					setFilter(&ab.TransactionFilter{ValidationCodes: []int32{int32(pb.TxValidationCode_NOT_VALIDATED)}})
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(1))
+					Expect(fakeResponseSender.SendBlockResponseArgsForCall(0).Data).To(Equal(block.Data))
+				})
+			})
+
+			Context("when the chaincode event name is not a valid regular expression", func() {
+				BeforeEach(func() {
+					seekInfo.Filter = &ab.TransactionFilter{ChaincodeEventName: "(transfer"}
+				})
+
+				It("sends status bad request", func() {
+					err := handler.Handle(context.Background(), server)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeResponseSender.SendBlockResponseCallCount()).To(Equal(0))
+					Expect(fakeResponseSender.SendStatusResponseCallCount()).To(Equal(1))
+					resp := fakeResponseSender.SendStatusResponseArgsForCall(0)
+					Expect(resp).To(Equal(cb.Status_BAD_REQUEST))
+				})
+			})
+		})
+
 		Context("when sending the block fails", func() {
 			BeforeEach(func() {
 				fakeResponseSender.SendBlockResponseReturns(errors.New("send-fails"))
@@ -854,3 +957,37 @@ var _ = Describe("Deliver", func() {
 		})
 	})
 })
+
+// filterTestTx returns a transaction of the given type created by the given
+// organization, which invokes the given chaincode and emits the given event.
+func filterTestTx(txType cb.HeaderType, mspID, chaincodeName, eventName string) []byte {
+	var events []byte
+	if eventName != "" {
+		events = utils.MarshalOrPanic(&pb.ChaincodeEvent{ChaincodeId: chaincodeName, EventName: eventName})
+	}
+	ccAction := &pb.ChaincodeAction{
+		ChaincodeId: &pb.ChaincodeID{Name: chaincodeName},
+		Events:      events,
+	}
+	tx := &pb.Transaction{
+		Actions: []*pb.TransactionAction{{
+			Payload: utils.MarshalOrPanic(&pb.ChaincodeActionPayload{
+				Action: &pb.ChaincodeEndorsedAction{
+					ProposalResponsePayload: utils.MarshalOrPanic(&pb.ProposalResponsePayload{
+						Extension: utils.MarshalOrPanic(ccAction),
+					}),
+				},
+			}),
+		}},
+	}
+	payload := &cb.Payload{
+		Header: &cb.Header{
+			ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(txType), ChannelId: "chain-id"}),
+			SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{
+				Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID}),
+			}),
+		},
+		Data: utils.MarshalOrPanic(tx),
+	}
+	return utils.MarshalOrPanic(&cb.Envelope{Payload: utils.MarshalOrPanic(payload)})
+}
@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package deliver
+
+import (
+	"regexp"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// transactionFilter selects the transactions of the blocks delivered for a
+// seek request according to its TransactionFilter. A nil transactionFilter
+// selects all transactions.
+type transactionFilter struct {
+	chaincodeName   string
+	eventName       *regexp.Regexp
+	validationCodes map[peer.TxValidationCode]struct{}
+	creatorMSPID    string
+}
+
+func newTransactionFilter(filter *ab.TransactionFilter) (*transactionFilter, error) {
+	if filter == nil {
+		return nil, nil
+	}
+
+	tf := &transactionFilter{
+		chaincodeName: filter.ChaincodeName,
+		creatorMSPID:  filter.CreatorMspId,
+	}
+	if filter.ChaincodeEventName != "" {
+		eventName, err := regexp.Compile(filter.ChaincodeEventName)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid chaincode event name")
+		}
+		tf.eventName = eventName
+	}
+	if len(filter.ValidationCodes) > 0 {
+		tf.validationCodes = make(map[peer.TxValidationCode]struct{})
+		for _, code := range filter.ValidationCodes {
+			tf.validationCodes[peer.TxValidationCode(code)] = struct{}{}
+		}
+	}
+	return tf, nil
+}
+
+// apply returns the block with the transactions which do not match the filter
+// replaced by empty entries. The block header and metadata are left untouched,
+// so that the transactions keep their index in the block.
+func (tf *transactionFilter) apply(block *cb.Block) *cb.Block {
+	if tf == nil || block.Data == nil {
+		return block
+	}
+
+	var flags []byte
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(cb.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		flags = block.Metadata.Metadata[cb.BlockMetadataIndex_TRANSACTIONS_FILTER]
+	}
+
+	data := make([][]byte, len(block.Data.Data))
+	for i, envBytes := range block.Data.Data {
+		// Blocks are not validated by the orderer, nor by the peer before they are committed
+		code := peer.TxValidationCode_NOT_VALIDATED
+		if i < len(flags) {
+			code = peer.TxValidationCode(flags[i])
+		}
+		if tf.matches(envBytes, code) {
+			data[i] = envBytes
+		}
+	}
+
+	return &cb.Block{
+		Header:   block.Header,
+		Data:     &cb.BlockData{Data: data},
+		Metadata: block.Metadata,
+	}
+}
+
+func (tf *transactionFilter) matches(envBytes []byte, code peer.TxValidationCode) bool {
+	if tf.validationCodes != nil {
+		if _, ok := tf.validationCodes[code]; !ok {
+			return false
+		}
+	}
+
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return false
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil || payload.Header == nil {
+		return false
+	}
+
+	if tf.creatorMSPID != "" {
+		shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+		if err != nil {
+			return false
+		}
+		creator := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(shdr.Creator, creator); err != nil || creator.Mspid != tf.creatorMSPID {
+			return false
+		}
+	}
+
+	if tf.chaincodeName == "" && tf.eventName == nil {
+		return true
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil || chdr.Type != int32(cb.HeaderType_ENDORSER_TRANSACTION) {
+		return false
+	}
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return false
+	}
+	for _, action := range tx.Actions {
+		if tf.matchesAction(action) {
+			return true
+		}
+	}
+	return false
+}
+
+func (tf *transactionFilter) matchesAction(action *peer.TransactionAction) bool {
+	ccActionPayload, err := utils.GetChaincodeActionPayload(action.Payload)
+	if err != nil || ccActionPayload.Action == nil {
+		return false
+	}
+	prp, err := utils.GetProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return false
+	}
+	ccAction, err := utils.GetChaincodeAction(prp.Extension)
+	if err != nil {
+		return false
+	}
+
+	if tf.chaincodeName != "" && ccAction.ChaincodeId.GetName() != tf.chaincodeName {
+		return false
+	}
+	if tf.eventName == nil {
+		return true
+	}
+	if len(ccAction.Events) == 0 {
+		return false
+	}
+	ccEvent, err := utils.GetChaincodeEvents(ccAction.Events)
+	return err == nil && tf.eventName.MatchString(ccEvent.EventName)
+}
@@ -40,12 +40,35 @@ var (
 	ErrAttrNotIndexed = errors.New("attribute not indexed")
 )
 
+// SnapshotInfo contains the blocks of a ledger snapshot that are retained by a block store
+// which is bootstrapped from the snapshot, instead of from the genesis block. The last block
+// and the last config block of the snapshot remain retrievable, whereas the blocks preceding
+// them are not available in the block store
+type SnapshotInfo struct {
+	LastBlock       *common.Block
+	LastConfigBlock *common.Block
+}
+
+// TxIDsIterator iterates over the ids of the transactions that were committed to a ledger
+// before the ledger snapshot was taken
+type TxIDsIterator interface {
+	// Next returns the next txid. The returned bool is false once the iterator is exhausted
+	Next() (string, bool, error)
+}
+
 // BlockStoreProvider provides an handle to a BlockStore
 type BlockStoreProvider interface {
 	CreateBlockStore(ledgerid string) (BlockStore, error)
+	// ImportFromSnapshot bootstraps the BlockStore for the given ledger from a snapshot. The txids
+	// of the transactions committed before the snapshot are imported so that the duplicate
+	// transactions can be detected. The BlockStore is expected to be opened afterwards
+	ImportFromSnapshot(ledgerid string, snapshotInfo *SnapshotInfo, txIDs TxIDsIterator) error
 	OpenBlockStore(ledgerid string) (BlockStore, error)
 	Exists(ledgerid string) (bool, error)
 	List() ([]string, error)
+	// Remove removes the BlockStore of the given ledger along with its index.
+	// The BlockStore is expected to be shut down beforehand
+	Remove(ledgerid string) error
 	Close()
 }
 
@@ -62,5 +85,20 @@ type BlockStore interface {
 	RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error)
 	RetrieveBlockByTxID(txID string) (*common.Block, error)
 	RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	// TxIDExists returns true if a transaction with the given id has been committed to the
+	// ledger, including the transactions committed before the snapshot the ledger may have
+	// been bootstrapped from
+	TxIDExists(txID string) (bool, error)
+	// ExportTxIDs invokes the handle function for the id of every transaction committed to
+	// the ledger
+	ExportTxIDs(handle func(txID string) error) error
+	// GetBootstrappingSnapshotInfo returns the info of the snapshot that the block store was
+	// bootstrapped from, or nil if the block store was bootstrapped from the genesis block
+	GetBootstrappingSnapshotInfo() (*SnapshotInfo, error)
+	// Prune removes the blocks below retainFromBlockNum from the block store, to the extent the
+	// implementation allows, and moves them to archiveDir. If archiveDir is empty, the blocks are
+	// deleted. The last config block is retained even if it is below retainFromBlockNum. The retrieval
+	// of any other pruned block returns an error of type *ledger.ErrBlockArchived
+	Prune(retainFromBlockNum uint64, archiveDir string) error
 	Shutdown()
 }
@@ -0,0 +1,49 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"github.com/golang/snappy"
+	"github.com/pkg/errors"
+)
+
+// Compression is the way the blocks appended to the block files of a ledger are compressed.
+type Compression string
+
+const (
+	// NoCompression appends the blocks uncompressed, as previous versions did.
+	NoCompression Compression = "none"
+	// SnappyCompression appends the blocks compressed with snappy.
+	SnappyCompression Compression = "snappy"
+)
+
+// CompressionConf holds the compression of the blocks appended to each ledger.
+// Only the blocks appended after the compression of a ledger is changed are
+// affected, the blocks already in the block files remain readable as they are.
+type CompressionConf struct {
+	// Default is the compression of the ledgers which are not listed in Ledgers.
+	Default Compression
+	// Ledgers overrides the default compression of the ledgers, by ledger ID.
+	Ledgers map[string]Compression
+}
+
+func (c Compression) validate() error {
+	switch c {
+	case "", NoCompression, SnappyCompression:
+		return nil
+	default:
+		return errors.Errorf("unknown block compression [%s]", c)
+	}
+}
+
+func decompressBlock(compressedBytes []byte) ([]byte, error) {
+	blockBytes, err := snappy.Decode(nil, compressedBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decompressing block")
+	}
+	return blockBytes, nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	putil "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeBlock(t *testing.T) {
+	blockBytes := bytes.Repeat([]byte("redundant protobuf"), 100)
+
+	encoded, encoding, err := encodeBlock(blockBytes, NoCompression, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, plainBlock, encoding)
+	assert.Len(t, encoded, 2+len(blockBytes))
+	assert.Equal(t, blockBytes, encoded[2:])
+
+	encoded, encoding, err = encodeBlock(blockBytes, SnappyCompression, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, compressedBlock, encoding)
+	assert.Equal(t, byte(blockEncodingMarker), encoded[0])
+	assert.True(t, len(encoded) < len(blockBytes)/10, "block should have been compressed")
+	decompressed, err := decompressBlock(encoded[2:])
+	assert.NoError(t, err)
+	assert.Equal(t, blockBytes, decompressed)
+
+	_, err = decompressBlock([]byte("not snappy"))
+	assert.EqualError(t, err, "error decompressing block: snappy: corrupt input")
+}
+
+func TestBlockfileMgrMixedCompression(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blocks := testutil.ConstructTestBlocks(t, 30)
+
+	// The compression of a ledger may be changed at any time, the blocks already
+	// appended to the block files being read as they were appended
+	for i, compression := range []Compression{NoCompression, SnappyCompression, NoCompression} {
+		env.provider.conf.compression.Ledgers = map[string]Compression{ledgerid: compression}
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+		blkfileMgrWrapper.addBlocks(blocks[i*10 : (i+1)*10])
+		blkfileMgrWrapper.close()
+	}
+
+	assertBlocksAndTransactions(t, env, ledgerid, blocks)
+
+	// The checkpoint info and the index are rebuilt from the block files
+	require.NoError(t, env.provider.leveldbProvider.GetDBHandle(ledgerid).DeleteAll())
+	assertBlocksAndTransactions(t, env, ledgerid, blocks)
+}
+
+func assertBlocksAndTransactions(t *testing.T, env *testEnv, ledgerid string, blocks []*common.Block) {
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	mgr := blkfileMgrWrapper.blockfileMgr
+
+	assert.Equal(t, uint64(len(blocks)), mgr.getBlockchainInfo().Height)
+	blkfileMgrWrapper.testGetBlockByHash(blocks)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0)
+	testBlockfileMgrBlockIterator(t, mgr, 0, len(blocks)-1, blocks)
+	for blockNum, block := range blocks {
+		for txNum, txEnvelopeBytes := range block.Data.Data {
+			txEnvelope, err := putil.GetEnvelopeFromBlock(txEnvelopeBytes)
+			require.NoError(t, err)
+			txID, err := extractTxID(txEnvelopeBytes)
+			require.NoError(t, err)
+
+			txEnvelopeFromFileMgr, err := mgr.retrieveTransactionByID(txID)
+			assert.NoError(t, err)
+			assert.Equal(t, txEnvelope, txEnvelopeFromFileMgr)
+			txEnvelopeFromFileMgr, err = mgr.retrieveTransactionByBlockNumTranNum(uint64(blockNum), uint64(txNum))
+			assert.NoError(t, err)
+			assert.Equal(t, txEnvelope, txEnvelopeFromFileMgr)
+		}
+	}
+}
+
+func TestBlockfileMgrCompressedCrashDuringWriting(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0).WithCompression(CompressionConf{Default: SnappyCompression}))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgrWrapper.addBlocks(blocks[:4])
+	cpInfo := blkfileMgrWrapper.blockfileMgr.cpInfo
+	blkfileMgrWrapper.close()
+
+	// A partially written compressed block is discarded on restart
+	blockBytes, _, err := serializeBlock(blocks[4])
+	require.NoError(t, err)
+	encoded, _, err := encodeBlock(blockBytes, SnappyCompression, nil)
+	require.NoError(t, err)
+	for _, partialLength := range []int{1, 2, len(encoded) - 1} {
+		w, err := newBlockfileWriter(deriveBlockfilePath(blkfileMgrWrapper.blockfileMgr.rootDir, cpInfo.latestFileChunkSuffixNum))
+		require.NoError(t, err)
+		require.NoError(t, w.append(encoded[:partialLength], true))
+		w.close()
+
+		require.NoError(t, env.provider.leveldbProvider.GetDBHandle(ledgerid).DeleteAll())
+		blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+		assert.Equal(t, uint64(4), blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height)
+		blkfileMgrWrapper.testGetBlockByNumber(blocks[:4], 0)
+		blkfileMgrWrapper.close()
+	}
+
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	blkfileMgrWrapper.addBlocks(blocks[4:])
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0)
+}
+
+func TestRollbackCompressedBlocks(t *testing.T) {
+	path := testPath()
+	env := newTestEnv(t, NewConf(path, 0).WithCompression(CompressionConf{Default: SnappyCompression}))
+	defer env.Cleanup()
+	ledgerid := "testLedger"
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+	blkfileMgrWrapper.addBlocks(blocks)
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	require.NoError(t, Rollback(path, ledgerid, 5, nil))
+
+	env = newTestEnv(t, NewConf(path, 0).WithCompression(CompressionConf{Default: SnappyCompression}))
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, ledgerid)
+	defer blkfileMgrWrapper.close()
+	assert.Equal(t, uint64(6), blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[:6], 0)
+	blkfileMgrWrapper.addBlocks(blocks[6:])
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0)
+}
+
+func TestOpenBlockStoreUnknownCompression(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0).WithCompression(CompressionConf{
+		Default: NoCompression,
+		Ledgers: map[string]Compression{"zstdLedger": "zstd"},
+	}))
+	defer env.Cleanup()
+
+	_, err := env.provider.OpenBlockStore("zstdLedger")
+	assert.EqualError(t, err, "invalid configuration of ledger [zstdLedger]: unknown block compression [zstd]")
+
+	store, err := env.provider.OpenBlockStore("testLedger")
+	assert.NoError(t, err)
+	store.Shutdown()
+}
+
+func TestFileLocPointerInEncodedBlock(t *testing.T) {
+	blockIdxInfo := &blockIdxInfo{
+		flp:     &fileLocPointer{fileSuffixNum: 2, locPointer: locPointer{offset: 300}},
+		encoded: true,
+	}
+	txFlp := blockIdxInfo.txLoc(&txindexInfo{loc: &locPointer{offset: 10, bytesLength: 20}})
+	assert.Equal(t, &fileLocPointer{
+		fileSuffixNum:  2,
+		locPointer:     locPointer{offset: 10, bytesLength: 20},
+		inEncodedBlock: true,
+		blockOffset:    300,
+	}, txFlp)
+
+	b, err := txFlp.marshal()
+	require.NoError(t, err)
+	unmarshaled := &fileLocPointer{}
+	require.NoError(t, unmarshaled.unmarshal(b))
+	assert.Equal(t, txFlp, unmarshaled)
+
+	// The locations within plain blocks are marshaled as by previous versions
+	blockIdxInfo.encoded = false
+	txFlp = blockIdxInfo.txLoc(&txindexInfo{loc: &locPointer{offset: 10, bytesLength: 20}})
+	assert.Equal(t, &fileLocPointer{fileSuffixNum: 2, locPointer: locPointer{offset: 310, bytesLength: 20}}, txFlp)
+	b, err = txFlp.marshal()
+	require.NoError(t, err)
+	assert.Len(t, b, 4)
+	unmarshaled = &fileLocPointer{}
+	require.NoError(t, unmarshaled.unmarshal(b))
+	assert.Equal(t, txFlp, unmarshaled)
+}
@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/pkg/errors"
+)
+
+// blockEncoding is the way the bytes of a block are stored in a block file. The blocks
+// with different encodings can be appended to the same block file, as each block is
+// preceded by a header telling its encoding:
+//   - a plain block is preceded by its length
+//   - a compressed block is preceded by a marker and by its length once compressed
+//   - an encrypted block is preceded by two markers and by its length once encrypted.
+//     The encrypted bytes are those of the block, compressed or not, preceded by a flag
+//     telling whether the block is compressed
+//
+// The marker reads as the length of an empty block, which is never appended.
+type blockEncoding int
+
+const (
+	plainBlock blockEncoding = iota
+	compressedBlock
+	encryptedBlock
+)
+
+const (
+	blockEncodingMarker = 0
+	// maxBlockHeaderLength is the length of the longest block header, assuming that the length
+	// of a block would be small enough to be represented in 8 bytes varint
+	maxBlockHeaderLength = 10
+)
+
+// encodeBlock returns the bytes to append to a block file for the given serialized block,
+// that is the block compressed and encrypted as requested, preceded by its header.
+func encodeBlock(blockBytes []byte, compression Compression, encrypter *encryption.Encrypter) ([]byte, blockEncoding, error) {
+	data := blockBytes
+	encoding := plainBlock
+	if compression == SnappyCompression {
+		data = snappy.Encode(nil, blockBytes)
+		encoding = compressedBlock
+	}
+
+	var header []byte
+	switch {
+	case encrypter != nil:
+		plaintext := make([]byte, 0, 1+len(data))
+		if encoding == compressedBlock {
+			plaintext = append(plaintext, 1)
+		} else {
+			plaintext = append(plaintext, 0)
+		}
+		ciphertext, err := encrypter.Encrypt(append(plaintext, data...))
+		if err != nil {
+			return nil, 0, errors.WithMessage(err, "error encrypting block")
+		}
+		data = ciphertext
+		encoding = encryptedBlock
+		header = []byte{blockEncodingMarker, blockEncodingMarker}
+	case encoding == compressedBlock:
+		header = []byte{blockEncodingMarker}
+	}
+
+	encoded := append(header, proto.EncodeVarint(uint64(len(data)))...)
+	return append(encoded, data...), encoding, nil
+}
+
+// decodeBlockHeader decodes the header preceding the bytes of a block in a block file. It
+// returns the encoding and the length of the block as stored, and the length of the header,
+// which is zero if the given bytes do not hold a complete header.
+func decodeBlockHeader(b []byte) (encoding blockEncoding, length uint64, n int) {
+	encoding = plainBlock
+	for n < 2 {
+		l, m := proto.DecodeVarint(b[n:])
+		if m == 0 {
+			return encoding, 0, 0
+		}
+		if l != blockEncodingMarker || m != 1 {
+			return encoding, l, n + m
+		}
+		encoding++
+		n++
+	}
+	length, m := proto.DecodeVarint(b[n:])
+	if m == 0 {
+		return encoding, 0, 0
+	}
+	return encoding, length, n + m
+}
+
+// decodeBlock returns the bytes of a block as serialized, given the bytes stored in a block
+// file with the given encoding.
+func decodeBlock(data []byte, encoding blockEncoding, encrypter *encryption.Encrypter) ([]byte, error) {
+	switch encoding {
+	case compressedBlock:
+		return decompressBlock(data)
+	case encryptedBlock:
+		if encrypter == nil {
+			return nil, errors.New("block is encrypted, the encryption must be enabled to read it")
+		}
+		plaintext, err := encrypter.Decrypt(data)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error decrypting block")
+		}
+		if len(plaintext) == 0 {
+			return nil, errors.New("error decrypting block: no compression flag")
+		}
+		if plaintext[0] == 1 {
+			return decompressBlock(plaintext[1:])
+		}
+		return plaintext[1:], nil
+	default:
+		return data, nil
+	}
+}
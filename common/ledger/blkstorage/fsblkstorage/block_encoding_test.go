@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEncrypter(t *testing.T, csp bccsp.BCCSP) *encryption.Encrypter {
+	ski, err := encryption.GenerateKey(csp)
+	require.NoError(t, err)
+	encrypter, err := encryption.NewEncrypter(csp, encryption.NewKeyProvider(csp, ski))
+	require.NoError(t, err)
+	return encrypter
+}
+
+func TestDecodeBlockHeader(t *testing.T) {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	require.NoError(t, err)
+	encrypter := newTestEncrypter(t, csp)
+	blockBytes := bytes.Repeat([]byte("redundant protobuf"), 100)
+
+	for _, testCase := range []struct {
+		compression Compression
+		encrypter   *encryption.Encrypter
+		encoding    blockEncoding
+	}{
+		{NoCompression, nil, plainBlock},
+		{SnappyCompression, nil, compressedBlock},
+		{NoCompression, encrypter, encryptedBlock},
+		{SnappyCompression, encrypter, encryptedBlock},
+	} {
+		encoded, encoding, err := encodeBlock(blockBytes, testCase.compression, testCase.encrypter)
+		require.NoError(t, err)
+		assert.Equal(t, testCase.encoding, encoding)
+
+		decodedEncoding, length, n := decodeBlockHeader(encoded[:maxBlockHeaderLength])
+		assert.Equal(t, testCase.encoding, decodedEncoding)
+		assert.Equal(t, len(encoded), n+int(length))
+		decoded, err := decodeBlock(encoded[n:], decodedEncoding, encrypter)
+		assert.NoError(t, err)
+		assert.Equal(t, blockBytes, decoded)
+
+		// A partial header is reported as such
+		for i := 0; i < n; i++ {
+			_, _, n := decodeBlockHeader(encoded[:i])
+			assert.Equal(t, 0, n)
+		}
+	}
+
+	encoded, _, err := encodeBlock(blockBytes, NoCompression, encrypter)
+	require.NoError(t, err)
+	_, _, n := decodeBlockHeader(encoded)
+	_, err = decodeBlock(encoded[n:], encryptedBlock, nil)
+	assert.EqualError(t, err, "block is encrypted, the encryption must be enabled to read it")
+}
+
+func TestBlockfileMgrEncryption(t *testing.T) {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	require.NoError(t, err)
+	path := testPath()
+	env := newTestEnv(t, NewConf(path, 0))
+	ledgerid := "testLedger"
+	blocks := testutil.ConstructTestBlocks(t, 40)
+
+	// The encryption may be enabled for an existing ledger, and its key rotated, the blocks
+	// already appended to the block files being read as they were appended
+	encrypter := newTestEncrypter(t, csp)
+	rotatedEncrypter := newTestEncrypter(t, csp)
+	for i, testCase := range []struct {
+		compression Compression
+		encrypter   *encryption.Encrypter
+	}{
+		{NoCompression, nil},
+		{NoCompression, encrypter},
+		{SnappyCompression, encrypter},
+		{SnappyCompression, rotatedEncrypter},
+	} {
+		env.provider.conf.compression.Default = testCase.compression
+		env.provider.conf.encrypter = testCase.encrypter
+		blkfileMgrWrapper := newTestBlockfileWrapper(env, ledgerid)
+		blkfileMgrWrapper.addBlocks(blocks[i*10 : (i+1)*10])
+		blkfileMgrWrapper.close()
+	}
+
+	assertBlocksAndTransactions(t, env, ledgerid, blocks)
+	require.NoError(t, env.provider.leveldbProvider.GetDBHandle(ledgerid).DeleteAll())
+	assertBlocksAndTransactions(t, env, ledgerid, blocks)
+
+	// The transactions of the encrypted blocks are not readable in the block files
+	fileBytes, err := ioutil.ReadFile(deriveBlockfilePath(env.provider.conf.getLedgerBlockDir(ledgerid), 0))
+	require.NoError(t, err)
+	assert.True(t, bytes.Contains(fileBytes, blocks[9].Data.Data[0]))
+	assert.False(t, bytes.Contains(fileBytes, blocks[10].Data.Data[0]))
+
+	env.provider.Close()
+	require.NoError(t, Rollback(path, ledgerid, 35, rotatedEncrypter))
+	env = newTestEnv(t, NewConf(path, 0).WithEncryption(rotatedEncrypter))
+	defer env.Cleanup()
+	assertBlocksAndTransactions(t, env, ledgerid, blocks[:36])
+}
@@ -12,7 +12,7 @@ import (
 	"io"
 	"os"
 
-	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/pkg/errors"
 )
 
@@ -28,6 +28,7 @@ type blockfileStream struct {
 	file          *os.File
 	reader        *bufio.Reader
 	currentOffset int64
+	encrypter     *encryption.Encrypter
 }
 
 // blockStream reads blocks sequentially from multiple files.
@@ -38,6 +39,7 @@ type blockStream struct {
 	currentFileNum    int
 	endFileNum        int
 	currentFileStream *blockfileStream
+	encrypter         *encryption.Encrypter
 }
 
 // blockPlacementInfo captures the information related
@@ -46,12 +48,14 @@ type blockPlacementInfo struct {
 	fileNum          int
 	blockStartOffset int64
 	blockBytesOffset int64
+	blockEndOffset   int64
+	encoding         blockEncoding
 }
 
 ///////////////////////////////////
 // blockfileStream functions
 ////////////////////////////////////
-func newBlockfileStream(rootDir string, fileNum int, startOffset int64) (*blockfileStream, error) {
+func newBlockfileStream(rootDir string, fileNum int, startOffset int64, encrypter *encryption.Encrypter) (*blockfileStream, error) {
 	filePath := deriveBlockfilePath(rootDir, fileNum)
 	logger.Debugf("newBlockfileStream(): filePath=[%s], startOffset=[%d]", filePath, startOffset)
 	var file *os.File
@@ -67,7 +71,7 @@ func newBlockfileStream(rootDir string, fileNum int, startOffset int64) (*blockf
 		panic(fmt.Sprintf("Could not seek block file [%s] to startOffset [%d]. New position = [%d]",
 			filePath, startOffset, newPosition))
 	}
-	s := &blockfileStream{fileNum, file, bufio.NewReader(file), startOffset}
+	s := &blockfileStream{fileNum, file, bufio.NewReader(file), startOffset, encrypter}
 	return s, nil
 }
 
@@ -76,7 +80,7 @@ func (s *blockfileStream) nextBlockBytes() ([]byte, error) {
 	return blockBytes, err
 }
 
-// nextBlockBytesAndPlacementInfo returns bytes for the next block
+// nextBlockBytesAndPlacementInfo returns bytes for the next block, decompressed and decrypted if needed,
 // along with the offset information in the block file.
 // An error `ErrUnexpectedEndOfBlockfile` is returned if a partial written data is detected
 // which is possible towards the tail of the file if a crash had taken place during appending of a block
@@ -94,9 +98,8 @@ func (s *blockfileStream) nextBlockBytesAndPlacementInfo() ([]byte, *blockPlacem
 		return nil, nil, nil
 	}
 	remainingBytes := fileInfo.Size() - s.currentOffset
-	// Peek 8 or smaller number of bytes (if remaining bytes are less than 8)
-	// Assumption is that a block size would be small enough to be represented in 8 bytes varint
-	peekBytes := 8
+	// Peek the bytes of the longest block header or smaller number of bytes (if remaining bytes are less)
+	peekBytes := maxBlockHeaderLength
 	if remainingBytes < int64(peekBytes) {
 		peekBytes = int(remainingBytes)
 		moreContentAvailable = false
@@ -105,9 +108,9 @@ func (s *blockfileStream) nextBlockBytesAndPlacementInfo() ([]byte, *blockPlacem
 	if lenBytes, err = s.reader.Peek(peekBytes); err != nil {
 		return nil, nil, errors.Wrapf(err, "error peeking [%d] bytes from block file", peekBytes)
 	}
-	length, n := proto.DecodeVarint(lenBytes)
+	encoding, length, n := decodeBlockHeader(lenBytes)
 	if n == 0 {
-		// proto.DecodeVarint did not consume any byte at all which means that the bytes
+		// decodeBlockHeader did not consume any byte at all which means that the bytes
 		// representing the size of the block are partial bytes
 		if !moreContentAvailable {
 			return nil, nil, ErrUnexpectedEndOfBlockfile
@@ -129,10 +132,15 @@ func (s *blockfileStream) nextBlockBytesAndPlacementInfo() ([]byte, *blockPlacem
 		logger.Errorf("Error reading [%d] bytes from file number [%d], error: %s", length, s.fileNum, err)
 		return nil, nil, errors.Wrapf(err, "error reading [%d] bytes from file number [%d]", length, s.fileNum)
 	}
+	if blockBytes, err = decodeBlock(blockBytes, encoding, s.encrypter); err != nil {
+		return nil, nil, errors.WithMessage(err, fmt.Sprintf("error reading block at offset [%d] in file number [%d]", s.currentOffset, s.fileNum))
+	}
 	blockPlacementInfo := &blockPlacementInfo{
 		fileNum:          s.fileNum,
 		blockStartOffset: s.currentOffset,
-		blockBytesOffset: s.currentOffset + int64(n)}
+		blockBytesOffset: s.currentOffset + int64(n),
+		blockEndOffset:   s.currentOffset + int64(n) + int64(length),
+		encoding:         encoding}
 	s.currentOffset += int64(n) + int64(length)
 	logger.Debugf("Returning blockbytes - length=[%d], placementInfo={%s}", len(blockBytes), blockPlacementInfo)
 	return blockBytes, blockPlacementInfo, nil
@@ -145,12 +153,12 @@ func (s *blockfileStream) close() error {
 ///////////////////////////////////
 // blockStream functions
 ////////////////////////////////////
-func newBlockStream(rootDir string, startFileNum int, startOffset int64, endFileNum int) (*blockStream, error) {
-	startFileStream, err := newBlockfileStream(rootDir, startFileNum, startOffset)
+func newBlockStream(rootDir string, startFileNum int, startOffset int64, endFileNum int, encrypter *encryption.Encrypter) (*blockStream, error) {
+	startFileStream, err := newBlockfileStream(rootDir, startFileNum, startOffset, encrypter)
 	if err != nil {
 		return nil, err
 	}
-	return &blockStream{rootDir, startFileNum, endFileNum, startFileStream}, nil
+	return &blockStream{rootDir, startFileNum, endFileNum, startFileStream, encrypter}, nil
 }
 
 func (s *blockStream) moveToNextBlockfileStream() error {
@@ -159,7 +167,7 @@ func (s *blockStream) moveToNextBlockfileStream() error {
 		return err
 	}
 	s.currentFileNum++
-	if s.currentFileStream, err = newBlockfileStream(s.rootDir, s.currentFileNum, 0); err != nil {
+	if s.currentFileStream, err = newBlockfileStream(s.rootDir, s.currentFileNum, 0, s.encrypter); err != nil {
 		return err
 	}
 	return nil
@@ -194,6 +202,6 @@ func (s *blockStream) close() error {
 }
 
 func (i *blockPlacementInfo) String() string {
-	return fmt.Sprintf("fileNum=[%d], startOffset=[%d], bytesOffset=[%d]",
-		i.fileNum, i.blockStartOffset, i.blockBytesOffset)
+	return fmt.Sprintf("fileNum=[%d], startOffset=[%d], bytesOffset=[%d], endOffset=[%d], encoding=[%d]",
+		i.fileNum, i.blockStartOffset, i.blockBytesOffset, i.blockEndOffset, i.encoding)
 }
@@ -15,6 +15,7 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
@@ -44,6 +45,17 @@ type blockfileMgr struct {
 	cpInfoCond        *sync.Cond
 	currentFileWriter *blockfileWriter
 	bcInfo            atomic.Value
+	// compression is the compression of the blocks appended to the block files
+	compression Compression
+	// bootstrappingSnapshotInfo is nil unless the ledger was bootstrapped from a snapshot
+	bootstrappingSnapshotInfo *blkstorage.SnapshotInfo
+	// pruningInfo holds the *pruningInfo of the last pruning, if the blocks have been pruned
+	pruningInfo atomic.Value
+	// reencryptionLock prevents the pruning from removing a block file while it is re-encrypted
+	reencryptionLock sync.Mutex
+	// stopReencryption and reencryptionDone are nil unless the blocks are re-encrypted in the background
+	stopReencryption chan struct{}
+	reencryptionDone chan struct{}
 }
 
 /*
@@ -96,7 +108,23 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		panic(fmt.Sprintf("Error creating block storage root dir [%s]: %s", rootDir, err))
 	}
 	// Instantiate the manager, i.e. blockFileMgr structure
-	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore}
+	mgr := &blockfileMgr{rootDir: rootDir, conf: conf, db: indexStore, compression: conf.getCompression(id)}
+
+	// Load the info of the snapshot, if the ledger was bootstrapped from one. The blocks up to the
+	// last block of the snapshot are not present in the block files
+	if mgr.bootstrappingSnapshotInfo, err = loadBootstrappingSnapshotInfo(rootDir); err != nil {
+		panic(fmt.Sprintf("Could not load bootstrapping snapshot info: %s", err))
+	}
+
+	// Load the info of the last pruning, if the blocks have been pruned. The block files below the
+	// first retained file are not considered
+	lastPruningInfo, err := loadPruningInfo(rootDir)
+	if err != nil {
+		panic(fmt.Sprintf("Could not load pruning info: %s", err))
+	}
+	if lastPruningInfo != nil {
+		mgr.pruningInfo.Store(lastPruningInfo)
+	}
 
 	// cp = checkpointInfo, retrieve from the database the file suffix or number of where blocks were stored.
 	// It also retrieves the current size of that file and the last block number that was written to that file.
@@ -107,13 +135,13 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 	}
 	if cpInfo == nil {
 		logger.Info(`Getting block information from block storage`)
-		if cpInfo, err = constructCheckpointInfoFromBlockFiles(rootDir); err != nil {
+		if cpInfo, err = constructCheckpointInfoFromBlockFiles(rootDir, conf.encrypter); err != nil {
 			panic(fmt.Sprintf("Could not build checkpoint info from block files: %s", err))
 		}
 		logger.Debugf("Info constructed by scanning the blocks dir = %s", spew.Sdump(cpInfo))
 	} else {
 		logger.Debug(`Synching block information from block storage (if needed)`)
-		syncCPInfoFromFS(rootDir, cpInfo)
+		syncCPInfoFromFS(rootDir, cpInfo, conf.encrypter)
 	}
 	err = mgr.saveCurrentInfo(cpInfo, true)
 	if err != nil {
@@ -136,6 +164,12 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 	if mgr.index, err = newBlockIndex(indexConfig, indexStore); err != nil {
 		panic(fmt.Sprintf("error in block index: %s", err))
 	}
+	// Complete the pruning, in case it was interrupted by a crash
+	if lastPruningInfo != nil {
+		if err := mgr.removePrunedBlockFiles(lastPruningInfo); err != nil {
+			panic(fmt.Sprintf("Could not complete the pruning of block files: %s", err))
+		}
+	}
 
 	// Update the manager with the checkpoint info and the file writer
 	mgr.cpInfo = cpInfo
@@ -150,6 +184,14 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		CurrentBlockHash:  nil,
 		PreviousBlockHash: nil}
 
+	if cpInfo.isChainEmpty && mgr.bootstrappingSnapshotInfo != nil {
+		lastBlockHeader := mgr.bootstrappingSnapshotInfo.LastBlock.Header
+		bcInfo = &common.BlockchainInfo{
+			Height:            lastBlockHeader.Number + 1,
+			CurrentBlockHash:  lastBlockHeader.Hash(),
+			PreviousBlockHash: lastBlockHeader.PreviousHash}
+	}
+
 	if !cpInfo.isChainEmpty {
 		//If start up is a restart of an existing storage, sync the index from block storage and update BlockchainInfo for external API's
 		mgr.syncIndex()
@@ -165,6 +207,8 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 			PreviousBlockHash: previousBlockHash}
 	}
 	mgr.bcInfo.Store(bcInfo)
+	// Re-encrypt the blocks encrypted with the previous keys, if the encryption key was rotated
+	mgr.initReencryption()
 	return mgr
 }
 
@@ -172,7 +216,7 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 // the file of where the last block was written.  Also retrieves contains the
 // last block number that was written.  At init
 //checkpointInfo:latestFileChunkSuffixNum=[0], latestFileChunksize=[0], lastBlockNumber=[0]
-func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo) {
+func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo, encrypter *encryption.Encrypter) {
 	logger.Debugf("Starting checkpoint=%s", cpInfo)
 	//Checks if the file suffix of where the last block was written exists
 	filePath := deriveBlockfilePath(rootDir, cpInfo.latestFileChunkSuffixNum)
@@ -189,8 +233,8 @@ func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo) {
 		return
 	}
 	//Scan the file system to verify that the checkpoint info stored in db is correct
-	_, endOffsetLastBlock, numBlocks, err := scanForLastCompleteBlock(
-		rootDir, cpInfo.latestFileChunkSuffixNum, int64(cpInfo.latestFileChunksize))
+	lastBlockBytes, endOffsetLastBlock, numBlocks, err := scanForLastCompleteBlock(
+		rootDir, cpInfo.latestFileChunkSuffixNum, int64(cpInfo.latestFileChunksize), encrypter)
 	if err != nil {
 		panic(fmt.Sprintf("Could not open current file for detecting last block in the file: %s", err))
 	}
@@ -200,7 +244,13 @@ func syncCPInfoFromFS(rootDir string, cpInfo *checkpointInfo) {
 	}
 	//Updates the checkpoint info for the actual last block number stored and it's end location
 	if cpInfo.isChainEmpty {
-		cpInfo.lastBlockNumber = uint64(numBlocks - 1)
+		// the first block in the files is not the genesis block if the ledger was bootstrapped
+		// from a snapshot, hence the number is taken from the last block itself
+		lastBlockInfo, err := extractSerializedBlockInfo(lastBlockBytes)
+		if err != nil {
+			panic(fmt.Sprintf("Could not extract info of the last block in the file: %s", err))
+		}
+		cpInfo.lastBlockNumber = lastBlockInfo.blockHeader.Number
 	} else {
 		cpInfo.lastBlockNumber += uint64(numBlocks)
 	}
@@ -213,6 +263,11 @@ func deriveBlockfilePath(rootDir string, suffixNum int) string {
 }
 
 func (mgr *blockfileMgr) close() {
+	if mgr.stopReencryption != nil {
+		close(mgr.stopReencryption)
+		<-mgr.reencryptionDone
+		mgr.stopReencryption = nil
+	}
 	mgr.currentFileWriter.close()
 }
 
@@ -265,9 +320,11 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	txOffsets := info.txOffsets
 	currentOffset := mgr.cpInfo.latestFileChunksize
 
-	blockBytesLen := len(blockBytes)
-	blockBytesEncodedLen := proto.EncodeVarint(uint64(blockBytesLen))
-	totalBytesToAppend := blockBytesLen + len(blockBytesEncodedLen)
+	encodedBlockBytes, encoding, err := encodeBlock(blockBytes, mgr.compression, mgr.conf.encrypter)
+	if err != nil {
+		return err
+	}
+	totalBytesToAppend := len(encodedBlockBytes)
 
 	//Determine if we need to start a new file since the size of this block
 	//exceeds the amount of space left in the current file
@@ -275,13 +332,8 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 		mgr.moveToNextFile()
 		currentOffset = 0
 	}
-	//append blockBytesEncodedLen to the file
-	err = mgr.currentFileWriter.append(blockBytesEncodedLen, false)
-	if err == nil {
-		//append the actual block bytes to the file
-		err = mgr.currentFileWriter.append(blockBytes, true)
-	}
-	if err != nil {
+	//append the block bytes, preceded by their header, to the file
+	if err = mgr.currentFileWriter.append(encodedBlockBytes, true); err != nil {
 		truncateErr := mgr.currentFileWriter.truncateFile(mgr.cpInfo.latestFileChunksize)
 		if truncateErr != nil {
 			panic(fmt.Sprintf("Could not truncate current file to known size after an error during block append: %s", err))
@@ -308,14 +360,17 @@ func (mgr *blockfileMgr) addBlock(block *common.Block) error {
 	//Index block file location pointer updated with file suffex and offset for the new block
 	blockFLP := &fileLocPointer{fileSuffixNum: newCPInfo.latestFileChunkSuffixNum}
 	blockFLP.offset = currentOffset
-	// shift the txoffset because we prepend length of bytes before block bytes
-	for _, txOffset := range txOffsets {
-		txOffset.loc.offset += len(blockBytesEncodedLen)
+	// shift the txoffset because we prepend length of bytes before block bytes. The txoffset of
+	// a compressed or encrypted block is relative to the block bytes once decoded
+	if encoding == plainBlock {
+		for _, txOffset := range txOffsets {
+			txOffset.loc.offset += len(encodedBlockBytes) - len(blockBytes)
+		}
 	}
 	//save the index in the database
 	if err = mgr.index.indexBlock(&blockIdxInfo{
 		blockNum: block.Header.Number, blockHash: blockHash,
-		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata}); err != nil {
+		flp: blockFLP, txOffsets: txOffsets, metadata: block.Metadata, encoded: encoding != plainBlock}); err != nil {
 		return err
 	}
 
@@ -339,6 +394,9 @@ func (mgr *blockfileMgr) syncIndex() error {
 
 	//initialize index to file number:zero, offset:zero and blockNum:0
 	startFileNum := 0
+	if info := mgr.getPruningInfo(); info != nil {
+		startFileNum = info.firstRetainedFileNum
+	}
 	startOffset := 0
 	skipFirstBlock := false
 	//get the last file that blocks were added to using the checkpoint info
@@ -368,7 +426,7 @@ func (mgr *blockfileMgr) syncIndex() error {
 
 	//open a blockstream to the file location that was stored in the index
 	var stream *blockStream
-	if stream, err = newBlockStream(mgr.rootDir, startFileNum, int64(startOffset), endFileNum); err != nil {
+	if stream, err = newBlockStream(mgr.rootDir, startFileNum, int64(startOffset), endFileNum, mgr.conf.encrypter); err != nil {
 		return err
 	}
 	var blockBytes []byte
@@ -401,10 +459,13 @@ func (mgr *blockfileMgr) syncIndex() error {
 		}
 
 		//The blockStartOffset will get applied to the txOffsets prior to indexing within indexBlock(),
-		//therefore just shift by the difference between blockBytesOffset and blockStartOffset
-		numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
-		for _, offset := range info.txOffsets {
-			offset.loc.offset += numBytesToShift
+		//therefore just shift by the difference between blockBytesOffset and blockStartOffset, unless
+		//the block is compressed or encrypted
+		if blockPlacementInfo.encoding == plainBlock {
+			numBytesToShift := int(blockPlacementInfo.blockBytesOffset - blockPlacementInfo.blockStartOffset)
+			for _, offset := range info.txOffsets {
+				offset.loc.offset += numBytesToShift
+			}
 		}
 
 		//Update the blockIndexInfo with what was actually stored in file system
@@ -414,6 +475,7 @@ func (mgr *blockfileMgr) syncIndex() error {
 			locPointer: locPointer{offset: int(blockPlacementInfo.blockStartOffset)}}
 		blockIdxInfo.txOffsets = info.txOffsets
 		blockIdxInfo.metadata = info.metadata
+		blockIdxInfo.encoded = blockPlacementInfo.encoding != plainBlock
 
 		logger.Debugf("syncIndex() indexing block [%d]", blockIdxInfo.blockNum)
 		if err = mgr.index.indexBlock(blockIdxInfo); err != nil {
@@ -453,7 +515,7 @@ func (mgr *blockfileMgr) retrieveBlockByHash(blockHash []byte) (*common.Block, e
 	logger.Debugf("retrieveBlockByHash() - blockHash = [%#v]", blockHash)
 	loc, err := mgr.index.getBlockLocByHash(blockHash)
 	if err != nil {
-		return nil, err
+		return nil, mgr.checkBlockHashNotArchived(blockHash, err)
 	}
 	return mgr.fetchBlock(loc)
 }
@@ -466,6 +528,17 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 		blockNum = mgr.getBlockchainInfo().Height - 1
 	}
 
+	if block, ok, err := mgr.retrieveLastConfigBlock(blockNum); ok || err != nil {
+		return block, err
+	}
+	if err := mgr.checkBlockNotArchived(blockNum); err != nil {
+		return nil, err
+	}
+
+	if mgr.bootstrappingSnapshotInfo != nil && blockNum <= mgr.bootstrappingSnapshotInfo.LastBlock.Header.Number {
+		return retrieveSnapshotBlock(mgr.bootstrappingSnapshotInfo, blockNum)
+	}
+
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -479,14 +552,18 @@ func (mgr *blockfileMgr) retrieveBlockByTxID(txID string) (*common.Block, error)
 	loc, err := mgr.index.getBlockLocByTxID(txID)
 
 	if err != nil {
-		return nil, err
+		return nil, mgr.checkTxNotArchived(txID, err)
 	}
 	return mgr.fetchBlock(loc)
 }
 
 func (mgr *blockfileMgr) retrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error) {
 	logger.Debugf("retrieveTxValidationCodeByTxID() - txID = [%s]", txID)
-	return mgr.index.getTxValidationCodeByTxID(txID)
+	validationCode, err := mgr.index.getTxValidationCodeByTxID(txID)
+	if err != nil {
+		return validationCode, mgr.checkTxNotArchived(txID, err)
+	}
+	return validationCode, nil
 }
 
 func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.BlockHeader, error) {
@@ -514,13 +591,16 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope,
 	logger.Debugf("retrieveTransactionByID() - txId = [%s]", txID)
 	loc, err := mgr.index.getTxLoc(txID)
 	if err != nil {
-		return nil, err
+		return nil, mgr.checkTxNotArchived(txID, err)
 	}
 	return mgr.fetchTransactionEnvelope(loc)
 }
 
 func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByBlockNumTranNum() - blockNum = [%d], tranNum = [%d]", blockNum, tranNum)
+	if err := mgr.checkBlockNotArchived(blockNum); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
 		return nil, err
@@ -544,7 +624,12 @@ func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.E
 	logger.Debugf("Entering fetchTransactionEnvelope() %v\n", lp)
 	var err error
 	var txEnvelopeBytes []byte
-	if txEnvelopeBytes, err = mgr.fetchRawBytes(lp); err != nil {
+	if lp.inEncodedBlock {
+		txEnvelopeBytes, err = mgr.fetchBytesInEncodedBlock(lp)
+	} else {
+		txEnvelopeBytes, err = mgr.fetchRawBytes(lp)
+	}
+	if err != nil {
 		return nil, err
 	}
 	_, n := proto.DecodeVarint(txEnvelopeBytes)
@@ -552,7 +637,10 @@ func (mgr *blockfileMgr) fetchTransactionEnvelope(lp *fileLocPointer) (*common.E
 }
 
 func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
-	stream, err := newBlockfileStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset))
+	if err := mgr.checkLocNotArchived(lp); err != nil {
+		return nil, err
+	}
+	stream, err := newBlockfileStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset), mgr.conf.encrypter)
 	if err != nil {
 		return nil, err
 	}
@@ -565,6 +653,9 @@ func (mgr *blockfileMgr) fetchBlockBytes(lp *fileLocPointer) ([]byte, error) {
 }
 
 func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
+	if err := mgr.checkLocNotArchived(lp); err != nil {
+		return nil, err
+	}
 	filePath := deriveBlockfilePath(mgr.rootDir, lp.fileSuffixNum)
 	reader, err := newBlockfileReader(filePath)
 	if err != nil {
@@ -578,6 +669,19 @@ func (mgr *blockfileMgr) fetchRawBytes(lp *fileLocPointer) ([]byte, error) {
 	return b, nil
 }
 
+// fetchBytesInEncodedBlock returns the bytes at the given location within the bytes
+// of a compressed or encrypted block, once decoded.
+func (mgr *blockfileMgr) fetchBytesInEncodedBlock(lp *fileLocPointer) ([]byte, error) {
+	blockBytes, err := mgr.fetchBlockBytes(&fileLocPointer{fileSuffixNum: lp.fileSuffixNum, locPointer: locPointer{offset: lp.blockOffset}})
+	if err != nil {
+		return nil, err
+	}
+	if lp.offset+lp.bytesLength > len(blockBytes) {
+		return nil, errors.Errorf("location [%s] is out of the bounds of the block, whose length is [%d]", lp, len(blockBytes))
+	}
+	return blockBytes[lp.offset : lp.offset+lp.bytesLength], nil
+}
+
 //Get the current checkpoint information that is stored in the database
 func (mgr *blockfileMgr) loadCurrentInfo() (*checkpointInfo, error) {
 	var b []byte
@@ -606,11 +710,11 @@ func (mgr *blockfileMgr) saveCurrentInfo(i *checkpointInfo, sync bool) error {
 
 // scanForLastCompleteBlock scan a given block file and detects the last offset in the file
 // after which there may lie a block partially written (towards the end of the file in a crash scenario).
-func scanForLastCompleteBlock(rootDir string, fileNum int, startingOffset int64) ([]byte, int64, int, error) {
+func scanForLastCompleteBlock(rootDir string, fileNum int, startingOffset int64, encrypter *encryption.Encrypter) ([]byte, int64, int, error) {
 	//scan the passed file number suffix starting from the passed offset to find the last completed block
 	numBlocks := 0
 	var lastBlockBytes []byte
-	blockStream, errOpen := newBlockfileStream(rootDir, fileNum, startingOffset)
+	blockStream, errOpen := newBlockfileStream(rootDir, fileNum, startingOffset, encrypter)
 	if errOpen != nil {
 		return nil, 0, 0, errOpen
 	}
@@ -43,7 +43,7 @@ func TestBlockFileScanSmallTxOnly(t *testing.T) {
 	_, fileSize, err := util.FileExists(filePath)
 	assert.NoError(t, err)
 
-	lastBlockBytes, endOffsetLastBlock, numBlocks, err := scanForLastCompleteBlock(env.provider.conf.getLedgerBlockDir(ledgerid), 0, 0)
+	lastBlockBytes, endOffsetLastBlock, numBlocks, err := scanForLastCompleteBlock(env.provider.conf.getLedgerBlockDir(ledgerid), 0, 0, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, len(blocks), numBlocks)
 	assert.Equal(t, fileSize, endOffsetLastBlock)
@@ -75,7 +75,7 @@ func TestBlockFileScanSmallTxLastTxIncomplete(t *testing.T) {
 	err = file.Truncate(fileSize - 1)
 	assert.NoError(t, err)
 
-	lastBlockBytes, _, numBlocks, err := scanForLastCompleteBlock(env.provider.conf.getLedgerBlockDir(ledgerid), 0, 0)
+	lastBlockBytes, _, numBlocks, err := scanForLastCompleteBlock(env.provider.conf.getLedgerBlockDir(ledgerid), 0, 0, nil)
 	assert.NoError(t, err)
 	assert.Equal(t, len(blocks)-1, numBlocks)
 
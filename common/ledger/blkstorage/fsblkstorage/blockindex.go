@@ -27,21 +27,29 @@ const (
 	blockNumTranNumIdxKeyPrefix    = 'a'
 	blockTxIDIdxKeyPrefix          = 'b'
 	txValidationResultIdxKeyPrefix = 'v'
+	snapshotTxIDIdxKeyPrefix       = 's'
+	archivedBlockHashIdxKeyPrefix  = 'p'
 	indexCheckpointKeyStr          = "indexCheckpointKey"
 )
 
 var indexCheckpointKey = []byte(indexCheckpointKeyStr)
+var snapshotTxIDMarker = []byte{1}
 var errIndexEmpty = errors.New("NoBlockIndexed")
 
 type index interface {
 	getLastBlockIndexed() (uint64, error)
 	indexBlock(blockIdxInfo *blockIdxInfo) error
 	getBlockLocByHash(blockHash []byte) (*fileLocPointer, error)
+	getArchivedBlockNumByHash(blockHash []byte) (uint64, bool, error)
 	getBlockLocByBlockNum(blockNum uint64) (*fileLocPointer, error)
 	getTxLoc(txID string) (*fileLocPointer, error)
 	getTXLocByBlockNumTranNum(blockNum uint64, tranNum uint64) (*fileLocPointer, error)
 	getBlockLocByTxID(txID string) (*fileLocPointer, error)
 	getTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	txIDExists(txID string) (bool, error)
+	exportTxIDs(handle func(txID string) error) error
+	importTxIDs(txIDs blkstorage.TxIDsIterator) error
+	unindexBlock(blockIdxInfo *blockIdxInfo) error
 }
 
 type blockIdxInfo struct {
@@ -50,6 +58,9 @@ type blockIdxInfo struct {
 	flp       *fileLocPointer
 	txOffsets []*txindexInfo
 	metadata  *common.BlockMetadata
+	// encoded is set if the block is compressed or encrypted in the block file, in
+	// which case the txOffsets are relative to the block bytes once decoded
+	encoded bool
 }
 
 type blockIndex struct {
@@ -124,7 +135,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 				logger.Debugf("txid [%s] is a duplicate of a previous tx. Not indexing in txid-index", txoffset.txID)
 				continue
 			}
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := blockIdxInfo.txLoc(txoffset)
 			logger.Debugf("Adding txLoc [%s] for tx ID: [%s] to txid-index", txFlp, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -137,7 +148,7 @@ func (index *blockIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 	//Index4 - Store BlockNumTranNum will be used to query history data
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockNumTranNum]; ok {
 		for txIterator, txoffset := range txOffsets {
-			txFlp := newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+			txFlp := blockIdxInfo.txLoc(txoffset)
 			logger.Debugf("Adding txLoc [%s] for tx number:[%d] ID: [%s] to blockNumTranNum index", txFlp, txIterator, txoffset.txID)
 			txFlpBytes, marshalErr := txFlp.marshal()
 			if marshalErr != nil {
@@ -184,14 +195,14 @@ func (index *blockIndex) markDuplicateTxids(blockIdxInfo *blockIdxInfo) error {
 			continue
 		}
 
-		loc, err := index.getTxLoc(txid)
-		if loc != nil { // txid is duplicate of a previous tx in the index
+		exists, err := index.txIDExists(txid)
+		if err != nil {
+			return err
+		}
+		if exists { // txid is duplicate of a previous tx in the index
 			txIdxInfo.isDuplicate = true
 			continue
 		}
-		if err != blkstorage.ErrNotFoundInIndex {
-			return err
-		}
 		uniqueTxids[txid] = true
 	}
 	return nil
@@ -213,6 +224,19 @@ func (index *blockIndex) getBlockLocByHash(blockHash []byte) (*fileLocPointer, e
 	return blkLoc, nil
 }
 
+// getArchivedBlockNumByHash returns the number of the pruned block with the given hash. The
+// hashes of the pruned blocks are retained so that their retrieval is reported as archived
+func (index *blockIndex) getArchivedBlockNumByHash(blockHash []byte) (uint64, bool, error) {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockHash]; !ok {
+		return 0, false, blkstorage.ErrAttrNotIndexed
+	}
+	b, err := index.db.Get(constructArchivedBlockHashKey(blockHash))
+	if err != nil || b == nil {
+		return 0, false, err
+	}
+	return decodeBlockNum(b), true, nil
+}
+
 func (index *blockIndex) getBlockLocByBlockNum(blockNum uint64) (*fileLocPointer, error) {
 	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockNum]; !ok {
 		return nil, blkstorage.ErrAttrNotIndexed
@@ -297,6 +321,96 @@ func (index *blockIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidati
 	return result, nil
 }
 
+// txIDExists checks the txid in the index of the committed transactions and in the txids
+// imported from the snapshot that the ledger may have been bootstrapped from, or retained
+// from the pruned blocks
+func (index *blockIndex) txIDExists(txID string) (bool, error) {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; !ok {
+		return false, blkstorage.ErrAttrNotIndexed
+	}
+	for _, key := range [][]byte{constructTxIDKey(txID), constructSnapshotTxIDKey(txID)} {
+		b, err := index.db.Get(key)
+		if err != nil {
+			return false, err
+		}
+		if b != nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// exportTxIDs invokes the handle function for the txids imported from the snapshot or retained
+// from the pruned blocks, if any, followed by the txids of the committed transactions
+func (index *blockIndex) exportTxIDs(handle func(txID string) error) error {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; !ok {
+		return blkstorage.ErrAttrNotIndexed
+	}
+	for _, prefix := range []byte{snapshotTxIDIdxKeyPrefix, txIDIdxKeyPrefix} {
+		itr := index.db.GetIterator([]byte{prefix}, []byte{prefix + 1})
+		for itr.Next() {
+			if err := handle(string(itr.Key()[1:])); err != nil {
+				itr.Release()
+				return err
+			}
+		}
+		err := itr.Error()
+		itr.Release()
+		if err != nil {
+			return errors.Wrap(err, "error while iterating over the txid index")
+		}
+	}
+	return nil
+}
+
+// importTxIDs adds the txids of a snapshot to the index. These entries are kept separate from
+// the index of the committed transactions as there is no block location to point to
+func (index *blockIndex) importTxIDs(txIDs blkstorage.TxIDsIterator) error {
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; !ok {
+		return errors.Errorf("index [%s] is required for importing txids from a snapshot", blkstorage.IndexableAttrTxID)
+	}
+	batch := leveldbhelper.NewUpdateBatch()
+	for {
+		txID, ok, err := txIDs.Next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			break
+		}
+		batch.Put(constructSnapshotTxIDKey(txID), snapshotTxIDMarker)
+		if batch.Len() >= snapshotTxIDsBatchSize {
+			if err := index.db.WriteBatch(batch, true); err != nil {
+				return err
+			}
+			batch = leveldbhelper.NewUpdateBatch()
+		}
+	}
+	return index.db.WriteBatch(batch, true)
+}
+
+// unindexBlock removes the entries of a pruned block from the index. The txids of the block are retained
+// in the same way as the txids imported from a snapshot, so that the duplicate txids are still detected,
+// and the hash of the block is retained so that its retrieval by hash is reported as archived
+func (index *blockIndex) unindexBlock(blockIdxInfo *blockIdxInfo) error {
+	batch := leveldbhelper.NewUpdateBatch()
+	batch.Delete(constructBlockHashKey(blockIdxInfo.blockHash))
+	if _, ok := index.indexItemsMap[blkstorage.IndexableAttrBlockHash]; ok {
+		batch.Put(constructArchivedBlockHashKey(blockIdxInfo.blockHash), encodeBlockNum(blockIdxInfo.blockNum))
+	}
+	batch.Delete(constructBlockNumKey(blockIdxInfo.blockNum))
+	for txNum, txoffset := range blockIdxInfo.txOffsets {
+		batch.Delete(constructBlockNumTranNumKey(blockIdxInfo.blockNum, uint64(txNum)))
+		batch.Delete(constructTxIDKey(txoffset.txID))
+		batch.Delete(constructBlockTxIDKey(txoffset.txID))
+		batch.Delete(constructTxValidationCodeIDKey(txoffset.txID))
+		if _, ok := index.indexItemsMap[blkstorage.IndexableAttrTxID]; ok {
+			batch.Put(constructSnapshotTxIDKey(txoffset.txID), snapshotTxIDMarker)
+		}
+	}
+	return index.db.WriteBatch(batch, true)
+}
+
 func constructBlockNumKey(blockNum uint64) []byte {
 	blkNumBytes := util.EncodeOrderPreservingVarUint64(blockNum)
 	return append([]byte{blockNumIdxKeyPrefix}, blkNumBytes...)
@@ -306,10 +420,18 @@ func constructBlockHashKey(blockHash []byte) []byte {
 	return append([]byte{blockHashIdxKeyPrefix}, blockHash...)
 }
 
+func constructArchivedBlockHashKey(blockHash []byte) []byte {
+	return append([]byte{archivedBlockHashIdxKeyPrefix}, blockHash...)
+}
+
 func constructTxIDKey(txID string) []byte {
 	return append([]byte{txIDIdxKeyPrefix}, []byte(txID)...)
 }
 
+func constructSnapshotTxIDKey(txID string) []byte {
+	return append([]byte{snapshotTxIDIdxKeyPrefix}, []byte(txID)...)
+}
+
 func constructBlockTxIDKey(txID string) []byte {
 	return append([]byte{blockTxIDIdxKeyPrefix}, []byte(txID)...)
 }
@@ -348,6 +470,10 @@ func (lp *locPointer) String() string {
 type fileLocPointer struct {
 	fileSuffixNum int
 	locPointer
+	// inEncodedBlock is set if the location is relative to the bytes, once decoded,
+	// of the compressed or encrypted block at blockOffset in the file
+	inEncodedBlock bool
+	blockOffset    int
 }
 
 func newFileLocationPointer(fileSuffixNum int, beginningOffset int, relativeLP *locPointer) *fileLocPointer {
@@ -371,11 +497,16 @@ func (flp *fileLocPointer) marshal() ([]byte, error) {
 	if e != nil {
 		return nil, e
 	}
+	if flp.inEncodedBlock {
+		if e = buffer.EncodeVarint(uint64(flp.blockOffset)); e != nil {
+			return nil, e
+		}
+	}
 	return buffer.Bytes(), nil
 }
 
 func (flp *fileLocPointer) unmarshal(b []byte) error {
-	buffer := proto.NewBuffer(b)
+	buffer := util.NewBuffer(b)
 	i, e := buffer.DecodeVarint()
 	if e != nil {
 		return e
@@ -392,13 +523,38 @@ func (flp *fileLocPointer) unmarshal(b []byte) error {
 		return e
 	}
 	flp.bytesLength = int(i)
+	// The offset of the block is only present for the locations within encoded blocks
+	if buffer.GetBytesConsumed() < len(b) {
+		if i, e = buffer.DecodeVarint(); e != nil {
+			return e
+		}
+		flp.inEncodedBlock = true
+		flp.blockOffset = int(i)
+	}
 	return nil
 }
 
 func (flp *fileLocPointer) String() string {
+	if flp.inEncodedBlock {
+		return fmt.Sprintf("fileSuffixNum=%d, blockOffset=%d, %s", flp.fileSuffixNum, flp.blockOffset, flp.locPointer.String())
+	}
 	return fmt.Sprintf("fileSuffixNum=%d, %s", flp.fileSuffixNum, flp.locPointer.String())
 }
 
+// txLoc returns the location of the given transaction of the block.
+func (blockIdxInfo *blockIdxInfo) txLoc(txoffset *txindexInfo) *fileLocPointer {
+	flp := blockIdxInfo.flp
+	if !blockIdxInfo.encoded {
+		return newFileLocationPointer(flp.fileSuffixNum, flp.offset, txoffset.loc)
+	}
+	return &fileLocPointer{
+		fileSuffixNum:  flp.fileSuffixNum,
+		locPointer:     *txoffset.loc,
+		inEncodedBlock: true,
+		blockOffset:    flp.offset,
+	}
+}
+
 func (blockIdxInfo *blockIdxInfo) String() string {
 
 	var buffer bytes.Buffer
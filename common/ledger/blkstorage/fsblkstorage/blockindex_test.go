@@ -41,6 +41,9 @@ func (i *noopIndex) indexBlock(blockIdxInfo *blockIdxInfo) error {
 func (i *noopIndex) getBlockLocByHash(blockHash []byte) (*fileLocPointer, error) {
 	return nil, nil
 }
+func (i *noopIndex) getArchivedBlockNumByHash(blockHash []byte) (uint64, bool, error) {
+	return 0, false, nil
+}
 func (i *noopIndex) getBlockLocByBlockNum(blockNum uint64) (*fileLocPointer, error) {
 	return nil, nil
 }
@@ -59,6 +62,22 @@ func (i *noopIndex) getTxValidationCodeByTxID(txID string) (peer.TxValidationCod
 	return peer.TxValidationCode(-1), nil
 }
 
+func (i *noopIndex) txIDExists(txID string) (bool, error) {
+	return false, nil
+}
+
+func (i *noopIndex) exportTxIDs(handle func(txID string) error) error {
+	return nil
+}
+
+func (i *noopIndex) importTxIDs(txIDs blkstorage.TxIDsIterator) error {
+	return nil
+}
+
+func (i *noopIndex) unindexBlock(blockIdxInfo *blockIdxInfo) error {
+	return nil
+}
+
 func TestBlockIndexSync(t *testing.T) {
 	testBlockIndexSync(t, 10, 5, false)
 	testBlockIndexSync(t, 10, 5, true)
@@ -56,7 +56,7 @@ func (itr *blocksItr) initStream() error {
 	if lp, err = itr.mgr.index.getBlockLocByBlockNum(itr.blockNumToRetrieve); err != nil {
 		return err
 	}
-	if itr.stream, err = newBlockStream(itr.mgr.rootDir, lp.fileSuffixNum, int64(lp.offset), -1); err != nil {
+	if itr.stream, err = newBlockStream(itr.mgr.rootDir, lp.fileSuffixNum, int64(lp.offset), -1, itr.mgr.conf.encrypter); err != nil {
 		return err
 	}
 	return nil
@@ -70,6 +70,13 @@ func (itr *blocksItr) shouldClose() bool {
 
 // Next moves the cursor to next block and returns true iff the iterator is not exhausted
 func (itr *blocksItr) Next() (ledger.QueryResult, error) {
+	if err := itr.mgr.checkBlockNotArchived(itr.blockNumToRetrieve); err != nil {
+		return nil, err
+	}
+	if snapshotInfo := itr.mgr.bootstrappingSnapshotInfo; snapshotInfo != nil &&
+		itr.blockNumToRetrieve <= snapshotInfo.LastBlock.Header.Number {
+		return itr.nextSnapshotBlock()
+	}
 	if itr.maxBlockNumAvailable < itr.blockNumToRetrieve {
 		itr.maxBlockNumAvailable = itr.waitForBlock(itr.blockNumToRetrieve)
 	}
@@ -92,6 +99,22 @@ func (itr *blocksItr) Next() (ledger.QueryResult, error) {
 	return deserializeBlock(nextBlockBytes)
 }
 
+// nextSnapshotBlock returns the retained block of the snapshot that the ledger was bootstrapped
+// from, as the blocks up to the last block of the snapshot are not present in the block files
+func (itr *blocksItr) nextSnapshotBlock() (ledger.QueryResult, error) {
+	itr.closeMarkerLock.Lock()
+	defer itr.closeMarkerLock.Unlock()
+	if itr.closeMarker {
+		return nil, nil
+	}
+	block, err := retrieveSnapshotBlock(itr.mgr.bootstrappingSnapshotInfo, itr.blockNumToRetrieve)
+	if err != nil {
+		return nil, err
+	}
+	itr.blockNumToRetrieve++
+	return block, nil
+}
+
 // Close releases any resources held by the iterator
 func (itr *blocksItr) Close() {
 	itr.mgr.cpInfoCond.L.Lock()
@@ -16,7 +16,11 @@ limitations under the License.
 
 package fsblkstorage
 
-import "path/filepath"
+import (
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+)
 
 const (
 	// ChainsDir is the name of the directory containing the channel ledgers.
@@ -30,6 +34,8 @@ const (
 type Conf struct {
 	blockStorageDir  string
 	maxBlockfileSize int
+	compression      CompressionConf
+	encrypter        *encryption.Encrypter
 }
 
 // NewConf constructs new `Conf`.
@@ -38,7 +44,22 @@ func NewConf(blockStorageDir string, maxBlockfileSize int) *Conf {
 	if maxBlockfileSize <= 0 {
 		maxBlockfileSize = defaultMaxBlockfileSize
 	}
-	return &Conf{blockStorageDir, maxBlockfileSize}
+	return &Conf{blockStorageDir: blockStorageDir, maxBlockfileSize: maxBlockfileSize}
+}
+
+// WithCompression sets the compression of the blocks appended to the ledgers and returns the `Conf`.
+func (conf *Conf) WithCompression(compression CompressionConf) *Conf {
+	conf.compression = compression
+	return conf
+}
+
+// WithEncryption sets the encrypter of the blocks appended to the ledgers and returns the `Conf`.
+// The blocks already in the block files are read as they were appended, so the encryption may be
+// enabled for existing ledgers. When the key is rotated, the blocks encrypted with the previous keys
+// are re-encrypted in the background, and the previous keys must remain available until then.
+func (conf *Conf) WithEncryption(encrypter *encryption.Encrypter) *Conf {
+	conf.encrypter = encrypter
+	return conf
 }
 
 func (conf *Conf) getIndexDir() string {
@@ -52,3 +73,10 @@ func (conf *Conf) getChainsDir() string {
 func (conf *Conf) getLedgerBlockDir(ledgerid string) string {
 	return filepath.Join(conf.getChainsDir(), ledgerid)
 }
+
+func (conf *Conf) getCompression(ledgerid string) Compression {
+	if compression, ok := conf.compression.Ledgers[ledgerid]; ok {
+		return compression
+	}
+	return conf.compression.Default
+}
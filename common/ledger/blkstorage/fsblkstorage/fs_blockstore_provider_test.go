@@ -147,6 +147,41 @@ func TestBlockStoreProvider(t *testing.T) {
 
 }
 
+func TestRemoveBlockStore(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+
+	provider := env.provider
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	for _, ledgerid := range []string{"ledger1", "ledger2"} {
+		store, _ := provider.OpenBlockStore(ledgerid)
+		for _, b := range blocks {
+			assert.NoError(t, store.AddBlock(b))
+		}
+		store.Shutdown()
+	}
+
+	assert.NoError(t, provider.Remove("ledger1"))
+
+	exists, err := provider.Exists("ledger1")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+	storeNames, _ := provider.List()
+	assert.Equal(t, []string{"ledger2"}, storeNames)
+
+	// A ledger created afresh with the same id does not inherit the index of the removed one
+	store1, _ := provider.OpenBlockStore("ledger1")
+	defer store1.Shutdown()
+	bcInfo, _ := store1.GetBlockchainInfo()
+	assert.Equal(t, uint64(0), bcInfo.Height)
+	_, err = store1.RetrieveBlockByHash(blocks[0].Header.Hash())
+	assert.Error(t, err)
+
+	store2, _ := provider.OpenBlockStore("ledger2")
+	defer store2.Shutdown()
+	checkBlocks(t, blocks, store2)
+}
+
 func constructLedgerid(id int) string {
 	return fmt.Sprintf("ledger_%d", id)
 }
@@ -0,0 +1,413 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	pruningInfoFile = "pruning.info"
+)
+
+// pruningInfo records the last pruning of a block store. The block files below firstRetainedFileNum
+// are removed from the ledger dir and the blocks below firstRetainedBlockNum are not available, except
+// for the last config block, which the peer retrieves when the channel is initialized. If it is below
+// firstRetainedBlockNum, the last config block is retained in lastConfigBlock, encoded as in a block file
+type pruningInfo struct {
+	firstRetainedFileNum  int
+	firstRetainedBlockNum uint64
+	archiveDir            string
+	lastConfigBlockNum    uint64
+	lastConfigBlock       []byte
+}
+
+// Prune removes the block files that contain only the blocks below retainFromBlockNum
+func (store *fsBlockStore) Prune(retainFromBlockNum uint64, archiveDir string) error {
+	return store.fileMgr.prune(retainFromBlockNum, archiveDir)
+}
+
+// prune removes the block files that contain only the blocks below retainFromBlockNum. The pruning
+// info is persisted before the block files are touched, so that the blocks are reported as archived
+// from then on and a pruning interrupted by a crash is completed when the block store is opened again.
+// The caller is expected to ensure that no block is added concurrently
+func (mgr *blockfileMgr) prune(retainFromBlockNum uint64, archiveDir string) error {
+	if mgr.cpInfo.isChainEmpty {
+		return errors.New("block store does not have any block to prune")
+	}
+	if retainFromBlockNum > mgr.cpInfo.lastBlockNumber {
+		return errors.Errorf("retain from block number [%d] should not be greater than the last block number [%d]",
+			retainFromBlockNum, mgr.cpInfo.lastBlockNumber)
+	}
+	firstFileNum := 0
+	if currentInfo := mgr.getPruningInfo(); currentInfo != nil {
+		if retainFromBlockNum <= currentInfo.firstRetainedBlockNum {
+			logger.Debugf("Blocks below block number [%d] have already been pruned", currentInfo.firstRetainedBlockNum)
+			return nil
+		}
+		firstFileNum = currentInfo.firstRetainedFileNum
+	}
+	if mgr.bootstrappingSnapshotInfo != nil && retainFromBlockNum <= mgr.bootstrappingSnapshotInfo.LastBlock.Header.Number {
+		logger.Debugf("Blocks up to block number [%d] are not present in the block files of the ledger bootstrapped from a snapshot",
+			mgr.bootstrappingSnapshotInfo.LastBlock.Header.Number)
+		return nil
+	}
+
+	loc, err := mgr.index.getBlockLocByBlockNum(retainFromBlockNum)
+	if err != nil {
+		return errors.WithMessage(err, "error retrieving the location of the first block to retain")
+	}
+	if loc.fileSuffixNum <= firstFileNum {
+		logger.Debugf("No block file contains only the blocks below block number [%d]", retainFromBlockNum)
+		return nil
+	}
+	firstRetainedBlockNum, err := firstBlockNumInFile(mgr.rootDir, loc.fileSuffixNum, mgr.conf.encrypter)
+	if err != nil {
+		return err
+	}
+	lastConfigBlockNum, lastConfigBlock, err := mgr.encodeLastConfigBlock(firstRetainedBlockNum)
+	if err != nil {
+		return err
+	}
+
+	info := &pruningInfo{
+		firstRetainedFileNum:  loc.fileSuffixNum,
+		firstRetainedBlockNum: firstRetainedBlockNum,
+		archiveDir:            archiveDir,
+		lastConfigBlockNum:    lastConfigBlockNum,
+		lastConfigBlock:       lastConfigBlock,
+	}
+	mgr.reencryptionLock.Lock()
+	defer mgr.reencryptionLock.Unlock()
+	if err := writePruningInfo(mgr.rootDir, info); err != nil {
+		return err
+	}
+	mgr.pruningInfo.Store(info)
+	logger.Infof("Pruning the blocks below block number [%d]", firstRetainedBlockNum)
+	return mgr.removePrunedBlockFiles(info)
+}
+
+// removePrunedBlockFiles removes the entries of the blocks in the pruned block files from the index
+// and then moves the files to the archive dir, or deletes them. Both the steps can be repeated safely
+func (mgr *blockfileMgr) removePrunedBlockFiles(info *pruningInfo) error {
+	fileNums, err := blockFileNumsBelow(mgr.rootDir, info.firstRetainedFileNum)
+	if err != nil {
+		return err
+	}
+	for _, fileNum := range fileNums {
+		if err := mgr.unindexBlockFile(fileNum); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("error removing the index entries of block file number [%d]", fileNum))
+		}
+		if err := archiveBlockFile(mgr.rootDir, fileNum, info.archiveDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeLastConfigBlock returns the number of the last config block and the block encoded as in a block
+// file, if the block is below firstRetainedBlockNum, or nil otherwise
+func (mgr *blockfileMgr) encodeLastConfigBlock(firstRetainedBlockNum uint64) (uint64, []byte, error) {
+	lastBlock, err := mgr.retrieveBlockByNumber(mgr.cpInfo.lastBlockNumber)
+	if err != nil {
+		return 0, nil, err
+	}
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return 0, nil, errors.WithMessage(err, "error retrieving the last config block number")
+	}
+	if lastConfigBlockNum >= firstRetainedBlockNum {
+		return lastConfigBlockNum, nil, nil
+	}
+	lastConfigBlock, err := mgr.retrieveBlockByNumber(lastConfigBlockNum)
+	if err != nil {
+		return 0, nil, err
+	}
+	blockBytes, _, err := serializeBlock(lastConfigBlock)
+	if err != nil {
+		return 0, nil, err
+	}
+	encoded, _, err := encodeBlock(blockBytes, mgr.compression, mgr.conf.encrypter)
+	return lastConfigBlockNum, encoded, err
+}
+
+// retrieveLastConfigBlock returns the last config block retained by the pruning, if it has the given number
+func (mgr *blockfileMgr) retrieveLastConfigBlock(blockNum uint64) (*common.Block, bool, error) {
+	info := mgr.getPruningInfo()
+	if info == nil || info.lastConfigBlock == nil || info.lastConfigBlockNum != blockNum {
+		return nil, false, nil
+	}
+	encoding, length, n := decodeBlockHeader(info.lastConfigBlock)
+	if n == 0 || uint64(len(info.lastConfigBlock)-n) != length {
+		return nil, false, errors.New("malformed last config block in pruning info")
+	}
+	blockBytes, err := decodeBlock(info.lastConfigBlock[n:], encoding, mgr.conf.encrypter)
+	if err != nil {
+		return nil, false, err
+	}
+	block, err := deserializeBlock(blockBytes)
+	if err != nil {
+		return nil, false, err
+	}
+	return block, true, nil
+}
+
+func (mgr *blockfileMgr) unindexBlockFile(fileNum int) error {
+	stream, err := newBlockfileStream(mgr.rootDir, fileNum, 0, mgr.conf.encrypter)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+	for {
+		blockBytes, err := stream.nextBlockBytes()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			return nil
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return err
+		}
+		if err := mgr.index.unindexBlock(&blockIdxInfo{
+			blockNum:  info.blockHeader.Number,
+			blockHash: info.blockHeader.Hash(),
+			txOffsets: info.txOffsets,
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+func (mgr *blockfileMgr) getPruningInfo() *pruningInfo {
+	info, _ := mgr.pruningInfo.Load().(*pruningInfo)
+	return info
+}
+
+// checkBlockNotArchived returns an error of type *ledger.ErrBlockArchived if the block has been pruned
+func (mgr *blockfileMgr) checkBlockNotArchived(blockNum uint64) error {
+	info := mgr.getPruningInfo()
+	if info == nil || blockNum >= info.firstRetainedBlockNum {
+		return nil
+	}
+	return newErrBlockArchived("block [%d] is archived, the block store retains the blocks from block number [%d]",
+		blockNum, info.firstRetainedBlockNum)
+}
+
+// checkLocNotArchived returns an error of type *ledger.ErrBlockArchived if the location obtained from
+// the index lies in a pruned block file, which is possible until the pruning has been completed
+func (mgr *blockfileMgr) checkLocNotArchived(lp *fileLocPointer) error {
+	info := mgr.getPruningInfo()
+	if info == nil || lp.fileSuffixNum >= info.firstRetainedFileNum {
+		return nil
+	}
+	return newErrBlockArchived("the requested block is archived, the block store retains the blocks from block number [%d]",
+		info.firstRetainedBlockNum)
+}
+
+// checkTxNotArchived converts the error ErrNotFoundInIndex into an error of type *ledger.ErrBlockArchived
+// if the transaction exists but its location has been removed from the index by the pruning
+func (mgr *blockfileMgr) checkTxNotArchived(txID string, err error) error {
+	if err != blkstorage.ErrNotFoundInIndex || mgr.getPruningInfo() == nil {
+		return err
+	}
+	exists, existsErr := mgr.index.txIDExists(txID)
+	if existsErr != nil || !exists {
+		return err
+	}
+	return newErrBlockArchived("transaction [%s] is archived along with its block", txID)
+}
+
+// checkBlockHashNotArchived converts the error ErrNotFoundInIndex into an error of type *ledger.ErrBlockArchived
+// if the block with the given hash has been pruned
+func (mgr *blockfileMgr) checkBlockHashNotArchived(blockHash []byte, err error) error {
+	if err != blkstorage.ErrNotFoundInIndex || mgr.getPruningInfo() == nil {
+		return err
+	}
+	blockNum, archived, archivedErr := mgr.index.getArchivedBlockNumByHash(blockHash)
+	if archivedErr != nil || !archived {
+		return err
+	}
+	return mgr.checkBlockNotArchived(blockNum)
+}
+
+func newErrBlockArchived(format string, args ...interface{}) error {
+	return &ledger.ErrBlockArchived{Msg: fmt.Sprintf(format, args...)}
+}
+
+// LedgersWithPrunedBlocks returns the ids of the ledgers in the block store whose blocks have been pruned
+func LedgersWithPrunedBlocks(blockStorageDir string) ([]string, error) {
+	return listLedgers(blockStorageDir, func(ledgerDir string) (bool, error) {
+		info, err := loadPruningInfo(ledgerDir)
+		return info != nil, err
+	})
+}
+
+func firstBlockNumInFile(rootDir string, fileNum int, encrypter *encryption.Encrypter) (uint64, error) {
+	stream, err := newBlockfileStream(rootDir, fileNum, 0, encrypter)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.close()
+	blockBytes, err := stream.nextBlockBytes()
+	if err != nil {
+		return 0, err
+	}
+	if blockBytes == nil {
+		return 0, errors.Errorf("block file number [%d] does not contain any block", fileNum)
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return 0, err
+	}
+	return info.blockHeader.Number, nil
+}
+
+// blockFileNumsBelow returns, in ascending order, the numbers of the block files present in the
+// ledger dir that are below the given file number
+func blockFileNumsBelow(rootDir string, fileNum int) ([]int, error) {
+	filesInfo, err := ioutil.ReadDir(rootDir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading dir %s", rootDir)
+	}
+	var fileNums []int
+	for _, fileInfo := range filesInfo {
+		if fileInfo.IsDir() || !isBlockFileName(fileInfo.Name()) {
+			continue
+		}
+		num, err := strconv.Atoi(strings.TrimPrefix(fileInfo.Name(), blockfilePrefix))
+		if err != nil {
+			return nil, err
+		}
+		if num < fileNum {
+			fileNums = append(fileNums, num)
+		}
+	}
+	return fileNums, nil
+}
+
+// archiveBlockFile moves the block file to the archive dir, or deletes it if the archive dir is empty
+func archiveBlockFile(rootDir string, fileNum int, archiveDir string) error {
+	filePath := deriveBlockfilePath(rootDir, fileNum)
+	if archiveDir == "" {
+		logger.Infof("Deleting block file [%s]", filePath)
+		return errors.Wrapf(os.Remove(filePath), "error deleting block file [%s]", filePath)
+	}
+	if _, err := util.CreateDirIfMissing(archiveDir); err != nil {
+		return errors.Wrapf(err, "error creating archive dir [%s]", archiveDir)
+	}
+	archivedFilePath := filepath.Join(archiveDir, filepath.Base(filePath))
+	logger.Infof("Moving block file [%s] to [%s]", filePath, archivedFilePath)
+	if err := os.Rename(filePath, archivedFilePath); err == nil {
+		return nil
+	}
+	// the archive dir may be on a different file system
+	if err := copyFile(filePath, archivedFilePath); err != nil {
+		return err
+	}
+	return errors.Wrapf(os.Remove(filePath), "error deleting block file [%s]", filePath)
+}
+
+func copyFile(srcPath, destPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return errors.Wrapf(err, "error opening file [%s]", srcPath)
+	}
+	defer src.Close()
+	dest, err := os.Create(destPath)
+	if err != nil {
+		return errors.Wrapf(err, "error creating file [%s]", destPath)
+	}
+	if _, err := io.Copy(dest, src); err != nil {
+		dest.Close()
+		return errors.Wrapf(err, "error copying file [%s] to [%s]", srcPath, destPath)
+	}
+	if err := dest.Sync(); err != nil {
+		dest.Close()
+		return errors.Wrapf(err, "error syncing file [%s]", destPath)
+	}
+	return errors.Wrapf(dest.Close(), "error closing file [%s]", destPath)
+}
+
+func writePruningInfo(ledgerDir string, info *pruningInfo) error {
+	buffer := proto.NewBuffer(nil)
+	if err := buffer.EncodeVarint(uint64(info.firstRetainedFileNum)); err != nil {
+		return errors.Wrap(err, "error encoding pruning info")
+	}
+	if err := buffer.EncodeVarint(info.firstRetainedBlockNum); err != nil {
+		return errors.Wrap(err, "error encoding pruning info")
+	}
+	if err := buffer.EncodeStringBytes(info.archiveDir); err != nil {
+		return errors.Wrap(err, "error encoding pruning info")
+	}
+	if err := buffer.EncodeVarint(info.lastConfigBlockNum); err != nil {
+		return errors.Wrap(err, "error encoding pruning info")
+	}
+	if err := buffer.EncodeRawBytes(info.lastConfigBlock); err != nil {
+		return errors.Wrap(err, "error encoding pruning info")
+	}
+	return writeFileAtomically(ledgerDir, pruningInfoFile, buffer.Bytes())
+}
+
+// loadPruningInfo loads the pruning info from the ledger dir. It returns nil if the block store
+// has not been pruned
+func loadPruningInfo(ledgerDir string) (*pruningInfo, error) {
+	infoBytes, err := ioutil.ReadFile(filepath.Join(ledgerDir, pruningInfoFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading pruning info from dir [%s]", ledgerDir)
+	}
+	buffer := proto.NewBuffer(infoBytes)
+	fileNum, err := buffer.DecodeVarint()
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding pruning info")
+	}
+	blockNum, err := buffer.DecodeVarint()
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding pruning info")
+	}
+	archiveDir, err := buffer.DecodeStringBytes()
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding pruning info")
+	}
+	lastConfigBlockNum, err := buffer.DecodeVarint()
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding pruning info")
+	}
+	lastConfigBlock, err := buffer.DecodeRawBytes(true)
+	if err != nil {
+		return nil, errors.Wrap(err, "error decoding pruning info")
+	}
+	if len(lastConfigBlock) == 0 {
+		lastConfigBlock = nil
+	}
+	return &pruningInfo{
+		firstRetainedFileNum:  int(fileNum),
+		firstRetainedBlockNum: blockNum,
+		archiveDir:            archiveDir,
+		lastConfigBlockNum:    lastConfigBlockNum,
+		lastConfigBlock:       lastConfigBlock,
+	}, nil
+}
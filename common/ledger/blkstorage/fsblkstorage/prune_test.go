@@ -0,0 +1,234 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrune(t *testing.T) {
+	blockStorageDir := testPath()
+	// a small file size makes the blocks span several block files
+	conf := NewConf(blockStorageDir, 16*1024)
+	env := newTestEnv(t, conf)
+	defer func() { env.Cleanup() }()
+	archiveDir := filepath.Join(blockStorageDir, "archive")
+	ledgerDir := conf.getLedgerBlockDir("ledger1")
+
+	blocks := testutil.ConstructTestBlocks(t, 20)
+	store, err := env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	for _, b := range blocks {
+		assert.NoError(t, store.AddBlock(b))
+	}
+	fileMgr := store.(*fsBlockStore).fileMgr
+	retainLoc, err := fileMgr.index.getBlockLocByBlockNum(10)
+	assert.NoError(t, err)
+	assert.True(t, retainLoc.fileSuffixNum > 0)
+	firstRetainedBlockNum, err := firstBlockNumInFile(ledgerDir, retainLoc.fileSuffixNum, nil)
+	assert.NoError(t, err)
+
+	assert.EqualError(t, store.Prune(20, archiveDir), "retain from block number [20] should not be greater than the last block number [19]")
+	assert.NoError(t, store.Prune(10, archiveDir))
+	info := fileMgr.getPruningInfo()
+	assert.Equal(t, retainLoc.fileSuffixNum, info.firstRetainedFileNum)
+	assert.Equal(t, firstRetainedBlockNum, info.firstRetainedBlockNum)
+	assert.Equal(t, archiveDir, info.archiveDir)
+	// the last config block, which is the first block of the test blocks, is retained
+	assert.Equal(t, uint64(0), info.lastConfigBlockNum)
+	assert.NotNil(t, info.lastConfigBlock)
+	for fileNum := 0; fileNum < retainLoc.fileSuffixNum; fileNum++ {
+		assertNoFile(t, deriveBlockfilePath(ledgerDir, fileNum))
+		assert.FileExists(t, deriveBlockfilePath(archiveDir, fileNum))
+	}
+	// pruning again below the first retained block is a no-op
+	assert.NoError(t, store.Prune(firstRetainedBlockNum, archiveDir))
+	checkPrunedBlocks(t, store, blocks, firstRetainedBlockNum)
+
+	// the pruned block store retains its state on restart and its index can be rebuilt
+	env.provider.Close()
+	assert.EqualError(t, Rollback(blockStorageDir, "ledger1", firstRetainedBlockNum-1, nil),
+		fmt.Sprintf("target block number [%d] should not be less than the first block number [%d] retained by the pruning of ledger [ledger1]",
+			firstRetainedBlockNum-1, firstRetainedBlockNum))
+	assert.NoError(t, Rollback(blockStorageDir, "ledger1", 15, nil))
+	assert.EqualError(t, ResetBlockStore(blockStorageDir, nil), "ledger [ledger1] cannot be reset as its blocks have been pruned")
+	prunedLedgerIDs, err := LedgersWithPrunedBlocks(blockStorageDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ledger1"}, prunedLedgerIDs)
+
+	env = newTestEnv(t, conf)
+	store, err = env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	for _, b := range blocks[16:] {
+		assert.NoError(t, store.AddBlock(b))
+	}
+	checkPrunedBlocks(t, store, blocks, firstRetainedBlockNum)
+
+	// the block files are deleted if no archive dir is given
+	lastRetainLoc, err := store.(*fsBlockStore).fileMgr.index.getBlockLocByBlockNum(19)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Prune(19, ""))
+	for fileNum := retainLoc.fileSuffixNum; fileNum < lastRetainLoc.fileSuffixNum; fileNum++ {
+		assertNoFile(t, deriveBlockfilePath(ledgerDir, fileNum))
+		assertNoFile(t, deriveBlockfilePath(archiveDir, fileNum))
+	}
+	block, err := store.RetrieveBlockByNumber(19)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[19], block))
+}
+
+func TestPruneCompletedOnRestart(t *testing.T) {
+	blockStorageDir := testPath()
+	conf := NewConf(blockStorageDir, 16*1024)
+	env := newTestEnv(t, conf)
+	defer func() { env.Cleanup() }()
+	ledgerDir := conf.getLedgerBlockDir("ledger1")
+
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	addBlocks(t, env, "ledger1", blocks)
+	env.provider.Close()
+
+	// simulate a crash after the pruning info is persisted
+	firstRetainedBlockNum, err := firstBlockNumInFile(ledgerDir, 1, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, writePruningInfo(ledgerDir, &pruningInfo{1, firstRetainedBlockNum, "", 0, nil}))
+	assert.FileExists(t, deriveBlockfilePath(ledgerDir, 0))
+
+	env = newTestEnv(t, conf)
+	store, err := env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	assertNoFile(t, deriveBlockfilePath(ledgerDir, 0))
+	checkPrunedBlocks(t, store, blocks, firstRetainedBlockNum)
+}
+
+func TestPruneBootstrappedFromSnapshot(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+
+	blocks := testutil.ConstructTestBlocks(t, 10)
+	snapshotInfo := &blkstorage.SnapshotInfo{LastBlock: blocks[5], LastConfigBlock: blocks[0]}
+	assert.NoError(t, env.provider.ImportFromSnapshot("ledger1", snapshotInfo, &testTxIDsIterator{}))
+	store, err := env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	for _, b := range blocks[6:] {
+		assert.NoError(t, store.AddBlock(b))
+	}
+
+	// the blocks of the snapshot are not present in the block files
+	assert.NoError(t, store.Prune(5, ""))
+	assert.Nil(t, store.(*fsBlockStore).fileMgr.getPruningInfo())
+	// all the block files of the ledger are retained as the block files start with the block following the snapshot
+	assert.NoError(t, store.Prune(9, ""))
+	assert.Nil(t, store.(*fsBlockStore).fileMgr.getPruningInfo())
+	block, err := store.RetrieveBlockByNumber(6)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[6], block))
+}
+
+func checkPrunedBlocks(t *testing.T, store blkstorage.BlockStore, blocks []*common.Block, firstRetainedBlockNum uint64) {
+	if store.(*fsBlockStore).fileMgr.getPruningInfo().lastConfigBlock != nil {
+		block, err := store.RetrieveBlockByNumber(0)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(blocks[0], block))
+	}
+	prunedBlock := blocks[firstRetainedBlockNum-1]
+	expectedErr := &ledger.ErrBlockArchived{
+		Msg: fmt.Sprintf("block [%d] is archived, the block store retains the blocks from block number [%d]",
+			firstRetainedBlockNum-1, firstRetainedBlockNum),
+	}
+	_, err := store.RetrieveBlockByNumber(firstRetainedBlockNum - 1)
+	assert.Equal(t, expectedErr, err)
+	_, err = store.RetrieveTxByBlockNumTranNum(firstRetainedBlockNum-1, 0)
+	assert.Equal(t, expectedErr, err)
+	itr, err := store.RetrieveBlocks(0)
+	assert.NoError(t, err)
+	_, err = itr.Next()
+	assert.IsType(t, &ledger.ErrBlockArchived{}, err)
+	itr.Close()
+	_, err = store.RetrieveBlockByHash(prunedBlock.Header.Hash())
+	assert.Equal(t, expectedErr, err)
+	_, err = store.RetrieveBlockByHash([]byte("non-existing-hash"))
+	assert.Equal(t, blkstorage.ErrNotFoundInIndex, err)
+
+	// the txids of the pruned blocks are retained for detecting the duplicate txids
+	txID, err := extractTxID(prunedBlock.Data.Data[0])
+	assert.NoError(t, err)
+	exists, err := store.TxIDExists(txID)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	_, err = store.RetrieveTxByID(txID)
+	assert.EqualError(t, err, "transaction ["+txID+"] is archived along with its block")
+	_, err = store.RetrieveBlockByTxID(txID)
+	assert.IsType(t, &ledger.ErrBlockArchived{}, err)
+	_, err = store.RetrieveTxValidationCodeByTxID(txID)
+	assert.EqualError(t, err, "transaction ["+txID+"] is archived along with its block")
+	_, err = store.RetrieveTxValidationCodeByTxID("non-existing-txid")
+	assert.Equal(t, blkstorage.ErrNotFoundInIndex, err)
+
+	for _, b := range blocks[firstRetainedBlockNum:] {
+		block, err := store.RetrieveBlockByNumber(b.Header.Number)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(b, block))
+		block, err = store.RetrieveBlockByHash(b.Header.Hash())
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(b, block))
+	}
+	itr, err = store.RetrieveBlocks(firstRetainedBlockNum)
+	assert.NoError(t, err)
+	defer itr.Close()
+	block, err := itr.Next()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[firstRetainedBlockNum], block.(*common.Block)))
+}
+
+func TestPruneRetainsEncryptedLastConfigBlock(t *testing.T) {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	assert.NoError(t, err)
+	conf := NewConf(testPath(), 16*1024).WithEncryption(newTestEncrypter(t, csp))
+	env := newTestEnv(t, conf)
+	defer func() { env.Cleanup() }()
+
+	blocks := testutil.ConstructTestBlocks(t, 20)
+	store, err := env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	for _, b := range blocks {
+		assert.NoError(t, store.AddBlock(b))
+	}
+	assert.NoError(t, store.Prune(10, ""))
+	info := store.(*fsBlockStore).fileMgr.getPruningInfo()
+	assert.NotContains(t, string(info.lastConfigBlock), string(blocks[0].Data.Data[0]))
+
+	// the retained last config block is available after a restart
+	env.provider.Close()
+	env = newTestEnv(t, conf)
+	store, err = env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	checkPrunedBlocks(t, store, blocks, store.(*fsBlockStore).fileMgr.getPruningInfo().firstRetainedBlockNum)
+}
+
+func TestArchiveBlockFileErrors(t *testing.T) {
+	rootDir := testPath()
+	defer os.RemoveAll(rootDir)
+	err := archiveBlockFile(rootDir, 0, "")
+	assert.Contains(t, err.Error(), "error deleting block file")
+}
+
+func assertNoFile(t *testing.T, filePath string) {
+	_, err := os.Stat(filePath)
+	assert.True(t, os.IsNotExist(err), "file [%s] should not exist", filePath)
+}
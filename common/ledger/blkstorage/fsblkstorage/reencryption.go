@@ -0,0 +1,280 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/pkg/errors"
+)
+
+const (
+	// encryptionKeysFile lists, in the ledger dir, the keys the blocks of the ledger may be encrypted with
+	encryptionKeysFile = "encryption.keys"
+	// reencryptedFilePrefix prefixes the name of a block file being rewritten with its blocks re-encrypted
+	reencryptedFilePrefix = "reencrypting_"
+)
+
+// initReencryption lists the current key among the keys the blocks of the ledger may be encrypted
+// with and, unless it is the only one, re-encrypts the blocks encrypted with the other keys in the
+// background. The block files are rewritten one at a time, with each block re-encrypted at the same
+// offset so that the index remains valid. If the current file holds such blocks, the blocks are
+// appended to a new file from then on, so that the current file can be rewritten as well. The keys
+// of a ledger with blocks which were not listed are unknown, hence all its block files are checked
+func (mgr *blockfileMgr) initReencryption() {
+	encrypter := mgr.conf.encrypter
+	if encrypter == nil {
+		return
+	}
+	if err := removeReencryptedFiles(mgr.rootDir); err != nil {
+		panic(fmt.Sprintf("Could not remove the block files left partially re-encrypted: %s", err))
+	}
+	keysPath := filepath.Join(mgr.rootDir, encryptionKeysFile)
+	_, err := os.Stat(keysPath)
+	listed := err == nil
+	if err := encrypter.RecordKeys(keysPath); err != nil {
+		panic(fmt.Sprintf("Error checking the encryption keys of the block files: %s", err))
+	}
+	if listed || mgr.cpInfo.isChainEmpty {
+		keys, err := ioutil.ReadFile(keysPath)
+		if err != nil {
+			panic(fmt.Sprintf("Error reading the encryption keys of the block files: %s", err))
+		}
+		if encrypter.OnlyCurrentKey(keys) {
+			return
+		}
+	}
+
+	if mgr.cpInfo.latestFileChunksize > 0 {
+		reencrypt, err := hasBlocksToReencrypt(deriveBlockfilePath(mgr.rootDir, mgr.cpInfo.latestFileChunkSuffixNum), encrypter)
+		if err != nil {
+			panic(fmt.Sprintf("Could not scan the current block file for the blocks to re-encrypt: %s", err))
+		}
+		if reencrypt {
+			mgr.moveToNextFile()
+		}
+	}
+	mgr.stopReencryption = make(chan struct{})
+	mgr.reencryptionDone = make(chan struct{})
+	go mgr.reencryptBlockFiles(mgr.cpInfo.latestFileChunkSuffixNum, keysPath)
+}
+
+// reencryptBlockFiles re-encrypts the blocks of the block files below the given file number, and the
+// last config block retained by the pruning, which are encrypted with the previous keys. Once they
+// are all re-encrypted, only the current key is listed. If it is stopped or fails, the re-encryption
+// is resumed when the block store is opened again
+func (mgr *blockfileMgr) reencryptBlockFiles(endFileNum int, keysPath string) {
+	defer close(mgr.reencryptionDone)
+	encrypter := mgr.conf.encrypter
+	logger.Infof("Re-encrypting the blocks of ledger [%s] with the current key", filepath.Base(mgr.rootDir))
+
+	total := 0
+	for fileNum := 0; fileNum < endFileNum; fileNum++ {
+		select {
+		case <-mgr.stopReencryption:
+			return
+		default:
+		}
+		reencrypted, err := mgr.reencryptBlockFile(fileNum)
+		if err != nil {
+			logger.Errorf("Error re-encrypting block file number [%d], the previous keys are still needed: %s", fileNum, err)
+			return
+		}
+		if reencrypted {
+			total++
+		}
+	}
+	if err := mgr.reencryptLastConfigBlock(); err != nil {
+		logger.Errorf("Error re-encrypting the last config block retained by the pruning, the previous keys are still needed: %s", err)
+		return
+	}
+	if err := encrypter.RetireKeys(keysPath); err != nil {
+		logger.Errorf("Error listing the encryption keys of the block files: %s", err)
+		return
+	}
+	logger.Infof("Re-encrypted [%d] block files of ledger [%s] with the current key, the previous keys are no longer needed",
+		total, filepath.Base(mgr.rootDir))
+}
+
+// reencryptBlockFile rewrites the given block file, if it holds blocks encrypted with the previous
+// keys, and returns whether it did. The pruning does not remove the file in the meantime
+func (mgr *blockfileMgr) reencryptBlockFile(fileNum int) (bool, error) {
+	mgr.reencryptionLock.Lock()
+	defer mgr.reencryptionLock.Unlock()
+
+	if info := mgr.getPruningInfo(); info != nil && fileNum < info.firstRetainedFileNum {
+		return false, nil
+	}
+	filePath := deriveBlockfilePath(mgr.rootDir, fileNum)
+	if _, err := os.Stat(filePath); os.IsNotExist(err) {
+		// the block files of a ledger bootstrapped from a snapshot may start above zero
+		return false, nil
+	}
+	reencrypt, err := hasBlocksToReencrypt(filePath, mgr.conf.encrypter)
+	if err != nil || !reencrypt {
+		return false, err
+	}
+	logger.Debugf("Re-encrypting block file [%s]", filePath)
+	return true, rewriteBlockFile(filePath, mgr.conf.encrypter)
+}
+
+// reencryptLastConfigBlock re-encrypts the last config block retained by the pruning, if it is
+// encrypted with a previous key
+func (mgr *blockfileMgr) reencryptLastConfigBlock() error {
+	mgr.reencryptionLock.Lock()
+	defer mgr.reencryptionLock.Unlock()
+
+	info := mgr.getPruningInfo()
+	if info == nil || info.lastConfigBlock == nil {
+		return nil
+	}
+	encoding, _, n := decodeBlockHeader(info.lastConfigBlock)
+	if n == 0 {
+		return errors.New("malformed last config block in pruning info")
+	}
+	data := info.lastConfigBlock[n:]
+	if encoding != encryptedBlock || mgr.conf.encrypter.IsCurrent(data) {
+		return nil
+	}
+	reencrypted, err := reencryptBlockData(data, mgr.conf.encrypter)
+	if err != nil {
+		return err
+	}
+	updatedInfo := *info
+	updatedInfo.lastConfigBlock = append(append([]byte{}, info.lastConfigBlock[:n]...), reencrypted...)
+	if err := writePruningInfo(mgr.rootDir, &updatedInfo); err != nil {
+		return err
+	}
+	mgr.pruningInfo.Store(&updatedInfo)
+	return nil
+}
+
+// hasBlocksToReencrypt returns whether the given block file holds blocks encrypted with the previous keys
+func hasBlocksToReencrypt(filePath string, encrypter *encryption.Encrypter) (bool, error) {
+	reencrypt := false
+	err := forEachEncodedBlock(filePath, func(header, data []byte, encoding blockEncoding) error {
+		if encoding == encryptedBlock && !encrypter.IsCurrent(data) {
+			reencrypt = true
+		}
+		return nil
+	})
+	return reencrypt, err
+}
+
+// rewriteBlockFile writes a copy of the given block file, with the blocks encrypted with the previous
+// keys re-encrypted with the current key, and replaces the file with it. The readers which opened the
+// file beforehand keep reading the blocks as they were, at the same offsets
+func rewriteBlockFile(filePath string, encrypter *encryption.Encrypter) error {
+	tmpPath := filepath.Join(filepath.Dir(filePath), reencryptedFilePrefix+filepath.Base(filePath))
+	tmpFile, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "error creating file [%s]", tmpPath)
+	}
+	writer := bufio.NewWriter(tmpFile)
+	err = forEachEncodedBlock(filePath, func(header, data []byte, encoding blockEncoding) error {
+		if encoding == encryptedBlock && !encrypter.IsCurrent(data) {
+			reencrypted, err := reencryptBlockData(data, encrypter)
+			if err != nil {
+				return err
+			}
+			if len(reencrypted) != len(data) {
+				return errors.Errorf("block re-encrypted with the current key has length [%d] instead of [%d]", len(reencrypted), len(data))
+			}
+			data = reencrypted
+		}
+		if _, err := writer.Write(header); err != nil {
+			return errors.Wrapf(err, "error writing file [%s]", tmpPath)
+		}
+		_, err := writer.Write(data)
+		return errors.Wrapf(err, "error writing file [%s]", tmpPath)
+	})
+	if err == nil {
+		err = errors.Wrapf(writer.Flush(), "error writing file [%s]", tmpPath)
+	}
+	if err == nil {
+		err = errors.Wrapf(tmpFile.Sync(), "error syncing file [%s]", tmpPath)
+	}
+	if closeErr := tmpFile.Close(); err == nil && closeErr != nil {
+		err = errors.Wrapf(closeErr, "error closing file [%s]", tmpPath)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return errors.Wrapf(os.Rename(tmpPath, filePath), "error replacing block file [%s]", filePath)
+}
+
+// forEachEncodedBlock calls f with the header and the data of each block of the given block file,
+// as stored in the file
+func forEachEncodedBlock(filePath string, f func(header, data []byte, encoding blockEncoding) error) error {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return errors.Wrapf(err, "error opening block file [%s]", filePath)
+	}
+	defer file.Close()
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return errors.Wrapf(err, "error getting block file stat")
+	}
+	reader := bufio.NewReader(file)
+	for remainingBytes := fileInfo.Size(); remainingBytes > 0; {
+		peekBytes := maxBlockHeaderLength
+		if remainingBytes < int64(peekBytes) {
+			peekBytes = int(remainingBytes)
+		}
+		headerBytes, err := reader.Peek(peekBytes)
+		if err != nil {
+			return errors.Wrapf(err, "error peeking [%d] bytes from block file", peekBytes)
+		}
+		encoding, length, n := decodeBlockHeader(headerBytes)
+		if n == 0 || int64(n)+int64(length) > remainingBytes {
+			return ErrUnexpectedEndOfBlockfile
+		}
+		block := make([]byte, n+int(length))
+		if _, err := io.ReadFull(reader, block); err != nil {
+			return errors.Wrapf(err, "error reading [%d] bytes from block file [%s]", len(block), filePath)
+		}
+		if err := f(block[:n], block[n:], encoding); err != nil {
+			return err
+		}
+		remainingBytes -= int64(len(block))
+	}
+	return nil
+}
+
+func reencryptBlockData(data []byte, encrypter *encryption.Encrypter) ([]byte, error) {
+	plaintext, err := encrypter.Decrypt(data)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error decrypting block")
+	}
+	reencrypted, err := encrypter.Encrypt(plaintext)
+	return reencrypted, errors.WithMessage(err, "error encrypting block")
+}
+
+// removeReencryptedFiles removes the copies of the block files left by a re-encryption interrupted
+// by a crash before they replaced the block files
+func removeReencryptedFiles(rootDir string) error {
+	filesInfo, err := ioutil.ReadDir(rootDir)
+	if err != nil {
+		return errors.Wrapf(err, "error reading dir %s", rootDir)
+	}
+	for _, fileInfo := range filesInfo {
+		if !fileInfo.IsDir() && strings.HasPrefix(fileInfo.Name(), reencryptedFilePrefix) {
+			if err := os.Remove(filepath.Join(rootDir, fileInfo.Name())); err != nil {
+				return errors.Wrapf(err, "error removing file [%s]", fileInfo.Name())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReencryptionOfBlockFiles(t *testing.T) {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	require.NoError(t, err)
+	oldEncrypter := newTestEncrypter(t, csp)
+	conf := NewConf(testPath(), 16*1024).WithEncryption(oldEncrypter)
+	env := newTestEnv(t, conf)
+	defer func() { env.Cleanup() }()
+
+	blocks := testutil.ConstructTestBlocks(t, 20)
+	store, err := env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	for _, b := range blocks[:15] {
+		require.NoError(t, store.AddBlock(b))
+	}
+	require.NoError(t, store.Prune(5, ""))
+	fileMgr := store.(*fsBlockStore).fileMgr
+	assert.Nil(t, fileMgr.stopReencryption, "the blocks should not be re-encrypted as the key was not rotated")
+	lastFileNum := fileMgr.cpInfo.latestFileChunkSuffixNum
+	env.provider.Close()
+
+	// a copy left by a re-encryption interrupted by a crash is removed
+	rootDir := conf.getLedgerBlockDir("ledger1")
+	leftoverPath := filepath.Join(rootDir, reencryptedFilePrefix+filepath.Base(deriveBlockfilePath(rootDir, 0)))
+	require.NoError(t, ioutil.WriteFile(leftoverPath, []byte("partial"), 0600))
+
+	// the key is rotated, the previous key remains in the keystore
+	newEncrypter := newTestEncrypter(t, csp)
+	conf = NewConf(conf.blockStorageDir, 16*1024).WithEncryption(newEncrypter)
+	env = newTestEnv(t, conf)
+	store, err = env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	fileMgr = store.(*fsBlockStore).fileMgr
+	<-fileMgr.reencryptionDone
+	_, err = os.Stat(leftoverPath)
+	assert.True(t, os.IsNotExist(err))
+	assert.Equal(t, lastFileNum+1, fileMgr.cpInfo.latestFileChunkSuffixNum, "the blocks should be appended to a new file")
+
+	fileNums, err := blockFileNumsBelow(rootDir, lastFileNum+1)
+	require.NoError(t, err)
+	for _, fileNum := range fileNums {
+		reencrypt, err := hasBlocksToReencrypt(deriveBlockfilePath(rootDir, fileNum), newEncrypter)
+		assert.NoError(t, err)
+		assert.False(t, reencrypt, "block file [%d] should be re-encrypted", fileNum)
+	}
+	info := fileMgr.getPruningInfo()
+	_, _, n := decodeBlockHeader(info.lastConfigBlock)
+	assert.True(t, newEncrypter.IsCurrent(info.lastConfigBlock[n:]))
+	keys, err := ioutil.ReadFile(filepath.Join(rootDir, encryptionKeysFile))
+	require.NoError(t, err)
+	assert.Equal(t, newEncrypter.CurrentKeys(), keys)
+
+	// the blocks are read at the same offsets, and the blocks are appended as before
+	for _, b := range blocks[15:] {
+		require.NoError(t, store.AddBlock(b))
+	}
+	checkPrunedBlocks(t, store, blocks, info.firstRetainedBlockNum)
+	for _, b := range blocks[info.firstRetainedBlockNum:] {
+		block, err := store.RetrieveBlockByHash(b.Header.Hash())
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(b, block))
+	}
+	env.provider.Close()
+
+	// the previous key is no longer listed, hence nothing is re-encrypted anymore
+	env = newTestEnv(t, conf)
+	store, err = env.provider.OpenBlockStore("ledger1")
+	require.NoError(t, err)
+	assert.Nil(t, store.(*fsBlockStore).fileMgr.stopReencryption)
+}
+
+func TestRewriteBlockFileFailure(t *testing.T) {
+	rootDir := testPath()
+	defer os.RemoveAll(rootDir)
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	require.NoError(t, err)
+	encrypter := newTestEncrypter(t, csp)
+
+	// a partially written block cannot be re-encrypted, and the file is left as it was
+	filePath := deriveBlockfilePath(rootDir, 0)
+	encoded, _, err := encodeBlock([]byte("block"), NoCompression, newTestEncrypter(t, csp))
+	require.NoError(t, err)
+	require.NoError(t, ioutil.WriteFile(filePath, encoded[:len(encoded)-1], 0600))
+	assert.Equal(t, ErrUnexpectedEndOfBlockfile, rewriteBlockFile(filePath, encrypter))
+	content, err := ioutil.ReadFile(filePath)
+	require.NoError(t, err)
+	assert.Equal(t, encoded[:len(encoded)-1], content)
+	filesInfo, err := ioutil.ReadDir(rootDir)
+	require.NoError(t, err)
+	assert.Len(t, filesInfo, 1)
+}
@@ -0,0 +1,194 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"os"
+
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// ValidateRollbackParams checks that the ledger exists in the block store and that
+// the target block number is below the last block of the ledger. The encrypter is needed to
+// read the ledgers whose blocks are encrypted
+func ValidateRollbackParams(blockStorageDir, ledgerID string, targetBlockNum uint64, encrypter *encryption.Encrypter) error {
+	logger.Infof("Validating the rollback parameters: ledgerID [%s], block number [%d]", ledgerID, targetBlockNum)
+	ledgerDir := NewConf(blockStorageDir, 0).getLedgerBlockDir(ledgerID)
+	exists, _, err := util.FileExists(ledgerDir)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return errors.Errorf("ledgerID [%s] does not exist", ledgerID)
+	}
+
+	cpInfo, err := constructCheckpointInfoFromBlockFiles(ledgerDir, encrypter)
+	if err != nil {
+		return err
+	}
+	if cpInfo.isChainEmpty {
+		return errors.Errorf("ledger [%s] does not contain any block", ledgerID)
+	}
+	lastPruningInfo, err := loadPruningInfo(ledgerDir)
+	if err != nil {
+		return err
+	}
+	if lastPruningInfo != nil && targetBlockNum < lastPruningInfo.firstRetainedBlockNum {
+		return errors.Errorf("target block number [%d] should not be less than the first block number [%d] retained by the pruning of ledger [%s]",
+			targetBlockNum, lastPruningInfo.firstRetainedBlockNum, ledgerID)
+	}
+	if targetBlockNum >= cpInfo.lastBlockNumber {
+		return errors.Errorf("target block number [%d] should be less than the biggest block number [%d]",
+			targetBlockNum, cpInfo.lastBlockNumber)
+	}
+	return nil
+}
+
+// Rollback truncates the block files of the ledger so that the target block becomes
+// the last block and deletes the block index of the ledger. The checkpoint info and the
+// block index are rebuilt from the block files the next time the block store is opened.
+// The caller is expected to ensure that the block store is not in use and, as the state
+// cannot be rebuilt from the blocks of a ledger bootstrapped from a snapshot, that the
+// ledger was not bootstrapped from one
+func Rollback(blockStorageDir, ledgerID string, targetBlockNum uint64, encrypter *encryption.Encrypter) error {
+	if err := ValidateRollbackParams(blockStorageDir, ledgerID, targetBlockNum, encrypter); err != nil {
+		return err
+	}
+	conf := NewConf(blockStorageDir, 0).WithEncryption(encrypter)
+	ledgerDir := conf.getLedgerBlockDir(ledgerID)
+
+	logger.Infof("Rolling back block files of ledger [%s] to block number [%d]", ledgerID, targetBlockNum)
+	if err := truncateBlockFiles(ledgerDir, targetBlockNum, encrypter); err != nil {
+		return err
+	}
+
+	logger.Infof("Dropping block index of ledger [%s]", ledgerID)
+	return dropBlockIndex(conf, ledgerID)
+}
+
+// ResetBlockStore rolls back every ledger in the block store to its genesis block. As the
+// genesis block is not available for the ledgers bootstrapped from a snapshot or with pruned
+// blocks, the block store is left untouched if any such ledger exists
+func ResetBlockStore(blockStorageDir string, encrypter *encryption.Encrypter) error {
+	conf := NewConf(blockStorageDir, 0)
+	exists, _, err := util.FileExists(conf.getChainsDir())
+	if err != nil {
+		return err
+	}
+	if !exists {
+		logger.Info("Block store does not contain any ledger")
+		return nil
+	}
+	ledgerIDs, err := util.ListSubdirs(conf.getChainsDir())
+	if err != nil {
+		return err
+	}
+	bootstrappedLedgerIDs, err := LedgersBootstrappedFromSnapshot(blockStorageDir)
+	if err != nil {
+		return err
+	}
+	if len(bootstrappedLedgerIDs) > 0 {
+		return errors.Errorf("ledger [%s] cannot be reset as it was bootstrapped from a snapshot", bootstrappedLedgerIDs[0])
+	}
+	prunedLedgerIDs, err := LedgersWithPrunedBlocks(blockStorageDir)
+	if err != nil {
+		return err
+	}
+	if len(prunedLedgerIDs) > 0 {
+		return errors.Errorf("ledger [%s] cannot be reset as its blocks have been pruned", prunedLedgerIDs[0])
+	}
+	for _, ledgerID := range ledgerIDs {
+		cpInfo, err := constructCheckpointInfoFromBlockFiles(conf.getLedgerBlockDir(ledgerID), encrypter)
+		if err != nil {
+			return err
+		}
+		if cpInfo.isChainEmpty || cpInfo.lastBlockNumber == 0 {
+			logger.Infof("Ledger [%s] contains no block beyond the genesis block", ledgerID)
+			continue
+		}
+		if err := Rollback(blockStorageDir, ledgerID, 0, encrypter); err != nil {
+			return errors.WithMessage(err, "error resetting ledger "+ledgerID)
+		}
+	}
+	return nil
+}
+
+// truncateBlockFiles scans the block files for the end of the target block, truncates
+// the file containing it at that offset and removes the subsequent block files
+func truncateBlockFiles(ledgerDir string, targetBlockNum uint64, encrypter *encryption.Encrypter) error {
+	lastFileNum, err := retrieveLastFileSuffix(ledgerDir)
+	if err != nil {
+		return err
+	}
+	startFileNum := 0
+	lastPruningInfo, err := loadPruningInfo(ledgerDir)
+	if err != nil {
+		return err
+	}
+	if lastPruningInfo != nil {
+		startFileNum = lastPruningInfo.firstRetainedFileNum
+	}
+	stream, err := newBlockStream(ledgerDir, startFileNum, 0, lastFileNum, encrypter)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+
+	for {
+		blockBytes, placementInfo, err := stream.nextBlockBytesAndPlacementInfo()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			return errors.Errorf("block number [%d] not found in block files", targetBlockNum)
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return err
+		}
+		if info.blockHeader.Number != targetBlockNum {
+			continue
+		}
+
+		if err := os.Truncate(deriveBlockfilePath(ledgerDir, placementInfo.fileNum), placementInfo.blockEndOffset); err != nil {
+			return errors.Wrapf(err, "error truncating block file number [%d]", placementInfo.fileNum)
+		}
+		for fileNum := placementInfo.fileNum + 1; fileNum <= lastFileNum; fileNum++ {
+			if err := os.Remove(deriveBlockfilePath(ledgerDir, fileNum)); err != nil {
+				return errors.Wrapf(err, "error removing block file number [%d]", fileNum)
+			}
+		}
+		return nil
+	}
+}
+
+// dropBlockIndex deletes all the entries of the ledger from the block index db,
+// including the checkpoint info. The txids imported from a snapshot or retained from the
+// pruned blocks are kept as these cannot be rebuilt from the block files
+func dropBlockIndex(conf *Conf, ledgerID string) error {
+	indexProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: conf.getIndexDir(), Encrypter: conf.encrypter})
+	defer indexProvider.Close()
+	indexDB := indexProvider.GetDBHandle(ledgerID)
+
+	itr := indexDB.GetIterator(nil, nil)
+	defer itr.Release()
+	batch := leveldbhelper.NewUpdateBatch()
+	for itr.Next() {
+		key := itr.Key()
+		if len(key) > 0 && (key[0] == snapshotTxIDIdxKeyPrefix || key[0] == archivedBlockHashIdxKeyPrefix) {
+			continue
+		}
+		batch.Delete(key)
+	}
+	if err := itr.Error(); err != nil {
+		return errors.Wrapf(err, "error iterating block index of ledger [%s]", ledgerID)
+	}
+	return indexDB.WriteBatch(batch, true)
+}
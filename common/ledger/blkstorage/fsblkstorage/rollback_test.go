@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollback(t *testing.T) {
+	blockStorageDir := testPath()
+	// a small file size makes the blocks span several block files
+	conf := NewConf(blockStorageDir, 16*1024)
+	env := newTestEnv(t, conf)
+	defer func() { env.Cleanup() }()
+
+	blocks1 := testutil.ConstructTestBlocks(t, 20)
+	blocks2 := testutil.ConstructTestBlocks(t, 5)
+	addBlocks(t, env, "ledger1", blocks1)
+	addBlocks(t, env, "ledger2", blocks2)
+	env.provider.Close()
+
+	lastFileNum, err := retrieveLastFileSuffix(conf.getLedgerBlockDir("ledger1"))
+	assert.NoError(t, err)
+	assert.True(t, lastFileNum > 0)
+
+	assert.NoError(t, Rollback(blockStorageDir, "ledger1", 9, nil))
+
+	env = newTestEnv(t, conf)
+	store1, err := env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	checkBlocks(t, blocks1[:10], store1)
+	// the index entries of the removed blocks are gone
+	txid, err := extractTxID(blocks1[10].Data.Data[0])
+	assert.NoError(t, err)
+	_, err = store1.RetrieveTxByID(txid)
+	assert.Equal(t, blkstorage.ErrNotFoundInIndex, err)
+	// the removed blocks can be committed again
+	for _, b := range blocks1[10:] {
+		assert.NoError(t, store1.AddBlock(b))
+	}
+	checkBlocks(t, blocks1, store1)
+
+	// other ledgers are not affected
+	store2, err := env.provider.OpenBlockStore("ledger2")
+	assert.NoError(t, err)
+	checkBlocks(t, blocks2, store2)
+}
+
+func TestRollbackErrors(t *testing.T) {
+	blockStorageDir := testPath()
+	env := newTestEnv(t, NewConf(blockStorageDir, 0))
+	defer env.Cleanup()
+
+	addBlocks(t, env, "ledger1", testutil.ConstructTestBlocks(t, 5))
+	env.provider.Close()
+
+	err := Rollback(blockStorageDir, "non-existent-ledger", 2, nil)
+	assert.EqualError(t, err, "ledgerID [non-existent-ledger] does not exist")
+
+	err = Rollback(blockStorageDir, "ledger1", 4, nil)
+	assert.EqualError(t, err, "target block number [4] should be less than the biggest block number [4]")
+
+	err = Rollback(blockStorageDir, "ledger1", 10, nil)
+	assert.EqualError(t, err, "target block number [10] should be less than the biggest block number [4]")
+}
+
+func TestResetBlockStore(t *testing.T) {
+	blockStorageDir := testPath()
+	conf := NewConf(blockStorageDir, 0)
+	env := newTestEnv(t, conf)
+	defer func() { env.Cleanup() }()
+
+	blocks1 := testutil.ConstructTestBlocks(t, 5)
+	blocks2 := testutil.ConstructTestBlocks(t, 1)
+	addBlocks(t, env, "ledger1", blocks1)
+	addBlocks(t, env, "ledger2", blocks2)
+	env.provider.Close()
+
+	assert.NoError(t, ResetBlockStore(blockStorageDir, nil))
+
+	env = newTestEnv(t, conf)
+	store1, err := env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	checkBlocks(t, blocks1[:1], store1)
+	store2, err := env.provider.OpenBlockStore("ledger2")
+	assert.NoError(t, err)
+	checkBlocks(t, blocks2, store2)
+}
+
+func addBlocks(t *testing.T, env *testEnv, ledgerID string, blocks []*common.Block) {
+	store, err := env.provider.OpenBlockStore(ledgerID)
+	assert.NoError(t, err)
+	defer store.Shutdown()
+	for _, b := range blocks {
+		assert.NoError(t, store.AddBlock(b))
+	}
+}
@@ -0,0 +1,201 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	bootstrappingSnapshotInfoFile = "bootstrappingSnapshot.info"
+	snapshotTxIDsBatchSize        = 1000
+)
+
+// ImportFromSnapshot bootstraps the block store of a ledger from a snapshot. The txids are imported
+// in the block index and the snapshot info is persisted in the directory of the ledger. As the
+// snapshot info is written last, a partially imported block store is not considered bootstrapped
+// and the import can be attempted again
+func (p *FsBlockstoreProvider) ImportFromSnapshot(ledgerid string, snapshotInfo *blkstorage.SnapshotInfo, txIDs blkstorage.TxIDsIterator) error {
+	if err := validateSnapshotInfo(snapshotInfo); err != nil {
+		return err
+	}
+	ledgerDir := p.conf.getLedgerBlockDir(ledgerid)
+	if _, err := util.CreateDirIfMissing(ledgerDir); err != nil {
+		return err
+	}
+	lastFileNum, err := retrieveLastFileSuffix(ledgerDir)
+	if err != nil {
+		return err
+	}
+	existingInfo, err := loadBootstrappingSnapshotInfo(ledgerDir)
+	if err != nil {
+		return err
+	}
+	if lastFileNum != -1 || existingInfo != nil {
+		return errors.Errorf("block store of ledger [%s] already exists", ledgerid)
+	}
+
+	index, err := newBlockIndex(p.indexConfig, p.leveldbProvider.GetDBHandle(ledgerid))
+	if err != nil {
+		return err
+	}
+	logger.Infof("Importing txids of ledger [%s] from the snapshot", ledgerid)
+	if err := index.importTxIDs(txIDs); err != nil {
+		return err
+	}
+	return writeBootstrappingSnapshotInfo(ledgerDir, snapshotInfo)
+}
+
+// TxIDExists returns true if a transaction with the given id has been committed to the ledger
+func (store *fsBlockStore) TxIDExists(txID string) (bool, error) {
+	return store.fileMgr.index.txIDExists(txID)
+}
+
+// ExportTxIDs invokes the handle function for the id of every transaction committed to the ledger
+func (store *fsBlockStore) ExportTxIDs(handle func(txID string) error) error {
+	return store.fileMgr.index.exportTxIDs(handle)
+}
+
+// GetBootstrappingSnapshotInfo returns the info of the snapshot that the block store was bootstrapped
+// from, or nil if the block store was bootstrapped from the genesis block
+func (store *fsBlockStore) GetBootstrappingSnapshotInfo() (*blkstorage.SnapshotInfo, error) {
+	return store.fileMgr.bootstrappingSnapshotInfo, nil
+}
+
+// LedgersBootstrappedFromSnapshot returns the ids of the ledgers in the block store that were
+// bootstrapped from a snapshot
+func LedgersBootstrappedFromSnapshot(blockStorageDir string) ([]string, error) {
+	return listLedgers(blockStorageDir, func(ledgerDir string) (bool, error) {
+		snapshotInfo, err := loadBootstrappingSnapshotInfo(ledgerDir)
+		return snapshotInfo != nil, err
+	})
+}
+
+// listLedgers returns the ids of the ledgers in the block store whose ledger dir satisfies the filter
+func listLedgers(blockStorageDir string, filter func(ledgerDir string) (bool, error)) ([]string, error) {
+	conf := NewConf(blockStorageDir, 0)
+	exists, _, err := util.FileExists(conf.getChainsDir())
+	if err != nil || !exists {
+		return nil, err
+	}
+	ledgerIDs, err := util.ListSubdirs(conf.getChainsDir())
+	if err != nil {
+		return nil, err
+	}
+	var filteredLedgerIDs []string
+	for _, ledgerID := range ledgerIDs {
+		ok, err := filter(conf.getLedgerBlockDir(ledgerID))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			filteredLedgerIDs = append(filteredLedgerIDs, ledgerID)
+		}
+	}
+	return filteredLedgerIDs, nil
+}
+
+func validateSnapshotInfo(snapshotInfo *blkstorage.SnapshotInfo) error {
+	if snapshotInfo == nil || snapshotInfo.LastBlock == nil || snapshotInfo.LastBlock.Header == nil {
+		return errors.New("last block of the snapshot is missing")
+	}
+	if snapshotInfo.LastConfigBlock == nil || snapshotInfo.LastConfigBlock.Header == nil {
+		return errors.New("last config block of the snapshot is missing")
+	}
+	if snapshotInfo.LastConfigBlock.Header.Number > snapshotInfo.LastBlock.Header.Number {
+		return errors.Errorf("last config block number [%d] should not be greater than the last block number [%d]",
+			snapshotInfo.LastConfigBlock.Header.Number, snapshotInfo.LastBlock.Header.Number)
+	}
+	return nil
+}
+
+// writeBootstrappingSnapshotInfo persists the snapshot info in the ledger dir
+func writeBootstrappingSnapshotInfo(ledgerDir string, snapshotInfo *blkstorage.SnapshotInfo) error {
+	buffer := proto.NewBuffer(nil)
+	for _, block := range []*common.Block{snapshotInfo.LastBlock, snapshotInfo.LastConfigBlock} {
+		blockBytes, err := proto.Marshal(block)
+		if err != nil {
+			return errors.Wrap(err, "error marshaling snapshot block")
+		}
+		if err := buffer.EncodeRawBytes(blockBytes); err != nil {
+			return errors.Wrap(err, "error encoding snapshot block")
+		}
+	}
+
+	return writeFileAtomically(ledgerDir, bootstrappingSnapshotInfoFile, buffer.Bytes())
+}
+
+// writeFileAtomically writes the file in the dir via a temporary file, so that the file is either
+// present in full or absent
+func writeFileAtomically(dir, fileName string, content []byte) error {
+	tempFilePath := filepath.Join(dir, fileName+".tmp")
+	f, err := os.Create(tempFilePath)
+	if err != nil {
+		return errors.Wrapf(err, "error creating file [%s]", tempFilePath)
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "error writing file [%s]", tempFilePath)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return errors.Wrapf(err, "error syncing file [%s]", tempFilePath)
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrapf(err, "error closing file [%s]", tempFilePath)
+	}
+	return os.Rename(tempFilePath, filepath.Join(dir, fileName))
+}
+
+// loadBootstrappingSnapshotInfo loads the snapshot info from the ledger dir. It returns nil if
+// the block store was not bootstrapped from a snapshot
+func loadBootstrappingSnapshotInfo(ledgerDir string) (*blkstorage.SnapshotInfo, error) {
+	infoBytes, err := ioutil.ReadFile(filepath.Join(ledgerDir, bootstrappingSnapshotInfoFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading bootstrapping snapshot info from dir [%s]", ledgerDir)
+	}
+
+	buffer := proto.NewBuffer(infoBytes)
+	blocks := make([]*common.Block, 2)
+	for i := range blocks {
+		blockBytes, err := buffer.DecodeRawBytes(false)
+		if err != nil {
+			return nil, errors.Wrap(err, "error decoding bootstrapping snapshot info")
+		}
+		blocks[i] = &common.Block{}
+		if err := proto.Unmarshal(blockBytes, blocks[i]); err != nil {
+			return nil, errors.Wrap(err, "error unmarshaling bootstrapping snapshot block")
+		}
+	}
+	return &blkstorage.SnapshotInfo{
+		LastBlock:       blocks[0],
+		LastConfigBlock: blocks[1],
+	}, nil
+}
+
+// retrieveSnapshotBlock returns the retained block of the snapshot with the given number. For
+// any other block preceding the snapshot, an error is returned as the block is not available
+func retrieveSnapshotBlock(snapshotInfo *blkstorage.SnapshotInfo, blockNum uint64) (*common.Block, error) {
+	for _, block := range []*common.Block{snapshotInfo.LastBlock, snapshotInfo.LastConfigBlock} {
+		if block.Header.Number == blockNum {
+			return block, nil
+		}
+	}
+	return nil, errors.Errorf("block [%d] is not available as the ledger was bootstrapped from a snapshot of block [%d]",
+		blockNum, snapshotInfo.LastBlock.Header.Number)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTxIDsIterator struct {
+	txIDs []string
+}
+
+func (itr *testTxIDsIterator) Next() (string, bool, error) {
+	if len(itr.txIDs) == 0 {
+		return "", false, nil
+	}
+	txID := itr.txIDs[0]
+	itr.txIDs = itr.txIDs[1:]
+	return txID, true, nil
+}
+
+func TestImportFromSnapshot(t *testing.T) {
+	blockStorageDir := testPath()
+	conf := NewConf(blockStorageDir, 0)
+	env := newTestEnv(t, conf)
+	defer func() { env.Cleanup() }()
+
+	blocks := testutil.ConstructTestBlocks(t, 15)
+	addBlocks(t, env, "sourceLedger", blocks[:10])
+	sourceStore, err := env.provider.OpenBlockStore("sourceLedger")
+	assert.NoError(t, err)
+	txIDs := []string{}
+	assert.NoError(t, sourceStore.ExportTxIDs(func(txID string) error {
+		txIDs = append(txIDs, txID)
+		return nil
+	}))
+	assert.Len(t, txIDs, 9*len(blocks[1].Data.Data)+len(blocks[0].Data.Data))
+
+	snapshotInfo := &blkstorage.SnapshotInfo{LastBlock: blocks[9], LastConfigBlock: blocks[0]}
+	assert.NoError(t, env.provider.ImportFromSnapshot("ledger1", snapshotInfo, &testTxIDsIterator{txIDs}))
+	err = env.provider.ImportFromSnapshot("ledger1", snapshotInfo, &testTxIDsIterator{txIDs})
+	assert.EqualError(t, err, "block store of ledger [ledger1] already exists")
+
+	store, err := env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	bcInfo, err := store.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, &common.BlockchainInfo{
+		Height:            10,
+		CurrentBlockHash:  blocks[9].Header.Hash(),
+		PreviousBlockHash: blocks[9].Header.PreviousHash,
+	}, bcInfo)
+	retrievedInfo, err := store.GetBootstrappingSnapshotInfo()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(snapshotInfo.LastBlock, retrievedInfo.LastBlock))
+	assert.True(t, proto.Equal(snapshotInfo.LastConfigBlock, retrievedInfo.LastConfigBlock))
+
+	// the retained blocks of the snapshot are available, the blocks before are not
+	block, err := store.RetrieveBlockByNumber(9)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[9], block))
+	block, err = store.RetrieveBlockByNumber(0)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[0], block))
+	_, err = store.RetrieveBlockByNumber(5)
+	assert.EqualError(t, err, "block [5] is not available as the ledger was bootstrapped from a snapshot of block [9]")
+
+	// the imported txids are detected as existing
+	txID, err := extractTxID(blocks[5].Data.Data[0])
+	assert.NoError(t, err)
+	exists, err := store.TxIDExists(txID)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = store.TxIDExists("unknownTxID")
+	assert.NoError(t, err)
+	assert.False(t, exists)
+
+	// the blocks following the snapshot can be committed and iterated over
+	for _, b := range blocks[10:] {
+		assert.NoError(t, store.AddBlock(b))
+	}
+	itr, err := store.RetrieveBlocks(9)
+	assert.NoError(t, err)
+	for _, expectedBlock := range blocks[9:] {
+		block, err := itr.Next()
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(expectedBlock, block.(*common.Block)))
+	}
+	itr.Close()
+
+	// the block store retains its state on restart
+	env.provider.Close()
+	env = newTestEnv(t, conf)
+	store, err = env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	bcInfo, err = store.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(15), bcInfo.Height)
+	block, err = store.RetrieveBlockByNumber(12)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[12], block)
+
+	exportedTxIDs := []string{}
+	assert.NoError(t, store.ExportTxIDs(func(txID string) error {
+		exportedTxIDs = append(exportedTxIDs, txID)
+		return nil
+	}))
+	assert.Len(t, exportedTxIDs, len(txIDs)+5*len(blocks[10].Data.Data))
+	env.provider.Close()
+
+	// the blocks committed after the snapshot can be rolled back
+	assert.NoError(t, Rollback(blockStorageDir, "ledger1", 11, nil))
+	err = ResetBlockStore(blockStorageDir, nil)
+	assert.EqualError(t, err, "ledger [ledger1] cannot be reset as it was bootstrapped from a snapshot")
+
+	// the imported txids survive the rollback
+	env = newTestEnv(t, conf)
+	store, err = env.provider.OpenBlockStore("ledger1")
+	assert.NoError(t, err)
+	bcInfo, err = store.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(12), bcInfo.Height)
+	exists, err = store.TxIDExists(txID)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestImportFromSnapshotErrors(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blocks := testutil.ConstructTestBlocks(t, 3)
+
+	err := env.provider.ImportFromSnapshot("ledger1", nil, &testTxIDsIterator{})
+	assert.EqualError(t, err, "last block of the snapshot is missing")
+
+	err = env.provider.ImportFromSnapshot("ledger1", &blkstorage.SnapshotInfo{LastBlock: blocks[1]}, &testTxIDsIterator{})
+	assert.EqualError(t, err, "last config block of the snapshot is missing")
+
+	err = env.provider.ImportFromSnapshot("ledger1", &blkstorage.SnapshotInfo{LastBlock: blocks[1], LastConfigBlock: blocks[2]}, &testTxIDsIterator{})
+	assert.EqualError(t, err, "last config block number [2] should not be greater than the last block number [1]")
+
+	addBlocks(t, env, "ledger2", blocks)
+	err = env.provider.ImportFromSnapshot("ledger2", &blkstorage.SnapshotInfo{LastBlock: blocks[1], LastConfigBlock: blocks[0]}, &testTxIDsIterator{})
+	assert.EqualError(t, err, "block store of ledger [ledger2] already exists")
+}
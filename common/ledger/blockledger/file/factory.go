@@ -19,6 +19,7 @@ package fileledger
 import (
 	"sync"
 
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
@@ -60,16 +61,30 @@ func (flf *fileLedgerFactory) ChainIDs() []string {
 	return chainIDs
 }
 
+// Remove shuts down the ledger of the given chain ID, if it was opened, and removes it
+func (flf *fileLedgerFactory) Remove(chainID string) error {
+	flf.mutex.Lock()
+	defer flf.mutex.Unlock()
+
+	if ledger, ok := flf.ledgers[chainID]; ok {
+		ledger.(*FileLedger).blockStore.(blkstorage.BlockStore).Shutdown()
+		delete(flf.ledgers, chainID)
+	}
+	return flf.blkstorageProvider.Remove(chainID)
+}
+
 // Close releases all resources acquired by the factory
 func (flf *fileLedgerFactory) Close() {
 	flf.blkstorageProvider.Close()
 }
 
-// New creates a new ledger factory
-func New(directory string) blockledger.Factory {
+// New creates a new ledger factory, appending the blocks to the block files
+// of each ledger with the given compression, and encrypted with the given
+// encrypter if not nil
+func New(directory string, compression fsblkstorage.CompressionConf, encrypter *encryption.Encrypter) blockledger.Factory {
 	return &fileLedgerFactory{
 		blkstorageProvider: fsblkstorage.NewProvider(
-			fsblkstorage.NewConf(directory, -1),
+			fsblkstorage.NewConf(directory, -1).WithCompression(compression).WithEncryption(encrypter),
 			&blkstorage.IndexConfig{
 				AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}},
 		),
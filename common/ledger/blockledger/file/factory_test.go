@@ -19,9 +19,11 @@ package fileledger
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/stretchr/testify/assert"
@@ -50,6 +52,14 @@ func (mbsp *mockBlockStoreProvider) List() ([]string, error) {
 	return mbsp.list, mbsp.error
 }
 
+func (mbsp *mockBlockStoreProvider) ImportFromSnapshot(ledgerid string, snapshotInfo *blkstorage.SnapshotInfo, txIDs blkstorage.TxIDsIterator) error {
+	return mbsp.error
+}
+
+func (mbsp *mockBlockStoreProvider) Remove(ledgerid string) error {
+	return mbsp.error
+}
+
 func (mbsp *mockBlockStoreProvider) Close() {
 }
 
@@ -72,21 +82,42 @@ func TestMultiReinitialization(t *testing.T) {
 	dir, err := ioutil.TempDir("", "hyperledger_fabric")
 	assert.NoError(t, err, "Error creating temp dir: %s", err)
 
-	flf := New(dir)
+	flf := New(dir, fsblkstorage.CompressionConf{}, nil)
 	_, err = flf.GetOrCreate(genesisconfig.TestChainID)
 	assert.NoError(t, err, "Error GetOrCreate chain")
 	assert.Equal(t, 1, len(flf.ChainIDs()), "Expected 1 chain")
 	flf.Close()
 
-	flf = New(dir)
+	flf = New(dir, fsblkstorage.CompressionConf{}, nil)
 	_, err = flf.GetOrCreate("foo")
 	assert.NoError(t, err, "Error creating chain")
 	assert.Equal(t, 2, len(flf.ChainIDs()), "Expected chain to be recovered")
 	flf.Close()
 
-	flf = New(dir)
+	flf = New(dir, fsblkstorage.CompressionConf{}, nil)
 	_, err = flf.GetOrCreate("bar")
 	assert.NoError(t, err, "Error creating chain")
 	assert.Equal(t, 3, len(flf.ChainIDs()), "Expected chain to be recovered")
 	flf.Close()
 }
+
+func TestRemove(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.NoError(t, err, "Error creating temp dir: %s", err)
+	defer os.RemoveAll(dir)
+
+	flf := New(dir, fsblkstorage.CompressionConf{}, nil)
+	defer flf.Close()
+	for _, chainID := range []string{"foo", "bar"} {
+		fl, err := flf.GetOrCreate(chainID)
+		assert.NoError(t, err, "Error GetOrCreate chain")
+		assert.NoError(t, fl.Append(genesisBlock))
+	}
+
+	assert.NoError(t, flf.Remove("foo"))
+	assert.Equal(t, []string{"bar"}, flf.ChainIDs())
+
+	fl, err := flf.GetOrCreate("foo")
+	assert.NoError(t, err, "Error GetOrCreate chain")
+	assert.Zero(t, fl.Height(), "Expected removed chain to be created afresh")
+}
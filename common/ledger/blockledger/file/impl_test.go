@@ -15,6 +15,8 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 	cl "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -40,7 +42,7 @@ func initialize(t *testing.T) (*testEnv, *FileLedger) {
 	name, err := ioutil.TempDir("", "hyperledger_fabric")
 	assert.NoError(t, err, "Error creating temp dir: %s", err)
 
-	flf := New(name).(*fileLedgerFactory)
+	flf := New(name, fsblkstorage.CompressionConf{}, nil).(*fileLedgerFactory)
 	fl, err := flf.GetOrCreate(genesisconfig.TestChainID)
 	assert.NoError(t, err, "Error GetOrCreate chain")
 
@@ -107,6 +109,22 @@ func (mbs *mockBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return mbs.txValidationCode, mbs.defaultError
 }
 
+func (mbs *mockBlockStore) TxIDExists(txID string) (bool, error) {
+	return false, mbs.defaultError
+}
+
+func (mbs *mockBlockStore) ExportTxIDs(handle func(txID string) error) error {
+	return mbs.defaultError
+}
+
+func (mbs *mockBlockStore) GetBootstrappingSnapshotInfo() (*blkstorage.SnapshotInfo, error) {
+	return nil, mbs.defaultError
+}
+
+func (mbs *mockBlockStore) Prune(retainFromBlockNum uint64, archiveDir string) error {
+	return mbs.defaultError
+}
+
 func (*mockBlockStore) Shutdown() {
 }
 
@@ -154,7 +172,7 @@ func TestReinitialization(t *testing.T) {
 	tev.shutDown()
 
 	// re-initialize the ledger provider (not the test ledger itself!)
-	provider2 := New(tev.location)
+	provider2 := New(tev.location, fsblkstorage.CompressionConf{}, nil)
 
 	// assert expected ledgers exist
 	chains := provider2.ChainIDs()
@@ -0,0 +1,284 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leveldbhelper
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+// encryptionMarkerKey is present, outside of the named dbs, in the leveldbs whose values
+// are encrypted. As the encrypted values cannot be told apart from the plain ones, all the
+// values of a leveldb are either encrypted or not. Its value is 1 followed by the list of
+// the keys the values may be encrypted with, which is missing from the leveldbs encrypted
+// before the keys were listed.
+var encryptionMarkerKey = []byte("\xffencrypted")
+
+// encryptionProgressKey is present while the plain values of a leveldb are being encrypted,
+// once the encryption is enabled, and holds the key from which the values are still plain.
+var encryptionProgressKey = []byte("\xffencrypting")
+
+// reencryptionBatchSize is the number of entries the values of which are re-encrypted
+// at once, while the writes to the leveldb are held.
+var reencryptionBatchSize = 100
+
+// valueEncryption encrypts the values of a leveldb. When the current key of the encrypter
+// is rotated, the values encrypted with the previous keys are re-encrypted in the background,
+// until then the previous keys are still used to decrypt them. A nil valueEncryption leaves
+// the values as they are. The keys are never encrypted, as the range queries rely on their
+// order.
+type valueEncryption struct {
+	encrypter *encryption.Encrypter
+	db        *DB
+	// writeLock is held by the writes to the leveldb, and exclusively by the re-encryption
+	// of each batch of values, so that it does not overwrite a value which was just written
+	writeLock   sync.RWMutex
+	stop        chan struct{}
+	reencrypted chan struct{}
+}
+
+// newValueEncryption checks that the values of the given opened leveldb are encrypted only
+// if the encrypter is set. When the encrypter is set, it encrypts the values of the leveldb
+// if they are plain, and starts re-encrypting the values encrypted with the keys which were
+// rotated.
+func newValueEncryption(db *DB, encrypter *encryption.Encrypter) *valueEncryption {
+	marker, err := db.Get(encryptionMarkerKey)
+	if err != nil {
+		panic(fmt.Sprintf("Error checking the encryption of leveldb: %s", err))
+	}
+	if encrypter == nil {
+		if marker != nil {
+			panic(fmt.Sprintf("The values of leveldb [%s] are encrypted, the encryption must be enabled to open it", db.conf.DBPath))
+		}
+		return nil
+	}
+
+	e := &valueEncryption{
+		encrypter:   encrypter,
+		db:          db,
+		stop:        make(chan struct{}),
+		reencrypted: make(chan struct{}),
+	}
+	if marker == nil {
+		marker = append([]byte{1}, encrypter.CurrentKeys()...)
+		if err := e.markEncrypted(marker); err != nil {
+			panic(fmt.Sprintf("Error marking leveldb [%s] as encrypted: %s", db.conf.DBPath, err))
+		}
+	}
+	if err := e.encryptPlainValues(); err != nil {
+		panic(fmt.Sprintf("Error encrypting the values of leveldb [%s]: %s", db.conf.DBPath, err))
+	}
+
+	// The values of a leveldb the keys of which are not listed are re-encrypted, and the
+	// keys listed once they are
+	listed := len(marker) > 1
+	keys, err := encrypter.AddCurrentKey(marker[1:])
+	if err != nil {
+		panic(fmt.Sprintf("Error checking the encryption keys of leveldb [%s]: %s", db.conf.DBPath, err))
+	}
+	if listed && encrypter.OnlyCurrentKey(keys) {
+		close(e.reencrypted)
+		return e
+	}
+	if listed {
+		if err := db.Put(encryptionMarkerKey, append([]byte{1}, keys...), true); err != nil {
+			panic(fmt.Sprintf("Error listing the encryption keys of leveldb [%s]: %s", db.conf.DBPath, err))
+		}
+	}
+	go e.reencrypt()
+	return e
+}
+
+// markEncrypted marks the leveldb as encrypted, and its values, if any, as plain from the
+// first key on.
+func (e *valueEncryption) markEncrypted(marker []byte) error {
+	itr := e.db.GetIterator(nil, nil)
+	empty := !itr.Next()
+	itr.Release()
+
+	batch := &leveldb.Batch{}
+	batch.Put(encryptionMarkerKey, marker)
+	if !empty {
+		logger.Infof("Encrypting the values of leveldb [%s]", e.db.conf.DBPath)
+		batch.Put(encryptionProgressKey, []byte{1})
+	}
+	return e.db.WriteBatch(batch, true)
+}
+
+// encryptPlainValues encrypts the values which are still plain, from the key recorded
+// in the progress of the encryption, batch by batch along with the progress, so that the
+// encryption resumes where it stopped after a crash. It is called before the leveldb is
+// used, as the plain values cannot be told apart from the encrypted ones.
+func (e *valueEncryption) encryptPlainValues() error {
+	progress, err := e.db.Get(encryptionProgressKey)
+	if err != nil || progress == nil {
+		return err
+	}
+
+	startKey := progress[1:]
+	total := 0
+	for {
+		batch := &leveldb.Batch{}
+		nextKey, err := e.forEachInBatch(startKey, func(key, value []byte) error {
+			encrypted, err := e.encrypter.Encrypt(value)
+			if err != nil {
+				return err
+			}
+			batch.Put(key, encrypted)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		total += batch.Len()
+		if nextKey == nil {
+			batch.Delete(encryptionProgressKey)
+		} else {
+			batch.Put(encryptionProgressKey, append([]byte{1}, nextKey...))
+		}
+		if err := e.db.WriteBatch(batch, true); err != nil {
+			return err
+		}
+		if nextKey == nil {
+			logger.Infof("Encrypted [%d] values of leveldb [%s]", total, e.db.conf.DBPath)
+			return nil
+		}
+		startKey = nextKey
+	}
+}
+
+func (e *valueEncryption) encrypt(value []byte) ([]byte, error) {
+	if e == nil {
+		return value, nil
+	}
+	return e.encrypter.Encrypt(value)
+}
+
+func (e *valueEncryption) decrypt(value []byte) ([]byte, error) {
+	if e == nil || value == nil {
+		return value, nil
+	}
+	return e.encrypter.Decrypt(value)
+}
+
+func (e *valueEncryption) beginWrite() {
+	if e != nil {
+		e.writeLock.RLock()
+	}
+}
+
+func (e *valueEncryption) endWrite() {
+	if e != nil {
+		e.writeLock.RUnlock()
+	}
+}
+
+// close stops the re-encryption, if it is still running, and waits for it to return.
+func (e *valueEncryption) close() {
+	if e == nil {
+		return
+	}
+	close(e.stop)
+	<-e.reencrypted
+}
+
+func (e *valueEncryption) reencrypt() {
+	defer close(e.reencrypted)
+
+	var startKey []byte
+	total := 0
+	for {
+		select {
+		case <-e.stop:
+			return
+		default:
+		}
+
+		e.writeLock.Lock()
+		nextKey, count, err := e.reencryptBatch(startKey)
+		e.writeLock.Unlock()
+		if err != nil {
+			logger.Errorf("Error re-encrypting the values of leveldb [%s], the previous keys are still needed: %s", e.db.conf.DBPath, err)
+			return
+		}
+		total += count
+		if nextKey == nil {
+			if err := e.db.Put(encryptionMarkerKey, append([]byte{1}, e.encrypter.CurrentKeys()...), true); err != nil {
+				logger.Errorf("Error listing the encryption keys of leveldb [%s]: %s", e.db.conf.DBPath, err)
+				return
+			}
+			logger.Infof("Re-encrypted [%d] values of leveldb [%s] with the current key, the previous keys are no longer needed", total, e.db.conf.DBPath)
+			return
+		}
+		startKey = nextKey
+	}
+}
+
+// reencryptBatch re-encrypts the values of a batch of entries from the given key, if they
+// are not encrypted with the current key. It returns the key to continue from, which is
+// nil once all the entries have been considered, along with the number of values re-encrypted.
+func (e *valueEncryption) reencryptBatch(startKey []byte) ([]byte, int, error) {
+	batch := &leveldb.Batch{}
+	nextKey, err := e.forEachInBatch(startKey, func(key, value []byte) error {
+		if e.encrypter.IsCurrent(value) {
+			return nil
+		}
+		plaintext, err := e.encrypter.Decrypt(value)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("error decrypting value of key [%#v]", key))
+		}
+		reencrypted, err := e.encrypter.Encrypt(plaintext)
+		if err != nil {
+			return err
+		}
+		batch.Put(key, reencrypted)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	if batch.Len() > 0 {
+		if err := e.db.WriteBatch(batch, false); err != nil {
+			return nil, 0, err
+		}
+	}
+	return nextKey, batch.Len(), nil
+}
+
+// forEachInBatch calls the given function with each of a batch of entries from the given
+// key, other than the ones which track the encryption. It returns the key to continue from,
+// which is nil once all the entries have been considered.
+func (e *valueEncryption) forEachInBatch(startKey []byte, f func(key, value []byte) error) ([]byte, error) {
+	itr := e.db.GetIterator(startKey, nil)
+	defer itr.Release()
+
+	entries := 0
+	for entries < reencryptionBatchSize && itr.Next() {
+		entries++
+		key, value := itr.Key(), itr.Value()
+		if bytes.Equal(key, encryptionMarkerKey) || bytes.Equal(key, encryptionProgressKey) {
+			continue
+		}
+		if err := f(append([]byte{}, key...), value); err != nil {
+			return nil, err
+		}
+	}
+	if err := itr.Error(); err != nil {
+		return nil, errors.Wrap(err, "error iterating leveldb")
+	}
+
+	if entries < reencryptionBatchSize {
+		return nil, nil
+	}
+	// The key following the last entry
+	return append(append([]byte{}, itr.Key()...), 0x00), nil
+}
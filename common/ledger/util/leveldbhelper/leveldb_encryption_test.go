@@ -0,0 +1,233 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leveldbhelper
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestEncrypter(t *testing.T, csp bccsp.BCCSP) *encryption.Encrypter {
+	ski, err := encryption.GenerateKey(csp)
+	require.NoError(t, err)
+	encrypter, err := encryption.NewEncrypter(csp, encryption.NewKeyProvider(csp, ski))
+	require.NoError(t, err)
+	return encrypter
+}
+
+func newTestCSP(t *testing.T) bccsp.BCCSP {
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	require.NoError(t, err)
+	return csp
+}
+
+func TestEncryptedValues(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+	encrypter := newTestEncrypter(t, newTestCSP(t))
+	p := NewProvider(&Conf{DBPath: testDBPath, Encrypter: encrypter})
+	defer p.Close()
+	db := p.GetDBHandle("db")
+
+	require.NoError(t, db.Put([]byte("key1"), []byte("value1"), true))
+	batch := NewUpdateBatch()
+	batch.Put([]byte("key2"), []byte("value2"))
+	batch.Put([]byte("key3"), []byte("value3"))
+	require.NoError(t, db.WriteBatch(batch, true))
+
+	value, err := db.Get([]byte("key1"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value1"), value)
+	value, err = db.Get([]byte("missing"))
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+	itr := db.GetIterator(nil, nil)
+	checkItrResults(t, itr, []string{"key1", "key2", "key3"}, []string{"value1", "value2", "value3"})
+	itr.Release()
+
+	// The values are stored encrypted, the keys are not
+	rawValue, err := p.db.Get(constructLevelKey("db", []byte("key2")))
+	assert.NoError(t, err)
+	assert.True(t, encryption.IsEncrypted(rawValue))
+	assert.NotContains(t, string(rawValue), "value2")
+}
+
+func TestEncryptionOfExistingDB(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+	defer func(batchSize int) { reencryptionBatchSize = batchSize }(reencryptionBatchSize)
+	reencryptionBatchSize = 3
+	encrypter := newTestEncrypter(t, newTestCSP(t))
+
+	p := NewProvider(&Conf{DBPath: testDBPath})
+	for i := 0; i < 10; i++ {
+		require.NoError(t, p.GetDBHandle("db").Put([]byte(createTestKey(i)), []byte(createTestValue("db", i)), true))
+	}
+	p.Close()
+
+	// The plain values are encrypted once the encryption is enabled
+	p = NewProvider(&Conf{DBPath: testDBPath, Encrypter: encrypter})
+	checkEncryptedWith(t, p, encrypter)
+	itr := p.GetDBHandle("db").GetIterator(nil, nil)
+	checkItrResults(t, itr, createTestKeys(0, 9), createTestValues("db", 0, 9))
+	itr.Release()
+	progress, err := p.db.Get(encryptionProgressKey)
+	assert.NoError(t, err)
+	assert.Nil(t, progress)
+	p.Close()
+
+	assert.PanicsWithValue(t,
+		fmt.Sprintf("The values of leveldb [%s] are encrypted, the encryption must be enabled to open it", testDBPath),
+		func() { NewProvider(&Conf{DBPath: testDBPath}) },
+	)
+}
+
+func TestEncryptionResumption(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+	encrypter := newTestEncrypter(t, newTestCSP(t))
+
+	p := NewProvider(&Conf{DBPath: testDBPath})
+	db := p.GetDBHandle("db")
+	for i := 0; i < 10; i++ {
+		require.NoError(t, db.Put([]byte(createTestKey(i)), []byte(createTestValue("db", i)), true))
+	}
+	// A crash left the values before the fifth key encrypted
+	for i := 0; i < 5; i++ {
+		encrypted, err := encrypter.Encrypt([]byte(createTestValue("db", i)))
+		require.NoError(t, err)
+		require.NoError(t, p.db.Put(constructLevelKey("db", []byte(createTestKey(i))), encrypted, true))
+	}
+	require.NoError(t, p.db.Put(encryptionMarkerKey, append([]byte{1}, encrypter.CurrentKeys()...), true))
+	require.NoError(t, p.db.Put(encryptionProgressKey, append([]byte{1}, constructLevelKey("db", []byte(createTestKey(5)))...), true))
+	p.Close()
+
+	p = NewProvider(&Conf{DBPath: testDBPath, Encrypter: encrypter})
+	defer p.Close()
+	checkEncryptedWith(t, p, encrypter)
+	itr := p.GetDBHandle("db").GetIterator(nil, nil)
+	checkItrResults(t, itr, createTestKeys(0, 9), createTestValues("db", 0, 9))
+	itr.Release()
+}
+
+func TestReencryption(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+	defer func(batchSize int) { reencryptionBatchSize = batchSize }(reencryptionBatchSize)
+	reencryptionBatchSize = 3
+	csp := newTestCSP(t)
+
+	p := NewProvider(&Conf{DBPath: testDBPath, Encrypter: newTestEncrypter(t, csp)})
+	for i := 0; i < 10; i++ {
+		require.NoError(t, p.GetDBHandle("db1").Put([]byte(createTestKey(i)), []byte(createTestValue("db1", i)), false))
+		require.NoError(t, p.GetDBHandle("db2").Put([]byte(createTestKey(i)), []byte(createTestValue("db2", i)), false))
+	}
+	p.Close()
+
+	// Once the key is rotated, the values encrypted with the previous key are re-encrypted
+	rotatedEncrypter := newTestEncrypter(t, csp)
+	p = NewProvider(&Conf{DBPath: testDBPath, Encrypter: rotatedEncrypter})
+	<-p.values.reencrypted
+
+	checkEncryptedWith(t, p, rotatedEncrypter)
+	for _, dbName := range []string{"db1", "db2"} {
+		itr := p.GetDBHandle(dbName).GetIterator(nil, nil)
+		checkItrResults(t, itr, createTestKeys(0, 9), createTestValues(dbName, 0, 9))
+		itr.Release()
+	}
+
+	// Only the current key is listed once the values are re-encrypted, so the previous key
+	// is no longer needed
+	marker, err := p.db.Get(encryptionMarkerKey)
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte{1}, rotatedEncrypter.CurrentKeys()...), marker)
+	p.Close()
+	p = NewProvider(&Conf{DBPath: testDBPath, Encrypter: rotatedEncrypter})
+	<-p.values.reencrypted
+	p.Close()
+}
+
+func TestReencryptionOfUnlistedKeys(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+	csp := newTestCSP(t)
+
+	p := NewProvider(&Conf{DBPath: testDBPath, Encrypter: newTestEncrypter(t, csp)})
+	require.NoError(t, p.GetDBHandle("db").Put([]byte("key"), []byte("value"), true))
+	// The leveldbs encrypted before the keys were listed are only marked as encrypted
+	require.NoError(t, p.db.Put(encryptionMarkerKey, []byte{1}, true))
+	p.Close()
+
+	rotatedEncrypter := newTestEncrypter(t, csp)
+	p = NewProvider(&Conf{DBPath: testDBPath, Encrypter: rotatedEncrypter})
+	defer p.Close()
+	<-p.values.reencrypted
+	checkEncryptedWith(t, p, rotatedEncrypter)
+	marker, err := p.db.Get(encryptionMarkerKey)
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte{1}, rotatedEncrypter.CurrentKeys()...), marker)
+}
+
+func TestRetiredKey(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+
+	p := NewProvider(&Conf{DBPath: testDBPath, Encrypter: newTestEncrypter(t, newTestCSP(t))})
+	require.NoError(t, p.GetDBHandle("db").Put([]byte("key"), []byte("value"), true))
+	p.Close()
+
+	// The values are still encrypted with a key which is no longer in the keystore
+	assert.Panics(t, func() {
+		NewProvider(&Conf{DBPath: testDBPath, Encrypter: newTestEncrypter(t, newTestCSP(t))})
+	})
+}
+
+func checkEncryptedWith(t *testing.T, p *Provider, encrypter *encryption.Encrypter) {
+	itr := p.db.GetIterator(nil, nil)
+	defer itr.Release()
+	for itr.Next() {
+		if string(itr.Key()) != string(encryptionMarkerKey) {
+			assert.True(t, encrypter.IsCurrent(itr.Value()), "value of key [%#v] is not encrypted with the current key", itr.Key())
+		}
+	}
+}
+
+func TestIteratorDecryptionError(t *testing.T) {
+	require.NoError(t, os.RemoveAll(testDBPath))
+	defer os.RemoveAll(testDBPath)
+	p := NewProvider(&Conf{DBPath: testDBPath, Encrypter: newTestEncrypter(t, newTestCSP(t))})
+	defer p.Close()
+	db := p.GetDBHandle("db")
+	for i := 0; i < 3; i++ {
+		require.NoError(t, db.Put([]byte(createTestKey(i)), []byte(createTestValue("db", i)), true))
+	}
+
+	// Tamper with the encrypted value of the second key
+	levelKey := constructLevelKey("db", []byte(createTestKey(1)))
+	rawValue, err := p.db.Get(levelKey)
+	require.NoError(t, err)
+	rawValue[len(rawValue)-1] ^= 1
+	require.NoError(t, p.db.Put(levelKey, rawValue, true))
+
+	itr := db.GetIterator(nil, nil)
+	defer itr.Release()
+	assert.True(t, itr.Next())
+	assert.Equal(t, []byte(createTestValue("db", 0)), itr.Value())
+	assert.NoError(t, itr.Error())
+
+	assert.True(t, itr.Next())
+	assert.Nil(t, itr.Value())
+	assert.Contains(t, itr.Error().Error(), fmt.Sprintf("error decrypting value of key [%#v]", levelKey))
+	assert.False(t, itr.Next())
+}
@@ -0,0 +1,62 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leveldbhelper
+
+import (
+	"syscall"
+
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// FileLock encapsulates the db that holds the file lock.
+// A FileLock is expected to be used by a single goroutine and
+// hence it is not synchronized
+type FileLock struct {
+	db       *leveldb.DB
+	filePath string
+}
+
+// NewFileLock returns a new file based lock
+func NewFileLock(filePath string) *FileLock {
+	return &FileLock{filePath: filePath}
+}
+
+// Lock acquires the file lock by opening a db at the file path.
+// leveldb holds an exclusive lock on the db directory while the db is open,
+// so opening the db again, either from the same or from another process,
+// fails until the db is closed or the owner process exits
+func (f *FileLock) Lock() error {
+	dirEmpty, err := util.CreateDirIfMissing(f.filePath)
+	if err != nil {
+		return errors.WithMessage(err, "error creating lock directory")
+	}
+	dbOpts := &opt.Options{ErrorIfMissing: !dirEmpty}
+	f.db, err = leveldb.OpenFile(f.filePath, dbOpts)
+	if err == syscall.EAGAIN {
+		return errors.Errorf("lock is already acquired on file %s", f.filePath)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "error acquiring lock on file %s", f.filePath)
+	}
+	return nil
+}
+
+// Unlock releases a previously acquired lock by closing the db.
+// Unlock may be called multiple times
+func (f *FileLock) Unlock() {
+	if f.db == nil {
+		return
+	}
+	if err := f.db.Close(); err != nil {
+		logger.Warningf("unable to release the lock on file %s: %s", f.filePath, err)
+		return
+	}
+	f.db = nil
+}
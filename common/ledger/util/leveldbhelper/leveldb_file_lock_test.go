@@ -0,0 +1,36 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leveldbhelper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileLock(t *testing.T) {
+	lockPath := filepath.Join(testDBPath, "fileLock")
+	defer os.RemoveAll(testDBPath)
+
+	fileLock := NewFileLock(lockPath)
+	assert.NoError(t, fileLock.Lock())
+
+	// a second lock on the same path fails while the first one is held
+	anotherLock := NewFileLock(lockPath)
+	err := anotherLock.Lock()
+	assert.EqualError(t, err, fmt.Sprintf("lock is already acquired on file %s", lockPath))
+
+	fileLock.Unlock()
+	// unlocking again is a no-op
+	fileLock.Unlock()
+
+	assert.NoError(t, anotherLock.Lock())
+	anotherLock.Unlock()
+}
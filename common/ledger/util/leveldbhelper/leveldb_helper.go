@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/pkg/errors"
@@ -31,6 +32,8 @@ const (
 // Conf configuration for `DB`
 type Conf struct {
 	DBPath string
+	// Encrypter, if set, encrypts the values stored by a `Provider`
+	Encrypter *encryption.Encrypter
 }
 
 // DB - a wrapper on an actual store
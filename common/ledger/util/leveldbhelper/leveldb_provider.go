@@ -18,8 +18,10 @@ package leveldbhelper
 
 import (
 	"bytes"
+	"fmt"
 	"sync"
 
+	"github.com/pkg/errors"
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
 )
@@ -32,13 +34,15 @@ type Provider struct {
 	db        *DB
 	dbHandles map[string]*DBHandle
 	mux       sync.Mutex
+	values    *valueEncryption
 }
 
 // NewProvider constructs a Provider
 func NewProvider(conf *Conf) *Provider {
 	db := CreateDB(conf)
 	db.Open()
-	return &Provider{db, make(map[string]*DBHandle), sync.Mutex{}}
+	values := newValueEncryption(db, conf.Encrypter)
+	return &Provider{db, make(map[string]*DBHandle), sync.Mutex{}, values}
 }
 
 // GetDBHandle returns a handle to a named db
@@ -47,7 +51,7 @@ func (p *Provider) GetDBHandle(dbName string) *DBHandle {
 	defer p.mux.Unlock()
 	dbHandle := p.dbHandles[dbName]
 	if dbHandle == nil {
-		dbHandle = &DBHandle{dbName, p.db}
+		dbHandle = &DBHandle{dbName, p.db, p.values}
 		p.dbHandles[dbName] = dbHandle
 	}
 	return dbHandle
@@ -55,6 +59,7 @@ func (p *Provider) GetDBHandle(dbName string) *DBHandle {
 
 // Close closes the underlying leveldb
 func (p *Provider) Close() {
+	p.values.close()
 	p.db.Close()
 }
 
@@ -62,20 +67,33 @@ func (p *Provider) Close() {
 type DBHandle struct {
 	dbName string
 	db     *DB
+	values *valueEncryption
 }
 
 // Get returns the value for the given key
 func (h *DBHandle) Get(key []byte) ([]byte, error) {
-	return h.db.Get(constructLevelKey(h.dbName, key))
+	value, err := h.db.Get(constructLevelKey(h.dbName, key))
+	if err != nil {
+		return nil, err
+	}
+	return h.values.decrypt(value)
 }
 
 // Put saves the key/value
 func (h *DBHandle) Put(key []byte, value []byte, sync bool) error {
+	value, err := h.values.encrypt(value)
+	if err != nil {
+		return err
+	}
+	h.values.beginWrite()
+	defer h.values.endWrite()
 	return h.db.Put(constructLevelKey(h.dbName, key), value, sync)
 }
 
 // Delete deletes the given key
 func (h *DBHandle) Delete(key []byte, sync bool) error {
+	h.values.beginWrite()
+	defer h.values.endWrite()
 	return h.db.Delete(constructLevelKey(h.dbName, key), sync)
 }
 
@@ -89,16 +107,39 @@ func (h *DBHandle) WriteBatch(batch *UpdateBatch, sync bool) error {
 		key := constructLevelKey(h.dbName, []byte(k))
 		if v == nil {
 			levelBatch.Delete(key)
-		} else {
-			levelBatch.Put(key, v)
+			continue
 		}
+		v, err := h.values.encrypt(v)
+		if err != nil {
+			return err
+		}
+		levelBatch.Put(key, v)
 	}
+	h.values.beginWrite()
+	defer h.values.endWrite()
 	if err := h.db.WriteBatch(levelBatch, sync); err != nil {
 		return err
 	}
 	return nil
 }
 
+// DeleteAll deletes all the keys of the named db
+func (h *DBHandle) DeleteAll() error {
+	itr := h.GetIterator(nil, nil)
+	defer itr.Release()
+
+	batch := &leveldb.Batch{}
+	for itr.Next() {
+		batch.Delete(itr.Iterator.Key())
+	}
+	if err := itr.Error(); err != nil {
+		return err
+	}
+	h.values.beginWrite()
+	defer h.values.endWrite()
+	return h.db.WriteBatch(batch, true)
+}
+
 // GetIterator gets an handle to iterator. The iterator should be released after the use.
 // The resultset contains all the keys that are present in the db between the startKey (inclusive) and the endKey (exclusive).
 // A nil startKey represents the first available key and a nil endKey represent a logical key after the last available key
@@ -110,7 +151,7 @@ func (h *DBHandle) GetIterator(startKey []byte, endKey []byte) *Iterator {
 		eKey[len(eKey)-1] = lastKeyIndicator
 	}
 	logger.Debugf("Getting iterator for range [%#v] - [%#v]", sKey, eKey)
-	return &Iterator{h.db.GetIterator(sKey, eKey)}
+	return &Iterator{Iterator: h.db.GetIterator(sKey, eKey), values: h.values}
 }
 
 // UpdateBatch encloses the details of multiple `updates`
@@ -144,6 +185,14 @@ func (batch *UpdateBatch) Len() int {
 // Iterator extends actual leveldb iterator
 type Iterator struct {
 	iterator.Iterator
+	values *valueEncryption
+	err    error
+}
+
+// Next wraps actual leveldb iterator method. The iterator is exhausted once a value
+// fails to be decrypted
+func (itr *Iterator) Next() bool {
+	return itr.err == nil && itr.Iterator.Next()
 }
 
 // Key wraps actual leveldb iterator method
@@ -151,6 +200,26 @@ func (itr *Iterator) Key() []byte {
 	return retrieveAppKey(itr.Iterator.Key())
 }
 
+// Value wraps actual leveldb iterator method, decrypting the value if the values of the db
+// are encrypted. If the decryption fails, it returns nil and the error is returned by Error
+func (itr *Iterator) Value() []byte {
+	value, err := itr.values.decrypt(itr.Iterator.Value())
+	if err != nil {
+		itr.err = errors.WithMessage(err, fmt.Sprintf("error decrypting value of key [%#v]", itr.Iterator.Key()))
+		return nil
+	}
+	return value
+}
+
+// Error wraps actual leveldb iterator method, returning the error of the decryption of a
+// value, if any
+func (itr *Iterator) Error() error {
+	if itr.err != nil {
+		return itr.err
+	}
+	return itr.Iterator.Error()
+}
+
 func constructLevelKey(dbName string, key []byte) []byte {
 	return append(append([]byte(dbName), dbNameKeySep...), key...)
 }
@@ -40,14 +40,14 @@ type testDBProviderEnv struct {
 func newTestDBEnv(t *testing.T, path string) *testDBEnv {
 	testDBEnv := &testDBEnv{t: t, path: path}
 	testDBEnv.cleanup()
-	testDBEnv.db = CreateDB(&Conf{path})
+	testDBEnv.db = CreateDB(&Conf{DBPath: path})
 	return testDBEnv
 }
 
 func newTestProviderEnv(t *testing.T, path string) *testDBProviderEnv {
 	testProviderEnv := &testDBProviderEnv{t: t, path: path}
 	testProviderEnv.cleanup()
-	testProviderEnv.provider = NewProvider(&Conf{path})
+	testProviderEnv.provider = NewProvider(&Conf{DBPath: path})
 	return testProviderEnv
 }
 
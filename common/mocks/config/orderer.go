@@ -32,6 +32,8 @@ type Orderer struct {
 	KafkaBrokersVal []string
 	// MaxChannelsCountVal is returns as the result of MaxChannelsCount()
 	MaxChannelsCountVal uint64
+	// BlockCuttingVal is returned as the result of BlockCutting()
+	BlockCuttingVal *ab.BlockCutting
 	// OrganizationsVal is returned as the result of Organizations()
 	OrganizationsVal map[string]channelconfig.Org
 	// CapabilitiesVal is returned as the result of Capabilities()
@@ -78,6 +80,11 @@ func (o *Orderer) MaxChannelsCount() uint64 {
 	return o.MaxChannelsCountVal
 }
 
+// BlockCutting returns the BlockCuttingVal
+func (o *Orderer) BlockCutting() *ab.BlockCutting {
+	return o.BlockCuttingVal
+}
+
 // Organizations returns OrganizationsVal
 func (o *Orderer) Organizations() map[string]channelconfig.Org {
 	return o.OrganizationsVal
@@ -103,6 +110,9 @@ type OrdererCapabilities struct {
 	ExpirationVal bool
 
 	Kafka2RaftMigVal bool
+
+	// FairBlockCuttingVal is returned by FairBlockCutting()
+	FairBlockCuttingVal bool
 }
 
 // Supported returns SupportedErr
@@ -130,3 +140,8 @@ func (oc *OrdererCapabilities) ExpirationCheck() bool {
 func (oc *OrdererCapabilities) Kafka2RaftMigration() bool {
 	return oc.Kafka2RaftMigVal
 }
+
+// FairBlockCutting returns FairBlockCuttingVal
+func (oc *OrdererCapabilities) FairBlockCutting() bool {
+	return oc.FairBlockCuttingVal
+}
@@ -19,6 +19,8 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/msp"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -203,6 +205,18 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 	addValue(ordererGroup, channelconfig.BatchTimeoutValue(conf.BatchTimeout.String()), channelconfig.AdminsPolicyKey)
 	addValue(ordererGroup, channelconfig.ChannelRestrictionsValue(conf.MaxChannels), channelconfig.AdminsPolicyKey)
 
+	if conf.BlockCutting != nil {
+		mode, ok := ab.BlockCutting_Mode_value[conf.BlockCutting.Mode]
+		if !ok {
+			return nil, errors.Errorf("unknown block cutting mode: %s", conf.BlockCutting.Mode)
+		}
+		addValue(ordererGroup, channelconfig.BlockCuttingValue(
+			ab.BlockCutting_Mode(mode),
+			conf.BlockCutting.OrgWeights,
+			conf.BlockCutting.PendingBatches,
+		), channelconfig.AdminsPolicyKey)
+	}
+
 	if len(conf.Capabilities) > 0 {
 		addValue(ordererGroup, channelconfig.CapabilitiesValue(conf.Capabilities), channelconfig.AdminsPolicyKey)
 	}
@@ -218,6 +232,18 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 		if consensusMetadata, err = etcdraft.Marshal(conf.EtcdRaft); err != nil {
 			return nil, errors.Errorf("cannot marshal metadata for orderer type %s: %s", etcdraft.TypeKey, err)
 		}
+	case bft.TypeKey:
+		if consensusMetadata, err = bft.Marshal(conf.BFT); err != nil {
+			return nil, errors.Errorf("cannot marshal metadata for orderer type %s: %s", bft.TypeKey, err)
+		}
+		blockValidationPolicy, err := bft.BlockValidationPolicy(conf.BFT.Consenters)
+		if err != nil {
+			return nil, errors.Errorf("cannot build block validation policy for orderer type %s: %s", bft.TypeKey, err)
+		}
+		ordererGroup.Policies[BlockValidationPolicyKey] = &cb.ConfigPolicy{
+			Policy:    policies.SignaturePolicy(BlockValidationPolicyKey, blockValidationPolicy).Value(),
+			ModPolicy: channelconfig.AdminsPolicyKey,
+		}
 	default:
 		return nil, errors.Errorf("unknown orderer type: %s", conf.OrdererType)
 	}
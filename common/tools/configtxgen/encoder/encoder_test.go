@@ -7,18 +7,25 @@ SPDX-License-Identifier: Apache-2.0
 package encoder
 
 import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/localmsp"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
 	cb "github.com/hyperledger/fabric/protos/common"
+	mb "github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
@@ -255,6 +262,32 @@ func TestNewOrdererGroup(t *testing.T) {
 		assert.Nil(t, group)
 	})
 
+	t.Run("Block cutting", func(t *testing.T) {
+		config := configtxgentest.Load(genesisconfig.SampleDevModeSoloProfile)
+		group, err := NewOrdererGroup(config.Orderer)
+		require.NoError(t, err)
+		assert.NotContains(t, group.GetValues(), channelconfig.BlockCuttingKey)
+
+		config.Orderer.BlockCutting = &genesisconfig.BlockCutting{
+			Mode:           "FAIR",
+			OrgWeights:     map[string]uint32{"SampleOrg": 2},
+			PendingBatches: 3,
+		}
+		group, err = NewOrdererGroup(config.Orderer)
+		require.NoError(t, err)
+		blockCutting := &ab.BlockCutting{}
+		require.NoError(t, proto.Unmarshal(group.GetValues()[channelconfig.BlockCuttingKey].GetValue(), blockCutting))
+		assert.True(t, proto.Equal(&ab.BlockCutting{
+			Mode:           ab.BlockCutting_FAIR,
+			OrgWeights:     map[string]uint32{"SampleOrg": 2},
+			PendingBatches: 3,
+		}, blockCutting))
+
+		config.Orderer.BlockCutting.Mode = "LIFO"
+		_, err = NewOrdererGroup(config.Orderer)
+		assert.EqualError(t, err, "unknown block cutting mode: LIFO")
+	})
+
 	t.Run("etcd/raft-based Orderer", func(t *testing.T) {
 		config := configtxgentest.Load(genesisconfig.SampleDevModeEtcdRaftProfile)
 		group, _ := NewOrdererGroup(config.Orderer)
@@ -271,6 +304,62 @@ func TestNewOrdererGroup(t *testing.T) {
 			require.NotNil(t, v.GetClientTlsCert(), "cannot extract PEM-encoded client certificate of consenter")
 		}
 	})
+
+	t.Run("BFT Orderer", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "encoder")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+
+		ca, err := tlsgen.NewCA()
+		require.NoError(t, err)
+		var identities [][]byte
+		config := configtxgentest.Load(genesisconfig.SampleDevModeSoloProfile)
+		config.Orderer.OrdererType = bft.TypeKey
+		config.Orderer.BFT = &bft.Metadata{Options: &bft.Options{RequestTimeout: 100, ViewChangeTimeout: 200}}
+		for i := 1; i <= 4; i++ {
+			kp, err := ca.NewClientCertKeyPair()
+			require.NoError(t, err)
+			certPath := filepath.Join(dir, fmt.Sprintf("cert%d.pem", i))
+			require.NoError(t, ioutil.WriteFile(certPath, kp.Cert, 0600))
+			identities = append(identities, kp.Cert)
+			config.Orderer.BFT.Consenters = append(config.Orderer.BFT.Consenters, &bft.Consenter{
+				ConsenterId:   uint64(i),
+				Host:          fmt.Sprintf("bft%d.example.com", i),
+				Port:          7050,
+				MspId:         "SampleOrg",
+				Identity:      []byte(certPath),
+				ClientTlsCert: []byte(certPath),
+				ServerTlsCert: []byte(certPath),
+			})
+		}
+
+		group, err := NewOrdererGroup(config.Orderer)
+		require.NoError(t, err)
+
+		unpackedType := new(ab.ConsensusType)
+		require.NoError(t, proto.Unmarshal(group.Values[channelconfig.ConsensusTypeKey].Value, unpackedType))
+		assert.Equal(t, bft.TypeKey, unpackedType.Type)
+		unpackedMetadata := new(bft.Metadata)
+		require.NoError(t, proto.Unmarshal(unpackedType.Metadata, unpackedMetadata))
+		require.Len(t, unpackedMetadata.Consenters, 4)
+		assert.Equal(t, identities[0], unpackedMetadata.Consenters[0].Identity)
+
+		// The blocks must be signed by a quorum of 3 consenters out of 4
+		policy := group.Policies[BlockValidationPolicyKey].Policy
+		assert.Equal(t, int32(cb.Policy_SIGNATURE), policy.Type)
+		envelope := &cb.SignaturePolicyEnvelope{}
+		require.NoError(t, proto.Unmarshal(policy.Value, envelope))
+		assert.Equal(t, int32(3), envelope.Rule.GetNOutOf().N)
+		assert.Len(t, envelope.Rule.GetNOutOf().Rules, 4)
+		require.Len(t, envelope.Identities, 4)
+		for i, principal := range envelope.Identities {
+			assert.Equal(t, mb.MSPPrincipal_IDENTITY, principal.PrincipalClassification)
+			sID := &mb.SerializedIdentity{}
+			require.NoError(t, proto.Unmarshal(principal.Principal, sID))
+			assert.Equal(t, "SampleOrg", sID.Mspid)
+			assert.Equal(t, identities[i], sID.IdBytes)
+		}
+	})
 }
 
 func TestBootstrapper(t *testing.T) {
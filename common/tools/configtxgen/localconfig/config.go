@@ -17,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric/common/viperutil"
 	cf "github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
 	"github.com/spf13/viper"
 )
@@ -161,12 +162,21 @@ type Orderer struct {
 	BatchSize     BatchSize          `yaml:"BatchSize"`
 	Kafka         Kafka              `yaml:"Kafka"`
 	EtcdRaft      *etcdraft.Metadata `yaml:"EtcdRaft"`
+	BFT           *bft.Metadata      `yaml:"BFT"`
 	Organizations []*Organization    `yaml:"Organizations"`
 	MaxChannels   uint64             `yaml:"MaxChannels"`
+	BlockCutting  *BlockCutting      `yaml:"BlockCutting"`
 	Capabilities  map[string]bool    `yaml:"Capabilities"`
 	Policies      map[string]*Policy `yaml:"Policies"`
 }
 
+// BlockCutting contains configuration affecting how transactions are cut into blocks.
+type BlockCutting struct {
+	Mode           string            `yaml:"Mode"`
+	OrgWeights     map[string]uint32 `yaml:"OrgWeights"`
+	PendingBatches uint32            `yaml:"PendingBatches"`
+}
+
 // BatchSize contains configuration affecting the size of batches.
 type BatchSize struct {
 	MaxMessageCount   uint32 `yaml:"MaxMessageCount"`
@@ -201,6 +211,12 @@ var genesisDefaults = TopLevel{
 				MaxSizePerMsg:   1048576,
 			},
 		},
+		BFT: &bft.Metadata{
+			Options: &bft.Options{
+				RequestTimeout:    10000,
+				ViewChangeTimeout: 20000,
+			},
+		},
 	},
 }
 
@@ -456,6 +472,65 @@ loop:
 			cf.TranslatePathInPlace(configDir, &serverCertPath)
 			c.ServerTlsCert = []byte(serverCertPath)
 		}
+	case bft.TypeKey:
+		if ord.BFT == nil {
+			logger.Panicf("%s configuration missing", bft.TypeKey)
+		}
+		if ord.BFT.Options == nil {
+			logger.Infof("Orderer.BFT.Options unset, setting to %v", genesisDefaults.Orderer.BFT.Options)
+			ord.BFT.Options = genesisDefaults.Orderer.BFT.Options
+		}
+	bft_loop:
+		for {
+			switch {
+			case ord.BFT.Options.RequestTimeout == 0:
+				logger.Infof("Orderer.BFT.Options.RequestTimeout unset, setting to %v", genesisDefaults.Orderer.BFT.Options.RequestTimeout)
+				ord.BFT.Options.RequestTimeout = genesisDefaults.Orderer.BFT.Options.RequestTimeout
+
+			case ord.BFT.Options.ViewChangeTimeout == 0:
+				logger.Infof("Orderer.BFT.Options.ViewChangeTimeout unset, setting to %v", genesisDefaults.Orderer.BFT.Options.ViewChangeTimeout)
+				ord.BFT.Options.ViewChangeTimeout = genesisDefaults.Orderer.BFT.Options.ViewChangeTimeout
+
+			case len(ord.BFT.Consenters) == 0:
+				logger.Panicf("%s configuration did not specify any consenter", bft.TypeKey)
+
+			default:
+				break bft_loop
+			}
+		}
+
+		for _, c := range ord.BFT.GetConsenters() {
+			if c.ConsenterId == 0 {
+				logger.Panicf("consenter info in %s configuration did not specify consenter ID", bft.TypeKey)
+			}
+			if c.Host == "" {
+				logger.Panicf("consenter info in %s configuration did not specify host", bft.TypeKey)
+			}
+			if c.Port == 0 {
+				logger.Panicf("consenter info in %s configuration did not specify port", bft.TypeKey)
+			}
+			if c.MspId == "" {
+				logger.Panicf("consenter info in %s configuration did not specify MSP ID", bft.TypeKey)
+			}
+			if c.Identity == nil {
+				logger.Panicf("consenter info in %s configuration did not specify identity", bft.TypeKey)
+			}
+			if c.ClientTlsCert == nil {
+				logger.Panicf("consenter info in %s configuration did not specify client TLS cert", bft.TypeKey)
+			}
+			if c.ServerTlsCert == nil {
+				logger.Panicf("consenter info in %s configuration did not specify server TLS cert", bft.TypeKey)
+			}
+			identityPath := string(c.GetIdentity())
+			cf.TranslatePathInPlace(configDir, &identityPath)
+			c.Identity = []byte(identityPath)
+			clientCertPath := string(c.GetClientTlsCert())
+			cf.TranslatePathInPlace(configDir, &clientCertPath)
+			c.ClientTlsCert = []byte(clientCertPath)
+			serverCertPath := string(c.GetServerTlsCert())
+			cf.TranslatePathInPlace(configDir, &serverCertPath)
+			c.ServerTlsCert = []byte(serverCertPath)
+		}
 	default:
 		logger.Panicf("unknown orderer type: %s", ord.OrdererType)
 	}
@@ -66,6 +66,17 @@ func (d *defaultACLProvider) initialize() {
 	d.cResourcePolicyMap[resources.Lscc_GetInstantiatedChaincodes] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Lscc_GetCollectionsConfig] = CHANNELREADERS
 
+	//-------------- new lifecycle --------------
+	//p resources (implemented by the chaincode currently)
+	d.pResourcePolicyMap[resources.Lifecycle_InstallChaincode] = ""
+	d.pResourcePolicyMap[resources.Lifecycle_QueryInstalledChaincode] = ""
+
+	//c resources
+	d.cResourcePolicyMap[resources.Lifecycle_ApproveChaincodeDefinitionForMyOrg] = CHANNELWRITERS
+	d.cResourcePolicyMap[resources.Lifecycle_CommitChaincodeDefinition] = CHANNELWRITERS
+	d.cResourcePolicyMap[resources.Lifecycle_QueryApprovalStatus] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Lifecycle_QueryChaincodeDefinition] = CHANNELREADERS
+
 	//-------------- QSCC --------------
 	//p resources (none)
 
@@ -22,6 +22,14 @@ const (
 	Lscc_GetInstalledChaincodes    = "lscc/GetInstalledChaincodes"
 	Lscc_GetCollectionsConfig      = "lscc/GetCollectionsConfig"
 
+	//New lifecycle resources
+	Lifecycle_InstallChaincode                   = "+lifecycle/InstallChaincode"
+	Lifecycle_QueryInstalledChaincode            = "+lifecycle/QueryInstalledChaincode"
+	Lifecycle_ApproveChaincodeDefinitionForMyOrg = "+lifecycle/ApproveChaincodeDefinitionForMyOrg"
+	Lifecycle_CommitChaincodeDefinition          = "+lifecycle/CommitChaincodeDefinition"
+	Lifecycle_QueryApprovalStatus                = "+lifecycle/QueryApprovalStatus"
+	Lifecycle_QueryChaincodeDefinition           = "+lifecycle/QueryChaincodeDefinition"
+
 	//Qscc resources
 	Qscc_GetChainInfo       = "qscc/GetChainInfo"
 	Qscc_GetBlockByNumber   = "qscc/GetBlockByNumber"
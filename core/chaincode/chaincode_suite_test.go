@@ -63,8 +63,18 @@ type packageProvider interface {
 	chaincode.PackageProvider
 }
 
+//go:generate counterfeiter -o mock/external_builder.go --fake-name ExternalBuilder . externalBuilder
+type externalBuilder interface {
+	chaincode.ExternalBuilder
+}
+
 // This is a bit weird, we need to import the chaincode/lifecycle package, but there is an error,
 // even if we alias it to another name, so, calling 'lifecycleIface' instead of 'lifecycle'
+//go:generate counterfeiter -o mock/connection_handler.go --fake-name ConnectionHandler . connectionHandler
+type connectionHandler interface {
+	chaincode.ConnectionHandler
+}
+
 //go:generate counterfeiter -o mock/lifecycle.go --fake-name Lifecycle . lifecycleIface
 type lifecycleIface interface {
 	chaincode.Lifecycle
@@ -13,6 +13,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/extcc"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
@@ -71,6 +72,7 @@ func NewChaincodeSupport(
 	lifecycle Lifecycle,
 	aclProvider ACLProvider,
 	processor Processor,
+	externalBuilder ExternalBuilder,
 	SystemCCProvider sysccprovider.SystemChaincodeProvider,
 	platformRegistry *platforms.Registry,
 	appConfig ApplicationConfigRetriever,
@@ -108,11 +110,14 @@ func NewChaincodeSupport(
 	}
 
 	cs.Launcher = &RuntimeLauncher{
-		Runtime:         cs.Runtime,
-		Registry:        cs.HandlerRegistry,
-		PackageProvider: packageProvider,
-		StartupTimeout:  config.StartupTimeout,
-		Metrics:         cs.LaunchMetrics,
+		Runtime:           cs.Runtime,
+		Registry:          cs.HandlerRegistry,
+		PackageProvider:   packageProvider,
+		ExternalBuilder:   externalBuilder,
+		ConnectionHandler: &extcc.ExternalChaincodeRuntime{},
+		StreamHandler:     cs,
+		StartupTimeout:    config.StartupTimeout,
+		Metrics:           cs.LaunchMetrics,
 	}
 
 	return cs
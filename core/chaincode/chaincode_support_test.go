@@ -190,6 +190,7 @@ func initMockPeer(chainIDs ...string) (*ChaincodeSupport, error) {
 				inproccontroller.ContainerType: ipRegistry,
 			},
 		),
+		nil,
 		sccp,
 		pr,
 		peer.DefaultSupport,
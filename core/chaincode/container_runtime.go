@@ -17,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/container"
 	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 )
@@ -47,7 +48,13 @@ type ContainerRuntime struct {
 func (c *ContainerRuntime) Start(ccci *ccprovider.ChaincodeContainerInfo, codePackage []byte) error {
 	cname := ccci.Name + ":" + ccci.Version
 
-	lc, err := c.LaunchConfig(cname, ccci.Type)
+	var lc *LaunchConfig
+	var err error
+	if ccci.ContainerType == externalbuilder.ContainerType {
+		lc, err = c.ExternalLaunchConfig(cname)
+	} else {
+		lc, err = c.LaunchConfig(cname, ccci.Type)
+	}
 	if err != nil {
 		return err
 	}
@@ -171,15 +178,41 @@ func (c *ContainerRuntime) LaunchConfig(cname string, ccType string) (*LaunchCon
 		return nil, errors.Errorf("unknown chaincodeType: %s", ccType)
 	}
 
-	// Pass TLS options to chaincode
+	if err := c.configureTLS(cname, &lc); err != nil {
+		return nil, err
+	}
+
+	chaincodeLogger.Debugf("launchConfig: %s", lc.String())
+
+	return &lc, nil
+}
+
+// ExternalLaunchConfig creates the LaunchConfig for chaincode run by an external
+// builder. The run executable of the builder decides how the chaincode is run, so
+// the address of the peer is passed in the environment rather than as an argument.
+func (c *ContainerRuntime) ExternalLaunchConfig(cname string) (*LaunchConfig, error) {
+	var lc LaunchConfig
+	lc.Envs = append(c.CommonEnv, "CORE_CHAINCODE_ID_NAME="+cname, "CORE_PEER_ADDRESS="+c.PeerAddress)
+
+	if err := c.configureTLS(cname, &lc); err != nil {
+		return nil, err
+	}
+
+	chaincodeLogger.Debugf("launchConfig: %s", lc.String())
+
+	return &lc, nil
+}
+
+// configureTLS adds the TLS options of the chaincode to the LaunchConfig.
+func (c *ContainerRuntime) configureTLS(cname string, lc *LaunchConfig) error {
 	if c.CertGenerator != nil {
 		certKeyPair, err := c.CertGenerator.Generate(cname)
 		if err != nil {
-			return nil, errors.WithMessage(err, fmt.Sprintf("failed to generate TLS certificates for %s", cname))
+			return errors.WithMessage(err, fmt.Sprintf("failed to generate TLS certificates for %s", cname))
 		}
 		lc.Files = c.getTLSFiles(certKeyPair)
 		if lc.Files == nil {
-			return nil, errors.Errorf("failed to acquire TLS certificates for %s", cname)
+			return errors.Errorf("failed to acquire TLS certificates for %s", cname)
 		}
 
 		lc.Envs = append(lc.Envs, "CORE_PEER_TLS_ENABLED=true")
@@ -190,9 +223,7 @@ func (c *ContainerRuntime) LaunchConfig(cname string, ccType string) (*LaunchCon
 		lc.Envs = append(lc.Envs, "CORE_PEER_TLS_ENABLED=false")
 	}
 
-	chaincodeLogger.Debugf("launchConfig: %s", lc.String())
-
-	return &lc, nil
+	return nil
 }
 
 func (lc *LaunchConfig) String() string {
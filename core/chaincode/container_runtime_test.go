@@ -118,6 +118,30 @@ func TestContainerRuntimeLaunchConfigEnv(t *testing.T) {
 	}
 }
 
+func TestContainerRuntimeExternalLaunchConfig(t *testing.T) {
+	certGenerator := &mock.CertGenerator{}
+	certGenerator.GenerateReturns(&accesscontrol.CertAndPrivKeyPair{Cert: "certificate", Key: "key"}, nil)
+	cr := &chaincode.ContainerRuntime{
+		CommonEnv:     []string{"COMMON_1=VALUE1"},
+		PeerAddress:   "peer-address",
+		CertGenerator: certGenerator,
+	}
+
+	lc, err := cr.ExternalLaunchConfig("chaincode-name")
+	assert.NoError(t, err)
+	assert.Empty(t, lc.Args)
+	assert.Equal(t, []string{
+		"COMMON_1=VALUE1",
+		"CORE_CHAINCODE_ID_NAME=chaincode-name",
+		"CORE_PEER_ADDRESS=peer-address",
+		"CORE_PEER_TLS_ENABLED=true",
+		"CORE_TLS_CLIENT_KEY_PATH=/etc/hyperledger/fabric/client.key",
+		"CORE_TLS_CLIENT_CERT_PATH=/etc/hyperledger/fabric/client.crt",
+		"CORE_PEER_TLS_ROOTCERT_FILE=/etc/hyperledger/fabric/peer.crt",
+	}, lc.Envs)
+	assert.Len(t, lc.Files, 3)
+}
+
 func TestContainerRuntimeLaunchConfigFiles(t *testing.T) {
 	keyPair := &accesscontrol.CertAndPrivKeyPair{Cert: "certificate", Key: "key"}
 	certGenerator := &mock.CertGenerator{}
@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extcc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+)
+
+var extccLogger = flogging.MustGetLogger("extcc")
+
+// StreamHandler handles the stream of a chaincode once it is established.
+type StreamHandler interface {
+	HandleChaincodeStream(stream ccintf.ChaincodeStream) error
+}
+
+// ExternalChaincodeRuntime connects to the chaincodes running as external
+// services, rather than waiting for the chaincodes to connect to the peer.
+type ExternalChaincodeRuntime struct{}
+
+// Stream connects to the chaincode server and hands the stream to the given
+// handler, blocking until the stream ends.
+func (i *ExternalChaincodeRuntime) Stream(ccid string, ccinfo *ccintf.ChaincodeServerInfo, sHandler StreamHandler) error {
+	extccLogger.Debugf("Starting external chaincode connection: %s", ccid)
+	conn, err := i.createConnection(ccinfo)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error cannot create connection for %s", ccid))
+	}
+	defer conn.Close()
+
+	client := pb.NewChaincodeClient(conn)
+	sess, err := client.Connect(context.Background())
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error creating grpc session to %s", ccid))
+	}
+
+	if err := sHandler.HandleChaincodeStream(sess); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error handling chaincode stream for %s", ccid))
+	}
+	return nil
+}
+
+func (i *ExternalChaincodeRuntime) createConnection(ccinfo *ccintf.ChaincodeServerInfo) (*grpc.ClientConn, error) {
+	client, err := comm.NewGRPCClient(ccinfo.ClientConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error creating grpc client")
+	}
+	conn, err := client.NewConnection(ccinfo.Address, "")
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error creating grpc connection to %s", ccinfo.Address))
+	}
+	extccLogger.Debugf("Created external chaincode connection: %s", ccinfo.Address)
+	return conn, nil
+}
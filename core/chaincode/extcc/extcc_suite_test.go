@@ -0,0 +1,25 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extcc_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/extcc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+//go:generate counterfeiter -o mock/stream_handler.go --fake-name StreamHandler . streamHandler
+type streamHandler interface {
+	extcc.StreamHandler
+}
+
+func TestExtcc(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Extcc Suite")
+}
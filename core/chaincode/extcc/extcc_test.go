@@ -0,0 +1,107 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extcc_test
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/extcc"
+	"github.com/hyperledger/fabric/core/chaincode/extcc/mock"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+type chaincodeServer struct{}
+
+func (chaincodeServer) Connect(stream pb.Chaincode_ConnectServer) error {
+	return stream.Send(&pb.ChaincodeMessage{Type: pb.ChaincodeMessage_REGISTER, Payload: []byte("register")})
+}
+
+var _ = Describe("ExternalChaincodeRuntime", func() {
+	var (
+		server            *comm.GRPCServer
+		fakeStreamHandler *mock.StreamHandler
+		ccservinfo        *ccintf.ChaincodeServerInfo
+		runtime           *extcc.ExternalChaincodeRuntime
+	)
+
+	BeforeEach(func() {
+		var err error
+		server, err = comm.NewGRPCServer("127.0.0.1:0", comm.ServerConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		pb.RegisterChaincodeServer(server.Server(), chaincodeServer{})
+		go server.Start()
+
+		fakeStreamHandler = &mock.StreamHandler{}
+		ccservinfo = &ccintf.ChaincodeServerInfo{
+			Address:      server.Address(),
+			ClientConfig: comm.ClientConfig{Timeout: 3 * time.Second},
+		}
+		runtime = &extcc.ExternalChaincodeRuntime{}
+	})
+
+	AfterEach(func() {
+		server.Stop()
+	})
+
+	It("connects to the chaincode server and handles the stream", func() {
+		var msg *pb.ChaincodeMessage
+		fakeStreamHandler.HandleChaincodeStreamStub = func(stream ccintf.ChaincodeStream) error {
+			var err error
+			msg, err = stream.Recv()
+			return err
+		}
+
+		err := runtime.Stream("mycc:1.0", ccservinfo, fakeStreamHandler)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeStreamHandler.HandleChaincodeStreamCallCount()).To(Equal(1))
+		Expect(msg.Type).To(Equal(pb.ChaincodeMessage_REGISTER))
+		Expect(msg.Payload).To(Equal([]byte("register")))
+	})
+
+	Context("when handling the stream fails", func() {
+		BeforeEach(func() {
+			fakeStreamHandler.HandleChaincodeStreamReturns(errors.New("mango"))
+		})
+
+		It("returns an error", func() {
+			err := runtime.Stream("mycc:1.0", ccservinfo, fakeStreamHandler)
+			Expect(err).To(MatchError("error handling chaincode stream for mycc:1.0: mango"))
+		})
+	})
+
+	Context("when the chaincode server cannot be reached", func() {
+		BeforeEach(func() {
+			server.Stop()
+			ccservinfo.ClientConfig.Timeout = 100 * time.Millisecond
+		})
+
+		It("returns an error", func() {
+			err := runtime.Stream("mycc:1.0", ccservinfo, fakeStreamHandler)
+			Expect(err).To(MatchError(ContainSubstring("error cannot create connection for mycc:1.0: error creating grpc connection to " + server.Address())))
+			Expect(fakeStreamHandler.HandleChaincodeStreamCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the client configuration is invalid", func() {
+		BeforeEach(func() {
+			ccservinfo.ClientConfig.SecOpts = &comm.SecureOptions{
+				UseTLS:            true,
+				RequireClientCert: true,
+			}
+		})
+
+		It("returns an error", func() {
+			err := runtime.Stream("mycc:1.0", ccservinfo, fakeStreamHandler)
+			Expect(err).To(MatchError("error cannot create connection for mycc:1.0: error creating grpc client: both Key and Certificate are required when using mutual TLS"))
+		})
+	})
+})
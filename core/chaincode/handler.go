@@ -894,15 +894,54 @@ func (h *Handler) HandleGetHistoryForKey(msg *pb.ChaincodeMessage, txContext *Tr
 		return nil, errors.Wrap(err, "unmarshal failed")
 	}
 
-	historyIter, err := txContext.HistoryQueryExecutor.GetHistoryForKey(chaincodeName, getHistoryForKey.Key)
+	metadata, err := getHistoryQueryMetadataFromBytes(getHistoryForKey.Metadata)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return nil, err
 	}
 
-	totalReturnLimit := calculateTotalReturnLimit(nil)
+	paginationMetadata := &pb.QueryMetadata{}
+	if metadata != nil {
+		paginationMetadata.PageSize = metadata.PageSize
+		paginationMetadata.Bookmark = metadata.Bookmark
+	}
+	totalReturnLimit := calculateTotalReturnLimit(paginationMetadata)
+	isPaginated := isMetadataSetForPagination(paginationMetadata)
+
+	var historyIter commonledger.ResultsIterator
+	collection := getHistoryForKey.Collection
+	if isCollectionSet(collection) {
+		if txContext.IsInitTransaction {
+			return nil, errors.New("private data APIs are not allowed in chaincode Init()")
+		}
+		if metadata != nil {
+			return nil, errors.New("history query options are not supported for private data")
+		}
+		if err := errorIfCreatorHasNoReadAccess(chaincodeName, collection, txContext); err != nil {
+			return nil, err
+		}
+		historyIter, err = txContext.HistoryQueryExecutor.GetPrivateDataHistoryForKey(chaincodeName, collection, getHistoryForKey.Key)
+	} else if metadata != nil {
+		options := &ledger.HistoryQueryOptions{
+			StartBlock:  metadata.StartBlock,
+			EndBlock:    metadata.EndBlock,
+			StartTime:   metadata.StartTime,
+			EndTime:     metadata.EndTime,
+			NewestFirst: metadata.NewestFirst,
+			Bookmark:    metadata.Bookmark,
+		}
+		if isPaginated {
+			options.Limit = totalReturnLimit
+		}
+		historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKeyWithOptions(chaincodeName, getHistoryForKey.Key, options)
+	} else {
+		historyIter, err = txContext.HistoryQueryExecutor.GetHistoryForKey(chaincodeName, getHistoryForKey.Key)
+	}
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
 
 	txContext.InitializeQueryContext(iterID, historyIter)
-	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, historyIter, iterID, false, totalReturnLimit)
+	payload, err := h.QueryResponseBuilder.BuildQueryResponse(txContext, historyIter, iterID, isPaginated, totalReturnLimit)
 	if err != nil {
 		txContext.CleanupQueryContext(iterID)
 		return nil, errors.WithStack(err)
@@ -946,6 +985,18 @@ func getQueryMetadataFromBytes(metadataBytes []byte) (*pb.QueryMetadata, error)
 	return nil, nil
 }
 
+func getHistoryQueryMetadataFromBytes(metadataBytes []byte) (*pb.HistoryQueryMetadata, error) {
+	if metadataBytes != nil {
+		metadata := &pb.HistoryQueryMetadata{}
+		err := proto.Unmarshal(metadataBytes, metadata)
+		if err != nil {
+			return nil, errors.Wrap(err, "unmarshal failed")
+		}
+		return metadata, nil
+	}
+	return nil, nil
+}
+
 func createPaginationInfoFromMetadata(metadata *pb.QueryMetadata, totalReturnLimit int32, queryType pb.ChaincodeMessage_Type) (map[string]interface{}, error) {
 	paginationInfoMap := make(map[string]interface{})
 
@@ -1167,9 +1218,12 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 
 		version = cd.CCVersion()
 
-		err = h.InstantiationPolicyChecker.CheckInstantiationPolicy(targetInstance.ChaincodeName, version, cd.(*ccprovider.ChaincodeData))
-		if err != nil {
-			return nil, errors.WithStack(err)
+		// only chaincodes instantiated through lscc carry an instantiation policy
+		if cdata, ok := cd.(*ccprovider.ChaincodeData); ok {
+			err = h.InstantiationPolicyChecker.CheckInstantiationPolicy(targetInstance.ChaincodeName, version, cdata)
+			if err != nil {
+				return nil, errors.WithStack(err)
+			}
 		}
 	}
 
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/common/util"
@@ -20,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/mock"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/ginkgo/extensions/table"
@@ -1923,6 +1925,166 @@ var _ = Describe("Handler", func() {
 			Expect(iterID).To(Equal("generated-query-id"))
 		})
 
+		Context("when the request has metadata", func() {
+			var metadata *pb.HistoryQueryMetadata
+
+			BeforeEach(func() {
+				metadata = &pb.HistoryQueryMetadata{
+					StartBlock:  2,
+					EndBlock:    5,
+					StartTime:   &timestamp.Timestamp{Seconds: 10},
+					EndTime:     &timestamp.Timestamp{Seconds: 20},
+					NewestFirst: true,
+				}
+				fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsReturns(fakeIterator, nil)
+			})
+
+			JustBeforeEach(func() {
+				metadataBytes, err := proto.Marshal(metadata)
+				Expect(err).NotTo(HaveOccurred())
+				request.Metadata = metadataBytes
+				incomingMessage.Payload, err = proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("calls GetHistoryForKeyWithOptions on the history query executor", func() {
+				_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeHistoryQueryExecutor.GetHistoryForKeyCallCount()).To(Equal(0))
+				Expect(fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsCallCount()).To(Equal(1))
+				ccname, key, options := fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsArgsForCall(0)
+				Expect(ccname).To(Equal("cc-instance-name"))
+				Expect(key).To(Equal("history-key"))
+				Expect(options).To(Equal(&ledger.HistoryQueryOptions{
+					StartBlock:  2,
+					EndBlock:    5,
+					StartTime:   &timestamp.Timestamp{Seconds: 10},
+					EndTime:     &timestamp.Timestamp{Seconds: 20},
+					NewestFirst: true,
+				}))
+
+				Expect(fakeQueryResponseBuilder.BuildQueryResponseCallCount()).To(Equal(1))
+				_, _, _, isPaginated, _ := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+				Expect(isPaginated).To(BeFalse())
+			})
+
+			Context("when the metadata has a page size and a bookmark", func() {
+				BeforeEach(func() {
+					metadata.PageSize = 5
+					metadata.Bookmark = "3:1"
+				})
+
+				It("paginates the history", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsCallCount()).To(Equal(1))
+					_, _, options := fakeHistoryQueryExecutor.GetHistoryForKeyWithOptionsArgsForCall(0)
+					Expect(options.Limit).To(Equal(int32(5)))
+					Expect(options.Bookmark).To(Equal("3:1"))
+
+					Expect(fakeQueryResponseBuilder.BuildQueryResponseCallCount()).To(Equal(1))
+					_, _, _, isPaginated, totalReturnLimit := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+					Expect(isPaginated).To(BeTrue())
+					Expect(totalReturnLimit).To(Equal(int32(5)))
+				})
+			})
+
+			Context("when unmarshalling the metadata fails", func() {
+				JustBeforeEach(func() {
+					request.Metadata = []byte("this-is-a-bogus-payload")
+					payload, err := proto.Marshal(request)
+					Expect(err).NotTo(HaveOccurred())
+					incomingMessage.Payload = payload
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).To(MatchError("unmarshal failed: proto: can't skip unknown wire type 4"))
+				})
+			})
+		})
+
+		Context("when collection is set", func() {
+			BeforeEach(func() {
+				request.Collection = "collection-name"
+				payload, err := proto.Marshal(request)
+				Expect(err).NotTo(HaveOccurred())
+				incomingMessage.Payload = payload
+
+				fakeCollectionStore.HasReadAccessReturns(true, nil)
+				fakeHistoryQueryExecutor.GetPrivateDataHistoryForKeyReturns(fakeIterator, nil)
+			})
+
+			It("calls GetPrivateDataHistoryForKey on the history query executor", func() {
+				_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeHistoryQueryExecutor.GetHistoryForKeyCallCount()).To(Equal(0))
+				Expect(fakeHistoryQueryExecutor.GetPrivateDataHistoryForKeyCallCount()).To(Equal(1))
+				ccname, collection, key := fakeHistoryQueryExecutor.GetPrivateDataHistoryForKeyArgsForCall(0)
+				Expect(ccname).To(Equal("cc-instance-name"))
+				Expect(collection).To(Equal("collection-name"))
+				Expect(key).To(Equal("history-key"))
+
+				Expect(fakeQueryResponseBuilder.BuildQueryResponseCallCount()).To(Equal(1))
+				_, iter, _, _, _ := fakeQueryResponseBuilder.BuildQueryResponseArgsForCall(0)
+				Expect(iter).To(Equal(fakeIterator))
+			})
+
+			Context("and the creator has no read access", func() {
+				BeforeEach(func() {
+					fakeCollectionStore.HasReadAccessReturns(false, nil)
+				})
+
+				It("returns the error from errorIfCreatorHasNoReadAccess", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).To(MatchError("tx creator does not have read access" +
+						" permission on privatedata in chaincodeName:cc-instance-name" +
+						" collectionName: collection-name"))
+					Expect(fakeHistoryQueryExecutor.GetPrivateDataHistoryForKeyCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("and the transaction is an Init transaction", func() {
+				BeforeEach(func() {
+					txContext.IsInitTransaction = true
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).To(MatchError("private data APIs are not allowed in chaincode Init()"))
+				})
+			})
+
+			Context("and the request has metadata", func() {
+				BeforeEach(func() {
+					metadataBytes, err := proto.Marshal(&pb.HistoryQueryMetadata{NewestFirst: true})
+					Expect(err).NotTo(HaveOccurred())
+					request.Metadata = metadataBytes
+					incomingMessage.Payload, err = proto.Marshal(request)
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).To(MatchError("history query options are not supported for private data"))
+				})
+			})
+
+			Context("and the history query executor fails", func() {
+				BeforeEach(func() {
+					fakeHistoryQueryExecutor.GetPrivateDataHistoryForKeyReturns(nil, errors.New("anchovies"))
+				})
+
+				It("returns an error", func() {
+					_, err := handler.HandleGetHistoryForKey(incomingMessage, txContext)
+					Expect(err).To(MatchError("anchovies"))
+				})
+			})
+		})
+
 		Context("when unmarshalling the request fails", func() {
 			BeforeEach(func() {
 				incomingMessage.Payload = []byte("this-is-a-bogus-payload")
@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LifecycleEndorsementPolicyName is the name of the policy of the application
+	// group of the channel config which the commit of a chaincode definition must
+	// be endorsed according to.  Its rule also decides how many of the application
+	// orgs of the channel must approve a definition before it may be committed.
+	LifecycleEndorsementPolicyName = "LifecycleEndorsement"
+
+	// LifecycleEndorsementPolicyPath is the fully qualified path of the
+	// LifecycleEndorsement policy.
+	LifecycleEndorsementPolicyPath = "/" + channelconfig.ChannelGroupKey + "/" + channelconfig.ApplicationGroupKey + "/" + LifecycleEndorsementPolicyName
+)
+
+// ChannelConfigSource provides the configuration of the channels the peer has joined.
+type ChannelConfigSource interface {
+	GetChannelConfig(channelID string) channelconfig.Resources
+}
+
+// ImplicitMetaApprovalPolicy requires ANY, ALL or a MAJORITY of the
+// application orgs of the channel to approve a definition, in the same
+// way an implicit meta policy combines the policies of sub-groups.
+type ImplicitMetaApprovalPolicy struct {
+	Rule cb.ImplicitMetaPolicy_Rule
+}
+
+// ApprovalPolicyFromChannelConfig returns the approval policy of a channel,
+// whose rule is the rule of the implicit meta LifecycleEndorsement policy
+// of the application group of the channel config.
+func ApprovalPolicyFromChannelConfig(config *cb.Config) (*ImplicitMetaApprovalPolicy, error) {
+	appGroup := config.GetChannelGroup().GetGroups()[channelconfig.ApplicationGroupKey]
+	if appGroup == nil {
+		return nil, errors.New("channel config has no application group")
+	}
+
+	configPolicy := appGroup.Policies[LifecycleEndorsementPolicyName]
+	if configPolicy == nil || configPolicy.Policy == nil {
+		return nil, errors.Errorf("channel config has no %s policy", LifecycleEndorsementPolicyName)
+	}
+
+	if configPolicy.Policy.Type != int32(cb.Policy_IMPLICIT_META) {
+		return nil, errors.Errorf("%s policy must be an implicit meta policy", LifecycleEndorsementPolicyName)
+	}
+
+	implicitMetaPolicy := &cb.ImplicitMetaPolicy{}
+	err := proto.Unmarshal(configPolicy.Policy.Value, implicitMetaPolicy)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal %s policy", LifecycleEndorsementPolicyName)
+	}
+
+	return &ImplicitMetaApprovalPolicy{
+		Rule: implicitMetaPolicy.Rule,
+	}, nil
+}
+
+// Evaluate takes the approval status of each application org of the channel
+// and returns an error if the number of approvals does not satisfy the policy rule.
+func (p *ImplicitMetaApprovalPolicy) Evaluate(approvals map[string]bool) error {
+	var approved, missing []string
+	for mspID, ok := range approvals {
+		if ok {
+			approved = append(approved, mspID)
+		} else {
+			missing = append(missing, mspID)
+		}
+	}
+	sort.Strings(missing)
+
+	var required int
+	switch p.Rule {
+	case cb.ImplicitMetaPolicy_ANY:
+		required = 1
+	case cb.ImplicitMetaPolicy_ALL:
+		required = len(approvals)
+	case cb.ImplicitMetaPolicy_MAJORITY:
+		required = len(approvals)/2 + 1
+	default:
+		return errors.Errorf("unknown approval policy rule %d", p.Rule)
+	}
+
+	if len(approved) < required {
+		return errors.Errorf("%s approval policy requires %d of %d orgs but only %d approved, orgs which have not approved: [%s]",
+			p.Rule, required, len(approvals), len(approved), strings.Join(missing, ", "))
+	}
+
+	return nil
+}
+
+// approvalPolicy returns the approval policy of the channel.
+func (l *Lifecycle) approvalPolicy(channelID string) (*ImplicitMetaApprovalPolicy, error) {
+	channelConfig := l.ChannelConfigSource.GetChannelConfig(channelID)
+	if channelConfig == nil {
+		return nil, errors.Errorf("could not get channel config for channel '%s'", channelID)
+	}
+
+	policy, err := ApprovalPolicyFromChannelConfig(channelConfig.ConfigtxValidator().ConfigProto())
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not get approval policy of channel '%s'", channelID))
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle_test
+
+import (
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	cb "github.com/hyperledger/fabric/protos/common"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ImplicitMetaApprovalPolicy", func() {
+	Describe("ApprovalPolicyFromChannelConfig", func() {
+		It("takes the rule of the LifecycleEndorsement policy", func() {
+			policy, err := lifecycle.ApprovalPolicyFromChannelConfig(ChannelConfigWithApprovalRule(cb.ImplicitMetaPolicy_MAJORITY))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(policy.Rule).To(Equal(cb.ImplicitMetaPolicy_MAJORITY))
+		})
+
+		Context("when the channel has no application group", func() {
+			It("returns an error", func() {
+				_, err := lifecycle.ApprovalPolicyFromChannelConfig(&cb.Config{})
+				Expect(err).To(MatchError("channel config has no application group"))
+			})
+		})
+
+		Context("when the LifecycleEndorsement policy is not an implicit meta policy", func() {
+			It("returns an error", func() {
+				config := ChannelConfigWithApprovalRule(cb.ImplicitMetaPolicy_ANY)
+				config.ChannelGroup.Groups["Application"].Policies["LifecycleEndorsement"].Policy.Type = int32(cb.Policy_SIGNATURE)
+				_, err := lifecycle.ApprovalPolicyFromChannelConfig(config)
+				Expect(err).To(MatchError("LifecycleEndorsement policy must be an implicit meta policy"))
+			})
+		})
+
+		Context("when the LifecycleEndorsement policy is corrupt", func() {
+			It("returns an error", func() {
+				config := ChannelConfigWithApprovalRule(cb.ImplicitMetaPolicy_ANY)
+				config.ChannelGroup.Groups["Application"].Policies["LifecycleEndorsement"].Policy.Value = []byte("garbage")
+				_, err := lifecycle.ApprovalPolicyFromChannelConfig(config)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("could not unmarshal LifecycleEndorsement policy"))
+			})
+		})
+	})
+
+	DescribeTable("Evaluate",
+		func(rule cb.ImplicitMetaPolicy_Rule, approvals map[string]bool, expectedErr string) {
+			policy := &lifecycle.ImplicitMetaApprovalPolicy{Rule: rule}
+			err := policy.Evaluate(approvals)
+			if expectedErr == "" {
+				Expect(err).NotTo(HaveOccurred())
+			} else {
+				Expect(err).To(MatchError(expectedErr))
+			}
+		},
+		Entry("ANY with one approval", cb.ImplicitMetaPolicy_ANY, map[string]bool{"org1": false, "org2": true}, ""),
+		Entry("ANY with no approvals", cb.ImplicitMetaPolicy_ANY, map[string]bool{"org1": false, "org2": false},
+			"ANY approval policy requires 1 of 2 orgs but only 0 approved, orgs which have not approved: [org1, org2]"),
+		Entry("ALL with all approvals", cb.ImplicitMetaPolicy_ALL, map[string]bool{"org1": true, "org2": true}, ""),
+		Entry("ALL with a missing approval", cb.ImplicitMetaPolicy_ALL, map[string]bool{"org1": true, "org2": false},
+			"ALL approval policy requires 2 of 2 orgs but only 1 approved, orgs which have not approved: [org2]"),
+		Entry("MAJORITY with a majority", cb.ImplicitMetaPolicy_MAJORITY, map[string]bool{"org1": true, "org2": true, "org3": false}, ""),
+		Entry("MAJORITY with half", cb.ImplicitMetaPolicy_MAJORITY, map[string]bool{"org1": true, "org2": false},
+			"MAJORITY approval policy requires 2 of 2 orgs but only 1 approved, orgs which have not approved: [org2]"),
+		Entry("unknown rule", cb.ImplicitMetaPolicy_Rule(99), map[string]bool{"org1": true}, "unknown approval policy rule 99"),
+	)
+})
@@ -0,0 +1,254 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/util"
+	cb "github.com/hyperledger/fabric/protos/common"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefinitionsPrefix is the prefix of the keys in the lifecycle namespace
+	// holding the chaincode definitions committed to the channel.
+	DefinitionsPrefix = "namespaces/definitions/"
+
+	// ApprovalsPrefix is the prefix of the keys in the lifecycle namespace
+	// holding each org's approval of a chaincode definition.
+	ApprovalsPrefix = "namespaces/approvals/"
+)
+
+// ReadableState is the subset of the chaincode stub needed to read
+// the lifecycle namespace of the channel state.
+type ReadableState interface {
+	GetState(key string) (value []byte, err error)
+}
+
+// ReadWritableState is the subset of the chaincode stub needed to read
+// and modify the lifecycle namespace of the channel state.
+type ReadWritableState interface {
+	ReadableState
+	PutState(key string, value []byte) error
+}
+
+// ChannelMembership returns the MSP IDs of the application orgs of a channel.
+type ChannelMembership interface {
+	GetMSPIDs(channelID string) []string
+}
+
+// ChaincodeDefinition contains the parameters of a chaincode which the
+// orgs of a channel must agree upon before the chaincode may be used.
+type ChaincodeDefinition struct {
+	Name              string
+	Sequence          int64
+	Version           string
+	EndorsementPlugin string
+	ValidationPlugin  string
+	EndorsementPolicy []byte
+	Collections       *cb.CollectionConfigPackage
+}
+
+// stateDefinition returns the form of the definition persisted in the
+// lifecycle namespace.
+func (cd *ChaincodeDefinition) stateDefinition() *lb.StateChaincodeDefinition {
+	return &lb.StateChaincodeDefinition{
+		Sequence:          cd.Sequence,
+		Version:           cd.Version,
+		EndorsementPlugin: cd.EndorsementPlugin,
+		ValidationPlugin:  cd.ValidationPlugin,
+		EndorsementPolicy: cd.EndorsementPolicy,
+		Collections:       cd.Collections,
+	}
+}
+
+// hash returns the hash of the definition which is recorded as an org's
+// approval.
+func (cd *ChaincodeDefinition) hash() ([]byte, error) {
+	definitionBytes, err := proto.Marshal(cd.stateDefinition())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal chaincode definition")
+	}
+	return util.ComputeSHA256(definitionBytes), nil
+}
+
+// DefinitionKey returns the key under which the definition of the named
+// chaincode is stored once committed.
+func DefinitionKey(name string) string {
+	return DefinitionsPrefix + name
+}
+
+// ApprovalKey returns the key under which an org records its approval of
+// a given sequence of the named chaincode.
+func ApprovalKey(name string, sequence int64, mspID string) string {
+	return fmt.Sprintf("%s%s#%d/%s", ApprovalsPrefix, name, sequence, mspID)
+}
+
+// ApproveChaincodeDefinitionForOrg records this peer's org approval of a chaincode
+// definition.  The definition must be for the sequence following the one
+// currently committed for the chaincode.
+func (l *Lifecycle) ApproveChaincodeDefinitionForOrg(cd *ChaincodeDefinition, publicState ReadWritableState) error {
+	if err := l.checkNextSequence(cd, publicState); err != nil {
+		return err
+	}
+
+	hash, err := cd.hash()
+	if err != nil {
+		return err
+	}
+
+	err = publicState.PutState(ApprovalKey(cd.Name, cd.Sequence, l.OrgMSPID), hash)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("could not write approval of org '%s'", l.OrgMSPID))
+	}
+
+	return nil
+}
+
+// CommitChaincodeDefinition commits a chaincode definition to the channel once
+// the orgs which have approved it satisfy the approval policy of the channel,
+// which is derived from the LifecycleEndorsement policy of the channel config.
+// This peer's own org must be among the approving orgs.  It returns the
+// approval status of each org of the channel.
+func (l *Lifecycle) CommitChaincodeDefinition(channelID string, cd *ChaincodeDefinition, publicState ReadWritableState) (map[string]bool, error) {
+	approvals, err := l.QueryApprovalStatus(channelID, cd, publicState)
+	if err != nil {
+		return nil, err
+	}
+
+	if !approvals[l.OrgMSPID] {
+		return approvals, errors.Errorf("chaincode definition for '%s' at sequence %d not approved by this peer's org '%s'", cd.Name, cd.Sequence, l.OrgMSPID)
+	}
+
+	approvalPolicy, err := l.approvalPolicy(channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := approvalPolicy.Evaluate(approvals); err != nil {
+		return approvals, errors.WithMessage(err, fmt.Sprintf("chaincode definition for '%s' at sequence %d not approved", cd.Name, cd.Sequence))
+	}
+
+	definitionBytes, err := proto.Marshal(cd.stateDefinition())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal chaincode definition")
+	}
+
+	err = publicState.PutState(DefinitionKey(cd.Name), definitionBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not write definition for chaincode '%s'", cd.Name))
+	}
+
+	return approvals, nil
+}
+
+// QueryApprovalStatus returns whether each application org of the channel has
+// approved the given chaincode definition.
+func (l *Lifecycle) QueryApprovalStatus(channelID string, cd *ChaincodeDefinition, publicState ReadableState) (map[string]bool, error) {
+	if err := l.checkNextSequence(cd, publicState); err != nil {
+		return nil, err
+	}
+
+	hash, err := cd.hash()
+	if err != nil {
+		return nil, err
+	}
+
+	return DefinitionApprovals(cd.Name, cd.Sequence, hash, l.ChannelMembership.GetMSPIDs(channelID), publicState)
+}
+
+// DefinitionApprovals returns whether each of the given orgs has recorded an
+// approval of the definition with the given hash for a sequence of the named
+// chaincode.
+func DefinitionApprovals(name string, sequence int64, hash []byte, mspIDs []string, publicState ReadableState) (map[string]bool, error) {
+	approvals := map[string]bool{}
+	for _, mspID := range mspIDs {
+		approvedHash, err := publicState.GetState(ApprovalKey(name, sequence, mspID))
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("could not read approval of org '%s'", mspID))
+		}
+		approvals[mspID] = approvedHash != nil && bytes.Equal(approvedHash, hash)
+	}
+
+	return approvals, nil
+}
+
+// QueryChaincodeDefinition returns the definition of the named chaincode which
+// is currently committed to the channel.
+func (l *Lifecycle) QueryChaincodeDefinition(name string, publicState ReadableState) (*ChaincodeDefinition, error) {
+	definition, err := CommittedDefinition(name, publicState)
+	if err != nil {
+		return nil, err
+	}
+
+	if definition == nil {
+		return nil, errors.Errorf("chaincode '%s' has no committed definition", name)
+	}
+
+	return definition, nil
+}
+
+// checkNextSequence ensures that the definition is well formed and that its
+// sequence immediately follows the sequence of the committed definition.
+func (l *Lifecycle) checkNextSequence(cd *ChaincodeDefinition, publicState ReadableState) error {
+	if cd.Name == "" {
+		return errors.New("chaincode name must be set")
+	}
+
+	if cd.Version == "" {
+		return errors.New("chaincode version must be set")
+	}
+
+	definition, err := CommittedDefinition(cd.Name, publicState)
+	if err != nil {
+		return err
+	}
+
+	var currentSequence int64
+	if definition != nil {
+		currentSequence = definition.Sequence
+	}
+
+	if cd.Sequence != currentSequence+1 {
+		return errors.Errorf("requested sequence is %d, but new definition must be sequence %d", cd.Sequence, currentSequence+1)
+	}
+
+	return nil
+}
+
+// CommittedDefinition reads the committed definition of the named chaincode,
+// returning nil if none has been committed.
+func CommittedDefinition(name string, publicState ReadableState) (*ChaincodeDefinition, error) {
+	definitionBytes, err := publicState.GetState(DefinitionKey(name))
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not read definition for chaincode '%s'", name))
+	}
+
+	if definitionBytes == nil {
+		return nil, nil
+	}
+
+	definition := &lb.StateChaincodeDefinition{}
+	err = proto.Unmarshal(definitionBytes, definition)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal definition for chaincode '%s'", name)
+	}
+
+	return &ChaincodeDefinition{
+		Name:              name,
+		Sequence:          definition.Sequence,
+		Version:           definition.Version,
+		EndorsementPlugin: definition.EndorsementPlugin,
+		ValidationPlugin:  definition.ValidationPlugin,
+		EndorsementPolicy: definition.EndorsementPolicy,
+		Collections:       definition.Collections,
+	}, nil
+}
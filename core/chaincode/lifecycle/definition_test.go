@@ -0,0 +1,261 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle_test
+
+import (
+	"github.com/golang/protobuf/proto"
+	mockchannelconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
+	cb "github.com/hyperledger/fabric/protos/common"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chaincode definitions", func() {
+	var (
+		l                       *lifecycle.Lifecycle
+		fakeChannelMembership   *mock.ChannelMembership
+		fakeChannelConfigSource *mock.ChannelConfigSource
+		fakeConfigtxValidator   *mockconfigtx.Validator
+		state                   MapState
+		cd                      *lifecycle.ChaincodeDefinition
+	)
+
+	BeforeEach(func() {
+		fakeChannelMembership = &mock.ChannelMembership{}
+		fakeChannelMembership.GetMSPIDsReturns([]string{"org1", "org2", "org3"})
+		fakeConfigtxValidator = &mockconfigtx.Validator{
+			ConfigProtoVal: ChannelConfigWithApprovalRule(cb.ImplicitMetaPolicy_ANY),
+		}
+		fakeChannelConfigSource = &mock.ChannelConfigSource{}
+		fakeChannelConfigSource.GetChannelConfigReturns(&mockchannelconfig.Resources{
+			ConfigtxValidatorVal: fakeConfigtxValidator,
+		})
+
+		l = &lifecycle.Lifecycle{
+			ChannelMembership:   fakeChannelMembership,
+			ChannelConfigSource: fakeChannelConfigSource,
+			OrgMSPID:            "org1",
+		}
+
+		state = MapState{}
+
+		cd = &lifecycle.ChaincodeDefinition{
+			Name:              "cc-name",
+			Sequence:          1,
+			Version:           "1.0",
+			EndorsementPlugin: "escc",
+			ValidationPlugin:  "vscc",
+			EndorsementPolicy: []byte("endorsement-policy"),
+			Collections:       &cb.CollectionConfigPackage{},
+		}
+	})
+
+	Describe("ApproveChaincodeDefinitionForOrg", func() {
+		It("records the approval of the org", func() {
+			err := l.ApproveChaincodeDefinitionForOrg(cd, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(state).To(HaveKey("namespaces/approvals/cc-name#1/org1"))
+			Expect(state["namespaces/approvals/cc-name#1/org1"]).To(HaveLen(32))
+		})
+
+		Context("when the sequence does not follow the committed sequence", func() {
+			BeforeEach(func() {
+				cd.Sequence = 2
+			})
+
+			It("returns an error", func() {
+				err := l.ApproveChaincodeDefinitionForOrg(cd, state)
+				Expect(err).To(MatchError("requested sequence is 2, but new definition must be sequence 1"))
+				Expect(state).To(BeEmpty())
+			})
+		})
+
+		Context("when the name is not set", func() {
+			BeforeEach(func() {
+				cd.Name = ""
+			})
+
+			It("returns an error", func() {
+				err := l.ApproveChaincodeDefinitionForOrg(cd, state)
+				Expect(err).To(MatchError("chaincode name must be set"))
+			})
+		})
+
+		Context("when the version is not set", func() {
+			BeforeEach(func() {
+				cd.Version = ""
+			})
+
+			It("returns an error", func() {
+				err := l.ApproveChaincodeDefinitionForOrg(cd, state)
+				Expect(err).To(MatchError("chaincode version must be set"))
+			})
+		})
+
+		Context("when the committed definition is corrupt", func() {
+			BeforeEach(func() {
+				state["namespaces/definitions/cc-name"] = []byte("garbage")
+			})
+
+			It("returns an error", func() {
+				err := l.ApproveChaincodeDefinitionForOrg(cd, state)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("could not unmarshal definition for chaincode 'cc-name'"))
+			})
+		})
+	})
+
+	Describe("QueryApprovalStatus", func() {
+		BeforeEach(func() {
+			err := l.ApproveChaincodeDefinitionForOrg(cd, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			l.OrgMSPID = "org2"
+			differentCD := *cd
+			differentCD.Version = "2.0"
+			err = l.ApproveChaincodeDefinitionForOrg(&differentCD, state)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("reports which orgs approved exactly this definition", func() {
+			approvals, err := l.QueryApprovalStatus("channel-id", cd, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(approvals).To(Equal(map[string]bool{
+				"org1": true,
+				"org2": false,
+				"org3": false,
+			}))
+
+			Expect(fakeChannelMembership.GetMSPIDsCallCount()).To(Equal(1))
+			Expect(fakeChannelMembership.GetMSPIDsArgsForCall(0)).To(Equal("channel-id"))
+		})
+	})
+
+	Describe("CommitChaincodeDefinition", func() {
+		BeforeEach(func() {
+			err := l.ApproveChaincodeDefinitionForOrg(cd, state)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("evaluates the approvals and writes the definition", func() {
+			approvals, err := l.CommitChaincodeDefinition("channel-id", cd, state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(approvals).To(Equal(map[string]bool{
+				"org1": true,
+				"org2": false,
+				"org3": false,
+			}))
+
+			Expect(fakeChannelConfigSource.GetChannelConfigCallCount()).To(Equal(1))
+			Expect(fakeChannelConfigSource.GetChannelConfigArgsForCall(0)).To(Equal("channel-id"))
+
+			committed := &lb.StateChaincodeDefinition{}
+			err = proto.Unmarshal(state["namespaces/definitions/cc-name"], committed)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(committed.Sequence).To(Equal(int64(1)))
+			Expect(committed.Version).To(Equal("1.0"))
+			Expect(committed.EndorsementPolicy).To(Equal([]byte("endorsement-policy")))
+		})
+
+		It("requires the next definition to use the following sequence", func() {
+			_, err := l.CommitChaincodeDefinition("channel-id", cd, state)
+			Expect(err).NotTo(HaveOccurred())
+
+			err = l.ApproveChaincodeDefinitionForOrg(cd, state)
+			Expect(err).To(MatchError("requested sequence is 1, but new definition must be sequence 2"))
+
+			cd.Sequence = 2
+			err = l.ApproveChaincodeDefinitionForOrg(cd, state)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when the approval policy of the channel is not satisfied", func() {
+			BeforeEach(func() {
+				fakeConfigtxValidator.ConfigProtoVal = ChannelConfigWithApprovalRule(cb.ImplicitMetaPolicy_MAJORITY)
+			})
+
+			It("does not write the definition", func() {
+				approvals, err := l.CommitChaincodeDefinition("channel-id", cd, state)
+				Expect(err).To(MatchError("chaincode definition for 'cc-name' at sequence 1 not approved: MAJORITY approval policy requires 2 of 3 orgs but only 1 approved, orgs which have not approved: [org2, org3]"))
+				Expect(approvals).To(HaveKeyWithValue("org1", true))
+				Expect(state).NotTo(HaveKey("namespaces/definitions/cc-name"))
+			})
+		})
+
+		Context("when this peer's org has not approved the definition", func() {
+			BeforeEach(func() {
+				l.OrgMSPID = "org2"
+			})
+
+			It("does not write the definition", func() {
+				_, err := l.CommitChaincodeDefinition("channel-id", cd, state)
+				Expect(err).To(MatchError("chaincode definition for 'cc-name' at sequence 1 not approved by this peer's org 'org2'"))
+				Expect(state).NotTo(HaveKey("namespaces/definitions/cc-name"))
+			})
+		})
+
+		Context("when the channel config cannot be found", func() {
+			BeforeEach(func() {
+				fakeChannelConfigSource.GetChannelConfigReturns(nil)
+			})
+
+			It("returns an error", func() {
+				_, err := l.CommitChaincodeDefinition("channel-id", cd, state)
+				Expect(err).To(MatchError("could not get channel config for channel 'channel-id'"))
+			})
+		})
+
+		Context("when the channel config has no LifecycleEndorsement policy", func() {
+			BeforeEach(func() {
+				fakeConfigtxValidator.ConfigProtoVal = &cb.Config{
+					ChannelGroup: &cb.ConfigGroup{
+						Groups: map[string]*cb.ConfigGroup{
+							"Application": {},
+						},
+					},
+				}
+			})
+
+			It("returns an error", func() {
+				_, err := l.CommitChaincodeDefinition("channel-id", cd, state)
+				Expect(err).To(MatchError("could not get approval policy of channel 'channel-id': channel config has no LifecycleEndorsement policy"))
+			})
+		})
+	})
+
+	Describe("QueryChaincodeDefinition", func() {
+		BeforeEach(func() {
+			err := l.ApproveChaincodeDefinitionForOrg(cd, state)
+			Expect(err).NotTo(HaveOccurred())
+			_, err = l.CommitChaincodeDefinition("channel-id", cd, state)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("returns the committed definition", func() {
+			committed, err := l.QueryChaincodeDefinition("cc-name", state)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(committed.Name).To(Equal("cc-name"))
+			Expect(committed.Sequence).To(Equal(int64(1)))
+			Expect(committed.Version).To(Equal("1.0"))
+			Expect(committed.EndorsementPlugin).To(Equal("escc"))
+			Expect(committed.ValidationPlugin).To(Equal("vscc"))
+			Expect(committed.EndorsementPolicy).To(Equal([]byte("endorsement-policy")))
+			Expect(proto.Equal(committed.Collections, &cb.CollectionConfigPackage{})).To(BeTrue())
+		})
+
+		Context("when the chaincode has not been defined", func() {
+			It("returns an error", func() {
+				_, err := l.QueryChaincodeDefinition("other-name", state)
+				Expect(err).To(MatchError("chaincode 'other-name' has no committed definition"))
+			})
+		})
+	})
+})
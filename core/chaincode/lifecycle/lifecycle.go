@@ -17,6 +17,7 @@ import (
 type ChaincodeStore interface {
 	Save(name, version string, ccInstallPkg []byte) (hash []byte, err error)
 	RetrieveHash(name, version string) (hash []byte, err error)
+	Load(hash []byte) (ccInstallPkg []byte, name, version string, err error)
 }
 
 type PackageParser interface {
@@ -26,8 +27,18 @@ type PackageParser interface {
 // Lifecycle implements the lifecycle operations which are invoked
 // by the SCC as well as internally
 type Lifecycle struct {
-	ChaincodeStore ChaincodeStore
-	PackageParser  PackageParser
+	ChaincodeStore      ChaincodeStore
+	PackageParser       PackageParser
+	ChannelMembership   ChannelMembership
+	ChannelConfigSource ChannelConfigSource
+
+	// LegacyLifecycle resolves the chaincodes which have no definition
+	// committed through the new lifecycle, i.e. those instantiated via LSCC.
+	LegacyLifecycle LegacyLifecycle
+
+	// OrgMSPID is the MSP ID of the org this peer belongs to, on whose
+	// behalf chaincode definitions are approved.
+	OrgMSPID string
 }
 
 // InstallChaincode installs a given chaincode to the peer's chaincode store.
@@ -9,8 +9,11 @@ package lifecycle_test
 import (
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/ledger"
+	cb "github.com/hyperledger/fabric/protos/common"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 )
@@ -35,6 +38,79 @@ type sccFunctions interface {
 	lifecycle.SCCFunctions
 }
 
+//go:generate counterfeiter -o mock/channel_membership.go --fake-name ChannelMembership . channelMembership
+type channelMembership interface {
+	lifecycle.ChannelMembership
+}
+
+//go:generate counterfeiter -o mock/acl_provider.go --fake-name ACLProvider . aclProvider
+type aclProvider interface {
+	lifecycle.ACLProvider
+}
+
+//go:generate counterfeiter -o mock/policy_checker.go --fake-name PolicyChecker . policyChecker
+type policyChecker interface {
+	lifecycle.PolicyChecker
+}
+
+//go:generate counterfeiter -o mock/channel_config_source.go --fake-name ChannelConfigSource . channelConfigSource
+type channelConfigSource interface {
+	lifecycle.ChannelConfigSource
+}
+
+//go:generate counterfeiter -o mock/legacy_lifecycle.go --fake-name LegacyLifecycle . legacyLifecycle
+type legacyLifecycle interface {
+	lifecycle.LegacyLifecycle
+}
+
+//go:generate counterfeiter -o mock/query_executor.go --fake-name QueryExecutor . queryExecutor
+type queryExecutor interface {
+	ledger.QueryExecutor
+}
+
+// MapState is a map backed implementation of the lifecycle state
+// interfaces for use in tests.
+type MapState map[string][]byte
+
+func (m MapState) GetState(key string) ([]byte, error) {
+	return m[key], nil
+}
+
+func (m MapState) PutState(key string, value []byte) error {
+	m[key] = value
+	return nil
+}
+
+// ChannelConfigWithApprovalRule returns a channel config whose
+// LifecycleEndorsement policy is an implicit meta policy with the given rule.
+func ChannelConfigWithApprovalRule(rule cb.ImplicitMetaPolicy_Rule) *cb.Config {
+	return &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				"Application": {
+					Policies: map[string]*cb.ConfigPolicy{
+						"LifecycleEndorsement": {
+							Policy: &cb.Policy{
+								Type: int32(cb.Policy_IMPLICIT_META),
+								Value: protoMarshal(&cb.ImplicitMetaPolicy{
+									SubPolicy: "Writers",
+									Rule:      rule,
+								}),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func protoMarshal(msg proto.Message) []byte {
+	b, err := proto.Marshal(msg)
+	Expect(err).NotTo(HaveOccurred())
+	return b
+}
+
 func TestLifecycle(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Lifecycle Suite")
@@ -6,6 +6,23 @@ import (
 )
 
 type ChaincodeStore struct {
+	LoadStub        func([]byte) ([]byte, string, string, error)
+	loadMutex       sync.RWMutex
+	loadArgsForCall []struct {
+		arg1 []byte
+	}
+	loadReturns struct {
+		result1 []byte
+		result2 string
+		result3 string
+		result4 error
+	}
+	loadReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 string
+		result3 string
+		result4 error
+	}
 	RetrieveHashStub        func(string, string) ([]byte, error)
 	retrieveHashMutex       sync.RWMutex
 	retrieveHashArgsForCall []struct {
@@ -39,6 +56,80 @@ type ChaincodeStore struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *ChaincodeStore) Load(arg1 []byte) ([]byte, string, string, error) {
+	var arg1Copy []byte
+	if arg1 != nil {
+		arg1Copy = make([]byte, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.loadMutex.Lock()
+	ret, specificReturn := fake.loadReturnsOnCall[len(fake.loadArgsForCall)]
+	fake.loadArgsForCall = append(fake.loadArgsForCall, struct {
+		arg1 []byte
+	}{arg1Copy})
+	fake.recordInvocation("Load", []interface{}{arg1Copy})
+	fake.loadMutex.Unlock()
+	if fake.LoadStub != nil {
+		return fake.LoadStub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3, ret.result4
+	}
+	fakeReturns := fake.loadReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3, fakeReturns.result4
+}
+
+func (fake *ChaincodeStore) LoadCallCount() int {
+	fake.loadMutex.RLock()
+	defer fake.loadMutex.RUnlock()
+	return len(fake.loadArgsForCall)
+}
+
+func (fake *ChaincodeStore) LoadCalls(stub func([]byte) ([]byte, string, string, error)) {
+	fake.loadMutex.Lock()
+	defer fake.loadMutex.Unlock()
+	fake.LoadStub = stub
+}
+
+func (fake *ChaincodeStore) LoadArgsForCall(i int) []byte {
+	fake.loadMutex.RLock()
+	defer fake.loadMutex.RUnlock()
+	argsForCall := fake.loadArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChaincodeStore) LoadReturns(result1 []byte, result2 string, result3 string, result4 error) {
+	fake.loadMutex.Lock()
+	defer fake.loadMutex.Unlock()
+	fake.LoadStub = nil
+	fake.loadReturns = struct {
+		result1 []byte
+		result2 string
+		result3 string
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
+func (fake *ChaincodeStore) LoadReturnsOnCall(i int, result1 []byte, result2 string, result3 string, result4 error) {
+	fake.loadMutex.Lock()
+	defer fake.loadMutex.Unlock()
+	fake.LoadStub = nil
+	if fake.loadReturnsOnCall == nil {
+		fake.loadReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 string
+			result3 string
+			result4 error
+		})
+	}
+	fake.loadReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 string
+		result3 string
+		result4 error
+	}{result1, result2, result3, result4}
+}
+
 func (fake *ChaincodeStore) RetrieveHash(arg1 string, arg2 string) ([]byte, error) {
 	fake.retrieveHashMutex.Lock()
 	ret, specificReturn := fake.retrieveHashReturnsOnCall[len(fake.retrieveHashArgsForCall)]
@@ -176,6 +267,8 @@ func (fake *ChaincodeStore) SaveReturnsOnCall(i int, result1 []byte, result2 err
 func (fake *ChaincodeStore) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.loadMutex.RLock()
+	defer fake.loadMutex.RUnlock()
 	fake.retrieveHashMutex.RLock()
 	defer fake.retrieveHashMutex.RUnlock()
 	fake.saveMutex.RLock()
@@ -138,6 +138,24 @@ type ChaincodeStub struct {
 		result1 shim.HistoryQueryIteratorInterface
 		result2 error
 	}
+	GetHistoryForKeyWithPaginationStub        func(string, *shim.HistoryQueryOptions, int32, string) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error)
+	getHistoryForKeyWithPaginationMutex       sync.RWMutex
+	getHistoryForKeyWithPaginationArgsForCall []struct {
+		arg1 string
+		arg2 *shim.HistoryQueryOptions
+		arg3 int32
+		arg4 string
+	}
+	getHistoryForKeyWithPaginationReturns struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}
+	getHistoryForKeyWithPaginationReturnsOnCall map[int]struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}
 	GetPrivateDataStub        func(string, string) ([]byte, error)
 	getPrivateDataMutex       sync.RWMutex
 	getPrivateDataArgsForCall []struct {
@@ -182,6 +200,20 @@ type ChaincodeStub struct {
 		result1 shim.StateQueryIteratorInterface
 		result2 error
 	}
+	GetPrivateDataHistoryForKeyStub        func(string, string) (shim.PrivateDataHistoryQueryIteratorInterface, error)
+	getPrivateDataHistoryForKeyMutex       sync.RWMutex
+	getPrivateDataHistoryForKeyArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	getPrivateDataHistoryForKeyReturns struct {
+		result1 shim.PrivateDataHistoryQueryIteratorInterface
+		result2 error
+	}
+	getPrivateDataHistoryForKeyReturnsOnCall map[int]struct {
+		result1 shim.PrivateDataHistoryQueryIteratorInterface
+		result2 error
+	}
 	GetPrivateDataQueryResultStub        func(string, string) (shim.StateQueryIteratorInterface, error)
 	getPrivateDataQueryResultMutex       sync.RWMutex
 	getPrivateDataQueryResultArgsForCall []struct {
@@ -1109,6 +1141,75 @@ func (fake *ChaincodeStub) GetHistoryForKeyReturnsOnCall(i int, result1 shim.His
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetHistoryForKeyWithPagination(arg1 string, arg2 *shim.HistoryQueryOptions, arg3 int32, arg4 string) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	fake.getHistoryForKeyWithPaginationMutex.Lock()
+	ret, specificReturn := fake.getHistoryForKeyWithPaginationReturnsOnCall[len(fake.getHistoryForKeyWithPaginationArgsForCall)]
+	fake.getHistoryForKeyWithPaginationArgsForCall = append(fake.getHistoryForKeyWithPaginationArgsForCall, struct {
+		arg1 string
+		arg2 *shim.HistoryQueryOptions
+		arg3 int32
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("GetHistoryForKeyWithPagination", []interface{}{arg1, arg2, arg3, arg4})
+	fake.getHistoryForKeyWithPaginationMutex.Unlock()
+	if fake.GetHistoryForKeyWithPaginationStub != nil {
+		return fake.GetHistoryForKeyWithPaginationStub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	fakeReturns := fake.getHistoryForKeyWithPaginationReturns
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithPaginationCallCount() int {
+	fake.getHistoryForKeyWithPaginationMutex.RLock()
+	defer fake.getHistoryForKeyWithPaginationMutex.RUnlock()
+	return len(fake.getHistoryForKeyWithPaginationArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithPaginationCalls(stub func(string, *shim.HistoryQueryOptions, int32, string) (shim.HistoryQueryIteratorInterface, *peer.QueryResponseMetadata, error)) {
+	fake.getHistoryForKeyWithPaginationMutex.Lock()
+	defer fake.getHistoryForKeyWithPaginationMutex.Unlock()
+	fake.GetHistoryForKeyWithPaginationStub = stub
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithPaginationArgsForCall(i int) (string, *shim.HistoryQueryOptions, int32, string) {
+	fake.getHistoryForKeyWithPaginationMutex.RLock()
+	defer fake.getHistoryForKeyWithPaginationMutex.RUnlock()
+	argsForCall := fake.getHistoryForKeyWithPaginationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithPaginationReturns(result1 shim.HistoryQueryIteratorInterface, result2 *peer.QueryResponseMetadata, result3 error) {
+	fake.getHistoryForKeyWithPaginationMutex.Lock()
+	defer fake.getHistoryForKeyWithPaginationMutex.Unlock()
+	fake.GetHistoryForKeyWithPaginationStub = nil
+	fake.getHistoryForKeyWithPaginationReturns = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *ChaincodeStub) GetHistoryForKeyWithPaginationReturnsOnCall(i int, result1 shim.HistoryQueryIteratorInterface, result2 *peer.QueryResponseMetadata, result3 error) {
+	fake.getHistoryForKeyWithPaginationMutex.Lock()
+	defer fake.getHistoryForKeyWithPaginationMutex.Unlock()
+	fake.GetHistoryForKeyWithPaginationStub = nil
+	if fake.getHistoryForKeyWithPaginationReturnsOnCall == nil {
+		fake.getHistoryForKeyWithPaginationReturnsOnCall = make(map[int]struct {
+			result1 shim.HistoryQueryIteratorInterface
+			result2 *peer.QueryResponseMetadata
+			result3 error
+		})
+	}
+	fake.getHistoryForKeyWithPaginationReturnsOnCall[i] = struct {
+		result1 shim.HistoryQueryIteratorInterface
+		result2 *peer.QueryResponseMetadata
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *ChaincodeStub) GetPrivateData(arg1 string, arg2 string) ([]byte, error) {
 	fake.getPrivateDataMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataReturnsOnCall[len(fake.getPrivateDataArgsForCall)]
@@ -1308,6 +1409,70 @@ func (fake *ChaincodeStub) GetPrivateDataByRangeReturnsOnCall(i int, result1 shi
 	}{result1, result2}
 }
 
+func (fake *ChaincodeStub) GetPrivateDataHistoryForKey(arg1 string, arg2 string) (shim.PrivateDataHistoryQueryIteratorInterface, error) {
+	fake.getPrivateDataHistoryForKeyMutex.Lock()
+	ret, specificReturn := fake.getPrivateDataHistoryForKeyReturnsOnCall[len(fake.getPrivateDataHistoryForKeyArgsForCall)]
+	fake.getPrivateDataHistoryForKeyArgsForCall = append(fake.getPrivateDataHistoryForKeyArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	fake.recordInvocation("GetPrivateDataHistoryForKey", []interface{}{arg1, arg2})
+	fake.getPrivateDataHistoryForKeyMutex.Unlock()
+	if fake.GetPrivateDataHistoryForKeyStub != nil {
+		return fake.GetPrivateDataHistoryForKeyStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getPrivateDataHistoryForKeyReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChaincodeStub) GetPrivateDataHistoryForKeyCallCount() int {
+	fake.getPrivateDataHistoryForKeyMutex.RLock()
+	defer fake.getPrivateDataHistoryForKeyMutex.RUnlock()
+	return len(fake.getPrivateDataHistoryForKeyArgsForCall)
+}
+
+func (fake *ChaincodeStub) GetPrivateDataHistoryForKeyCalls(stub func(string, string) (shim.PrivateDataHistoryQueryIteratorInterface, error)) {
+	fake.getPrivateDataHistoryForKeyMutex.Lock()
+	defer fake.getPrivateDataHistoryForKeyMutex.Unlock()
+	fake.GetPrivateDataHistoryForKeyStub = stub
+}
+
+func (fake *ChaincodeStub) GetPrivateDataHistoryForKeyArgsForCall(i int) (string, string) {
+	fake.getPrivateDataHistoryForKeyMutex.RLock()
+	defer fake.getPrivateDataHistoryForKeyMutex.RUnlock()
+	argsForCall := fake.getPrivateDataHistoryForKeyArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChaincodeStub) GetPrivateDataHistoryForKeyReturns(result1 shim.PrivateDataHistoryQueryIteratorInterface, result2 error) {
+	fake.getPrivateDataHistoryForKeyMutex.Lock()
+	defer fake.getPrivateDataHistoryForKeyMutex.Unlock()
+	fake.GetPrivateDataHistoryForKeyStub = nil
+	fake.getPrivateDataHistoryForKeyReturns = struct {
+		result1 shim.PrivateDataHistoryQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChaincodeStub) GetPrivateDataHistoryForKeyReturnsOnCall(i int, result1 shim.PrivateDataHistoryQueryIteratorInterface, result2 error) {
+	fake.getPrivateDataHistoryForKeyMutex.Lock()
+	defer fake.getPrivateDataHistoryForKeyMutex.Unlock()
+	fake.GetPrivateDataHistoryForKeyStub = nil
+	if fake.getPrivateDataHistoryForKeyReturnsOnCall == nil {
+		fake.getPrivateDataHistoryForKeyReturnsOnCall = make(map[int]struct {
+			result1 shim.PrivateDataHistoryQueryIteratorInterface
+			result2 error
+		})
+	}
+	fake.getPrivateDataHistoryForKeyReturnsOnCall[i] = struct {
+		result1 shim.PrivateDataHistoryQueryIteratorInterface
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChaincodeStub) GetPrivateDataQueryResult(arg1 string, arg2 string) (shim.StateQueryIteratorInterface, error) {
 	fake.getPrivateDataQueryResultMutex.Lock()
 	ret, specificReturn := fake.getPrivateDataQueryResultReturnsOnCall[len(fake.getPrivateDataQueryResultArgsForCall)]
@@ -2728,12 +2893,16 @@ func (fake *ChaincodeStub) Invocations() map[string][][]interface{} {
 	defer fake.getFunctionAndParametersMutex.RUnlock()
 	fake.getHistoryForKeyMutex.RLock()
 	defer fake.getHistoryForKeyMutex.RUnlock()
+	fake.getHistoryForKeyWithPaginationMutex.RLock()
+	defer fake.getHistoryForKeyWithPaginationMutex.RUnlock()
 	fake.getPrivateDataMutex.RLock()
 	defer fake.getPrivateDataMutex.RUnlock()
 	fake.getPrivateDataByPartialCompositeKeyMutex.RLock()
 	defer fake.getPrivateDataByPartialCompositeKeyMutex.RUnlock()
 	fake.getPrivateDataByRangeMutex.RLock()
 	defer fake.getPrivateDataByRangeMutex.RUnlock()
+	fake.getPrivateDataHistoryForKeyMutex.RLock()
+	defer fake.getPrivateDataHistoryForKeyMutex.RUnlock()
 	fake.getPrivateDataQueryResultMutex.RLock()
 	defer fake.getPrivateDataQueryResultMutex.RUnlock()
 	fake.getPrivateDataValidationParameterMutex.RLock()
@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	// LifecycleNamespace is the namespace of the channel state holding the
+	// chaincode definitions and approvals.
+	LifecycleNamespace = "+lifecycle"
+
+	// DefaultEndorsementPlugin is the endorsement plugin of definitions which
+	// do not specify one.
+	DefaultEndorsementPlugin = "escc"
+
+	// DefaultValidationPlugin is the validation plugin of definitions which
+	// do not specify one.
+	DefaultValidationPlugin = "vscc"
+)
+
+// LegacyLifecycle is the lifecycle implemented by LSCC, which is consulted for
+// chaincodes with no definition committed through the new lifecycle.
+type LegacyLifecycle interface {
+	ChaincodeDefinition(chaincodeName string, qe ledger.QueryExecutor) (ccprovider.ChaincodeDefinition, error)
+	ChaincodeContainerInfo(chaincodeName string, qe ledger.QueryExecutor) (*ccprovider.ChaincodeContainerInfo, error)
+}
+
+// CCName returns the name of the chaincode.
+func (cd *ChaincodeDefinition) CCName() string {
+	return cd.Name
+}
+
+// Hash returns nil, as a definition is not bound to the hash of a
+// particular chaincode package.
+func (cd *ChaincodeDefinition) Hash() []byte {
+	return nil
+}
+
+// CCVersion returns the version of the chaincode.
+func (cd *ChaincodeDefinition) CCVersion() string {
+	return cd.Version
+}
+
+// Validation returns the validation plugin and endorsement policy of the chaincode.
+func (cd *ChaincodeDefinition) Validation() (string, []byte) {
+	if cd.ValidationPlugin == "" {
+		return DefaultValidationPlugin, cd.EndorsementPolicy
+	}
+	return cd.ValidationPlugin, cd.EndorsementPolicy
+}
+
+// Endorsement returns the endorsement plugin of the chaincode.
+func (cd *ChaincodeDefinition) Endorsement() string {
+	if cd.EndorsementPlugin == "" {
+		return DefaultEndorsementPlugin
+	}
+	return cd.EndorsementPlugin
+}
+
+// queryExecutorState adapts a ledger query executor to read the lifecycle namespace.
+type queryExecutorState struct {
+	qe ledger.QueryExecutor
+}
+
+func (s *queryExecutorState) GetState(key string) ([]byte, error) {
+	return s.qe.GetState(LifecycleNamespace, key)
+}
+
+// ChaincodeDefinition returns the definition of the named chaincode committed to
+// the channel, falling back to the legacy lifecycle if none was committed.
+func (l *Lifecycle) ChaincodeDefinition(chaincodeName string, qe ledger.QueryExecutor) (ccprovider.ChaincodeDefinition, error) {
+	definition, err := CommittedDefinition(chaincodeName, &queryExecutorState{qe: qe})
+	if err != nil {
+		return nil, err
+	}
+
+	if definition == nil {
+		return l.LegacyLifecycle.ChaincodeDefinition(chaincodeName, qe)
+	}
+
+	return definition, nil
+}
+
+// ChaincodeContainerInfo returns the information necessary to launch the named
+// chaincode at the version committed to the channel, falling back to the legacy
+// lifecycle if no definition was committed.
+func (l *Lifecycle) ChaincodeContainerInfo(chaincodeName string, qe ledger.QueryExecutor) (*ccprovider.ChaincodeContainerInfo, error) {
+	definition, err := CommittedDefinition(chaincodeName, &queryExecutorState{qe: qe})
+	if err != nil {
+		return nil, err
+	}
+
+	if definition == nil {
+		return l.LegacyLifecycle.ChaincodeContainerInfo(chaincodeName, qe)
+	}
+
+	hash, err := l.ChaincodeStore.RetrieveHash(chaincodeName, definition.Version)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("chaincode '%s:%s' is not installed", chaincodeName, definition.Version))
+	}
+
+	ccInstallPkg, _, _, err := l.ChaincodeStore.Load(hash)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not load chaincode '%s:%s'", chaincodeName, definition.Version))
+	}
+
+	ccPackage, err := l.PackageParser.Parse(ccInstallPkg)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not parse chaincode package for '%s:%s'", chaincodeName, definition.Version))
+	}
+
+	return &ccprovider.ChaincodeContainerInfo{
+		Name:          chaincodeName,
+		Version:       definition.Version,
+		Path:          ccPackage.Metadata.Path,
+		Type:          strings.ToUpper(ccPackage.Metadata.Type),
+		ContainerType: pb.ChaincodeDeploymentSpec_DOCKER.String(),
+	}, nil
+}
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle_test
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chaincode resolution", func() {
+	var (
+		l                   *lifecycle.Lifecycle
+		fakeCCStore         *mock.ChaincodeStore
+		fakeParser          *mock.PackageParser
+		fakeLegacyLifecycle *mock.LegacyLifecycle
+		fakeQueryExecutor   *mock.QueryExecutor
+		state               MapState
+	)
+
+	BeforeEach(func() {
+		fakeCCStore = &mock.ChaincodeStore{}
+		fakeParser = &mock.PackageParser{}
+		fakeLegacyLifecycle = &mock.LegacyLifecycle{}
+
+		state = MapState{
+			"namespaces/definitions/cc-name": protoMarshal(&lb.StateChaincodeDefinition{
+				Sequence:          3,
+				Version:           "1.0",
+				EndorsementPolicy: []byte("endorsement-policy"),
+			}),
+		}
+		fakeQueryExecutor = &mock.QueryExecutor{}
+		fakeQueryExecutor.GetStateStub = func(namespace, key string) ([]byte, error) {
+			if namespace != "+lifecycle" {
+				return nil, fmt.Errorf("unexpected namespace '%s'", namespace)
+			}
+			return state.GetState(key)
+		}
+
+		l = &lifecycle.Lifecycle{
+			ChaincodeStore:  fakeCCStore,
+			PackageParser:   fakeParser,
+			LegacyLifecycle: fakeLegacyLifecycle,
+		}
+	})
+
+	Describe("ChaincodeDefinition", func() {
+		It("returns the committed definition", func() {
+			cd, err := l.ChaincodeDefinition("cc-name", fakeQueryExecutor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cd.CCName()).To(Equal("cc-name"))
+			Expect(cd.CCVersion()).To(Equal("1.0"))
+			Expect(cd.Hash()).To(BeNil())
+			Expect(cd.Endorsement()).To(Equal("escc"))
+			plugin, policy := cd.Validation()
+			Expect(plugin).To(Equal("vscc"))
+			Expect(policy).To(Equal([]byte("endorsement-policy")))
+
+			Expect(fakeLegacyLifecycle.ChaincodeDefinitionCallCount()).To(Equal(0))
+		})
+
+		Context("when the chaincode has no committed definition", func() {
+			BeforeEach(func() {
+				fakeLegacyLifecycle.ChaincodeDefinitionReturns(&ccprovider.ChaincodeData{Name: "legacy-name"}, nil)
+			})
+
+			It("falls back to the legacy lifecycle", func() {
+				cd, err := l.ChaincodeDefinition("legacy-name", fakeQueryExecutor)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cd).To(Equal(&ccprovider.ChaincodeData{Name: "legacy-name"}))
+
+				Expect(fakeLegacyLifecycle.ChaincodeDefinitionCallCount()).To(Equal(1))
+				name, qe := fakeLegacyLifecycle.ChaincodeDefinitionArgsForCall(0)
+				Expect(name).To(Equal("legacy-name"))
+				Expect(qe).To(Equal(fakeQueryExecutor))
+			})
+		})
+
+		Context("when the state cannot be read", func() {
+			BeforeEach(func() {
+				fakeQueryExecutor.GetStateReturns(nil, fmt.Errorf("state-error"))
+				fakeQueryExecutor.GetStateStub = nil
+			})
+
+			It("returns an error", func() {
+				_, err := l.ChaincodeDefinition("cc-name", fakeQueryExecutor)
+				Expect(err).To(MatchError("could not read definition for chaincode 'cc-name': state-error"))
+			})
+		})
+	})
+
+	Describe("ChaincodeContainerInfo", func() {
+		BeforeEach(func() {
+			fakeCCStore.RetrieveHashReturns([]byte("hash"), nil)
+			fakeCCStore.LoadReturns([]byte("cc-package"), "cc-name", "1.0", nil)
+			fakeParser.ParseReturns(&persistence.ChaincodePackage{
+				Metadata: &persistence.ChaincodePackageMetadata{
+					Type: "golang",
+					Path: "github.com/example/cc",
+				},
+			}, nil)
+		})
+
+		It("returns the container info of the installed chaincode at the committed version", func() {
+			info, err := l.ChaincodeContainerInfo("cc-name", fakeQueryExecutor)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info).To(Equal(&ccprovider.ChaincodeContainerInfo{
+				Name:          "cc-name",
+				Version:       "1.0",
+				Path:          "github.com/example/cc",
+				Type:          "GOLANG",
+				ContainerType: "DOCKER",
+			}))
+
+			name, version := fakeCCStore.RetrieveHashArgsForCall(0)
+			Expect(name).To(Equal("cc-name"))
+			Expect(version).To(Equal("1.0"))
+			Expect(fakeCCStore.LoadArgsForCall(0)).To(Equal([]byte("hash")))
+			Expect(fakeParser.ParseArgsForCall(0)).To(Equal([]byte("cc-package")))
+		})
+
+		Context("when the chaincode has no committed definition", func() {
+			BeforeEach(func() {
+				fakeLegacyLifecycle.ChaincodeContainerInfoReturns(&ccprovider.ChaincodeContainerInfo{Name: "legacy-name"}, nil)
+			})
+
+			It("falls back to the legacy lifecycle", func() {
+				info, err := l.ChaincodeContainerInfo("legacy-name", fakeQueryExecutor)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info).To(Equal(&ccprovider.ChaincodeContainerInfo{Name: "legacy-name"}))
+				Expect(fakeCCStore.RetrieveHashCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the chaincode is not installed", func() {
+			BeforeEach(func() {
+				fakeCCStore.RetrieveHashReturns(nil, fmt.Errorf("not-found"))
+			})
+
+			It("returns an error", func() {
+				_, err := l.ChaincodeContainerInfo("cc-name", fakeQueryExecutor)
+				Expect(err).To(MatchError("chaincode 'cc-name:1.0' is not installed: not-found"))
+			})
+		})
+
+		Context("when the chaincode package cannot be loaded", func() {
+			BeforeEach(func() {
+				fakeCCStore.LoadReturns(nil, "", "", fmt.Errorf("load-error"))
+			})
+
+			It("returns an error", func() {
+				_, err := l.ChaincodeContainerInfo("cc-name", fakeQueryExecutor)
+				Expect(err).To(MatchError("could not load chaincode 'cc-name:1.0': load-error"))
+			})
+		})
+
+		Context("when the chaincode package cannot be parsed", func() {
+			BeforeEach(func() {
+				fakeParser.ParseReturns(nil, fmt.Errorf("parse-error"))
+			})
+
+			It("returns an error", func() {
+				_, err := l.ChaincodeContainerInfo("cc-name", fakeQueryExecutor)
+				Expect(err).To(MatchError("could not parse chaincode package for 'cc-name:1.0': parse-error"))
+			})
+		})
+	})
+})
@@ -9,7 +9,10 @@ package lifecycle
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	cb "github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
 	"github.com/pkg/errors"
@@ -21,6 +24,18 @@ const (
 
 	// QueryInstalledChaincodeFuncName is the chaincode function name used to query an installed chaincode
 	QueryInstalledChaincodeFuncName = "QueryInstalledChaincode"
+
+	// ApproveChaincodeDefinitionForMyOrgFuncName is the chaincode function name used to approve a chaincode definition for the peer's org
+	ApproveChaincodeDefinitionForMyOrgFuncName = "ApproveChaincodeDefinitionForMyOrg"
+
+	// CommitChaincodeDefinitionFuncName is the chaincode function name used to commit a chaincode definition to the channel
+	CommitChaincodeDefinitionFuncName = "CommitChaincodeDefinition"
+
+	// QueryApprovalStatusFuncName is the chaincode function name used to query which orgs have approved a chaincode definition
+	QueryApprovalStatusFuncName = "QueryApprovalStatus"
+
+	// QueryChaincodeDefinitionFuncName is the chaincode function name used to query a committed chaincode definition
+	QueryChaincodeDefinitionFuncName = "QueryChaincodeDefinition"
 )
 
 // SCCFunctions provides a backing implementation with concrete arguments
@@ -31,13 +46,37 @@ type SCCFunctions interface {
 
 	// QueryInstalledChaincode returns the hash for a given name and version of an installed chaincode
 	QueryInstalledChaincode(name, version string) (hash []byte, err error)
+
+	// ApproveChaincodeDefinitionForOrg records the peer's org approval of a chaincode definition
+	ApproveChaincodeDefinitionForOrg(cd *ChaincodeDefinition, publicState ReadWritableState) error
+
+	// CommitChaincodeDefinition commits a chaincode definition to the channel if it has sufficient approvals
+	CommitChaincodeDefinition(channelID string, cd *ChaincodeDefinition, publicState ReadWritableState) (approvals map[string]bool, err error)
+
+	// QueryApprovalStatus returns which orgs of the channel have approved a chaincode definition
+	QueryApprovalStatus(channelID string, cd *ChaincodeDefinition, publicState ReadableState) (approvals map[string]bool, err error)
+
+	// QueryChaincodeDefinition returns the committed definition of a chaincode
+	QueryChaincodeDefinition(name string, publicState ReadableState) (*ChaincodeDefinition, error)
+}
+
+// ACLProvider checks the access control of the SCC functions scoped to a channel.
+type ACLProvider interface {
+	CheckACL(resName string, channelID string, idinfo interface{}) error
+}
+
+// PolicyChecker checks proposals against the policies of the peer's local MSP.
+type PolicyChecker interface {
+	CheckPolicyNoChannel(policyName string, signedProp *pb.SignedProposal) error
 }
 
 // SCC implements the required methods to satisfy the chaincode interface.
 // It routes the invocation calls to the backing implementations.
 type SCC struct {
-	Protobuf  Protobuf
-	Functions SCCFunctions
+	Protobuf      Protobuf
+	Functions     SCCFunctions
+	ACLProvider   ACLProvider
+	PolicyChecker PolicyChecker
 }
 
 // Name returns "+lifecycle"
@@ -96,7 +135,15 @@ func (scc *SCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	funcName := args[0]
 	inputBytes := args[1]
 
-	// TODO add ACLs
+	sp, err := stub.GetSignedProposal()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("failed retrieving signed proposal: %s", err))
+	}
+
+	err = scc.checkACL(string(funcName), stub.GetChannelID(), sp)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("access denied for [%s]: %s", funcName, err))
+	}
 
 	switch string(funcName) {
 	// Each lifecycle SCC function gets a case here
@@ -145,8 +192,152 @@ func (scc *SCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 			return shim.Error(err.Error())
 		}
 
+		return shim.Success(resultBytes)
+	case ApproveChaincodeDefinitionForMyOrgFuncName:
+		input := &lb.ApproveChaincodeDefinitionForMyOrgArgs{}
+		err := scc.Protobuf.Unmarshal(inputBytes, input)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to decode input arg to ApproveChaincodeDefinitionForMyOrg")
+			return shim.Error(err.Error())
+		}
+
+		err = scc.Functions.ApproveChaincodeDefinitionForOrg(definitionFromArgs(input), stub)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to invoke backing ApproveChaincodeDefinitionForOrg")
+			return shim.Error(err.Error())
+		}
+
+		resultBytes, err := scc.Protobuf.Marshal(&lb.ApproveChaincodeDefinitionForMyOrgResult{})
+		if err != nil {
+			err = errors.WithMessage(err, "failed to marshal result")
+			return shim.Error(err.Error())
+		}
+
+		return shim.Success(resultBytes)
+	case CommitChaincodeDefinitionFuncName:
+		input := &lb.CommitChaincodeDefinitionArgs{}
+		err := scc.Protobuf.Unmarshal(inputBytes, input)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to decode input arg to CommitChaincodeDefinition")
+			return shim.Error(err.Error())
+		}
+
+		_, err = scc.Functions.CommitChaincodeDefinition(stub.GetChannelID(), definitionFromArgs(input), stub)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to invoke backing CommitChaincodeDefinition")
+			return shim.Error(err.Error())
+		}
+
+		resultBytes, err := scc.Protobuf.Marshal(&lb.CommitChaincodeDefinitionResult{})
+		if err != nil {
+			err = errors.WithMessage(err, "failed to marshal result")
+			return shim.Error(err.Error())
+		}
+
+		return shim.Success(resultBytes)
+	case QueryApprovalStatusFuncName:
+		input := &lb.QueryApprovalStatusArgs{}
+		err := scc.Protobuf.Unmarshal(inputBytes, input)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to decode input arg to QueryApprovalStatus")
+			return shim.Error(err.Error())
+		}
+
+		approvals, err := scc.Functions.QueryApprovalStatus(stub.GetChannelID(), definitionFromArgs(input), stub)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to invoke backing QueryApprovalStatus")
+			return shim.Error(err.Error())
+		}
+
+		resultBytes, err := scc.Protobuf.Marshal(&lb.QueryApprovalStatusResult{
+			Approved: approvals,
+		})
+		if err != nil {
+			err = errors.WithMessage(err, "failed to marshal result")
+			return shim.Error(err.Error())
+		}
+
+		return shim.Success(resultBytes)
+	case QueryChaincodeDefinitionFuncName:
+		input := &lb.QueryChaincodeDefinitionArgs{}
+		err := scc.Protobuf.Unmarshal(inputBytes, input)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to decode input arg to QueryChaincodeDefinition")
+			return shim.Error(err.Error())
+		}
+
+		cd, err := scc.Functions.QueryChaincodeDefinition(input.Name, stub)
+		if err != nil {
+			err = errors.WithMessage(err, "failed to invoke backing QueryChaincodeDefinition")
+			return shim.Error(err.Error())
+		}
+
+		resultBytes, err := scc.Protobuf.Marshal(&lb.QueryChaincodeDefinitionResult{
+			Sequence:          cd.Sequence,
+			Version:           cd.Version,
+			EndorsementPlugin: cd.EndorsementPlugin,
+			ValidationPlugin:  cd.ValidationPlugin,
+			EndorsementPolicy: cd.EndorsementPolicy,
+			Collections:       cd.Collections,
+		})
+		if err != nil {
+			err = errors.WithMessage(err, "failed to marshal result")
+			return shim.Error(err.Error())
+		}
+
 		return shim.Success(resultBytes)
 	default:
 		return shim.Error(fmt.Sprintf("unknown lifecycle function: %s", funcName))
 	}
 }
+
+// checkACL checks that the signed proposal satisfies the access control of
+// the given function.  Installed chaincodes are peer wide and may only be
+// managed by admins of the peer's org, as may the approval of definitions
+// on behalf of the peer's org.
+func (scc *SCC) checkACL(funcName, channelID string, sp *pb.SignedProposal) error {
+	switch funcName {
+	case InstallChaincodeFuncName, QueryInstalledChaincodeFuncName:
+		return scc.PolicyChecker.CheckPolicyNoChannel(mgmt.Admins, sp)
+	case ApproveChaincodeDefinitionForMyOrgFuncName:
+		err := scc.ACLProvider.CheckACL(resources.Lifecycle_ApproveChaincodeDefinitionForMyOrg, channelID, sp)
+		if err != nil {
+			return err
+		}
+		return scc.PolicyChecker.CheckPolicyNoChannel(mgmt.Admins, sp)
+	case CommitChaincodeDefinitionFuncName:
+		return scc.ACLProvider.CheckACL(resources.Lifecycle_CommitChaincodeDefinition, channelID, sp)
+	case QueryApprovalStatusFuncName:
+		return scc.ACLProvider.CheckACL(resources.Lifecycle_QueryApprovalStatus, channelID, sp)
+	case QueryChaincodeDefinitionFuncName:
+		return scc.ACLProvider.CheckACL(resources.Lifecycle_QueryChaincodeDefinition, channelID, sp)
+	default:
+		return nil
+	}
+}
+
+// definitionArgs is implemented by each of the SCC argument messages
+// which carry a chaincode definition.
+type definitionArgs interface {
+	GetName() string
+	GetSequence() int64
+	GetVersion() string
+	GetEndorsementPlugin() string
+	GetValidationPlugin() string
+	GetEndorsementPolicy() []byte
+	GetCollections() *cb.CollectionConfigPackage
+}
+
+// definitionFromArgs converts the definition carried by SCC arguments
+// into a ChaincodeDefinition.
+func definitionFromArgs(args definitionArgs) *ChaincodeDefinition {
+	return &ChaincodeDefinition{
+		Name:              args.GetName(),
+		Sequence:          args.GetSequence(),
+		Version:           args.GetVersion(),
+		EndorsementPlugin: args.GetEndorsementPlugin(),
+		ValidationPlugin:  args.GetValidationPlugin(),
+		EndorsementPolicy: args.GetEndorsementPolicy(),
+		Collections:       args.GetCollections(),
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -20,17 +22,23 @@ import (
 
 var _ = Describe("SCC", func() {
 	var (
-		scc          *lifecycle.SCC
-		fakeProto    *mock.Protobuf
-		fakeSCCFuncs *mock.SCCFunctions
+		scc               *lifecycle.SCC
+		fakeProto         *mock.Protobuf
+		fakeSCCFuncs      *mock.SCCFunctions
+		fakeACLProvider   *mock.ACLProvider
+		fakePolicyChecker *mock.PolicyChecker
 	)
 
 	BeforeEach(func() {
 		fakeProto = &mock.Protobuf{}
 		fakeSCCFuncs = &mock.SCCFunctions{}
+		fakeACLProvider = &mock.ACLProvider{}
+		fakePolicyChecker = &mock.PolicyChecker{}
 		scc = &lifecycle.SCC{
-			Protobuf:  fakeProto,
-			Functions: fakeSCCFuncs,
+			Protobuf:      fakeProto,
+			Functions:     fakeSCCFuncs,
+			ACLProvider:   fakeACLProvider,
+			PolicyChecker: fakePolicyChecker,
 		}
 	})
 
@@ -84,11 +92,15 @@ var _ = Describe("SCC", func() {
 
 	Describe("Invoke", func() {
 		var (
-			fakeStub *mock.ChaincodeStub
+			fakeStub       *mock.ChaincodeStub
+			signedProposal *pb.SignedProposal
 		)
 
 		BeforeEach(func() {
+			signedProposal = &pb.SignedProposal{ProposalBytes: []byte("proposal")}
 			fakeStub = &mock.ChaincodeStub{}
+			fakeStub.GetSignedProposalReturns(signedProposal, nil)
+			fakeStub.GetChannelIDReturns("channel-id")
 		})
 
 		Context("when no arguments are provided", func() {
@@ -107,6 +119,17 @@ var _ = Describe("SCC", func() {
 			})
 		})
 
+		Context("when the signed proposal cannot be retrieved", func() {
+			BeforeEach(func() {
+				fakeStub.GetArgsReturns([][]byte{[]byte("CommitChaincodeDefinition"), nil})
+				fakeStub.GetSignedProposalReturns(nil, fmt.Errorf("proposal-error"))
+			})
+
+			It("returns an error", func() {
+				Expect(scc.Invoke(fakeStub)).To(Equal(shim.Error("failed retrieving signed proposal: proposal-error")))
+			})
+		})
+
 		Context("when an unknown function is provided as the first argument", func() {
 			BeforeEach(func() {
 				fakeStub.GetArgsReturns([][]byte{[]byte("bad-function"), nil})
@@ -157,6 +180,22 @@ var _ = Describe("SCC", func() {
 				Expect(ccInstallPackage).To(Equal([]byte("chaincode-package")))
 			})
 
+			Context("when the creator is not an admin of the peer's org", func() {
+				BeforeEach(func() {
+					fakePolicyChecker.CheckPolicyNoChannelReturns(fmt.Errorf("not-an-admin"))
+				})
+
+				It("returns an error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("access denied for [InstallChaincode]: not-an-admin"))
+					Expect(fakeSCCFuncs.InstallChaincodeCallCount()).To(Equal(0))
+
+					policyName, _ := fakePolicyChecker.CheckPolicyNoChannelArgsForCall(0)
+					Expect(policyName).To(Equal("Admins"))
+				})
+			})
+
 			Context("when the underlying function implementation fails", func() {
 				BeforeEach(func() {
 					fakeSCCFuncs.InstallChaincodeReturns(nil, fmt.Errorf("underlying-error"))
@@ -268,5 +307,370 @@ var _ = Describe("SCC", func() {
 				})
 			})
 		})
+
+		Describe("ApproveChaincodeDefinitionForMyOrg", func() {
+			var (
+				arg          *lb.ApproveChaincodeDefinitionForMyOrgArgs
+				marshaledArg []byte
+			)
+
+			BeforeEach(func() {
+				arg = &lb.ApproveChaincodeDefinitionForMyOrgArgs{
+					Sequence:          7,
+					Name:              "name",
+					Version:           "version",
+					EndorsementPlugin: "escc",
+					ValidationPlugin:  "vscc",
+					EndorsementPolicy: []byte("endorsement-policy"),
+					Collections:       &cb.CollectionConfigPackage{},
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetArgsReturns([][]byte{[]byte("ApproveChaincodeDefinitionForMyOrg"), marshaledArg})
+
+				fakeProto.UnmarshalStub = proto.Unmarshal
+				fakeProto.MarshalStub = proto.Marshal
+			})
+
+			It("passes the arguments to and returns the results from the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.ApproveChaincodeDefinitionForMyOrgResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSCCFuncs.ApproveChaincodeDefinitionForOrgCallCount()).To(Equal(1))
+				cd, state := fakeSCCFuncs.ApproveChaincodeDefinitionForOrgArgsForCall(0)
+				Expect(cd).To(Equal(&lifecycle.ChaincodeDefinition{
+					Name:              "name",
+					Sequence:          7,
+					Version:           "version",
+					EndorsementPlugin: "escc",
+					ValidationPlugin:  "vscc",
+					EndorsementPolicy: []byte("endorsement-policy"),
+					Collections:       &cb.CollectionConfigPackage{},
+				}))
+				Expect(state).To(Equal(fakeStub))
+			})
+
+			It("checks the ACL of the channel and that the creator is an admin of the peer's org", func() {
+				scc.Invoke(fakeStub)
+				Expect(fakeACLProvider.CheckACLCallCount()).To(Equal(1))
+				resName, channelID, idinfo := fakeACLProvider.CheckACLArgsForCall(0)
+				Expect(resName).To(Equal("+lifecycle/ApproveChaincodeDefinitionForMyOrg"))
+				Expect(channelID).To(Equal("channel-id"))
+				Expect(idinfo).To(Equal(signedProposal))
+
+				Expect(fakePolicyChecker.CheckPolicyNoChannelCallCount()).To(Equal(1))
+				policyName, sp := fakePolicyChecker.CheckPolicyNoChannelArgsForCall(0)
+				Expect(policyName).To(Equal("Admins"))
+				Expect(sp).To(Equal(signedProposal))
+			})
+
+			Context("when the ACL check fails", func() {
+				BeforeEach(func() {
+					fakeACLProvider.CheckACLReturns(fmt.Errorf("acl-error"))
+				})
+
+				It("returns an error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("access denied for [ApproveChaincodeDefinitionForMyOrg]: acl-error"))
+					Expect(fakeSCCFuncs.ApproveChaincodeDefinitionForOrgCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the creator is not an admin of the peer's org", func() {
+				BeforeEach(func() {
+					fakePolicyChecker.CheckPolicyNoChannelReturns(fmt.Errorf("not-an-admin"))
+				})
+
+				It("returns an error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("access denied for [ApproveChaincodeDefinitionForMyOrg]: not-an-admin"))
+					Expect(fakeSCCFuncs.ApproveChaincodeDefinitionForOrgCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.ApproveChaincodeDefinitionForOrgReturns(fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing ApproveChaincodeDefinitionForOrg: underlying-error"))
+				})
+			})
+
+			Context("when unmarshaling the input fails", func() {
+				BeforeEach(func() {
+					fakeProto.UnmarshalReturns(fmt.Errorf("unmarshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to decode input arg to ApproveChaincodeDefinitionForMyOrg: unmarshal-error"))
+				})
+			})
+
+			Context("when marshaling the output fails", func() {
+				BeforeEach(func() {
+					fakeProto.MarshalReturns(nil, fmt.Errorf("marshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to marshal result: marshal-error"))
+				})
+			})
+		})
+
+		Describe("CommitChaincodeDefinition", func() {
+			var (
+				arg          *lb.CommitChaincodeDefinitionArgs
+				marshaledArg []byte
+			)
+
+			BeforeEach(func() {
+				arg = &lb.CommitChaincodeDefinitionArgs{
+					Sequence: 7,
+					Name:     "name",
+					Version:  "version",
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetArgsReturns([][]byte{[]byte("CommitChaincodeDefinition"), marshaledArg})
+				fakeStub.GetChannelIDReturns("channel-id")
+
+				fakeProto.UnmarshalStub = proto.Unmarshal
+				fakeProto.MarshalStub = proto.Marshal
+
+				fakeSCCFuncs.CommitChaincodeDefinitionReturns(map[string]bool{"org1": true}, nil)
+			})
+
+			It("passes the arguments to and returns the results from the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.CommitChaincodeDefinitionResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSCCFuncs.CommitChaincodeDefinitionCallCount()).To(Equal(1))
+				channelID, cd, state := fakeSCCFuncs.CommitChaincodeDefinitionArgsForCall(0)
+				Expect(channelID).To(Equal("channel-id"))
+				Expect(cd).To(Equal(&lifecycle.ChaincodeDefinition{
+					Name:     "name",
+					Sequence: 7,
+					Version:  "version",
+				}))
+				Expect(state).To(Equal(fakeStub))
+			})
+
+			Context("when the ACL check fails", func() {
+				BeforeEach(func() {
+					fakeACLProvider.CheckACLReturns(fmt.Errorf("acl-error"))
+				})
+
+				It("returns an error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("access denied for [CommitChaincodeDefinition]: acl-error"))
+					Expect(fakeSCCFuncs.CommitChaincodeDefinitionCallCount()).To(Equal(0))
+
+					resName, channelID, _ := fakeACLProvider.CheckACLArgsForCall(0)
+					Expect(resName).To(Equal("+lifecycle/CommitChaincodeDefinition"))
+					Expect(channelID).To(Equal("channel-id"))
+				})
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.CommitChaincodeDefinitionReturns(nil, fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing CommitChaincodeDefinition: underlying-error"))
+				})
+			})
+
+			Context("when unmarshaling the input fails", func() {
+				BeforeEach(func() {
+					fakeProto.UnmarshalReturns(fmt.Errorf("unmarshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to decode input arg to CommitChaincodeDefinition: unmarshal-error"))
+				})
+			})
+
+			Context("when marshaling the output fails", func() {
+				BeforeEach(func() {
+					fakeProto.MarshalReturns(nil, fmt.Errorf("marshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to marshal result: marshal-error"))
+				})
+			})
+		})
+
+		Describe("QueryApprovalStatus", func() {
+			var (
+				arg          *lb.QueryApprovalStatusArgs
+				marshaledArg []byte
+			)
+
+			BeforeEach(func() {
+				arg = &lb.QueryApprovalStatusArgs{
+					Sequence: 7,
+					Name:     "name",
+					Version:  "version",
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetArgsReturns([][]byte{[]byte("QueryApprovalStatus"), marshaledArg})
+				fakeStub.GetChannelIDReturns("channel-id")
+
+				fakeProto.UnmarshalStub = proto.Unmarshal
+				fakeProto.MarshalStub = proto.Marshal
+
+				fakeSCCFuncs.QueryApprovalStatusReturns(map[string]bool{"org1": true, "org2": false}, nil)
+			})
+
+			It("passes the arguments to and returns the results from the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.QueryApprovalStatusResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(payload.Approved).To(Equal(map[string]bool{"org1": true, "org2": false}))
+
+				Expect(fakeSCCFuncs.QueryApprovalStatusCallCount()).To(Equal(1))
+				channelID, cd, state := fakeSCCFuncs.QueryApprovalStatusArgsForCall(0)
+				Expect(channelID).To(Equal("channel-id"))
+				Expect(cd.Name).To(Equal("name"))
+				Expect(cd.Sequence).To(Equal(int64(7)))
+				Expect(state).To(Equal(fakeStub))
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.QueryApprovalStatusReturns(nil, fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing QueryApprovalStatus: underlying-error"))
+				})
+			})
+
+			Context("when unmarshaling the input fails", func() {
+				BeforeEach(func() {
+					fakeProto.UnmarshalReturns(fmt.Errorf("unmarshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to decode input arg to QueryApprovalStatus: unmarshal-error"))
+				})
+			})
+		})
+
+		Describe("QueryChaincodeDefinition", func() {
+			var (
+				arg          *lb.QueryChaincodeDefinitionArgs
+				marshaledArg []byte
+			)
+
+			BeforeEach(func() {
+				arg = &lb.QueryChaincodeDefinitionArgs{
+					Name: "name",
+				}
+
+				var err error
+				marshaledArg, err = proto.Marshal(arg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fakeStub.GetArgsReturns([][]byte{[]byte("QueryChaincodeDefinition"), marshaledArg})
+
+				fakeProto.UnmarshalStub = proto.Unmarshal
+				fakeProto.MarshalStub = proto.Marshal
+
+				fakeSCCFuncs.QueryChaincodeDefinitionReturns(&lifecycle.ChaincodeDefinition{
+					Name:              "name",
+					Sequence:          3,
+					Version:           "version",
+					EndorsementPlugin: "escc",
+					ValidationPlugin:  "vscc",
+					EndorsementPolicy: []byte("endorsement-policy"),
+				}, nil)
+			})
+
+			It("passes the arguments to and returns the results from the backing scc function implementation", func() {
+				res := scc.Invoke(fakeStub)
+				Expect(res.Status).To(Equal(int32(200)))
+				payload := &lb.QueryChaincodeDefinitionResult{}
+				err := proto.Unmarshal(res.Payload, payload)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(proto.Equal(payload, &lb.QueryChaincodeDefinitionResult{
+					Sequence:          3,
+					Version:           "version",
+					EndorsementPlugin: "escc",
+					ValidationPlugin:  "vscc",
+					EndorsementPolicy: []byte("endorsement-policy"),
+				})).To(BeTrue())
+
+				Expect(fakeSCCFuncs.QueryChaincodeDefinitionCallCount()).To(Equal(1))
+				name, state := fakeSCCFuncs.QueryChaincodeDefinitionArgsForCall(0)
+				Expect(name).To(Equal("name"))
+				Expect(state).To(Equal(fakeStub))
+			})
+
+			Context("when the underlying function implementation fails", func() {
+				BeforeEach(func() {
+					fakeSCCFuncs.QueryChaincodeDefinitionReturns(nil, fmt.Errorf("underlying-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to invoke backing QueryChaincodeDefinition: underlying-error"))
+				})
+			})
+
+			Context("when unmarshaling the input fails", func() {
+				BeforeEach(func() {
+					fakeProto.UnmarshalReturns(fmt.Errorf("unmarshal-error"))
+				})
+
+				It("wraps and returns the error", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(500)))
+					Expect(res.Message).To(Equal("failed to decode input arg to QueryChaincodeDefinition: unmarshal-error"))
+				})
+			})
+		})
 	})
 })
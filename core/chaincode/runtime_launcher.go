@@ -10,7 +10,10 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/hyperledger/fabric/core/chaincode/extcc"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
 	"github.com/hyperledger/fabric/core/container/inproccontroller"
 	"github.com/pkg/errors"
 )
@@ -26,18 +29,35 @@ type PackageProvider interface {
 	GetChaincodeCodePackage(ccname string, ccversion string) ([]byte, error)
 }
 
+// ExternalBuilder detects the chaincodes which are built and run by the
+// external builders rather than in Docker containers, and tells how to connect
+// to those running as external services.
+type ExternalBuilder interface {
+	Detect(ccid ccintf.CCID, ccType, path string, codePackage []byte) (bool, error)
+	ChaincodeServerInfo(ccid ccintf.CCID, ccType, path string, codePackage []byte) (*ccintf.ChaincodeServerInfo, error)
+}
+
+// ConnectionHandler connects to the chaincodes running as external services.
+type ConnectionHandler interface {
+	Stream(ccid string, ccinfo *ccintf.ChaincodeServerInfo, sHandler extcc.StreamHandler) error
+}
+
 // RuntimeLauncher is responsible for launching chaincode runtimes.
 type RuntimeLauncher struct {
-	Runtime         Runtime
-	Registry        LaunchRegistry
-	PackageProvider PackageProvider
-	StartupTimeout  time.Duration
-	Metrics         *LaunchMetrics
+	Runtime           Runtime
+	Registry          LaunchRegistry
+	PackageProvider   PackageProvider
+	ExternalBuilder   ExternalBuilder
+	ConnectionHandler ConnectionHandler
+	StreamHandler     extcc.StreamHandler
+	StartupTimeout    time.Duration
+	Metrics           *LaunchMetrics
 }
 
 func (r *RuntimeLauncher) Launch(ccci *ccprovider.ChaincodeContainerInfo) error {
 	var startFailCh chan error
 	var timeoutCh <-chan time.Time
+	var runtimeCCCICh chan *ccprovider.ChaincodeContainerInfo
 
 	startTime := time.Now()
 	cname := ccci.Name + ":" + ccci.Version
@@ -45,18 +65,43 @@ func (r *RuntimeLauncher) Launch(ccci *ccprovider.ChaincodeContainerInfo) error
 	if !alreadyStarted {
 		startFailCh = make(chan error, 1)
 		timeoutCh = time.NewTimer(r.StartupTimeout).C
+		runtimeCCCICh = make(chan *ccprovider.ChaincodeContainerInfo, 1)
 
-		codePackage, err := r.getCodePackage(ccci)
-		if err != nil {
-			return err
-		}
-
+		// The chaincode is built, if need be, in the background like it is
+		// started, so that the build is bounded by the startup timeout and a
+		// failure is reported to the chaincodes waiting for the launch
 		go func() {
-			if err := r.Runtime.Start(ccci, codePackage); err != nil {
+			codePackage, err := r.getCodePackage(ccci)
+			if err != nil {
+				startFailCh <- err
+				return
+			}
+			runtimeCCCI, err := r.externalContainerInfo(ccci, codePackage)
+			if err != nil {
+				startFailCh <- err
+				return
+			}
+			runtimeCCCICh <- runtimeCCCI
+
+			ccservinfo, err := r.chaincodeServerInfo(runtimeCCCI, codePackage)
+			if err != nil {
+				startFailCh <- err
+				return
+			}
+			if ccservinfo != nil {
+				if err := r.ConnectionHandler.Stream(cname, ccservinfo, r.StreamHandler); err != nil {
+					launchState.Notify(errors.WithMessage(err, "connection to chaincode server failed"))
+					return
+				}
+				launchState.Notify(errors.Errorf("connection to chaincode server of %s terminated", cname))
+				return
+			}
+
+			if err := r.Runtime.Start(runtimeCCCI, codePackage); err != nil {
 				startFailCh <- errors.WithMessage(err, "error starting container")
 				return
 			}
-			exitCode, err := r.Runtime.Wait(ccci)
+			exitCode, err := r.Runtime.Wait(runtimeCCCI)
 			if err != nil {
 				launchState.Notify(errors.Wrap(err, "failed to wait on container exit"))
 			}
@@ -82,6 +127,11 @@ func (r *RuntimeLauncher) Launch(ccci *ccprovider.ChaincodeContainerInfo) error
 		success = false
 		chaincodeLogger.Debugf("stopping due to error while launching: %+v", err)
 		defer r.Registry.Deregister(cname)
+		// stop the chaincode in the runtime it was started in
+		select {
+		case ccci = <-runtimeCCCICh:
+		default:
+		}
 		if err := r.Runtime.Stop(ccci); err != nil {
 			chaincodeLogger.Debugf("stop failed: %+v", err)
 		}
@@ -108,3 +158,40 @@ func (r *RuntimeLauncher) getCodePackage(ccci *ccprovider.ChaincodeContainerInfo
 
 	return codePackage, nil
 }
+
+// externalContainerInfo returns the container info of the chaincode to be built
+// and run by an external builder if one detects it, and the given container info
+// otherwise.
+func (r *RuntimeLauncher) externalContainerInfo(ccci *ccprovider.ChaincodeContainerInfo, codePackage []byte) (*ccprovider.ChaincodeContainerInfo, error) {
+	if r.ExternalBuilder == nil || ccci.ContainerType == inproccontroller.ContainerType {
+		return ccci, nil
+	}
+
+	ccid := ccintf.CCID{Name: ccci.Name, Version: ccci.Version}
+	detected, err := r.ExternalBuilder.Detect(ccid, ccci.Type, ccci.Path, codePackage)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to detect external builder")
+	}
+	if !detected {
+		return ccci, nil
+	}
+
+	externalCCCI := *ccci
+	externalCCCI.ContainerType = externalbuilder.ContainerType
+	return &externalCCCI, nil
+}
+
+// chaincodeServerInfo returns how to connect to the chaincode if it runs as an
+// external service, or nil if it is to be started.
+func (r *RuntimeLauncher) chaincodeServerInfo(ccci *ccprovider.ChaincodeContainerInfo, codePackage []byte) (*ccintf.ChaincodeServerInfo, error) {
+	if ccci.ContainerType != externalbuilder.ContainerType {
+		return nil, nil
+	}
+
+	ccid := ccintf.CCID{Name: ccci.Name, Version: ccci.Version}
+	ccservinfo, err := r.ExternalBuilder.ChaincodeServerInfo(ccid, ccci.Type, ccci.Path, codePackage)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get chaincode server info")
+	}
+	return ccservinfo, nil
+}
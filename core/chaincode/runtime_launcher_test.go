@@ -11,9 +11,13 @@ import (
 
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/chaincode/extcc"
 	"github.com/hyperledger/fabric/core/chaincode/fake"
 	"github.com/hyperledger/fabric/core/chaincode/mock"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
+	"github.com/hyperledger/fabric/core/container/inproccontroller"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
@@ -341,4 +345,178 @@ var _ = Describe("RuntimeLauncher", func() {
 			Expect(fakeRuntime.StopCallCount()).To(Equal(1))
 		})
 	})
+
+	Context("when external builders are configured", func() {
+		var fakeExternalBuilder *mock.ExternalBuilder
+
+		BeforeEach(func() {
+			fakeExternalBuilder = &mock.ExternalBuilder{}
+			fakeExternalBuilder.DetectReturns(true, nil)
+			runtimeLauncher.ExternalBuilder = fakeExternalBuilder
+		})
+
+		It("asks them to detect the chaincode", func() {
+			err := runtimeLauncher.Launch(ccci)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeExternalBuilder.DetectCallCount()).To(Equal(1))
+			ccid, ccType, path, codePackage := fakeExternalBuilder.DetectArgsForCall(0)
+			Expect(ccid).To(Equal(ccintf.CCID{Name: "chaincode-name", Version: "chaincode-version"}))
+			Expect(ccType).To(Equal("chaincode-type"))
+			Expect(path).To(Equal("chaincode-path"))
+			Expect(codePackage).To(Equal([]byte("code-package")))
+		})
+
+		It("starts the chaincode with the external builders", func() {
+			err := runtimeLauncher.Launch(ccci)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeRuntime.StartCallCount()).To(Equal(1))
+			ccciArg, _ := fakeRuntime.StartArgsForCall(0)
+			Expect(ccciArg.ContainerType).To(Equal(externalbuilder.ContainerType))
+			Expect(ccciArg.Name).To(Equal("chaincode-name"))
+			Expect(ccci.ContainerType).To(Equal("chaincode-container-type"))
+		})
+
+		Context("when the chaincode runs as an external service", func() {
+			var (
+				fakeConnectionHandler *mock.ConnectionHandler
+				fakeStreamHandler     *chaincode.ChaincodeSupport
+				ccservinfo            *ccintf.ChaincodeServerInfo
+			)
+
+			BeforeEach(func() {
+				ccservinfo = &ccintf.ChaincodeServerInfo{Address: "chaincode-address:9999"}
+				fakeExternalBuilder.ChaincodeServerInfoReturns(ccservinfo, nil)
+
+				fakeConnectionHandler = &mock.ConnectionHandler{}
+				fakeConnectionHandler.StreamStub = func(string, *ccintf.ChaincodeServerInfo, extcc.StreamHandler) error {
+					launchState.Notify(nil)
+					return nil
+				}
+				fakeStreamHandler = &chaincode.ChaincodeSupport{}
+				runtimeLauncher.ConnectionHandler = fakeConnectionHandler
+				runtimeLauncher.StreamHandler = fakeStreamHandler
+			})
+
+			It("connects to the chaincode server instead of starting the chaincode", func() {
+				err := runtimeLauncher.Launch(ccci)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeExternalBuilder.ChaincodeServerInfoCallCount()).To(Equal(1))
+				ccid, ccType, path, codePackage := fakeExternalBuilder.ChaincodeServerInfoArgsForCall(0)
+				Expect(ccid).To(Equal(ccintf.CCID{Name: "chaincode-name", Version: "chaincode-version"}))
+				Expect(ccType).To(Equal("chaincode-type"))
+				Expect(path).To(Equal("chaincode-path"))
+				Expect(codePackage).To(Equal([]byte("code-package")))
+
+				Expect(fakeConnectionHandler.StreamCallCount()).To(Equal(1))
+				cname, ccservinfoArg, streamHandler := fakeConnectionHandler.StreamArgsForCall(0)
+				Expect(cname).To(Equal("chaincode-name:chaincode-version"))
+				Expect(ccservinfoArg).To(Equal(ccservinfo))
+				Expect(streamHandler).To(Equal(fakeStreamHandler))
+				Expect(fakeRuntime.StartCallCount()).To(Equal(0))
+			})
+
+			Context("when the connection fails", func() {
+				BeforeEach(func() {
+					fakeConnectionHandler.StreamStub = nil
+					fakeConnectionHandler.StreamReturns(errors.New("banana"))
+				})
+
+				It("returns an error", func() {
+					err := runtimeLauncher.Launch(ccci)
+					Expect(err).To(MatchError("chaincode registration failed: connection to chaincode server failed: banana"))
+				})
+			})
+
+			Context("when the connection terminates before the chaincode registers", func() {
+				BeforeEach(func() {
+					fakeConnectionHandler.StreamStub = nil
+				})
+
+				It("returns an error", func() {
+					err := runtimeLauncher.Launch(ccci)
+					Expect(err).To(MatchError("chaincode registration failed: connection to chaincode server of chaincode-name:chaincode-version terminated"))
+				})
+			})
+
+			Context("when getting the chaincode server info fails", func() {
+				BeforeEach(func() {
+					fakeExternalBuilder.ChaincodeServerInfoReturns(nil, errors.New("kiwi"))
+				})
+
+				It("returns an error", func() {
+					err := runtimeLauncher.Launch(ccci)
+					Expect(err).To(MatchError("failed to get chaincode server info: kiwi"))
+					Expect(fakeConnectionHandler.StreamCallCount()).To(Equal(0))
+					Expect(fakeRuntime.StartCallCount()).To(Equal(0))
+				})
+
+				It("does not leave the next launches waiting", func() {
+					runtimeLauncher.Registry = chaincode.NewHandlerRegistry(false)
+
+					errCh := make(chan error, 2)
+					go func() {
+						errCh <- runtimeLauncher.Launch(ccci)
+						errCh <- runtimeLauncher.Launch(ccci)
+					}()
+					Eventually(errCh).Should(Receive(MatchError("failed to get chaincode server info: kiwi")))
+					Eventually(errCh).Should(Receive(MatchError("failed to get chaincode server info: kiwi")))
+					Expect(fakeExternalBuilder.ChaincodeServerInfoCallCount()).To(Equal(2))
+				})
+			})
+		})
+
+		Context("when no external builder detects the chaincode", func() {
+			BeforeEach(func() {
+				fakeExternalBuilder.DetectReturns(false, nil)
+			})
+
+			It("starts the chaincode in its container type", func() {
+				err := runtimeLauncher.Launch(ccci)
+				Expect(err).NotTo(HaveOccurred())
+
+				ccciArg, _ := fakeRuntime.StartArgsForCall(0)
+				Expect(ccciArg).To(Equal(ccci))
+			})
+		})
+
+		Context("when the detection fails", func() {
+			BeforeEach(func() {
+				fakeExternalBuilder.DetectReturns(false, errors.New("mango"))
+			})
+
+			It("returns an error", func() {
+				err := runtimeLauncher.Launch(ccci)
+				Expect(err).To(MatchError("failed to detect external builder: mango"))
+				Expect(fakeRuntime.StartCallCount()).To(Equal(0))
+			})
+
+			It("does not leave the next launches waiting", func() {
+				runtimeLauncher.Registry = chaincode.NewHandlerRegistry(false)
+
+				errCh := make(chan error, 2)
+				go func() {
+					errCh <- runtimeLauncher.Launch(ccci)
+					errCh <- runtimeLauncher.Launch(ccci)
+				}()
+				Eventually(errCh).Should(Receive(MatchError("failed to detect external builder: mango")))
+				Eventually(errCh).Should(Receive(MatchError("failed to detect external builder: mango")))
+				Expect(fakeExternalBuilder.DetectCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the chaincode is a system chaincode", func() {
+			BeforeEach(func() {
+				ccci.ContainerType = inproccontroller.ContainerType
+			})
+
+			It("does not ask the external builders", func() {
+				err := runtimeLauncher.Launch(ccci)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(fakeExternalBuilder.DetectCallCount()).To(Equal(0))
+			})
+		})
+	})
 })
@@ -321,7 +321,9 @@ func newPeerClientConnection() (*grpc.ClientConn, error) {
 func chatWithPeer(chaincodename string, stream PeerChaincodeStream, cc Chaincode) error {
 	// Create the shim handler responsible for all control logic
 	handler := newChaincodeHandler(stream, cc)
-	defer stream.CloseSend()
+	if cs, ok := stream.(ClientStream); ok {
+		defer cs.CloseSend()
+	}
 
 	// Send the ChaincodeID during register.
 	chaincodeID := &pb.ChaincodeID{Name: chaincodename}
@@ -631,11 +633,17 @@ type HistoryQueryIterator struct {
 	*CommonIterator
 }
 
+// PrivateDataHistoryQueryIterator documentation can be found in interfaces.go
+type PrivateDataHistoryQueryIterator struct {
+	*CommonIterator
+}
+
 type resultType uint8
 
 const (
 	STATE_QUERY_RESULT resultType = iota + 1
 	HISTORY_QUERY_RESULT
+	PRIVATE_DATA_HISTORY_QUERY_RESULT
 )
 
 func createQueryResponseMetadata(metadataBytes []byte) (*pb.QueryResponseMetadata, error) {
@@ -700,13 +708,54 @@ func (stub *ChaincodeStub) GetStateByRange(startKey, endKey string) (StateQueryI
 
 // GetHistoryForKey documentation can be found in interfaces.go
 func (stub *ChaincodeStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
-	response, err := stub.handler.handleGetHistoryForKey(key, stub.ChannelId, stub.TxID)
+	response, err := stub.handler.handleGetHistoryForKey("", key, nil, stub.ChannelId, stub.TxID)
 	if err != nil {
 		return nil, err
 	}
 	return &HistoryQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}, nil
 }
 
+// GetHistoryForKeyWithPagination documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetHistoryForKeyWithPagination(key string, options *HistoryQueryOptions, pageSize int32,
+	bookmark string) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+
+	metadata := &pb.HistoryQueryMetadata{PageSize: pageSize, Bookmark: bookmark}
+	if options != nil {
+		metadata.StartBlock = options.StartBlock
+		metadata.EndBlock = options.EndBlock
+		metadata.StartTime = options.StartTime
+		metadata.EndTime = options.EndTime
+		metadata.NewestFirst = options.NewestFirst
+	}
+	metadataBytes, err := proto.Marshal(metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	response, err := stub.handler.handleGetHistoryForKey("", key, metadataBytes, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, nil, err
+	}
+	iterator := &HistoryQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}
+	responseMetadata, err := createQueryResponseMetadata(response.Metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	return iterator, responseMetadata, nil
+}
+
+// GetPrivateDataHistoryForKey documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetPrivateDataHistoryForKey(collection, key string) (PrivateDataHistoryQueryIteratorInterface, error) {
+	if collection == "" {
+		return nil, fmt.Errorf("collection must not be an empty string")
+	}
+	response, err := stub.handler.handleGetHistoryForKey(collection, key, nil, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateDataHistoryQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}, nil
+}
+
 //CreateCompositeKey documentation can be found in interfaces.go
 func (stub *ChaincodeStub) CreateCompositeKey(objectType string, attributes []string) (string, error) {
 	return createCompositeKey(objectType, attributes)
@@ -862,6 +911,14 @@ func (iter *HistoryQueryIterator) Next() (*queryresult.KeyModification, error) {
 	}
 }
 
+func (iter *PrivateDataHistoryQueryIterator) Next() (*queryresult.PrivateDataKeyModification, error) {
+	if result, err := iter.nextResult(PRIVATE_DATA_HISTORY_QUERY_RESULT); err == nil {
+		return result.(*queryresult.PrivateDataKeyModification), err
+	} else {
+		return nil, err
+	}
+}
+
 // HasNext documentation can be found in interfaces.go
 func (iter *CommonIterator) HasNext() bool {
 	if iter.currentLoc < len(iter.response.Results) || iter.response.HasMore {
@@ -870,9 +927,9 @@ func (iter *CommonIterator) HasNext() bool {
 	return false
 }
 
-// getResultsFromBytes deserializes QueryResult and return either a KV struct
-// or KeyModification depending on the result type (i.e., state (range/execute)
-// query, history query). Note that commonledger.QueryResult is an empty golang
+// getResultsFromBytes deserializes QueryResult and return either a KV struct,
+// a KeyModification or a PrivateDataKeyModification depending on the result type
+// (i.e., state (range/execute) query, history query, private data history query). Note that commonledger.QueryResult is an empty golang
 // interface that can hold values of any type.
 func (iter *CommonIterator) getResultFromBytes(queryResultBytes *pb.QueryResultBytes,
 	rType resultType) (commonledger.QueryResult, error) {
@@ -890,6 +947,13 @@ func (iter *CommonIterator) getResultFromBytes(queryResultBytes *pb.QueryResultB
 			return nil, err
 		}
 		return historyQueryResult, nil
+
+	} else if rType == PRIVATE_DATA_HISTORY_QUERY_RESULT {
+		pvtDataHistoryQueryResult := &queryresult.PrivateDataKeyModification{}
+		if err := proto.Unmarshal(queryResultBytes.ResultBytes, pvtDataHistoryQueryResult); err != nil {
+			return nil, err
+		}
+		return pvtDataHistoryQueryResult, nil
 	}
 	return nil, errors.New("wrong result type")
 }
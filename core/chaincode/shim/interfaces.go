@@ -232,6 +232,22 @@ type ChaincodeStubInterface interface {
 	// update ledger, and should limit use to read-only chaincode operations.
 	GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error)
 
+	// GetHistoryForKeyWithPagination returns a history of key values across
+	// time, like GetHistoryForKey, restricted to the blocks and the time range
+	// of the options, and from the newest update if options.NewestFirst is set.
+	// Nil options return the whole history from the oldest update.
+	// When an empty string is passed as a value to the bookmark argument, the
+	// returned iterator can be used to fetch the first `pageSize` historic key
+	// updates. When the bookmark is a non-empty string, the iterator can be
+	// used to fetch the first `pageSize` historic key updates from the bookmark.
+	// A `pageSize` of zero does not limit the number of historic key updates.
+	// Note that only the bookmark present in a prior page of query results
+	// (ResponseMetadata) can be used as a value to the bookmark argument.
+	// Otherwise, an empty string must be passed as bookmark.
+	// The same restrictions as GetHistoryForKey apply.
+	GetHistoryForKeyWithPagination(key string, options *HistoryQueryOptions, pageSize int32,
+		bookmark string) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetPrivateData returns the value of the specified `key` from the specified
 	// `collection`. Note that GetPrivateData doesn't read data from the
 	// private writeset, which has not been committed to the `collection`. In
@@ -303,6 +319,18 @@ type ChaincodeStubInterface interface {
 	// ledger, and should limit use to read-only chaincode operations.
 	GetPrivateDataQueryResult(collection, query string) (StateQueryIteratorInterface, error)
 
+	// GetPrivateDataHistoryForKey returns a history of the hashes of the values
+	// of the specified `key` of the specified `collection` across time. For each
+	// historic key update, the hash of the historic value, the associated
+	// transaction id and timestamp and the delete indicator are returned. The
+	// historic value itself is returned only if the peer still holds the private
+	// data of the transaction, i.e. if the peer is a member of the collection
+	// and the private data has not been purged.
+	// The history is indexed from the blocks committed after the peer is upgraded
+	// or its history database is rebuilt. The same restrictions as
+	// GetHistoryForKey apply.
+	GetPrivateDataHistoryForKey(collection, key string) (PrivateDataHistoryQueryIteratorInterface, error)
+
 	// GetCreator returns `SignatureHeader.Creator` (e.g. an identity)
 	// of the `SignedProposal`. This is the identity of the agent (or user)
 	// submitting the transaction.
@@ -365,6 +393,21 @@ type StateQueryIteratorInterface interface {
 	Next() (*queryresult.KV, error)
 }
 
+// HistoryQueryOptions restricts the history of a key returned by
+// GetHistoryForKeyWithPagination to the updates of the blocks from StartBlock
+// to EndBlock, and with a timestamp from StartTime to EndTime, all bounds
+// included. An EndBlock of zero and nil times do not restrict the history.
+// The time bounds are compared with the timestamps provided by the clients
+// in the proposal headers. The history is returned from the newest update
+// if NewestFirst is set.
+type HistoryQueryOptions struct {
+	StartBlock  uint64
+	EndBlock    uint64
+	StartTime   *timestamp.Timestamp
+	EndTime     *timestamp.Timestamp
+	NewestFirst bool
+}
+
 // HistoryQueryIteratorInterface allows a chaincode to iterate over a set of
 // key/value pairs returned by a history query.
 type HistoryQueryIteratorInterface interface {
@@ -375,6 +418,16 @@ type HistoryQueryIteratorInterface interface {
 	Next() (*queryresult.KeyModification, error)
 }
 
+// PrivateDataHistoryQueryIteratorInterface allows a chaincode to iterate over
+// the hashes of the values returned by a private data history query.
+type PrivateDataHistoryQueryIteratorInterface interface {
+	// Inherit HasNext() and Close()
+	CommonIteratorInterface
+
+	// Next returns the next key update in the private data history query iterator.
+	Next() (*queryresult.PrivateDataKeyModification, error)
+}
+
 // MockQueryIteratorInterface allows a chaincode to iterate over a set of
 // key/value pairs returned by range query.
 // TODO: Once the execute query and history query are implemented in MockStub,
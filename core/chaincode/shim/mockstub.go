@@ -196,6 +196,10 @@ func (stub *MockStub) GetPrivateDataQueryResult(collection, query string) (State
 	return nil, errors.New("Not Implemented")
 }
 
+func (stub *MockStub) GetPrivateDataHistoryForKey(collection, key string) (PrivateDataHistoryQueryIteratorInterface, error) {
+	return nil, errors.New("not implemented")
+}
+
 // GetState retrieves the value for a given key from the ledger
 func (stub *MockStub) GetState(key string) ([]byte, error) {
 	value := stub.State[key]
@@ -292,6 +296,13 @@ func (stub *MockStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterfac
 	return nil, errors.New("not implemented")
 }
 
+// GetHistoryForKeyWithPagination function can be invoked by a chaincode to return a page of
+// the history of key values across time. It is intended to be used for read-only queries.
+func (stub *MockStub) GetHistoryForKeyWithPagination(key string, options *HistoryQueryOptions, pageSize int32,
+	bookmark string) (HistoryQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	return nil, nil, errors.New("not implemented")
+}
+
 //GetStateByPartialCompositeKey function can be invoked by a chaincode to query the
 //state based on a given partial composite key. This function returns an
 //iterator which can be used to iterate over all composite keys whose prefix
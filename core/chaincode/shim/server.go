@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/core/comm"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// TLSProperties are the TLS properties of a ChaincodeServer.
+type TLSProperties struct {
+	// Disabled disables TLS, which should only be done in development.
+	Disabled bool
+	// Key and Cert are the PEM encoded key pair of the server.
+	Key  []byte
+	Cert []byte
+	// ClientCACerts are the PEM encoded certificate authorities the client
+	// certificates of the peers must be issued by. Client authentication is
+	// not required if it is empty.
+	ClientCACerts []byte
+}
+
+// ChaincodeServer runs a chaincode as a long-running service which the peers
+// connect to, instead of the chaincode connecting to the peer as with Start.
+type ChaincodeServer struct {
+	// CCID is the ID the chaincode registers with, i.e. name:version.
+	CCID string
+	// Address is the address the server listens on.
+	Address string
+	// CC is the chaincode served.
+	CC Chaincode
+	// TLSProps are the TLS properties of the server.
+	TLSProps TLSProperties
+	// KaOpts are the keepalive options of the server, the default ones being
+	// used if not set.
+	KaOpts *comm.KeepaliveOptions
+}
+
+// Connect is called by the peers to establish their stream with the chaincode.
+func (cs *ChaincodeServer) Connect(stream pb.Chaincode_ConnectServer) error {
+	return chatWithPeer(cs.CCID, stream, cs.CC)
+}
+
+// Start starts the server and serves the chaincode until the server fails.
+func (cs *ChaincodeServer) Start() error {
+	// If Start() is called, we assume this is a standalone chaincode and set
+	// up formatted logging.
+	SetupChaincodeLogging()
+
+	err := factory.InitFactories(factory.GetDefaultOpts())
+	if err != nil {
+		return errors.WithMessage(err, "internal error, BCCSP could not be initialized with default options")
+	}
+
+	server, err := cs.newServer()
+	if err != nil {
+		return err
+	}
+	return server.Start()
+}
+
+func (cs *ChaincodeServer) newServer() (*comm.GRPCServer, error) {
+	if cs.CCID == "" {
+		return nil, errors.New("ccid must be specified")
+	}
+	if cs.Address == "" {
+		return nil, errors.New("address must be specified")
+	}
+	if cs.CC == nil {
+		return nil, errors.New("chaincode must be specified")
+	}
+
+	secOpts := &comm.SecureOptions{}
+	if !cs.TLSProps.Disabled {
+		if len(cs.TLSProps.Key) == 0 {
+			return nil, errors.New("key must be specified when TLS is enabled")
+		}
+		if len(cs.TLSProps.Cert) == 0 {
+			return nil, errors.New("cert must be specified when TLS is enabled")
+		}
+		secOpts.UseTLS = true
+		secOpts.Key = cs.TLSProps.Key
+		secOpts.Certificate = cs.TLSProps.Cert
+		if len(cs.TLSProps.ClientCACerts) != 0 {
+			secOpts.RequireClientCert = true
+			secOpts.ClientRootCAs = [][]byte{cs.TLSProps.ClientCACerts}
+		}
+	}
+
+	server, err := comm.NewGRPCServer(cs.Address, comm.ServerConfig{
+		SecOpts: secOpts,
+		KaOpts:  cs.KaOpts,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create chaincode server")
+	}
+	pb.RegisterChaincodeServer(server.Server(), cs)
+	return server, nil
+}
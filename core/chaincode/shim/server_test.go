@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/core/comm"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChaincodeServerValidation(t *testing.T) {
+	tests := []struct {
+		cs          *ChaincodeServer
+		expectedErr string
+	}{
+		{&ChaincodeServer{Address: "127.0.0.1:0", CC: &shimTestCC{}}, "ccid must be specified"},
+		{&ChaincodeServer{CCID: "mycc:1.0", CC: &shimTestCC{}}, "address must be specified"},
+		{&ChaincodeServer{CCID: "mycc:1.0", Address: "127.0.0.1:0"}, "chaincode must be specified"},
+		{&ChaincodeServer{CCID: "mycc:1.0", Address: "127.0.0.1:0", CC: &shimTestCC{}}, "key must be specified when TLS is enabled"},
+		{&ChaincodeServer{CCID: "mycc:1.0", Address: "127.0.0.1:0", CC: &shimTestCC{}, TLSProps: TLSProperties{Key: []byte("key")}}, "cert must be specified when TLS is enabled"},
+		{&ChaincodeServer{CCID: "mycc:1.0", Address: "127.0.0.1:0", CC: &shimTestCC{}, TLSProps: TLSProperties{Key: []byte("key"), Cert: []byte("cert")}}, "failed to create chaincode server: tls: failed to find any PEM data in certificate input"},
+	}
+	for _, tc := range tests {
+		_, err := tc.cs.newServer()
+		assert.EqualError(t, err, tc.expectedErr)
+	}
+}
+
+func TestChaincodeServer(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	serverKeyPair, err := ca.NewServerCertKeyPair("127.0.0.1")
+	require.NoError(t, err)
+	clientKeyPair, err := ca.NewClientCertKeyPair()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		tlsProps  TLSProperties
+		clientSec *comm.SecureOptions
+	}{
+		{
+			name:     "tls-disabled",
+			tlsProps: TLSProperties{Disabled: true},
+		},
+		{
+			name:      "tls",
+			tlsProps:  TLSProperties{Key: serverKeyPair.Key, Cert: serverKeyPair.Cert},
+			clientSec: &comm.SecureOptions{UseTLS: true, ServerRootCAs: [][]byte{ca.CertBytes()}},
+		},
+		{
+			name:     "mutual-tls",
+			tlsProps: TLSProperties{Key: serverKeyPair.Key, Cert: serverKeyPair.Cert, ClientCACerts: ca.CertBytes()},
+			clientSec: &comm.SecureOptions{
+				UseTLS:            true,
+				RequireClientCert: true,
+				Key:               clientKeyPair.Key,
+				Certificate:       clientKeyPair.Cert,
+				ServerRootCAs:     [][]byte{ca.CertBytes()},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := &ChaincodeServer{
+				CCID:     "mycc:1.0",
+				Address:  "127.0.0.1:0",
+				CC:       &shimTestCC{},
+				TLSProps: tc.tlsProps,
+			}
+			server, err := cs.newServer()
+			require.NoError(t, err)
+			go server.Start()
+			defer server.Stop()
+
+			client, err := comm.NewGRPCClient(comm.ClientConfig{SecOpts: tc.clientSec, Timeout: 3 * time.Second})
+			require.NoError(t, err)
+			conn, err := client.NewConnection(server.Address(), "")
+			require.NoError(t, err)
+			defer conn.Close()
+
+			stream, err := pb.NewChaincodeClient(conn).Connect(context.Background())
+			require.NoError(t, err)
+			msg, err := stream.Recv()
+			require.NoError(t, err)
+			assert.Equal(t, pb.ChaincodeMessage_REGISTER, msg.Type)
+			chaincodeID := &pb.ChaincodeID{}
+			require.NoError(t, proto.Unmarshal(msg.Payload, chaincodeID))
+			assert.Equal(t, "mycc:1.0", chaincodeID.Name)
+			require.NoError(t, stream.CloseSend())
+		})
+	}
+}
@@ -65,6 +65,16 @@ func (m *mockLedger) GetTransactionByID(txID string) (*peer.ProcessedTransaction
 	return args.Get(0).(*peer.ProcessedTransaction), args.Error(1)
 }
 
+func (m *mockLedger) TxIDExists(txID string) (bool, error) {
+	args := m.Called(txID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *mockLedger) GenerateSnapshot() (string, error) {
+	args := m.Called()
+	return args.String(0), args.Error(1)
+}
+
 func (m *mockLedger) GetBlockByHash(blockHash []byte) (*common.Block, error) {
 	args := m.Called(blockHash)
 	return args.Get(0).(*common.Block), args.Error(1)
@@ -0,0 +1,246 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	coreUtil "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// lifecycleTxSignatures holds the signatures of an invocation of the new
+// lifecycle system chaincode against which its writes are validated.
+type lifecycleTxSignatures struct {
+	creator      []*common.SignedData
+	endorsements []*common.SignedData
+}
+
+// validateLifecycleWrites validates the writes of an invocation of the new
+// lifecycle system chaincode against the channel config, rather than relying on
+// the endorsing peers to have enforced it:
+//   - an approval of an org must be endorsed by a member of that org and submitted
+//     by an admin of that org; peers cannot be told apart from the other members
+//     of an org that does not enable NodeOUs
+//   - a definition must be endorsed according to the LifecycleEndorsement policy
+//     of the channel and be approved by the orgs of the channel as its rule requires
+func (v *VsccValidatorImpl) validateLifecycleWrites(chdr *common.ChannelHeader, payload *common.Payload, envBytes []byte, txRWSet *rwsetutil.TxRwSet) (error, peer.TxValidationCode) {
+	var signatures *lifecycleTxSignatures
+
+	for _, ns := range txRWSet.NsRwSets {
+		if !v.txWritesToNamespace(ns) {
+			continue
+		}
+
+		if ns.NameSpace != lifecycle.LifecycleNamespace {
+			return errors.Errorf("%s attempted to write to namespace %s", lifecycle.LifecycleNamespace, ns.NameSpace),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+
+		if len(ns.CollHashedRwSets) != 0 || ns.KvRwSet == nil || len(ns.KvRwSet.MetadataWrites) != 0 {
+			return errors.Errorf("%s attempted to write private data or metadata", lifecycle.LifecycleNamespace),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+
+		if signatures == nil {
+			var err error
+			signatures, err = lifecycleSignatures(payload, envBytes)
+			if err != nil {
+				return err, peer.TxValidationCode_INVALID_OTHER_REASON
+			}
+		}
+
+		for _, write := range ns.KvRwSet.Writes {
+			if write.IsDelete {
+				return errors.Errorf("%s attempted to delete key %s", lifecycle.LifecycleNamespace, write.Key),
+					peer.TxValidationCode_ILLEGAL_WRITESET
+			}
+
+			var err error
+			switch {
+			case strings.HasPrefix(write.Key, lifecycle.ApprovalsPrefix):
+				err = v.validateApprovalWrite(write.Key, signatures)
+			case strings.HasPrefix(write.Key, lifecycle.DefinitionsPrefix):
+				err = v.validateDefinitionWrite(chdr.ChannelId, strings.TrimPrefix(write.Key, lifecycle.DefinitionsPrefix), write.Value, signatures)
+			default:
+				return errors.Errorf("%s attempted to write unknown key %s", lifecycle.LifecycleNamespace, write.Key),
+					peer.TxValidationCode_ILLEGAL_WRITESET
+			}
+			if err != nil {
+				return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+			}
+		}
+	}
+
+	return nil, peer.TxValidationCode_VALID
+}
+
+// validateApprovalWrite ensures that the approval of an org is endorsed by a
+// member of that org and submitted by one of its admins.
+func (v *VsccValidatorImpl) validateApprovalWrite(key string, signatures *lifecycleTxSignatures) error {
+	mspID := key[strings.LastIndex(key, "/")+1:]
+	if mspID == "" {
+		return errors.Errorf("approval key %s has no org", key)
+	}
+
+	err := v.evaluateSignaturePolicy(cauthdsl.SignedByMspMember(mspID), signatures.endorsements)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("approval of org %s is not endorsed by a member of the org", mspID))
+	}
+
+	err = v.evaluateSignaturePolicy(cauthdsl.SignedByMspAdmin(mspID), signatures.creator)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("approval of org %s is not submitted by an admin of the org", mspID))
+	}
+
+	return nil
+}
+
+// validateDefinitionWrite ensures that the definition of a chaincode is endorsed
+// according to the LifecycleEndorsement policy of the channel, immediately follows
+// the committed definition and has been approved by enough orgs of the channel.
+func (v *VsccValidatorImpl) validateDefinitionWrite(channelID, name string, value []byte, signatures *lifecycleTxSignatures) error {
+	endorsementPolicy, ok := v.support.PolicyManager().GetPolicy(lifecycle.LifecycleEndorsementPolicyPath)
+	if !ok {
+		return errors.Errorf("channel %s has no %s policy", channelID, lifecycle.LifecycleEndorsementPolicyName)
+	}
+
+	err := endorsementPolicy.Evaluate(signatures.endorsements)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("definition of chaincode %s does not satisfy the %s policy", name, lifecycle.LifecycleEndorsementPolicyName))
+	}
+
+	definition := &lb.StateChaincodeDefinition{}
+	err = proto.Unmarshal(value, definition)
+	if err != nil {
+		return errors.Wrapf(err, "could not unmarshal definition of chaincode %s", name)
+	}
+
+	approvalPolicy, err := lifecycle.ApprovalPolicyFromChannelConfig(v.support.ConfigtxValidator().ConfigProto())
+	if err != nil {
+		return err
+	}
+
+	l := v.support.Ledger()
+	if l == nil {
+		return errors.New("nil ledger instance")
+	}
+
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return errors.WithMessage(err, "could not retrieve QueryExecutor")
+	}
+	defer qe.Done()
+
+	state := &lifecycleState{qe: qe}
+	committed, err := lifecycle.CommittedDefinition(name, state)
+	if err != nil {
+		return err
+	}
+
+	var committedSequence int64
+	if committed != nil {
+		committedSequence = committed.Sequence
+	}
+	if definition.Sequence != committedSequence+1 {
+		return errors.Errorf("definition of chaincode %s is for sequence %d but the next sequence is %d", name, definition.Sequence, committedSequence+1)
+	}
+
+	approvals, err := lifecycle.DefinitionApprovals(name, definition.Sequence, coreUtil.ComputeSHA256(value), v.support.GetMSPIDs(channelID), state)
+	if err != nil {
+		return err
+	}
+
+	err = approvalPolicy.Evaluate(approvals)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("definition of chaincode %s at sequence %d is not approved", name, definition.Sequence))
+	}
+
+	return nil
+}
+
+// evaluateSignaturePolicy evaluates a signature policy against the MSPs of the channel.
+func (v *VsccValidatorImpl) evaluateSignaturePolicy(envelope *common.SignaturePolicyEnvelope, signedData []*common.SignedData) error {
+	policy, _, err := cauthdsl.NewPolicyProvider(v.support.MSPManager()).NewPolicy(utils.MarshalOrPanic(envelope))
+	if err != nil {
+		return err
+	}
+	return policy.Evaluate(signedData)
+}
+
+// lifecycleSignatures extracts the signature of the creator of a transaction
+// and the signatures of its endorsers.
+func lifecycleSignatures(payload *common.Payload, envBytes []byte) (*lifecycleTxSignatures, error) {
+	env, err := utils.UnmarshalEnvelope(envBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tx.Actions) != 1 {
+		return nil, errors.Errorf("expected exactly one action, got %d", len(tx.Actions))
+	}
+
+	ccActionPayload, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if ccActionPayload.Action == nil {
+		return nil, errors.New("nil chaincode endorsed action")
+	}
+
+	signatures := &lifecycleTxSignatures{
+		creator: []*common.SignedData{{
+			Data:      env.Payload,
+			Identity:  shdr.Creator,
+			Signature: env.Signature,
+		}},
+	}
+	prpBytes := ccActionPayload.Action.ProposalResponsePayload
+	for _, endorsement := range ccActionPayload.Action.Endorsements {
+		data := make([]byte, len(prpBytes)+len(endorsement.Endorser))
+		copy(data, prpBytes)
+		copy(data[len(prpBytes):], endorsement.Endorser)
+
+		signatures.endorsements = append(signatures.endorsements, &common.SignedData{
+			Data:      data,
+			Identity:  endorsement.Endorser,
+			Signature: endorsement.Signature,
+		})
+	}
+
+	return signatures, nil
+}
+
+// lifecycleState reads the committed state of the lifecycle namespace.
+type lifecycleState struct {
+	qe ledger.QueryExecutor
+}
+
+func (s *lifecycleState) GetState(key string) ([]byte, error) {
+	return s.qe.GetState(lifecycle.LifecycleNamespace, key)
+}
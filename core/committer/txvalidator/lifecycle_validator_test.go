@@ -0,0 +1,226 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	ctxt "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/mocks/scc"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	"github.com/hyperledger/fabric/core/committer/txvalidator/mocks"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+func setupLedgerAndLifecycleValidator(t *testing.T, rule common.ImplicitMetaPolicy_Rule, lifecycleEndorsement policies.Policy) (ledger.PeerLedger, txvalidator.Validator) {
+	viper.Set("peer.fileSystemPath", "/tmp/fabric/validatortest")
+	ledgermgmt.InitializeTestEnv()
+	gb, err := ctxt.MakeGenesisBlock("TestLedger")
+	assert.NoError(t, err)
+	theLedger, err := ledgermgmt.CreateLedger(gb)
+	assert.NoError(t, err)
+
+	config := &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				"Application": {
+					Policies: map[string]*common.ConfigPolicy{
+						lifecycle.LifecycleEndorsementPolicyName: {
+							Policy: &common.Policy{
+								Type:  int32(common.Policy_IMPLICIT_META),
+								Value: utils.MarshalOrPanic(&common.ImplicitMetaPolicy{SubPolicy: "Writers", Rule: rule}),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	vcs := struct {
+		*mocktxvalidator.Support
+		*semaphore.Weighted
+	}{&mocktxvalidator.Support{
+		LedgerVal:     theLedger,
+		ACVal:         &mockconfig.MockApplicationCapabilities{},
+		MSPManagerVal: mgmt.GetManagerForChain(util.GetTestChainID()),
+		PolicyManagerVal: &mockpolicies.Manager{
+			PolicyMap: map[string]policies.Policy{
+				lifecycle.LifecycleEndorsementPolicyPath: lifecycleEndorsement,
+			},
+		},
+		ConfigtxValidatorVal: &mockconfigtx.Validator{ConfigProtoVal: config},
+	}, semaphore.NewWeighted(10)}
+
+	mp := &scc.MocksccProviderImpl{
+		SysCCMap: map[string]bool{"lscc": true, "+lifecycle": true},
+	}
+
+	plugin := &mocks.Plugin{}
+	plugin.On("Init", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	plugin.On("Validate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	factory := &mocks.PluginFactory{}
+	factory.On("New").Return(plugin)
+	pm := &mocks.PluginMapper{}
+	pm.On("PluginFactoryByName", txvalidator.PluginName("vscc")).Return(factory)
+
+	return theLedger, txvalidator.NewTxValidator("", vcs, mp, pm)
+}
+
+func lifecycleRWSet(t *testing.T, writes map[string][]byte) []byte {
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	for key, value := range writes {
+		rwsetBuilder.AddToWriteSet("+lifecycle", key, value)
+	}
+	rwset, err := rwsetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+	rwsetBytes, err := rwset.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	return rwsetBytes
+}
+
+func putLifecycleState(theLedger ledger.PeerLedger, key string, value []byte, t *testing.T) {
+	simulator, err := theLedger.NewTxSimulator(util.GenerateUUID())
+	assert.NoError(t, err)
+	simulator.SetState("+lifecycle", key, value)
+	simulator.Done()
+
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	pubSimulationBytes, err := simRes.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	bcInfo, err := theLedger.GetBlockchainInfo()
+	assert.NoError(t, err)
+	block := testutil.ConstructBlock(t, bcInfo.Height, bcInfo.CurrentBlockHash, [][]byte{pubSimulationBytes}, true)
+	err = theLedger.CommitWithPvtData(&ledger.BlockAndPvtData{
+		Block: block,
+	})
+	assert.NoError(t, err)
+}
+
+func validateLifecycleTx(t *testing.T, v txvalidator.Validator, ccID string, rwset []byte) *common.Block {
+	tx := getEnv(ccID, nil, rwset, t)
+	b := &common.Block{
+		Data:   &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}},
+		Header: &common.BlockHeader{},
+	}
+	err := v.Validate(b)
+	assert.NoError(t, err)
+	return b
+}
+
+func TestLifecycleApprovalValidation(t *testing.T) {
+	l, v := setupLedgerAndLifecycleValidator(t, common.ImplicitMetaPolicy_MAJORITY, &mockpolicies.Policy{})
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	t.Run("ApprovalOfOwnOrg", func(t *testing.T) {
+		b := validateLifecycleTx(t, v, "+lifecycle", lifecycleRWSet(t, map[string][]byte{
+			lifecycle.ApprovalKey("mycc", 1, "SampleOrg"): []byte("hash"),
+		}))
+		assertValid(b, t)
+	})
+
+	t.Run("ApprovalOfAnotherOrg", func(t *testing.T) {
+		b := validateLifecycleTx(t, v, "+lifecycle", lifecycleRWSet(t, map[string][]byte{
+			lifecycle.ApprovalKey("mycc", 1, "OtherOrg"): []byte("hash"),
+		}))
+		assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+	})
+
+	t.Run("UnknownKey", func(t *testing.T) {
+		b := validateLifecycleTx(t, v, "+lifecycle", lifecycleRWSet(t, map[string][]byte{
+			"some/other/key": []byte("value"),
+		}))
+		assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+	})
+
+	t.Run("WriteByApplicationChaincode", func(t *testing.T) {
+		putCCInfo(l, "mycc", signedByAnyMember([]string{"SampleOrg"}), t)
+		b := validateLifecycleTx(t, v, "mycc", createRWset(t, "mycc", "+lifecycle"))
+		assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+	})
+}
+
+func TestLifecycleDefinitionValidation(t *testing.T) {
+	definition, err := proto.Marshal(&lb.StateChaincodeDefinition{
+		Sequence: 1,
+		Version:  "1.0",
+	})
+	assert.NoError(t, err)
+
+	t.Run("NotApproved", func(t *testing.T) {
+		l, v := setupLedgerAndLifecycleValidator(t, common.ImplicitMetaPolicy_ANY, &mockpolicies.Policy{})
+		defer ledgermgmt.CleanupTestEnv()
+		defer l.Close()
+
+		b := validateLifecycleTx(t, v, "+lifecycle", lifecycleRWSet(t, map[string][]byte{
+			lifecycle.DefinitionKey("mycc"): definition,
+		}))
+		assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+	})
+
+	t.Run("Approved", func(t *testing.T) {
+		l, v := setupLedgerAndLifecycleValidator(t, common.ImplicitMetaPolicy_ANY, &mockpolicies.Policy{})
+		defer ledgermgmt.CleanupTestEnv()
+		defer l.Close()
+
+		putLifecycleState(l, lifecycle.ApprovalKey("mycc", 1, "SampleOrg"), util.ComputeSHA256(definition), t)
+
+		b := validateLifecycleTx(t, v, "+lifecycle", lifecycleRWSet(t, map[string][]byte{
+			lifecycle.DefinitionKey("mycc"): definition,
+		}))
+		assertValid(b, t)
+	})
+
+	t.Run("ApprovedDifferentDefinition", func(t *testing.T) {
+		l, v := setupLedgerAndLifecycleValidator(t, common.ImplicitMetaPolicy_ANY, &mockpolicies.Policy{})
+		defer ledgermgmt.CleanupTestEnv()
+		defer l.Close()
+
+		putLifecycleState(l, lifecycle.ApprovalKey("mycc", 1, "SampleOrg"), []byte("other-hash"), t)
+
+		b := validateLifecycleTx(t, v, "+lifecycle", lifecycleRWSet(t, map[string][]byte{
+			lifecycle.DefinitionKey("mycc"): definition,
+		}))
+		assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+	})
+
+	t.Run("LifecycleEndorsementNotSatisfied", func(t *testing.T) {
+		l, v := setupLedgerAndLifecycleValidator(t, common.ImplicitMetaPolicy_ANY, &mockpolicies.Policy{Err: errors.New("not-endorsed")})
+		defer ledgermgmt.CleanupTestEnv()
+		defer l.Close()
+
+		putLifecycleState(l, lifecycle.ApprovalKey("mycc", 1, "SampleOrg"), util.ComputeSHA256(definition), t)
+
+		b := validateLifecycleTx(t, v, "+lifecycle", lifecycleRWSet(t, map[string][]byte{
+			lifecycle.DefinitionKey("mycc"): definition,
+		}))
+		assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+	})
+}
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// DependsOnPrecedingBlock returns whether the validation of the block depends on the commit of the
+// preceding block, in which case the block cannot be validated while the preceding block is committed.
+// Besides the block itself, the validation reads the channel configuration, the chaincode definitions,
+// the collection configurations and the key-level endorsement policies, and looks up the transaction
+// IDs in the ledger. The preceding block changes them if it holds configuration or other non-endorser
+// transactions, writes to the lifecycle namespaces, writes the metadata of keys or deletes keys, or
+// has transaction IDs in common with the block. A preceding block that cannot be parsed is assumed
+// to change them
+func DependsOnPrecedingBlock(block, preceding *common.Block) bool {
+	txIDs := make(map[string]struct{}, len(preceding.Data.Data))
+	for i, envBytes := range preceding.Data.Data {
+		chdr, txRWSet, err := unmarshalTx(envBytes)
+		if err != nil {
+			logger.Debugf("Block [%d] is validated once block [%d] is committed, as transaction [%d] of the latter could not be parsed: %s",
+				block.Header.Number, preceding.Header.Number, i, err)
+			return true
+		}
+		if txRWSet == nil || changesValidationState(txRWSet) {
+			logger.Debugf("Block [%d] is validated once block [%d] is committed, as transaction [%d] of the latter changes the state read by the validation",
+				block.Header.Number, preceding.Header.Number, i)
+			return true
+		}
+		txIDs[chdr.TxId] = struct{}{}
+	}
+	for _, envBytes := range block.Data.Data {
+		// the transactions which cannot be parsed are invalidated by the validation regardless of the ledger
+		chdr, _, err := unmarshalTx(envBytes)
+		if err != nil {
+			continue
+		}
+		if _, ok := txIDs[chdr.TxId]; ok {
+			logger.Debugf("Block [%d] is validated once block [%d] is committed, as both hold transaction [%s]",
+				block.Header.Number, preceding.Header.Number, chdr.TxId)
+			return true
+		}
+	}
+	return false
+}
+
+// unmarshalTx returns the channel header of the transaction, and its read-write set if it is an
+// endorser transaction
+func unmarshalTx(envBytes []byte) (*common.ChannelHeader, *rwsetutil.TxRwSet, error) {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, nil, err
+	}
+	if payload.Header == nil {
+		return nil, nil, errors.New("missing payload header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return chdr, nil, nil
+	}
+	respPayload, err := utils.GetActionFromEnvelopeMsg(env)
+	if err != nil {
+		return nil, nil, err
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(respPayload.Results); err != nil {
+		return nil, nil, err
+	}
+	return chdr, txRWSet, nil
+}
+
+// changesValidationState returns whether the read-write set changes the chaincode definitions, the
+// collection configurations or the key-level endorsement policies
+func changesValidationState(txRWSet *rwsetutil.TxRwSet) bool {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace == "lscc" || nsRWSet.NameSpace == lifecycle.LifecycleNamespace {
+			return true
+		}
+		if nsRWSet.KvRwSet != nil {
+			if len(nsRWSet.KvRwSet.MetadataWrites) > 0 {
+				return true
+			}
+			for _, w := range nsRWSet.KvRwSet.Writes {
+				if w.IsDelete {
+					return true
+				}
+			}
+		}
+		for _, collRWSet := range nsRWSet.CollHashedRwSets {
+			if collRWSet.HashedRwSet == nil {
+				continue
+			}
+			if len(collRWSet.HashedRwSet.MetadataWrites) > 0 {
+				return true
+			}
+			for _, w := range collRWSet.HashedRwSet.HashedWrites {
+				if w.IsDelete {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
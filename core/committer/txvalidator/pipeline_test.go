@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependsOnPrecedingBlock(t *testing.T) {
+	rwSet := func(build func(b *rwsetutil.RWSetBuilder)) []byte {
+		b := rwsetutil.NewRWSetBuilder()
+		build(b)
+		simRes, err := b.GetTxSimulationResults()
+		require.NoError(t, err)
+		bytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		return bytes
+	}
+	tx := getEnv("mycc", nil, rwSet(func(b *rwsetutil.RWSetBuilder) {
+		b.AddToReadSet("mycc", "key1", nil)
+		b.AddToWriteSet("mycc", "key2", []byte("value"))
+	}), t)
+	block := testutil.NewBlock([]*common.Envelope{tx}, 2, nil)
+
+	tests := []struct {
+		name      string
+		preceding *common.Block
+		depends   bool
+	}{
+		{
+			name: "writes",
+			preceding: testutil.NewBlock([]*common.Envelope{getEnv("mycc", nil, rwSet(func(b *rwsetutil.RWSetBuilder) {
+				b.AddToWriteSet("mycc", "key1", []byte("value"))
+				b.AddToPvtAndHashedWriteSet("mycc", "coll", "key2", []byte("value"))
+			}), t)}, 1, nil),
+		},
+		{
+			name: "lscc",
+			preceding: testutil.NewBlock([]*common.Envelope{getEnv("lscc", nil, rwSet(func(b *rwsetutil.RWSetBuilder) {
+				b.AddToWriteSet("lscc", "mycc", []byte("definition"))
+			}), t)}, 1, nil),
+			depends: true,
+		},
+		{
+			name: "lifecycle",
+			preceding: testutil.NewBlock([]*common.Envelope{getEnv("_lifecycle", nil, rwSet(func(b *rwsetutil.RWSetBuilder) {
+				b.AddToWriteSet(lifecycle.LifecycleNamespace, "mycc", []byte("definition"))
+			}), t)}, 1, nil),
+			depends: true,
+		},
+		{
+			name: "metadata",
+			preceding: testutil.NewBlock([]*common.Envelope{getEnv("mycc", nil, rwSet(func(b *rwsetutil.RWSetBuilder) {
+				b.AddToMetadataWriteSet("mycc", "key2", map[string][]byte{"VALIDATION_PARAMETER": []byte("policy")})
+			}), t)}, 1, nil),
+			depends: true,
+		},
+		{
+			name: "hashed metadata",
+			preceding: testutil.NewBlock([]*common.Envelope{getEnv("mycc", nil, rwSet(func(b *rwsetutil.RWSetBuilder) {
+				b.AddToHashedMetadataWriteSet("mycc", "coll", "key2", map[string][]byte{"VALIDATION_PARAMETER": []byte("policy")})
+			}), t)}, 1, nil),
+			depends: true,
+		},
+		{
+			name: "delete",
+			preceding: testutil.NewBlock([]*common.Envelope{getEnv("mycc", nil, rwSet(func(b *rwsetutil.RWSetBuilder) {
+				b.AddToWriteSet("mycc", "key2", nil)
+			}), t)}, 1, nil),
+			depends: true,
+		},
+		{
+			name:      "config",
+			preceding: testutil.NewBlock([]*common.Envelope{getEnvWithType("mycc", nil, nil, common.HeaderType_CONFIG, t)}, 1, nil),
+			depends:   true,
+		},
+		{
+			name:      "same transaction",
+			preceding: testutil.NewBlock([]*common.Envelope{tx}, 1, nil),
+			depends:   true,
+		},
+		{
+			name: "malformed",
+			preceding: &common.Block{
+				Header: &common.BlockHeader{Number: 1},
+				Data:   &common.BlockData{Data: [][]byte{[]byte("garbage")}},
+			},
+			depends: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.depends, txvalidator.DependsOnPrecedingBlock(block, test.preceding))
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/common/configtx"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/chaincode/platforms/golang"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
@@ -53,6 +54,13 @@ type Support interface {
 
 	// Capabilities defines the capabilities for the application portion of this channel
 	Capabilities() channelconfig.ApplicationCapabilities
+
+	// PolicyManager returns the policy manager of the channel
+	PolicyManager() policies.Manager
+
+	// ConfigtxValidator returns the config validator of the channel,
+	// which holds the current channel config
+	ConfigtxValidator() configtx.Validator
 }
 
 //Validator interface which defines API to validate block transactions
@@ -479,22 +487,11 @@ func (v *TxValidator) checkTxIdDupsLedger(tIdx int, chdr *common.ChannelHeader,
 	// Retrieve the transaction identifier of the input header
 	txID := chdr.TxId
 
-	// Look for a transaction with the same identifier inside the ledger
-	_, err := ldgr.GetTransactionByID(txID)
-
-	// if returned error is nil, it means that there is already a tx in
-	// the ledger with the supplied id
-	if err == nil {
-		logger.Error("Duplicate transaction found, ", txID, ", skipping")
-		return &blockValidationResult{
-			tIdx:           tIdx,
-			validationCode: peer.TxValidationCode_DUPLICATE_TXID,
-		}
-	}
-
-	// if returned error is not of type blkstorage.NotFoundInIndexErr, it means
-	// we could not verify whether a tx with the supplied id is in the ledger
-	if _, isNotFoundInIndexErrType := err.(ledger.NotFoundInIndexErr); !isNotFoundInIndexErrType {
+	// Look for a transaction with the same identifier inside the ledger. The ids of the
+	// transactions preceding the snapshot that the ledger may have been bootstrapped from
+	// are checked as well, even though these transactions cannot be retrieved
+	exists, err := ldgr.TxIDExists(txID)
+	if err != nil {
 		logger.Errorf("Ledger failure while attempting to detect duplicate status for "+
 			"txid %s, err '%s'. Aborting", txID, err)
 		return &blockValidationResult{
@@ -503,6 +500,14 @@ func (v *TxValidator) checkTxIdDupsLedger(tIdx int, chdr *common.ChannelHeader,
 		}
 	}
 
+	if exists {
+		logger.Error("Duplicate transaction found, ", txID, ", skipping")
+		return &blockValidationResult{
+			tIdx:           tIdx,
+			validationCode: peer.TxValidationCode_DUPLICATE_TXID,
+		}
+	}
+
 	// it otherwise means that there is no transaction with the same identifier
 	// residing in the ledger
 	return nil
@@ -1500,7 +1500,7 @@ func TestTokenDuplicateTxId(t *testing.T) {
 	validator := txvalidator.NewTxValidator("", vcs, mp, pm)
 
 	tx := getTokenTx(t)
-	theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, nil)
+	theLedger.On("TxIDExists", mock.Anything).Return(true, nil)
 
 	b := testutil.NewBlock([]*common.Envelope{tx}, 0, nil)
 
@@ -1530,6 +1530,17 @@ func (m *mockLedger) GetTransactionByID(txID string) (*peer.ProcessedTransaction
 	return args.Get(0).(*peer.ProcessedTransaction), args.Error(1)
 }
 
+// TxIDExists returns true if a transaction with the given id exists in the ledger
+func (m *mockLedger) TxIDExists(txID string) (bool, error) {
+	args := m.Called(txID)
+	return args.Bool(0), args.Error(1)
+}
+
+// GenerateSnapshot generates a snapshot of the ledger
+func (m *mockLedger) GenerateSnapshot() (string, error) {
+	return "", nil
+}
+
 // GetBlockByHash returns block using its hash value
 func (m *mockLedger) GetBlockByHash(blockHash []byte) (*common.Block, error) {
 	args := m.Called(blockHash)
@@ -1800,7 +1811,7 @@ func TestLedgerIsNoAvailable(t *testing.T) {
 	ccID := "mycc"
 	tx := getEnv(ccID, nil, createRWset(t, ccID), t)
 
-	theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, ledger.NotFoundInIndexErr(""))
+	theLedger.On("TxIDExists", mock.Anything).Return(false, nil)
 
 	queryExecutor := new(mockQueryExecutor)
 	queryExecutor.On("GetState", mock.Anything, mock.Anything).Return([]byte{}, errors.New("Unable to connect to DB"))
@@ -1833,7 +1844,7 @@ func TestLedgerIsNotAvailableForCheckingTxidDuplicate(t *testing.T) {
 	ccID := "mycc"
 	tx := getEnv(ccID, nil, createRWset(t, ccID), t)
 
-	theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, errors.New("Unable to connect to DB"))
+	theLedger.On("TxIDExists", mock.Anything).Return(false, errors.New("Unable to connect to DB"))
 
 	b := &common.Block{
 		Data:   &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}},
@@ -1860,7 +1871,7 @@ func TestDuplicateTxId(t *testing.T) {
 	ccID := "mycc"
 	tx := getEnv(ccID, nil, createRWset(t, ccID), t)
 
-	theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, nil)
+	theLedger.On("TxIDExists", mock.Anything).Return(true, nil)
 
 	b := &common.Block{
 		Data:   &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}},
@@ -1898,7 +1909,7 @@ func TestValidationInvalidEndorsing(t *testing.T) {
 	ccID := "mycc"
 	tx := getEnv(ccID, nil, createRWset(t, ccID), t)
 
-	theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, ledger.NotFoundInIndexErr(""))
+	theLedger.On("TxIDExists", mock.Anything).Return(false, nil)
 
 	cd := &ccp.ChaincodeData{
 		Name:    ccID,
@@ -1910,6 +1921,7 @@ func TestValidationInvalidEndorsing(t *testing.T) {
 	cdbytes := utils.MarshalOrPanic(cd)
 
 	queryExecutor := new(mockQueryExecutor)
+	queryExecutor.On("GetState", "+lifecycle", "namespaces/definitions/"+ccID).Return([]byte(nil), nil)
 	queryExecutor.On("GetState", "lscc", ccID).Return(cdbytes, nil)
 	theLedger.On("NewQueryExecutor", mock.Anything).Return(queryExecutor, nil)
 
@@ -1927,7 +1939,7 @@ func TestValidationInvalidEndorsing(t *testing.T) {
 
 func createMockLedger(t *testing.T, ccID string) *mockLedger {
 	l := new(mockLedger)
-	l.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, ledger.NotFoundInIndexErr(""))
+	l.On("TxIDExists", mock.Anything).Return(false, nil)
 	cd := &ccp.ChaincodeData{
 		Name:    ccID,
 		Version: ccVersion,
@@ -1937,6 +1949,7 @@ func createMockLedger(t *testing.T, ccID string) *mockLedger {
 
 	cdbytes := utils.MarshalOrPanic(cd)
 	queryExecutor := new(mockQueryExecutor)
+	queryExecutor.On("GetState", "+lifecycle", "namespaces/definitions/"+ccID).Return([]byte(nil), nil)
 	queryExecutor.On("GetState", "lscc", ccID).Return(cdbytes, nil)
 	l.On("NewQueryExecutor", mock.Anything).Return(queryExecutor, nil)
 	return l
@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	coreUtil "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/handlers/validation/api"
@@ -68,6 +69,7 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 	   2) does it write to LSCC's namespace?
 	   3) does it write to any cc that cannot be invoked? */
 	writesToLSCC := false
+	writesToLifecycle := false
 	writesToNonInvokableSCC := false
 	respPayload, err := utils.GetActionFromEnvelope(envBytes)
 	if err != nil {
@@ -139,6 +141,10 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 			writesToLSCC = true
 		}
 
+		if !writesToLifecycle && ns.NameSpace == lifecycle.LifecycleNamespace {
+			writesToLifecycle = true
+		}
+
 		if !writesToNonInvokableSCC && v.sccprovider.IsSysCCAndNotInvokableCC2CC(ns.NameSpace) {
 			writesToNonInvokableSCC = true
 		}
@@ -164,6 +170,12 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 			return errors.Errorf("chaincode %s attempted to write to the namespace of LSCC", ccID),
 				peer.TxValidationCode_ILLEGAL_WRITESET
 		}
+		// the same holds for the namespace of the new lifecycle, whose writes
+		// are only validated when it is invoked directly
+		if writesToLifecycle {
+			return errors.Errorf("chaincode %s attempted to write to the namespace of %s", ccID, lifecycle.LifecycleNamespace),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
 		// 2) we don't write to the namespace of a chaincode that we cannot invoke - if
 		//    the chaincode cannot be invoked in the first place, there's no legitimate
 		//    way in which a transaction has a write set that writes to it; additionally
@@ -224,6 +236,15 @@ func (v *VsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, env
 				peer.TxValidationCode_ILLEGAL_WRITESET
 		}
 
+		// the writes of the new lifecycle are validated against the channel
+		// config in addition to the default validation of system chaincodes
+		if ccID == lifecycle.LifecycleNamespace {
+			if err, code := v.validateLifecycleWrites(chdr, payload, envBytes, txRWSet); err != nil {
+				logger.Errorf("validation of %s writes for txId = %s failed: %+v", ccID, chdr.TxId, err)
+				return err, code
+			}
+		}
+
 		// Get latest chaincode version, vscc and validate policy
 		_, vscc, policy, err := v.GetInfoForValidate(chdr, ccID)
 		if err != nil {
@@ -285,6 +306,24 @@ func (v *VsccValidatorImpl) getCDataForCC(chid, ccid string) (ccprovider.Chainco
 	}
 	defer qe.Done()
 
+	// chaincodes defined through the new lifecycle take precedence over lscc
+	definition, err := lifecycle.CommittedDefinition(ccid, &lifecycleState{qe: qe})
+	if err != nil {
+		return nil, &commonerrors.VSCCInfoLookupFailureError{
+			Reason: fmt.Sprintf("Could not retrieve definition for chaincode %s, error %s", ccid, err),
+		}
+	}
+
+	if definition != nil {
+		if len(definition.EndorsementPolicy) == 0 {
+			definition.EndorsementPolicy, err = utils.Marshal(cauthdsl.SignedByAnyMember(v.support.GetMSPIDs(chid)))
+			if err != nil {
+				return nil, err
+			}
+		}
+		return definition, nil
+	}
+
 	bytes, err := qe.GetState("lscc", ccid)
 	if err != nil {
 		return nil, &commonerrors.VSCCInfoLookupFailureError{
@@ -512,6 +512,9 @@ type TransactionParams struct {
 	HistoryQueryExecutor ledger.HistoryQueryExecutor
 	CollectionStore      privdata.CollectionStore
 	IsInitTransaction    bool
+	// IsQuery is set for the proposals which are only evaluated, and never
+	// submitted as transactions
+	IsQuery bool
 
 	// this is additional data passed to the chaincode
 	ProposalDecorations map[string][]byte
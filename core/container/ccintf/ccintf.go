@@ -13,6 +13,7 @@ package ccintf
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric/core/comm"
 	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -22,6 +23,13 @@ type ChaincodeStream interface {
 	Recv() (*pb.ChaincodeMessage, error)
 }
 
+// ChaincodeServerInfo tells how to connect to a chaincode running as an
+// external service.
+type ChaincodeServerInfo struct {
+	Address      string
+	ClientConfig comm.ClientConfig
+}
+
 // CCSupport must be implemented by the chaincode support side in peer
 // (such as chaincode_support)
 type CCSupport interface {
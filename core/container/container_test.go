@@ -140,9 +140,11 @@ var _ = Describe("Container", func() {
 			})
 
 			Context("the request is for an unknown VM provider type", func() {
-				It("causes the system to halt as this is a serious bug", func() {
-					Expect(func() { vmController.Process("Unknown-Type", nil) }).To(Panic())
+				It("returns an error", func() {
+					err := vmController.Process("Unknown-Type", vmcReq)
+					Expect(err).To(MatchError("unsupported VM type: Unknown-Type"))
 					Expect(vmProvider.NewVMCallCount()).To(Equal(0))
+					Expect(vmcReq.DoCallCount()).To(Equal(0))
 				})
 			})
 		})
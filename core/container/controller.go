@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
 )
 
 type VMProvider interface {
@@ -59,12 +60,14 @@ func NewVMController(vmProviders map[string]VMProvider) *VMController {
 	}
 }
 
-func (vmc *VMController) newVM(typ string) VM {
+func (vmc *VMController) newVM(typ string) (VM, error) {
 	v, ok := vmc.vmProviders[typ]
 	if !ok {
-		vmLogger.Panicf("Programming error: unsupported VM type: %s", typ)
+		// Docker may be disabled, in which case only the chaincodes detected by
+		// the external builders can be started
+		return nil, errors.Errorf("unsupported VM type: %s", typ)
 	}
-	return v.NewVM()
+	return v.NewVM(), nil
 }
 
 func (vmc *VMController) lockContainer(id string) {
@@ -199,7 +202,10 @@ func (w WaitContainerReq) GetCCID() ccintf.CCID {
 }
 
 func (vmc *VMController) Process(vmtype string, req VMCReq) error {
-	v := vmc.newVM(vmtype)
+	v, err := vmc.newVM(vmtype)
+	if err != nil {
+		return err
+	}
 	ccid := req.GetCCID()
 	id := ccid.GetName()
 
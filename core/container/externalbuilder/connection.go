@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/pkg/errors"
+)
+
+// DialTimeout is the timeout for connecting to a chaincode server, unless
+// its connection information states otherwise.
+const DialTimeout = 3 * time.Second
+
+// Duration is a time.Duration which is represented in JSON as a string
+// such as "10s".
+type Duration struct {
+	time.Duration
+}
+
+// MarshalJSON returns the duration as a JSON string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON parses the duration from a JSON string.
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	d.Duration = duration
+	return nil
+}
+
+// ChaincodeServerUserData is the content of the chaincode/server/connection.json
+// file which the release executable of a builder provides for a chaincode
+// running as an external service, telling the peer how to connect to it.
+type ChaincodeServerUserData struct {
+	Address            string   `json:"address"`
+	DialTimeout        Duration `json:"dial_timeout"`
+	TLSRequired        bool     `json:"tls_required"`
+	ClientAuthRequired bool     `json:"client_auth_required"`
+	ClientKey          string   `json:"client_key"`  // PEM encoded client key
+	ClientCert         string   `json:"client_cert"` // PEM encoded client certificate
+	RootCert           string   `json:"root_cert"`   // PEM encoded chaincode server certificate authority
+}
+
+// ChaincodeServerInfo validates the connection information and returns the
+// configuration of the client connecting to the chaincode server.
+func (c *ChaincodeServerUserData) ChaincodeServerInfo() (*ccintf.ChaincodeServerInfo, error) {
+	if c.Address == "" {
+		return nil, errors.New("chaincode address not provided")
+	}
+	connInfo := &ccintf.ChaincodeServerInfo{
+		Address: c.Address,
+		ClientConfig: comm.ClientConfig{
+			KaOpts:  comm.DefaultKeepaliveOptions,
+			Timeout: c.DialTimeout.Duration,
+		},
+	}
+	if connInfo.ClientConfig.Timeout == 0 {
+		connInfo.ClientConfig.Timeout = DialTimeout
+	}
+
+	if !c.TLSRequired {
+		return connInfo, nil
+	}
+	if c.RootCert == "" {
+		return nil, errors.New("chaincode tls root cert not provided")
+	}
+	if c.ClientAuthRequired {
+		if c.ClientKey == "" {
+			return nil, errors.New("chaincode tls key not provided")
+		}
+		if c.ClientCert == "" {
+			return nil, errors.New("chaincode tls cert not provided")
+		}
+	}
+	connInfo.ClientConfig.SecOpts = &comm.SecureOptions{
+		UseTLS:            true,
+		RequireClientCert: c.ClientAuthRequired,
+		Key:               []byte(c.ClientKey),
+		Certificate:       []byte(c.ClientCert),
+		ServerRootCAs:     [][]byte{[]byte(c.RootCert)},
+	}
+	return connInfo, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDurationJSON(t *testing.T) {
+	d := Duration{}
+	require.NoError(t, json.Unmarshal([]byte(`"10s"`), &d))
+	assert.Equal(t, 10*time.Second, d.Duration)
+	marshaled, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, `"10s"`, string(marshaled))
+
+	assert.EqualError(t, json.Unmarshal([]byte(`10`), &d), "json: cannot unmarshal number into Go value of type string")
+	assert.EqualError(t, json.Unmarshal([]byte(`"ten seconds"`), &d), `time: invalid duration "ten seconds"`)
+}
+
+func TestChaincodeServerInfo(t *testing.T) {
+	userData := &ChaincodeServerUserData{Address: "chaincode:9999"}
+	ccservinfo, err := userData.ChaincodeServerInfo()
+	require.NoError(t, err)
+	assert.Equal(t, &ccintf.ChaincodeServerInfo{
+		Address: "chaincode:9999",
+		ClientConfig: comm.ClientConfig{
+			KaOpts:  comm.DefaultKeepaliveOptions,
+			Timeout: DialTimeout,
+		},
+	}, ccservinfo)
+
+	userData = &ChaincodeServerUserData{
+		Address:            "chaincode:9999",
+		DialTimeout:        Duration{10 * time.Second},
+		TLSRequired:        true,
+		ClientAuthRequired: true,
+		ClientKey:          "client-key",
+		ClientCert:         "client-cert",
+		RootCert:           "root-cert",
+	}
+	ccservinfo, err = userData.ChaincodeServerInfo()
+	require.NoError(t, err)
+	assert.Equal(t, &ccintf.ChaincodeServerInfo{
+		Address: "chaincode:9999",
+		ClientConfig: comm.ClientConfig{
+			SecOpts: &comm.SecureOptions{
+				UseTLS:            true,
+				RequireClientCert: true,
+				Key:               []byte("client-key"),
+				Certificate:       []byte("client-cert"),
+				ServerRootCAs:     [][]byte{[]byte("root-cert")},
+			},
+			KaOpts:  comm.DefaultKeepaliveOptions,
+			Timeout: 10 * time.Second,
+		},
+	}, ccservinfo)
+}
+
+func TestChaincodeServerInfoInvalid(t *testing.T) {
+	tests := []struct {
+		userData    *ChaincodeServerUserData
+		expectedErr string
+	}{
+		{&ChaincodeServerUserData{}, "chaincode address not provided"},
+		{&ChaincodeServerUserData{Address: "chaincode:9999", TLSRequired: true}, "chaincode tls root cert not provided"},
+		{&ChaincodeServerUserData{Address: "chaincode:9999", TLSRequired: true, ClientAuthRequired: true, RootCert: "root-cert"}, "chaincode tls key not provided"},
+		{&ChaincodeServerUserData{Address: "chaincode:9999", TLSRequired: true, ClientAuthRequired: true, RootCert: "root-cert", ClientKey: "client-key"}, "chaincode tls cert not provided"},
+	}
+	for _, tc := range tests {
+		_, err := tc.userData.ChaincodeServerInfo()
+		assert.EqualError(t, err, tc.expectedErr)
+	}
+}
@@ -0,0 +1,300 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+// ContainerType is the string which the external builder container type
+// is registered with the container.VMController
+const ContainerType = "EXTERNAL"
+
+var logger = flogging.MustGetLogger("container.externalbuilder")
+
+// DefaultEnvironmentWhitelist is the environment of the peer which is always
+// passed to the executables of the external builders.
+var DefaultEnvironmentWhitelist = []string{"LD_LIBRARY_PATH", "LIBPATH", "PATH", "TMPDIR"}
+
+// Config is the configuration of an external builder, as found in the
+// chaincode.externalBuilders section of core.yaml.
+type Config struct {
+	Name                 string   `mapstructure:"name" yaml:"name"`
+	Path                 string   `mapstructure:"path" yaml:"path"`
+	EnvironmentWhitelist []string `mapstructure:"environmentWhitelist" yaml:"environmentWhitelist"`
+}
+
+// Builder is an external builder, that is a directory whose bin sub-directory
+// holds the executables which build and run a chaincode:
+//   - bin/detect SOURCE METADATA exits with 0 if the builder builds the chaincode
+//   - bin/build SOURCE METADATA BUILD_OUTPUT builds the chaincode
+//   - bin/release BUILD_OUTPUT RELEASE_OUTPUT, which is optional, provides the
+//     metadata of the chaincode, such as its CouchDB indexes
+//   - bin/run BUILD_OUTPUT RUN_METADATA runs the chaincode until it exits, the
+//     RUN_METADATA directory holding the chaincode.json file which tells how the
+//     chaincode connects to the peer
+type Builder struct {
+	Name                 string
+	Location             string
+	EnvironmentWhitelist []string
+	Logger               *flogging.FabricLogger
+}
+
+// CreateBuilders creates the external builders of the given configurations,
+// a builder which is not named being named after its directory.
+func CreateBuilders(configs []Config) []*Builder {
+	var builders []*Builder
+	for _, config := range configs {
+		name := config.Name
+		if name == "" {
+			name = filepath.Base(config.Path)
+		}
+		builders = append(builders, &Builder{
+			Name:                 name,
+			Location:             config.Path,
+			EnvironmentWhitelist: config.EnvironmentWhitelist,
+			Logger:               logger.With("builder", name),
+		})
+	}
+	return builders
+}
+
+// Detect returns whether the builder builds the chaincode of the build context.
+func (b *Builder) Detect(bc *BuildContext) bool {
+	detect := filepath.Join(b.Location, "bin", "detect")
+	if err := b.runCommand(detect, bc.SourceDir, bc.MetadataDir); err != nil {
+		b.Logger.Debugf("Detection for chaincode '%s' failed: %s", bc.CCID, err)
+		return false
+	}
+	return true
+}
+
+// Build builds the chaincode of the build context into its build output
+// directory, and provides its release output if the builder has a release
+// executable.
+func (b *Builder) Build(bc *BuildContext) error {
+	build := filepath.Join(b.Location, "bin", "build")
+	if err := b.runCommand(build, bc.SourceDir, bc.MetadataDir, bc.BldDir); err != nil {
+		return errors.WithMessage(err, "external builder failed to build")
+	}
+
+	release := filepath.Join(b.Location, "bin", "release")
+	if _, err := os.Stat(release); os.IsNotExist(err) {
+		return nil
+	}
+	if err := b.runCommand(release, bc.BldDir, bc.ReleaseDir); err != nil {
+		return errors.WithMessage(err, "external builder failed to release")
+	}
+	return nil
+}
+
+// Run runs the chaincode built into the given build output directory, with the
+// environment and the files it is launched with. The files are written to the
+// run metadata directory, along with the chaincode.json file, and the environment
+// of the run executable refers to them there.
+func (b *Builder) Run(bldDir string, env []string, files map[string][]byte) (*Session, error) {
+	runMetadataDir, err := ioutil.TempDir("", "fabric-run-")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create temporary run metadata directory")
+	}
+	runEnv, err := writeRunMetadata(runMetadataDir, env, files)
+	if err != nil {
+		os.RemoveAll(runMetadataDir)
+		return nil, err
+	}
+
+	run := filepath.Join(b.Location, "bin", "run")
+	cmd := b.newCommand(run, bldDir, runMetadataDir)
+	cmd.Env = append(cmd.Env, runEnv...)
+	sess, err := Start(b.Logger, cmd, func(error) { os.RemoveAll(runMetadataDir) })
+	if err != nil {
+		os.RemoveAll(runMetadataDir)
+		return nil, errors.WithMessage(err, "external builder failed to run")
+	}
+	return sess, nil
+}
+
+// newCommand creates a command which runs with the whitelisted environment of
+// the peer only.
+func (b *Builder) newCommand(name string, args ...string) *exec.Cmd {
+	cmd := exec.Command(name, args...)
+	whitelist := append(append([]string{}, DefaultEnvironmentWhitelist...), b.EnvironmentWhitelist...)
+	for _, key := range whitelist {
+		if value, ok := os.LookupEnv(key); ok {
+			cmd.Env = append(cmd.Env, key+"="+value)
+		}
+	}
+	return cmd
+}
+
+func (b *Builder) runCommand(name string, args ...string) error {
+	sess, err := Start(b.Logger, b.newCommand(name, args...), nil)
+	if err != nil {
+		return err
+	}
+	return sess.Wait()
+}
+
+// RunConfig is the content of the chaincode.json file given to the run
+// executable of a builder.
+type RunConfig struct {
+	CCID        string `json:"chaincode_id"`
+	PeerAddress string `json:"peer_address"`
+	ClientCert  string `json:"client_cert"` // PEM encoded client certificate
+	ClientKey   string `json:"client_key"`  // PEM encoded client key
+	RootCert    string `json:"root_cert"`   // PEM encoded peer certificate authority
+}
+
+// writeRunMetadata writes the files a chaincode is launched with, and the
+// chaincode.json file derived from its environment, to the given run metadata
+// directory. It returns the environment rewritten to refer to the files there.
+func writeRunMetadata(dir string, env []string, files map[string][]byte) ([]string, error) {
+	paths := map[string]string{}
+	for path, content := range files {
+		paths[path] = filepath.Join(dir, filepath.Base(path))
+		if err := ioutil.WriteFile(paths[path], content, 0600); err != nil {
+			return nil, errors.Wrapf(err, "could not write %s", filepath.Base(path))
+		}
+	}
+
+	envMap := map[string]string{}
+	var runEnv []string
+	for _, kv := range env {
+		kvs := strings.SplitN(kv, "=", 2)
+		if len(kvs) != 2 {
+			continue
+		}
+		if path, ok := paths[kvs[1]]; ok {
+			kvs[1] = path
+		}
+		envMap[kvs[0]] = kvs[1]
+		runEnv = append(runEnv, kvs[0]+"="+kvs[1])
+	}
+
+	runConfig := &RunConfig{
+		CCID:        envMap["CORE_CHAINCODE_ID_NAME"],
+		PeerAddress: envMap["CORE_PEER_ADDRESS"],
+	}
+	if envMap["CORE_PEER_TLS_ENABLED"] == "true" {
+		runConfig.ClientCert = readPEM(envMap["CORE_TLS_CLIENT_CERT_PATH"])
+		runConfig.ClientKey = readPEM(envMap["CORE_TLS_CLIENT_KEY_PATH"])
+		runConfig.RootCert = readPEM(envMap["CORE_PEER_TLS_ROOTCERT_FILE"])
+	}
+	runConfigJSON, err := json.Marshal(runConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal run config")
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "chaincode.json"), runConfigJSON, 0600); err != nil {
+		return nil, errors.Wrap(err, "could not write run config")
+	}
+
+	return runEnv, nil
+}
+
+// readPEM reads a PEM file, decoding it if it is base64 encoded, as the client
+// certificate and key files of the chaincodes are.
+func readPEM(path string) string {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(string(content)); err == nil {
+		return string(decoded)
+	}
+	return string(content)
+}
+
+// BuildContext holds the directories given to the executables of a builder
+// to build a chaincode.
+type BuildContext struct {
+	CCID        string
+	ScratchDir  string
+	SourceDir   string
+	MetadataDir string
+	BldDir      string
+	ReleaseDir  string
+}
+
+// buildMetadata is the content of the metadata.json file given to the detect
+// and build executables of a builder.
+type buildMetadata struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+// NewBuildContext creates the directories to build the chaincode of the
+// given type and path under the given directory, extracting its code package
+// into the source directory.
+func NewBuildContext(dir, ccid, ccType, path string, codePackage []byte) (*BuildContext, error) {
+	scratchDir, err := ioutil.TempDir(dir, "fabric-"+sanitizeCCID(ccid))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create temporary build directory")
+	}
+	bc := &BuildContext{
+		CCID:        ccid,
+		ScratchDir:  scratchDir,
+		SourceDir:   filepath.Join(scratchDir, "src"),
+		MetadataDir: filepath.Join(scratchDir, "metadata"),
+		BldDir:      filepath.Join(scratchDir, "bld"),
+		ReleaseDir:  filepath.Join(scratchDir, "release"),
+	}
+	if err := bc.prepare(ccType, path, codePackage); err != nil {
+		bc.Cleanup()
+		return nil, err
+	}
+	return bc, nil
+}
+
+// prepare creates the directories of the build context, extracting the code
+// package into the source directory and writing the build metadata.
+func (bc *BuildContext) prepare(ccType, path string, codePackage []byte) error {
+	for _, d := range []string{bc.SourceDir, bc.MetadataDir, bc.BldDir, bc.ReleaseDir} {
+		if err := os.Mkdir(d, 0700); err != nil {
+			return errors.Wrap(err, "could not create build directory")
+		}
+	}
+
+	if err := Untar(bytes.NewReader(codePackage), bc.SourceDir); err != nil {
+		return errors.WithMessage(err, "could not untar source package")
+	}
+
+	metadataJSON, err := json.Marshal(&buildMetadata{Type: ccType, Path: path})
+	if err != nil {
+		return errors.Wrap(err, "could not marshal build metadata")
+	}
+	if err := ioutil.WriteFile(filepath.Join(bc.MetadataDir, "metadata.json"), metadataJSON, 0600); err != nil {
+		return errors.Wrap(err, "could not write build metadata")
+	}
+	return nil
+}
+
+// Cleanup removes the directories of the build context.
+func (bc *BuildContext) Cleanup() {
+	os.RemoveAll(bc.ScratchDir)
+}
+
+// sanitizeCCID returns a chaincode ID which may be used in a file name.
+func sanitizeCCID(ccid string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '.', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, ccid)
+}
@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// codePackage returns a gzipped tar stream holding the given files.
+func codePackage(t *testing.T, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		require.NoError(t, err)
+		_, err = tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gw.Close())
+	return buf.Bytes()
+}
+
+func TestCreateBuilders(t *testing.T) {
+	builders := CreateBuilders([]Config{
+		{Path: "/path/to/first-builder"},
+		{Name: "second", Path: "/path/to/second-builder", EnvironmentWhitelist: []string{"GOPROXY"}},
+	})
+	require.Len(t, builders, 2)
+	assert.Equal(t, "first-builder", builders[0].Name)
+	assert.Equal(t, "/path/to/first-builder", builders[0].Location)
+	assert.Equal(t, "second", builders[1].Name)
+	assert.Equal(t, "/path/to/second-builder", builders[1].Location)
+	assert.Equal(t, []string{"GOPROXY"}, builders[1].EnvironmentWhitelist)
+}
+
+func TestNewBuildContext(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bc, err := NewBuildContext(dir, "mycc:1.0", "GOLANG", "github.com/mycc", codePackage(t, map[string]string{"src/main.go": "package main"}))
+	require.NoError(t, err)
+	assert.Equal(t, dir, filepath.Dir(bc.ScratchDir))
+	assert.Contains(t, filepath.Base(bc.ScratchDir), "fabric-mycc-1.0")
+
+	content, err := ioutil.ReadFile(filepath.Join(bc.SourceDir, "src", "main.go"))
+	require.NoError(t, err)
+	assert.Equal(t, "package main", string(content))
+	metadata, err := ioutil.ReadFile(filepath.Join(bc.MetadataDir, "metadata.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "GOLANG", "path": "github.com/mycc"}`, string(metadata))
+	assert.DirExists(t, bc.BldDir)
+	assert.DirExists(t, bc.ReleaseDir)
+
+	bc.Cleanup()
+	_, err = os.Stat(bc.ScratchDir)
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = NewBuildContext(dir, "mycc:1.0", "GOLANG", "github.com/mycc", []byte("garbage"))
+	assert.EqualError(t, err, "could not untar source package: could not read code package: unexpected EOF")
+	entries, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestBuilderDetect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	builder := CreateBuilders([]Config{{Path: "testdata/goodbuilder"}})[0]
+
+	bc, err := NewBuildContext(dir, "mycc:1.0", "GOLANG", "github.com/mycc", codePackage(t, nil))
+	require.NoError(t, err)
+	defer bc.Cleanup()
+	assert.True(t, builder.Detect(bc))
+
+	bc, err = NewBuildContext(dir, "mycc:1.0", "NODE", "mycc", codePackage(t, nil))
+	require.NoError(t, err)
+	defer bc.Cleanup()
+	assert.False(t, builder.Detect(bc))
+}
+
+func TestBuilderBuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bc, err := NewBuildContext(dir, "mycc:1.0", "GOLANG", "github.com/mycc", codePackage(t, map[string]string{"src/main.go": "package main"}))
+	require.NoError(t, err)
+	defer bc.Cleanup()
+
+	builder := CreateBuilders([]Config{{Path: "testdata/goodbuilder"}})[0]
+	err = builder.Build(bc)
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(bc.BldDir, "src", "main.go"))
+	assert.FileExists(t, filepath.Join(bc.ReleaseDir, "statedb", "couchdb", "indexes", "index.json"))
+
+	builder = CreateBuilders([]Config{{Path: "testdata/failbuilder"}})[0]
+	err = builder.Build(bc)
+	assert.EqualError(t, err, "external builder failed to build: exit status 1")
+}
+
+func TestWriteRunMetadata(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	env := []string{
+		"CORE_CHAINCODE_ID_NAME=mycc:1.0",
+		"CORE_PEER_ADDRESS=peer0:7052",
+		"CORE_PEER_TLS_ENABLED=true",
+		"CORE_TLS_CLIENT_KEY_PATH=/etc/hyperledger/fabric/client.key",
+		"CORE_TLS_CLIENT_CERT_PATH=/etc/hyperledger/fabric/client.crt",
+		"CORE_PEER_TLS_ROOTCERT_FILE=/etc/hyperledger/fabric/peer.crt",
+		"INVALID",
+	}
+	files := map[string][]byte{
+		"/etc/hyperledger/fabric/client.key": []byte(base64.StdEncoding.EncodeToString([]byte("client-key"))),
+		"/etc/hyperledger/fabric/client.crt": []byte(base64.StdEncoding.EncodeToString([]byte("client-cert"))),
+		"/etc/hyperledger/fabric/peer.crt":   []byte("root-cert"),
+	}
+
+	runEnv, err := writeRunMetadata(dir, env, files)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"CORE_CHAINCODE_ID_NAME=mycc:1.0",
+		"CORE_PEER_ADDRESS=peer0:7052",
+		"CORE_PEER_TLS_ENABLED=true",
+		"CORE_TLS_CLIENT_KEY_PATH=" + filepath.Join(dir, "client.key"),
+		"CORE_TLS_CLIENT_CERT_PATH=" + filepath.Join(dir, "client.crt"),
+		"CORE_PEER_TLS_ROOTCERT_FILE=" + filepath.Join(dir, "peer.crt"),
+	}, runEnv)
+
+	runConfigJSON, err := ioutil.ReadFile(filepath.Join(dir, "chaincode.json"))
+	require.NoError(t, err)
+	runConfig := &RunConfig{}
+	require.NoError(t, json.Unmarshal(runConfigJSON, runConfig))
+	assert.Equal(t, &RunConfig{
+		CCID:        "mycc:1.0",
+		PeerAddress: "peer0:7052",
+		ClientCert:  "client-cert",
+		ClientKey:   "client-key",
+		RootCert:    "root-cert",
+	}, runConfig)
+}
+
+func TestWriteRunMetadataTLSDisabled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = writeRunMetadata(dir, []string{"CORE_CHAINCODE_ID_NAME=mycc:1.0", "CORE_PEER_TLS_ENABLED=false"}, nil)
+	require.NoError(t, err)
+	runConfigJSON, err := ioutil.ReadFile(filepath.Join(dir, "chaincode.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"chaincode_id": "mycc:1.0", "peer_address": "", "client_cert": "", "client_key": "", "root_cert": ""}`, string(runConfigJSON))
+}
+
+func TestSanitizeCCID(t *testing.T) {
+	assert.Equal(t, "my_cc-1.0", sanitizeCCID("my_cc:1.0"))
+	assert.Equal(t, "..-..-etc", sanitizeCCID("../../etc"))
+}
@@ -0,0 +1,274 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/pkg/errors"
+)
+
+// Provider builds and runs the chaincodes with the external builders. It
+// implements container.VMProvider.
+type Provider struct {
+	Builders []*Builder
+	// DurablePath is the directory where the build outputs of the chaincodes
+	// are kept, so that each code package of a chaincode is built once.
+	DurablePath string
+
+	mutex    sync.Mutex
+	sessions map[string]*Session
+}
+
+// NewProvider creates a provider of the external builders of the given
+// configurations, which keeps the build outputs in the given directory.
+func NewProvider(configs []Config, durablePath string) *Provider {
+	return &Provider{
+		Builders:    CreateBuilders(configs),
+		DurablePath: durablePath,
+		sessions:    make(map[string]*Session),
+	}
+}
+
+// NewVM creates an external builder VM instance
+func (p *Provider) NewVM() container.VM {
+	return &VM{provider: p}
+}
+
+// buildInfo is the content of the build-info.json file, written along the
+// build output of a chaincode once it is built.
+type buildInfo struct {
+	BuilderName string `json:"builder_name"`
+}
+
+// Detect returns whether one of the builders builds the chaincode with the
+// given ID, type, path and code package.
+func (p *Provider) Detect(ccid ccintf.CCID, ccType, path string, codePackage []byte) (bool, error) {
+	if p.builtBy(ccid, codePackage) != nil {
+		return true, nil
+	}
+
+	bc, err := p.newBuildContext(ccid, ccType, path, codePackage)
+	if err != nil {
+		return false, err
+	}
+	defer bc.Cleanup()
+	return p.detect(bc) != nil, nil
+}
+
+func (p *Provider) newBuildContext(ccid ccintf.CCID, ccType, path string, codePackage []byte) (*BuildContext, error) {
+	if err := os.MkdirAll(p.DurablePath, 0700); err != nil {
+		return nil, errors.Wrap(err, "could not create external builds directory")
+	}
+	return NewBuildContext(p.DurablePath, ccid.GetName(), ccType, path, codePackage)
+}
+
+func (p *Provider) detect(bc *BuildContext) *Builder {
+	for _, builder := range p.Builders {
+		if builder.Detect(bc) {
+			return builder
+		}
+	}
+	return nil
+}
+
+// durableDir returns the directory of the build output of the chaincode with
+// the given ID and code package. It is named after the hash of the code package
+// as well, so that a chaincode installed again with another code package is
+// built again rather than run from the build output of the previous one.
+func (p *Provider) durableDir(ccid ccintf.CCID, codePackage []byte) string {
+	hash := hex.EncodeToString(util.ComputeSHA256(codePackage))
+	return filepath.Join(p.DurablePath, sanitizeCCID(ccid.GetName())+"-"+hash)
+}
+
+// builtBy returns the builder which the chaincode with the given ID and code
+// package was built by, or nil if it was not built yet or the builder is no
+// longer configured.
+func (p *Provider) builtBy(ccid ccintf.CCID, codePackage []byte) *Builder {
+	buildInfoJSON, err := ioutil.ReadFile(filepath.Join(p.durableDir(ccid, codePackage), "build-info.json"))
+	if err != nil {
+		return nil
+	}
+	bi := &buildInfo{}
+	if err := json.Unmarshal(buildInfoJSON, bi); err != nil {
+		logger.Warningf("Ignoring the build output of chaincode '%s': %s", ccid.GetName(), err)
+		return nil
+	}
+	for _, builder := range p.Builders {
+		if builder.Name == bi.BuilderName {
+			return builder
+		}
+	}
+	return nil
+}
+
+// build builds the chaincode with the given ID and code package, unless it was
+// already built, and returns the builder it was built by along with its build
+// output directory.
+func (p *Provider) build(ccid ccintf.CCID, ccType, path string, codePackage []byte) (*Builder, string, error) {
+	durableDir := p.durableDir(ccid, codePackage)
+	if builder := p.builtBy(ccid, codePackage); builder != nil {
+		return builder, filepath.Join(durableDir, "bld"), nil
+	}
+
+	bc, err := p.newBuildContext(ccid, ccType, path, codePackage)
+	if err != nil {
+		return nil, "", err
+	}
+	defer bc.Cleanup()
+
+	builder := p.detect(bc)
+	if builder == nil {
+		return nil, "", errors.Errorf("no external builder detected chaincode '%s'", ccid.GetName())
+	}
+	if err := builder.Build(bc); err != nil {
+		return nil, "", err
+	}
+
+	// The build-info.json file is written last, once the build output is in place
+	if err := os.RemoveAll(durableDir); err != nil {
+		return nil, "", errors.Wrap(err, "could not remove previous build output")
+	}
+	if err := os.Mkdir(durableDir, 0700); err != nil {
+		return nil, "", errors.Wrap(err, "could not create build output directory")
+	}
+	if err := os.Rename(bc.BldDir, filepath.Join(durableDir, "bld")); err != nil {
+		return nil, "", errors.Wrap(err, "could not move build output")
+	}
+	if err := os.Rename(bc.ReleaseDir, filepath.Join(durableDir, "release")); err != nil {
+		return nil, "", errors.Wrap(err, "could not move release output")
+	}
+	buildInfoJSON, err := json.Marshal(&buildInfo{BuilderName: builder.Name})
+	if err != nil {
+		return nil, "", errors.Wrap(err, "could not marshal build info")
+	}
+	if err := ioutil.WriteFile(filepath.Join(durableDir, "build-info.json"), buildInfoJSON, 0600); err != nil {
+		return nil, "", errors.Wrap(err, "could not write build info")
+	}
+
+	return builder, filepath.Join(durableDir, "bld"), nil
+}
+
+// ChaincodeServerInfo builds the chaincode with the given ID, unless it was
+// already built, and returns how to connect to it if its release output states
+// that it runs as an external service, or nil if it is to be run by its builder.
+func (p *Provider) ChaincodeServerInfo(ccid ccintf.CCID, ccType, path string, codePackage []byte) (*ccintf.ChaincodeServerInfo, error) {
+	if _, _, err := p.build(ccid, ccType, path, codePackage); err != nil {
+		return nil, errors.WithMessage(err, "failed to build chaincode")
+	}
+
+	connectionJSON, err := ioutil.ReadFile(filepath.Join(p.durableDir(ccid, codePackage), "release", "chaincode", "server", "connection.json"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not read chaincode server connection information")
+	}
+	userData := &ChaincodeServerUserData{}
+	if err := json.Unmarshal(connectionJSON, userData); err != nil {
+		return nil, errors.Wrap(err, "malformed chaincode server connection information")
+	}
+	return userData.ChaincodeServerInfo()
+}
+
+func (p *Provider) getSession(name string) *Session {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.sessions[name]
+}
+
+func (p *Provider) setSession(name string, sess *Session) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.sessions[name] = sess
+}
+
+func (p *Provider) removeSession(name string) *Session {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	sess := p.sessions[name]
+	delete(p.sessions, name)
+	return sess
+}
+
+// VM is a vm which runs the chaincodes as processes started by the external
+// builders.
+type VM struct {
+	provider *Provider
+}
+
+// Start builds the chaincode with the external builder which detects it, unless
+// it was already built, and runs it.
+func (vm *VM) Start(ccid ccintf.CCID, args []string, env []string, filesToUpload map[string][]byte, builder container.Builder) error {
+	pb, ok := builder.(*container.PlatformBuilder)
+	if !ok {
+		return errors.Errorf("external builders cannot build chaincode '%s' with %T", ccid.GetName(), builder)
+	}
+
+	name := ccid.GetName()
+	if sess := vm.provider.removeSession(name); sess != nil {
+		logger.Debugf("Stopping the previous process of chaincode '%s'", name)
+		sess.Signal(syscall.SIGKILL)
+		sess.Wait()
+	}
+
+	b, bldDir, err := vm.provider.build(ccid, pb.Type, pb.Path, pb.CodePackage)
+	if err != nil {
+		return errors.WithMessage(err, "failed to build chaincode")
+	}
+	sess, err := b.Run(bldDir, env, filesToUpload)
+	if err != nil {
+		return errors.WithMessage(err, "failed to run chaincode")
+	}
+	vm.provider.setSession(name, sess)
+	return nil
+}
+
+// Stop terminates the process of the chaincode, killing it unless dontkill is
+// set if it has not exited once the timeout, in seconds, has expired.
+func (vm *VM) Stop(ccid ccintf.CCID, timeout uint, dontkill bool, dontremove bool) error {
+	sess := vm.provider.removeSession(ccid.GetName())
+	if sess == nil {
+		return nil
+	}
+
+	sess.Signal(syscall.SIGTERM)
+	if dontkill {
+		return nil
+	}
+	select {
+	case <-sess.Exited():
+	case <-time.After(time.Duration(timeout) * time.Second):
+		sess.Signal(syscall.SIGKILL)
+	}
+	return nil
+}
+
+// Wait blocks until the process of the chaincode exits, and returns its exit code.
+func (vm *VM) Wait(ccid ccintf.CCID) (int, error) {
+	sess := vm.provider.getSession(ccid.GetName())
+	if sess == nil {
+		return 0, errors.Errorf("chaincode '%s' is not running", ccid.GetName())
+	}
+	return sess.ExitCode(), nil
+}
+
+// HealthCheck always succeeds, as the external builders depend on no daemon.
+func (vm *VM) HealthCheck(ctx context.Context) error {
+	return nil
+}
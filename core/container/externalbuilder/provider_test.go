@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderDetect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	provider := NewProvider([]Config{{Path: "testdata/goodbuilder"}}, filepath.Join(dir, "builds"))
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+
+	detected, err := provider.Detect(ccid, "GOLANG", "github.com/mycc", codePackage(t, nil))
+	require.NoError(t, err)
+	assert.True(t, detected)
+	detected, err = provider.Detect(ccid, "NODE", "mycc", codePackage(t, nil))
+	require.NoError(t, err)
+	assert.False(t, detected)
+
+	// Once built, a chaincode is detected without running the builders
+	builder, bldDir, err := provider.build(ccid, "GOLANG", "github.com/mycc", codePackage(t, nil))
+	require.NoError(t, err)
+	assert.Equal(t, "goodbuilder", builder.Name)
+	durableDir := filepath.Join(dir, "builds", "mycc-1.0-"+hex.EncodeToString(util.ComputeSHA256(codePackage(t, nil))))
+	assert.Equal(t, filepath.Join(durableDir, "bld"), bldDir)
+	assert.FileExists(t, filepath.Join(durableDir, "build-info.json"))
+	detected, err = provider.Detect(ccid, "NODE", "mycc", codePackage(t, nil))
+	require.NoError(t, err)
+	assert.True(t, detected)
+
+	// Unless it is installed again with another code package
+	detected, err = provider.Detect(ccid, "NODE", "mycc", codePackage(t, map[string]string{"connection.json": "{}"}))
+	require.NoError(t, err)
+	assert.False(t, detected)
+
+	// Unless the builder it was built by is no longer configured
+	provider = NewProvider([]Config{{Name: "another", Path: "testdata/goodbuilder"}}, filepath.Join(dir, "builds"))
+	detected, err = provider.Detect(ccid, "NODE", "mycc", codePackage(t, nil))
+	require.NoError(t, err)
+	assert.False(t, detected)
+
+	_, err = provider.Detect(ccid, "GOLANG", "github.com/mycc", []byte("garbage"))
+	assert.EqualError(t, err, "could not untar source package: could not read code package: unexpected EOF")
+}
+
+func TestProviderBuildFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	provider := NewProvider([]Config{{Path: "testdata/goodbuilder"}}, dir)
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+	_, _, err = provider.build(ccid, "NODE", "mycc", codePackage(t, nil))
+	assert.EqualError(t, err, "no external builder detected chaincode 'mycc-1.0'")
+
+	provider = NewProvider([]Config{{Path: "testdata/failbuilder"}}, dir)
+	_, _, err = provider.build(ccid, "GOLANG", "github.com/mycc", codePackage(t, nil))
+	assert.EqualError(t, err, "external builder failed to build: exit status 1")
+	durableDirs, err := filepath.Glob(filepath.Join(dir, "mycc-1.0-*"))
+	require.NoError(t, err)
+	assert.Empty(t, durableDirs)
+}
+
+func TestProviderChaincodeServerInfo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	provider := NewProvider([]Config{{Path: "testdata/goodbuilder"}}, dir)
+
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+	ccservinfo, err := provider.ChaincodeServerInfo(ccid, "GOLANG", "github.com/mycc", codePackage(t, nil))
+	require.NoError(t, err)
+	assert.Nil(t, ccservinfo)
+
+	ccid = ccintf.CCID{Name: "myservice", Version: "1.0"}
+	connection := `{"address": "chaincode:9999", "dial_timeout": "10s"}`
+	ccservinfo, err = provider.ChaincodeServerInfo(ccid, "GOLANG", "github.com/myservice", codePackage(t, map[string]string{"connection.json": connection}))
+	require.NoError(t, err)
+	require.NotNil(t, ccservinfo)
+	assert.Equal(t, "chaincode:9999", ccservinfo.Address)
+	assert.Equal(t, 10*time.Second, ccservinfo.ClientConfig.Timeout)
+
+	ccid = ccintf.CCID{Name: "invalid", Version: "1.0"}
+	_, err = provider.ChaincodeServerInfo(ccid, "GOLANG", "github.com/invalid", codePackage(t, map[string]string{"connection.json": `{"dial_timeout": "10s"}`}))
+	assert.EqualError(t, err, "chaincode address not provided")
+
+	ccid = ccintf.CCID{Name: "malformed", Version: "1.0"}
+	_, err = provider.ChaincodeServerInfo(ccid, "GOLANG", "github.com/malformed", codePackage(t, map[string]string{"connection.json": `{`}))
+	assert.EqualError(t, err, "malformed chaincode server connection information: unexpected end of JSON input")
+
+	ccid = ccintf.CCID{Name: "undetected", Version: "1.0"}
+	_, err = provider.ChaincodeServerInfo(ccid, "NODE", "undetected", codePackage(t, nil))
+	assert.EqualError(t, err, "failed to build chaincode: no external builder detected chaincode 'undetected-1.0'")
+}
+
+func TestVM(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	provider := NewProvider([]Config{{Path: "testdata/goodbuilder"}}, filepath.Join(dir, "builds"))
+	vm := provider.NewVM()
+	ccid := ccintf.CCID{Name: "mycc", Version: "1.0"}
+	pb := &container.PlatformBuilder{Type: "GOLANG", Path: "github.com/mycc", CodePackage: codePackage(t, nil)}
+	runOutput := filepath.Join(dir, "chaincode.json")
+	env := []string{"CORE_CHAINCODE_ID_NAME=mycc:1.0", "CORE_PEER_ADDRESS=peer0:7052", "RUN_OUTPUT=" + runOutput}
+
+	_, err = vm.Wait(ccid)
+	assert.EqualError(t, err, "chaincode 'mycc-1.0' is not running")
+
+	err = vm.Start(ccid, nil, append(env, "RUN_EXIT_CODE=3"), nil, pb)
+	require.NoError(t, err)
+	exitCode, err := vm.Wait(ccid)
+	require.NoError(t, err)
+	assert.Equal(t, 3, exitCode)
+
+	runConfigJSON, err := ioutil.ReadFile(runOutput)
+	require.NoError(t, err)
+	runConfig := &RunConfig{}
+	require.NoError(t, json.Unmarshal(runConfigJSON, runConfig))
+	assert.Equal(t, "mycc:1.0", runConfig.CCID)
+	assert.Equal(t, "peer0:7052", runConfig.PeerAddress)
+
+	err = vm.Start(ccid, nil, append(env, "RUN_FOREVER=true"), nil, pb)
+	require.NoError(t, err)
+	err = vm.Stop(ccid, 5, false, false)
+	require.NoError(t, err)
+	_, err = vm.Wait(ccid)
+	assert.EqualError(t, err, "chaincode 'mycc-1.0' is not running")
+
+	err = vm.Start(ccid, nil, env, nil, nil)
+	assert.EqualError(t, err, "external builders cannot build chaincode 'mycc-1.0' with <nil>")
+
+	assert.NoError(t, vm.HealthCheck(nil))
+}
@@ -0,0 +1,99 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"os/exec"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+// ExitFunc is called once the process of a session has exited, with the
+// error it exited with, if any.
+type ExitFunc func(error)
+
+// Session is a process started by an external builder, the standard error of
+// which is logged.
+type Session struct {
+	command *exec.Cmd
+	exited  chan struct{}
+	exitErr error
+}
+
+// Start starts the given command in a new session, calling the given exit
+// function, if not nil, once the process has exited.
+func Start(logger *flogging.FabricLogger, cmd *exec.Cmd, exitFunc ExitFunc) (*Session, error) {
+	logger = logger.With("command", cmd.Path)
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get stderr of command")
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "could not start command %s", cmd.Path)
+	}
+
+	sess := &Session{
+		command: cmd,
+		exited:  make(chan struct{}),
+	}
+	go func() {
+		logLines(logger, stderr)
+		sess.exitErr = cmd.Wait()
+		if exitFunc != nil {
+			exitFunc(sess.exitErr)
+		}
+		close(sess.exited)
+	}()
+
+	return sess, nil
+}
+
+// logLines logs each line read from the given reader until it is exhausted.
+func logLines(logger *flogging.FabricLogger, r io.Reader) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		logger.Info(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Errorf("Error reading the standard error of the command: %s", err)
+	}
+}
+
+// Wait waits for the process of the session to exit, and returns the error it
+// exited with, if any.
+func (s *Session) Wait() error {
+	<-s.exited
+	return s.exitErr
+}
+
+// ExitCode waits for the process of the session to exit, and returns its exit
+// code, which is -1 if it was terminated by a signal.
+func (s *Session) ExitCode() int {
+	<-s.exited
+	return s.command.ProcessState.ExitCode()
+}
+
+// Signal sends the given signal to the process of the session, unless it has
+// exited already.
+func (s *Session) Signal(sig os.Signal) {
+	select {
+	case <-s.exited:
+	default:
+		s.command.Process.Signal(sig)
+	}
+}
+
+// Exited returns a channel which is closed once the process of the session
+// has exited.
+func (s *Session) Exited() <-chan struct{} {
+	return s.exited
+}
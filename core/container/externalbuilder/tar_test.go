@@ -0,0 +1,64 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalbuilder
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUntar(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"src/main.go":                 "package main",
+		"META-INF/statedb/index.json": "{}",
+	}
+	err = Untar(bytes.NewReader(codePackage(t, files)), dir)
+	require.NoError(t, err)
+	for name, content := range files {
+		extracted, err := ioutil.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(extracted))
+	}
+}
+
+func TestUntarInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "externalbuilder-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		header      *tar.Header
+		expectedErr string
+	}{
+		{&tar.Header{Name: "../escape", Typeflag: tar.TypeReg}, "illegal file path in code package: ../escape"},
+		{&tar.Header{Name: "src/../../escape", Typeflag: tar.TypeReg}, "illegal file path in code package: src/../../escape"},
+		{&tar.Header{Name: "link", Linkname: "/etc/passwd", Typeflag: tar.TypeSymlink}, "invalid file type 50 of link in code package"},
+	}
+	for _, tc := range tests {
+		buf := &bytes.Buffer{}
+		gw := gzip.NewWriter(buf)
+		tw := tar.NewWriter(gw)
+		require.NoError(t, tw.WriteHeader(tc.header))
+		require.NoError(t, tw.Close())
+		require.NoError(t, gw.Close())
+
+		err := Untar(buf, dir)
+		assert.EqualError(t, err, tc.expectedErr)
+	}
+}
@@ -54,8 +54,10 @@ type Support interface {
 	// specified ledger
 	GetHistoryQueryExecutor(ledgername string) (ledger.HistoryQueryExecutor, error)
 
-	// GetTransactionByID retrieves a transaction by id
-	GetTransactionByID(chid, txID string) (*pb.ProcessedTransaction, error)
+	// TxIDExists returns true if a transaction with the given id has been
+	// committed to the ledger of the channel, including the transactions
+	// whose blocks have been pruned
+	TxIDExists(chid, txID string) (bool, error)
 
 	// IsSysCC returns true if the name matches a system chaincode's
 	// system chaincode names are system, chain wide
@@ -106,6 +108,9 @@ type Endorser struct {
 	PlatformRegistry      *platforms.Registry
 	PvtRWSetAssembler
 	Metrics *EndorserMetrics
+	// QueryCache caches the responses of the queries, it is nil if the
+	// caching is disabled
+	QueryCache *QueryCache
 }
 
 // validateResult provides the result of endorseProposal verification
@@ -114,6 +119,7 @@ type validateResult struct {
 	hdrExt  *pb.ChaincodeHeaderExtension
 	chainID string
 	txid    string
+	creator []byte
 	resp    *pb.ProposalResponse
 }
 
@@ -256,7 +262,9 @@ func (e *Endorser) SimulateProposal(txParams *ccprovider.TransactionParams, cid
 			return nil, nil, nil, nil, err
 		}
 
-		if simResult.PvtSimulationResults != nil {
+		// the private data written by a query is not distributed, as it is
+		// never committed
+		if simResult.PvtSimulationResults != nil && !txParams.IsQuery {
 			if cid.Name == "lscc" {
 				// TODO: remove once we can store collection configuration outside of LSCC
 				txParams.TXSimulator.Done()
@@ -389,7 +397,12 @@ func (e *Endorser) preProcess(signedProp *pb.SignedProposal) (*validateResult, e
 
 		// Here we handle uniqueness check and ACLs for proposals targeting a chain
 		// Notice that ValidateProposalMessage has already verified that TxID is computed properly
-		if _, err = e.s.GetTransactionByID(chainID, txid); err == nil {
+		txIDExists, err := e.s.TxIDExists(chainID, txid)
+		if err != nil {
+			vr.resp = &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}
+			return vr, err
+		}
+		if txIDExists {
 			// increment failure due to duplicate transactions. Useful for catching replay attacks in
 			// addition to benign retries
 			e.Metrics.DuplicateTxsFailure.With(meterLabels...).Add(1)
@@ -415,7 +428,7 @@ func (e *Endorser) preProcess(signedProp *pb.SignedProposal) (*validateResult, e
 		// MSP of the peer instead by the call to ValidateProposalMessage above
 	}
 
-	vr.prop, vr.hdrExt, vr.chainID, vr.txid = prop, hdrExt, chainID, txid
+	vr.prop, vr.hdrExt, vr.chainID, vr.txid, vr.creator = prop, hdrExt, chainID, txid, shdr.Creator
 	return vr, nil
 }
 
@@ -457,6 +470,36 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 
 	prop, hdrExt, chainID, txid := vr.prop, vr.hdrExt, vr.chainID, vr.txid
 
+	// queries flagged by the client are not endorsed, and their responses
+	// may be served from the query cache. The ledger height must be obtained
+	// before acquiring the tx simulator, as the commit of a block holds the
+	// block storage lock while waiting for the state lock
+	query := chainID != "" && hdrExt.Query
+	var cacheKey string
+	var height uint64
+	if query && e.QueryCache != nil {
+		meterLabels := []string{
+			"channel", chainID,
+			"chaincode", hdrExt.ChaincodeId.Name + ":" + hdrExt.ChaincodeId.Version,
+		}
+		if height, err = e.s.GetLedgerHeight(chainID); err != nil {
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, nil
+		}
+		if cacheKey, err = queryCacheKeyForProposal(vr, height); err != nil {
+			return &pb.ProposalResponse{Response: &pb.Response{Status: 500, Message: err.Error()}}, nil
+		}
+		if cacheKey != "" {
+			if res, ok := e.QueryCache.Get(cacheKey); ok {
+				endorserLogger.Debugf("[%s][%s] query served from the query cache", chainID, shorttxid(txid))
+				e.Metrics.QueryCacheHits.With(meterLabels...).Add(1)
+				e.Metrics.SuccessfulProposals.Add(1)
+				success = true
+				return &pb.ProposalResponse{Version: 1, Response: res}, nil
+			}
+			e.Metrics.QueryCacheMisses.With(meterLabels...).Add(1)
+		}
+	}
+
 	// obtaining once the tx simulator for this proposal. This will be nil
 	// for chainless proposals
 	// Also obtain a history query executor for history queries, since tx simulator does not cover history
@@ -488,6 +531,7 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 		Proposal:             prop,
 		TXSimulator:          txsim,
 		HistoryQueryExecutor: historyQueryExecutor,
+		IsQuery:              query,
 	}
 	// this could be a request to a chainless SysCC
 
@@ -527,6 +571,16 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	// chainless proposals (such as CSCC) don't have to be endorsed
 	if chainID == "" {
 		pResp = &pb.ProposalResponse{Response: res}
+	} else if query {
+		// queries are not submitted as transactions, hence they do not have
+		// to be endorsed either. The tx simulator has been released by now,
+		// and the response is only cached if no block was committed meanwhile
+		pResp = &pb.ProposalResponse{Version: 1}
+		if cacheKey != "" && res != nil && res.Status < shim.ERRORTHRESHOLD {
+			if h, err := e.s.GetLedgerHeight(chainID); err == nil && h == height {
+				e.QueryCache.Put(cacheKey, res)
+			}
+		}
 	} else {
 		// Note: To endorseProposal(), we pass the released txsim. Hence, an error would occur if we try to use this txsim
 		pResp, err = e.endorseProposal(ctx, chainID, txid, signedProp, prop, res, simulationResult, ccevent, hdrExt.PayloadVisibility, hdrExt.ChaincodeId, txsim, cd)
@@ -564,6 +618,28 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	return pResp, nil
 }
 
+// queryCacheKeyForProposal returns the query cache key of the proposal at the
+// given ledger height, or an empty key if the proposal must not be cached as
+// it carries transient data.
+func queryCacheKeyForProposal(vr *validateResult, height uint64) (string, error) {
+	cpp, err := putils.GetChaincodeProposalPayload(vr.prop.Payload)
+	if err != nil {
+		return "", err
+	}
+	if len(cpp.TransientMap) != 0 {
+		return "", nil
+	}
+	cis, err := putils.GetChaincodeInvocationSpec(vr.prop)
+	if err != nil {
+		return "", err
+	}
+	var args [][]byte
+	if cis.ChaincodeSpec != nil && cis.ChaincodeSpec.Input != nil {
+		args = cis.ChaincodeSpec.Input.Args
+	}
+	return queryCacheKey(vr.chainID, vr.hdrExt.ChaincodeId.Name, vr.creator, args, height), nil
+}
+
 // determine whether or not a transaction simulator should be
 // obtained for a proposal.
 func acquireTxSimulator(chainID string, ccid *pb.ChaincodeID) bool {
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -73,6 +74,22 @@ func getSignedPropWithCHIdAndArgs(chid, ccid, ccver string, ccargs [][]byte, t *
 	return &pb.SignedProposal{ProposalBytes: propBytes, Signature: signature}
 }
 
+func getSignedQueryProp(ccid, ccver string, t *testing.T) *pb.SignedProposal {
+	spec := &pb.ChaincodeSpec{Type: 1, ChaincodeId: &pb.ChaincodeID{Name: ccid, Version: ccver}, Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("args")}}}
+
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
+
+	creator, err := signer.Serialize()
+	assert.NoError(t, err)
+	prop, _, err := utils.CreateChaincodeQueryProposalWithTxIDAndTransient(util.GetTestChainID(), cis, creator, "", nil)
+	assert.NoError(t, err)
+	propBytes, err := utils.GetBytesProposal(prop)
+	assert.NoError(t, err)
+	signature, err := signer.Sign(propBytes)
+	assert.NoError(t, err)
+	return &pb.SignedProposal{ProposalBytes: propBytes, Signature: signature}
+}
+
 func newMockTxSim() *mockccprovider.MockTxSim {
 	return &mockccprovider.MockTxSim{
 		GetTxSimulationResultsRv: &ledger.TxSimulationResults{
@@ -91,6 +108,8 @@ type fakeEndorserMetrics struct {
 	initFailed               *metricsfakes.Counter
 	endorsementsFailed       *metricsfakes.Counter
 	duplicateTxsFailure      *metricsfakes.Counter
+	queryCacheHits           *metricsfakes.Counter
+	queryCacheMisses         *metricsfakes.Counter
 }
 
 // initalize Endorser with fake metrics
@@ -104,6 +123,8 @@ func initFakeMetrics(es *endorser.Endorser) *fakeEndorserMetrics {
 		initFailed:               &metricsfakes.Counter{},
 		endorsementsFailed:       &metricsfakes.Counter{},
 		duplicateTxsFailure:      &metricsfakes.Counter{},
+		queryCacheHits:           &metricsfakes.Counter{},
+		queryCacheMisses:         &metricsfakes.Counter{},
 	}
 
 	fakeMetrics.proposalDuration.WithReturns(fakeMetrics.proposalDuration)
@@ -111,6 +132,8 @@ func initFakeMetrics(es *endorser.Endorser) *fakeEndorserMetrics {
 	fakeMetrics.initFailed.WithReturns(fakeMetrics.initFailed)
 	fakeMetrics.endorsementsFailed.WithReturns(fakeMetrics.endorsementsFailed)
 	fakeMetrics.duplicateTxsFailure.WithReturns(fakeMetrics.duplicateTxsFailure)
+	fakeMetrics.queryCacheHits.WithReturns(fakeMetrics.queryCacheHits)
+	fakeMetrics.queryCacheMisses.WithReturns(fakeMetrics.queryCacheMisses)
 
 	es.Metrics.ProposalDuration = fakeMetrics.proposalDuration
 	es.Metrics.ProposalsReceived = fakeMetrics.proposalsReceived
@@ -120,6 +143,8 @@ func initFakeMetrics(es *endorser.Endorser) *fakeEndorserMetrics {
 	es.Metrics.InitFailed = fakeMetrics.initFailed
 	es.Metrics.EndorsementsFailed = fakeMetrics.endorsementsFailed
 	es.Metrics.DuplicateTxsFailure = fakeMetrics.duplicateTxsFailure
+	es.Metrics.QueryCacheHits = fakeMetrics.queryCacheHits
+	es.Metrics.QueryCacheMisses = fakeMetrics.queryCacheMisses
 
 	return fakeMetrics
 }
@@ -136,7 +161,6 @@ func TestEndorserNilProp(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -164,7 +188,6 @@ func TestEndorserUninvokableSysCC(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv:       true,
 		GetApplicationConfigRv:           &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:            errors.New(""),
 		IsSysCCAndNotInvokableExternalRv: true,
 	}, platforms.NewRegistry(&golang.Platform{}), &disabled.Provider{})
 
@@ -180,7 +203,6 @@ func TestEndorserCCInvocationFailed(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 1000, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}}), Message: "Chaincode Error"},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -202,7 +224,6 @@ func TestEndorserNoCCDef(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionError:   errors.New(""),
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -224,7 +245,6 @@ func TestEndorserBadInstPolicy(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv:    true,
 		GetApplicationConfigRv:        &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:         errors.New(""),
 		CheckInstantiationPolicyError: errors.New(""),
 		ChaincodeDefinitionRv:         &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                   &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
@@ -251,7 +271,6 @@ func TestEndorserSysCC(t *testing.T) {
 		Mock:                       m,
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		IsSysCCRv:                  true,
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
@@ -270,7 +289,6 @@ func TestEndorserCCInvocationError(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ExecuteError:               errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -291,7 +309,6 @@ func TestEndorserLSCCBadType(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -319,6 +336,7 @@ func TestEndorserLSCCBadType(t *testing.T) {
 
 func TestEndorserDupTXId(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
+		TxIDExistsRv:               true,
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
@@ -347,12 +365,30 @@ func TestEndorserDupTXId(t *testing.T) {
 	assert.EqualValues(t, 1, fakeMetrics.duplicateTxsFailure.AddArgsForCall(0))
 }
 
+func TestEndorserTxIDExistsError(t *testing.T) {
+	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
+		GetApplicationConfigBoolRv: true,
+		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
+		TxIDExistsErr:              errors.New("index-error"),
+		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
+	}, platforms.NewRegistry(&golang.Platform{}), &disabled.Provider{})
+
+	fakeMetrics := initFakeMetrics(es)
+
+	signedProp := getSignedProp("ccid", "0", t)
+
+	pResp, err := es.ProcessProposal(context.Background(), signedProp)
+	assert.Error(t, err)
+	assert.EqualValues(t, 500, pResp.Response.Status)
+	assert.Equal(t, "index-error", pResp.Response.Message)
+	assert.EqualValues(t, 0, fakeMetrics.duplicateTxsFailure.AddCallCount())
+}
+
 func TestEndorserBadACL(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
 		CheckACLErr:                errors.New(""),
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -382,7 +418,6 @@ func TestEndorserGoodPathEmptyChannel(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -408,7 +443,6 @@ func TestEndorserLSCCInitFails(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -453,7 +487,6 @@ func TestEndorserLSCCDeploySysCC(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -489,7 +522,6 @@ func TestEndorserGoodPathWEvents(t *testing.T) {
 		Mock:                       m,
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 		ExecuteEvent:               &pb.ChaincodeEvent{},
@@ -508,7 +540,6 @@ func TestEndorserBadChannel(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -535,7 +566,6 @@ func TestEndorserGoodPath(t *testing.T) {
 		Mock:                       m,
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Name: "ccid", Version: "0", Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 	}
@@ -568,7 +598,6 @@ func TestEndorserChaincodeCallLogging(t *testing.T) {
 		Mock:                       m,
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 	}
@@ -595,7 +624,6 @@ func TestEndorserLSCC(t *testing.T) {
 		Mock:                       m,
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 	}
@@ -633,6 +661,119 @@ func attachPluginEndorser(support *em.MockSupport, signerReturnErr error) {
 	})
 }
 
+func TestEndorserQuery(t *testing.T) {
+	m := &mock.Mock{}
+	m.On("Sign", mock.Anything).Return([]byte{1, 2, 3, 4, 5}, nil)
+	m.On("Serialize").Return([]byte{1, 1, 1}, nil)
+	m.On("GetTxSimulator", mock.Anything, mock.Anything).Return(newMockTxSim(), nil)
+	support := &em.MockSupport{
+		Mock:                       m,
+		GetApplicationConfigBoolRv: true,
+		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
+		ChaincodeDefinitionRv:      &resourceconfig.MockChaincodeDefinition{NameRv: "ccid", VersionRv: "0", EndorsementStr: "ESCC"},
+		ExecuteResp:                &pb.Response{Status: 200, Payload: []byte{1}},
+	}
+	attachPluginEndorser(support, nil)
+
+	es := endorser.NewEndorserServer(pvtEmptyDistributor, support, platforms.NewRegistry(&golang.Platform{}), &disabled.Provider{})
+	fakeMetrics := initFakeMetrics(es)
+
+	resp, err := es.ProcessProposal(context.Background(), getSignedQueryProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.Nil(t, resp.Endorsement)
+	assert.Equal(t, 200, int(resp.Response.Status))
+	assert.Equal(t, []byte{1}, resp.Response.Payload)
+	m.AssertNotCalled(t, "Sign", mock.Anything)
+	assert.EqualValues(t, 1, fakeMetrics.successfulProposals.AddCallCount())
+	assert.EqualValues(t, 0, fakeMetrics.queryCacheMisses.AddCallCount())
+	testEndorsementCompletedMetric(t, fakeMetrics, 1, util.GetTestChainID(), "ccid:0", "true")
+}
+
+func TestEndorserQueryPrivateData(t *testing.T) {
+	m := &mock.Mock{}
+	m.On("Serialize").Return([]byte{1, 1, 1}, nil)
+	m.On("GetTxSimulator", mock.Anything, mock.Anything).Return(&mockccprovider.MockTxSim{
+		GetTxSimulationResultsRv: &ledger.TxSimulationResults{
+			PubSimulationResults: &rwset.TxReadWriteSet{},
+			PvtSimulationResults: &rwset.TxPvtReadWriteSet{},
+		},
+	}, nil)
+	support := &em.MockSupport{
+		Mock:                       m,
+		GetApplicationConfigBoolRv: true,
+		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
+		ChaincodeDefinitionRv:      &resourceconfig.MockChaincodeDefinition{NameRv: "ccid", VersionRv: "0", EndorsementStr: "ESCC"},
+		ExecuteResp:                &pb.Response{Status: 200, Payload: []byte{1}},
+	}
+
+	distributed := false
+	distributor := func(_ string, _ string, _ *transientstore.TxPvtReadWriteSetWithConfigInfo, _ uint64) error {
+		distributed = true
+		return nil
+	}
+	es := endorser.NewEndorserServer(distributor, support, platforms.NewRegistry(&golang.Platform{}), &disabled.Provider{})
+
+	resp, err := es.ProcessProposal(context.Background(), getSignedQueryProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.Equal(t, 200, int(resp.Response.Status))
+	assert.False(t, distributed)
+}
+
+func TestEndorserQueryCache(t *testing.T) {
+	m := &mock.Mock{}
+	m.On("Serialize").Return([]byte{1, 1, 1}, nil)
+	m.On("GetTxSimulator", mock.Anything, mock.Anything).Return(newMockTxSim(), nil)
+	height := m.On("GetLedgerHeight", util.GetTestChainID()).Return(uint64(5), nil)
+	support := &em.MockSupport{
+		Mock:                       m,
+		GetApplicationConfigBoolRv: true,
+		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
+		ChaincodeDefinitionRv:      &resourceconfig.MockChaincodeDefinition{NameRv: "ccid", VersionRv: "0", EndorsementStr: "ESCC"},
+		ExecuteResp:                &pb.Response{Status: 200, Payload: []byte{1}},
+	}
+
+	es := endorser.NewEndorserServer(pvtEmptyDistributor, support, platforms.NewRegistry(&golang.Platform{}), &disabled.Provider{})
+	es.QueryCache = endorser.NewQueryCache(time.Minute, 10)
+	fakeMetrics := initFakeMetrics(es)
+
+	// the first query is executed and cached
+	resp, err := es.ProcessProposal(context.Background(), getSignedQueryProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1}, resp.Response.Payload)
+	assert.EqualValues(t, 1, fakeMetrics.queryCacheMisses.AddCallCount())
+	assert.EqualValues(t, 0, fakeMetrics.queryCacheHits.AddCallCount())
+	assert.Equal(t, []string{"channel", util.GetTestChainID(), "chaincode", "ccid:0"}, fakeMetrics.queryCacheMisses.WithArgsForCall(0))
+
+	// the same query at the same height is served from the cache
+	support.ExecuteResp = &pb.Response{Status: 200, Payload: []byte{2}}
+	resp, err = es.ProcessProposal(context.Background(), getSignedQueryProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{1}, resp.Response.Payload)
+	assert.EqualValues(t, 1, fakeMetrics.queryCacheMisses.AddCallCount())
+	assert.EqualValues(t, 1, fakeMetrics.queryCacheHits.AddCallCount())
+	assert.Equal(t, []string{"channel", util.GetTestChainID(), "chaincode", "ccid:0"}, fakeMetrics.queryCacheHits.WithArgsForCall(0))
+	assert.EqualValues(t, 2, fakeMetrics.successfulProposals.AddCallCount())
+
+	// the query is executed again once the ledger height changes
+	height.Return(uint64(6), nil)
+	resp, err = es.ProcessProposal(context.Background(), getSignedQueryProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{2}, resp.Response.Payload)
+	assert.EqualValues(t, 2, fakeMetrics.queryCacheMisses.AddCallCount())
+
+	// failed queries are not cached
+	height.Return(uint64(7), nil)
+	support.ExecuteResp = &pb.Response{Status: 400, Message: "bad query"}
+	resp, err = es.ProcessProposal(context.Background(), getSignedQueryProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 400, resp.Response.Status)
+	support.ExecuteResp = &pb.Response{Status: 200, Payload: []byte{3}}
+	resp, err = es.ProcessProposal(context.Background(), getSignedQueryProp("ccid", "0", t))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{3}, resp.Response.Payload)
+	assert.EqualValues(t, 4, fakeMetrics.queryCacheMisses.AddCallCount())
+}
+
 func TestEndorseWithPlugin(t *testing.T) {
 	m := &mock.Mock{}
 	m.On("Sign", mock.Anything).Return([]byte{1, 2, 3, 4, 5}, nil)
@@ -642,7 +783,6 @@ func TestEndorseWithPlugin(t *testing.T) {
 		Mock:                       m,
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &resourceconfig.MockChaincodeDefinition{EndorsementStr: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: []byte{1}},
 	}
@@ -668,7 +808,6 @@ func TestEndorseEndorsementFailure(t *testing.T) {
 		Mock:                       m,
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &resourceconfig.MockChaincodeDefinition{NameRv: "ccid", VersionRv: "0", EndorsementStr: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: []byte{1}},
 	}
@@ -705,7 +844,6 @@ func TestEndorseEndorsementFailureDueToCCError(t *testing.T) {
 		Mock:                       m,
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &resourceconfig.MockChaincodeDefinition{NameRv: "ccid", VersionRv: "0", EndorsementStr: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 400, Message: "CC error"},
 	}
@@ -736,7 +874,6 @@ func TestSimulateProposal(t *testing.T) {
 	es := endorser.NewEndorserServer(pvtEmptyDistributor, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-		GetTransactionByIDErr:      errors.New(""),
 		ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
 		GetTxSimulatorRv: &mockccprovider.MockTxSim{
@@ -775,7 +912,6 @@ func TestEndorserAcquireTxSimulator(t *testing.T) {
 				Mock:                       m,
 				GetApplicationConfigBoolRv: true,
 				GetApplicationConfigRv:     &mc.MockApplication{CapabilitiesRv: &mc.MockApplicationCapabilities{}},
-				GetTransactionByIDErr:      errors.New(""),
 				ChaincodeDefinitionRv:      &ccprovider.ChaincodeData{Escc: "ESCC"},
 				ExecuteResp:                expectedResponse,
 			}
@@ -66,6 +66,22 @@ var (
 		LabelNames:   []string{"channel", "chaincode"},
 		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
 	}
+
+	queryCacheHitsCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "query_cache_hits",
+		Help:         "The number of queries answered from the query cache.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	queryCacheMissesCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "query_cache_misses",
+		Help:         "The number of queries not found in the query cache.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
 )
 
 type EndorserMetrics struct {
@@ -77,6 +93,8 @@ type EndorserMetrics struct {
 	InitFailed               metrics.Counter
 	EndorsementsFailed       metrics.Counter
 	DuplicateTxsFailure      metrics.Counter
+	QueryCacheHits           metrics.Counter
+	QueryCacheMisses         metrics.Counter
 }
 
 func NewEndorserMetrics(p metrics.Provider) *EndorserMetrics {
@@ -89,5 +107,7 @@ func NewEndorserMetrics(p metrics.Provider) *EndorserMetrics {
 		InitFailed:               p.NewCounter(initFailureCounterOpts),
 		EndorsementsFailed:       p.NewCounter(endorsementFailureCounterOpts),
 		DuplicateTxsFailure:      p.NewCounter(duplicateTxsFailureCounterOpts),
+		QueryCacheHits:           p.NewCounter(queryCacheHitsCounterOpts),
+		QueryCacheMisses:         p.NewCounter(queryCacheMissesCounterOpts),
 	}
 }
@@ -30,6 +30,8 @@ func TestNewEndorserMetrics(t *testing.T) {
 		InitFailed:               &metricsfakes.Counter{},
 		EndorsementsFailed:       &metricsfakes.Counter{},
 		DuplicateTxsFailure:      &metricsfakes.Counter{},
+		QueryCacheHits:           &metricsfakes.Counter{},
+		QueryCacheMisses:         &metricsfakes.Counter{},
 	}))
 
 	gt.Expect(provider.NewHistogramCallCount()).To(Equal(1))
@@ -37,7 +39,7 @@ func TestNewEndorserMetrics(t *testing.T) {
 		{proposalDurationHistogramOpts},
 	}))
 
-	gt.Expect(provider.NewCounterCallCount()).To(Equal(7))
+	gt.Expect(provider.NewCounterCallCount()).To(Equal(9))
 	gt.Expect(provider.Invocations()["NewCounter"]).To(ConsistOf([][]interface{}{
 		{receivedProposalsCounterOpts},
 		{successfulProposalsCounterOpts},
@@ -46,5 +48,7 @@ func TestNewEndorserMetrics(t *testing.T) {
 		{initFailureCounterOpts},
 		{endorsementFailureCounterOpts},
 		{duplicateTxsFailureCounterOpts},
+		{queryCacheHitsCounterOpts},
+		{queryCacheMissesCounterOpts},
 	}))
 }
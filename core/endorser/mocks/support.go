@@ -75,18 +75,18 @@ type Support struct {
 		result1 ledger.HistoryQueryExecutor
 		result2 error
 	}
-	GetTransactionByIDStub        func(chid, txID string) (*pb.ProcessedTransaction, error)
-	getTransactionByIDMutex       sync.RWMutex
-	getTransactionByIDArgsForCall []struct {
+	TxIDExistsStub        func(chid, txID string) (bool, error)
+	txIDExistsMutex       sync.RWMutex
+	txIDExistsArgsForCall []struct {
 		chid string
 		txID string
 	}
-	getTransactionByIDReturns struct {
-		result1 *pb.ProcessedTransaction
+	txIDExistsReturns struct {
+		result1 bool
 		result2 error
 	}
-	getTransactionByIDReturnsOnCall map[int]struct {
-		result1 *pb.ProcessedTransaction
+	txIDExistsReturnsOnCall map[int]struct {
+		result1 bool
 		result2 error
 	}
 	IsSysCCStub        func(name string) bool
@@ -517,54 +517,54 @@ func (fake *Support) GetHistoryQueryExecutorReturnsOnCall(i int, result1 ledger.
 	}{result1, result2}
 }
 
-func (fake *Support) GetTransactionByID(chid string, txID string) (*pb.ProcessedTransaction, error) {
-	fake.getTransactionByIDMutex.Lock()
-	ret, specificReturn := fake.getTransactionByIDReturnsOnCall[len(fake.getTransactionByIDArgsForCall)]
-	fake.getTransactionByIDArgsForCall = append(fake.getTransactionByIDArgsForCall, struct {
+func (fake *Support) TxIDExists(chid string, txID string) (bool, error) {
+	fake.txIDExistsMutex.Lock()
+	ret, specificReturn := fake.txIDExistsReturnsOnCall[len(fake.txIDExistsArgsForCall)]
+	fake.txIDExistsArgsForCall = append(fake.txIDExistsArgsForCall, struct {
 		chid string
 		txID string
 	}{chid, txID})
-	fake.recordInvocation("GetTransactionByID", []interface{}{chid, txID})
-	fake.getTransactionByIDMutex.Unlock()
-	if fake.GetTransactionByIDStub != nil {
-		return fake.GetTransactionByIDStub(chid, txID)
+	fake.recordInvocation("TxIDExists", []interface{}{chid, txID})
+	fake.txIDExistsMutex.Unlock()
+	if fake.TxIDExistsStub != nil {
+		return fake.TxIDExistsStub(chid, txID)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.getTransactionByIDReturns.result1, fake.getTransactionByIDReturns.result2
+	return fake.txIDExistsReturns.result1, fake.txIDExistsReturns.result2
 }
 
-func (fake *Support) GetTransactionByIDCallCount() int {
-	fake.getTransactionByIDMutex.RLock()
-	defer fake.getTransactionByIDMutex.RUnlock()
-	return len(fake.getTransactionByIDArgsForCall)
+func (fake *Support) TxIDExistsCallCount() int {
+	fake.txIDExistsMutex.RLock()
+	defer fake.txIDExistsMutex.RUnlock()
+	return len(fake.txIDExistsArgsForCall)
 }
 
-func (fake *Support) GetTransactionByIDArgsForCall(i int) (string, string) {
-	fake.getTransactionByIDMutex.RLock()
-	defer fake.getTransactionByIDMutex.RUnlock()
-	return fake.getTransactionByIDArgsForCall[i].chid, fake.getTransactionByIDArgsForCall[i].txID
+func (fake *Support) TxIDExistsArgsForCall(i int) (string, string) {
+	fake.txIDExistsMutex.RLock()
+	defer fake.txIDExistsMutex.RUnlock()
+	return fake.txIDExistsArgsForCall[i].chid, fake.txIDExistsArgsForCall[i].txID
 }
 
-func (fake *Support) GetTransactionByIDReturns(result1 *pb.ProcessedTransaction, result2 error) {
-	fake.GetTransactionByIDStub = nil
-	fake.getTransactionByIDReturns = struct {
-		result1 *pb.ProcessedTransaction
+func (fake *Support) TxIDExistsReturns(result1 bool, result2 error) {
+	fake.TxIDExistsStub = nil
+	fake.txIDExistsReturns = struct {
+		result1 bool
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *Support) GetTransactionByIDReturnsOnCall(i int, result1 *pb.ProcessedTransaction, result2 error) {
-	fake.GetTransactionByIDStub = nil
-	if fake.getTransactionByIDReturnsOnCall == nil {
-		fake.getTransactionByIDReturnsOnCall = make(map[int]struct {
-			result1 *pb.ProcessedTransaction
+func (fake *Support) TxIDExistsReturnsOnCall(i int, result1 bool, result2 error) {
+	fake.TxIDExistsStub = nil
+	if fake.txIDExistsReturnsOnCall == nil {
+		fake.txIDExistsReturnsOnCall = make(map[int]struct {
+			result1 bool
 			result2 error
 		})
 	}
-	fake.getTransactionByIDReturnsOnCall[i] = struct {
-		result1 *pb.ProcessedTransaction
+	fake.txIDExistsReturnsOnCall[i] = struct {
+		result1 bool
 		result2 error
 	}{result1, result2}
 }
@@ -1216,8 +1216,8 @@ func (fake *Support) Invocations() map[string][][]interface{} {
 	defer fake.getTxSimulatorMutex.RUnlock()
 	fake.getHistoryQueryExecutorMutex.RLock()
 	defer fake.getHistoryQueryExecutorMutex.RUnlock()
-	fake.getTransactionByIDMutex.RLock()
-	defer fake.getTransactionByIDMutex.RUnlock()
+	fake.txIDExistsMutex.RLock()
+	defer fake.txIDExistsMutex.RUnlock()
 	fake.isSysCCMutex.RLock()
 	defer fake.isSysCCMutex.RUnlock()
 	fake.executeMutex.RLock()
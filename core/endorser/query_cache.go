@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// QueryCache caches the responses of the queries for a short time, so that
+// identical queries evaluated at the same ledger height are answered without
+// invoking the chaincode again.
+type QueryCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mutex   sync.Mutex
+	entries map[string]*queryCacheEntry
+}
+
+type queryCacheEntry struct {
+	response *pb.Response
+	expiry   time.Time
+}
+
+// NewQueryCache creates a QueryCache whose entries expire after ttl and which
+// holds at most size entries.
+func NewQueryCache(ttl time.Duration, size int) *QueryCache {
+	return &QueryCache{
+		ttl:        ttl,
+		maxEntries: size,
+		entries:    map[string]*queryCacheEntry{},
+	}
+}
+
+// Get returns a copy of the cached response for the given key, if it has not
+// expired, so that the callers do not share the cached response.
+func (c *QueryCache) Get(key string) (*pb.Response, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiry) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return proto.Clone(entry.response).(*pb.Response), true
+}
+
+// Put caches a copy of the response for the given key. The expired entries are
+// purged when the cache is full, and the response is not cached if it still is.
+func (c *QueryCache) Put(key string, response *pb.Response) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if now.After(entry.expiry) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			return
+		}
+	}
+	c.entries[key] = &queryCacheEntry{
+		response: proto.Clone(response).(*pb.Response),
+		expiry:   now.Add(c.ttl),
+	}
+}
+
+// queryCacheKey computes the key of a query invoking the given chaincode with
+// the given arguments on behalf of the given creator at the given ledger height.
+func queryCacheKey(chainID, chaincodeName string, creator []byte, args [][]byte, height uint64) string {
+	h := sha256.New()
+	writeField := func(b []byte) {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(b)))
+		h.Write(length[:])
+		h.Write(b)
+	}
+	writeField([]byte(chainID))
+	writeField([]byte(chaincodeName))
+	writeField(creator)
+	for _, arg := range args {
+		writeField(arg)
+	}
+	var heightBytes [8]byte
+	binary.BigEndian.PutUint64(heightBytes[:], height)
+	h.Write(heightBytes[:])
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCache(t *testing.T) {
+	cache := NewQueryCache(time.Minute, 2)
+
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+
+	cache.Put("a", &pb.Response{Status: 200, Payload: []byte("a")})
+	res, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("a"), res.Payload)
+
+	// the callers do not share the cached response
+	res.Payload[0] = 'z'
+	res, ok = cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("a"), res.Payload)
+
+	// the response is not cached once the cache is full of live entries
+	cache.Put("b", &pb.Response{Status: 200, Payload: []byte("b")})
+	cache.Put("c", &pb.Response{Status: 200, Payload: []byte("c")})
+	_, ok = cache.Get("b")
+	assert.True(t, ok)
+	_, ok = cache.Get("c")
+	assert.False(t, ok)
+}
+
+func TestQueryCacheExpiry(t *testing.T) {
+	cache := NewQueryCache(time.Millisecond, 1)
+
+	cache.Put("a", &pb.Response{Status: 200})
+	time.Sleep(10 * time.Millisecond)
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+
+	// expired entries are purged to make room for new ones
+	cache.Put("b", &pb.Response{Status: 200})
+	time.Sleep(10 * time.Millisecond)
+	cache.Put("c", &pb.Response{Status: 200})
+	assert.Len(t, cache.entries, 1)
+	assert.Contains(t, cache.entries, "c")
+}
+
+func TestQueryCacheKey(t *testing.T) {
+	key := queryCacheKey("ch", "cc", []byte("creator"), [][]byte{[]byte("a"), []byte("b")}, 1)
+	assert.Equal(t, key, queryCacheKey("ch", "cc", []byte("creator"), [][]byte{[]byte("a"), []byte("b")}, 1))
+	assert.NotEqual(t, key, queryCacheKey("ch", "cc", []byte("creator"), [][]byte{[]byte("a"), []byte("b")}, 2))
+	assert.NotEqual(t, key, queryCacheKey("ch", "cc", []byte("creator"), [][]byte{[]byte("ab")}, 1))
+	assert.NotEqual(t, key, queryCacheKey("ch", "cc2", []byte("creator"), [][]byte{[]byte("a"), []byte("b")}, 1))
+	assert.NotEqual(t, key, queryCacheKey("ch", "cc", []byte("other"), [][]byte{[]byte("a"), []byte("b")}, 1))
+}
@@ -77,17 +77,18 @@ func (s *SupportImpl) GetHistoryQueryExecutor(ledgername string) (ledger.History
 	return lgr.NewHistoryQueryExecutor()
 }
 
-// GetTransactionByID retrieves a transaction by id
-func (s *SupportImpl) GetTransactionByID(chid, txID string) (*pb.ProcessedTransaction, error) {
+// TxIDExists returns true if a transaction with the given id has been committed
+// to the ledger of the channel
+func (s *SupportImpl) TxIDExists(chid, txID string) (bool, error) {
 	lgr := s.Peer.GetLedger(chid)
 	if lgr == nil {
-		return nil, errors.Errorf("failed to look up the ledger for Channel %s", chid)
+		return false, errors.Errorf("failed to look up the ledger for Channel %s", chid)
 	}
-	tx, err := lgr.GetTransactionByID(txID)
+	exists, err := lgr.TxIDExists(txID)
 	if err != nil {
-		return nil, errors.WithMessage(err, "GetTransactionByID failed")
+		return false, errors.WithMessage(err, "TxIDExists failed")
 	}
-	return tx, nil
+	return exists, nil
 }
 
 // GetLedgerHeight returns ledger height for given channelID
@@ -171,9 +172,14 @@ func (s *SupportImpl) IsJavaCC(buf []byte) (bool, error) {
 }
 
 // CheckInstantiationPolicy returns an error if the instantiation in the supplied
-// ChaincodeDefinition differs from the instantiation policy stored on the ledger
+// ChaincodeDefinition differs from the instantiation policy stored on the ledger.
+// Definitions committed through the new lifecycle have no instantiation policy.
 func (s *SupportImpl) CheckInstantiationPolicy(name, version string, cd ccprovider.ChaincodeDefinition) error {
-	return ccprovider.CheckInstantiationPolicy(name, version, cd.(*ccprovider.ChaincodeData))
+	cdata, ok := cd.(*ccprovider.ChaincodeData)
+	if !ok {
+		return nil
+	}
+	return ccprovider.CheckInstantiationPolicy(name, version, cdata)
 }
 
 // GetApplicationConfig returns the configtxapplication.SharedConfig for the Channel
@@ -9,9 +9,11 @@ package confighistory
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"math"
 
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/pkg/errors"
 )
 
@@ -44,7 +46,11 @@ type batch struct {
 
 func newDBProvider(dbPath string) *dbProvider {
 	logger.Debugf("Opening db for config history: db path = %s", dbPath)
-	return &dbProvider{leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})}
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		panic(fmt.Sprintf("Error initializing the encryption of the config history database: %s", err))
+	}
+	return &dbProvider{leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath, Encrypter: encrypter})}
 }
 
 func newBatch() *batch {
@@ -95,6 +101,20 @@ func (d *db) entryAt(blockNum uint64, ns, key string) (*compositeKV, error) {
 	return &compositeKV{k, v}, nil
 }
 
+func (d *db) exportEntries(handle func(entry *Entry) error) error {
+	itr := d.GetIterator([]byte(keyPrefix), []byte{keyPrefix[0] + 1})
+	defer itr.Release()
+	for itr.Next() {
+		k := decodeCompositeKey(itr.Key())
+		v := make([]byte, len(itr.Value()))
+		copy(v, itr.Value())
+		if err := handle(&Entry{Namespace: k.ns, Key: k.key, BlockNum: k.blockNum, Value: v}); err != nil {
+			return err
+		}
+	}
+	return errors.Wrap(itr.Error(), "error while iterating over the config history")
+}
+
 func encodeCompositeKey(ns, key string, blockNum uint64) []byte {
 	b := []byte(keyPrefix + ns)
 	b = append(b, separatorByte)
@@ -28,9 +28,26 @@ const (
 type Mgr interface {
 	ledger.StateListener
 	GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetriever) ledger.ConfigHistoryRetriever
+	ExportConfigHistory(ledgerID string, handle func(entry *Entry) error) error
+	ImportConfigHistory(ledgerID string, entries EntriesIterator) error
 	Close()
 }
 
+// Entry is an entry in the config history of a ledger. It is used for exporting the config history
+// to a snapshot and for importing it from a snapshot
+type Entry struct {
+	Namespace string
+	Key       string
+	BlockNum  uint64
+	Value     []byte
+}
+
+// EntriesIterator returns the entries to be imported in the config history. The function Next
+// returns nil when there are no more entries
+type EntriesIterator interface {
+	Next() (*Entry, error)
+}
+
 type mgr struct {
 	ccInfoProvider ledger.DeployedChaincodeInfoProvider
 	dbProvider     *dbProvider
@@ -96,6 +113,30 @@ func (m *mgr) GetRetriever(ledgerID string, ledgerInfoRetriever LedgerInfoRetrie
 	return &retriever{dbHandle: m.dbProvider.getDB(ledgerID), ledgerInfoRetriever: ledgerInfoRetriever}
 }
 
+// ExportConfigHistory implements the function in the interface 'Mgr'
+func (m *mgr) ExportConfigHistory(ledgerID string, handle func(entry *Entry) error) error {
+	return m.dbProvider.getDB(ledgerID).exportEntries(handle)
+}
+
+// ImportConfigHistory implements the function in the interface 'Mgr'. As the entries are written
+// in a single batch and are keyed by the committing block number, importing the same entries again
+// (e.g., when retrying a failed bootstrap from a snapshot) leaves the config history unchanged
+func (m *mgr) ImportConfigHistory(ledgerID string, entries EntriesIterator) error {
+	dbHandle := m.dbProvider.getDB(ledgerID)
+	batch := newBatch()
+	for {
+		entry, err := entries.Next()
+		if err != nil {
+			return err
+		}
+		if entry == nil {
+			break
+		}
+		batch.add(entry.Namespace, entry.Key, entry.BlockNum, entry.Value)
+	}
+	return dbHandle.writeBatch(batch, true)
+}
+
 // Close implements the function in the interface 'Mgr'
 func (m *mgr) Close() {
 	m.dbProvider.Close()
@@ -115,6 +115,62 @@ func TestMgr(t *testing.T) {
 	})
 }
 
+func TestExportAndImportConfigHistory(t *testing.T) {
+	dbPath := "/tmp/fabric/core/ledger/confighistory"
+	mockCCInfoProvider := &mock.DeployedChaincodeInfoProvider{}
+	env := newTestEnv(t, dbPath, mockCCInfoProvider)
+	mgr := env.mgr
+	defer env.cleanup()
+	chaincodeName := "chaincode1"
+	configCommittingBlockNums := []uint64{5, 10, 15}
+	for _, committingBlockNum := range configCommittingBlockNums {
+		collConfigPackage := sampleCollectionConfigPackage("ledger1", committingBlockNum)
+		testutilEquipMockCCInfoProviderToReturnDesiredCollConfig(mockCCInfoProvider, chaincodeName, collConfigPackage)
+		assert.NoError(t, mgr.HandleStateUpdates(&ledger.StateUpdateTrigger{
+			LedgerID:           "ledger1",
+			CommittingBlockNum: committingBlockNum},
+		))
+	}
+
+	entries := []*Entry{}
+	assert.NoError(t, mgr.ExportConfigHistory("ledger1", func(entry *Entry) error {
+		entries = append(entries, entry)
+		return nil
+	}))
+	assert.Len(t, entries, len(configCommittingBlockNums))
+
+	assert.NoError(t, mgr.ImportConfigHistory("ledger2", &testEntriesIterator{entries}))
+	dummyLedgerInfoRetriever := &dummyLedgerInfoRetriever{info: &common.BlockchainInfo{Height: 20}}
+	retriever := mgr.GetRetriever("ledger2", dummyLedgerInfoRetriever)
+	for _, committingBlockNum := range configCommittingBlockNums {
+		retrievedConfig, err := retriever.CollectionConfigAt(committingBlockNum, chaincodeName)
+		assert.NoError(t, err)
+		assert.Equal(t, sampleCollectionConfigPackage("ledger1", committingBlockNum), retrievedConfig.CollectionConfig)
+	}
+
+	// importing the same entries again leaves the config history unchanged
+	assert.NoError(t, mgr.ImportConfigHistory("ledger2", &testEntriesIterator{entries}))
+	reimported := []*Entry{}
+	assert.NoError(t, mgr.ExportConfigHistory("ledger2", func(entry *Entry) error {
+		reimported = append(reimported, entry)
+		return nil
+	}))
+	assert.Equal(t, entries, reimported)
+}
+
+type testEntriesIterator struct {
+	entries []*Entry
+}
+
+func (itr *testEntriesIterator) Next() (*Entry, error) {
+	if len(itr.entries) == 0 {
+		return nil, nil
+	}
+	entry := itr.entries[0]
+	itr.entries = itr.entries[1:]
+	return entry, nil
+}
+
 type testEnv struct {
 	dbPath string
 	mgr    Mgr
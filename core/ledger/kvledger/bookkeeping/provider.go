@@ -37,7 +37,11 @@ type provider struct {
 
 // NewProvider instantiates a new provider
 func NewProvider() Provider {
-	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: getInternalBookkeeperPath()})
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		panic(fmt.Sprintf("Error initializing the encryption of the bookkeeping database: %s", err))
+	}
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: getInternalBookkeeperPath(), Encrypter: encrypter})
 	return &provider{dbProvider: dbProvider}
 }
 
@@ -54,6 +54,38 @@ func ConstructPartialCompositeHistoryKey(ns string, key string, endkey bool) []b
 	return compositeKey
 }
 
+// PvtDataHistoryKeyPrefix is the prefix of the History Keys of the private data hashes, which sorts
+// before the namespaces so that the private data history records do not fall in the range of the
+// History Keys of the public data
+var PvtDataHistoryKeyPrefix = []byte{0x01}
+
+// ConstructCompositePvtDataHistoryKey builds the History Key of the private data hash of a key,
+// in the form prefix~namespace~collection~keyhash~blocknum~trannum, using an order preserving
+// encoding so that history query results are ordered by height
+func ConstructCompositePvtDataHistoryKey(ns, coll string, keyHash []byte, blocknum uint64, trannum uint64) []byte {
+	compositeKey := ConstructPartialCompositePvtDataHistoryKey(ns, coll, keyHash, false)
+	compositeKey = append(compositeKey, util.EncodeOrderPreservingVarUint64(blocknum)...)
+	compositeKey = append(compositeKey, util.EncodeOrderPreservingVarUint64(trannum)...)
+	return compositeKey
+}
+
+// ConstructPartialCompositePvtDataHistoryKey builds a partial History Key prefix~namespace~collection~keyhash~
+// for use in private data history key range queries
+func ConstructPartialCompositePvtDataHistoryKey(ns, coll string, keyHash []byte, endkey bool) []byte {
+	var compositeKey []byte
+	compositeKey = append(compositeKey, PvtDataHistoryKeyPrefix...)
+	compositeKey = append(compositeKey, []byte(ns)...)
+	compositeKey = append(compositeKey, CompositeKeySep...)
+	compositeKey = append(compositeKey, []byte(coll)...)
+	compositeKey = append(compositeKey, CompositeKeySep...)
+	compositeKey = append(compositeKey, keyHash...)
+	compositeKey = append(compositeKey, CompositeKeySep...)
+	if endkey {
+		compositeKey = append(compositeKey, []byte{0xff}...)
+	}
+	return compositeKey
+}
+
 //SplitCompositeHistoryKey splits the key bytes using a separator
 func SplitCompositeHistoryKey(bytesToSplit []byte, separator []byte) ([]byte, []byte) {
 	split := bytes.SplitN(bytesToSplit, separator, 2)
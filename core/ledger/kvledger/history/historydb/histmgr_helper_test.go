@@ -46,3 +46,16 @@ func TestSplitCompositeKey(t *testing.T) {
 	// second position should hold the extra bytes that were split off
 	assert.Equal(t, []byte("extra bytes to split"), extraBytes)
 }
+
+func TestConstructPartialCompositePvtDataKey(t *testing.T) {
+	keyHash := []byte("keyhash")
+	compositeStartKey := ConstructPartialCompositePvtDataHistoryKey("ns1", "coll1", keyHash, false)
+	compositeEndKey := ConstructPartialCompositePvtDataHistoryKey("ns1", "coll1", keyHash, true)
+
+	assert.Equal(t, []byte(string(PvtDataHistoryKeyPrefix)+"ns1"+strKeySep+"coll1"+strKeySep+"keyhash"+strKeySep), compositeStartKey)
+	assert.Equal(t, []byte(string(PvtDataHistoryKeyPrefix)+"ns1"+strKeySep+"coll1"+strKeySep+"keyhash"+strKeySep+string([]byte{0xff})), compositeEndKey)
+
+	compositeKey := ConstructCompositePvtDataHistoryKey("ns1", "coll1", keyHash, 1, 1)
+	_, extraBytes := SplitCompositeHistoryKey(compositeKey, compositeStartKey)
+	assert.Len(t, extraBytes, 4)
+}
@@ -33,9 +33,14 @@ type HistoryDBProvider interface {
 
 // HistoryDB - an interface that a history database should implement
 type HistoryDB interface {
-	NewHistoryQueryExecutor(blockStore blkstorage.BlockStore) (ledger.HistoryQueryExecutor, error)
+	NewHistoryQueryExecutor(blockStore blkstorage.BlockStore, pvtDataRetriever PvtDataRetriever) (ledger.HistoryQueryExecutor, error)
 	Commit(block *common.Block) error
 	GetLastSavepoint() (*version.Height, error)
 	ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error)
 	CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData) error
 }
+
+// PvtDataRetriever retrieves the private data of the transactions of a block that the peer holds
+type PvtDataRetriever interface {
+	GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error)
+}
@@ -0,0 +1,233 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package historyleveldb
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/pkg/errors"
+)
+
+// The block time index records, for buckets of consecutive blocks, the lowest and the highest timestamps
+// of the endorser transactions of the blocks in the bucket. A bucket at level l holds 16^l blocks, so that
+// the bucket at level l of the block b is b>>(4*l) and the buckets of a level are the children of the buckets
+// of the next level. As the timestamps of the transactions are set by the clients and do not grow with the
+// block numbers, the index is searched top down to find the first or the last block that may hold a transaction
+// in a time range, skipping the buckets whose timestamps are all outside the time range
+const (
+	blockTimeIndexLevels   = 8
+	blockTimeIndexLevelLog = 4
+)
+
+// blockTimeIndexPrefix is the prefix of the entries of the block time index, which sorts before the namespaces
+var blockTimeIndexPrefix = []byte{0x02}
+
+// blockTimeIndexFirstBlockKey records the first block committed to the block time index. The blocks committed
+// before, by a peer that did not maintain the index, are not narrowed by the time ranges of the history queries
+var blockTimeIndexFirstBlockKey = []byte{0x03}
+
+// blockTimeBounds holds the lowest and the highest timestamps of the transactions of a block or a bucket
+type blockTimeBounds struct {
+	min *timestamp.Timestamp
+	max *timestamp.Timestamp
+}
+
+// add widens the bounds to include the given timestamp
+func (b *blockTimeBounds) add(t *timestamp.Timestamp) {
+	if b.min == nil || compareTimestamps(t, b.min) < 0 {
+		b.min = t
+	}
+	if b.max == nil || compareTimestamps(t, b.max) > 0 {
+		b.max = t
+	}
+}
+
+// overlaps returns true if some timestamp within the bounds may fall in the time range. A nil start or end time
+// leaves the time range open on that side
+func (b *blockTimeBounds) overlaps(startTime, endTime *timestamp.Timestamp) bool {
+	if startTime != nil && compareTimestamps(b.max, startTime) < 0 {
+		return false
+	}
+	if endTime != nil && compareTimestamps(b.min, endTime) > 0 {
+		return false
+	}
+	return true
+}
+
+func (b *blockTimeBounds) toBytes() []byte {
+	buf := proto.NewBuffer(nil)
+	for _, t := range []*timestamp.Timestamp{b.min, b.max} {
+		buf.EncodeZigzag64(uint64(t.Seconds))
+		buf.EncodeZigzag32(uint64(t.Nanos))
+	}
+	return buf.Bytes()
+}
+
+func blockTimeBoundsFromBytes(bytes []byte) (*blockTimeBounds, error) {
+	buf := proto.NewBuffer(bytes)
+	timestamps := make([]*timestamp.Timestamp, 2)
+	for i := range timestamps {
+		seconds, err := buf.DecodeZigzag64()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode the block time index entry")
+		}
+		nanos, err := buf.DecodeZigzag32()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode the block time index entry")
+		}
+		timestamps[i] = &timestamp.Timestamp{Seconds: int64(seconds), Nanos: int32(nanos)}
+	}
+	return &blockTimeBounds{min: timestamps[0], max: timestamps[1]}, nil
+}
+
+func constructBlockTimeIndexKey(level int, bucket uint64) []byte {
+	key := append([]byte{}, blockTimeIndexPrefix...)
+	key = append(key, byte(level))
+	return append(key, util.EncodeOrderPreservingVarUint64(bucket)...)
+}
+
+// addToBlockTimeIndex adds the update of the block time index for the timestamps of the transactions of a block to
+// the batch. The bounds are nil if the block has no endorser transaction with a timestamp
+func (historyDB *historyDB) addToBlockTimeIndex(dbBatch *leveldbhelper.UpdateBatch, blockNum uint64, bounds *blockTimeBounds) error {
+	firstBlockBytes, err := historyDB.db.Get(blockTimeIndexFirstBlockKey)
+	if err != nil {
+		return err
+	}
+	if firstBlockBytes == nil {
+		dbBatch.Put(blockTimeIndexFirstBlockKey, util.EncodeOrderPreservingVarUint64(blockNum))
+	}
+	if bounds == nil {
+		return nil
+	}
+	for level := 0; level < blockTimeIndexLevels; level++ {
+		key := constructBlockTimeIndexKey(level, blockNum>>uint(level*blockTimeIndexLevelLog))
+		bucketBounds, err := historyDB.getBlockTimeBounds(key)
+		if err != nil {
+			return err
+		}
+		if bucketBounds == nil {
+			bucketBounds = &blockTimeBounds{}
+		}
+		bucketBounds.add(bounds.min)
+		bucketBounds.add(bounds.max)
+		dbBatch.Put(key, bucketBounds.toBytes())
+	}
+	return nil
+}
+
+func (historyDB *historyDB) getBlockTimeBounds(key []byte) (*blockTimeBounds, error) {
+	boundsBytes, err := historyDB.db.Get(key)
+	if err != nil || boundsBytes == nil {
+		return nil, err
+	}
+	return blockTimeBoundsFromBytes(boundsBytes)
+}
+
+// resolveTimeRange narrows the block range [startBlock, endBlock] to the blocks from the first to the last block
+// that may hold a transaction in the time range. It returns false if no block in the block range may hold a
+// transaction in the time range
+func (historyDB *historyDB) resolveTimeRange(startBlock, endBlock uint64,
+	startTime, endTime *timestamp.Timestamp) (uint64, uint64, bool, error) {
+
+	firstBlockBytes, err := historyDB.db.Get(blockTimeIndexFirstBlockKey)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if firstBlockBytes == nil {
+		return startBlock, endBlock, true, nil
+	}
+	firstIndexedBlock, _ := util.DecodeOrderPreservingVarUint64(firstBlockBytes)
+	if endBlock < firstIndexedBlock {
+		return startBlock, endBlock, true, nil
+	}
+
+	search := &blockTimeIndexSearch{
+		historyDB: historyDB,
+		lo:        startBlock,
+		hi:        endBlock,
+		startTime: startTime,
+		endTime:   endTime,
+	}
+	if search.lo < firstIndexedBlock {
+		search.lo = firstIndexedBlock
+	}
+	lastBlock, found, err := search.find(true)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	if !found {
+		// only the blocks that are not indexed may hold a transaction in the time range
+		if startBlock < firstIndexedBlock {
+			return startBlock, firstIndexedBlock - 1, true, nil
+		}
+		return 0, 0, false, nil
+	}
+	if startBlock < firstIndexedBlock {
+		return startBlock, lastBlock, true, nil
+	}
+	firstBlock, _, err := search.find(false)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	return firstBlock, lastBlock, true, nil
+}
+
+// blockTimeIndexSearch searches the block time index for the first or the last block within [lo, hi]
+// that may hold a transaction in the time range
+type blockTimeIndexSearch struct {
+	historyDB          *historyDB
+	lo, hi             uint64
+	startTime, endTime *timestamp.Timestamp
+}
+
+func (s *blockTimeIndexSearch) find(last bool) (uint64, bool, error) {
+	topLevel := blockTimeIndexLevels - 1
+	shift := uint(topLevel * blockTimeIndexLevelLog)
+	first, end := s.lo>>shift, s.hi>>shift
+	for i := uint64(0); i <= end-first; i++ {
+		bucket := first + i
+		if last {
+			bucket = end - i
+		}
+		blockNum, found, err := s.findInBucket(topLevel, bucket, last)
+		if err != nil || found {
+			return blockNum, found, err
+		}
+	}
+	return 0, false, nil
+}
+
+func (s *blockTimeIndexSearch) findInBucket(level int, bucket uint64, last bool) (uint64, bool, error) {
+	shift := uint(level * blockTimeIndexLevelLog)
+	if bucket<<shift > s.hi || (bucket+1)<<shift <= s.lo {
+		return 0, false, nil
+	}
+	bounds, err := s.historyDB.getBlockTimeBounds(constructBlockTimeIndexKey(level, bucket))
+	if err != nil {
+		return 0, false, err
+	}
+	if bounds == nil || !bounds.overlaps(s.startTime, s.endTime) {
+		return 0, false, nil
+	}
+	if level == 0 {
+		return bucket, true, nil
+	}
+	numChildren := uint64(1) << blockTimeIndexLevelLog
+	for i := uint64(0); i < numChildren; i++ {
+		child := bucket<<blockTimeIndexLevelLog + i
+		if last {
+			child = bucket<<blockTimeIndexLevelLog + numChildren - 1 - i
+		}
+		blockNum, found, err := s.findInBucket(level-1, child, last)
+		if err != nil || found {
+			return blockNum, found, err
+		}
+	}
+	return 0, false, nil
+}
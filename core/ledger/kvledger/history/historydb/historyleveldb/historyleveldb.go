@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package historyleveldb
 
 import (
+	"fmt"
+
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -34,7 +36,11 @@ type HistoryDBProvider struct {
 func NewHistoryDBProvider() *HistoryDBProvider {
 	dbPath := ledgerconfig.GetHistoryLevelDBPath()
 	logger.Debugf("constructing HistoryDBProvider dbPath=%s", dbPath)
-	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		panic(fmt.Sprintf("Error initializing the encryption of the history database: %s", err))
+	}
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath, Encrypter: encrypter})
 	return &HistoryDBProvider{dbProvider}
 }
 
@@ -78,6 +84,7 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 	var tranNo uint64
 
 	dbBatch := leveldbhelper.NewUpdateBatch()
+	var timeBounds *blockTimeBounds
 
 	logger.Debugf("Channel [%s]: Updating history database for blockNo [%v] with [%d] transactions",
 		historyDB.dbName, blockNo, len(block.Data.Data))
@@ -113,6 +120,13 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 
 		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
 
+			if chdr.Timestamp != nil {
+				if timeBounds == nil {
+					timeBounds = &blockTimeBounds{}
+				}
+				timeBounds.add(chdr.Timestamp)
+			}
+
 			// extract actions from the envelope message
 			respPayload, err := putils.GetActionFromEnvelope(envBytes)
 			if err != nil {
@@ -141,6 +155,17 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 					// No value is required, write an empty byte array (emptyValue) since Put() of nil is not allowed
 					dbBatch.Put(compositeHistoryKey, emptyValue)
 				}
+
+				// add a history record for each write of the private data, keyed by the hash of the
+				// key since the peer may not be eligible to hold the private data itself
+				for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+					coll := collHashedRWSet.CollectionName
+					for _, kvWriteHash := range collHashedRWSet.HashedRwSet.HashedWrites {
+						compositePvtDataHistoryKey := historydb.ConstructCompositePvtDataHistoryKey(
+							ns, coll, kvWriteHash.KeyHash, blockNo, tranNo)
+						dbBatch.Put(compositePvtDataHistoryKey, emptyValue)
+					}
+				}
 			}
 
 		} else {
@@ -149,6 +174,10 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 		tranNo++
 	}
 
+	if err := historyDB.addToBlockTimeIndex(dbBatch, blockNo, timeBounds); err != nil {
+		return err
+	}
+
 	// add savepoint for recovery purpose
 	height := version.NewHeight(blockNo, tranNo)
 	dbBatch.Put(savePointKey, height.ToBytes())
@@ -164,8 +193,9 @@ func (historyDB *historyDB) Commit(block *common.Block) error {
 }
 
 // NewHistoryQueryExecutor implements method in HistoryDB interface
-func (historyDB *historyDB) NewHistoryQueryExecutor(blockStore blkstorage.BlockStore) (ledger.HistoryQueryExecutor, error) {
-	return &LevelHistoryDBQueryExecutor{historyDB, blockStore}, nil
+func (historyDB *historyDB) NewHistoryQueryExecutor(blockStore blkstorage.BlockStore,
+	pvtDataRetriever historydb.PvtDataRetriever) (ledger.HistoryQueryExecutor, error) {
+	return &LevelHistoryDBQueryExecutor{historyDB, blockStore, pvtDataRetriever}, nil
 }
 
 // GetBlockNumFromSavepoint implements method in HistoryDB interface
@@ -8,13 +8,21 @@ package historyleveldb
 
 import (
 	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
 
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	putils "github.com/hyperledger/fabric/protos/utils"
@@ -24,25 +32,124 @@ import (
 
 // LevelHistoryDBQueryExecutor is a query executor against the LevelDB history DB
 type LevelHistoryDBQueryExecutor struct {
-	historyDB  *historyDB
-	blockStore blkstorage.BlockStore
+	historyDB        *historyDB
+	blockStore       blkstorage.BlockStore
+	pvtDataRetriever historydb.PvtDataRetriever
 }
 
 // GetHistoryForKey implements method in interface `ledger.HistoryQueryExecutor`
 func (q *LevelHistoryDBQueryExecutor) GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error) {
+	return q.GetHistoryForKeyWithOptions(namespace, key, &ledger.HistoryQueryOptions{})
+}
+
+// GetHistoryForKeyWithOptions implements method in interface `ledger.HistoryQueryExecutor`
+func (q *LevelHistoryDBQueryExecutor) GetHistoryForKeyWithOptions(namespace string, key string, options *ledger.HistoryQueryOptions) (ledger.QueryResultsIterator, error) {
 
 	if ledgerconfig.IsHistoryDBEnabled() == false {
 		return nil, errors.New("history database not enabled")
 	}
+	if options.EndBlock != 0 && options.EndBlock < options.StartBlock {
+		return nil, errors.Errorf("end block [%d] of the history query is lower than the start block [%d]",
+			options.EndBlock, options.StartBlock)
+	}
+
+	startBlock, endBlock := options.StartBlock, options.EndBlock
+	hasEndBlock := endBlock != 0 && endBlock != math.MaxUint64
+	inRange := true
+	if options.StartTime != nil || options.EndTime != nil {
+		var err error
+		if startBlock, endBlock, inRange, err = q.resolveTimeRange(options); err != nil {
+			return nil, err
+		}
+		hasEndBlock = true
+	}
+
+	compositePartialKey := historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
+	compositeStartKey := compositePartialKey
+	if startBlock != 0 {
+		compositeStartKey = constructBlockBoundKey(compositePartialKey, startBlock)
+	}
+	compositeEndKey := historydb.ConstructPartialCompositeHistoryKey(namespace, key, true)
+	if hasEndBlock {
+		compositeEndKey = constructBlockBoundKey(compositePartialKey, endBlock+1)
+	}
+	if !inRange {
+		// no block holds a transaction in the time range, scan an empty range
+		compositeEndKey = compositeStartKey
+	}
 
-	var compositeStartKey []byte
-	var compositeEndKey []byte
-	compositeStartKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, false)
-	compositeEndKey = historydb.ConstructPartialCompositeHistoryKey(namespace, key, true)
+	// the bookmark marks the next history record to return, in the order of the query
+	if options.Bookmark != "" {
+		blockNum, tranNum, err := decodeHistoryBookmark(options.Bookmark)
+		if err != nil {
+			return nil, err
+		}
+		bookmarkKey := historydb.ConstructCompositeHistoryKey(namespace, key, blockNum, tranNum)
+		if options.NewestFirst {
+			bookmarkEndKey := append(bookmarkKey, 0x00)
+			if bytes.Compare(bookmarkEndKey, compositeEndKey) < 0 {
+				compositeEndKey = bookmarkEndKey
+			}
+		} else if bytes.Compare(bookmarkKey, compositeStartKey) > 0 {
+			compositeStartKey = bookmarkKey
+		}
+	}
 
 	// range scan to find any history records starting with namespace~key
 	dbItr := q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey)
-	return newHistoryScanner(compositeStartKey, namespace, key, dbItr, q.blockStore), nil
+	return newHistoryScanner(compositePartialKey, namespace, key, dbItr, q.blockStore, options), nil
+}
+
+// resolveTimeRange resolves the time range of the query to the range of the blocks that may hold a
+// transaction in the time range, within the block range of the query, so that the history records of
+// the other blocks are not scanned. It returns false if no block may hold a transaction in the time range
+func (q *LevelHistoryDBQueryExecutor) resolveTimeRange(options *ledger.HistoryQueryOptions) (uint64, uint64, bool, error) {
+	endBlock := options.EndBlock
+	if endBlock == 0 || endBlock == math.MaxUint64 {
+		savepoint, err := q.historyDB.GetLastSavepoint()
+		if err != nil {
+			return 0, 0, false, err
+		}
+		if savepoint == nil {
+			return 0, 0, false, nil
+		}
+		endBlock = savepoint.BlockNum
+	}
+	if endBlock < options.StartBlock {
+		return 0, 0, false, nil
+	}
+	return q.historyDB.resolveTimeRange(options.StartBlock, endBlock, options.StartTime, options.EndTime)
+}
+
+// GetPrivateDataHistoryForKey implements method in interface `ledger.HistoryQueryExecutor`
+func (q *LevelHistoryDBQueryExecutor) GetPrivateDataHistoryForKey(namespace string, collection string, key string) (commonledger.ResultsIterator, error) {
+
+	if ledgerconfig.IsHistoryDBEnabled() == false {
+		return nil, errors.New("history database not enabled")
+	}
+
+	keyHash := ledgerutil.ComputeStringHash(key)
+	// range scan to find any history records starting with prefix~namespace~collection~keyhash
+	compositeStartKey := historydb.ConstructPartialCompositePvtDataHistoryKey(namespace, collection, keyHash, false)
+	compositeEndKey := historydb.ConstructPartialCompositePvtDataHistoryKey(namespace, collection, keyHash, true)
+	dbItr := q.historyDB.db.GetIterator(compositeStartKey, compositeEndKey)
+	return &pvtDataHistoryScanner{
+		compositePartialKey: compositeStartKey,
+		namespace:           namespace,
+		collection:          collection,
+		key:                 key,
+		keyHash:             keyHash,
+		dbItr:               dbItr,
+		blockStore:          q.blockStore,
+		pvtDataRetriever:    q.pvtDataRetriever,
+	}, nil
+}
+
+// constructBlockBoundKey returns the history key namespace~key~blocknum that sorts before the
+// history records of the key in the block
+func constructBlockBoundKey(compositePartialKey []byte, blockNum uint64) []byte {
+	boundKey := append([]byte{}, compositePartialKey...)
+	return append(boundKey, util.EncodeOrderPreservingVarUint64(blockNum)...)
 }
 
 //historyScanner implements ResultsIterator for iterating through history results
@@ -52,18 +159,69 @@ type historyScanner struct {
 	key                 string
 	dbItr               iterator.Iterator
 	blockStore          blkstorage.BlockStore
+	options             *ledger.HistoryQueryOptions
+	started             bool
+	returned            int32
 }
 
 func newHistoryScanner(compositePartialKey []byte, namespace string, key string,
-	dbItr iterator.Iterator, blockStore blkstorage.BlockStore) *historyScanner {
-	return &historyScanner{compositePartialKey, namespace, key, dbItr, blockStore}
+	dbItr iterator.Iterator, blockStore blkstorage.BlockStore, options *ledger.HistoryQueryOptions) *historyScanner {
+	return &historyScanner{
+		compositePartialKey: compositePartialKey,
+		namespace:           namespace,
+		key:                 key,
+		dbItr:               dbItr,
+		blockStore:          blockStore,
+		options:             options,
+	}
 }
 
 func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
+	if scanner.options.Limit > 0 && scanner.returned >= scanner.options.Limit {
+		return nil, nil
+	}
 	for {
-		if !scanner.dbItr.Next() {
+		blockNum, tranNum, ok := scanner.nextRecord()
+		if !ok {
 			return nil, nil
 		}
+		logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
+			scanner.namespace, scanner.key, blockNum, tranNum)
+
+		// Get the transaction from block storage that is associated with this history record
+		tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+		if _, ok := err.(*ledger.ErrBlockArchived); ok {
+			// the history of the key is available only from the blocks retained by the pruning
+			logger.Debugf("Block [%d] is archived. Skipping history record for namespace:%s key:%s",
+				blockNum, scanner.namespace, scanner.key)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Get the txid, key write value, timestamp, and delete indicator associated with this transaction
+		queryResult, err := getKeyModificationFromTran(tranEnvelope, scanner.namespace, scanner.key)
+		if err != nil {
+			return nil, err
+		}
+		keyModification := queryResult.(*queryresult.KeyModification)
+		if !scanner.inTimeRange(keyModification.Timestamp) {
+			continue
+		}
+		logger.Debugf("Found historic key value for namespace:%s key:%s from transaction %s\n",
+			scanner.namespace, scanner.key, keyModification.TxId)
+		scanner.returned++
+		return queryResult, nil
+	}
+}
+
+// nextRecord moves to the next history record of the key, in the order of the query, and returns its height
+func (scanner *historyScanner) nextRecord() (blockNum uint64, tranNum uint64, ok bool) {
+	for {
+		if !scanner.moveNext() {
+			return 0, 0, false
+		}
 		historyKey := scanner.dbItr.Key() // history key is in the form namespace~key~blocknum~trannum
 
 		// SplitCompositeKey(namespace~key~blocknum~trannum, namespace~key~) will return the blocknum~trannum in second position
@@ -89,27 +247,173 @@ func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
 		}
 		blockNum, bytesConsumed := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[0:])
 		tranNum, _ := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[bytesConsumed:])
-		logger.Debugf("Found history record for namespace:%s key:%s at blockNumTranNum %v:%v\n",
-			scanner.namespace, scanner.key, blockNum, tranNum)
+		return blockNum, tranNum, true
+	}
+}
+
+func (scanner *historyScanner) moveNext() bool {
+	if !scanner.options.NewestFirst {
+		return scanner.dbItr.Next()
+	}
+	if !scanner.started {
+		scanner.started = true
+		return scanner.dbItr.Last()
+	}
+	return scanner.dbItr.Prev()
+}
+
+func (scanner *historyScanner) inTimeRange(txTimestamp *timestamp.Timestamp) bool {
+	if scanner.options.StartTime == nil && scanner.options.EndTime == nil {
+		return true
+	}
+	if txTimestamp == nil {
+		return false
+	}
+	if scanner.options.StartTime != nil && compareTimestamps(txTimestamp, scanner.options.StartTime) < 0 {
+		return false
+	}
+	if scanner.options.EndTime != nil && compareTimestamps(txTimestamp, scanner.options.EndTime) > 0 {
+		return false
+	}
+	return true
+}
+
+func compareTimestamps(t1, t2 *timestamp.Timestamp) int {
+	switch {
+	case t1.Seconds != t2.Seconds:
+		if t1.Seconds < t2.Seconds {
+			return -1
+		}
+		return 1
+	case t1.Nanos < t2.Nanos:
+		return -1
+	case t1.Nanos > t2.Nanos:
+		return 1
+	}
+	return 0
+}
+
+func (scanner *historyScanner) Close() {
+	scanner.dbItr.Release()
+}
+
+// GetBookmarkAndClose returns the bookmark of the next history record of the key, if any, and
+// releases the resources held by the scanner
+func (scanner *historyScanner) GetBookmarkAndClose() string {
+	bookmark := ""
+	if blockNum, tranNum, ok := scanner.nextRecord(); ok {
+		bookmark = encodeHistoryBookmark(blockNum, tranNum)
+	}
+	scanner.Close()
+	return bookmark
+}
+
+func encodeHistoryBookmark(blockNum, tranNum uint64) string {
+	return fmt.Sprintf("%d:%d", blockNum, tranNum)
+}
+
+func decodeHistoryBookmark(bookmark string) (blockNum uint64, tranNum uint64, err error) {
+	parts := strings.Split(bookmark, ":")
+	if len(parts) == 2 {
+		if blockNum, err = strconv.ParseUint(parts[0], 10, 64); err == nil {
+			if tranNum, err = strconv.ParseUint(parts[1], 10, 64); err == nil {
+				return blockNum, tranNum, nil
+			}
+		}
+	}
+	return 0, 0, errors.Errorf("invalid bookmark [%s] for the history query", bookmark)
+}
+
+// pvtDataHistoryScanner implements ResultsIterator for iterating through the history of the hashes of a private data key
+type pvtDataHistoryScanner struct {
+	compositePartialKey []byte //compositePartialKey includes prefix~namespace~collection~keyhash
+	namespace           string
+	collection          string
+	key                 string
+	keyHash             []byte
+	dbItr               iterator.Iterator
+	blockStore          blkstorage.BlockStore
+	pvtDataRetriever    historydb.PvtDataRetriever
+}
+
+func (scanner *pvtDataHistoryScanner) Next() (commonledger.QueryResult, error) {
+	for {
+		if !scanner.dbItr.Next() {
+			return nil, nil
+		}
+		// history key is in the form prefix~namespace~collection~keyhash~blocknum~trannum. As the key hash
+		// has a fixed length, no history record of some other key can fall in the range of the scan
+		historyKey := scanner.dbItr.Key()
+		_, blockNumTranNumBytes := historydb.SplitCompositeHistoryKey(historyKey, scanner.compositePartialKey)
+		blockNum, bytesConsumed := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[0:])
+		tranNum, _ := util.DecodeOrderPreservingVarUint64(blockNumTranNumBytes[bytesConsumed:])
+		logger.Debugf("Found private data history record for namespace:%s collection:%s key:%s at blockNumTranNum %v:%v\n",
+			scanner.namespace, scanner.collection, scanner.key, blockNum, tranNum)
 
-		// Get the transaction from block storage that is associated with this history record
 		tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+		if _, ok := err.(*ledger.ErrBlockArchived); ok {
+			logger.Debugf("Block [%d] is archived. Skipping private data history record for namespace:%s collection:%s key:%s",
+				blockNum, scanner.namespace, scanner.collection, scanner.key)
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
 
-		// Get the txid, key write value, timestamp, and delete indicator associated with this transaction
-		queryResult, err := getKeyModificationFromTran(tranEnvelope, scanner.namespace, scanner.key)
+		keyModification, err := getPrivateDataKeyModificationFromTran(tranEnvelope, scanner.namespace, scanner.collection, scanner.keyHash)
 		if err != nil {
 			return nil, err
 		}
-		logger.Debugf("Found historic key value for namespace:%s key:%s from transaction %s\n",
-			scanner.namespace, scanner.key, queryResult.(*queryresult.KeyModification).TxId)
-		return queryResult, nil
+		if !keyModification.IsDelete {
+			if keyModification.Value, err = scanner.retrievePrivateValue(blockNum, tranNum); err != nil {
+				return nil, err
+			}
+		}
+		return keyModification, nil
 	}
 }
 
-func (scanner *historyScanner) Close() {
+// retrievePrivateValue returns the value of the key written by the transaction, if the peer holds the
+// private data of the transaction, and nil otherwise - e.g. if the peer is not eligible for the collection
+// or the private data has been purged
+func (scanner *pvtDataHistoryScanner) retrievePrivateValue(blockNum uint64, tranNum uint64) ([]byte, error) {
+	if scanner.pvtDataRetriever == nil {
+		return nil, nil
+	}
+	filter := ledger.NewPvtNsCollFilter()
+	filter.Add(scanner.namespace, scanner.collection)
+	pvtData, err := scanner.pvtDataRetriever.GetPvtDataByNum(blockNum, filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, txPvtData := range pvtData {
+		if txPvtData.SeqInBlock != tranNum || txPvtData.WriteSet == nil {
+			continue
+		}
+		txPvtRWSet, err := rwsetutil.TxPvtRwSetFromProtoMsg(txPvtData.WriteSet)
+		if err != nil {
+			return nil, err
+		}
+		for _, nsPvtRWSet := range txPvtRWSet.NsPvtRwSet {
+			if nsPvtRWSet.NameSpace != scanner.namespace {
+				continue
+			}
+			for _, collPvtRWSet := range nsPvtRWSet.CollPvtRwSets {
+				if collPvtRWSet.CollectionName != scanner.collection {
+					continue
+				}
+				for _, kvWrite := range collPvtRWSet.KvRwSet.Writes {
+					if kvWrite.Key == scanner.key {
+						return kvWrite.Value, nil
+					}
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (scanner *pvtDataHistoryScanner) Close() {
 	scanner.dbItr.Release()
 }
 
@@ -117,51 +421,89 @@ func (scanner *historyScanner) Close() {
 func getKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string, key string) (commonledger.QueryResult, error) {
 	logger.Debugf("Entering getKeyModificationFromTran()\n", namespace, key)
 
+	chdr, txRWSet, err := getTxRWSetFromTran(tranEnvelope)
+	if err != nil {
+		return nil, err
+	}
+	txID := chdr.TxId
+	timestamp := chdr.Timestamp
+
+	// look for the namespace and key by looping through the transaction's ReadWriteSets
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace == namespace {
+			// got the correct namespace, now find the key write
+			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+				if kvWrite.Key == key {
+					return &queryresult.KeyModification{TxId: txID, Value: kvWrite.Value,
+						Timestamp: timestamp, IsDelete: kvWrite.IsDelete}, nil
+				}
+			} // end keys loop
+			return nil, errors.New("key not found in namespace's writeset")
+		} // end if
+	} //end namespaces loop
+	return nil, errors.New("namespace not found in transaction's ReadWriteSets")
+
+}
+
+// getPrivateDataKeyModificationFromTran inspects a transaction for writes to the hash of a given private data key
+func getPrivateDataKeyModificationFromTran(tranEnvelope *common.Envelope, namespace string, collection string,
+	keyHash []byte) (*queryresult.PrivateDataKeyModification, error) {
+
+	chdr, txRWSet, err := getTxRWSetFromTran(tranEnvelope)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != namespace {
+			continue
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			if collHashedRWSet.CollectionName != collection {
+				continue
+			}
+			for _, kvWriteHash := range collHashedRWSet.HashedRwSet.HashedWrites {
+				if bytes.Equal(kvWriteHash.KeyHash, keyHash) {
+					return &queryresult.PrivateDataKeyModification{TxId: chdr.TxId, ValueHash: kvWriteHash.ValueHash,
+						Timestamp: chdr.Timestamp, IsDelete: kvWriteHash.IsDelete}, nil
+				}
+			}
+			return nil, errors.New("key hash not found in collection's hashed writeset")
+		}
+		return nil, errors.New("collection not found in namespace's hashed ReadWriteSets")
+	}
+	return nil, errors.New("namespace not found in transaction's ReadWriteSets")
+}
+
+// getTxRWSetFromTran returns the channel header and the read-write set of an endorser transaction
+func getTxRWSetFromTran(tranEnvelope *common.Envelope) (*common.ChannelHeader, *rwsetutil.TxRwSet, error) {
 	// extract action from the envelope
 	payload, err := putils.GetPayload(tranEnvelope)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	tx, err := putils.GetTransaction(payload.Data)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	_, respPayload, err := putils.GetPayloads(tx.Actions[0])
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	txID := chdr.TxId
-	timestamp := chdr.Timestamp
-
 	txRWSet := &rwsetutil.TxRwSet{}
 
 	// Get the Result from the Action and then Unmarshal
 	// it into a TxReadWriteSet using custom unmarshalling
 	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-
-	// look for the namespace and key by looping through the transaction's ReadWriteSets
-	for _, nsRWSet := range txRWSet.NsRwSets {
-		if nsRWSet.NameSpace == namespace {
-			// got the correct namespace, now find the key write
-			for _, kvWrite := range nsRWSet.KvRwSet.Writes {
-				if kvWrite.Key == key {
-					return &queryresult.KeyModification{TxId: txID, Value: kvWrite.Value,
-						Timestamp: timestamp, IsDelete: kvWrite.IsDelete}, nil
-				}
-			} // end keys loop
-			return nil, errors.New("key not found in namespace's writeset")
-		} // end if
-	} //end namespaces loop
-	return nil, errors.New("namespace not found in transaction's ReadWriteSets")
-
+	return chdr, txRWSet, nil
 }
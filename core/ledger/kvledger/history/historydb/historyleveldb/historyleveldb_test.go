@@ -11,15 +11,20 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/golang/protobuf/ptypes/timestamp"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	ledgerutil "github.com/hyperledger/fabric/common/ledger/util"
 	util2 "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
 	"github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -160,7 +165,7 @@ func TestHistory(t *testing.T) {
 	assert.NoError(t, err)
 	t.Logf("Inserted all 3 blocks")
 
-	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1, nil)
 	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
 
 	itr, err2 := qhistory.GetHistoryForKey("ns1", "key7")
@@ -226,7 +231,7 @@ func TestHistoryForInvalidTran(t *testing.T) {
 	err = env.testHistoryDB.Commit(block1)
 	assert.NoError(t, err)
 
-	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1, nil)
 	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
 
 	itr, err2 := qhistory.GetHistoryForKey("ns1", "key7")
@@ -243,7 +248,7 @@ func TestHistoryDisabled(t *testing.T) {
 	defer env.cleanup()
 	viper.Set("ledger.history.enableHistoryDatabase", "false")
 	//no need to pass blockstore into history executore, it won't be used in this test
-	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(nil)
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(nil, nil)
 	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
 	_, err2 := qhistory.GetHistoryForKey("ns1", "key7")
 	assert.Error(t, err2, "Error should have been returned for GetHistoryForKey() when history disabled")
@@ -314,13 +319,361 @@ func TestHistoryWithKeyContainingNilBytes(t *testing.T) {
 	err = env.testHistoryDB.Commit(block2)
 	assert.NoError(t, err)
 
-	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1)
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1, nil)
 	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
 	testutilVerifyResults(t, qhistory, "ns1", "key", []string{"value1", "value2"})
 	testutilVerifyResults(t, qhistory, "ns1", "key\x00\x01\x01\x15", []string{"dummyVal1"})
 	testutilVerifyResults(t, qhistory, "ns1", "\x00key\x00\x01\x01\x15", []string{"dummyVal2"})
 }
 
+func TestHistoryWithOptions(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+
+	// block1 has value1, block2 has value2 and value3, block3 has value4 and block4 has value5 for the key <key7>
+	// block3 has also a value for the key <key7\x00\x01\x01\x15>, that falls in the range of the history of <key7>
+	for _, blockValues := range [][]string{{"value1"}, {"value2", "value3"}, {"value4"}, {"value5"}} {
+		simulationResults := [][]byte{}
+		for _, value := range blockValues {
+			simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+			simulator.SetState("ns1", "key7", []byte(value))
+			if value == "value4" {
+				simulator.SetState("ns1", "key7\x00\x01\x01\x15", []byte("dummyVal"))
+			}
+			simulator.Done()
+			simRes, _ := simulator.GetTxSimulationResults()
+			pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+			simulationResults = append(simulationResults, pubSimResBytes)
+		}
+		block := bg.NextBlock(simulationResults)
+		assert.NoError(t, store1.AddBlock(block))
+		assert.NoError(t, env.testHistoryDB.Commit(block))
+	}
+
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1, nil)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+
+	testCases := []struct {
+		name             string
+		options          *ledger.HistoryQueryOptions
+		expectedVals     []string
+		expectedBookmark string
+	}{
+		{"all", &ledger.HistoryQueryOptions{}, []string{"value1", "value2", "value3", "value4", "value5"}, ""},
+		{"newestFirst", &ledger.HistoryQueryOptions{NewestFirst: true}, []string{"value5", "value4", "value3", "value2", "value1"}, ""},
+		{"blockRange", &ledger.HistoryQueryOptions{StartBlock: 2, EndBlock: 3}, []string{"value2", "value3", "value4"}, ""},
+		{"blockRangeNewestFirst", &ledger.HistoryQueryOptions{StartBlock: 2, EndBlock: 3, NewestFirst: true}, []string{"value4", "value3", "value2"}, ""},
+		{"startBlock", &ledger.HistoryQueryOptions{StartBlock: 3}, []string{"value4", "value5"}, ""},
+		{"endBlock", &ledger.HistoryQueryOptions{EndBlock: 1}, []string{"value1"}, ""},
+		{"emptyBlockRange", &ledger.HistoryQueryOptions{StartBlock: 5}, []string{}, ""},
+		{"limit", &ledger.HistoryQueryOptions{Limit: 2}, []string{"value1", "value2"}, "2:1"},
+		{"limitNewestFirst", &ledger.HistoryQueryOptions{Limit: 2, NewestFirst: true}, []string{"value5", "value4"}, "2:1"},
+		{"bookmark", &ledger.HistoryQueryOptions{Limit: 2, Bookmark: "2:1"}, []string{"value3", "value4"}, "4:0"},
+		{"bookmarkNewestFirst", &ledger.HistoryQueryOptions{Limit: 2, Bookmark: "2:1", NewestFirst: true}, []string{"value3", "value2"}, "1:0"},
+		{"lastPage", &ledger.HistoryQueryOptions{Limit: 2, Bookmark: "4:0"}, []string{"value5"}, ""},
+		{"bookmarkInBlockRange", &ledger.HistoryQueryOptions{Limit: 2, Bookmark: "2:1", EndBlock: 2}, []string{"value3"}, ""},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			itr, err := qhistory.GetHistoryForKeyWithOptions("ns1", "key7", testCase.options)
+			assert.NoError(t, err)
+			retrievedVals := []string{}
+			for {
+				kmod, err := itr.Next()
+				assert.NoError(t, err)
+				if kmod == nil {
+					break
+				}
+				retrievedVals = append(retrievedVals, string(kmod.(*queryresult.KeyModification).Value))
+			}
+			assert.Equal(t, testCase.expectedVals, retrievedVals)
+			assert.Equal(t, testCase.expectedBookmark, itr.GetBookmarkAndClose())
+		})
+	}
+
+	t.Run("timeRange", func(t *testing.T) {
+		itr, err := qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &ledger.HistoryQueryOptions{})
+		assert.NoError(t, err)
+		kmods := []*queryresult.KeyModification{}
+		for {
+			kmod, _ := itr.Next()
+			if kmod == nil {
+				break
+			}
+			kmods = append(kmods, kmod.(*queryresult.KeyModification))
+		}
+		itr.Close()
+		assert.Len(t, kmods, 5)
+
+		startTime, endTime := kmods[1].Timestamp, kmods[3].Timestamp
+		expectedVals := []string{}
+		for _, kmod := range kmods {
+			if compareTimestamps(kmod.Timestamp, startTime) >= 0 && compareTimestamps(kmod.Timestamp, endTime) <= 0 {
+				expectedVals = append(expectedVals, string(kmod.Value))
+			}
+		}
+		assert.Contains(t, expectedVals, "value2")
+		assert.Contains(t, expectedVals, "value4")
+
+		itr, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &ledger.HistoryQueryOptions{StartTime: startTime, EndTime: endTime})
+		assert.NoError(t, err)
+		defer itr.Close()
+		retrievedVals := []string{}
+		for {
+			kmod, _ := itr.Next()
+			if kmod == nil {
+				break
+			}
+			retrievedVals = append(retrievedVals, string(kmod.(*queryresult.KeyModification).Value))
+		}
+		assert.Equal(t, expectedVals, retrievedVals)
+	})
+
+	_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &ledger.HistoryQueryOptions{StartBlock: 3, EndBlock: 2})
+	assert.EqualError(t, err, "end block [2] of the history query is lower than the start block [3]")
+	_, err = qhistory.GetHistoryForKeyWithOptions("ns1", "key7", &ledger.HistoryQueryOptions{Bookmark: "invalid"})
+	assert.EqualError(t, err, "invalid bookmark [invalid] for the history query")
+}
+
+func TestHistoryWithTimeRange(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	_, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+
+	// block i has the value<i> for the key <key7>, written by a transaction with the timestamp 1000+i,
+	// except for the block 30, whose transaction has the timestamp 1012
+	previousHash := gb.Header.Hash()
+	for i := 1; i <= 40; i++ {
+		simulator, _ := env.txmgr.NewTxSimulator(util2.GenerateUUID())
+		simulator.SetState("ns1", "key7", []byte("value"+strconv.Itoa(i)))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		seconds := int64(1000 + i)
+		if i == 30 {
+			seconds = 1012
+		}
+		block := constructBlockWithTimestamp(t, uint64(i), previousHash, [][]byte{pubSimResBytes}, seconds)
+		assert.NoError(t, store1.AddBlock(block))
+		assert.NoError(t, env.testHistoryDB.Commit(block))
+		previousHash = block.Header.Hash()
+	}
+
+	values := func(blockNums ...int) []string {
+		vals := []string{}
+		for _, blockNum := range blockNums {
+			vals = append(vals, "value"+strconv.Itoa(blockNum))
+		}
+		return vals
+	}
+	at := func(seconds int64) *timestamp.Timestamp {
+		return &timestamp.Timestamp{Seconds: seconds}
+	}
+
+	testCases := []struct {
+		name            string
+		options         *ledger.HistoryQueryOptions
+		expectedVals    []string
+		expectedVisited int
+	}{
+		{"timeRange", &ledger.HistoryQueryOptions{StartTime: at(1010), EndTime: at(1015)},
+			values(10, 11, 12, 13, 14, 15, 30), 21},
+		{"timeRangeNewestFirst", &ledger.HistoryQueryOptions{StartTime: at(1010), EndTime: at(1015), NewestFirst: true},
+			values(30, 15, 14, 13, 12, 11, 10), 21},
+		{"timeRangeWithLimit", &ledger.HistoryQueryOptions{StartTime: at(1010), EndTime: at(1015), Limit: 2},
+			values(10, 11), 2},
+		{"timeRangeInBlockRange", &ledger.HistoryQueryOptions{StartTime: at(1020), EndTime: at(1025), StartBlock: 22},
+			values(22, 23, 24, 25), 4},
+		{"startTime", &ledger.HistoryQueryOptions{StartTime: at(1035)}, values(35, 36, 37, 38, 39, 40), 6},
+		{"endTime", &ledger.HistoryQueryOptions{EndTime: at(1003)}, values(1, 2, 3), 3},
+		{"emptyTimeRange", &ledger.HistoryQueryOptions{StartTime: at(2000), EndTime: at(3000)}, values(), 0},
+	}
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			blockStore := &countingBlockStore{BlockStore: store1}
+			qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(blockStore, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedVals, testutilRetrieveValues(t, qhistory, testCase.options))
+			assert.Equal(t, testCase.expectedVisited, blockStore.retrievedTxs)
+		})
+	}
+
+	t.Run("notIndexedBlocks", func(t *testing.T) {
+		// the blocks committed before the block time index was maintained are scanned regardless of the time range
+		historyDB := env.testHistoryDB.(*historyDB)
+		assert.NoError(t, historyDB.db.Put(blockTimeIndexFirstBlockKey, ledgerutil.EncodeOrderPreservingVarUint64(25), true))
+		blockStore := &countingBlockStore{BlockStore: store1}
+		qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(blockStore, nil)
+		assert.NoError(t, err)
+
+		options := &ledger.HistoryQueryOptions{StartTime: at(1010), EndTime: at(1015)}
+		assert.Equal(t, values(10, 11, 12, 13, 14, 15, 30), testutilRetrieveValues(t, qhistory, options))
+		assert.Equal(t, 30, blockStore.retrievedTxs)
+
+		blockStore.retrievedTxs = 0
+		options = &ledger.HistoryQueryOptions{EndTime: at(1003)}
+		assert.Equal(t, values(1, 2, 3), testutilRetrieveValues(t, qhistory, options))
+		assert.Equal(t, 24, blockStore.retrievedTxs)
+	})
+}
+
+// countingBlockStore counts the transactions retrieved by the history queries
+type countingBlockStore struct {
+	blkstorage.BlockStore
+	retrievedTxs int
+}
+
+func (s *countingBlockStore) RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
+	s.retrievedTxs++
+	return s.BlockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+}
+
+// constructBlockWithTimestamp constructs a block whose transactions have the given timestamp
+func constructBlockWithTimestamp(t *testing.T, blockNum uint64, previousHash []byte, simulationResults [][]byte, seconds int64) *common.Block {
+	envs := []*common.Envelope{}
+	for _, simRes := range simulationResults {
+		env, _, err := testutil.ConstructTransaction(t, simRes, "", false)
+		assert.NoError(t, err)
+		payload, err := putils.GetPayload(env)
+		assert.NoError(t, err)
+		chdr, err := putils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		assert.NoError(t, err)
+		chdr.Timestamp = &timestamp.Timestamp{Seconds: seconds}
+		payload.Header.ChannelHeader = putils.MarshalOrPanic(chdr)
+		env.Payload = putils.MarshalOrPanic(payload)
+		envs = append(envs, env)
+	}
+	return testutil.NewBlock(envs, blockNum, previousHash)
+}
+
+func testutilRetrieveValues(t *testing.T, hqe ledger.HistoryQueryExecutor, options *ledger.HistoryQueryOptions) []string {
+	itr, err := hqe.GetHistoryForKeyWithOptions("ns1", "key7", options)
+	assert.NoError(t, err)
+	defer itr.Close()
+	retrievedVals := []string{}
+	for {
+		kmod, err := itr.Next()
+		assert.NoError(t, err)
+		if kmod == nil {
+			break
+		}
+		retrievedVals = append(retrievedVals, string(kmod.(*queryresult.KeyModification).Value))
+	}
+	return retrievedVals
+}
+
+func TestPrivateDataHistory(t *testing.T) {
+	env := newTestHistoryEnv(t)
+	defer env.cleanup()
+	provider := env.testBlockStorageEnv.provider
+	ledger1id := "ledger1"
+	store1, err := provider.OpenBlockStore(ledger1id)
+	assert.NoError(t, err, "Error upon provider.OpenBlockStore()")
+	defer store1.Shutdown()
+
+	bg, gb := testutil.NewBlockGenerator(t, ledger1id, false)
+	assert.NoError(t, store1.AddBlock(gb))
+	assert.NoError(t, env.testHistoryDB.Commit(gb))
+
+	// block1 writes value1, block2 writes value2 and block3 deletes the private data key <key1>.
+	// The peer holds the private data of block1 only
+	pvtDataRetriever := &testPvtDataRetriever{pvtData: map[uint64][]*ledger.TxPvtData{}}
+	for i, value := range []string{"value1", "value2", ""} {
+		rwsetBuilder := rwsetutil.NewRWSetBuilder()
+		if value != "" {
+			rwsetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", []byte(value))
+		} else {
+			rwsetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll1", "key1", nil)
+		}
+		rwsetBuilder.AddToPvtAndHashedWriteSet("ns1", "coll2", "key1", []byte("otherCollValue"))
+		simRes, _ := rwsetBuilder.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		block := bg.NextBlock([][]byte{pubSimResBytes})
+		assert.NoError(t, store1.AddBlock(block))
+		assert.NoError(t, env.testHistoryDB.Commit(block))
+		if i == 0 {
+			pvtDataRetriever.pvtData[block.Header.Number] = []*ledger.TxPvtData{
+				{SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults},
+			}
+		}
+	}
+
+	verifyResults := func(qhistory ledger.HistoryQueryExecutor, expectedVals []string) {
+		itr, err := qhistory.GetPrivateDataHistoryForKey("ns1", "coll1", "key1")
+		assert.NoError(t, err)
+		defer itr.Close()
+		kmods := []*queryresult.PrivateDataKeyModification{}
+		for {
+			kmod, err := itr.Next()
+			assert.NoError(t, err)
+			if kmod == nil {
+				break
+			}
+			kmods = append(kmods, kmod.(*queryresult.PrivateDataKeyModification))
+		}
+		assert.Len(t, kmods, 3)
+		assert.Equal(t, util.ComputeHash([]byte("value1")), kmods[0].ValueHash)
+		assert.Equal(t, util.ComputeHash([]byte("value2")), kmods[1].ValueHash)
+		assert.True(t, kmods[2].IsDelete)
+		retrievedVals := []string{}
+		for _, kmod := range kmods {
+			assert.NotEmpty(t, kmod.TxId)
+			assert.NotNil(t, kmod.Timestamp)
+			retrievedVals = append(retrievedVals, string(kmod.Value))
+		}
+		assert.Equal(t, expectedVals, retrievedVals)
+	}
+
+	qhistory, err := env.testHistoryDB.NewHistoryQueryExecutor(store1, pvtDataRetriever)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+	verifyResults(qhistory, []string{"value1", "", ""})
+
+	// without the private data, only the hashes are returned
+	qhistory, err = env.testHistoryDB.NewHistoryQueryExecutor(store1, nil)
+	assert.NoError(t, err, "Error upon NewHistoryQueryExecutor")
+	verifyResults(qhistory, []string{"", "", ""})
+
+	// the private data history does not include the public data of the key
+	itr, err := qhistory.GetHistoryForKey("ns1", "key1")
+	assert.NoError(t, err)
+	kmod, err := itr.Next()
+	assert.NoError(t, err)
+	assert.Nil(t, kmod)
+	itr.Close()
+
+	itr, err = qhistory.GetPrivateDataHistoryForKey("ns1", "coll3", "key1")
+	assert.NoError(t, err)
+	kmod, err = itr.Next()
+	assert.NoError(t, err)
+	assert.Nil(t, kmod)
+	itr.Close()
+}
+
+type testPvtDataRetriever struct {
+	pvtData map[uint64][]*ledger.TxPvtData
+}
+
+func (r *testPvtDataRetriever) GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error) {
+	return r.pvtData[blockNum], nil
+}
+
 func testutilVerifyResults(t *testing.T, hqe ledger.HistoryQueryExecutor, ns, key string, expectedVals []string) {
 	itr, err := hqe.GetHistoryForKey(ns, key)
 	assert.NoError(t, err, "Error upon GetHistoryForKey()")
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -41,6 +42,19 @@ type kvLedger struct {
 	configHistoryRetriever ledger.ConfigHistoryRetriever
 	blockAPIsRWLock        *sync.RWMutex
 	stats                  *ledgerStats
+	// stateDB and configHistoryMgr are used directly only for generating snapshots
+	stateDB          privacyenabledstate.DB
+	configHistoryMgr confighistory.Mgr
+	// with the commit pipeline enabled, the state and history databases are updated with a block
+	// asynchronously. The pending channel, guarded by pendingHistoryCommitLock, is closed
+	// once the update of the history database with the last block is done
+	commitPipelineEnabled    bool
+	pendingHistoryCommitLock sync.Mutex
+	pendingHistoryCommit     chan struct{}
+	// the blocks are pruned after the commit of a block, retaining the pruningRetainBlocks most
+	// recent blocks, if pruningRetainBlocks is not zero
+	pruningRetainBlocks uint64
+	pruningArchiveDir   string
 }
 
 // NewKVLedger constructs new `KVLedger`
@@ -58,15 +72,20 @@ func newKVLedger(
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying
 	// id store, blockstore, txmgr (state database), history database
-	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, historyDB: historyDB, blockAPIsRWLock: &sync.RWMutex{}}
+	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, historyDB: historyDB, blockAPIsRWLock: &sync.RWMutex{},
+		stateDB: versionedDB, configHistoryMgr: configHistoryMgr, commitPipelineEnabled: ledgerconfig.IsCommitPipelineEnabled(),
+		pruningRetainBlocks: ledgerconfig.GetPruningRetainBlocks(), pruningArchiveDir: ledgerconfig.GetPruningArchiveDir()}
 
 	// TODO Move the function `GetChaincodeEventListener` to ledger interface and
 	// this functionality of regiserting for events to ledgermgmt package so that this
 	// is reused across other future ledger implementations
+	// The event manager is not initialized when the ledger is opened outside of the ledger
+	// management, such as by the tools that operate on the ledger data offline
 	ccEventListener := versionedDB.GetChaincodeEventListener()
-	logger.Debugf("Register state db for chaincode lifecycle events: %t", ccEventListener != nil)
-	if ccEventListener != nil {
-		cceventmgmt.GetMgr().Register(ledgerID, ccEventListener)
+	ccEventMgr := cceventmgmt.GetMgr()
+	logger.Debugf("Register state db for chaincode lifecycle events: %t", ccEventListener != nil && ccEventMgr != nil)
+	if ccEventListener != nil && ccEventMgr != nil {
+		ccEventMgr.Register(ledgerID, ccEventListener)
 	}
 	btlPolicy := pvtdatapolicy.ConstructBTLPolicy(&collectionInfoRetriever{l, ccInfoProvider})
 	if err := l.initTxMgr(versionedDB, stateListeners, btlPolicy, bookkeeperProvider, ccInfoProvider); err != nil {
@@ -113,49 +132,6 @@ func (l *kvLedger) recoverDBs() error {
 	return nil
 }
 
-func (l *kvLedger) syncStateAndHistoryDBWithBlockstore() error {
-	//If there is no block in blockstorage, nothing to recover.
-	info, _ := l.blockStore.GetBlockchainInfo()
-	if info.Height == 0 {
-		logger.Debug("Block storage is empty.")
-		return nil
-	}
-	lastAvailableBlockNum := info.Height - 1
-	recoverables := []recoverable{l.txtmgmt, l.historyDB}
-	recoverers := []*recoverer{}
-	for _, recoverable := range recoverables {
-		recoverFlag, firstBlockNum, err := recoverable.ShouldRecover(lastAvailableBlockNum)
-		if err != nil {
-			return err
-		}
-		if recoverFlag {
-			recoverers = append(recoverers, &recoverer{firstBlockNum, recoverable})
-		}
-	}
-	if len(recoverers) == 0 {
-		return nil
-	}
-	if len(recoverers) == 1 {
-		return l.recommitLostBlocks(recoverers[0].firstBlockNum, lastAvailableBlockNum, recoverers[0].recoverable)
-	}
-
-	// both dbs need to be recovered
-	if recoverers[0].firstBlockNum > recoverers[1].firstBlockNum {
-		// swap (put the lagger db at 0 index)
-		recoverers[0], recoverers[1] = recoverers[1], recoverers[0]
-	}
-	if recoverers[0].firstBlockNum != recoverers[1].firstBlockNum {
-		// bring the lagger db equal to the other db
-		if err := l.recommitLostBlocks(recoverers[0].firstBlockNum, recoverers[1].firstBlockNum-1,
-			recoverers[0].recoverable); err != nil {
-			return err
-		}
-	}
-	// get both the db upto block storage
-	return l.recommitLostBlocks(recoverers[1].firstBlockNum, lastAvailableBlockNum,
-		recoverers[0].recoverable, recoverers[1].recoverable)
-}
-
 func (l *kvLedger) syncStateDBWithPvtdatastore() error {
 	// TODO: So far, the design philosophy was that the scope of block storage is
 	// limited to storing and retrieving blocks data with certain guarantees and statedb is
@@ -179,26 +155,6 @@ func (l *kvLedger) syncStateDBWithPvtdatastore() error {
 	return nil
 }
 
-//recommitLostBlocks retrieves blocks in specified range and commit the write set to either
-//state DB or history DB or both
-func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64, recoverables ...recoverable) error {
-	logger.Infof("Recommitting lost blocks - firstBlockNum=%d, lastBlockNum=%d, recoverables=%#v", firstBlockNum, lastBlockNum, recoverables)
-	var err error
-	var blockAndPvtdata *ledger.BlockAndPvtData
-	for blockNumber := firstBlockNum; blockNumber <= lastBlockNum; blockNumber++ {
-		if blockAndPvtdata, err = l.GetPvtDataAndBlockByNum(blockNumber, nil); err != nil {
-			return err
-		}
-		for _, r := range recoverables {
-			if err := r.CommitLostBlock(blockAndPvtdata); err != nil {
-				return err
-			}
-		}
-	}
-	logger.Infof("Recommitted lost blocks - firstBlockNum=%d, lastBlockNum=%d, recoverables=%#v", firstBlockNum, lastBlockNum, recoverables)
-	return nil
-}
-
 // GetTransactionByID retrieves a transaction by id
 func (l *kvLedger) GetTransactionByID(txID string) (*peer.ProcessedTransaction, error) {
 	tranEnv, err := l.blockStore.RetrieveTxByID(txID)
@@ -215,6 +171,14 @@ func (l *kvLedger) GetTransactionByID(txID string) (*peer.ProcessedTransaction,
 	return processedTran, nil
 }
 
+// TxIDExists returns true if a transaction with the given id has been committed to the ledger
+func (l *kvLedger) TxIDExists(txID string) (bool, error) {
+	exists, err := l.blockStore.TxIDExists(txID)
+	l.blockAPIsRWLock.RLock()
+	l.blockAPIsRWLock.RUnlock()
+	return exists, err
+}
+
 // GetBlockchainInfo returns basic info about blockchain
 func (l *kvLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 	bcInfo, err := l.blockStore.GetBlockchainInfo()
@@ -266,9 +230,27 @@ func (l *kvLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode
 	return txValidationCode, err
 }
 
-//Prune prunes the blocks/transactions that satisfy the given policy
+// Prune prunes the blocks below the RetainFromBlockNum of the given *ledger.BlockPrunePolicy. The
+// block store retains the last config block even if it is below RetainFromBlockNum, as the peer
+// retrieves it when the channel is initialized. The commit of blocks is blocked while the blocks are pruned
 func (l *kvLedger) Prune(policy commonledger.PrunePolicy) error {
-	return errors.New("not yet implemented")
+	blockPrunePolicy, ok := policy.(*ledger.BlockPrunePolicy)
+	if !ok {
+		return errors.Errorf("unsupported prune policy type [%T]", policy)
+	}
+	l.blockAPIsRWLock.Lock()
+	defer l.blockAPIsRWLock.Unlock()
+	logger.Infof("[%s] Pruning the blocks below block [%d]", l.ledgerID, blockPrunePolicy.RetainFromBlockNum)
+	return l.pruneBlocks(blockPrunePolicy.RetainFromBlockNum, blockPrunePolicy.ArchiveDir)
+}
+
+// pruneBlocks prunes the blocks below retainFromBlockNum. If archiveDir is set, the pruned block files
+// are moved to its sub-directory named after the ledger. The caller is expected to hold the blockAPIsRWLock
+func (l *kvLedger) pruneBlocks(retainFromBlockNum uint64, archiveDir string) error {
+	if archiveDir != "" {
+		archiveDir = filepath.Join(archiveDir, l.ledgerID)
+	}
+	return l.blockStore.Prune(retainFromBlockNum, archiveDir)
 }
 
 // NewTxSimulator returns new `ledger.TxSimulator`
@@ -288,10 +270,17 @@ func (l *kvLedger) NewQueryExecutor() (ledger.QueryExecutor, error) {
 // Any synchronization should be performed at the implementation level if required
 // Pass the ledger blockstore so that historical values can be looked up from the chain
 func (l *kvLedger) NewHistoryQueryExecutor() (ledger.HistoryQueryExecutor, error) {
-	return l.historyDB.NewHistoryQueryExecutor(l.blockStore)
+	l.waitForHistoryCommit()
+	return l.historyDB.NewHistoryQueryExecutor(l.blockStore, l.blockStore)
 }
 
-// CommitWithPvtData commits the block and the corresponding pvt data in an atomic operation
+// CommitWithPvtData commits the block and the corresponding pvt data in an atomic operation.
+// If the commit pipeline is enabled, the state and history databases are updated with the block
+// in the background, overlapping with the validation of the next block and its commit to the block
+// storage. The next block is validated against the state database overlaid with the updates of
+// the block, and the simulators and queries started after the function returns wait for the state
+// database to be updated. A crash before the databases are updated is recovered from the block
+// storage when the ledger is opened, see recovery.go
 func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData) error {
 	var err error
 	block := pvtdataAndBlock.Block
@@ -316,18 +305,20 @@ func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData) er
 
 	startCommitState := time.Now()
 	logger.Debugf("[%s] Committing block [%d] transactions to state database", l.ledgerID, blockNo)
-	if err = l.txtmgmt.Commit(); err != nil {
+	if l.commitPipelineEnabled {
+		// this waits for the update of the state database with the preceding block, if still running
+		err = l.txtmgmt.StartCommit()
+	} else {
+		err = l.txtmgmt.Commit()
+	}
+	if err != nil {
 		panic(errors.WithMessage(err, "error during commit to txmgr"))
 	}
 	elapsedCommitState := time.Since(startCommitState)
 
-	// History database could be written in parallel with state and/or async as a future optimization,
-	// although it has not been a bottleneck...no need to clutter the log with elapsed duration.
+	// History database has not been a bottleneck...no need to clutter the log with elapsed duration.
 	if ledgerconfig.IsHistoryDBEnabled() {
-		logger.Debugf("[%s] Committing block [%d] transactions to history database", l.ledgerID, blockNo)
-		if err := l.historyDB.Commit(block); err != nil {
-			panic(errors.WithMessage(err, "Error during commit to history db"))
-		}
+		l.commitHistory(block)
 	}
 
 	elapsedCommitWithPvtData := time.Since(startBlockProcessing)
@@ -345,9 +336,51 @@ func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData) er
 		elapsedCommitState,
 		txstatsInfo,
 	)
+
+	// The block is committed regardless of the pruning, which is attempted again after the next block
+	if l.pruningRetainBlocks > 0 && blockNo >= l.pruningRetainBlocks {
+		if err := l.pruneBlocks(blockNo+1-l.pruningRetainBlocks, l.pruningArchiveDir); err != nil {
+			logger.Errorf("[%s] Error pruning the blocks after committing block [%d]: %s", l.ledgerID, blockNo, err)
+		}
+	}
 	return nil
 }
 
+// commitHistory updates the history database with the block, in the background if the commit
+// pipeline is enabled. At most one block is pending, as the history database commits the blocks in order
+func (l *kvLedger) commitHistory(block *common.Block) {
+	commit := func() {
+		logger.Debugf("[%s] Committing block [%d] transactions to history database", l.ledgerID, block.Header.Number)
+		if err := l.historyDB.Commit(block); err != nil {
+			panic(errors.WithMessage(err, "Error during commit to history db"))
+		}
+	}
+	if !l.commitPipelineEnabled {
+		commit()
+		return
+	}
+
+	l.waitForHistoryCommit()
+	done := make(chan struct{})
+	l.pendingHistoryCommitLock.Lock()
+	l.pendingHistoryCommit = done
+	l.pendingHistoryCommitLock.Unlock()
+	go func() {
+		defer close(done)
+		commit()
+	}()
+}
+
+// waitForHistoryCommit waits for the pipelined update of the history database with the last block, if any
+func (l *kvLedger) waitForHistoryCommit() {
+	l.pendingHistoryCommitLock.Lock()
+	done := l.pendingHistoryCommit
+	l.pendingHistoryCommitLock.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
 func (l *kvLedger) updateBlockStats(
 	blockNum uint64,
 	blockProcessingTime time.Duration,
@@ -436,6 +469,7 @@ func (l *kvLedger) GetMissingPvtDataTracker() (ledger.MissingPvtDataTracker, err
 
 // Close closes `KVLedger`
 func (l *kvLedger) Close() {
+	l.waitForHistoryCommit()
 	l.blockStore.Shutdown()
 	l.txtmgmt.Shutdown()
 }
@@ -9,8 +9,11 @@ package kvledger
 import (
 	"bytes"
 	"fmt"
+	"path/filepath"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/confighistory"
@@ -50,12 +53,19 @@ type Provider struct {
 	initializer         *ledger.Initializer
 	collElgNotifier     *collElgNotifier
 	stats               *stats
+	fileLock            *leveldbhelper.FileLock
 }
 
 // NewProvider instantiates a new Provider.
 // This is not thread-safe and assumed to be synchronized be the caller
 func NewProvider() (ledger.PeerLedgerProvider, error) {
 	logger.Info("Initializing ledger provider")
+	// Acquire the file lock so that the offline ledger commands (such as rollback)
+	// cannot operate on the ledger data while the ledger is in use
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return nil, errors.WithMessage(err, "as another peer node command is executing, wait for that command to complete its execution or terminate it before starting the peer")
+	}
 	// Initialize the ID store (inventory of chainIds/ledgerIds)
 	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
 	ledgerStoreProvider := ledgerstorage.NewProvider()
@@ -63,7 +73,7 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 	historydbProvider := historyleveldb.NewHistoryDBProvider()
 	logger.Info("ledger provider Initialized")
 	provider := &Provider{idStore, ledgerStoreProvider,
-		nil, historydbProvider, nil, nil, nil, nil, nil, nil}
+		nil, historydbProvider, nil, nil, nil, nil, nil, nil, fileLock}
 	return provider, nil
 }
 
@@ -131,6 +141,84 @@ func (provider *Provider) Create(genesisBlock *common.Block) (ledger.PeerLedger,
 	return lgr, nil
 }
 
+// CreateFromSnapshot implements the corresponding method from interface ledger.PeerLedgerProvider
+// As in the function 'Create', the under construction flag is set before importing the snapshot and is
+// removed upon a successful import. The block store is imported last, so that a crash in between leaves
+// the ledger with an empty block store and the flag is unset by the function 'recoverUnderConstructionLedger'.
+// The state db and the config history already imported by a failed attempt are retained, as importing
+// the same snapshot again is expected to produce the same contents
+func (provider *Provider) CreateFromSnapshot(snapshotDir string) (ledger.PeerLedger, string, error) {
+	metadata, snapshotInfo, err := loadSnapshot(snapshotDir)
+	if err != nil {
+		return nil, "", err
+	}
+	ledgerID := metadata.ChannelName
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, "", err
+	}
+	if exists {
+		return nil, "", ErrLedgerIDExists
+	}
+	if err = provider.idStore.setUnderConstructionFlag(ledgerID); err != nil {
+		return nil, "", err
+	}
+	if err := provider.importFromSnapshot(ledgerID, snapshotDir, snapshotInfo); err != nil {
+		logger.Errorf("Error importing ledger [%s] from the snapshot in dir [%s]. Unsetting under construction flag. Error: %+v", ledgerID, snapshotDir, err)
+		panicOnErr(provider.runCleanup(ledgerID), "Error running cleanup for ledger id [%s]", ledgerID)
+		panicOnErr(provider.idStore.unsetUnderConstructionFlag(), "Error while unsetting under construction flag")
+		return nil, "", err
+	}
+	lgr, err := provider.openInternal(ledgerID)
+	if err != nil {
+		return nil, "", err
+	}
+	panicOnErr(provider.idStore.createLedgerID(ledgerID, snapshotInfo.LastConfigBlock), "Error while marking ledger as created")
+	return lgr, ledgerID, nil
+}
+
+func (provider *Provider) importFromSnapshot(ledgerID, snapshotDir string, snapshotInfo *blkstorage.SnapshotInfo) error {
+	logger.Infof("Importing state of ledger [%s] from the snapshot", ledgerID)
+	vDB, err := provider.vdbProvider.GetDBHandle(ledgerID)
+	if err != nil {
+		return err
+	}
+	savepoint, err := vDB.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+	// the state db is not empty if it was imported by a previous attempt that failed afterwards
+	if expectedSavepoint := stateSavepoint(snapshotInfo.LastBlock); savepoint == nil || savepoint.Compare(expectedSavepoint) != 0 {
+		r, err := openSnapshotFile(filepath.Join(snapshotDir, snapshotStateFile))
+		if err != nil {
+			return err
+		}
+		if err := vDB.ImportFromSnapshot(&stateImporter{r}, expectedSavepoint); err != nil {
+			r.close()
+			return err
+		}
+		r.close()
+	}
+
+	logger.Infof("Importing config history of ledger [%s] from the snapshot", ledgerID)
+	r, err := openSnapshotFile(filepath.Join(snapshotDir, snapshotConfigHistoryFile))
+	if err != nil {
+		return err
+	}
+	defer r.close()
+	if err := provider.configHistoryMgr.ImportConfigHistory(ledgerID, &configHistoryImporter{r}); err != nil {
+		return err
+	}
+
+	logger.Infof("Importing block store of ledger [%s] from the snapshot", ledgerID)
+	txIDsReader, err := openSnapshotFile(filepath.Join(snapshotDir, snapshotTxIDsFile))
+	if err != nil {
+		return err
+	}
+	defer txIDsReader.close()
+	return provider.ledgerStoreProvider.ImportFromSnapshot(ledgerID, snapshotInfo, &txIDsImporter{txIDsReader})
+}
+
 // Open implements the corresponding method from interface ledger.PeerLedgerProvider
 func (provider *Provider) Open(ledgerID string) (ledger.PeerLedger, error) {
 	logger.Debugf("Open() opening kvledger: %s", ledgerID)
@@ -197,6 +285,7 @@ func (provider *Provider) Close() {
 	provider.historydbProvider.Close()
 	provider.bookkeepingProvider.Close()
 	provider.configHistoryMgr.Close()
+	provider.fileLock.Unlock()
 }
 
 // recoverUnderConstructionLedger checks whether the under construction flag is set - this would be the case
@@ -216,8 +305,21 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 	panicOnErr(err, "Error while opening under construction ledger [%s]", ledgerID)
 	bcInfo, err := ledger.GetBlockchainInfo()
 	panicOnErr(err, "Error while getting blockchain info for the under construction ledger [%s]", ledgerID)
+	snapshotInfo, err := ledger.(*kvLedger).blockStore.GetBootstrappingSnapshotInfo()
+	panicOnErr(err, "Error while getting bootstrapping snapshot info for the under construction ledger [%s]", ledgerID)
 	ledger.Close()
 
+	if snapshotInfo != nil {
+		if bcInfo.Height != snapshotInfo.LastBlock.Header.Number+1 {
+			panic(errors.Errorf(
+				"data inconsistency: under construction flag is set for ledger [%s] bootstrapped from the snapshot of block [%d] while the height of the blockchain is [%d]",
+				ledgerID, snapshotInfo.LastBlock.Header.Number, bcInfo.Height))
+		}
+		logger.Infof("Ledger was bootstrapped from a snapshot. Hence, marking the peer ledger as created")
+		panicOnErr(provider.idStore.createLedgerID(ledgerID, snapshotInfo.LastConfigBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
+		return
+	}
+
 	switch bcInfo.Height {
 	case 0:
 		logger.Infof("Genesis block was not committed. Hence, the peer ledger not created. unsetting the under construction flag")
@@ -260,12 +362,62 @@ func panicOnErr(err error, mgsFormat string, args ...interface{}) {
 ///////////////////////////////////////////////////////////////////////
 type idStore struct {
 	db *leveldbhelper.DB
+	// encrypter encrypts the genesis blocks of the ledgers, if the encryption is enabled
+	encrypter *encryption.Encrypter
 }
 
 func openIDStore(path string) *idStore {
 	db := leveldbhelper.CreateDB(&leveldbhelper.Conf{DBPath: path})
 	db.Open()
-	return &idStore{db}
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		panic(fmt.Sprintf("Error initializing the encryption of the ledger id store: %s", err))
+	}
+	s := &idStore{db, encrypter}
+	if err := s.encryptGenesisBlocks(); err != nil {
+		panic(fmt.Sprintf("Error encrypting the genesis blocks in the ledger id store: %s", err))
+	}
+	return s
+}
+
+// encryptGenesisBlocks encrypts with the current key the genesis blocks which are plain, as they
+// were stored before the encryption was enabled, or encrypted with a previous key. The genesis
+// blocks are marshaled protobuf messages, which are told apart from the encrypted ones
+func (s *idStore) encryptGenesisBlocks() error {
+	if s.encrypter == nil {
+		return nil
+	}
+	batch := &leveldb.Batch{}
+	itr := s.db.GetIterator(ledgerKeyPrefix, append(ledgerKeyPrefix, 0xff))
+	for itr.Next() {
+		val := itr.Value()
+		if encryption.IsEncrypted(val) && s.encrypter.IsCurrent(val) {
+			continue
+		}
+		if encryption.IsEncrypted(val) {
+			plaintext, err := s.encrypter.Decrypt(val)
+			if err != nil {
+				itr.Release()
+				return err
+			}
+			val = plaintext
+		}
+		encrypted, err := s.encrypter.Encrypt(val)
+		if err != nil {
+			itr.Release()
+			return err
+		}
+		batch.Put(append([]byte{}, itr.Key()...), encrypted)
+	}
+	itr.Release()
+	if err := itr.Error(); err != nil {
+		return errors.Wrap(err, "error iterating over the ledger ids")
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+	logger.Infof("Encrypting [%d] genesis blocks in the ledger id store with the current key", batch.Len())
+	return s.db.WriteBatch(batch, true)
 }
 
 func (s *idStore) setUnderConstructionFlag(ledgerID string) error {
@@ -297,6 +449,11 @@ func (s *idStore) createLedgerID(ledgerID string, gb *common.Block) error {
 	if val, err = proto.Marshal(gb); err != nil {
 		return err
 	}
+	if s.encrypter != nil {
+		if val, err = s.encrypter.Encrypt(val); err != nil {
+			return err
+		}
+	}
 	batch := &leveldb.Batch{}
 	batch.Put(key, val)
 	batch.Delete(underConstructionLedgerKey)
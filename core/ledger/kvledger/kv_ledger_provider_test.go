@@ -18,14 +18,18 @@ package kvledger
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/sw"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/common/util"
 	lgr "github.com/hyperledger/fabric/core/ledger"
@@ -36,6 +40,7 @@ import (
 	putils "github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLedgerProvider(t *testing.T) {
@@ -137,6 +142,56 @@ func TestRecovery(t *testing.T) {
 
 }
 
+func TestIDStoreEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "idstore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	require.NoError(t, err)
+	newEncrypter := func() *encryption.Encrypter {
+		ski, err := encryption.GenerateKey(csp)
+		require.NoError(t, err)
+		encrypter, err := encryption.NewEncrypter(csp, encryption.NewKeyProvider(csp, ski))
+		require.NoError(t, err)
+		return encrypter
+	}
+	gb, err := configtxtest.MakeGenesisBlock(constructTestLedgerID(1))
+	require.NoError(t, err)
+	gbBytes, err := proto.Marshal(gb)
+	require.NoError(t, err)
+
+	// the genesis block stored before the encryption was enabled is encrypted
+	db := leveldbhelper.CreateDB(&leveldbhelper.Conf{DBPath: dir})
+	db.Open()
+	defer db.Close()
+	s := &idStore{db: db}
+	require.NoError(t, s.createLedgerID(constructTestLedgerID(1), gb))
+	s.encrypter = newEncrypter()
+	require.NoError(t, s.encryptGenesisBlocks())
+	require.NoError(t, s.createLedgerID(constructTestLedgerID(2), gb))
+	for _, ledgerID := range []string{constructTestLedgerID(1), constructTestLedgerID(2)} {
+		val, err := db.Get(s.encodeLedgerKey(ledgerID))
+		require.NoError(t, err)
+		assert.True(t, s.encrypter.IsCurrent(val))
+	}
+
+	// and re-encrypted once the key is rotated
+	previousEncrypter := s.encrypter
+	s.encrypter = newEncrypter()
+	require.NoError(t, s.encryptGenesisBlocks())
+	for _, ledgerID := range []string{constructTestLedgerID(1), constructTestLedgerID(2)} {
+		val, err := db.Get(s.encodeLedgerKey(ledgerID))
+		require.NoError(t, err)
+		assert.False(t, previousEncrypter.IsCurrent(val))
+		plaintext, err := s.encrypter.Decrypt(val)
+		require.NoError(t, err)
+		assert.Equal(t, gbBytes, plaintext)
+	}
+	ids, err := s.getAllLedgerIds()
+	require.NoError(t, err)
+	assert.Equal(t, []string{constructTestLedgerID(1), constructTestLedgerID(2)}, ids)
+}
+
 func TestMultipleLedgerBasicRW(t *testing.T) {
 	env := newTestEnv(t)
 	defer env.cleanup()
@@ -7,7 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
@@ -15,6 +17,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/util"
 	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/history/historydb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
@@ -374,6 +377,118 @@ func testSyncStateAndHistoryDBWithBlockstore(t *testing.T) {
 	)
 }
 
+func TestKVLedgerCommitPipeline(t *testing.T) {
+	viper.Set("ledger.state.enableCommitPipeline", true)
+	defer viper.Set("ledger.state.enableCommitPipeline", false)
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProviderWithCollectionConfig(t,
+		"ns", map[string]uint64{"coll": 0},
+	)
+	testLedgerid := "testLedger"
+	bg, gb := testutil.NewBlockGenerator(t, testLedgerid, false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	kvl := ledger.(*kvLedger)
+	assert.True(t, kvl.commitPipelineEnabled)
+	kvl.waitForHistoryCommit()
+	releaseHistoryCommit := make(chan bool, 1)
+	kvl.historyDB = &blockingHistoryDB{HistoryDB: kvl.historyDB, release: releaseHistoryCommit}
+
+	// the commit returns while the history db is still being updated with the block, and the state db
+	// possibly as well. The simulators started once the commit returns wait for the state db
+	blockAndPvtdata1 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk1",
+		map[string]string{"key1": "value1.1", "key2": "value2.1"},
+		map[string]string{"key1": "pvtValue1.1", "key2": "pvtValue2.1"})
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata1))
+	checkBCSummaryForTest(t, ledger,
+		&bcSummary{
+			bcInfo: &common.BlockchainInfo{Height: 2,
+				CurrentBlockHash:  blockAndPvtdata1.Block.Header.Hash(),
+				PreviousBlockHash: gb.Header.Hash()},
+			stateDBKVs:    map[string]string{"key1": "value1.1", "key2": "value2.1"},
+			stateDBPvtKVs: map[string]string{"key1": "pvtValue1.1", "key2": "pvtValue2.1"},
+		},
+	)
+	assert.NoError(t, kvl.txtmgmt.WaitForCommit())
+	checkBCSummaryForTest(t, ledger, &bcSummary{stateDBSavePoint: uint64(1)})
+	historySavepoint, err := kvl.historyDB.GetLastSavepoint()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), historySavepoint.BlockNum)
+	releaseHistoryCommit <- true
+
+	blockAndPvtdata2 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk2",
+		map[string]string{"key1": "value1.2", "key2": "value2.2"},
+		map[string]string{"key1": "pvtValue1.2", "key2": "pvtValue2.2"})
+	releaseHistoryCommit <- true
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata2))
+	checkHistoryDBForTest(t, ledger, "key1", []string{"value1.1", "value1.2"})
+	assert.NoError(t, kvl.txtmgmt.WaitForCommit())
+	checkBCSummaryForTest(t, ledger,
+		&bcSummary{
+			bcInfo: &common.BlockchainInfo{Height: 3,
+				CurrentBlockHash:  blockAndPvtdata2.Block.Header.Hash(),
+				PreviousBlockHash: blockAndPvtdata1.Block.Header.Hash()},
+			stateDBSavePoint:   uint64(2),
+			stateDBKVs:         map[string]string{"key1": "value1.2", "key2": "value2.2"},
+			historyDBSavePoint: uint64(2),
+		},
+	)
+
+	// the peer fails once the fourth block is committed to the block storage, before the state and
+	// history dbs are updated with the third block, which were being updated in the meantime
+	blockAndPvtdata3 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk3",
+		map[string]string{"key1": "value1.3", "key2": "value2.3"},
+		map[string]string{"key1": "pvtValue1.3", "key2": "pvtValue2.3"})
+	blockAndPvtdata4 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk4",
+		map[string]string{"key1": "value1.4", "key3": "value3.4"},
+		map[string]string{"key1": "pvtValue1.4", "key3": "pvtValue3.4"})
+	for _, blockAndPvtdata := range []*lgr.BlockAndPvtData{blockAndPvtdata3, blockAndPvtdata4} {
+		_, err = kvl.txtmgmt.ValidateAndPrepare(blockAndPvtdata, true)
+		assert.NoError(t, err)
+		assert.NoError(t, kvl.blockStore.CommitWithPvtData(blockAndPvtdata))
+		kvl.txtmgmt.Rollback()
+	}
+	ledger.Close()
+	provider.Close()
+
+	// the state and history dbs are recovered when the ledger is opened
+	provider = testutilNewProviderWithCollectionConfig(t,
+		"ns", map[string]uint64{"coll": 0},
+	)
+	defer provider.Close()
+	ledger, err = provider.Open(testLedgerid)
+	assert.NoError(t, err)
+	defer ledger.Close()
+	checkBCSummaryForTest(t, ledger,
+		&bcSummary{
+			bcInfo: &common.BlockchainInfo{Height: 5,
+				CurrentBlockHash:  blockAndPvtdata4.Block.Header.Hash(),
+				PreviousBlockHash: blockAndPvtdata3.Block.Header.Hash()},
+			stateDBSavePoint:   uint64(4),
+			stateDBKVs:         map[string]string{"key1": "value1.4", "key2": "value2.3", "key3": "value3.4"},
+			stateDBPvtKVs:      map[string]string{"key1": "pvtValue1.4", "key2": "pvtValue2.3", "key3": "pvtValue3.4"},
+			historyDBSavePoint: uint64(4),
+			historyKey:         "key1",
+			historyVals:        []string{"value1.1", "value1.2", "value1.3", "value1.4"},
+		},
+	)
+}
+
+// blockingHistoryDB holds the commit of each block until it is released. The commit is
+// dropped, as if the peer failed before performing it, if the block is released with false
+type blockingHistoryDB struct {
+	historydb.HistoryDB
+	release chan bool
+}
+
+func (h *blockingHistoryDB) Commit(block *common.Block) error {
+	if !<-h.release {
+		return nil
+	}
+	return h.HistoryDB.Commit(block)
+}
+
 func testSyncStateDBWithPvtdatastore(t *testing.T) {
 	env := newTestEnv(t)
 	defer env.cleanup()
@@ -649,3 +764,77 @@ type bcSummary struct {
 	historyKey         string
 	historyVals        []string
 }
+
+func TestPrune(t *testing.T) {
+	// a small block file size makes the blocks span several block files
+	viper.Set("ledger.blockchain.maxBlockfileSize", 512)
+	defer viper.Set("ledger.blockchain.maxBlockfileSize", 0)
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer ledger.Close()
+	blocks := commitEmptyBlocksForTest(t, ledger, bg, gb, 20)
+
+	assert.EqualError(t, ledger.Prune("unsupported-policy"), "unsupported prune policy type [string]")
+
+	assert.NoError(t, ledger.Prune(&lgr.BlockPrunePolicy{RetainFromBlockNum: 15, ArchiveDir: env.path}))
+	checkPrunedLedgerForTest(t, ledger, blocks, 15)
+	archivedFiles, err := ioutil.ReadDir(filepath.Join(env.path, "testLedger"))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, archivedFiles)
+}
+
+func TestPruneAfterCommit(t *testing.T) {
+	viper.Set("ledger.blockchain.maxBlockfileSize", 512)
+	defer viper.Set("ledger.blockchain.maxBlockfileSize", 0)
+	viper.Set("ledger.blockchain.pruning.retainBlocks", 5)
+	defer viper.Set("ledger.blockchain.pruning.retainBlocks", 0)
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProvider(t)
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer ledger.Close()
+	blocks := commitEmptyBlocksForTest(t, ledger, bg, gb, 20)
+
+	// the 5 most recent blocks and the genesis block, which is the last config block, are retained
+	checkPrunedLedgerForTest(t, ledger, blocks, 15)
+}
+
+func commitEmptyBlocksForTest(t *testing.T, ledger lgr.PeerLedger, bg *testutil.BlockGenerator, gb *common.Block, numBlocks int) []*common.Block {
+	blocks := []*common.Block{gb}
+	for i := 1; i < numBlocks; i++ {
+		block := bg.NextBlock([][]byte{})
+		assert.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block}))
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// checkPrunedLedgerForTest checks that the blocks from retainFromBlockNum are retained, along with the genesis
+// block, which is the last config block, and that the blocks in between are archived, up to the first retained block file
+func checkPrunedLedgerForTest(t *testing.T, ledger lgr.PeerLedger, blocks []*common.Block, retainFromBlockNum uint64) {
+	firstRetainedBlockNum := retainFromBlockNum
+	for blockNum := uint64(1); blockNum < retainFromBlockNum; blockNum++ {
+		_, err := ledger.GetBlockByNumber(blockNum)
+		if err == nil {
+			firstRetainedBlockNum = blockNum
+			break
+		}
+		assert.IsType(t, &lgr.ErrBlockArchived{}, err)
+	}
+	assert.NotEqual(t, uint64(1), firstRetainedBlockNum, "no block has been pruned")
+	for _, expectedBlock := range append([]*common.Block{blocks[0]}, blocks[firstRetainedBlockNum:]...) {
+		block, err := ledger.GetBlockByNumber(expectedBlock.Header.Number)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(expectedBlock, block))
+	}
+}
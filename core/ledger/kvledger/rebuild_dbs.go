@@ -0,0 +1,33 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/pkg/errors"
+)
+
+// RebuildDBs drops the state, history, config history and bookkeeper databases of
+// all the ledgers. The databases are rebuilt from the blocks in the block store the
+// next time the peer starts. As the databases are rebuilt according to the current
+// configuration, this can also be used to switch the state database of a peer
+// between LevelDB and CouchDB
+func RebuildDBs() error {
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return errors.WithMessage(err, "as another peer node command is executing, wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	logger.Info("Dropping databases")
+	if err := dropDBs(); err != nil {
+		return err
+	}
+	logger.Info("All the databases have been dropped and will be rebuilt from the blocks when the peer starts")
+	return nil
+}
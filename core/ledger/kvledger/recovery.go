@@ -16,7 +16,10 @@ limitations under the License.
 
 package kvledger
 
-import "github.com/hyperledger/fabric/core/ledger"
+import (
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/pkg/errors"
+)
 
 type recoverable interface {
 	// ShouldRecover return whether recovery is need.
@@ -31,3 +34,126 @@ type recoverer struct {
 	firstBlockNum uint64
 	recoverable   recoverable
 }
+
+// syncStateAndHistoryDBWithBlockstore recommits the blocks of the block storage which are missing from the
+// state database or the history database. A database misses the last block if the peer stopped after the
+// block was committed to the block storage, before the database was updated with it. With the commit pipeline
+// enabled, the databases are updated with a block in the background while the next block is validated and
+// committed to the block storage, hence they may miss the last two blocks. As the validation results of
+// the transactions are recorded in the blocks, the blocks are recommitted without validating them again
+func (l *kvLedger) syncStateAndHistoryDBWithBlockstore() error {
+	//If there is no block in blockstorage, nothing to recover.
+	info, _ := l.blockStore.GetBlockchainInfo()
+	if info.Height == 0 {
+		logger.Debug("Block storage is empty.")
+		return nil
+	}
+	lastAvailableBlockNum := info.Height - 1
+	// the blocks up to the last block of the snapshot, if the ledger was bootstrapped from one, are not
+	// available for recommitting. The state db is imported from the snapshot, whereas the history db
+	// starts with the block following the snapshot
+	snapshotInfo, err := l.blockStore.GetBootstrappingSnapshotInfo()
+	if err != nil {
+		return err
+	}
+	recoverables := []recoverable{l.txtmgmt, l.historyDB}
+	recoverers := []*recoverer{}
+	for _, recoverable := range recoverables {
+		recoverFlag, firstBlockNum, err := recoverable.ShouldRecover(lastAvailableBlockNum)
+		if err != nil {
+			return err
+		}
+		if !recoverFlag {
+			continue
+		}
+		if lastAvailableBlockNum >= firstBlockNum {
+			logger.Infof("[%s] Recovering a database missing the last [%d] block(s) of the block storage, from block [%d]",
+				l.ledgerID, lastAvailableBlockNum-firstBlockNum+1, firstBlockNum)
+		}
+		if snapshotInfo != nil && firstBlockNum <= snapshotInfo.LastBlock.Header.Number {
+			if recoverable != l.historyDB {
+				return errors.Errorf("state database of ledger [%s] cannot be recovered from block [%d] as the ledger was bootstrapped from a snapshot of block [%d]",
+					l.ledgerID, firstBlockNum, snapshotInfo.LastBlock.Header.Number)
+			}
+			firstBlockNum = snapshotInfo.LastBlock.Header.Number + 1
+			if firstBlockNum > lastAvailableBlockNum {
+				continue
+			}
+		}
+		recoverers = append(recoverers, &recoverer{firstBlockNum, recoverable})
+	}
+	if len(recoverers) == 0 {
+		return nil
+	}
+	if len(recoverers) == 1 {
+		return l.recommitLostBlocks(recoverers[0].firstBlockNum, lastAvailableBlockNum, recoverers[0].recoverable)
+	}
+
+	// both dbs need to be recovered
+	if recoverers[0].firstBlockNum > recoverers[1].firstBlockNum {
+		// swap (put the lagger db at 0 index)
+		recoverers[0], recoverers[1] = recoverers[1], recoverers[0]
+	}
+	if recoverers[0].firstBlockNum != recoverers[1].firstBlockNum {
+		// bring the lagger db equal to the other db
+		if err := l.recommitLostBlocks(recoverers[0].firstBlockNum, recoverers[1].firstBlockNum-1,
+			recoverers[0].recoverable); err != nil {
+			return err
+		}
+	}
+	// get both the db upto block storage
+	return l.recommitLostBlocks(recoverers[1].firstBlockNum, lastAvailableBlockNum,
+		recoverers[0].recoverable, recoverers[1].recoverable)
+}
+
+//recommitLostBlocks retrieves blocks in specified range and commit the write set to either
+//state DB or history DB or both
+func (l *kvLedger) recommitLostBlocks(firstBlockNum uint64, lastBlockNum uint64, recoverables ...recoverable) error {
+	logger.Infof("Recommitting lost blocks - firstBlockNum=%d, lastBlockNum=%d, recoverables=%#v", firstBlockNum, lastBlockNum, recoverables)
+	var err error
+	var blockAndPvtdata *ledger.BlockAndPvtData
+	progress := newRecommitProgress(l.ledgerID, firstBlockNum, lastBlockNum)
+	for blockNumber := firstBlockNum; blockNumber <= lastBlockNum; blockNumber++ {
+		if blockAndPvtdata, err = l.GetPvtDataAndBlockByNum(blockNumber, nil); err != nil {
+			return err
+		}
+		for _, r := range recoverables {
+			if err := r.CommitLostBlock(blockAndPvtdata); err != nil {
+				return err
+			}
+		}
+		progress.blockRecommitted(blockNumber)
+	}
+	logger.Infof("Recommitted lost blocks - firstBlockNum=%d, lastBlockNum=%d, recoverables=%#v", firstBlockNum, lastBlockNum, recoverables)
+	return nil
+}
+
+// recommitProgress reports the progress of recommitting the blocks, which may take
+// a long time when the databases are rebuilt from the complete block store
+type recommitProgress struct {
+	ledgerID          string
+	firstBlockNum     uint64
+	totalBlocks       uint64
+	nextReportPercent uint64
+}
+
+func newRecommitProgress(ledgerID string, firstBlockNum, lastBlockNum uint64) *recommitProgress {
+	return &recommitProgress{
+		ledgerID:          ledgerID,
+		firstBlockNum:     firstBlockNum,
+		totalBlocks:       lastBlockNum - firstBlockNum + 1,
+		nextReportPercent: 10,
+	}
+}
+
+// blockRecommitted logs the progress whenever another ten percent of the blocks
+// have been recommitted
+func (p *recommitProgress) blockRecommitted(blockNum uint64) {
+	recommitted := blockNum - p.firstBlockNum + 1
+	percent := recommitted * 100 / p.totalBlocks
+	if percent < p.nextReportPercent {
+		return
+	}
+	logger.Infof("[%s] Recommitted %d of %d blocks (%d%%)", p.ledgerID, recommitted, p.totalBlocks, percent)
+	p.nextReportPercent = percent - percent%10 + 10
+}
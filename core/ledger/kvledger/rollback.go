@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"os"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+	"github.com/pkg/errors"
+)
+
+// RollbackKVLedger rolls back the block store of a ledger to the given block number
+// and drops the databases derived from the blocks. The databases are rebuilt from the
+// blocks the next time the peer starts
+func RollbackKVLedger(ledgerID string, blockNum uint64) error {
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return errors.WithMessage(err, "as another peer node command is executing, wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	blockstorePath := ledgerconfig.GetBlockStorePath()
+	if err := ledgerstorage.ValidateRollbackParams(blockstorePath, ledgerID, blockNum); err != nil {
+		return err
+	}
+
+	logger.Info("Dropping databases")
+	if err := dropDBs(); err != nil {
+		return err
+	}
+
+	logger.Info("Rolling back ledger store")
+	if err := ledgerstorage.Rollback(blockstorePath, ledgerID, blockNum); err != nil {
+		return err
+	}
+	logger.Infof("The channel [%s] has been successfully rolled back to the block number [%d]", ledgerID, blockNum)
+	return nil
+}
+
+// ResetAllKVLedgers rolls back all the ledgers to their genesis blocks and drops the
+// databases derived from the blocks. The databases are rebuilt from the blocks the
+// next time the peer starts
+func ResetAllKVLedgers() error {
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return errors.WithMessage(err, "as another peer node command is executing, wait for that command to complete its execution or terminate it before retrying")
+	}
+	defer fileLock.Unlock()
+
+	logger.Info("Dropping databases")
+	if err := dropDBs(); err != nil {
+		return err
+	}
+
+	logger.Info("Resetting ledger store to genesis block")
+	if err := ledgerstorage.ResetBlockStore(ledgerconfig.GetBlockStorePath()); err != nil {
+		return err
+	}
+	logger.Info("All channels have been successfully reset to the genesis block")
+	return nil
+}
+
+// dropDBs drops the state, history, bookkeeper and config history databases of all
+// the ledgers, including the state databases of the implementations other than the
+// configured one. While recommitting the blocks to the state database, the transaction
+// manager populates the bookkeeper and a state listener populates the config history,
+// so these need to be dropped along with the state database. As the blocks preceding the
+// snapshot, or the pruned blocks, are not available for recommitting, the databases are not
+// dropped if any ledger was bootstrapped from a snapshot or has pruned blocks
+func dropDBs() error {
+	bootstrappedLedgerIDs, err := ledgerstorage.LedgersBootstrappedFromSnapshot(ledgerconfig.GetBlockStorePath())
+	if err != nil {
+		return err
+	}
+	if len(bootstrappedLedgerIDs) > 0 {
+		return errors.Errorf("the databases cannot be dropped as ledger [%s] was bootstrapped from a snapshot and its blocks preceding the snapshot are not available", bootstrappedLedgerIDs[0])
+	}
+	prunedLedgerIDs, err := ledgerstorage.LedgersWithPrunedBlocks(ledgerconfig.GetBlockStorePath())
+	if err != nil {
+		return err
+	}
+	if len(prunedLedgerIDs) > 0 {
+		return errors.Errorf("the databases cannot be dropped as the blocks of ledger [%s] have been pruned", prunedLedgerIDs[0])
+	}
+	stateDatabase := ledgerconfig.GetStateDatabase()
+	factory, err := statedb.GetVersionedDBProviderFactory(stateDatabase)
+	if err != nil {
+		return err
+	}
+	if err := factory.DropAll(); err != nil {
+		return err
+	}
+	// The state databases of the other implementations are left behind when the state database
+	// of the peer is switched, such as from CouchDB to LevelDB, so these are dropped as well.
+	// As the peer may no longer have access to them, failing to drop them is not an error
+	for _, name := range statedb.RegisteredStateDatabases() {
+		if name == stateDatabase {
+			continue
+		}
+		factory, err := statedb.GetVersionedDBProviderFactory(name)
+		if err != nil {
+			return err
+		}
+		if err := factory.DropAll(); err != nil {
+			logger.Warningf("Could not drop the state databases of [%s], which is not the configured state database: %s", name, err)
+		}
+	}
+	for _, path := range []string{
+		ledgerconfig.GetHistoryLevelDBPath(),
+		ledgerconfig.GetInternalBookkeeperPath(),
+		ledgerconfig.GetConfigHistoryPath(),
+	} {
+		logger.Infof("Dropping database at [%s]", path)
+		if err := os.RemoveAll(path); err != nil {
+			return errors.Wrapf(err, "error removing database at [%s]", path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,491 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/core/ledger/confighistory"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	snapshotMetadataFile        = "_snapshot_metadata.json"
+	snapshotTxIDsFile           = "txids.data"
+	snapshotStateFile           = "state.data"
+	snapshotConfigHistoryFile   = "confighistory.data"
+	snapshotLastBlockFile       = "last_block.data"
+	snapshotLastConfigBlockFile = "last_config_block.data"
+
+	snapshotsTempDirName      = "temp"
+	snapshotsCompletedDirName = "completed"
+)
+
+var snapshotDataFiles = []string{
+	snapshotTxIDsFile,
+	snapshotStateFile,
+	snapshotConfigHistoryFile,
+	snapshotLastBlockFile,
+	snapshotLastConfigBlockFile,
+}
+
+// snapshotMetadata is persisted in the snapshot dir along with the data files. The hashes of
+// the data files are verified before a ledger is bootstrapped from the snapshot
+type snapshotMetadata struct {
+	ChannelName       string            `json:"channel_name"`
+	LastBlockNumber   uint64            `json:"last_block_number"`
+	LastBlockHash     string            `json:"last_block_hash"`
+	PreviousBlockHash string            `json:"previous_block_hash"`
+	FilesHash         map[string]string `json:"files_hash"`
+}
+
+// GenerateSnapshot generates a snapshot of the ledger at the last committed block. The snapshot
+// is first written to a temporary dir and then moved to the dir '<snapshots root dir>/completed/<ledger id>/<block number>',
+// which is returned. The commit of blocks is blocked while the snapshot is being generated, so that
+// the exported block store, state and config history are consistent with each other. The state
+// is exported by a full scan of the state database, so generating a snapshot fails for a state
+// database that does not support it
+func (l *kvLedger) GenerateSnapshot() (string, error) {
+	l.blockAPIsRWLock.Lock()
+	defer l.blockAPIsRWLock.Unlock()
+	// the state database may still be being updated with the last block, if the commit pipeline is enabled
+	if err := l.txtmgmt.WaitForCommit(); err != nil {
+		return "", err
+	}
+
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return "", err
+	}
+	if bcInfo.Height == 0 {
+		return "", errors.Errorf("ledger [%s] does not have any block", l.ledgerID)
+	}
+	lastBlockNum := bcInfo.Height - 1
+	lastBlock, err := l.blockStore.RetrieveBlockByNumber(lastBlockNum)
+	if err != nil {
+		return "", err
+	}
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return "", errors.WithMessage(err, "error retrieving the last config block number")
+	}
+	lastConfigBlock, err := l.blockStore.RetrieveBlockByNumber(lastConfigBlockNum)
+	if err != nil {
+		return "", err
+	}
+
+	snapshotsRootDir := ledgerconfig.GetSnapshotsRootDir()
+	tempDir := filepath.Join(snapshotsRootDir, snapshotsTempDirName, fmt.Sprintf("%s-%d", l.ledgerID, lastBlockNum))
+	completedDir := filepath.Join(snapshotsRootDir, snapshotsCompletedDirName, l.ledgerID, fmt.Sprintf("%d", lastBlockNum))
+	if _, err := os.Stat(completedDir); err == nil {
+		return "", errors.Errorf("snapshot of ledger [%s] at block [%d] already exists at [%s]", l.ledgerID, lastBlockNum, completedDir)
+	}
+	if err := os.RemoveAll(tempDir); err != nil {
+		return "", errors.Wrapf(err, "error removing dir [%s]", tempDir)
+	}
+	if _, err := util.CreateDirIfMissing(tempDir); err != nil {
+		return "", errors.Wrapf(err, "error creating dir [%s]", tempDir)
+	}
+
+	logger.Infof("Generating snapshot of ledger [%s] at block [%d]", l.ledgerID, lastBlockNum)
+	filesHash := map[string]string{}
+	exporters := map[string]func(w *snapshotFileWriter) error{
+		snapshotTxIDsFile:           l.exportTxIDs,
+		snapshotStateFile:           l.exportState,
+		snapshotConfigHistoryFile:   l.exportConfigHistory,
+		snapshotLastBlockFile:       blockExporter(lastBlock),
+		snapshotLastConfigBlockFile: blockExporter(lastConfigBlock),
+	}
+	for _, fileName := range snapshotDataFiles {
+		fileHash, err := writeSnapshotFile(filepath.Join(tempDir, fileName), exporters[fileName])
+		if err != nil {
+			return "", err
+		}
+		filesHash[fileName] = fileHash
+	}
+	metadata := &snapshotMetadata{
+		ChannelName:       l.ledgerID,
+		LastBlockNumber:   lastBlockNum,
+		LastBlockHash:     hex.EncodeToString(bcInfo.CurrentBlockHash),
+		PreviousBlockHash: hex.EncodeToString(bcInfo.PreviousBlockHash),
+		FilesHash:         filesHash,
+	}
+	metadataBytes, err := json.MarshalIndent(metadata, "", "    ")
+	if err != nil {
+		return "", errors.Wrap(err, "error marshaling snapshot metadata")
+	}
+	if err := ioutil.WriteFile(filepath.Join(tempDir, snapshotMetadataFile), metadataBytes, 0644); err != nil {
+		return "", errors.Wrap(err, "error writing snapshot metadata")
+	}
+
+	if _, err := util.CreateDirIfMissing(filepath.Dir(completedDir)); err != nil {
+		return "", errors.Wrapf(err, "error creating dir [%s]", filepath.Dir(completedDir))
+	}
+	if err := os.Rename(tempDir, completedDir); err != nil {
+		return "", errors.Wrapf(err, "error moving snapshot to dir [%s]", completedDir)
+	}
+	logger.Infof("Generated snapshot of ledger [%s] at block [%d] in dir [%s]", l.ledgerID, lastBlockNum, completedDir)
+	return completedDir, nil
+}
+
+func (l *kvLedger) exportTxIDs(w *snapshotFileWriter) error {
+	return l.blockStore.ExportTxIDs(func(txID string) error {
+		return w.encodeBytes([]byte(txID))
+	})
+}
+
+func (l *kvLedger) exportState(w *snapshotFileWriter) error {
+	itr, err := l.stateDB.GetFullScanIterator()
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	for {
+		compositeKey, vv, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if compositeKey == nil {
+			return nil
+		}
+		for _, b := range [][]byte{
+			[]byte(compositeKey.Namespace),
+			[]byte(compositeKey.Key),
+			vv.Value,
+			vv.Metadata,
+			vv.Version.ToBytes(),
+		} {
+			if err := w.encodeBytes(b); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (l *kvLedger) exportConfigHistory(w *snapshotFileWriter) error {
+	return l.configHistoryMgr.ExportConfigHistory(l.ledgerID, func(entry *confighistory.Entry) error {
+		for _, b := range [][]byte{
+			[]byte(entry.Namespace),
+			[]byte(entry.Key),
+			proto.EncodeVarint(entry.BlockNum),
+			entry.Value,
+		} {
+			if err := w.encodeBytes(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func blockExporter(block *common.Block) func(w *snapshotFileWriter) error {
+	return func(w *snapshotFileWriter) error {
+		blockBytes, err := proto.Marshal(block)
+		if err != nil {
+			return errors.Wrapf(err, "error marshaling block [%d]", block.Header.Number)
+		}
+		return w.encodeBytes(blockBytes)
+	}
+}
+
+// loadSnapshot loads the metadata of the snapshot in the given dir and verifies the data files
+// against the hashes recorded in the metadata
+func loadSnapshot(snapshotDir string) (*snapshotMetadata, *blkstorage.SnapshotInfo, error) {
+	metadataBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotMetadataFile))
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "error reading snapshot metadata from dir [%s]", snapshotDir)
+	}
+	metadata := &snapshotMetadata{}
+	if err := json.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, nil, errors.Wrapf(err, "error unmarshaling snapshot metadata from dir [%s]", snapshotDir)
+	}
+	if metadata.ChannelName == "" {
+		return nil, nil, errors.Errorf("channel name is missing in the snapshot metadata in dir [%s]", snapshotDir)
+	}
+	for _, fileName := range snapshotDataFiles {
+		fileHash, err := computeFileHash(filepath.Join(snapshotDir, fileName))
+		if err != nil {
+			return nil, nil, err
+		}
+		if fileHash != metadata.FilesHash[fileName] {
+			return nil, nil, errors.Errorf("hash of the snapshot file [%s] does not match the hash in the snapshot metadata", fileName)
+		}
+	}
+
+	lastBlock, err := readSnapshotBlock(filepath.Join(snapshotDir, snapshotLastBlockFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	lastConfigBlock, err := readSnapshotBlock(filepath.Join(snapshotDir, snapshotLastConfigBlockFile))
+	if err != nil {
+		return nil, nil, err
+	}
+	if lastBlock.Header.Number != metadata.LastBlockNumber ||
+		hex.EncodeToString(lastBlock.Header.Hash()) != metadata.LastBlockHash {
+		return nil, nil, errors.Errorf("last block in the snapshot does not match the block [%d] in the snapshot metadata", metadata.LastBlockNumber)
+	}
+	channelName, err := utils.GetChainIDFromBlock(lastConfigBlock)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error retrieving the channel name from the last config block in the snapshot")
+	}
+	if channelName != metadata.ChannelName {
+		return nil, nil, errors.Errorf("channel name [%s] in the last config block does not match the channel name [%s] in the snapshot metadata",
+			channelName, metadata.ChannelName)
+	}
+	return metadata, &blkstorage.SnapshotInfo{LastBlock: lastBlock, LastConfigBlock: lastConfigBlock}, nil
+}
+
+// stateSavepoint returns the savepoint of the state db as recorded on committing the last block
+func stateSavepoint(lastBlock *common.Block) *version.Height {
+	numTxs := uint64(len(lastBlock.Data.Data))
+	if numTxs == 0 {
+		return version.NewHeight(lastBlock.Header.Number, 0)
+	}
+	return version.NewHeight(lastBlock.Header.Number, numTxs-1)
+}
+
+func readSnapshotBlock(filePath string) (*common.Block, error) {
+	r, err := openSnapshotFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer r.close()
+	blockBytes, err := r.decodeBytes()
+	if err != nil {
+		return nil, err
+	}
+	block := &common.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshaling block from the snapshot file [%s]", filePath)
+	}
+	if block.Header == nil {
+		return nil, errors.Errorf("block header is missing in the snapshot file [%s]", filePath)
+	}
+	return block, nil
+}
+
+func computeFileHash(filePath string) (string, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "error opening snapshot file [%s]", filePath)
+	}
+	defer f.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return "", errors.Wrapf(err, "error reading snapshot file [%s]", filePath)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// writeSnapshotFile creates the file at the given path, invokes the export function for writing
+// the entries, and returns the hash of the file contents
+func writeSnapshotFile(filePath string, export func(w *snapshotFileWriter) error) (string, error) {
+	f, err := os.Create(filePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "error creating snapshot file [%s]", filePath)
+	}
+	hasher := sha256.New()
+	w := &snapshotFileWriter{
+		file:   f,
+		hasher: hasher,
+		buf:    bufio.NewWriter(io.MultiWriter(f, hasher)),
+	}
+	if err := export(w); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := w.buf.Flush(); err != nil {
+		f.Close()
+		return "", errors.Wrapf(err, "error writing snapshot file [%s]", filePath)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return "", errors.Wrapf(err, "error syncing snapshot file [%s]", filePath)
+	}
+	if err := f.Close(); err != nil {
+		return "", errors.Wrapf(err, "error closing snapshot file [%s]", filePath)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// snapshotFileWriter writes the entries of a snapshot file, each prefixed with its length
+type snapshotFileWriter struct {
+	file   *os.File
+	hasher hash.Hash
+	buf    *bufio.Writer
+}
+
+func (w *snapshotFileWriter) encodeBytes(b []byte) error {
+	lenBytes := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(lenBytes, uint64(len(b)))
+	if _, err := w.buf.Write(lenBytes[:n]); err != nil {
+		return errors.Wrapf(err, "error writing to snapshot file [%s]", w.file.Name())
+	}
+	if _, err := w.buf.Write(b); err != nil {
+		return errors.Wrapf(err, "error writing to snapshot file [%s]", w.file.Name())
+	}
+	return nil
+}
+
+// snapshotFileReader reads the entries written by snapshotFileWriter
+type snapshotFileReader struct {
+	file *os.File
+	buf  *bufio.Reader
+}
+
+func openSnapshotFile(filePath string) (*snapshotFileReader, error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error opening snapshot file [%s]", filePath)
+	}
+	return &snapshotFileReader{file: f, buf: bufio.NewReader(f)}, nil
+}
+
+// hasMore returns true if there are more entries to be read
+func (r *snapshotFileReader) hasMore() (bool, error) {
+	_, err := r.buf.Peek(1)
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Wrapf(err, "error reading snapshot file [%s]", r.file.Name())
+	}
+	return true, nil
+}
+
+// decodeBytes reads the next entry. An empty entry is returned as nil
+func (r *snapshotFileReader) decodeBytes() ([]byte, error) {
+	size, err := binary.ReadUvarint(r.buf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading snapshot file [%s]", r.file.Name())
+	}
+	if size == 0 {
+		return nil, nil
+	}
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r.buf, b); err != nil {
+		return nil, errors.Wrapf(err, "error reading snapshot file [%s]", r.file.Name())
+	}
+	return b, nil
+}
+
+// decodeEntry reads the given number of consecutive entries. It returns nil if there are no
+// more entries to be read
+func (r *snapshotFileReader) decodeEntry(numFields int) ([][]byte, error) {
+	more, err := r.hasMore()
+	if err != nil || !more {
+		return nil, err
+	}
+	fields := make([][]byte, numFields)
+	for i := range fields {
+		if fields[i], err = r.decodeBytes(); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+func (r *snapshotFileReader) close() {
+	r.file.Close()
+}
+
+// txIDsImporter implements the interface blkstorage.TxIDsIterator over the txids file of a snapshot
+type txIDsImporter struct {
+	*snapshotFileReader
+}
+
+func (i *txIDsImporter) Next() (string, bool, error) {
+	fields, err := i.decodeEntry(1)
+	if err != nil || fields == nil {
+		return "", false, err
+	}
+	return string(fields[0]), true, nil
+}
+
+// stateImporter implements the interface statedb.FullScanIterator over the state file of a snapshot
+type stateImporter struct {
+	*snapshotFileReader
+}
+
+func (i *stateImporter) Next() (*statedb.CompositeKey, *statedb.VersionedValue, error) {
+	fields, err := i.decodeEntry(5)
+	if err != nil || fields == nil {
+		return nil, nil, err
+	}
+	ver, err := decodeSnapshotVersion(fields[4])
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, fmt.Sprintf("error decoding version of key [%s] of namespace [%s] in the snapshot file [%s]",
+			fields[1], fields[0], i.file.Name()))
+	}
+	return &statedb.CompositeKey{Namespace: string(fields[0]), Key: string(fields[1])},
+		&statedb.VersionedValue{Value: fields[2], Metadata: fields[3], Version: ver},
+		nil
+}
+
+// decodeSnapshotVersion decodes a version written by function version.Height.ToBytes. As function
+// version.NewHeightFromBytes does not validate its input, the bytes are checked to hold exactly the
+// encoded block number and tx number before being decoded
+func decodeSnapshotVersion(b []byte) (*version.Height, error) {
+	offset := 0
+	for i := 0; i < 2; i++ {
+		// each number is encoded as a single byte holding its size, of at most 8 bytes, followed by its bytes
+		if offset >= len(b) || b[offset] > 8 || offset+int(b[offset])+1 > len(b) {
+			return nil, errors.Errorf("malformed version bytes [%x]", b)
+		}
+		offset += int(b[offset]) + 1
+	}
+	if offset != len(b) {
+		return nil, errors.Errorf("malformed version bytes [%x]", b)
+	}
+	ver, _ := version.NewHeightFromBytes(b)
+	return ver, nil
+}
+
+func (i *stateImporter) Close() {
+	i.close()
+}
+
+// configHistoryImporter implements the interface confighistory.EntriesIterator over the config
+// history file of a snapshot
+type configHistoryImporter struct {
+	*snapshotFileReader
+}
+
+func (i *configHistoryImporter) Next() (*confighistory.Entry, error) {
+	fields, err := i.decodeEntry(4)
+	if err != nil || fields == nil {
+		return nil, err
+	}
+	blockNum, n := proto.DecodeVarint(fields[2])
+	if n == 0 {
+		return nil, errors.Errorf("error decoding block number in the snapshot file [%s]", i.file.Name())
+	}
+	return &confighistory.Entry{
+		Namespace: string(fields[0]),
+		Key:       string(fields[1]),
+		BlockNum:  blockNum,
+		Value:     fields[3],
+	}, nil
+}
@@ -0,0 +1,177 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	ledgerutil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateSnapshotAndCreateFromSnapshot(t *testing.T) {
+	sourceEnv := newTestEnv(t)
+	defer sourceEnv.cleanup()
+	provider := testutilNewProviderWithCollectionConfig(t, "ns", map[string]uint64{"coll": 0})
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	sourceLedger, err := provider.Create(gb)
+	assert.NoError(t, err)
+
+	blockAndPvtdata1 := prepareNextBlockForTest(t, sourceLedger, bg, util.GenerateUUID(),
+		map[string]string{"key1": "value1.1", "key2": "value2.1"}, map[string]string{"key1": "pvtValue1.1"})
+	assert.NoError(t, sourceLedger.CommitWithPvtData(blockAndPvtdata1))
+	blockAndPvtdata2 := prepareNextBlockForTest(t, sourceLedger, bg, util.GenerateUUID(),
+		map[string]string{"key1": "value1.2"}, map[string]string{"key2": "pvtValue2.2"})
+	assert.NoError(t, sourceLedger.CommitWithPvtData(blockAndPvtdata2))
+
+	snapshotDir, err := sourceLedger.GenerateSnapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(ledgerconfig.GetSnapshotsRootDir(), "completed", "testLedger", "2"), snapshotDir)
+	for _, fileName := range append(snapshotDataFiles, snapshotMetadataFile) {
+		assert.FileExists(t, filepath.Join(snapshotDir, fileName))
+	}
+	_, err = sourceLedger.GenerateSnapshot()
+	assert.EqualError(t, err, "snapshot of ledger [testLedger] at block [2] already exists at ["+snapshotDir+"]")
+	sourceBCInfo, err := sourceLedger.GetBlockchainInfo()
+	assert.NoError(t, err)
+	sourceLedger.Close()
+	provider.Close()
+
+	// bootstrap the ledger on a different peer from the snapshot
+	targetEnv := newTestEnv(t)
+	defer targetEnv.cleanup()
+	provider = testutilNewProviderWithCollectionConfig(t, "ns", map[string]uint64{"coll": 0})
+	ledger, ledgerID, err := provider.CreateFromSnapshot(snapshotDir)
+	assert.NoError(t, err)
+	assert.Equal(t, "testLedger", ledgerID)
+	_, _, err = provider.CreateFromSnapshot(snapshotDir)
+	assert.Equal(t, ErrLedgerIDExists, err)
+
+	bcInfo, err := ledger.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, sourceBCInfo, bcInfo)
+	block, err := ledger.GetBlockByNumber(0)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(gb, block))
+	_, err = ledger.GetBlockByNumber(1)
+	assert.EqualError(t, err, "block [1] is not available as the ledger was bootstrapped from a snapshot of block [2]")
+	txEnv, err := putils.GetEnvelopeFromBlock(blockAndPvtdata1.Block.Data.Data[0])
+	assert.NoError(t, err)
+	chdr, err := putils.ChannelHeader(txEnv)
+	assert.NoError(t, err)
+	exists, err := ledger.TxIDExists(chdr.TxId)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	// the public state and the hashes of the private data are imported, the private data is not
+	checkStateDBForTest(t, ledger, map[string]string{"key1": "value1.2", "key2": "value2.1"}, nil)
+	vv, err := ledger.(*kvLedger).stateDB.GetValueHash("ns", "coll", ledgerutil.ComputeStringHash("key2"))
+	assert.NoError(t, err)
+	assert.Equal(t, ledgerutil.ComputeHash([]byte("pvtValue2.2")), vv.Value)
+	qe, err := ledger.NewQueryExecutor()
+	assert.NoError(t, err)
+	_, err = qe.GetPrivateData("ns", "coll", "key2")
+	assert.Contains(t, err.Error(), "private data matching public hash version is not available")
+	qe.Done()
+
+	// the blocks following the snapshot are committed as usual
+	blockAndPvtdata3 := prepareNextBlockForTest(t, ledger, bg, util.GenerateUUID(),
+		map[string]string{"key1": "value1.3"}, map[string]string{"key1": "pvtValue1.3"})
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata3))
+	checkBCSummaryForTest(t, ledger, &bcSummary{
+		bcInfo: &common.BlockchainInfo{Height: 4,
+			CurrentBlockHash:  blockAndPvtdata3.Block.Header.Hash(),
+			PreviousBlockHash: blockAndPvtdata2.Block.Header.Hash()},
+		stateDBSavePoint:   3,
+		stateDBKVs:         map[string]string{"key1": "value1.3", "key2": "value2.1"},
+		stateDBPvtKVs:      map[string]string{"key1": "pvtValue1.3"},
+		historyDBSavePoint: 3,
+		historyKey:         "key1",
+		historyVals:        []string{"value1.3"},
+	})
+	ledger.Close()
+
+	// the ledger bootstrapped from the snapshot can be reopened
+	ledger, err = provider.Open("testLedger")
+	assert.NoError(t, err)
+	bcInfo, err = ledger.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), bcInfo.Height)
+	block, err = ledger.GetBlockByNumber(3)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(blockAndPvtdata3.Block, block))
+	ledger.Close()
+	provider.Close()
+
+	// the blocks committed after the snapshot cannot be rolled back either, as the
+	// state of the ledger cannot be rebuilt from its blocks
+	err = RollbackKVLedger("testLedger", 2)
+	assert.EqualError(t, err, "the databases cannot be dropped as ledger [testLedger] was bootstrapped from a snapshot and its blocks preceding the snapshot are not available")
+}
+
+func TestCreateFromSnapshotErrors(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider := testutilNewProviderWithCollectionConfig(t, "ns", map[string]uint64{"coll": 0})
+	defer provider.Close()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	sourceLedger, err := provider.Create(gb)
+	assert.NoError(t, err)
+	defer sourceLedger.Close()
+	assert.NoError(t, sourceLedger.CommitWithPvtData(prepareNextBlockForTest(t, sourceLedger, bg, util.GenerateUUID(),
+		map[string]string{"key1": "value1"}, map[string]string{"key1": "pvtValue1"})))
+	snapshotDir, err := sourceLedger.GenerateSnapshot()
+	assert.NoError(t, err)
+
+	_, _, err = provider.CreateFromSnapshot(filepath.Join(env.path, "non-existing-dir"))
+	assert.Contains(t, err.Error(), "error reading snapshot metadata from dir")
+
+	// a tampered snapshot file is detected before the import
+	tamperedDir := filepath.Join(env.path, "tampered")
+	assert.NoError(t, os.MkdirAll(tamperedDir, 0755))
+	for _, fileName := range append(snapshotDataFiles, snapshotMetadataFile) {
+		b, err := ioutil.ReadFile(filepath.Join(snapshotDir, fileName))
+		assert.NoError(t, err)
+		if fileName == snapshotStateFile {
+			b = append(b, 0)
+		}
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(tamperedDir, fileName), b, 0644))
+	}
+	_, _, err = provider.CreateFromSnapshot(tamperedDir)
+	assert.EqualError(t, err, "hash of the snapshot file [state.data] does not match the hash in the snapshot metadata")
+
+	// the snapshot cannot be imported for a ledger that exists already
+	_, _, err = provider.CreateFromSnapshot(snapshotDir)
+	assert.Equal(t, ErrLedgerIDExists, err)
+}
+
+func TestDecodeSnapshotVersion(t *testing.T) {
+	ver, err := decodeSnapshotVersion(version.NewHeight(1000, 25).ToBytes())
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(1000, 25), ver)
+
+	for _, b := range [][]byte{
+		nil,
+		{0x01, 0x05},
+		{0x09, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		append(version.NewHeight(1000, 25).ToBytes(), 0x00),
+	} {
+		_, err := decodeSnapshotVersion(b)
+		assert.EqualError(t, err, fmt.Sprintf("malformed version bytes [%x]", b))
+	}
+}
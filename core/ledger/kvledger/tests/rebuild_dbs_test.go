@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tests
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebuildDBs(t *testing.T) {
+	env := newEnv(defaultConfig, t)
+	defer env.cleanup()
+
+	h1, h2 := newTestHelperCreateLgr("ledger1", t), newTestHelperCreateLgr("ledger2", t)
+	dataHelper := newSampleDataHelper(t)
+	dataHelper.populateLedger(h1)
+	dataHelper.populateLedger(h2)
+	dataHelper.verifyLedgerContent(h1)
+	dataHelper.verifyLedgerContent(h2)
+
+	// rebuilding the databases is not allowed while the ledgers are in use
+	err := kvledger.RebuildDBs()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "as another peer node command is executing")
+
+	closeLedgerMgmt()
+	assert.NoError(t, kvledger.RebuildDBs())
+	env.verifyRebuilableDoesNotExist(rebuildableStatedb + rebuildableConfigHistory)
+	initLedgerMgmt()
+
+	// the databases are rebuilt from the blocks when the ledgers are opened
+	h1, h2 = newTestHelperOpenLgr("ledger1", t), newTestHelperOpenLgr("ledger2", t)
+	dataHelper.verifyLedgerContent(h1)
+	dataHelper.verifyLedgerContent(h2)
+}
+
+var (
+	registerSwitchTestDBOnce sync.Once
+	switchTestDBDropErr      error
+	switchTestDBDrops        int
+)
+
+func TestRebuildDBsSwitchingStateDatabase(t *testing.T) {
+	registerSwitchTestDBOnce.Do(func() {
+		statedb.RegisterVersionedDBProviderFactory("switchtestdb", &statedb.VersionedDBProviderFactory{
+			NewProvider: func(metrics.Provider) (statedb.VersionedDBProvider, error) {
+				return nil, errors.New("not implemented")
+			},
+			DropAll: func() error {
+				switchTestDBDrops++
+				return switchTestDBDropErr
+			},
+		})
+	})
+	switchTestDBDrops, switchTestDBDropErr = 0, nil
+	defer viper.Set("ledger.state.stateDatabase", "goleveldb")
+
+	env := newEnv(defaultConfig, t)
+	defer env.cleanup()
+
+	h := newTestHelperCreateLgr("ledger1", t)
+	dataHelper := newSampleDataHelper(t)
+	dataHelper.populateLedger(h)
+	closeLedgerMgmt()
+
+	// switching from LevelDB to another state database drops the LevelDB state database
+	viper.Set("ledger.state.stateDatabase", "switchtestdb")
+	assert.NoError(t, kvledger.RebuildDBs())
+	env.verifyRebuilableDoesNotExist(rebuildableStatedb + rebuildableConfigHistory)
+	assert.Equal(t, 1, switchTestDBDrops)
+
+	// switching back to LevelDB drops the state database that is no longer configured,
+	// which may not be reachable anymore
+	viper.Set("ledger.state.stateDatabase", "goleveldb")
+	switchTestDBDropErr = errors.New("unreachable")
+	assert.NoError(t, kvledger.RebuildDBs())
+	assert.Equal(t, 2, switchTestDBDrops)
+
+	initLedgerMgmt()
+	h = newTestHelperOpenLgr("ledger1", t)
+	dataHelper.verifyLedgerContent(h)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollback(t *testing.T) {
+	env := newEnv(defaultConfig, t)
+	defer env.cleanup()
+
+	h1, h2 := newTestHelperCreateLgr("ledger1", t), newTestHelperCreateLgr("ledger2", t)
+	dataHelper := newSampleDataHelper(t)
+	dataHelper.populateLedger(h1)
+	dataHelper.populateLedger(h2)
+
+	// rollback is not allowed while the ledgers are in use
+	err := kvledger.RollbackKVLedger("ledger1", 4)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "as another peer node command is executing")
+
+	closeLedgerMgmt()
+	assert.NoError(t, kvledger.RollbackKVLedger("ledger1", 4))
+	env.verifyRebuilableDoesNotExist(rebuildableStatedb + rebuildableConfigHistory)
+	initLedgerMgmt()
+
+	h1 = newTestHelperOpenLgr("ledger1", t)
+	h1.verifyLedgerHeight(5)
+	// the state is rebuilt up to block 4
+	h1.verifyPubState("cc1", "key1", dataHelper.sampleVal("value01", "ledger1"))
+	h1.verifyPubState("cc1", "key2", dataHelper.sampleVal("value02", "ledger1"))
+	h1.verifyPvtState("cc1", "coll1", "key3", dataHelper.sampleVal("value05", "ledger1"))
+	h1.verifyPvtState("cc1", "coll1", "key4", dataHelper.sampleVal("value06", "ledger1"))
+
+	// the rolled back blocks can be committed again, retaining their pvt data
+	for _, blk := range dataHelper.submittedData["ledger1"].Blocks[4:] {
+		assert.NoError(t, h1.lgr.CommitWithPvtData(blk))
+	}
+	dataHelper.verifyLedgerContent(h1)
+
+	h2 = newTestHelperOpenLgr("ledger2", t)
+	dataHelper.verifyLedgerContent(h2)
+}
+
+func TestRollbackErrors(t *testing.T) {
+	env := newEnv(defaultConfig, t)
+	defer env.cleanup()
+
+	h1 := newTestHelperCreateLgr("ledger1", t)
+	dataHelper := newSampleDataHelper(t)
+	dataHelper.populateLedger(h1)
+	closeLedgerMgmt()
+	defer initLedgerMgmt()
+
+	err := kvledger.RollbackKVLedger("non-existent-ledger", 4)
+	assert.EqualError(t, err, "ledgerID [non-existent-ledger] does not exist")
+
+	err = kvledger.RollbackKVLedger("ledger1", 8)
+	assert.EqualError(t, err, "target block number [8] should be less than the biggest block number [8]")
+}
+
+func TestResetAllLedgers(t *testing.T) {
+	env := newEnv(defaultConfig, t)
+	defer env.cleanup()
+
+	h1, h2 := newTestHelperCreateLgr("ledger1", t), newTestHelperCreateLgr("ledger2", t)
+	dataHelper := newSampleDataHelper(t)
+	dataHelper.populateLedger(h1)
+	dataHelper.populateLedger(h2)
+
+	closeLedgerMgmt()
+	assert.NoError(t, kvledger.ResetAllKVLedgers())
+	env.verifyRebuilableDoesNotExist(rebuildableStatedb + rebuildableConfigHistory)
+	initLedgerMgmt()
+
+	for _, lgrid := range []string{"ledger1", "ledger2"} {
+		h := newTestHelperOpenLgr(lgrid, t)
+		h.verifyLedgerHeight(1)
+		h.verifyPubState("cc1", "key1", "")
+		for _, blk := range dataHelper.submittedData[lgrid].Blocks {
+			assert.NoError(t, h.lgr.CommitWithPvtData(blk))
+		}
+		dataHelper.verifyLedgerContent(h)
+	}
+}
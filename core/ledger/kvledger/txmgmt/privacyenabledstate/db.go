@@ -39,6 +39,8 @@ type DB interface {
 	GetPrivateDataMetadataByHash(namespace, collection string, keyHash []byte) ([]byte, error)
 	ExecuteQueryOnPrivateData(namespace, collection, query string) (statedb.ResultsIterator, error)
 	ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Height) error
+	GetFullScanIterator() (statedb.FullScanIterator, error)
+	ImportFromSnapshot(itr statedb.FullScanIterator, savepoint *version.Height) error
 }
 
 // PvtdataCompositeKey encloses Namespace, CollectionName and Key components
@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"strings"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/pkg/errors"
+)
+
+// maxSnapshotImportBatchSize is the number of entries applied to the db in one batch while
+// importing the state from a snapshot
+const maxSnapshotImportBatchSize = 1000
+
+// GetFullScanIterator implements corresponding function in interface DB. The iterator returns the
+// public data and the hashes of the private data. The private data itself is skipped, as it is
+// not shared with the peers that bootstrap a ledger from a snapshot
+func (s *CommonStorageDB) GetFullScanIterator() (statedb.FullScanIterator, error) {
+	fullScanIteratorProvider, ok := s.VersionedDB.(statedb.FullScanIteratorProvider)
+	if !ok {
+		return nil, errors.New("exporting the state is not supported by the configured state database")
+	}
+	itr, err := fullScanIteratorProvider.GetFullScanIterator()
+	if err != nil {
+		return nil, err
+	}
+	return &pvtDataSkippingIterator{itr}, nil
+}
+
+// ImportFromSnapshot implements corresponding function in interface DB. The entries are expected to
+// be the ones returned by the iterator from function GetFullScanIterator. The savepoint is recorded
+// only along with the last batch so that a partially imported state is not considered usable
+func (s *CommonStorageDB) ImportFromSnapshot(itr statedb.FullScanIterator, savepoint *version.Height) error {
+	existingSavepoint, err := s.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+	if existingSavepoint != nil {
+		return errors.New("state database is not empty")
+	}
+	batch := NewUpdateBatch()
+	numEntries := 0
+	for {
+		compositeKey, vv, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if compositeKey == nil {
+			break
+		}
+		if ns, coll, isHashedData := splitHashedDataNs(compositeKey.Namespace); isHashedData {
+			batch.HashUpdates.PutValHashAndMetadata(ns, coll, []byte(compositeKey.Key), vv.Value, vv.Metadata, vv.Version)
+		} else {
+			batch.PubUpdates.PutValAndMetadata(compositeKey.Namespace, compositeKey.Key, vv.Value, vv.Metadata, vv.Version)
+		}
+		numEntries++
+		if numEntries == maxSnapshotImportBatchSize {
+			if err := s.ApplyPrivacyAwareUpdates(batch, nil); err != nil {
+				return err
+			}
+			batch = NewUpdateBatch()
+			numEntries = 0
+		}
+	}
+	return s.ApplyPrivacyAwareUpdates(batch, savepoint)
+}
+
+// splitHashedDataNs splits a namespace derived by function deriveHashedDataNs into the chaincode
+// namespace and the collection name
+func splitHashedDataNs(derivedNs string) (string, string, bool) {
+	prefix := nsJoiner + hashDataPrefix
+	i := strings.Index(derivedNs, prefix)
+	if i == -1 {
+		return "", "", false
+	}
+	return derivedNs[:i], derivedNs[i+len(prefix):], true
+}
+
+func isPvtDataNs(ns string) bool {
+	return strings.Contains(ns, nsJoiner+pvtDataPrefix)
+}
+
+type pvtDataSkippingIterator struct {
+	statedb.FullScanIterator
+}
+
+func (itr *pvtDataSkippingIterator) Next() (*statedb.CompositeKey, *statedb.VersionedValue, error) {
+	for {
+		compositeKey, vv, err := itr.FullScanIterator.Next()
+		if err != nil || compositeKey == nil || !isPvtDataNs(compositeKey.Namespace) {
+			return compositeKey, vv, err
+		}
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportAndImportState(t *testing.T) {
+	for _, env := range testEnvs {
+		t.Run(env.GetName(), func(t *testing.T) {
+			testExportAndImportState(t, env)
+		})
+	}
+}
+
+func testExportAndImportState(t *testing.T, env TestEnv) {
+	env.Init(t)
+	defer env.Cleanup()
+	sourceDB := env.GetDBHandle("source-ledger")
+
+	updates := NewUpdateBatch()
+	updates.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	updates.PubUpdates.PutValAndMetadata("ns1", "key2", []byte("value2"), []byte("metadata2"), version.NewHeight(1, 2))
+	updates.PubUpdates.Put("ns2", "key1", []byte("value3"), version.NewHeight(2, 1))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvt-value1"), version.NewHeight(2, 2))
+	putPvtUpdatesWithMetadata(t, updates, "ns2", "coll1", "key2", []byte("pvt-value2"), []byte("metadata3"), version.NewHeight(3, 1))
+	assert.NoError(t, sourceDB.ApplyPrivacyAwareUpdates(updates, version.NewHeight(3, 1)))
+
+	itr, err := sourceDB.GetFullScanIterator()
+	assert.NoError(t, err)
+	exported := map[statedb.CompositeKey]*statedb.VersionedValue{}
+	for {
+		compositeKey, vv, err := itr.Next()
+		assert.NoError(t, err)
+		if compositeKey == nil {
+			break
+		}
+		exported[*compositeKey] = vv
+	}
+	itr.Close()
+	// three public entries and two hashed entries, the private data is not exported
+	assert.Len(t, exported, 5)
+	for compositeKey := range exported {
+		assert.False(t, isPvtDataNs(compositeKey.Namespace))
+	}
+
+	targetDB := env.GetDBHandle("target-ledger")
+	itr, err = sourceDB.GetFullScanIterator()
+	assert.NoError(t, err)
+	assert.NoError(t, targetDB.ImportFromSnapshot(itr, version.NewHeight(3, 1)))
+	itr.Close()
+
+	savepoint, err := targetDB.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(3, 1), savepoint)
+	vv, err := targetDB.GetState("ns1", "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: []byte("value2"), Metadata: []byte("metadata2"), Version: version.NewHeight(1, 2)}, vv)
+	vv, err = targetDB.GetValueHash("ns2", "coll1", util.ComputeStringHash("key2"))
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: util.ComputeHash([]byte("pvt-value2")), Metadata: []byte("metadata3"), Version: version.NewHeight(3, 1)}, vv)
+	metadata, err := targetDB.GetStateMetadata("ns1", "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("metadata2"), metadata)
+	vv, err = targetDB.GetPrivateData("ns1", "coll1", "key1")
+	assert.NoError(t, err)
+	assert.Nil(t, vv)
+
+	itr, err = sourceDB.GetFullScanIterator()
+	assert.NoError(t, err)
+	defer itr.Close()
+	assert.EqualError(t, targetDB.ImportFromSnapshot(itr, version.NewHeight(3, 1)), "state database is not empty")
+}
+
+func TestExportStateNotSupported(t *testing.T) {
+	db := &CommonStorageDB{VersionedDB: struct{ statedb.VersionedDB }{}}
+	_, err := db.GetFullScanIterator()
+	assert.EqualError(t, err, "exporting the state is not supported by the configured state database")
+}
+
+func TestSplitHashedDataNs(t *testing.T) {
+	ns, coll, ok := splitHashedDataNs(deriveHashedDataNs("ns1", "coll1"))
+	assert.True(t, ok)
+	assert.Equal(t, "ns1", ns)
+	assert.Equal(t, "coll1", coll)
+
+	_, _, ok = splitHashedDataNs("ns1")
+	assert.False(t, ok)
+	_, _, ok = splitHashedDataNs(derivePvtDataNs("ns1", "coll1"))
+	assert.False(t, ok)
+}
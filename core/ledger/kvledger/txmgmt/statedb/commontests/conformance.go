@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commontests
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+)
+
+// conformanceTests are the tests that every implementation of statedb.VersionedDB is expected to pass.
+// The tests that depend on the rich query support or on the batching of CouchDB are not included
+var conformanceTests = []struct {
+	name string
+	test func(t *testing.T, dbProvider statedb.VersionedDBProvider)
+}{
+	{"GetStateMultipleKeys", TestGetStateMultipleKeys},
+	{"BasicRW", TestBasicRW},
+	{"MultiDBBasicRW", TestMultiDBBasicRW},
+	{"Deletes", TestDeletes},
+	{"Iterator", TestIterator},
+	{"GetVersion", TestGetVersion},
+	{"ValueAndMetadataWrites", TestValueAndMetadataWrites},
+	{"PaginatedRangeQuery", TestPaginatedRangeQuery},
+	{"ApplyUpdatesWithNilHeight", TestApplyUpdatesWithNilHeight},
+}
+
+// RunConformanceTests runs the conformance tests against a state database implementation, each as a
+// subtest of t. The function newDBProvider is invoked for every test to get a provider of empty
+// databases, along with a function that closes the provider and removes its databases
+func RunConformanceTests(t *testing.T, newDBProvider func(t *testing.T) (statedb.VersionedDBProvider, func())) {
+	for _, c := range conformanceTests {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			dbProvider, cleanup := newDBProvider(t)
+			defer cleanup()
+			c.test(t, dbProvider)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+)
+
+// VersionedDBProviderFactory bundles the functions through which the ledger uses a state database
+// implementation that is registered by name
+type VersionedDBProviderFactory struct {
+	// NewProvider constructs the VersionedDBProvider of the implementation
+	NewProvider func(metricsProvider metrics.Provider) (VersionedDBProvider, error)
+	// DropAll drops the state databases of all the ledgers, so that these are rebuilt from the
+	// blocks the next time the peer starts, such as after a rollback of the ledgers
+	DropAll func() error
+}
+
+var (
+	factoriesLock sync.RWMutex
+	factories     = map[string]*VersionedDBProviderFactory{}
+)
+
+// RegisterVersionedDBProviderFactory makes a state database implementation available under the
+// given name, which is then selected by setting ledger.state.stateDatabase to the name in core.yaml.
+// It is expected to be invoked from the init function of the package of the implementation. It
+// panics if the name is already registered or if the factory is incomplete
+func RegisterVersionedDBProviderFactory(name string, factory *VersionedDBProviderFactory) {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	if factory == nil || factory.NewProvider == nil || factory.DropAll == nil {
+		panic("statedb: incomplete VersionedDBProviderFactory registered for state database " + name)
+	}
+	if _, ok := factories[name]; ok {
+		panic("statedb: RegisterVersionedDBProviderFactory called twice for state database " + name)
+	}
+	factories[name] = factory
+}
+
+// GetVersionedDBProviderFactory returns the factory of the state database registered under the given name
+func GetVersionedDBProviderFactory(name string) (*VersionedDBProviderFactory, error) {
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+	factory, ok := factories[name]
+	if !ok {
+		return nil, errors.Errorf("state database [%s] is not registered, the registered state databases are %v",
+			name, registeredNames())
+	}
+	return factory, nil
+}
+
+// RegisteredStateDatabases returns the names under which the state database implementations are registered
+func RegisteredStateDatabases() []string {
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+	return registeredNames()
+}
+
+func registeredNames() []string {
+	names := []string{}
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
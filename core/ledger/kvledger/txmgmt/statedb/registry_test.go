@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statedb
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterVersionedDBProviderFactory(t *testing.T) {
+	defer func() {
+		factoriesLock.Lock()
+		delete(factories, "testdb")
+		factoriesLock.Unlock()
+	}()
+
+	factory := &VersionedDBProviderFactory{
+		NewProvider: func(metrics.Provider) (VersionedDBProvider, error) { return nil, nil },
+		DropAll:     func() error { return nil },
+	}
+	RegisterVersionedDBProviderFactory("testdb", factory)
+	f, err := GetVersionedDBProviderFactory("testdb")
+	assert.NoError(t, err)
+	assert.Equal(t, factory, f)
+	assert.Contains(t, RegisteredStateDatabases(), "testdb")
+
+	_, err = GetVersionedDBProviderFactory("unknowndb")
+	assert.EqualError(t, err, "state database [unknowndb] is not registered, the registered state databases are [testdb]")
+
+	assert.PanicsWithValue(t, "statedb: RegisterVersionedDBProviderFactory called twice for state database testdb", func() {
+		RegisterVersionedDBProviderFactory("testdb", factory)
+	})
+	assert.PanicsWithValue(t, "statedb: incomplete VersionedDBProviderFactory registered for state database otherdb", func() {
+		RegisterVersionedDBProviderFactory("otherdb", &VersionedDBProviderFactory{DropAll: factory.DropAll})
+	})
+	assert.PanicsWithValue(t, "statedb: incomplete VersionedDBProviderFactory registered for state database otherdb", func() {
+		RegisterVersionedDBProviderFactory("otherdb", nil)
+	})
+}
@@ -8,6 +8,7 @@ package statecouchdb
 import (
 	"fmt"
 
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
@@ -20,6 +21,7 @@ type nsCommittersBuilder struct {
 	updates         map[string]*statedb.VersionedValue
 	db              *couchdb.CouchDatabase
 	revisions       map[string]string
+	encrypter       *encryption.Encrypter
 	subNsCommitters []batch
 }
 
@@ -45,7 +47,7 @@ func (vdb *VersionedDB) buildCommitters(updates *statedb.UpdateBatch) ([]batch,
 		}
 		// for each namespace, construct one builder with the corresponding couchdb handle and couch revisions
 		// that are already loaded into cache (during validation phase)
-		nsCommitterBuilder = append(nsCommitterBuilder, &nsCommittersBuilder{updates: nsUpdates, db: db, revisions: nsRevs})
+		nsCommitterBuilder = append(nsCommitterBuilder, &nsCommittersBuilder{updates: nsUpdates, db: db, revisions: nsRevs, encrypter: vdb.encrypter})
 	}
 	if err := executeBatches(nsCommitterBuilder); err != nil {
 		return nil, err
@@ -67,7 +69,7 @@ func (builder *nsCommittersBuilder) execute() error {
 	maxBacthSize := ledgerconfig.GetMaxBatchUpdateSize()
 	batchUpdateMap := make(map[string]*batchableDocument)
 	for key, vv := range builder.updates {
-		couchDoc, err := keyValToCouchDoc(&keyValue{key: key, VersionedValue: vv}, builder.revisions[key])
+		couchDoc, err := keyValToCouchDoc(&keyValue{key: key, VersionedValue: vv}, builder.revisions[key], builder.encrypter)
 		if err != nil {
 			return err
 		}
@@ -8,9 +8,11 @@ package statecouchdb
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"unicode/utf8"
 
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
@@ -64,7 +66,9 @@ func (v jsonValue) toBytes() ([]byte, error) {
 	return jsonBytes, err
 }
 
-func couchDocToKeyValue(doc *couchdb.CouchDoc) (*keyValue, error) {
+// couchDocToKeyValue converts a document to the key and its value, decrypting the value and the
+// metadata with the encrypter, if set
+func couchDocToKeyValue(doc *couchdb.CouchDoc, encrypter *encryption.Encrypter) (*keyValue, error) {
 	// initialize the return value
 	var returnValue []byte
 	var err error
@@ -105,6 +109,16 @@ func couchDocToKeyValue(doc *couchdb.CouchDoc) (*keyValue, error) {
 			return nil, err
 		}
 	}
+	if encrypter != nil {
+		if returnValue, err = encrypter.Decrypt(returnValue); err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error decrypting value of key [%s]", key))
+		}
+		if returnMetadata != nil {
+			if returnMetadata, err = encrypter.Decrypt(returnMetadata); err != nil {
+				return nil, errors.WithMessage(err, fmt.Sprintf("error decrypting metadata of key [%s]", key))
+			}
+		}
+	}
 	return &keyValue{key, &statedb.VersionedValue{
 		Value:    returnValue,
 		Metadata: returnMetadata,
@@ -112,7 +126,9 @@ func couchDocToKeyValue(doc *couchdb.CouchDoc) (*keyValue, error) {
 	}, nil
 }
 
-func keyValToCouchDoc(kv *keyValue, revision string) (*couchdb.CouchDoc, error) {
+// keyValToCouchDoc converts a key and its value to a document. When the encrypter is set, the
+// value and the metadata are encrypted, and the value is always stored as an attachment
+func keyValToCouchDoc(kv *keyValue, revision string, encrypter *encryption.Encrypter) (*couchdb.CouchDoc, error) {
 	type kvType int32
 	const (
 		kvTypeDelete = iota
@@ -126,6 +142,17 @@ func keyValToCouchDoc(kv *keyValue, revision string) (*couchdb.CouchDoc, error)
 	switch {
 	case value == nil:
 		kvtype = kvTypeDelete
+	case encrypter != nil:
+		var err error
+		if value, err = encrypter.Encrypt(value); err != nil {
+			return nil, err
+		}
+		if metadata != nil {
+			if metadata, err = encrypter.Encrypt(metadata); err != nil {
+				return nil, err
+			}
+		}
+		kvtype = kvTypeAttachment
 	// check for the case where the jsonMap is nil,  this will indicate
 	// a special case for the Unmarshal that results in a valid JSON returning nil
 	case json.Unmarshal(value, &jsonMap) == nil && jsonMap != nil:
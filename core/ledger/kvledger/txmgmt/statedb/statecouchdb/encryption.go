@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
+	"github.com/pkg/errors"
+)
+
+// Encryption metadata docid (key) for couchdb
+const encryptionMetadataDocID = "statedb_encryption"
+
+// encryptionMetadata is present in the metadata db of the channels whose state is encrypted, and
+// lists the keys the values and the metadata of the keys may be encrypted with. As the encrypted
+// values cannot be told apart from the plain ones, the state of a channel is either encrypted or not
+type encryptionMetadata struct {
+	Keys string `json:"Keys"`
+}
+
+// initEncryption checks that the state is encrypted only if the encrypter is set. The state of
+// a channel can only be encrypted from the start, and is otherwise to be rebuilt. When the current
+// key was rotated, the documents encrypted with the previous keys are re-encrypted in the background
+func (vdb *VersionedDB) initEncryption() error {
+	couchDoc, _, err := vdb.metadataDB.ReadDoc(encryptionMetadataDocID)
+	if err != nil {
+		return err
+	}
+	if vdb.encrypter == nil {
+		close(vdb.reencrypted)
+		if couchDoc != nil {
+			return errors.Errorf("the state database of channel [%s] is encrypted, the encryption must be enabled to open it", vdb.chainName)
+		}
+		return nil
+	}
+
+	var keys []byte
+	if couchDoc == nil {
+		savepoint, err := vdb.GetLatestSavePoint()
+		if err != nil {
+			return err
+		}
+		if savepoint != nil {
+			return errors.Errorf("the state database of channel [%s] is not encrypted, it needs to be rebuilt for the encryption to be enabled, such as with the command 'peer node rebuild-dbs'", vdb.chainName)
+		}
+	} else {
+		metadata := &encryptionMetadata{}
+		if err := json.Unmarshal(couchDoc.JSONValue, metadata); err != nil {
+			return errors.Wrap(err, "failed to unmarshal encryption metadata")
+		}
+		keys = []byte(metadata.Keys)
+	}
+	updatedKeys, err := vdb.encrypter.AddCurrentKey(keys)
+	if err != nil {
+		return errors.WithMessage(err, "error checking the encryption keys of the state database of channel "+vdb.chainName)
+	}
+	if couchDoc == nil || !bytes.Equal(updatedKeys, keys) {
+		if err := vdb.saveEncryptionMetadata(updatedKeys); err != nil {
+			return err
+		}
+	}
+	if vdb.encrypter.OnlyCurrentKey(updatedKeys) {
+		close(vdb.reencrypted)
+		return nil
+	}
+	go vdb.reencrypt()
+	return nil
+}
+
+func (vdb *VersionedDB) saveEncryptionMetadata(keys []byte) error {
+	metadataJSON, err := json.Marshal(&encryptionMetadata{Keys: string(keys)})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal encryption metadata")
+	}
+	_, err = vdb.metadataDB.SaveDoc(encryptionMetadataDocID, "", &couchdb.CouchDoc{JSONValue: metadataJSON})
+	return err
+}
+
+// reencrypt re-encrypts the documents of the namespaces of the channel, page by page, that
+// are encrypted with the previous keys. A document updated by a commit in the meantime is not
+// overwritten, as the update of its previous revision conflicts, and was encrypted with the
+// current key anyway. Once all the documents are re-encrypted, only the current key is listed
+func (vdb *VersionedDB) reencrypt() {
+	defer close(vdb.reencrypted)
+
+	vdb.channelMetadataLock.Lock()
+	if vdb.channelMetadata == nil {
+		vdb.channelMetadataLock.Unlock()
+		logger.Errorf("The state of channel [%s] cannot be re-encrypted as its namespaces are not recorded, the previous keys are still needed", vdb.chainName)
+		return
+	}
+	namespaces := append([]string{}, vdb.channelMetadata.Namespaces...)
+	vdb.channelMetadataLock.Unlock()
+
+	total := 0
+	for _, ns := range append(namespaces, "") {
+		count, err := vdb.reencryptNamespace(ns)
+		if err != nil {
+			logger.Errorf("Error re-encrypting the state of namespace [%s] of channel [%s], the previous keys are still needed: %s", ns, vdb.chainName, err)
+			return
+		}
+		total += count
+	}
+	if err := vdb.saveEncryptionMetadata(vdb.encrypter.CurrentKeys()); err != nil {
+		logger.Errorf("Error listing the encryption keys of the state database of channel [%s]: %s", vdb.chainName, err)
+		return
+	}
+	logger.Infof("Re-encrypted [%d] documents of the state database of channel [%s] with the current key, the previous keys are no longer needed", total, vdb.chainName)
+}
+
+func (vdb *VersionedDB) reencryptNamespace(ns string) (int, error) {
+	db, err := vdb.getNamespaceDBHandle(ns)
+	if err != nil {
+		return 0, err
+	}
+	queryLimit := int32(ledgerconfig.GetInternalQueryLimit())
+	startKey := ""
+	total := 0
+	for {
+		results, nextStartKey, err := rangeScanFilterCouchInternalDocs(db, startKey, "", queryLimit)
+		if err != nil {
+			return 0, err
+		}
+		var docs []*couchdb.CouchDoc
+		for _, result := range results {
+			doc, err := reencryptDoc(result, vdb.encrypter)
+			if err != nil {
+				return 0, err
+			}
+			if doc != nil {
+				docs = append(docs, doc)
+			}
+		}
+		if len(docs) > 0 {
+			resp, err := db.BatchUpdateDocuments(docs)
+			if err != nil {
+				return 0, err
+			}
+			for _, respDoc := range resp {
+				if respDoc.Ok {
+					total++
+					continue
+				}
+				logger.Debugf("Document [%s] of namespace [%s] was not re-encrypted as it was updated: %s", respDoc.ID, ns, respDoc.Reason)
+			}
+		}
+		if nextStartKey == "" {
+			return total, nil
+		}
+		startKey = nextStartKey
+	}
+}
+
+// reencryptDoc returns the document of the given result re-encrypted with the current key, or
+// nil if it is already encrypted with it. The document keeps its revision, so that its update
+// conflicts with any update committed since it was read.
+func reencryptDoc(result *couchdb.QueryResult, encrypter *encryption.Encrypter) (*couchdb.CouchDoc, error) {
+	jsonResult := make(map[string]interface{})
+	if err := json.Unmarshal(result.Value, &jsonResult); err != nil {
+		return nil, errors.Wrapf(err, "error unmarshalling document [%s]", result.ID)
+	}
+	if _, ok := jsonResult[versionField]; !ok {
+		// not a key of the state, such as the savepoint
+		return nil, nil
+	}
+	_, metadata, err := decodeVersionAndMetadata(jsonResult[versionField].(string))
+	if err != nil {
+		return nil, err
+	}
+	current := metadata == nil || encrypter.IsCurrent(metadata)
+	for _, attachment := range result.Attachments {
+		if attachment.Name == binaryWrapper && !encrypter.IsCurrent(attachment.AttachmentBytes) {
+			current = false
+		}
+	}
+	if current {
+		return nil, nil
+	}
+
+	kv, err := couchDocToKeyValue(&couchdb.CouchDoc{JSONValue: result.Value, Attachments: result.Attachments}, encrypter)
+	if err != nil {
+		return nil, err
+	}
+	revision, _ := jsonResult[revField].(string)
+	return keyValToCouchDoc(kv, revision, encrypter)
+}
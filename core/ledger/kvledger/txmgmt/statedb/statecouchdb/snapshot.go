@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
+	"github.com/pkg/errors"
+)
+
+// Channel metadata docid (key) for couchdb
+const channelMetadataDocID = "channel_metadata"
+
+// channelMetadata records the namespaces that have a database for the channel. The namespaces
+// cannot be derived from the names of the databases, which are escaped and truncated
+type channelMetadata struct {
+	Namespaces []string `json:"Namespaces"`
+}
+
+// loadChannelMetadata loads the channel metadata when the db is opened. A new db starts with
+// empty channel metadata, whereas the namespaces of a db that was populated before the channel
+// metadata was introduced are unknown and the channel metadata is left nil
+func (vdb *VersionedDB) loadChannelMetadata() error {
+	couchDoc, _, err := vdb.metadataDB.ReadDoc(channelMetadataDocID)
+	if err != nil {
+		return err
+	}
+	if couchDoc != nil && couchDoc.JSONValue != nil {
+		metadata := &channelMetadata{}
+		if err := json.Unmarshal(couchDoc.JSONValue, metadata); err != nil {
+			return errors.Wrap(err, "failed to unmarshal channel metadata")
+		}
+		vdb.channelMetadata = metadata
+		return nil
+	}
+	savepoint, err := vdb.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+	if savepoint != nil {
+		logger.Warningf("The namespaces of channel [%s] are not recorded in the state database, a snapshot cannot be generated until the state database is rebuilt", vdb.chainName)
+		return nil
+	}
+	return vdb.saveChannelMetadata(&channelMetadata{})
+}
+
+// recordNamespaces adds the namespaces that are not recorded yet to the channel metadata. This is
+// expected to be invoked before the savepoint of the updates to the namespaces is recorded
+func (vdb *VersionedDB) recordNamespaces(namespaces []string) error {
+	vdb.channelMetadataLock.Lock()
+	defer vdb.channelMetadataLock.Unlock()
+	if vdb.channelMetadata == nil {
+		return nil
+	}
+	recorded := make(map[string]bool)
+	for _, ns := range vdb.channelMetadata.Namespaces {
+		recorded[ns] = true
+	}
+	updatedNamespaces := append([]string{}, vdb.channelMetadata.Namespaces...)
+	for _, ns := range namespaces {
+		// the empty namespace is the metadataDB itself, which is not exported
+		if ns == "" || recorded[ns] {
+			continue
+		}
+		recorded[ns] = true
+		updatedNamespaces = append(updatedNamespaces, ns)
+	}
+	if len(updatedNamespaces) == len(vdb.channelMetadata.Namespaces) {
+		return nil
+	}
+	return vdb.saveChannelMetadata(&channelMetadata{Namespaces: updatedNamespaces})
+}
+
+func (vdb *VersionedDB) saveChannelMetadata(metadata *channelMetadata) error {
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal channel metadata")
+	}
+	if _, err := vdb.metadataDB.SaveDoc(channelMetadataDocID, "", &couchdb.CouchDoc{JSONValue: metadataJSON}); err != nil {
+		return err
+	}
+	vdb.channelMetadata = metadata
+	return nil
+}
+
+// GetFullScanIterator implements method in FullScanIteratorProvider interface. The namespaces
+// recorded in the channel metadata are scanned one after the other via their _all_docs
+func (vdb *VersionedDB) GetFullScanIterator() (statedb.FullScanIterator, error) {
+	vdb.channelMetadataLock.Lock()
+	defer vdb.channelMetadataLock.Unlock()
+	if vdb.channelMetadata == nil {
+		return nil, errors.Errorf("the namespaces of channel [%s] are not recorded in the state database, which needs to be rebuilt, such as with the command 'peer node rebuild-dbs'", vdb.chainName)
+	}
+	namespaces := append([]string{}, vdb.channelMetadata.Namespaces...)
+	sort.Strings(namespaces)
+	return &fullScanIterator{
+		vdb:                vdb,
+		namespaces:         namespaces,
+		internalQueryLimit: int32(ledgerconfig.GetInternalQueryLimit()),
+	}, nil
+}
+
+type fullScanIterator struct {
+	vdb                *VersionedDB
+	namespaces         []string
+	internalQueryLimit int32
+	db                 *couchdb.CouchDatabase
+	results            []*couchdb.QueryResult
+	cursor             int
+	nextStartKey       string
+}
+
+func (itr *fullScanIterator) Next() (*statedb.CompositeKey, *statedb.VersionedValue, error) {
+	for itr.cursor == len(itr.results) {
+		more, err := itr.fetchNextResults()
+		if err != nil {
+			return nil, nil, err
+		}
+		if !more {
+			return nil, nil, nil
+		}
+	}
+	result := itr.results[itr.cursor]
+	itr.cursor++
+	kv, err := couchDocToKeyValue(&couchdb.CouchDoc{JSONValue: result.Value, Attachments: result.Attachments}, itr.vdb.encrypter)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &statedb.CompositeKey{Namespace: itr.namespaces[0], Key: kv.key}, kv.VersionedValue, nil
+}
+
+// fetchNextResults fetches the next page of the current namespace or, once the current namespace
+// is exhausted, the first page of the next namespace. It returns false when all the namespaces are exhausted
+func (itr *fullScanIterator) fetchNextResults() (bool, error) {
+	if itr.db == nil || itr.nextStartKey == "" {
+		if itr.db != nil {
+			itr.namespaces = itr.namespaces[1:]
+			itr.db = nil
+		}
+		if len(itr.namespaces) == 0 {
+			return false, nil
+		}
+		db, err := itr.vdb.getNamespaceDBHandle(itr.namespaces[0])
+		if err != nil {
+			return false, err
+		}
+		itr.db = db
+	}
+	results, nextStartKey, err := rangeScanFilterCouchInternalDocs(itr.db, itr.nextStartKey, "", itr.internalQueryLimit)
+	if err != nil {
+		return false, err
+	}
+	itr.results = results
+	itr.cursor = 0
+	itr.nextStartKey = nextStartKey
+	return true, nil
+}
+
+func (itr *fullScanIterator) Close() {
+}
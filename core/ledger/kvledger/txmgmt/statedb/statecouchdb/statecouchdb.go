@@ -12,8 +12,10 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
@@ -28,6 +30,22 @@ var logger = flogging.MustGetLogger("statecouchdb")
 // currently defaulted to 0 and is not used
 const querySkip = 0
 
+// StateDatabaseName is the name under which the CouchDB based state database is registered
+const StateDatabaseName = "CouchDB"
+
+func init() {
+	statedb.RegisterVersionedDBProviderFactory(StateDatabaseName, &statedb.VersionedDBProviderFactory{
+		NewProvider: func(metricsProvider metrics.Provider) (statedb.VersionedDBProvider, error) {
+			provider, err := NewVersionedDBProvider(metricsProvider)
+			if err != nil {
+				return nil, err
+			}
+			return provider, nil
+		},
+		DropAll: DropApplicationDBs,
+	})
+}
+
 // LsccCacheSize denotes the number of entries allowed in the lsccStateCache
 const lsccCacheSize = 50
 
@@ -37,6 +55,7 @@ type VersionedDBProvider struct {
 	databases     map[string]*VersionedDB
 	mux           sync.Mutex
 	openCounts    uint64
+	encrypter     *encryption.Encrypter
 }
 
 // NewVersionedDBProvider instantiates VersionedDBProvider
@@ -48,7 +67,11 @@ func NewVersionedDBProvider(metricsProvider metrics.Provider) (*VersionedDBProvi
 	if err != nil {
 		return nil, err
 	}
-	return &VersionedDBProvider{couchInstance, make(map[string]*VersionedDB), sync.Mutex{}, 0}, nil
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error initializing the encryption of the state database")
+	}
+	return &VersionedDBProvider{couchInstance, make(map[string]*VersionedDB), sync.Mutex{}, 0, encrypter}, nil
 }
 
 // GetDBHandle gets the handle to a named database
@@ -58,7 +81,7 @@ func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.Version
 	vdb := provider.databases[dbName]
 	if vdb == nil {
 		var err error
-		vdb, err = newVersionedDB(provider.couchInstance, dbName)
+		vdb, err = newVersionedDB(provider.couchInstance, dbName, provider.encrypter)
 		if err != nil {
 			return nil, err
 		}
@@ -77,6 +100,35 @@ func (provider *VersionedDBProvider) HealthCheck(ctx context.Context) error {
 	return provider.couchInstance.HealthCheck(ctx)
 }
 
+// DropApplicationDBs drops all the application databases of the CouchDB instance
+// configured for the peer so that the state database gets rebuilt from the blocks
+// the next time the peer starts. If CouchDB is no longer the state database of the
+// peer, the instance is not waited for
+func DropApplicationDBs() error {
+	logger.Info("Dropping CouchDB application databases")
+	couchDBDef := couchdb.GetCouchDBDefinition()
+	maxRetriesOnStartup := couchDBDef.MaxRetriesOnStartup
+	if !ledgerconfig.IsCouchDBEnabled() {
+		maxRetriesOnStartup = 0
+	}
+	couchInstance, err := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+		couchDBDef.MaxRetries, maxRetriesOnStartup, couchDBDef.RequestTimeout, couchDBDef.CreateGlobalChangesDB, &disabled.Provider{})
+	if err != nil {
+		return err
+	}
+	dbNames, err := couchInstance.RetrieveApplicationDBNames()
+	if err != nil {
+		return err
+	}
+	for _, dbName := range dbNames {
+		db := &couchdb.CouchDatabase{CouchInstance: couchInstance, DBName: dbName}
+		if _, err := db.DropDatabase(); err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("error dropping CouchDB database %s", dbName))
+		}
+	}
+	return nil
+}
+
 // VersionedDB implements VersionedDB interface
 type VersionedDB struct {
 	couchInstance      *couchdb.CouchInstance
@@ -87,6 +139,12 @@ type VersionedDB struct {
 	verCacheLock       sync.RWMutex
 	mux                sync.RWMutex
 	lsccStateCache     *lsccStateCache
+	// channelMetadata records the namespaces of the channel, it is nil if they are unknown
+	channelMetadata     *channelMetadata
+	channelMetadataLock sync.Mutex
+	// encrypter, if set, encrypts the values and the metadata of the keys
+	encrypter   *encryption.Encrypter
+	reencrypted chan struct{}
 }
 
 type lsccStateCache struct {
@@ -139,7 +197,7 @@ func (l *lsccStateCache) evictARandomEntry() {
 }
 
 // newVersionedDB constructs an instance of VersionedDB
-func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string) (*VersionedDB, error) {
+func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string, encrypter *encryption.Encrypter) (*VersionedDB, error) {
 	// CreateCouchDatabase creates a CouchDB database object, as well as the underlying database if it does not exist
 	chainName := dbName
 	dbName = couchdb.ConstructMetadataDBName(dbName)
@@ -149,7 +207,7 @@ func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string) (*Versi
 		return nil, err
 	}
 	namespaceDBMap := make(map[string]*couchdb.CouchDatabase)
-	return &VersionedDB{
+	vdb := &VersionedDB{
 		couchInstance:      couchInstance,
 		metadataDB:         metadataDB,
 		chainName:          chainName,
@@ -158,7 +216,16 @@ func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string) (*Versi
 		lsccStateCache: &lsccStateCache{
 			cache: make(map[string]*statedb.VersionedValue),
 		},
-	}, nil
+		encrypter:   encrypter,
+		reencrypted: make(chan struct{}),
+	}
+	if err := vdb.loadChannelMetadata(); err != nil {
+		return nil, err
+	}
+	if err := vdb.initEncryption(); err != nil {
+		return nil, err
+	}
+	return vdb, nil
 }
 
 // getNamespaceDBHandle gets the handle to a named chaincode database
@@ -184,8 +251,13 @@ func (vdb *VersionedDB) getNamespaceDBHandle(namespace string) (*couchdb.CouchDa
 	return db, nil
 }
 
-// ProcessIndexesForChaincodeDeploy creates indexes for a specified namespace
+// ProcessIndexesForChaincodeDeploy creates indexes for a specified namespace. No index is created
+// when the state is encrypted, as the values cannot be indexed
 func (vdb *VersionedDB) ProcessIndexesForChaincodeDeploy(namespace string, fileEntries []*ccprovider.TarFileEntry) error {
+	if vdb.encrypter != nil && len(fileEntries) > 0 {
+		logger.Warningf("Channel [%s]: The indexes of namespace [%s] are not created, as the state is encrypted", vdb.chainName, namespace)
+		return nil
+	}
 	db, err := vdb.getNamespaceDBHandle(namespace)
 	if err != nil {
 		return err
@@ -301,7 +373,7 @@ func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.Version
 	if couchDoc == nil {
 		return nil, nil
 	}
-	kv, err := couchDocToKeyValue(couchDoc)
+	kv, err := couchDocToKeyValue(couchDoc, vdb.encrypter)
 	if err != nil {
 		return nil, err
 	}
@@ -361,7 +433,7 @@ func (vdb *VersionedDB) GetStateRangeScanIteratorWithMetadata(namespace string,
 	if err != nil {
 		return nil, err
 	}
-	return newQueryScanner(namespace, db, "", internalQueryLimit, requestedLimit, "", startKey, endKey)
+	return newQueryScanner(namespace, db, "", internalQueryLimit, requestedLimit, "", startKey, endKey, vdb.encrypter)
 }
 
 func (scanner *queryScanner) getNextStateRangeScanResults() error {
@@ -436,13 +508,16 @@ func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 // ExecuteQueryWithMetadata implements method in VersionedDB interface
 func (vdb *VersionedDB) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (statedb.QueryResultsIterator, error) {
 	logger.Debugf("Entering ExecuteQueryWithMetadata  namespace: %s,  query: %s,  metadata: %v", namespace, query, metadata)
+	if vdb.encrypter != nil {
+		return nil, errors.New("rich queries are not supported when the CouchDB state database is encrypted")
+	}
 	// Get the querylimit from core.yaml
 	internalQueryLimit := int32(ledgerconfig.GetInternalQueryLimit())
 	bookmark := ""
 	requestedLimit := int32(0)
 	// if metadata is provided, then validate and set provided options
 	if metadata != nil {
-		err := validateQueryMetadata(metadata)
+		err := statedb.ValidateQueryMetadata(metadata)
 		if err != nil {
 			return nil, err
 		}
@@ -462,7 +537,7 @@ func (vdb *VersionedDB) ExecuteQueryWithMetadata(namespace, query string, metada
 	if err != nil {
 		return nil, err
 	}
-	return newQueryScanner(namespace, db, queryString, internalQueryLimit, requestedLimit, bookmark, "", "")
+	return newQueryScanner(namespace, db, queryString, internalQueryLimit, requestedLimit, bookmark, "", "", nil)
 }
 
 // executeQueryWithBookmark executes a "paging" query with a bookmark, this method allows a
@@ -491,30 +566,6 @@ func (scanner *queryScanner) executeQueryWithBookmark() error {
 	return nil
 }
 
-func validateQueryMetadata(metadata map[string]interface{}) error {
-	for key, keyVal := range metadata {
-		switch key {
-		case optionBookmark:
-			//Verify the bookmark is a string
-			if _, ok := keyVal.(string); ok {
-				continue
-			}
-			return fmt.Errorf("Invalid entry, \"bookmark\" must be a string")
-
-		case optionLimit:
-			//Verify the limit is an integer
-			if _, ok := keyVal.(int32); ok {
-				continue
-			}
-			return fmt.Errorf("Invalid entry, \"limit\" must be an int32")
-
-		default:
-			return fmt.Errorf("Invalid entry, option %s not recognized", key)
-		}
-	}
-	return nil
-}
-
 // ApplyUpdates implements method in VersionedDB interface
 func (vdb *VersionedDB) ApplyUpdates(updates *statedb.UpdateBatch, height *version.Height) error {
 	// TODO a note about https://jira.hyperledger.org/browse/FAB-8622
@@ -535,6 +586,10 @@ func (vdb *VersionedDB) ApplyUpdates(updates *statedb.UpdateBatch, height *versi
 
 	// Stgae 3 - PostUpdateProcessing - flush and record savepoint.
 	namespaces := updates.GetUpdatedNamespaces()
+	// Record the namespaces before the savepoint so that a full scan does not miss any of them
+	if err = vdb.recordNamespaces(namespaces); err != nil {
+		return err
+	}
 	// Record a savepoint at a given height
 	if err = vdb.ensureFullCommitAndRecordSavepoint(height, namespaces); err != nil {
 		logger.Errorf("Error during recordSavepoint: %s", err.Error())
@@ -678,6 +733,7 @@ type queryScanner struct {
 	queryDefinition *queryDefinition
 	paginationInfo  *paginationInfo
 	resultsInfo     *resultsInfo
+	encrypter       *encryption.Encrypter
 }
 
 type queryDefinition struct {
@@ -699,8 +755,8 @@ type resultsInfo struct {
 }
 
 func newQueryScanner(namespace string, db *couchdb.CouchDatabase, query string, internalQueryLimit,
-	limit int32, bookmark, startKey, endKey string) (*queryScanner, error) {
-	scanner := &queryScanner{namespace, db, &queryDefinition{startKey, endKey, query, internalQueryLimit}, &paginationInfo{-1, limit, bookmark}, &resultsInfo{0, nil}}
+	limit int32, bookmark, startKey, endKey string, encrypter *encryption.Encrypter) (*queryScanner, error) {
+	scanner := &queryScanner{namespace, db, &queryDefinition{startKey, endKey, query, internalQueryLimit}, &paginationInfo{-1, limit, bookmark}, &resultsInfo{0, nil}, encrypter}
 	var err error
 	// query is defined, then execute the query and return the records and bookmark
 	if scanner.queryDefinition.query != "" {
@@ -747,7 +803,7 @@ func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
 	selectedResultRecord := scanner.resultsInfo.results[scanner.paginationInfo.cursor]
 	key := selectedResultRecord.ID
 	// remove the reserved fields from CouchDB JSON and return the value and version
-	kv, err := couchDocToKeyValue(&couchdb.CouchDoc{JSONValue: selectedResultRecord.Value, Attachments: selectedResultRecord.Attachments})
+	kv, err := couchDocToKeyValue(&couchdb.CouchDoc{JSONValue: selectedResultRecord.Value, Attachments: selectedResultRecord.Attachments}, scanner.encrypter)
 	if err != nil {
 		return nil, err
 	}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package statecouchdb
 
 import (
+	"archive/tar"
+	"bytes"
 	"fmt"
 	"os"
 	"strconv"
@@ -14,6 +16,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
@@ -647,51 +651,6 @@ func executeQuery(t *testing.T, db statedb.VersionedDB, namespace, query, bookma
 	return returnBookmark, nil
 }
 
-// TestPaginatedQueryValidation tests queries with pagination
-func TestPaginatedQueryValidation(t *testing.T) {
-
-	queryOptions := make(map[string]interface{})
-	queryOptions["bookmark"] = "Test1"
-	queryOptions["limit"] = int32(10)
-
-	err := validateQueryMetadata(queryOptions)
-	assert.NoError(t, err, "An error was thrown for a valid options")
-	queryOptions = make(map[string]interface{})
-	queryOptions["bookmark"] = "Test1"
-	queryOptions["limit"] = float64(10.2)
-
-	err = validateQueryMetadata(queryOptions)
-	assert.Error(t, err, "An should have been thrown for an invalid options")
-
-	queryOptions = make(map[string]interface{})
-	queryOptions["bookmark"] = "Test1"
-	queryOptions["limit"] = "10"
-
-	err = validateQueryMetadata(queryOptions)
-	assert.Error(t, err, "An should have been thrown for an invalid options")
-
-	queryOptions = make(map[string]interface{})
-	queryOptions["bookmark"] = int32(10)
-	queryOptions["limit"] = "10"
-
-	err = validateQueryMetadata(queryOptions)
-	assert.Error(t, err, "An should have been thrown for an invalid options")
-
-	queryOptions = make(map[string]interface{})
-	queryOptions["bookmark"] = "Test1"
-	queryOptions["limit1"] = int32(10)
-
-	err = validateQueryMetadata(queryOptions)
-	assert.Error(t, err, "An should have been thrown for an invalid options")
-
-	queryOptions = make(map[string]interface{})
-	queryOptions["bookmark1"] = "Test1"
-	queryOptions["limit1"] = int32(10)
-
-	err = validateQueryMetadata(queryOptions)
-	assert.Error(t, err, "An should have been thrown for an invalid options")
-}
-
 func TestLSCCStateCache(t *testing.T) {
 	env := NewTestVDBEnv(t)
 	defer env.Cleanup()
@@ -806,17 +765,17 @@ func TestRangeScanWithCouchInternalDocsPresent(t *testing.T) {
 	// The Keys in db are in this order
 	// Key-1, Key-2, Key-3,_design/indexAssetNam, _design/indexAssetValue, key-1, key-2, key-3
 	// query different ranges and verify results
-	s, err := newQueryScanner("ns", couchDatabse, "", 3, 3, "", "", "")
+	s, err := newQueryScanner("ns", couchDatabse, "", 3, 3, "", "", "", nil)
 	assert.NoError(t, err)
 	assertQueryResults(t, s.resultsInfo.results, []string{"Key-1", "Key-2", "Key-3"})
 	assert.Equal(t, "key-1", s.queryDefinition.startKey)
 
-	s, err = newQueryScanner("ns", couchDatabse, "", 4, 4, "", "", "")
+	s, err = newQueryScanner("ns", couchDatabse, "", 4, 4, "", "", "", nil)
 	assert.NoError(t, err)
 	assertQueryResults(t, s.resultsInfo.results, []string{"Key-1", "Key-2", "Key-3", "key-1"})
 	assert.Equal(t, "key-2", s.queryDefinition.startKey)
 
-	s, err = newQueryScanner("ns", couchDatabse, "", 2, 2, "", "", "")
+	s, err = newQueryScanner("ns", couchDatabse, "", 2, 2, "", "", "", nil)
 	assert.NoError(t, err)
 	assertQueryResults(t, s.resultsInfo.results, []string{"Key-1", "Key-2"})
 	assert.Equal(t, "Key-3", s.queryDefinition.startKey)
@@ -824,7 +783,7 @@ func TestRangeScanWithCouchInternalDocsPresent(t *testing.T) {
 	assertQueryResults(t, s.resultsInfo.results, []string{"Key-3", "key-1"})
 	assert.Equal(t, "key-2", s.queryDefinition.startKey)
 
-	s, err = newQueryScanner("ns", couchDatabse, "", 2, 2, "", "_", "")
+	s, err = newQueryScanner("ns", couchDatabse, "", 2, 2, "", "_", "", nil)
 	assert.NoError(t, err)
 	assertQueryResults(t, s.resultsInfo.results, []string{"key-1", "key-2"})
 	assert.Equal(t, "key-3", s.queryDefinition.startKey)
@@ -837,3 +796,135 @@ func assertQueryResults(t *testing.T, results []*couchdb.QueryResult, expectedId
 	}
 	assert.Equal(t, expectedIds, actualIds)
 }
+
+func TestFullScanIterator(t *testing.T) {
+	// a small internal query limit makes the iterator fetch each namespace over multiple pages
+	viper.Set("ledger.state.couchDBConfig.internalQueryLimit", 2)
+	defer viper.Set("ledger.state.couchDBConfig.internalQueryLimit", 1000)
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testfullscaniterator")
+	assert.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns2", "key1", []byte("value4"), version.NewHeight(1, 4))
+	batch.Put("ns1", "key1", []byte(`{"asset":"value1"}`), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.PutValAndMetadata("ns1", "key3", []byte("value3"), []byte("metadata3"), version.NewHeight(1, 3))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 4)))
+
+	vdb := db.(*VersionedDB)
+	itr, err := vdb.GetFullScanIterator()
+	assert.NoError(t, err)
+	var keys []statedb.CompositeKey
+	var values []*statedb.VersionedValue
+	for {
+		compositeKey, vv, err := itr.Next()
+		assert.NoError(t, err)
+		if compositeKey == nil {
+			break
+		}
+		keys = append(keys, *compositeKey)
+		values = append(values, vv)
+	}
+	itr.Close()
+	assert.Equal(t, []statedb.CompositeKey{
+		{Namespace: "ns1", Key: "key1"},
+		{Namespace: "ns1", Key: "key2"},
+		{Namespace: "ns1", Key: "key3"},
+		{Namespace: "ns2", Key: "key1"},
+	}, keys)
+	assert.Equal(t, []*statedb.VersionedValue{
+		{Value: []byte(`{"asset":"value1"}`), Version: version.NewHeight(1, 1)},
+		{Value: []byte("value2"), Version: version.NewHeight(1, 2)},
+		{Value: []byte("value3"), Metadata: []byte("metadata3"), Version: version.NewHeight(1, 3)},
+		{Value: []byte("value4"), Version: version.NewHeight(1, 4)},
+	}, values)
+
+	// the namespaces of a db populated before they were recorded are unknown
+	_, rev, err := vdb.metadataDB.ReadDoc(channelMetadataDocID)
+	assert.NoError(t, err)
+	assert.NoError(t, vdb.metadataDB.DeleteDoc(channelMetadataDocID, rev))
+	vdb.channelMetadata = nil
+	assert.NoError(t, vdb.loadChannelMetadata())
+	_, err = vdb.GetFullScanIterator()
+	assert.EqualError(t, err, "the namespaces of channel [testfullscaniterator] are not recorded in the state database, which needs to be rebuilt, such as with the command 'peer node rebuild-dbs'")
+}
+
+func TestEncryptedState(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	couchInstance := env.DBProvider.(*VersionedDBProvider).couchInstance
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	assert.NoError(t, err)
+	newEncrypter := func() *encryption.Encrypter {
+		ski, err := encryption.GenerateKey(csp)
+		assert.NoError(t, err)
+		encrypter, err := encryption.NewEncrypter(csp, encryption.NewKeyProvider(csp, ski))
+		assert.NoError(t, err)
+		return encrypter
+	}
+
+	encrypter := newEncrypter()
+	vdb, err := newVersionedDB(couchInstance, "testencryptedstate", encrypter)
+	assert.NoError(t, err)
+	env.DBProvider.(*VersionedDBProvider).databases["testencryptedstate"] = vdb
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte(`{"asset":"value1"}`), version.NewHeight(1, 1))
+	batch.PutValAndMetadata("ns1", "key2", []byte("value2"), []byte("metadata2"), version.NewHeight(1, 2))
+	assert.NoError(t, vdb.ApplyUpdates(batch, version.NewHeight(1, 2)))
+
+	// the values and the metadata are stored encrypted
+	db, err := vdb.getNamespaceDBHandle("ns1")
+	assert.NoError(t, err)
+	couchDoc, _, err := db.ReadDoc("key1")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(couchDoc.JSONValue), "value1")
+	assert.Len(t, couchDoc.Attachments, 1)
+	assert.True(t, encrypter.IsCurrent(couchDoc.Attachments[0].AttachmentBytes))
+	vv, err := vdb.GetState("ns1", "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedValue{Value: []byte("value2"), Metadata: []byte("metadata2"), Version: version.NewHeight(1, 2)}, vv)
+	itr, err := vdb.GetStateRangeScanIterator("ns1", "", "")
+	assert.NoError(t, err)
+	result, err := itr.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"asset":"value1"}`), result.(*statedb.VersionedKV).Value)
+	itr.Close()
+
+	// the values cannot be queried nor indexed
+	_, err = vdb.ExecuteQuery("ns1", `{"selector":{"asset":"value1"}}`)
+	assert.EqualError(t, err, "rich queries are not supported when the CouchDB state database is encrypted")
+	assert.NoError(t, vdb.ProcessIndexesForChaincodeDeploy("ns1", []*ccprovider.TarFileEntry{
+		{FileHeader: &tar.Header{Name: "META-INF/statedb/couchdb/indexes/index.json"}, FileContent: []byte(`{"index":{"fields":["asset"]},"name":"index","type":"json"}`)},
+	}))
+
+	// once the key is rotated, the documents are re-encrypted with the current key
+	rotatedEncrypter := newEncrypter()
+	vdb, err = newVersionedDB(couchInstance, "testencryptedstate", rotatedEncrypter)
+	assert.NoError(t, err)
+	<-vdb.reencrypted
+	for _, key := range []string{"key1", "key2"} {
+		couchDoc, _, err := db.ReadDoc(key)
+		assert.NoError(t, err)
+		assert.True(t, rotatedEncrypter.IsCurrent(couchDoc.Attachments[0].AttachmentBytes))
+	}
+	vv, err = vdb.GetState("ns1", "key2")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("metadata2"), vv.Metadata)
+	couchDoc, _, err = vdb.metadataDB.ReadDoc(encryptionMetadataDocID)
+	assert.NoError(t, err)
+	assert.Contains(t, string(couchDoc.JSONValue), string(bytes.TrimSpace(rotatedEncrypter.CurrentKeys())))
+	assert.NotContains(t, string(couchDoc.JSONValue), string(bytes.TrimSpace(encrypter.CurrentKeys())))
+
+	// an encrypted state cannot be opened without the encryption, nor a plain state with it
+	_, err = newVersionedDB(couchInstance, "testencryptedstate", nil)
+	assert.EqualError(t, err, "the state database of channel [testencryptedstate] is encrypted, the encryption must be enabled to open it")
+	plainDB, err := env.DBProvider.GetDBHandle("testplainstate")
+	assert.NoError(t, err)
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	assert.NoError(t, plainDB.ApplyUpdates(batch, version.NewHeight(1, 1)))
+	_, err = newVersionedDB(couchInstance, "testplainstate", encrypter)
+	assert.EqualError(t, err, "the state database of channel [testplainstate] is not encrypted, it needs to be rebuilt for the encryption to be enabled, such as with the command 'peer node rebuild-dbs'")
+}
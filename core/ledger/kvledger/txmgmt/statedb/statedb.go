@@ -88,6 +88,19 @@ type IndexCapable interface {
 	ProcessIndexesForChaincodeDeploy(namespace string, fileEntries []*ccprovider.TarFileEntry) error
 }
 
+// FullScanIteratorProvider interface provides an additional function for
+// databases capable of exporting all their content, such as for generating a snapshot
+type FullScanIteratorProvider interface {
+	GetFullScanIterator() (FullScanIterator, error)
+}
+
+// FullScanIterator iterates over all the keys of all the namespaces in the db.
+// The function Next returns a nil key when the iterator is exhausted
+type FullScanIterator interface {
+	Next() (*CompositeKey, *VersionedValue, error)
+	Close()
+}
+
 // CompositeKey encloses Namespace and Key components
 type CompositeKey struct {
 	Namespace string
@@ -284,6 +297,7 @@ func (itr *nsIterator) GetBookmarkAndClose() string {
 }
 
 const optionLimit = "limit"
+const optionBookmark = "bookmark"
 
 // ValidateRangeMetadata validates the JSON containing attributes for the range query
 func ValidateRangeMetadata(metadata map[string]interface{}) error {
@@ -303,3 +317,28 @@ func ValidateRangeMetadata(metadata map[string]interface{}) error {
 	}
 	return nil
 }
+
+// ValidateQueryMetadata validates the JSON containing attributes for the rich query
+func ValidateQueryMetadata(metadata map[string]interface{}) error {
+	for key, keyVal := range metadata {
+		switch key {
+		case optionBookmark:
+			//Verify the bookmark is a string
+			if _, ok := keyVal.(string); ok {
+				continue
+			}
+			return fmt.Errorf("Invalid entry, \"bookmark\" must be a string")
+
+		case optionLimit:
+			//Verify the limit is an integer
+			if _, ok := keyVal.(int32); ok {
+				continue
+			}
+			return fmt.Errorf("Invalid entry, \"limit\" must be an int32")
+
+		default:
+			return fmt.Errorf("Invalid entry, option %s not recognized", key)
+		}
+	}
+	return nil
+}
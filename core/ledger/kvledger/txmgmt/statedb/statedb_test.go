@@ -163,3 +163,48 @@ func TestPaginatedRangeValidation(t *testing.T) {
 	assert.Error(t, err, "An should have been thrown for an invalid option")
 
 }
+
+// TestPaginatedQueryValidation tests queries with pagination
+func TestPaginatedQueryValidation(t *testing.T) {
+
+	queryOptions := make(map[string]interface{})
+	queryOptions["bookmark"] = "Test1"
+	queryOptions["limit"] = int32(10)
+
+	err := ValidateQueryMetadata(queryOptions)
+	assert.NoError(t, err, "An error was thrown for a valid options")
+	queryOptions = make(map[string]interface{})
+	queryOptions["bookmark"] = "Test1"
+	queryOptions["limit"] = float64(10.2)
+
+	err = ValidateQueryMetadata(queryOptions)
+	assert.Error(t, err, "An should have been thrown for an invalid options")
+
+	queryOptions = make(map[string]interface{})
+	queryOptions["bookmark"] = "Test1"
+	queryOptions["limit"] = "10"
+
+	err = ValidateQueryMetadata(queryOptions)
+	assert.Error(t, err, "An should have been thrown for an invalid options")
+
+	queryOptions = make(map[string]interface{})
+	queryOptions["bookmark"] = int32(10)
+	queryOptions["limit"] = "10"
+
+	err = ValidateQueryMetadata(queryOptions)
+	assert.Error(t, err, "An should have been thrown for an invalid options")
+
+	queryOptions = make(map[string]interface{})
+	queryOptions["bookmark"] = "Test1"
+	queryOptions["limit1"] = int32(10)
+
+	err = ValidateQueryMetadata(queryOptions)
+	assert.Error(t, err, "An should have been thrown for an invalid options")
+
+	queryOptions = make(map[string]interface{})
+	queryOptions["bookmark1"] = "Test1"
+	queryOptions["limit1"] = int32(10)
+
+	err = ValidateQueryMetadata(queryOptions)
+	assert.Error(t, err, "An should have been thrown for an invalid options")
+}
@@ -0,0 +1,342 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// The secondary indexes are stored in the db of the channel along with the state, so that the
+// index entries are updated atomically with the state. The index keys share a prefix that sorts
+// before the composite keys of the state, and are skipped by the full scan of the state
+var (
+	indexKeyPrefix           = []byte{0x00, 0xff}
+	indexKeysEnd             = util.BytesPrefix(indexKeyPrefix).Limit
+	indexDefinitionKeyPrefix = append(append([]byte{}, indexKeyPrefix...), 'd')
+	indexEntryKeyPrefix      = append(append([]byte{}, indexKeyPrefix...), 'e')
+	indexEntryValue          = []byte{}
+)
+
+// indexDefinition is a secondary index of the JSON documents of a namespace. The index entries are
+// ordered by the values of the indexed fields and then by the key. A document without all the
+// indexed fields is not indexed
+type indexDefinition struct {
+	Name   string   `json:"name"`
+	DDoc   string   `json:"ddoc,omitempty"`
+	Fields []string `json:"fields"`
+	fields []*field
+}
+
+// parseIndexDefinition parses an index definition in the format of the CouchDB index definitions, such as
+// {"index":{"fields":["owner","size"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}. The name
+// of the index defaults to the file name. The sort direction of the fields is ignored as the index
+// can be scanned in both directions
+func parseIndexDefinition(fileName string, content []byte) (*indexDefinition, error) {
+	jsonDefinition := &struct {
+		Index *struct {
+			Fields                []interface{}          `json:"fields"`
+			PartialFilterSelector map[string]interface{} `json:"partial_filter_selector"`
+		} `json:"index"`
+		DDoc string `json:"ddoc"`
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}{}
+	if err := json.Unmarshal(content, jsonDefinition); err != nil {
+		return nil, errors.Wrap(err, "error parsing index definition")
+	}
+	if jsonDefinition.Index == nil || len(jsonDefinition.Index.Fields) == 0 {
+		return nil, errors.New("index definition must include the fields of the index")
+	}
+	if jsonDefinition.Index.PartialFilterSelector != nil {
+		return nil, errors.New("partial_filter_selector is not supported by the leveldb state database")
+	}
+	if jsonDefinition.Type != "" && jsonDefinition.Type != "json" {
+		return nil, errors.New("index type must be json")
+	}
+	index := &indexDefinition{
+		Name: jsonDefinition.Name,
+		DDoc: strings.TrimPrefix(jsonDefinition.DDoc, "_design/"),
+	}
+	if index.Name == "" {
+		index.Name = strings.TrimSuffix(filepath.Base(fileName), ".json")
+	}
+	if index.Name == "" || strings.ContainsRune(index.Name, 0) {
+		return nil, errors.Errorf("index name [%s] is not valid", index.Name)
+	}
+	for _, item := range jsonDefinition.Index.Fields {
+		switch f := item.(type) {
+		case string:
+			index.Fields = append(index.Fields, f)
+		case map[string]interface{}:
+			if len(f) != 1 {
+				return nil, errors.Errorf("index field [%v] must have a single field name", f)
+			}
+			for name := range f {
+				index.Fields = append(index.Fields, name)
+			}
+		default:
+			return nil, errors.Errorf("index field [%v] must be a field name or an object", item)
+		}
+	}
+	index.init()
+	return index, nil
+}
+
+func (index *indexDefinition) init() {
+	index.fields = nil
+	for _, name := range index.Fields {
+		index.fields = append(index.fields, newField(name))
+	}
+}
+
+func (index *indexDefinition) sameFields(other *indexDefinition) bool {
+	if len(index.Fields) != len(other.Fields) {
+		return false
+	}
+	for i, name := range index.Fields {
+		if name != other.Fields[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// position returns the position of the document in the index, which is the concatenation of the
+// encoded values of the indexed fields and the key. It returns nil if the document is not indexed
+func (index *indexDefinition) position(doc map[string]interface{}, key string) []byte {
+	var position []byte
+	for _, f := range index.fields {
+		value, ok := f.lookup(doc, key)
+		if !ok {
+			return nil
+		}
+		position = append(position, encodeIndexValue(value)...)
+	}
+	return append(position, key...)
+}
+
+// keyFromPosition returns the key of the document at the given position in the index
+func (index *indexDefinition) keyFromPosition(position []byte) (string, error) {
+	offset := 0
+	for range index.fields {
+		n, err := encodedIndexValueLen(position[offset:])
+		if err != nil {
+			return "", err
+		}
+		offset += n
+	}
+	return string(position[offset:]), nil
+}
+
+func constructIndexDefinitionKey(ns, indexName string) []byte {
+	key := append(append([]byte{}, indexDefinitionKeyPrefix...), ns...)
+	return append(append(key, 0x00), indexName...)
+}
+
+func constructIndexEntriesPrefix(ns, indexName string) []byte {
+	prefix := append(append([]byte{}, indexEntryKeyPrefix...), ns...)
+	prefix = append(append(prefix, 0x00), indexName...)
+	return append(prefix, 0x00)
+}
+
+// decodeJSONDocument decodes a value of the state as a JSON document. It returns false if the
+// value is not a JSON object, as such values are neither indexed nor matched by the rich queries
+func decodeJSONDocument(value []byte) (map[string]interface{}, bool) {
+	if len(value) == 0 || value[0] != '{' {
+		return nil, false
+	}
+	doc := map[string]interface{}{}
+	if err := unmarshalJSON(value, &doc); err != nil {
+		return nil, false
+	}
+	return doc, true
+}
+
+// loadIndexDefinitions loads the definitions of the indexes of all the namespaces from the db
+func loadIndexDefinitions(db *leveldbhelper.DBHandle) (map[string][]*indexDefinition, error) {
+	indexes := map[string][]*indexDefinition{}
+	itr := db.GetIterator(indexDefinitionKeyPrefix, util.BytesPrefix(indexDefinitionKeyPrefix).Limit)
+	defer itr.Release()
+	for itr.Next() {
+		ns := string(bytes.SplitN(itr.Key()[len(indexDefinitionKeyPrefix):], []byte{0x00}, 2)[0])
+		value := itr.Value()
+		if itr.Error() != nil {
+			break
+		}
+		index := &indexDefinition{}
+		if err := json.Unmarshal(value, index); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshaling definition of an index of namespace [%s]", ns)
+		}
+		index.init()
+		indexes[ns] = append(indexes[ns], index)
+	}
+	return indexes, errors.Wrap(itr.Error(), "error loading index definitions")
+}
+
+// dropIndexes deletes the definitions and the entries of the indexes of all the namespaces
+func dropIndexes(db *leveldbhelper.DBHandle, dbName string) error {
+	dbBatch := leveldbhelper.NewUpdateBatch()
+	itr := db.GetIterator(indexKeyPrefix, indexKeysEnd)
+	defer itr.Release()
+	for itr.Next() {
+		dbBatch.Delete(append([]byte{}, itr.Key()...))
+	}
+	if err := itr.Error(); err != nil {
+		return errors.Wrap(err, "error dropping indexes")
+	}
+	if dbBatch.Len() == 0 {
+		return nil
+	}
+	logger.Infof("Channel [%s]: Dropping the indexes, as the state is encrypted", dbName)
+	return db.WriteBatch(dbBatch, true)
+}
+
+// addIndexUpdates adds to the batch the changes of the index entries that result from the update of a key
+func (vdb *versionedDB) addIndexUpdates(dbBatch *leveldbhelper.UpdateBatch, ns, key string, vv *statedb.VersionedValue, indexes []*indexDefinition) error {
+	committedVV, err := vdb.GetState(ns, key)
+	if err != nil {
+		return err
+	}
+	if committedVV != nil {
+		if doc, ok := decodeJSONDocument(committedVV.Value); ok {
+			for _, index := range indexes {
+				if position := index.position(doc, key); position != nil {
+					dbBatch.Delete(append(constructIndexEntriesPrefix(ns, index.Name), position...))
+				}
+			}
+		}
+	}
+	if vv.Value == nil {
+		return nil
+	}
+	if doc, ok := decodeJSONDocument(vv.Value); ok {
+		for _, index := range indexes {
+			if position := index.position(doc, key); position != nil {
+				dbBatch.Put(append(constructIndexEntriesPrefix(ns, index.Name), position...), indexEntryValue)
+			}
+		}
+	}
+	return nil
+}
+
+// ProcessIndexesForChaincodeDeploy creates the indexes for a namespace from the index definitions
+// in the META-INF/statedb/leveldb directory of the chaincode. An index is created from the existing
+// documents of the namespace. An index that exists already is rebuilt if its fields are changed.
+// No index is created when the state is encrypted, and the rich queries scan the namespace instead
+func (vdb *versionedDB) ProcessIndexesForChaincodeDeploy(namespace string, fileEntries []*ccprovider.TarFileEntry) error {
+	if vdb.encrypted && len(fileEntries) > 0 {
+		logger.Warningf("Channel [%s]: The indexes of namespace [%s] are not created, as the state is encrypted", vdb.dbName, namespace)
+		return nil
+	}
+	for _, fileEntry := range fileEntries {
+		filename := fileEntry.FileHeader.Name
+		index, err := parseIndexDefinition(filename, fileEntry.FileContent)
+		if err == nil {
+			err = vdb.createIndex(namespace, index)
+		}
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf(
+				"error creating index from file [%s] for namespace [%s]", filename, namespace))
+		}
+	}
+	return nil
+}
+
+// GetDBType returns the name of the directory of the index definitions in the chaincode package
+func (vdb *versionedDB) GetDBType() string {
+	return "leveldb"
+}
+
+func (vdb *versionedDB) createIndex(ns string, index *indexDefinition) error {
+	vdb.indexesLock.Lock()
+	defer vdb.indexesLock.Unlock()
+
+	indexes := vdb.indexes[ns]
+	existingPos := -1
+	for i, existing := range indexes {
+		if existing.Name == index.Name {
+			existingPos = i
+		}
+	}
+	if existingPos != -1 && indexes[existingPos].sameFields(index) && indexes[existingPos].DDoc == index.DDoc {
+		logger.Debugf("Channel [%s]: Index [%s] of namespace [%s] exists already", vdb.dbName, index.Name, ns)
+		return nil
+	}
+
+	logger.Infof("Channel [%s]: Building index [%s] of namespace [%s] on fields %v", vdb.dbName, index.Name, ns, index.Fields)
+	dbBatch := leveldbhelper.NewUpdateBatch()
+	entriesPrefix := constructIndexEntriesPrefix(ns, index.Name)
+	if existingPos != -1 {
+		if err := vdb.deleteKeys(dbBatch, entriesPrefix); err != nil {
+			return err
+		}
+	}
+	compositeStartKey := constructCompositeKey(ns, "")
+	compositeEndKey := constructCompositeKey(ns, "")
+	compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
+	dbItr := vdb.db.GetIterator(compositeStartKey, compositeEndKey)
+	defer dbItr.Release()
+	for dbItr.Next() {
+		_, key := splitCompositeKey(dbItr.Key())
+		dbVal := dbItr.Value()
+		if dbItr.Error() != nil {
+			break
+		}
+		dbValCopy := make([]byte, len(dbVal))
+		copy(dbValCopy, dbVal)
+		vv, err := decodeValue(dbValCopy)
+		if err != nil {
+			return err
+		}
+		doc, ok := decodeJSONDocument(vv.Value)
+		if !ok {
+			continue
+		}
+		if position := index.position(doc, key); position != nil {
+			dbBatch.Put(append(append([]byte{}, entriesPrefix...), position...), indexEntryValue)
+		}
+	}
+	if err := dbItr.Error(); err != nil {
+		return errors.Wrapf(err, "error building index [%s] of namespace [%s]", index.Name, ns)
+	}
+	definitionBytes, err := json.Marshal(index)
+	if err != nil {
+		return errors.Wrap(err, "error marshaling index definition")
+	}
+	dbBatch.Put(constructIndexDefinitionKey(ns, index.Name), definitionBytes)
+	if err := vdb.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+
+	if existingPos != -1 {
+		indexes = append(indexes[:existingPos:existingPos], indexes[existingPos+1:]...)
+	}
+	indexes = append(indexes, index)
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i].Name < indexes[j].Name })
+	vdb.indexes[ns] = indexes
+	return nil
+}
+
+func (vdb *versionedDB) deleteKeys(dbBatch *leveldbhelper.UpdateBatch, prefix []byte) error {
+	dbItr := vdb.db.GetIterator(prefix, util.BytesPrefix(prefix).Limit)
+	defer dbItr.Release()
+	for dbItr.Next() {
+		dbBatch.Delete(append([]byte{}, dbItr.Key()...))
+	}
+	return errors.Wrap(dbItr.Error(), "error deleting index entries")
+}
@@ -0,0 +1,576 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// the options of a rich query, which follow the Mango query syntax of CouchDB
+const (
+	queryOptionSelector = "selector"
+	queryOptionSort     = "sort"
+	queryOptionLimit    = "limit"
+	queryOptionSkip     = "skip"
+	queryOptionBookmark = "bookmark"
+	queryOptionFields   = "fields"
+	queryOptionUseIndex = "use_index"
+)
+
+// idField is the pseudo field that selects the key of a document, as in CouchDB
+const idField = "_id"
+
+// richQuery is a parsed rich query. The LevelDB state database supports a subset of the Mango
+// query syntax of CouchDB: the selector operators $eq, $ne, $gt, $gte, $lt, $lte, $in, $exists,
+// $and, $or and $not, along with the sort, limit, skip, bookmark, fields and use_index options
+type richQuery struct {
+	selector selector
+	sort     []*sortField
+	limit    int32
+	skip     int32
+	bookmark string
+	fields   []*field
+	useIndex *useIndex
+}
+
+type sortField struct {
+	field *field
+	desc  bool
+}
+
+type useIndex struct {
+	ddoc string
+	name string
+}
+
+// parseQuery parses the JSON query string of a rich query
+func parseQuery(queryString string) (*richQuery, error) {
+	jsonQuery := map[string]interface{}{}
+	if err := unmarshalJSON([]byte(queryString), &jsonQuery); err != nil {
+		return nil, errors.Wrapf(err, "error parsing query [%s]", queryString)
+	}
+	jsonSelector, ok := jsonQuery[queryOptionSelector]
+	if !ok {
+		return nil, errors.Errorf("query [%s] does not include a selector", queryString)
+	}
+	q := &richQuery{}
+	var err error
+	if q.selector, err = parseSelector(jsonSelector, ""); err != nil {
+		return nil, err
+	}
+	for option, value := range jsonQuery {
+		switch option {
+		case queryOptionSelector:
+		case queryOptionSort:
+			q.sort, err = parseSort(value)
+		case queryOptionLimit:
+			q.limit, err = parseCount(queryOptionLimit, value)
+		case queryOptionSkip:
+			q.skip, err = parseCount(queryOptionSkip, value)
+		case queryOptionBookmark:
+			if q.bookmark, ok = value.(string); !ok {
+				err = errors.New("bookmark must be a string")
+			}
+		case queryOptionFields:
+			q.fields, err = parseFields(value)
+		case queryOptionUseIndex:
+			q.useIndex, err = parseUseIndex(value)
+		default:
+			err = errors.Errorf("query option [%s] is not supported by the leveldb state database", option)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return q, nil
+}
+
+func parseSort(value interface{}) ([]*sortField, error) {
+	jsonSort, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("sort must be an array of fields")
+	}
+	var sortFields []*sortField
+	for _, item := range jsonSort {
+		switch s := item.(type) {
+		case string:
+			sortFields = append(sortFields, &sortField{field: newField(s)})
+		case map[string]interface{}:
+			if len(s) != 1 {
+				return nil, errors.Errorf("sort field [%v] must have a single field name", s)
+			}
+			for name, direction := range s {
+				switch direction {
+				case "asc":
+					sortFields = append(sortFields, &sortField{field: newField(name)})
+				case "desc":
+					sortFields = append(sortFields, &sortField{field: newField(name), desc: true})
+				default:
+					return nil, errors.Errorf("sort direction [%v] of field [%s] must be either asc or desc", direction, name)
+				}
+			}
+		default:
+			return nil, errors.Errorf("sort field [%v] must be a field name or an object", item)
+		}
+	}
+	return sortFields, nil
+}
+
+func parseCount(option string, value interface{}) (int32, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		return 0, errors.Errorf("%s must be a number", option)
+	}
+	count, err := number.Int64()
+	if err != nil || count < 0 || count > math.MaxInt32 {
+		return 0, errors.Errorf("%s [%s] must be a non-negative integer", option, number)
+	}
+	return int32(count), nil
+}
+
+func parseFields(value interface{}) ([]*field, error) {
+	jsonFields, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("fields definition must be an array")
+	}
+	var fields []*field
+	for _, item := range jsonFields {
+		name, ok := item.(string)
+		if !ok {
+			return nil, errors.Errorf("field [%v] must be a string", item)
+		}
+		fields = append(fields, newField(name))
+	}
+	return fields, nil
+}
+
+// parseUseIndex parses the use_index option, which is either the design document of the index
+// or an array with the design document and the name of the index
+func parseUseIndex(value interface{}) (*useIndex, error) {
+	var names []string
+	switch v := value.(type) {
+	case string:
+		names = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			name, ok := item.(string)
+			if !ok {
+				names = nil
+				break
+			}
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 || len(names) > 2 {
+		return nil, errors.New("use_index must be a design document name or an array of a design document name and an index name")
+	}
+	u := &useIndex{ddoc: strings.TrimPrefix(names[0], "_design/")}
+	if len(names) == 2 {
+		u.name = names[1]
+	}
+	return u, nil
+}
+
+func (u *useIndex) matches(index *indexDefinition) bool {
+	return (u.ddoc == "" || u.ddoc == index.DDoc) && (u.name == "" || u.name == index.Name)
+}
+
+// field is a field of a JSON document, the segments of nested fields are separated by dots
+type field struct {
+	name     string
+	segments []string
+}
+
+func newField(name string) *field {
+	return &field{name, strings.Split(name, ".")}
+}
+
+// lookup returns the value of the field in the document and whether the document has the field
+func (f *field) lookup(doc map[string]interface{}, key string) (interface{}, bool) {
+	if f.name == idField {
+		return key, true
+	}
+	var value interface{} = doc
+	for _, segment := range f.segments {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[segment]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// project returns a document with only the given fields of the document
+func project(doc map[string]interface{}, fields []*field) map[string]interface{} {
+	projection := map[string]interface{}{}
+	for _, f := range fields {
+		value, ok := f.lookup(doc, "")
+		if !ok || f.name == idField {
+			continue
+		}
+		m := projection
+		for _, segment := range f.segments[:len(f.segments)-1] {
+			sub, ok := m[segment].(map[string]interface{})
+			if !ok {
+				sub = map[string]interface{}{}
+				m[segment] = sub
+			}
+			m = sub
+		}
+		m[f.segments[len(f.segments)-1]] = value
+	}
+	return projection
+}
+
+// selector matches the documents that satisfy the selector of a rich query
+type selector interface {
+	matches(doc map[string]interface{}, key string) bool
+}
+
+type andSelector []selector
+
+func (s andSelector) matches(doc map[string]interface{}, key string) bool {
+	for _, sub := range s {
+		if !sub.matches(doc, key) {
+			return false
+		}
+	}
+	return true
+}
+
+type orSelector []selector
+
+func (s orSelector) matches(doc map[string]interface{}, key string) bool {
+	for _, sub := range s {
+		if sub.matches(doc, key) {
+			return true
+		}
+	}
+	return false
+}
+
+type notSelector struct {
+	selector
+}
+
+func (s *notSelector) matches(doc map[string]interface{}, key string) bool {
+	return !s.selector.matches(doc, key)
+}
+
+// condition is a condition on a single field. The operands are compared in their index encoding,
+// which orders the values of different types as CouchDB does: null, false, true, numbers, strings,
+// arrays and objects. The range operators only match the values of the same type as the operand
+type condition struct {
+	field    *field
+	operator string
+	operands [][]byte
+	exists   bool
+}
+
+func (c *condition) matches(doc map[string]interface{}, key string) bool {
+	value, ok := c.field.lookup(doc, key)
+	if c.operator == "$exists" {
+		return ok == c.exists
+	}
+	if !ok {
+		return false
+	}
+	encodedValue := encodeIndexValue(value)
+	switch c.operator {
+	case "$in":
+		for _, operand := range c.operands {
+			if bytes.Equal(encodedValue, operand) {
+				return true
+			}
+		}
+		return false
+	case "$eq":
+		return bytes.Equal(encodedValue, c.operands[0])
+	case "$ne":
+		return !bytes.Equal(encodedValue, c.operands[0])
+	}
+	operand := c.operands[0]
+	if encodedValue[0] != operand[0] {
+		return false
+	}
+	cmp := bytes.Compare(encodedValue, operand)
+	switch c.operator {
+	case "$gt":
+		return cmp > 0
+	case "$gte":
+		return cmp >= 0
+	case "$lt":
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+// impliesExistence returns true if the documents that satisfy the condition have the field
+func (c *condition) impliesExistence() bool {
+	return c.operator != "$exists" || c.exists
+}
+
+// parseSelector parses a selector, the prefix is the name of the parent field of a nested selector
+func parseSelector(value interface{}, prefix string) (selector, error) {
+	jsonSelector, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf("selector [%v] must be a JSON object", value)
+	}
+	var selectors andSelector
+	for _, name := range sortedNames(jsonSelector) {
+		value := jsonSelector[name]
+		switch name {
+		case "$and", "$or":
+			list, ok := value.([]interface{})
+			if !ok || len(list) == 0 {
+				return nil, errors.Errorf("operator [%s] must be followed by a non-empty array of selectors", name)
+			}
+			var subs []selector
+			for _, item := range list {
+				sub, err := parseSelector(item, prefix)
+				if err != nil {
+					return nil, err
+				}
+				subs = append(subs, sub)
+			}
+			if name == "$and" {
+				selectors = append(selectors, andSelector(subs))
+			} else {
+				selectors = append(selectors, orSelector(subs))
+			}
+		case "$not":
+			sub, err := parseSelector(value, prefix)
+			if err != nil {
+				return nil, err
+			}
+			selectors = append(selectors, &notSelector{sub})
+		default:
+			if strings.HasPrefix(name, "$") {
+				return nil, errors.Errorf("operator [%s] is not supported by the leveldb state database", name)
+			}
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+			sub, err := parseFieldSelector(name, value)
+			if err != nil {
+				return nil, err
+			}
+			selectors = append(selectors, sub)
+		}
+	}
+	if len(selectors) == 1 {
+		return selectors[0], nil
+	}
+	return selectors, nil
+}
+
+// parseFieldSelector parses the selector of a field, which is either a value that the field equals,
+// an object of operators or a nested selector
+func parseFieldSelector(name string, value interface{}) (selector, error) {
+	jsonOperators, ok := value.(map[string]interface{})
+	if !ok || len(jsonOperators) == 0 {
+		return &condition{field: newField(name), operator: "$eq", operands: [][]byte{encodeIndexValue(value)}}, nil
+	}
+	names := sortedNames(jsonOperators)
+	if !strings.HasPrefix(names[0], "$") {
+		return parseSelector(jsonOperators, name)
+	}
+	var selectors andSelector
+	for _, operator := range names {
+		operand := jsonOperators[operator]
+		c := &condition{field: newField(name), operator: operator}
+		switch operator {
+		case "$eq", "$ne", "$gt", "$gte", "$lt", "$lte":
+			c.operands = [][]byte{encodeIndexValue(operand)}
+		case "$in":
+			list, ok := operand.([]interface{})
+			if !ok {
+				return nil, errors.Errorf("operator [$in] of field [%s] must be followed by an array", name)
+			}
+			for _, item := range list {
+				c.operands = append(c.operands, encodeIndexValue(item))
+			}
+		case "$exists":
+			if c.exists, ok = operand.(bool); !ok {
+				return nil, errors.Errorf("operator [$exists] of field [%s] must be followed by a boolean", name)
+			}
+		case "$not":
+			sub, err := parseFieldSelector(name, operand)
+			if err != nil {
+				return nil, err
+			}
+			selectors = append(selectors, &notSelector{sub})
+			continue
+		default:
+			if !strings.HasPrefix(operator, "$") {
+				return nil, errors.Errorf("selector of field [%s] mixes operators and fields", name)
+			}
+			return nil, errors.Errorf("operator [%s] is not supported by the leveldb state database", operator)
+		}
+		selectors = append(selectors, c)
+	}
+	if len(selectors) == 1 {
+		return selectors[0], nil
+	}
+	return selectors, nil
+}
+
+// conjunctiveConditions returns the conditions that every document matching the selector satisfies,
+// that is, the conditions that are not nested in an $or or a $not, grouped by field name
+func conjunctiveConditions(s selector) map[string][]*condition {
+	conditions := map[string][]*condition{}
+	var collect func(s selector)
+	collect = func(s selector) {
+		switch v := s.(type) {
+		case *condition:
+			conditions[v.field.name] = append(conditions[v.field.name], v)
+		case andSelector:
+			for _, sub := range v {
+				collect(sub)
+			}
+		}
+	}
+	collect(s)
+	return conditions
+}
+
+func sortedNames(m map[string]interface{}) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func unmarshalJSON(jsonBytes []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("unexpected data after the JSON value")
+	}
+	return nil
+}
+
+// the type tags of the index encoding of the JSON values, in the collation order of CouchDB
+const (
+	indexValueNull byte = iota + 1
+	indexValueFalse
+	indexValueTrue
+	indexValueNumber
+	indexValueString
+	indexValueArray
+	indexValueObject
+)
+
+// encodeIndexValue encodes a JSON value such that the byte order of the encoded values is the order
+// of the values. The strings are ordered by their bytes, rather than by the unicode collation of
+// CouchDB, and the arrays and the objects are ordered by their JSON encoding. The encoded values are
+// self-delimiting, so that the encodings of several values can be concatenated in an index key
+func encodeIndexValue(value interface{}) []byte {
+	switch v := value.(type) {
+	case nil:
+		return []byte{indexValueNull}
+	case bool:
+		if v {
+			return []byte{indexValueTrue}
+		}
+		return []byte{indexValueFalse}
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			// the number overflows a float64 and is ordered as a string
+			return escapeIndexValue(indexValueString, []byte(v))
+		}
+		return encodeNumber(f)
+	case float64:
+		return encodeNumber(v)
+	case string:
+		return escapeIndexValue(indexValueString, []byte(v))
+	case []interface{}:
+		jsonBytes, _ := json.Marshal(v)
+		return escapeIndexValue(indexValueArray, jsonBytes)
+	default:
+		jsonBytes, err := json.Marshal(v)
+		if err != nil {
+			jsonBytes = []byte(fmt.Sprintf("%v", v))
+		}
+		return escapeIndexValue(indexValueObject, jsonBytes)
+	}
+}
+
+func encodeNumber(f float64) []byte {
+	if f == 0 {
+		// the negative zero is encoded as the positive zero
+		f = 0
+	}
+	bits := math.Float64bits(f)
+	if f < 0 {
+		bits = ^bits
+	} else {
+		bits |= 1 << 63
+	}
+	encoded := make([]byte, 9)
+	encoded[0] = indexValueNumber
+	binary.BigEndian.PutUint64(encoded[1:], bits)
+	return encoded
+}
+
+// escapeIndexValue escapes the zero bytes of the value and terminates it with the bytes 0x00 0x01
+func escapeIndexValue(tag byte, value []byte) []byte {
+	encoded := make([]byte, 0, len(value)+3)
+	encoded = append(encoded, tag)
+	for _, b := range value {
+		if b == 0x00 {
+			encoded = append(encoded, 0x00, 0xff)
+		} else {
+			encoded = append(encoded, b)
+		}
+	}
+	return append(encoded, 0x00, 0x01)
+}
+
+// encodedIndexValueLen returns the length of the encoded value at the start of the bytes
+func encodedIndexValueLen(encoded []byte) (int, error) {
+	if len(encoded) == 0 {
+		return 0, errors.New("encoded index value is empty")
+	}
+	switch encoded[0] {
+	case indexValueNull, indexValueFalse, indexValueTrue:
+		return 1, nil
+	case indexValueNumber:
+		if len(encoded) >= 9 {
+			return 9, nil
+		}
+	case indexValueString, indexValueArray, indexValueObject:
+		for i := 1; i+1 < len(encoded); i++ {
+			if encoded[i] != 0x00 {
+				continue
+			}
+			if encoded[i+1] == 0x01 {
+				return i + 2, nil
+			}
+			i++
+		}
+	}
+	return 0, errors.Errorf("invalid encoded index value [%#v]", encoded)
+}
@@ -0,0 +1,463 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// queryPlan describes how the documents that match a rich query are retrieved. The documents are
+// retrieved either from a range of an index or from a scan of the namespace, and each of them has
+// a position that orders the results. The bookmark of a page of results is the position of the
+// first document of the next page
+type queryPlan struct {
+	// index is the index that is scanned, or nil if the namespace is scanned
+	index *indexDefinition
+	// start and end are the range of the positions that are scanned in the index. The start is
+	// inclusive, the end is exclusive and a nil end denotes the end of the index
+	start, end []byte
+	// reverse denotes that the index is scanned in the descending order of the positions
+	reverse bool
+	// sortInMemory denotes that the order of the scan does not satisfy the sort of the query and
+	// the matching documents are sorted in memory, by positions derived from the sort fields
+	sortInMemory bool
+}
+
+// id identifies the kind of positions of the plan, which a bookmark is valid for
+func (p *queryPlan) id() string {
+	switch {
+	case p.sortInMemory:
+		return "sort"
+	case p.index == nil:
+		return "scan"
+	case p.reverse:
+		return "desc:" + p.index.Name
+	default:
+		return "asc:" + p.index.Name
+	}
+}
+
+// planQuery selects the index that is used for the query, if any. An index is usable only if every
+// matching document has all the indexed fields, as the other documents are not indexed. That is the
+// case when every indexed field is a sort field or has a condition that implies its existence, other
+// than the conditions nested in an $or or a $not. Among the usable indexes, the ones that satisfy the
+// sort are preferred, and then the ones with the narrowest range
+func planQuery(q *richQuery, indexes []*indexDefinition) *queryPlan {
+	conditions := conjunctiveConditions(q.selector)
+	var plan *queryPlan
+	planScore, planSorts := 0, false
+	for _, index := range indexes {
+		if q.useIndex != nil && !q.useIndex.matches(index) {
+			continue
+		}
+		if !indexCovers(index, conditions, q.sort) {
+			continue
+		}
+		start, end, score := indexRange(index, conditions)
+		sorts, reverse := indexSorts(index, conditions, q.sort)
+		if !sorts && score == 0 {
+			continue
+		}
+		if plan == nil || (sorts && !planSorts) || (sorts == planSorts && score > planScore) {
+			plan = &queryPlan{
+				index:        index,
+				start:        start,
+				end:          end,
+				reverse:      reverse,
+				sortInMemory: !sorts && len(q.sort) > 0,
+			}
+			planScore, planSorts = score, sorts
+		}
+	}
+	if plan == nil {
+		if q.useIndex != nil {
+			logger.Warnf("No usable index matches the use_index option of the query, the namespace is scanned instead")
+		}
+		return &queryPlan{sortInMemory: len(q.sort) > 0}
+	}
+	return plan
+}
+
+func indexCovers(index *indexDefinition, conditions map[string][]*condition, sortFields []*sortField) bool {
+	for _, name := range index.Fields {
+		covered := false
+		for _, c := range conditions[name] {
+			covered = covered || c.impliesExistence()
+		}
+		for _, s := range sortFields {
+			covered = covered || s.field.name == name
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// indexRange returns the range of the positions in the index that includes all the matching documents.
+// The leading indexed fields with an $eq condition form a prefix of the range, and the bounds of a range
+// condition on the following field narrow it further. The score of the range is higher for narrower ranges
+func indexRange(index *indexDefinition, conditions map[string][]*condition) (start, end []byte, score int) {
+	var prefix []byte
+	for _, name := range index.Fields {
+		var eq, lower, upper *condition
+		for _, c := range conditions[name] {
+			switch {
+			case c.operator == "$eq" && eq == nil:
+				eq = c
+			case (c.operator == "$gt" || c.operator == "$gte") && lower == nil:
+				lower = c
+			case (c.operator == "$lt" || c.operator == "$lte") && upper == nil:
+				upper = c
+			}
+		}
+		if eq != nil {
+			prefix = append(prefix, eq.operands[0]...)
+			score += 2
+			continue
+		}
+		if lower == nil && upper == nil {
+			break
+		}
+		// the range conditions only match the values of the type of their operand
+		if lower != nil {
+			start = append(append([]byte{}, prefix...), lower.operands[0]...)
+			if lower.operator == "$gt" {
+				start = util.BytesPrefix(start).Limit
+			}
+			score++
+		} else {
+			start = append(append([]byte{}, prefix...), upper.operands[0][0])
+		}
+		if upper != nil {
+			end = append(append([]byte{}, prefix...), upper.operands[0]...)
+			if upper.operator == "$lte" {
+				end = util.BytesPrefix(end).Limit
+			}
+			score++
+		} else {
+			end = append(append([]byte{}, prefix...), lower.operands[0][0]+1)
+		}
+		return start, end, score
+	}
+	if len(prefix) == 0 {
+		return nil, nil, score
+	}
+	return prefix, util.BytesPrefix(prefix).Limit, score
+}
+
+// indexSorts returns whether the order of the index satisfies the sort of the query, and whether the
+// index is scanned in reverse for that. The indexed fields with an $eq condition have a single value
+// in the range of the index and do not affect the order
+func indexSorts(index *indexDefinition, conditions map[string][]*condition, sortFields []*sortField) (sorts bool, reverse bool) {
+	if len(sortFields) == 0 {
+		return false, false
+	}
+	i := 0
+	for _, name := range index.Fields {
+		if i == len(sortFields) {
+			break
+		}
+		if sortFields[i].field.name == name {
+			if sortFields[i].desc != sortFields[0].desc {
+				return false, false
+			}
+			i++
+			continue
+		}
+		hasEq := false
+		for _, c := range conditions[name] {
+			hasEq = hasEq || c.operator == "$eq"
+		}
+		if !hasEq {
+			break
+		}
+	}
+	return i == len(sortFields), sortFields[0].desc
+}
+
+type queryMatch struct {
+	position []byte
+	kv       *statedb.VersionedKV
+}
+
+// queryScanner implements interface statedb.QueryResultsIterator for the rich queries
+type queryScanner struct {
+	vdb       *versionedDB
+	namespace string
+	query     *richQuery
+	plan      *queryPlan
+	bookmark  []byte
+	dbItr     iterator.Iterator
+	started   bool
+	sorted    []*queryMatch
+	skipped   int32
+	returned  int32
+}
+
+func newQueryScanner(vdb *versionedDB, namespace string, q *richQuery) (*queryScanner, error) {
+	vdb.indexesLock.RLock()
+	plan := planQuery(q, vdb.indexes[namespace])
+	vdb.indexesLock.RUnlock()
+	if plan.index != nil {
+		logger.Debugf("Channel [%s]: Query on namespace [%s] uses index [%s]", vdb.dbName, namespace, plan.index.Name)
+	}
+
+	scanner := &queryScanner{vdb: vdb, namespace: namespace, query: q, plan: plan}
+	if q.bookmark != "" {
+		var err error
+		if scanner.bookmark, err = decodeBookmark(q.bookmark, plan.id()); err != nil {
+			return nil, err
+		}
+	}
+	scanner.dbItr = scanner.newDBIterator()
+	if plan.sortInMemory {
+		if err := scanner.sortMatches(); err != nil {
+			scanner.Close()
+			return nil, err
+		}
+	}
+	return scanner, nil
+}
+
+func (scanner *queryScanner) newDBIterator() iterator.Iterator {
+	// a bookmark applies to the positions of the scan, unless the matches are sorted in memory
+	bookmark := scanner.bookmark
+	if scanner.plan.sortInMemory {
+		bookmark = nil
+	}
+	if scanner.plan.index == nil {
+		compositeStartKey := constructCompositeKey(scanner.namespace, string(bookmark))
+		compositeEndKey := constructCompositeKey(scanner.namespace, "")
+		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
+		return scanner.vdb.db.GetIterator(compositeStartKey, compositeEndKey)
+	}
+
+	entriesPrefix := constructIndexEntriesPrefix(scanner.namespace, scanner.plan.index.Name)
+	start := append(append([]byte{}, entriesPrefix...), scanner.plan.start...)
+	end := util.BytesPrefix(entriesPrefix).Limit
+	if scanner.plan.end != nil {
+		end = append(append([]byte{}, entriesPrefix...), scanner.plan.end...)
+	}
+	if bookmark != nil {
+		bookmarkKey := append(append([]byte{}, entriesPrefix...), bookmark...)
+		if !scanner.plan.reverse && bytes.Compare(bookmarkKey, start) > 0 {
+			start = bookmarkKey
+		}
+		if scanner.plan.reverse && bytes.Compare(append(bookmarkKey, 0x00), end) < 0 {
+			end = append(bookmarkKey, 0x00)
+		}
+	}
+	return scanner.vdb.db.GetIterator(start, end)
+}
+
+// sortMatches retrieves all the matching documents and sorts them by their positions
+func (scanner *queryScanner) sortMatches() error {
+	for {
+		match, err := scanner.nextFromDB()
+		if err != nil {
+			return err
+		}
+		if match == nil {
+			break
+		}
+		if !scanner.precedesBookmark(match.position) {
+			scanner.sorted = append(scanner.sorted, match)
+		}
+	}
+	sort.Slice(scanner.sorted, func(i, j int) bool {
+		return bytes.Compare(scanner.sorted[i].position, scanner.sorted[j].position) < 0
+	})
+	return nil
+}
+
+// nextFromDB returns the next matching document of the scan, or nil if there is none
+func (scanner *queryScanner) nextFromDB() (*queryMatch, error) {
+	for {
+		var ok bool
+		switch {
+		case !scanner.plan.reverse:
+			ok = scanner.dbItr.Next()
+		case !scanner.started:
+			ok = scanner.dbItr.Last()
+		default:
+			ok = scanner.dbItr.Prev()
+		}
+		scanner.started = true
+		if !ok {
+			return nil, errors.Wrap(scanner.dbItr.Error(), "error while executing query")
+		}
+		dbVal := scanner.dbItr.Value()
+		if err := scanner.dbItr.Error(); err != nil {
+			return nil, errors.Wrap(err, "error while executing query")
+		}
+		match, err := scanner.match(scanner.dbItr.Key(), dbVal)
+		if err != nil {
+			return nil, err
+		}
+		if match == nil || (!scanner.plan.sortInMemory && scanner.precedesBookmark(match.position)) {
+			continue
+		}
+		return match, nil
+	}
+}
+
+// match returns the match for the given entry of the scan, or nil if the document does not match the query
+func (scanner *queryScanner) match(dbKey, dbVal []byte) (*queryMatch, error) {
+	var key string
+	var position []byte
+	var vv *statedb.VersionedValue
+	var err error
+	if scanner.plan.index == nil {
+		_, key = splitCompositeKey(dbKey)
+		position = []byte(key)
+		dbValCopy := make([]byte, len(dbVal))
+		copy(dbValCopy, dbVal)
+		if vv, err = decodeValue(dbValCopy); err != nil {
+			return nil, err
+		}
+	} else {
+		entriesPrefix := constructIndexEntriesPrefix(scanner.namespace, scanner.plan.index.Name)
+		position = append([]byte{}, dbKey[len(entriesPrefix):]...)
+		if key, err = scanner.plan.index.keyFromPosition(position); err != nil {
+			return nil, err
+		}
+		if vv, err = scanner.vdb.GetState(scanner.namespace, key); err != nil || vv == nil {
+			return nil, err
+		}
+	}
+
+	doc, ok := decodeJSONDocument(vv.Value)
+	if !ok || !scanner.query.selector.matches(doc, key) {
+		return nil, nil
+	}
+	// the documents without all the sort fields are not included in the results of a sorted query
+	for _, s := range scanner.query.sort {
+		if _, ok := s.field.lookup(doc, key); !ok {
+			return nil, nil
+		}
+	}
+	if scanner.plan.sortInMemory {
+		position = sortPosition(scanner.query.sort, doc, key)
+	}
+	value := vv.Value
+	if len(scanner.query.fields) > 0 {
+		if value, err = json.Marshal(project(doc, scanner.query.fields)); err != nil {
+			return nil, errors.Wrap(err, "error marshaling query result")
+		}
+	}
+	return &queryMatch{
+		position: position,
+		kv: &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
+			VersionedValue: statedb.VersionedValue{Value: value, Metadata: vv.Metadata, Version: vv.Version},
+		},
+	}, nil
+}
+
+// sortPosition returns the position of a document in the order of the sort fields, followed by the
+// key. The encoded values of the descending fields are complemented to reverse their order
+func sortPosition(sortFields []*sortField, doc map[string]interface{}, key string) []byte {
+	var position []byte
+	for _, s := range sortFields {
+		value, _ := s.field.lookup(doc, key)
+		encodedValue := encodeIndexValue(value)
+		if s.desc {
+			for i := range encodedValue {
+				encodedValue[i] = ^encodedValue[i]
+			}
+		}
+		position = append(position, encodedValue...)
+	}
+	return append(position, key...)
+}
+
+func (scanner *queryScanner) precedesBookmark(position []byte) bool {
+	if scanner.bookmark == nil {
+		return false
+	}
+	if scanner.plan.reverse && !scanner.plan.sortInMemory {
+		return bytes.Compare(position, scanner.bookmark) > 0
+	}
+	return bytes.Compare(position, scanner.bookmark) < 0
+}
+
+func (scanner *queryScanner) nextMatch() (*queryMatch, error) {
+	if !scanner.plan.sortInMemory {
+		return scanner.nextFromDB()
+	}
+	if len(scanner.sorted) == 0 {
+		return nil, nil
+	}
+	match := scanner.sorted[0]
+	scanner.sorted = scanner.sorted[1:]
+	return match, nil
+}
+
+// Next implements method in interface statedb.QueryResultsIterator
+func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
+	if scanner.query.limit > 0 && scanner.returned >= scanner.query.limit {
+		return nil, nil
+	}
+	for ; scanner.skipped < scanner.query.skip; scanner.skipped++ {
+		if match, err := scanner.nextMatch(); err != nil || match == nil {
+			return nil, err
+		}
+	}
+	match, err := scanner.nextMatch()
+	if err != nil || match == nil {
+		return nil, err
+	}
+	scanner.returned++
+	return match.kv, nil
+}
+
+// Close implements method in interface statedb.QueryResultsIterator
+func (scanner *queryScanner) Close() {
+	scanner.dbItr.Release()
+}
+
+// GetBookmarkAndClose implements method in interface statedb.QueryResultsIterator. The bookmark
+// is empty if there are no more results
+func (scanner *queryScanner) GetBookmarkAndClose() string {
+	defer scanner.Close()
+	match, err := scanner.nextMatch()
+	if err != nil || match == nil {
+		return ""
+	}
+	return encodeBookmark(scanner.plan.id(), match.position)
+}
+
+type bookmark struct {
+	Plan     string `json:"plan"`
+	Position []byte `json:"position"`
+}
+
+func encodeBookmark(planID string, position []byte) string {
+	bookmarkBytes, _ := json.Marshal(&bookmark{planID, position})
+	return base64.RawURLEncoding.EncodeToString(bookmarkBytes)
+}
+
+func decodeBookmark(encodedBookmark, planID string) ([]byte, error) {
+	b := &bookmark{}
+	bookmarkBytes, err := base64.RawURLEncoding.DecodeString(encodedBookmark)
+	if err == nil {
+		err = json.Unmarshal(bookmarkBytes, b)
+	}
+	if err != nil || b.Plan != planID || b.Position == nil {
+		return nil, errors.Errorf("bookmark [%s] is not valid for the query", encodedBookmark)
+	}
+	return b.Position, nil
+}
@@ -0,0 +1,356 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateleveldb
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeIndexValue(t *testing.T) {
+	orderedValues := []string{
+		`null`, `false`, `true`,
+		`-1e300`, `-2.5`, `-1`, `0`, `1`, `1.5`, `10`, `1000007`, `1e300`,
+		`""`, `"A"`, `"a"`, `"a\u0000"`, `"a\u0000b"`, `"aa"`, `"b"`,
+		`[1]`, `[]`, `{"a":1}`, `{}`,
+	}
+	var previous []byte
+	for _, jsonValue := range orderedValues {
+		var value interface{}
+		assert.NoError(t, unmarshalJSON([]byte(jsonValue), &value))
+		encoded := encodeIndexValue(value)
+		assert.True(t, bytes.Compare(previous, encoded) < 0, "encoding of [%s] should sort after the previous value", jsonValue)
+		n, err := encodedIndexValueLen(append(encoded, []byte("key")...))
+		assert.NoError(t, err)
+		assert.Equal(t, len(encoded), n)
+		previous = encoded
+	}
+	assert.Equal(t, encodeIndexValue(json.Number("0")), encodeNumber(-0.0*1))
+	_, err := encodedIndexValueLen([]byte{indexValueString, 'a'})
+	assert.EqualError(t, err, `invalid encoded index value [[]byte{0x5, 0x61}]`)
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for query, expectedErr := range map[string]string{
+		`{"sort":["size"]}`:                                    `query [{"sort":["size"]}] does not include a selector`,
+		`{"selector":[]}`:                                      "selector [[]] must be a JSON object",
+		`{"selector":{"$nor":[{"a":1}]}}`:                      "operator [$nor] is not supported by the leveldb state database",
+		`{"selector":{"a":{"$regex":"^a"}}}`:                   "operator [$regex] is not supported by the leveldb state database",
+		`{"selector":{"a":{"$gt":1,"b":1}}}`:                   "selector of field [a] mixes operators and fields",
+		`{"selector":{"$or":[]}}`:                              "operator [$or] must be followed by a non-empty array of selectors",
+		`{"selector":{"a":{"$in":1}}}`:                         "operator [$in] of field [a] must be followed by an array",
+		`{"selector":{"a":{"$exists":1}}}`:                     "operator [$exists] of field [a] must be followed by a boolean",
+		`{"selector":{},"sort":[{"a":"up"}]}`:                  "sort direction [up] of field [a] must be either asc or desc",
+		`{"selector":{},"limit":-1}`:                           "limit [-1] must be a non-negative integer",
+		`{"selector":{},"skip":"1"}`:                           "skip must be a number",
+		`{"selector":{},"fields":"a"}`:                         "fields definition must be an array",
+		`{"selector":{},"use_index":["a","b","c"]}`:            "use_index must be a design document name or an array of a design document name and an index name",
+		`{"selector":{},"execution_stats":true}`:               "query option [execution_stats] is not supported by the leveldb state database",
+		`{"selector":{},"bookmark":1}`:                         "bookmark must be a string",
+		`{"selector":{"a":{"$not":{"$eq":1,"$size":1}}}}`:      "operator [$size] is not supported by the leveldb state database",
+		`{"selector":{"a":{"$gt":1}}, "sort":[{"a":1,"b":2}]}`: `sort field [map[a:1 b:2]] must have a single field name`,
+	} {
+		_, err := parseQuery(query)
+		assert.EqualError(t, err, expectedErr, "query [%s]", query)
+	}
+}
+
+func TestSelector(t *testing.T) {
+	doc, ok := decodeJSONDocument([]byte(`{"owner":"tom","size":5,"color":"blue","tags":["a"],"details":{"price":10,"sold":false}}`))
+	assert.True(t, ok)
+	for jsonSelector, expectedMatch := range map[string]bool{
+		`{}`:                                             true,
+		`{"owner":"tom"}`:                                true,
+		`{"owner":"jerry"}`:                              false,
+		`{"_id":"key1"}`:                                 true,
+		`{"size":{"$gt":4,"$lte":5}}`:                    true,
+		`{"size":{"$gt":5}}`:                             false,
+		`{"size":{"$lt":"a"}}`:                           false,
+		`{"size":{"$ne":4}}`:                             true,
+		`{"size":{"$in":[1,5]}}`:                         true,
+		`{"size":{"$in":["5"]}}`:                         false,
+		`{"tags":["a"]}`:                                 true,
+		`{"details":{"price":10}}`:                       true,
+		`{"details.price":{"$gte":10}}`:                  true,
+		`{"details.sold":false}`:                         true,
+		`{"details.buyer":{"$exists":false}}`:            true,
+		`{"details.buyer":{"$exists":true}}`:             false,
+		`{"missing":{"$ne":1}}`:                          false,
+		`{"$or":[{"owner":"jerry"},{"size":5}]}`:         true,
+		`{"$and":[{"owner":"tom"},{"size":6}]}`:          false,
+		`{"$not":{"owner":"tom"}}`:                       false,
+		`{"color":{"$not":{"$in":["red","green"]}}}`:     true,
+		`{"color":"blue","$or":[{"size":1},{"size":5}]}`: true,
+		`{"color":"blue","$or":[{"size":1},{"size":2}]}`: false,
+	} {
+		q, err := parseQuery(fmt.Sprintf(`{"selector":%s}`, jsonSelector))
+		assert.NoError(t, err)
+		assert.Equal(t, expectedMatch, q.selector.matches(doc, "key1"), "selector [%s]", jsonSelector)
+	}
+}
+
+func TestPlanQuery(t *testing.T) {
+	indexes := []*indexDefinition{
+		{Name: "color", DDoc: "colorDoc", Fields: []string{"color"}},
+		{Name: "colorSize", DDoc: "colorSizeDoc", Fields: []string{"color", "size"}},
+		{Name: "owner", Fields: []string{"owner"}},
+	}
+	for _, index := range indexes {
+		index.init()
+	}
+	for query, expectedPlanID := range map[string]string{
+		`{"selector":{"owner":"tom"}}`:                                                    "asc:owner",
+		`{"selector":{"color":"blue"}}`:                                                   "asc:color",
+		`{"selector":{"color":"blue","size":{"$gt":1}}}`:                                  "asc:colorSize",
+		`{"selector":{"color":"blue"},"sort":[{"size":"desc"}]}`:                          "desc:colorSize",
+		`{"selector":{"color":{"$gt":"a"}},"sort":["color","size"]}`:                      "asc:colorSize",
+		`{"selector":{"color":"blue"},"sort":["owner"]}`:                                  "asc:owner",
+		`{"selector":{"color":{"$gt":"a"}},"sort":[{"color":"asc"},{"size":"desc"}]}`:     "sort",
+		`{"selector":{"size":{"$gt":1}}}`:                                                 "scan",
+		`{"selector":{"$or":[{"owner":"tom"},{"owner":"jerry"}]}}`:                        "scan",
+		`{"selector":{"owner":{"$exists":false}}}`:                                        "scan",
+		`{"selector":{"color":"blue"},"use_index":"_design/colorSizeDoc"}`:                "scan",
+		`{"selector":{"color":"blue","size":1},"use_index":["colorSizeDoc","colorSize"]}`: "asc:colorSize",
+	} {
+		q, err := parseQuery(query)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedPlanID, planQuery(q, indexes).id(), "query [%s]", query)
+	}
+}
+
+func TestQueryWithIndexes(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testquerywithindexes")
+	assert.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	for i, owner := range []string{"tom", "jerry", "fred", "tom", "jerry", "fred", "tom", "jerry", "fred", "tom"} {
+		value := fmt.Sprintf(`{"owner":"%s","size":%d,"color":"%s"}`, owner, 10-i, []string{"blue", "red"}[i%2])
+		batch.Put("ns1", fmt.Sprintf("key%d", i), []byte(value), version.NewHeight(1, uint64(i)))
+	}
+	batch.Put("ns1", "key10", []byte("not a JSON value"), version.NewHeight(1, 10))
+	batch.Put("ns1", "key11", []byte(`{"owner":"tom"}`), version.NewHeight(1, 11))
+	batch.Put("ns2", "key0", []byte(`{"owner":"tom","size":1}`), version.NewHeight(1, 12))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 12)))
+
+	queries := map[string][]string{
+		`{"selector":{"owner":"tom"}}`:                                          {"key0", "key11", "key3", "key6", "key9"},
+		`{"selector":{"owner":"tom"},"sort":["size"]}`:                          {"key9", "key6", "key3", "key0"},
+		`{"selector":{"owner":"tom"},"sort":[{"size":"desc"}]}`:                 {"key0", "key3", "key6", "key9"},
+		`{"selector":{"size":{"$gte":3,"$lt":6}},"sort":["size"]}`:              {"key7", "key6", "key5"},
+		`{"selector":{"color":"red"},"sort":[{"owner":"asc"},{"size":"desc"}]}`: {"key5", "key1", "key7", "key3", "key9"},
+		`{"selector":{"owner":"tom"},"sort":["size"],"skip":1,"limit":2}`:       {"key6", "key3"},
+	}
+	checkQueries := func() {
+		for query, expectedKeys := range queries {
+			checkQueryResults(t, db, "ns1", query, expectedKeys)
+			// the results are the same when retrieved in pages, with the bookmark of each page
+			bookmark := ""
+			var pagedKeys []string
+			for {
+				itr, err := db.ExecuteQueryWithMetadata("ns1", query, map[string]interface{}{"limit": int32(2), "bookmark": bookmark})
+				assert.NoError(t, err)
+				pagedKeys = append(pagedKeys, queryResultKeys(t, itr)...)
+				if bookmark = itr.GetBookmarkAndClose(); bookmark == "" {
+					break
+				}
+			}
+			if len(expectedKeys) <= 2 {
+				continue
+			}
+			assert.Equal(t, expectedKeys, pagedKeys, "paged query [%s]", query)
+		}
+	}
+	checkQueries()
+
+	// the queries have the same results with the indexes
+	indexCapable := db.(statedb.IndexCapable)
+	assert.Equal(t, "leveldb", indexCapable.GetDBType())
+	assert.NoError(t, indexCapable.ProcessIndexesForChaincodeDeploy("ns1", indexFileEntries(
+		`{"index":{"fields":["owner","size"]},"ddoc":"indexOwnerDoc","name":"indexOwner","type":"json"}`,
+		`{"index":{"fields":[{"size":"desc"}]},"name":"indexSize","type":"json"}`,
+	)))
+	checkIndexEntries(t, db, "ns1", "indexOwner", []string{"key8", "key5", "key2", "key7", "key4", "key1", "key9", "key6", "key3", "key0"})
+	checkIndexEntries(t, db, "ns1", "indexSize", []string{"key9", "key8", "key7", "key6", "key5", "key4", "key3", "key2", "key1", "key0"})
+	checkQueries()
+
+	// the index entries are maintained along with the updates of the state
+	batch = statedb.NewUpdateBatch()
+	batch.Put("ns1", "key0", []byte(`{"owner":"fred","size":0}`), version.NewHeight(2, 1))
+	batch.Delete("ns1", "key1", version.NewHeight(2, 2))
+	batch.Put("ns1", "key11", []byte(`{"owner":"tom","size":1.5}`), version.NewHeight(2, 3))
+	batch.Put("ns1", "key12", []byte(`{"owner":"tom","size":2.5,"color":"blue"}`), version.NewHeight(2, 4))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 4)))
+	checkIndexEntries(t, db, "ns1", "indexOwner", []string{"key0", "key8", "key5", "key2", "key7", "key4", "key9", "key11", "key12", "key6", "key3"})
+	checkQueryResults(t, db, "ns1", `{"selector":{"owner":"tom"},"sort":["size"]}`, []string{"key9", "key11", "key12", "key6", "key3"})
+	checkQueryResults(t, db, "ns1", `{"selector":{"owner":{"$gt":"a"},"size":{"$gt":2}},"sort":["owner","size"],"fields":["size"]}`,
+		[]string{"key5", "key2", "key7", "key4", "key12", "key6", "key3"})
+
+	// the fields of a query are projected in the results
+	itr, err := db.ExecuteQuery("ns1", `{"selector":{"owner":"tom","size":{"$gt":2}},"fields":["size","color","missing"],"use_index":"indexOwnerDoc"}`)
+	assert.NoError(t, err)
+	result, err := itr.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, &statedb.VersionedKV{
+		CompositeKey:   statedb.CompositeKey{Namespace: "ns1", Key: "key12"},
+		VersionedValue: statedb.VersionedValue{Value: []byte(`{"color":"blue","size":2.5}`), Version: version.NewHeight(2, 4)},
+	}, result)
+	itr.Close()
+
+	// an index with changed fields is rebuilt, the indexes are loaded when the db is reopened
+	assert.NoError(t, indexCapable.ProcessIndexesForChaincodeDeploy("ns1", indexFileEntries(
+		`{"index":{"fields":["color"]},"name":"indexOwner","type":"json"}`,
+	)))
+	env.DBProvider.Close()
+	env.DBProvider = NewVersionedDBProvider()
+	db, err = env.DBProvider.GetDBHandle("testquerywithindexes")
+	assert.NoError(t, err)
+	assert.Len(t, db.(*versionedDB).indexes["ns1"], 2)
+	checkIndexEntries(t, db, "ns1", "indexOwner", []string{"key12", "key2", "key4", "key6", "key8", "key3", "key5", "key7", "key9"})
+	checkQueryResults(t, db, "ns1", `{"selector":{"color":"red"}}`, []string{"key3", "key5", "key7", "key9"})
+
+	// the full scan of the state does not include the index entries
+	fullScanItr, err := db.(statedb.FullScanIteratorProvider).GetFullScanIterator()
+	assert.NoError(t, err)
+	defer fullScanItr.Close()
+	count := 0
+	for {
+		compositeKey, _, err := fullScanItr.Next()
+		assert.NoError(t, err)
+		if compositeKey == nil {
+			break
+		}
+		assert.Contains(t, []string{"ns1", "ns2"}, compositeKey.Namespace)
+		count++
+	}
+	assert.Equal(t, 13, count)
+}
+
+func TestNoIndexesWhenEncrypted(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testnoindexeswhenencrypted")
+	assert.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key0", []byte(`{"owner":"tom","size":1}`), version.NewHeight(1, 0))
+	batch.Put("ns1", "key1", []byte(`{"owner":"jerry","size":2}`), version.NewHeight(1, 1))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)))
+	definition := `{"index":{"fields":["owner"]},"name":"indexOwner","type":"json"}`
+	assert.NoError(t, db.(statedb.IndexCapable).ProcessIndexesForChaincodeDeploy("ns1", indexFileEntries(definition)))
+	checkIndexEntries(t, db, "ns1", "indexOwner", []string{"key1", "key0"})
+
+	// the existing indexes are dropped once the state is encrypted, as the keys of their
+	// entries embed the values of the indexed fields, and no index is created
+	encryptedDB, err := newVersionedDB(db.(*versionedDB).db, "testnoindexeswhenencrypted", true)
+	assert.NoError(t, err)
+	assert.Empty(t, encryptedDB.indexes)
+	assert.NoError(t, encryptedDB.ProcessIndexesForChaincodeDeploy("ns1", indexFileEntries(definition)))
+	assert.Empty(t, encryptedDB.indexes)
+	itr := encryptedDB.db.GetIterator(indexKeyPrefix, indexKeysEnd)
+	assert.False(t, itr.Next())
+	itr.Release()
+
+	// the rich queries scan the namespace instead
+	checkQueryResults(t, encryptedDB, "ns1", `{"selector":{"owner":"tom"}}`, []string{"key0"})
+}
+
+func TestQueryErrors(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testqueryerrors")
+	assert.NoError(t, err)
+
+	_, err = db.ExecuteQueryWithMetadata("ns1", `{"selector":{}}`, map[string]interface{}{"limit": 1})
+	assert.EqualError(t, err, `Invalid entry, "limit" must be an int32`)
+	_, err = db.ExecuteQuery("ns1", `{"selector":{},"bookmark":"invalid"}`)
+	assert.EqualError(t, err, "bookmark [invalid] is not valid for the query")
+	_, err = db.ExecuteQuery("ns1", `{"selector":{},"sort":["size"],"bookmark":"`+encodeBookmark("scan", []byte("key1"))+`"}`)
+	assert.EqualError(t, err, "bookmark [eyJwbGFuIjoic2NhbiIsInBvc2l0aW9uIjoiYTJWNU1RPT0ifQ] is not valid for the query")
+
+	err = db.(statedb.IndexCapable).ProcessIndexesForChaincodeDeploy("ns1", indexFileEntries(
+		`{"index":{"fields":["owner"],"partial_filter_selector":{"size":1}},"name":"index1"}`,
+	))
+	assert.EqualError(t, err, "error creating index from file [META-INF/statedb/leveldb/indexes/index0.json] for namespace [ns1]: "+
+		"partial_filter_selector is not supported by the leveldb state database")
+}
+
+func TestParseIndexDefinitionErrors(t *testing.T) {
+	for definition, expectedErr := range map[string]string{
+		`{"index":{"fields":[]}}`:                      "index definition must include the fields of the index",
+		`{"index":{"fields":["a"]},"type":"text"}`:     "index type must be json",
+		`{"index":{"fields":[{"a":"asc","b":"asc"}]}}`: "index field [map[a:asc b:asc]] must have a single field name",
+		`{"index":{"fields":[1]}}`:                     "index field [1] must be a field name or an object",
+		`{"index":{"fields":["a"]},"name":"a\u0000"}`:  "index name [a\x00] is not valid",
+	} {
+		_, err := parseIndexDefinition("index.json", []byte(definition))
+		assert.EqualError(t, err, expectedErr)
+	}
+	index, err := parseIndexDefinition("META-INF/statedb/leveldb/indexes/indexOwner.json", []byte(`{"index":{"fields":["owner"]}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "indexOwner", index.Name)
+}
+
+func checkQueryResults(t *testing.T, db statedb.VersionedDB, ns, query string, expectedKeys []string) {
+	itr, err := db.ExecuteQuery(ns, query)
+	assert.NoError(t, err)
+	defer itr.Close()
+	assert.Equal(t, expectedKeys, queryResultKeys(t, itr), "query [%s]", query)
+}
+
+func queryResultKeys(t *testing.T, itr statedb.ResultsIterator) []string {
+	var keys []string
+	for {
+		result, err := itr.Next()
+		assert.NoError(t, err)
+		if result == nil {
+			return keys
+		}
+		keys = append(keys, result.(*statedb.VersionedKV).Key)
+	}
+}
+
+func checkIndexEntries(t *testing.T, db statedb.VersionedDB, ns, indexName string, expectedKeys []string) {
+	vdb := db.(*versionedDB)
+	var index *indexDefinition
+	for _, i := range vdb.indexes[ns] {
+		if i.Name == indexName {
+			index = i
+		}
+	}
+	assert.NotNil(t, index)
+	entriesPrefix := constructIndexEntriesPrefix(ns, indexName)
+	itr := vdb.db.GetIterator(entriesPrefix, append(append([]byte{}, entriesPrefix...), 0xff))
+	defer itr.Release()
+	var keys []string
+	for itr.Next() {
+		key, err := index.keyFromPosition(itr.Key()[len(entriesPrefix):])
+		assert.NoError(t, err)
+		keys = append(keys, key)
+	}
+	assert.Equal(t, expectedKeys, keys)
+}
+
+func indexFileEntries(definitions ...string) []*ccprovider.TarFileEntry {
+	var fileEntries []*ccprovider.TarFileEntry
+	for i, definition := range definitions {
+		fileEntries = append(fileEntries, &ccprovider.TarFileEntry{
+			FileHeader:  &tar.Header{Name: fmt.Sprintf("META-INF/statedb/leveldb/indexes/index%d.json", i)},
+			FileContent: []byte(definition),
+		})
+	}
+	return fileEntries
+}
@@ -7,9 +7,13 @@ package stateleveldb
 
 import (
 	"bytes"
+	"fmt"
+	"os"
+	"sync"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
@@ -19,6 +23,18 @@ import (
 
 var logger = flogging.MustGetLogger("stateleveldb")
 
+// StateDatabaseName is the name under which the LevelDB based state database is registered
+const StateDatabaseName = "goleveldb"
+
+func init() {
+	statedb.RegisterVersionedDBProviderFactory(StateDatabaseName, &statedb.VersionedDBProviderFactory{
+		NewProvider: func(metrics.Provider) (statedb.VersionedDBProvider, error) {
+			return NewVersionedDBProvider(), nil
+		},
+		DropAll: DropAllDBs,
+	})
+}
+
 var compositeKeySep = []byte{0x00}
 var lastKeyIndicator = byte(0x01)
 var savePointKey = []byte{0x00}
@@ -26,19 +42,45 @@ var savePointKey = []byte{0x00}
 // VersionedDBProvider implements interface VersionedDBProvider
 type VersionedDBProvider struct {
 	dbProvider *leveldbhelper.Provider
+	databases  map[string]*versionedDB
+	mux        sync.Mutex
+	encrypted  bool
 }
 
 // NewVersionedDBProvider instantiates VersionedDBProvider
 func NewVersionedDBProvider() *VersionedDBProvider {
 	dbPath := ledgerconfig.GetStateLevelDBPath()
 	logger.Debugf("constructing VersionedDBProvider dbPath=%s", dbPath)
-	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
-	return &VersionedDBProvider{dbProvider}
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		panic(fmt.Sprintf("Error initializing the encryption of the state database: %s", err))
+	}
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath, Encrypter: encrypter})
+	return &VersionedDBProvider{dbProvider: dbProvider, databases: make(map[string]*versionedDB), encrypted: encrypter != nil}
+}
+
+// DropAllDBs removes the state databases of all the ledgers so that these get rebuilt
+// from the blocks the next time the peer starts
+func DropAllDBs() error {
+	dbPath := ledgerconfig.GetStateLevelDBPath()
+	logger.Infof("Dropping state database at [%s]", dbPath)
+	return errors.Wrapf(os.RemoveAll(dbPath), "error removing state database at [%s]", dbPath)
 }
 
 // GetDBHandle gets the handle to a named database
 func (provider *VersionedDBProvider) GetDBHandle(dbName string) (statedb.VersionedDB, error) {
-	return newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName), nil
+	provider.mux.Lock()
+	defer provider.mux.Unlock()
+	vdb := provider.databases[dbName]
+	if vdb == nil {
+		var err error
+		vdb, err = newVersionedDB(provider.dbProvider.GetDBHandle(dbName), dbName, provider.encrypted)
+		if err != nil {
+			return nil, err
+		}
+		provider.databases[dbName] = vdb
+	}
+	return vdb, nil
 }
 
 // Close closes the underlying db
@@ -50,11 +92,27 @@ func (provider *VersionedDBProvider) Close() {
 type versionedDB struct {
 	db     *leveldbhelper.DBHandle
 	dbName string
+	// indexes are the secondary indexes of the namespaces, which are used for the rich queries
+	indexes     map[string][]*indexDefinition
+	indexesLock sync.RWMutex
+	// encrypted is set when the values of the state are encrypted, in which case the
+	// namespaces have no secondary indexes
+	encrypted bool
 }
 
-// newVersionedDB constructs an instance of VersionedDB
-func newVersionedDB(db *leveldbhelper.DBHandle, dbName string) *versionedDB {
-	return &versionedDB{db, dbName}
+// newVersionedDB constructs an instance of VersionedDB. The indexes of an encrypted state
+// are dropped, as the keys of their entries embed the values of the indexed fields
+func newVersionedDB(db *leveldbhelper.DBHandle, dbName string, encrypted bool) (*versionedDB, error) {
+	if encrypted {
+		if err := dropIndexes(db, dbName); err != nil {
+			return nil, err
+		}
+	}
+	indexes, err := loadIndexDefinitions(db)
+	if err != nil {
+		return nil, err
+	}
+	return &versionedDB{db: db, dbName: dbName, indexes: indexes, encrypted: encrypted}, nil
 }
 
 // Open implements method in VersionedDB interface
@@ -156,24 +214,56 @@ func (vdb *versionedDB) GetStateRangeScanIteratorWithMetadata(namespace string,
 
 // ExecuteQuery implements method in VersionedDB interface
 func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
-	return nil, errors.New("ExecuteQuery not supported for leveldb")
+	return vdb.ExecuteQueryWithMetadata(namespace, query, nil)
 }
 
-// ExecuteQueryWithMetadata implements method in VersionedDB interface
+const optionBookmark = "bookmark"
+
+// ExecuteQueryWithMetadata implements method in VersionedDB interface. The query uses a subset of
+// the Mango query syntax of CouchDB, see type richQuery. The limit in the metadata applies along
+// with the limit in the query, and the bookmark in the metadata overrides the bookmark in the query
 func (vdb *versionedDB) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (statedb.QueryResultsIterator, error) {
-	return nil, errors.New("ExecuteQueryWithMetadata not supported for leveldb")
+	logger.Debugf("Entering ExecuteQueryWithMetadata  namespace: %s,  query: %s,  metadata: %v", namespace, query, metadata)
+	q, err := parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if metadata != nil {
+		if err := statedb.ValidateQueryMetadata(metadata); err != nil {
+			return nil, err
+		}
+		if limitOption, ok := metadata[optionLimit]; ok {
+			if requestedLimit := limitOption.(int32); requestedLimit > 0 && (q.limit == 0 || requestedLimit < q.limit) {
+				q.limit = requestedLimit
+			}
+		}
+		if bookmarkOption, ok := metadata[optionBookmark]; ok && bookmarkOption.(string) != "" {
+			q.bookmark = bookmarkOption.(string)
+		}
+	}
+	return newQueryScanner(vdb, namespace, q)
 }
 
 // ApplyUpdates implements method in VersionedDB interface
 func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
+	// the lock prevents the creation of an index while the updates are applied
+	vdb.indexesLock.RLock()
+	defer vdb.indexesLock.RUnlock()
 	dbBatch := leveldbhelper.NewUpdateBatch()
 	namespaces := batch.GetUpdatedNamespaces()
 	for _, ns := range namespaces {
 		updates := batch.GetUpdates(ns)
+		indexes := vdb.indexes[ns]
 		for k, vv := range updates {
 			compositeKey := constructCompositeKey(ns, k)
 			logger.Debugf("Channel [%s]: Applying key(string)=[%s] key(bytes)=[%#v]", vdb.dbName, string(compositeKey), compositeKey)
 
+			if len(indexes) > 0 {
+				if err := vdb.addIndexUpdates(dbBatch, ns, k, vv, indexes); err != nil {
+					return err
+				}
+			}
+
 			if vv.Value == nil {
 				dbBatch.Delete(compositeKey)
 			} else {
@@ -212,6 +302,14 @@ func (vdb *versionedDB) GetLatestSavePoint() (*version.Height, error) {
 	return version, nil
 }
 
+// GetFullScanIterator implements method in FullScanIteratorProvider interface
+func (vdb *versionedDB) GetFullScanIterator() (statedb.FullScanIterator, error) {
+	// the savepoint key sorts before all the composite keys and hence is skipped by starting
+	// the iteration just after it
+	dbItr := vdb.db.GetIterator(append(savePointKey, 0x00), nil)
+	return &fullScanIterator{vdb.db, dbItr}, nil
+}
+
 func constructCompositeKey(ns string, key string) []byte {
 	return append(append([]byte(ns), compositeKeySep...), []byte(key)...)
 }
@@ -244,6 +342,9 @@ func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
 
 	dbKey := scanner.dbItr.Key()
 	dbVal := scanner.dbItr.Value()
+	if err := scanner.dbItr.Error(); err != nil {
+		return nil, errors.Wrap(err, "error while executing query")
+	}
 	dbValCopy := make([]byte, len(dbVal))
 	copy(dbValCopy, dbVal)
 	_, key := splitCompositeKey(dbKey)
@@ -275,3 +376,36 @@ func (scanner *kvScanner) GetBookmarkAndClose() string {
 	scanner.Close()
 	return retval
 }
+
+type fullScanIterator struct {
+	db    *leveldbhelper.DBHandle
+	dbItr iterator.Iterator
+}
+
+func (itr *fullScanIterator) Next() (*statedb.CompositeKey, *statedb.VersionedValue, error) {
+	if !itr.dbItr.Next() {
+		return nil, nil, errors.Wrap(itr.dbItr.Error(), "error while iterating over the state")
+	}
+	if bytes.HasPrefix(itr.dbItr.Key(), indexKeyPrefix) {
+		// the indexes are not part of the state and the iteration resumes after them
+		itr.dbItr.Release()
+		itr.dbItr = itr.db.GetIterator(indexKeysEnd, nil)
+		return itr.Next()
+	}
+	dbVal := itr.dbItr.Value()
+	if err := itr.dbItr.Error(); err != nil {
+		return nil, nil, errors.Wrap(err, "error while iterating over the state")
+	}
+	dbValCopy := make([]byte, len(dbVal))
+	copy(dbValCopy, dbVal)
+	ns, key := splitCompositeKey(itr.dbItr.Key())
+	vv, err := decodeValue(dbValCopy)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &statedb.CompositeKey{Namespace: ns, Key: key}, vv, nil
+}
+
+func (itr *fullScanIterator) Close() {
+	itr.dbItr.Release()
+}
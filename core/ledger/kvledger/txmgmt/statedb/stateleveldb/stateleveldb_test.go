@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/commontests"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -69,27 +70,10 @@ func testCompositeKey(t *testing.T, dbName string, ns string, key string) {
 	assert.Equal(t, key, key1)
 }
 
-// TestQueryOnLevelDB tests queries on levelDB.
-func TestQueryOnLevelDB(t *testing.T) {
+func TestQuery(t *testing.T) {
 	env := NewTestVDBEnv(t)
 	defer env.Cleanup()
-	db, err := env.DBProvider.GetDBHandle("testquery")
-	assert.NoError(t, err)
-	db.Open()
-	defer db.Close()
-	batch := statedb.NewUpdateBatch()
-	jsonValue1 := `{"asset_name": "marble1","color": "blue","size": 1,"owner": "tom"}`
-	batch.Put("ns1", "key1", []byte(jsonValue1), version.NewHeight(1, 1))
-
-	savePoint := version.NewHeight(2, 22)
-	db.ApplyUpdates(batch, savePoint)
-
-	// query for owner=jerry, use namespace "ns1"
-	// As queries are not supported in levelDB, call to ExecuteQuery()
-	// should return a error message
-	itr, err := db.ExecuteQuery("ns1", `{"selector":{"owner":"jerry"}}`)
-	assert.Error(t, err, "ExecuteQuery not supported for leveldb")
-	assert.Nil(t, itr)
+	commontests.TestQuery(t, env.DBProvider)
 }
 
 func TestGetStateMultipleKeys(t *testing.T) {
@@ -136,3 +120,68 @@ func TestApplyUpdatesWithNilHeight(t *testing.T) {
 	defer env.Cleanup()
 	commontests.TestApplyUpdatesWithNilHeight(t, env.DBProvider)
 }
+
+func TestFullScanIterator(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testfullscaniterator")
+	assert.NoError(t, err)
+	otherDB, err := env.DBProvider.GetDBHandle("testfullscaniterator-other")
+	assert.NoError(t, err)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.PutValAndMetadata("ns1", "key2", []byte("value2"), []byte("metadata2"), version.NewHeight(1, 2))
+	batch.Put("ns2", "key1", []byte("value3"), version.NewHeight(2, 1))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 1)))
+	otherBatch := statedb.NewUpdateBatch()
+	otherBatch.Put("ns1", "key3", []byte("value4"), version.NewHeight(1, 1))
+	assert.NoError(t, otherDB.ApplyUpdates(otherBatch, version.NewHeight(1, 1)))
+
+	itr, err := db.(statedb.FullScanIteratorProvider).GetFullScanIterator()
+	assert.NoError(t, err)
+	defer itr.Close()
+	expected := []*statedb.VersionedKV{
+		{CompositeKey: statedb.CompositeKey{Namespace: "ns1", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}},
+		{CompositeKey: statedb.CompositeKey{Namespace: "ns1", Key: "key2"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value2"), Metadata: []byte("metadata2"), Version: version.NewHeight(1, 2)}},
+		{CompositeKey: statedb.CompositeKey{Namespace: "ns2", Key: "key1"},
+			VersionedValue: statedb.VersionedValue{Value: []byte("value3"), Version: version.NewHeight(2, 1)}},
+	}
+	for _, kv := range expected {
+		compositeKey, vv, err := itr.Next()
+		assert.NoError(t, err)
+		assert.Equal(t, &kv.CompositeKey, compositeKey)
+		assert.Equal(t, &kv.VersionedValue, vv)
+	}
+	compositeKey, vv, err := itr.Next()
+	assert.NoError(t, err)
+	assert.Nil(t, compositeKey)
+	assert.Nil(t, vv)
+}
+
+func TestConformance(t *testing.T) {
+	commontests.RunConformanceTests(t, func(t *testing.T) (statedb.VersionedDBProvider, func()) {
+		env := NewTestVDBEnv(t)
+		return env.DBProvider, env.Cleanup
+	})
+}
+
+func TestRegisteredFactory(t *testing.T) {
+	factory, err := statedb.GetVersionedDBProviderFactory(StateDatabaseName)
+	assert.NoError(t, err)
+	removeDBPath(t, "TestRegisteredFactory")
+	dbProvider, err := factory.NewProvider(nil)
+	assert.NoError(t, err)
+	db, err := dbProvider.GetDBHandle("testregisteredfactory")
+	assert.NoError(t, err)
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	assert.NoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)))
+	dbProvider.Close()
+
+	assert.NoError(t, factory.DropAll())
+	_, err = os.Stat(ledgerconfig.GetStateLevelDBPath())
+	assert.True(t, os.IsNotExist(err))
+}
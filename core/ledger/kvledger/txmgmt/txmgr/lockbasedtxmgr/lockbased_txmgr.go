@@ -41,6 +41,10 @@ type LockBasedTxMgr struct {
 	commitRWLock    sync.RWMutex
 	oldBlockCommit  sync.Mutex
 	current         *current
+	// validationDB overlays db with the updates of the block being committed in the background, see StartCommit
+	validationDB      *pendingUpdatesDB
+	pendingCommitLock sync.Mutex
+	pendingCommit     *pendingCommit
 }
 
 type current struct {
@@ -72,7 +76,8 @@ func NewLockBasedTxMgr(ledgerid string, db privacyenabledstate.DB, stateListener
 		return nil, err
 	}
 	txmgr.pvtdataPurgeMgr = &pvtdataPurgeMgr{pvtstatePurgeMgr, false}
-	txmgr.validator = valimpl.NewStatebasedValidator(txmgr, db)
+	txmgr.validationDB = &pendingUpdatesDB{DB: db}
+	txmgr.validator = valimpl.NewStatebasedValidator(txmgr, txmgr.validationDB)
 	return txmgr, nil
 }
 
@@ -120,6 +125,12 @@ func (txmgr *LockBasedTxMgr) ValidateAndPrepare(blockAndPvtdata *ledger.BlockAnd
 	defer txmgr.oldBlockCommit.Unlock()
 	logger.Debug("lock acquired on oldBlockCommit for validating read set version against the committed version")
 
+	// the block is validated against the state as of the preceding block, which may still be being committed
+	txmgr.validationDB.updates = nil
+	if pending := txmgr.getPendingCommit(); pending != nil && !pending.isDone() {
+		txmgr.validationDB.updates = pending.updates
+	}
+
 	block := blockAndPvtdata.Block
 	logger.Debugf("Validating new block with num trans = [%d]", len(block.Data.Data))
 	batch, txstatsInfo, err := txmgr.validator.ValidateAndPrepareBatch(blockAndPvtdata, doMVCCValidation)
@@ -173,6 +184,10 @@ func (txmgr *LockBasedTxMgr) RemoveStaleAndCommitPvtDataOfOldBlocks(blocksPvtDat
 	txmgr.oldBlockCommit.Lock()
 	defer txmgr.oldBlockCommit.Unlock()
 	logger.Debug("lock acquired on oldBlockCommit for committing pvtData of old blocks to state database")
+	// the stale pvtData is found against the state updated with the last block
+	if err := txmgr.WaitForCommit(); err != nil {
+		return err
+	}
 
 	// (1) as the blocksPvtData can contain multiple versions of pvtData for
 	// a given <ns, coll, key>, we need to find duplicate tuples with different
@@ -406,12 +421,12 @@ func (txmgr *LockBasedTxMgr) invokeNamespaceListeners() error {
 		txmgr.current.listeners = append(txmgr.current.listeners, listener)
 
 		committedStateQueryExecuter := &queryutil.QECombiner{
-			QueryExecuters: []queryutil.QueryExecuter{txmgr.db}}
+			QueryExecuters: []queryutil.QueryExecuter{txmgr.validationDB}}
 
 		postCommitQueryExecuter := &queryutil.QECombiner{
 			QueryExecuters: []queryutil.QueryExecuter{
 				&queryutil.UpdateBatchBackedQueryExecuter{UpdateBatch: txmgr.current.batch.PubUpdates.UpdateBatch},
-				txmgr.db,
+				txmgr.validationDB,
 			},
 		}
 
@@ -434,12 +449,49 @@ func (txmgr *LockBasedTxMgr) invokeNamespaceListeners() error {
 func (txmgr *LockBasedTxMgr) Shutdown() {
 	// wait for background go routine to finish else the timing issue causes a nil pointer inside goleveldb code
 	// see FAB-11974
+	if err := txmgr.WaitForCommit(); err != nil {
+		logger.Errorf("Error committing updates to state database: %s", err)
+	}
 	txmgr.pvtdataPurgeMgr.WaitForPrepareToFinish()
 	txmgr.db.Close()
 }
 
 // Commit implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Commit() error {
+	return txmgr.commit(false)
+}
+
+// StartCommit implements method in interface `txmgmt.TxMgr`. The updates are applied to the state database
+// in the background, whereas the write lock on the state is acquired before returning, so that the simulators
+// and query executors created from then on see the updates. In the meantime, the next block is validated
+// against the state database overlaid with the updates. A state database which caches the committed versions
+// for validating in bulk, such as CouchDB, is updated before returning, as the cache is shared with the validation
+func (txmgr *LockBasedTxMgr) StartCommit() error {
+	return txmgr.commit(!txmgr.db.IsBulkOptimizable())
+}
+
+// WaitForCommit implements method in interface `txmgmt.TxMgr`
+func (txmgr *LockBasedTxMgr) WaitForCommit() error {
+	pending := txmgr.getPendingCommit()
+	if pending == nil {
+		return nil
+	}
+	<-pending.done
+	return pending.err
+}
+
+func (txmgr *LockBasedTxMgr) getPendingCommit() *pendingCommit {
+	txmgr.pendingCommitLock.Lock()
+	defer txmgr.pendingCommitLock.Unlock()
+	return txmgr.pendingCommit
+}
+
+func (txmgr *LockBasedTxMgr) commit(inBackground bool) error {
+	// the commit of the preceding block must be done, as it prepares the keys expiring with this block
+	if err := txmgr.WaitForCommit(); err != nil {
+		return err
+	}
+
 	// we need to acquire a lock on oldBlockCommit. The following are the two reasons:
 	// (1) the DeleteExpiredAndUpdateBookkeeping() would perform incorrect operation if
 	//        toPurgeList is updated by RemoveStaleAndCommitPvtDataOfOldBlocks().
@@ -447,37 +499,58 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 	//     batch based on the current state and if we allow regular block commits at the
 	//     same time, the former may overwrite the newer versions of the data and we may
 	//     end up with an incorrect update batch.
+	// RemoveStaleAndCommitPvtDataOfOldBlocks waits for a commit done in the background once it holds the lock
 	txmgr.oldBlockCommit.Lock()
 	defer txmgr.oldBlockCommit.Unlock()
 	logger.Debug("lock acquired on oldBlockCommit for committing regular updates to state database")
 
+	logger.Debugf("Committing updates to state database")
+	if txmgr.current == nil {
+		panic("validateAndPrepare() method should have been called before calling commit()")
+	}
+	current := txmgr.current
+	txmgr.reset()
+
 	// When using the purge manager for the first block commit after peer start, the asynchronous function
 	// 'PrepareForExpiringKeys' is invoked in-line. However, for the subsequent blocks commits, this function is invoked
 	// in advance for the next block
 	if !txmgr.pvtdataPurgeMgr.usedOnce {
-		txmgr.pvtdataPurgeMgr.PrepareForExpiringKeys(txmgr.current.blockNum())
+		txmgr.pvtdataPurgeMgr.PrepareForExpiringKeys(current.blockNum())
 		txmgr.pvtdataPurgeMgr.usedOnce = true
 	}
-	defer func() {
-		txmgr.pvtdataPurgeMgr.PrepareForExpiringKeys(txmgr.current.blockNum() + 1)
-		logger.Debugf("launched the background routine for preparing keys to purge with the next block")
-		txmgr.reset()
-	}()
-
-	logger.Debugf("Committing updates to state database")
-	if txmgr.current == nil {
-		panic("validateAndPrepare() method should have been called before calling commit()")
-	}
 
 	if err := txmgr.pvtdataPurgeMgr.DeleteExpiredAndUpdateBookkeeping(
-		txmgr.current.batch.PvtUpdates, txmgr.current.batch.HashUpdates); err != nil {
+		current.batch.PvtUpdates, current.batch.HashUpdates); err != nil {
+		txmgr.prepareForExpiringKeysOfNextBlock(current)
 		return err
 	}
 
-	commitHeight := version.NewHeight(txmgr.current.blockNum(), txmgr.current.maxTxNumber())
+	commitHeight := version.NewHeight(current.blockNum(), current.maxTxNumber())
 	txmgr.commitRWLock.Lock()
 	logger.Debugf("Write lock acquired for committing updates to state database")
-	if err := txmgr.db.ApplyPrivacyAwareUpdates(txmgr.current.batch, commitHeight); err != nil {
+	if !inBackground {
+		return txmgr.applyUpdates(current, commitHeight)
+	}
+
+	pending := &pendingCommit{
+		updates: copyUpdatesForValidation(current.batch),
+		done:    make(chan struct{}),
+	}
+	txmgr.pendingCommitLock.Lock()
+	txmgr.pendingCommit = pending
+	txmgr.pendingCommitLock.Unlock()
+	go func() {
+		defer close(pending.done)
+		pending.err = txmgr.applyUpdates(current, commitHeight)
+	}()
+	return nil
+}
+
+// applyUpdates applies the updates of the block to the state database and releases the write lock on the
+// state, which the caller acquired
+func (txmgr *LockBasedTxMgr) applyUpdates(current *current, commitHeight *version.Height) error {
+	defer txmgr.prepareForExpiringKeysOfNextBlock(current)
+	if err := txmgr.db.ApplyPrivacyAwareUpdates(current.batch, commitHeight); err != nil {
 		txmgr.commitRWLock.Unlock()
 		return err
 	}
@@ -485,6 +558,7 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 	// only while holding a lock on oldBlockCommit, we should clear the cache as the
 	// cache is being used by the old pvtData committer to load the version of
 	// hashedKeys. Also, note that the PrepareForExpiringKeys uses the cache.
+	// The updates are applied in the background only to a state database without a cache
 	txmgr.clearCache()
 	logger.Debugf("Updates committed to state database and the write lock is released")
 
@@ -494,10 +568,15 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 	}
 	// In the case of error state listeners will not recieve this call - instead a peer panic is caused by the ledger upon receiveing
 	// an error from this function
-	txmgr.updateStateListeners()
+	txmgr.updateStateListeners(current)
 	return nil
 }
 
+func (txmgr *LockBasedTxMgr) prepareForExpiringKeysOfNextBlock(current *current) {
+	txmgr.pvtdataPurgeMgr.PrepareForExpiringKeys(current.blockNum() + 1)
+	logger.Debugf("launched the background routine for preparing keys to purge with the next block")
+}
+
 // Rollback implements method in interface `txmgmt.TxMgr`
 func (txmgr *LockBasedTxMgr) Rollback() {
 	txmgr.reset()
@@ -555,8 +634,8 @@ func extractStateUpdates(batch *privacyenabledstate.UpdateBatch, namespaces []st
 	return stateupdates
 }
 
-func (txmgr *LockBasedTxMgr) updateStateListeners() {
-	for _, l := range txmgr.current.listeners {
+func (txmgr *LockBasedTxMgr) updateStateListeners(current *current) {
+	for _, l := range current.listeners {
 		l.StateCommitDone(txmgr.ledgerid)
 	}
 }
@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lockbasedtxmgr
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/statebasedval"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+// pendingCommit is the commit of the updates of a block to the state database running in the background.
+// The updates are kept, apart from the batch being applied, for validating the next block in the meantime
+type pendingCommit struct {
+	updates *privacyenabledstate.UpdateBatch
+	done    chan struct{}
+	err     error
+}
+
+// isDone returns whether the commit is done
+func (p *pendingCommit) isDone() bool {
+	select {
+	case <-p.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// copyUpdatesForValidation returns the updates of the batch to be read while the batch is applied to the
+// state database. The public updates are copied, as the private data and hashed updates are added to the
+// public updates by the state database when the batch is applied, whereas the other updates are only read
+func copyUpdatesForValidation(batch *privacyenabledstate.UpdateBatch) *privacyenabledstate.UpdateBatch {
+	pubUpdates := privacyenabledstate.NewPubUpdateBatch()
+	for _, ns := range batch.PubUpdates.GetUpdatedNamespaces() {
+		for key, vv := range batch.PubUpdates.GetUpdates(ns) {
+			pubUpdates.Update(ns, key, vv)
+		}
+	}
+	return &privacyenabledstate.UpdateBatch{
+		PubUpdates:  pubUpdates,
+		HashUpdates: batch.HashUpdates,
+		PvtUpdates:  batch.PvtUpdates,
+	}
+}
+
+// pendingUpdatesDB overlays the state database with the updates of the block being committed in the
+// background, if any, so that the next block is validated against the state as of the block. Only the
+// reads done by the validation of a block and the state listeners are overlaid
+type pendingUpdatesDB struct {
+	privacyenabledstate.DB
+	// updates is set by ValidateAndPrepare, while the lock on oldBlockCommit is held
+	updates *privacyenabledstate.UpdateBatch
+}
+
+func (d *pendingUpdatesDB) pubUpdate(ns, key string) *statedb.VersionedValue {
+	if d.updates == nil {
+		return nil
+	}
+	return d.updates.PubUpdates.Get(ns, key)
+}
+
+func (d *pendingUpdatesDB) hashedUpdate(ns, coll string, keyHash []byte) *statedb.VersionedValue {
+	if d.updates == nil {
+		return nil
+	}
+	return d.updates.HashUpdates.Get(ns, coll, string(keyHash))
+}
+
+// GetState implements method in interface `statedb.VersionedDB`
+func (d *pendingUpdatesDB) GetState(namespace, key string) (*statedb.VersionedValue, error) {
+	if vv := d.pubUpdate(namespace, key); vv != nil {
+		return nonDeleted(vv), nil
+	}
+	return d.DB.GetState(namespace, key)
+}
+
+// GetVersion implements method in interface `statedb.VersionedDB`
+func (d *pendingUpdatesDB) GetVersion(namespace, key string) (*version.Height, error) {
+	if vv := d.pubUpdate(namespace, key); vv != nil {
+		return versionOf(vv), nil
+	}
+	return d.DB.GetVersion(namespace, key)
+}
+
+// GetStateMultipleKeys implements method in interface `statedb.VersionedDB`
+func (d *pendingUpdatesDB) GetStateMultipleKeys(namespace string, keys []string) ([]*statedb.VersionedValue, error) {
+	vals := make([]*statedb.VersionedValue, len(keys))
+	for i, key := range keys {
+		vv, err := d.GetState(namespace, key)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = vv
+	}
+	return vals, nil
+}
+
+// GetStateRangeScanIterator implements method in interface `statedb.VersionedDB`
+func (d *pendingUpdatesDB) GetStateRangeScanIterator(namespace, startKey, endKey string) (statedb.ResultsIterator, error) {
+	if d.updates == nil {
+		return d.DB.GetStateRangeScanIterator(namespace, startKey, endKey)
+	}
+	return statebasedval.NewCombinedIterator(d.DB, d.updates.PubUpdates.UpdateBatch, namespace, startKey, endKey)
+}
+
+// GetStateMetadata implements method in interface `privacyenabledstate.DB`
+func (d *pendingUpdatesDB) GetStateMetadata(namespace, key string) ([]byte, error) {
+	if vv := d.pubUpdate(namespace, key); vv != nil {
+		return metadataOf(vv), nil
+	}
+	return d.DB.GetStateMetadata(namespace, key)
+}
+
+// GetPrivateData implements method in interface `privacyenabledstate.DB`
+func (d *pendingUpdatesDB) GetPrivateData(namespace, collection, key string) (*statedb.VersionedValue, error) {
+	if d.updates != nil {
+		if vv := d.updates.PvtUpdates.Get(namespace, collection, key); vv != nil {
+			return nonDeleted(vv), nil
+		}
+	}
+	return d.DB.GetPrivateData(namespace, collection, key)
+}
+
+// GetValueHash implements method in interface `privacyenabledstate.DB`
+func (d *pendingUpdatesDB) GetValueHash(namespace, collection string, keyHash []byte) (*statedb.VersionedValue, error) {
+	if vv := d.hashedUpdate(namespace, collection, keyHash); vv != nil {
+		return nonDeleted(vv), nil
+	}
+	return d.DB.GetValueHash(namespace, collection, keyHash)
+}
+
+// GetKeyHashVersion implements method in interface `privacyenabledstate.DB`
+func (d *pendingUpdatesDB) GetKeyHashVersion(namespace, collection string, keyHash []byte) (*version.Height, error) {
+	if vv := d.hashedUpdate(namespace, collection, keyHash); vv != nil {
+		return versionOf(vv), nil
+	}
+	return d.DB.GetKeyHashVersion(namespace, collection, keyHash)
+}
+
+// GetCachedKeyHashVersion implements method in interface `privacyenabledstate.DB`
+func (d *pendingUpdatesDB) GetCachedKeyHashVersion(namespace, collection string, keyHash []byte) (*version.Height, bool) {
+	if vv := d.hashedUpdate(namespace, collection, keyHash); vv != nil {
+		return versionOf(vv), true
+	}
+	return d.DB.GetCachedKeyHashVersion(namespace, collection, keyHash)
+}
+
+// GetPrivateDataMetadataByHash implements method in interface `privacyenabledstate.DB`
+func (d *pendingUpdatesDB) GetPrivateDataMetadataByHash(namespace, collection string, keyHash []byte) ([]byte, error) {
+	if vv := d.hashedUpdate(namespace, collection, keyHash); vv != nil {
+		return metadataOf(vv), nil
+	}
+	return d.DB.GetPrivateDataMetadataByHash(namespace, collection, keyHash)
+}
+
+// nonDeleted returns nil for the update of a deleted key, which is not found once the update is applied
+func nonDeleted(vv *statedb.VersionedValue) *statedb.VersionedValue {
+	if vv.IsDelete() {
+		return nil
+	}
+	return vv
+}
+
+func versionOf(vv *statedb.VersionedValue) *version.Height {
+	if vv.IsDelete() {
+		return nil
+	}
+	return vv.Version
+}
+
+func metadataOf(vv *statedb.VersionedValue) []byte {
+	if vv.IsDelete() {
+		return nil
+	}
+	return vv.Metadata
+}
@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lockbasedtxmgr
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartCommit(t *testing.T) {
+	dbEnv := &privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	dbEnv.Init(t)
+	defer dbEnv.Cleanup()
+	bookkeepingEnv := bookkeeping.NewTestEnv(t)
+	defer bookkeepingEnv.Cleanup()
+	db := &blockingDB{DB: dbEnv.GetDBHandle("testledger"), release: make(chan struct{}, 1)}
+	txMgr, err := NewLockBasedTxMgr("testledger", db, nil, nil, bookkeepingEnv.TestProvider, &mock.DeployedChaincodeInfoProvider{})
+	require.NoError(t, err)
+	defer txMgr.Shutdown()
+	bg, _ := testutil.NewBlockGenerator(t, "testledger", false)
+
+	// block 1 writes key1 and key2
+	block1 := validateBlockForTest(t, txMgr, bg, txRWSetForTest(t, func(b *rwsetutil.RWSetBuilder) {
+		b.AddToWriteSet("ns", "key1", []byte("value1.1"))
+		b.AddToWriteSet("ns", "key2", []byte("value2.1"))
+	}))
+	checkValidationFlags(t, block1, true)
+	db.release <- struct{}{}
+	require.NoError(t, txMgr.Commit())
+
+	// block 2 updates key1, deletes key2 and adds key3, and is committed in the background
+	block2 := validateBlockForTest(t, txMgr, bg, txRWSetForTest(t, func(b *rwsetutil.RWSetBuilder) {
+		b.AddToWriteSet("ns", "key1", []byte("value1.2"))
+		b.AddToWriteSet("ns", "key2", nil)
+		b.AddToWriteSet("ns", "key3", []byte("value3.2"))
+	}))
+	checkValidationFlags(t, block2, true)
+	require.NoError(t, txMgr.StartCommit())
+
+	// the queries wait for the commit
+	queried := make(chan []byte, 1)
+	go func() {
+		qe, err := txMgr.NewQueryExecutor("query")
+		assert.NoError(t, err)
+		defer qe.Done()
+		val, err := qe.GetState("ns", "key1")
+		assert.NoError(t, err)
+		queried <- val
+	}()
+
+	// block 3 is validated against the state as of block 2 while block 2 is being committed
+	block3 := validateBlockForTest(t, txMgr, bg,
+		txRWSetForTest(t, func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet("ns", "key1", version.NewHeight(2, 0))
+			b.AddToWriteSet("ns", "key4", []byte("value4.3"))
+		}),
+		txRWSetForTest(t, func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet("ns", "key1", version.NewHeight(1, 0))
+		}),
+		txRWSetForTest(t, func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet("ns", "key2", nil)
+			rqi := &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: "key4", ItrExhausted: true}
+			rqi.SetRawReads([]*kvrwset.KVRead{
+				rwsetutil.NewKVRead("key1", version.NewHeight(2, 0)),
+				rwsetutil.NewKVRead("key3", version.NewHeight(2, 0)),
+			})
+			b.AddToRangeQuerySet("ns", rqi)
+		}),
+		txRWSetForTest(t, func(b *rwsetutil.RWSetBuilder) {
+			rqi := &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: "key4", ItrExhausted: true}
+			rqi.SetRawReads([]*kvrwset.KVRead{
+				rwsetutil.NewKVRead("key1", version.NewHeight(1, 0)),
+				rwsetutil.NewKVRead("key2", version.NewHeight(1, 0)),
+			})
+			b.AddToRangeQuerySet("ns", rqi)
+		}),
+	)
+	checkValidationFlags(t, block3, true, false, true, false)
+	select {
+	case <-queried:
+		t.Fatal("the query should wait for the commit of block 2")
+	case <-time.After(100 * time.Millisecond):
+	}
+	savepoint, err := txMgr.GetLastSavepoint()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), savepoint.BlockNum)
+
+	db.release <- struct{}{}
+	assert.Equal(t, []byte("value1.2"), <-queried)
+	assert.NoError(t, txMgr.WaitForCommit())
+	savepoint, err = txMgr.GetLastSavepoint()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), savepoint.BlockNum)
+
+	// the commit of block 3 waits for nothing, as the commit of block 2 is done
+	db.release <- struct{}{}
+	require.NoError(t, txMgr.StartCommit())
+	assert.NoError(t, txMgr.WaitForCommit())
+	savepoint, err = txMgr.GetLastSavepoint()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), savepoint.BlockNum)
+	qe, err := txMgr.NewQueryExecutor("query")
+	require.NoError(t, err)
+	defer qe.Done()
+	val, err := qe.GetState("ns", "key4")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value4.3"), val)
+}
+
+// blockingDB holds the updates of each block until they are released
+type blockingDB struct {
+	privacyenabledstate.DB
+	release chan struct{}
+}
+
+func (db *blockingDB) ApplyPrivacyAwareUpdates(updates *privacyenabledstate.UpdateBatch, height *version.Height) error {
+	<-db.release
+	return db.DB.ApplyPrivacyAwareUpdates(updates, height)
+}
+
+func txRWSetForTest(t *testing.T, build func(b *rwsetutil.RWSetBuilder)) []byte {
+	b := rwsetutil.NewRWSetBuilder()
+	build(b)
+	simRes, err := b.GetTxSimulationResults()
+	require.NoError(t, err)
+	bytes, err := proto.Marshal(simRes.PubSimulationResults)
+	require.NoError(t, err)
+	return bytes
+}
+
+func validateBlockForTest(t *testing.T, txMgr *LockBasedTxMgr, bg *testutil.BlockGenerator, txRWSets ...[]byte) *common.Block {
+	block := bg.NextBlock(txRWSets)
+	_, err := txMgr.ValidateAndPrepare(&ledger.BlockAndPvtData{Block: block}, true)
+	require.NoError(t, err)
+	return block
+}
+
+func checkValidationFlags(t *testing.T, block *common.Block, valid ...bool) {
+	txsFltr := util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	for i, v := range valid {
+		assert.Equal(t, v, txsFltr.IsValid(i), "unexpected validation of transaction [%d]", i)
+	}
+}
@@ -659,18 +659,14 @@ func createTestValue(i int) []byte {
 	return []byte(fmt.Sprintf("value_%03d", i))
 }
 
-//TestExecuteQueryQuery is only tested on the CouchDB testEnv
 func TestExecuteQuery(t *testing.T) {
 
 	for _, testEnv := range testEnvs {
-		// Query is only supported and tested on the CouchDB testEnv
-		if testEnv.getName() == couchDBtestEnvName {
-			t.Logf("Running test for TestEnv = %s", testEnv.getName())
-			testLedgerID := "testexecutequery"
-			testEnv.init(t, testLedgerID, nil)
-			testExecuteQuery(t, testEnv)
-			testEnv.cleanup()
-		}
+		t.Logf("Running test for TestEnv = %s", testEnv.getName())
+		testLedgerID := "testexecutequery"
+		testEnv.init(t, testLedgerID, nil)
+		testExecuteQuery(t, testEnv)
+		testEnv.cleanup()
 	}
 }
 
@@ -734,18 +730,14 @@ func testExecuteQuery(t *testing.T, env testEnv) {
 	assert.Equal(t, 3, counter)
 }
 
-// TestExecutePaginatedQuery is only tested on the CouchDB testEnv
 func TestExecutePaginatedQuery(t *testing.T) {
 
 	for _, testEnv := range testEnvs {
-		// Query is only supported and tested on the CouchDB testEnv
-		if testEnv.getName() == couchDBtestEnvName {
-			t.Logf("Running test for TestEnv = %s", testEnv.getName())
-			testLedgerID := "testexecutepaginatedquery"
-			testEnv.init(t, testLedgerID, nil)
-			testExecutePaginatedQuery(t, testEnv)
-			testEnv.cleanup()
-		}
+		t.Logf("Running test for TestEnv = %s", testEnv.getName())
+		testLedgerID := "testexecutepaginatedquery"
+		testEnv.init(t, testLedgerID, nil)
+		testExecutePaginatedQuery(t, testEnv)
+		testEnv.cleanup()
 	}
 }
 
@@ -33,6 +33,10 @@ type TxMgr interface {
 	ShouldRecover(lastAvailableBlock uint64) (bool, uint64, error)
 	CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvtData) error
 	Commit() error
+	// StartCommit starts the commit of the block prepared by ValidateAndPrepare, which may complete in
+	// the background while the next block is validated. WaitForCommit waits for it and returns its error
+	StartCommit() error
+	WaitForCommit() error
 	Rollback()
 	Shutdown()
 }
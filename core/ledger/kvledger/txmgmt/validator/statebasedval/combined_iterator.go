@@ -72,6 +72,13 @@ func newCombinedIterator(db statedb.VersionedDB, updates *statedb.UpdateBatch,
 		dbItr, updatesItr, dbItem, updatesItem, false}, nil
 }
 
+// NewCombinedIterator returns an iterator over the keys of the given range in the db, as if the
+// given updates were applied to the db
+func NewCombinedIterator(db statedb.VersionedDB, updates *statedb.UpdateBatch,
+	ns string, startKey string, endKey string) (statedb.ResultsIterator, error) {
+	return newCombinedIterator(db, updates, ns, startKey, endKey, false)
+}
+
 // Next returns the KV from either dbItr or updatesItr that gives the next smaller key
 // If both gives the same keys, then it returns the KV from updatesItr.
 func (itr *combinedIterator) Next() (statedb.QueryResult, error) {
@@ -85,16 +85,29 @@ func (v *Validator) preLoadCommittedVersionOfRSet(block *internal.Block) error {
 	return nil
 }
 
-// ValidateAndPrepareBatch implements method in Validator interface
-func (v *Validator) ValidateAndPrepareBatch(block *internal.Block, doMVCCValidation bool) (*internal.PubAndHashUpdates, error) {
+// PreLoadCommittedVersions loads the committed versions of the keys read by the
+// transactions of the block into the cache of the statedb, if the statedb
+// supports bulk loading
+func (v *Validator) PreLoadCommittedVersions(block *internal.Block) error {
 	// Check whether statedb implements BulkOptimizable interface. For now,
 	// only CouchDB implements BulkOptimizable to reduce the number of REST
 	// API calls from peer to CouchDB instance.
 	if v.db.IsBulkOptimizable() {
-		err := v.preLoadCommittedVersionOfRSet(block)
-		if err != nil {
-			return nil, err
-		}
+		return v.preLoadCommittedVersionOfRSet(block)
+	}
+	return nil
+}
+
+// ValidateTx performs the mvcc validation of a transaction against the latest
+// committed state and the updates of the preceding valid transactions in the block
+func (v *Validator) ValidateTx(tx *internal.Transaction, updates *internal.PubAndHashUpdates) (peer.TxValidationCode, error) {
+	return v.validateTx(tx.RWSet, updates)
+}
+
+// ValidateAndPrepareBatch implements method in Validator interface
+func (v *Validator) ValidateAndPrepareBatch(block *internal.Block, doMVCCValidation bool) (*internal.PubAndHashUpdates, error) {
+	if err := v.PreLoadCommittedVersions(block); err != nil {
+		return nil, err
 	}
 
 	updates := internal.NewPubAndHashUpdates()
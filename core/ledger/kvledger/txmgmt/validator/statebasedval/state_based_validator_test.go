@@ -19,6 +19,7 @@ package statebasedval
 import (
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/flogging"
@@ -28,7 +29,6 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/internal"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/util"
-	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -177,171 +177,33 @@ func TestValidatorBulkLoadingOfCache(t *testing.T) {
 }
 
 func TestValidator(t *testing.T) {
-	testDBEnv := privacyenabledstate.LevelDBCommonStorageTestEnv{}
-	testDBEnv.Init(t)
-	defer testDBEnv.Cleanup()
-	db := testDBEnv.GetDBHandle("TestDB")
-
-	//populate db with initial data
-	batch := privacyenabledstate.NewUpdateBatch()
-	batch.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
-	batch.PubUpdates.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
-	batch.PubUpdates.Put("ns1", "key3", []byte("value3"), version.NewHeight(1, 2))
-	batch.PubUpdates.Put("ns1", "key4", []byte("value4"), version.NewHeight(1, 3))
-	batch.PubUpdates.Put("ns1", "key5", []byte("value5"), version.NewHeight(1, 4))
-	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 4))
-
-	validator := NewValidator(db)
-
-	//rwset1 should be valid
-	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
-	rwsetBuilder1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
-	rwsetBuilder1.AddToReadSet("ns2", "key2", nil)
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder1), []int{})
-
-	//rwset2 should not be valid
-	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
-	rwsetBuilder2.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder2), []int{0})
-
-	//rwset3 should not be valid
-	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
-	rwsetBuilder3.AddToReadSet("ns1", "key1", nil)
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder3), []int{0})
-
-	// rwset4 and rwset5 within same block - rwset4 should be valid and makes rwset5 as invalid
-	rwsetBuilder4 := rwsetutil.NewRWSetBuilder()
-	rwsetBuilder4.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
-	rwsetBuilder4.AddToWriteSet("ns1", "key1", []byte("value1_new"))
-
-	rwsetBuilder5 := rwsetutil.NewRWSetBuilder()
-	rwsetBuilder5.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder4, rwsetBuilder5), []int{1})
+	checkValidationTestCases(t, "reads/")
 }
 
 func TestPhantomValidation(t *testing.T) {
-	testDBEnv := privacyenabledstate.LevelDBCommonStorageTestEnv{}
-	testDBEnv.Init(t)
-	defer testDBEnv.Cleanup()
-	db := testDBEnv.GetDBHandle("TestDB")
-
-	//populate db with initial data
-	batch := privacyenabledstate.NewUpdateBatch()
-	batch.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
-	batch.PubUpdates.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
-	batch.PubUpdates.Put("ns1", "key3", []byte("value3"), version.NewHeight(1, 2))
-	batch.PubUpdates.Put("ns1", "key4", []byte("value4"), version.NewHeight(1, 3))
-	batch.PubUpdates.Put("ns1", "key5", []byte("value5"), version.NewHeight(1, 4))
-	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 4))
-
-	validator := NewValidator(db)
-
-	//rwset1 should be valid
-	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
-	rqi1 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: true}
-	rqi1.SetRawReads([]*kvrwset.KVRead{
-		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
-		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2))})
-	rwsetBuilder1.AddToRangeQuerySet("ns1", rqi1)
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder1), []int{})
-
-	//rwset2 should not be valid - Version of key4 changed
-	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
-	rqi2 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: false}
-	rqi2.SetRawReads([]*kvrwset.KVRead{
-		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
-		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2)),
-		rwsetutil.NewKVRead("key4", version.NewHeight(1, 2))})
-	rwsetBuilder2.AddToRangeQuerySet("ns1", rqi2)
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder2), []int{0})
-
-	//rwset3 should not be valid - simulate key3 got committed to db
-	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
-	rqi3 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: false}
-	rqi3.SetRawReads([]*kvrwset.KVRead{
-		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
-		rwsetutil.NewKVRead("key4", version.NewHeight(1, 3))})
-	rwsetBuilder3.AddToRangeQuerySet("ns1", rqi3)
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder3), []int{0})
-
-	// //Remove a key in rwset4 and rwset5 should become invalid
-	rwsetBuilder4 := rwsetutil.NewRWSetBuilder()
-	rwsetBuilder4.AddToWriteSet("ns1", "key3", nil)
-	rwsetBuilder5 := rwsetutil.NewRWSetBuilder()
-	rqi5 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: false}
-	rqi5.SetRawReads([]*kvrwset.KVRead{
-		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
-		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2)),
-		rwsetutil.NewKVRead("key4", version.NewHeight(1, 3))})
-	rwsetBuilder5.AddToRangeQuerySet("ns1", rqi5)
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder4, rwsetBuilder5), []int{1})
-
-	//Add a key in rwset6 and rwset7 should become invalid
-	rwsetBuilder6 := rwsetutil.NewRWSetBuilder()
-	rwsetBuilder6.AddToWriteSet("ns1", "key2_1", []byte("value2_1"))
-
-	rwsetBuilder7 := rwsetutil.NewRWSetBuilder()
-	rqi7 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: false}
-	rqi7.SetRawReads([]*kvrwset.KVRead{
-		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
-		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2)),
-		rwsetutil.NewKVRead("key4", version.NewHeight(1, 3))})
-	rwsetBuilder7.AddToRangeQuerySet("ns1", rqi7)
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder6, rwsetBuilder7), []int{1})
+	checkValidationTestCases(t, "phantom/")
 }
 
 func TestPhantomHashBasedValidation(t *testing.T) {
+	checkValidationTestCases(t, "phantomHash/")
+}
+
+// checkValidationTestCases runs the test cases whose name has the given prefix, each against a new db
+func checkValidationTestCases(t *testing.T, namePrefix string) {
 	testDBEnv := privacyenabledstate.LevelDBCommonStorageTestEnv{}
 	testDBEnv.Init(t)
 	defer testDBEnv.Cleanup()
-	db := testDBEnv.GetDBHandle("TestDB")
-
-	//populate db with initial data
-	batch := privacyenabledstate.NewUpdateBatch()
-	batch.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
-	batch.PubUpdates.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
-	batch.PubUpdates.Put("ns1", "key3", []byte("value3"), version.NewHeight(1, 2))
-	batch.PubUpdates.Put("ns1", "key4", []byte("value4"), version.NewHeight(1, 3))
-	batch.PubUpdates.Put("ns1", "key5", []byte("value5"), version.NewHeight(1, 4))
-	batch.PubUpdates.Put("ns1", "key6", []byte("value6"), version.NewHeight(1, 5))
-	batch.PubUpdates.Put("ns1", "key7", []byte("value7"), version.NewHeight(1, 6))
-	batch.PubUpdates.Put("ns1", "key8", []byte("value8"), version.NewHeight(1, 7))
-	batch.PubUpdates.Put("ns1", "key9", []byte("value9"), version.NewHeight(1, 8))
-	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 8))
-
-	validator := NewValidator(db)
 
-	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
-	rqi1 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key9", ItrExhausted: true}
-	kvReadsDuringSimulation1 := []*kvrwset.KVRead{
-		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
-		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2)),
-		rwsetutil.NewKVRead("key4", version.NewHeight(1, 3)),
-		rwsetutil.NewKVRead("key5", version.NewHeight(1, 4)),
-		rwsetutil.NewKVRead("key6", version.NewHeight(1, 5)),
-		rwsetutil.NewKVRead("key7", version.NewHeight(1, 6)),
-		rwsetutil.NewKVRead("key8", version.NewHeight(1, 7)),
-	}
-	rqi1.SetMerkelSummary(buildTestHashResults(t, 2, kvReadsDuringSimulation1))
-	rwsetBuilder1.AddToRangeQuerySet("ns1", rqi1)
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder1), []int{})
-
-	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
-	rqi2 := &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: "key9", ItrExhausted: false}
-	kvReadsDuringSimulation2 := []*kvrwset.KVRead{
-		rwsetutil.NewKVRead("key1", version.NewHeight(1, 0)),
-		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
-		rwsetutil.NewKVRead("key3", version.NewHeight(1, 1)),
-		rwsetutil.NewKVRead("key4", version.NewHeight(1, 3)),
-		rwsetutil.NewKVRead("key5", version.NewHeight(1, 4)),
-		rwsetutil.NewKVRead("key6", version.NewHeight(1, 5)),
-		rwsetutil.NewKVRead("key7", version.NewHeight(1, 6)),
-		rwsetutil.NewKVRead("key8", version.NewHeight(1, 7)),
-		rwsetutil.NewKVRead("key9", version.NewHeight(1, 8)),
+	for i, testCase := range ValidationTestCases(t) {
+		if !strings.HasPrefix(testCase.Name, namePrefix) {
+			continue
+		}
+		t.Run(testCase.Name, func(t *testing.T) {
+			db := testDBEnv.GetDBHandle(fmt.Sprintf("testdb%d", i))
+			testCase.PopulateDB(db)
+			checkValidation(t, NewValidator(db), getTestPubSimulationRWSet(t, testCase.Txs...), testCase.ExpectedInvalidTxs)
+		})
 	}
-	rqi2.SetMerkelSummary(buildTestHashResults(t, 2, kvReadsDuringSimulation2))
-	rwsetBuilder2.AddToRangeQuerySet("ns1", rqi2)
-	checkValidation(t, validator, getTestPubSimulationRWSet(t, rwsetBuilder2), []int{0})
 }
 
 func checkValidation(t *testing.T, val *Validator, transRWSets []*rwsetutil.TxRwSet, expectedInvalidTxIndexes []int) {
@@ -369,20 +231,6 @@ func checkValidation(t *testing.T, val *Validator, transRWSets []*rwsetutil.TxRw
 	assert.ElementsMatch(t, invalidTxs, expectedInvalidTxIndexes)
 }
 
-func buildTestHashResults(t *testing.T, maxDegree int, kvReads []*kvrwset.KVRead) *kvrwset.QueryReadsMerkleSummary {
-	if len(kvReads) <= maxDegree {
-		t.Fatal("This method should be called with number of KVReads more than maxDegree; Else, hashing won't be performedrwset")
-	}
-	helper, _ := rwsetutil.NewRangeQueryResultsHelper(true, uint32(maxDegree))
-	for _, kvRead := range kvReads {
-		helper.AddResult(kvRead)
-	}
-	_, h, err := helper.Done()
-	assert.NoError(t, err)
-	assert.NotNil(t, h)
-	return h
-}
-
 func getTestPubSimulationRWSet(t *testing.T, builders ...*rwsetutil.RWSetBuilder) []*rwsetutil.TxRwSet {
 	var pubRWSets []*rwsetutil.TxRwSet
 	for _, b := range builders {
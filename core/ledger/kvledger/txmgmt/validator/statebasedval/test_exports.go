@@ -0,0 +1,172 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statebasedval
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/stretchr/testify/assert"
+)
+
+// ValidationTestCase is a block of transactions validated against a state db holding the keys key1 to keyN
+// of the namespace ns1, where N is NumInitialKeys, along with the indexes of the transactions expected to be
+// invalid. The test cases are shared with the tests of the other validators, which are expected to agree
+// with the statebased validator
+type ValidationTestCase struct {
+	Name               string
+	NumInitialKeys     int
+	Txs                []*rwsetutil.RWSetBuilder
+	ExpectedInvalidTxs []int
+}
+
+// PopulateDB commits the initial keys of the test case to the db. The key keyI has the value valueI and
+// the version (1, I-1)
+func (c *ValidationTestCase) PopulateDB(db privacyenabledstate.DB) {
+	batch := privacyenabledstate.NewUpdateBatch()
+	for i := 1; i <= c.NumInitialKeys; i++ {
+		batch.PubUpdates.Put("ns1", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value%d", i)), version.NewHeight(1, uint64(i-1)))
+	}
+	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, uint64(c.NumInitialKeys-1)))
+}
+
+// ValidationTestCases returns the test cases of the validation of the reads, of the range queries and
+// of the range queries summarized by merkle trees
+func ValidationTestCases(t testing.TB) []*ValidationTestCase {
+	var testCases []*ValidationTestCase
+	add := func(name string, numInitialKeys int, expectedInvalidTxs []int, txs ...*rwsetutil.RWSetBuilder) {
+		testCases = append(testCases, &ValidationTestCase{name, numInitialKeys, txs, expectedInvalidTxs})
+	}
+
+	//rwset1 should be valid
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder1.AddToReadSet("ns2", "key2", nil)
+	add("reads/valid", 5, []int{}, rwsetBuilder1)
+
+	//rwset2 should not be valid
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToReadSet("ns1", "key1", version.NewHeight(1, 1))
+	add("reads/stale version", 5, []int{0}, rwsetBuilder2)
+
+	//rwset3 should not be valid
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder3.AddToReadSet("ns1", "key1", nil)
+	add("reads/missing version", 5, []int{0}, rwsetBuilder3)
+
+	// rwset4 and rwset5 within same block - rwset4 should be valid and makes rwset5 as invalid
+	rwsetBuilder4 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder4.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder4.AddToWriteSet("ns1", "key1", []byte("value1_new"))
+	rwsetBuilder5 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder5.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	add("reads/written in block", 5, []int{1}, rwsetBuilder4, rwsetBuilder5)
+
+	//rwset1 should be valid
+	rwsetBuilder1 = rwsetutil.NewRWSetBuilder()
+	rqi1 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: true}
+	rqi1.SetRawReads([]*kvrwset.KVRead{
+		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
+		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2))})
+	rwsetBuilder1.AddToRangeQuerySet("ns1", rqi1)
+	add("phantom/valid", 5, []int{}, rwsetBuilder1)
+
+	//rwset2 should not be valid - Version of key4 changed
+	rwsetBuilder2 = rwsetutil.NewRWSetBuilder()
+	rqi2 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: false}
+	rqi2.SetRawReads([]*kvrwset.KVRead{
+		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
+		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2)),
+		rwsetutil.NewKVRead("key4", version.NewHeight(1, 2))})
+	rwsetBuilder2.AddToRangeQuerySet("ns1", rqi2)
+	add("phantom/stale version", 5, []int{0}, rwsetBuilder2)
+
+	//rwset3 should not be valid - simulate key3 got committed to db
+	rwsetBuilder3 = rwsetutil.NewRWSetBuilder()
+	rqi3 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: false}
+	rqi3.SetRawReads([]*kvrwset.KVRead{
+		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
+		rwsetutil.NewKVRead("key4", version.NewHeight(1, 3))})
+	rwsetBuilder3.AddToRangeQuerySet("ns1", rqi3)
+	add("phantom/committed key", 5, []int{0}, rwsetBuilder3)
+
+	// //Remove a key in rwset4 and rwset5 should become invalid
+	rwsetBuilder4 = rwsetutil.NewRWSetBuilder()
+	rwsetBuilder4.AddToWriteSet("ns1", "key3", nil)
+	rwsetBuilder5 = rwsetutil.NewRWSetBuilder()
+	rqi5 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: false}
+	rqi5.SetRawReads([]*kvrwset.KVRead{
+		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
+		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2)),
+		rwsetutil.NewKVRead("key4", version.NewHeight(1, 3))})
+	rwsetBuilder5.AddToRangeQuerySet("ns1", rqi5)
+	add("phantom/deleted in block", 5, []int{1}, rwsetBuilder4, rwsetBuilder5)
+
+	//Add a key in rwset6 and rwset7 should become invalid
+	rwsetBuilder6 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder6.AddToWriteSet("ns1", "key2_1", []byte("value2_1"))
+	rwsetBuilder7 := rwsetutil.NewRWSetBuilder()
+	rqi7 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: false}
+	rqi7.SetRawReads([]*kvrwset.KVRead{
+		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
+		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2)),
+		rwsetutil.NewKVRead("key4", version.NewHeight(1, 3))})
+	rwsetBuilder7.AddToRangeQuerySet("ns1", rqi7)
+	add("phantom/added in block", 5, []int{1}, rwsetBuilder6, rwsetBuilder7)
+
+	rwsetBuilder1 = rwsetutil.NewRWSetBuilder()
+	rqi1 = &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key9", ItrExhausted: true}
+	kvReadsDuringSimulation1 := []*kvrwset.KVRead{
+		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
+		rwsetutil.NewKVRead("key3", version.NewHeight(1, 2)),
+		rwsetutil.NewKVRead("key4", version.NewHeight(1, 3)),
+		rwsetutil.NewKVRead("key5", version.NewHeight(1, 4)),
+		rwsetutil.NewKVRead("key6", version.NewHeight(1, 5)),
+		rwsetutil.NewKVRead("key7", version.NewHeight(1, 6)),
+		rwsetutil.NewKVRead("key8", version.NewHeight(1, 7)),
+	}
+	rqi1.SetMerkelSummary(buildTestHashResults(t, 2, kvReadsDuringSimulation1))
+	rwsetBuilder1.AddToRangeQuerySet("ns1", rqi1)
+	add("phantomHash/valid", 9, []int{}, rwsetBuilder1)
+
+	rwsetBuilder2 = rwsetutil.NewRWSetBuilder()
+	rqi2 = &kvrwset.RangeQueryInfo{StartKey: "key1", EndKey: "key9", ItrExhausted: false}
+	kvReadsDuringSimulation2 := []*kvrwset.KVRead{
+		rwsetutil.NewKVRead("key1", version.NewHeight(1, 0)),
+		rwsetutil.NewKVRead("key2", version.NewHeight(1, 1)),
+		rwsetutil.NewKVRead("key3", version.NewHeight(1, 1)),
+		rwsetutil.NewKVRead("key4", version.NewHeight(1, 3)),
+		rwsetutil.NewKVRead("key5", version.NewHeight(1, 4)),
+		rwsetutil.NewKVRead("key6", version.NewHeight(1, 5)),
+		rwsetutil.NewKVRead("key7", version.NewHeight(1, 6)),
+		rwsetutil.NewKVRead("key8", version.NewHeight(1, 7)),
+		rwsetutil.NewKVRead("key9", version.NewHeight(1, 8)),
+	}
+	rqi2.SetMerkelSummary(buildTestHashResults(t, 2, kvReadsDuringSimulation2))
+	rwsetBuilder2.AddToRangeQuerySet("ns1", rqi2)
+	add("phantomHash/stale version", 9, []int{0}, rwsetBuilder2)
+
+	return testCases
+}
+
+func buildTestHashResults(t testing.TB, maxDegree int, kvReads []*kvrwset.KVRead) *kvrwset.QueryReadsMerkleSummary {
+	if len(kvReads) <= maxDegree {
+		t.Fatal("This method should be called with number of KVReads more than maxDegree; Else, hashing won't be performedrwset")
+	}
+	helper, _ := rwsetutil.NewRangeQueryResultsHelper(true, uint32(maxDegree))
+	for _, kvRead := range kvReads {
+		helper.AddResult(kvRead)
+	}
+	_, h, err := helper.Done()
+	assert.NoError(t, err)
+	assert.NotNil(t, h)
+	return h
+}
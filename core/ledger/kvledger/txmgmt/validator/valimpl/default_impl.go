@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/internal"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/statebasedval"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 )
@@ -31,9 +32,16 @@ type DefaultImpl struct {
 }
 
 // NewStatebasedValidator constructs a validator that internally manages statebased validator and in addition
-// handles the tasks that are agnostic to a particular validation scheme such as parsing the block and handling the pvt data
+// handles the tasks that are agnostic to a particular validation scheme such as parsing the block and handling the pvt data.
+// The transactions of a block are validated concurrently by the statebased validator, based on the dependencies between
+// them, unless the configured pool size of the validator is 1
 func NewStatebasedValidator(txmgr txmgr.TxMgr, db privacyenabledstate.DB) validator.Validator {
-	return &DefaultImpl{txmgr, db, statebasedval.NewValidator(db)}
+	statebasedValidator := statebasedval.NewValidator(db)
+	poolSize := ledgerconfig.GetStateValidatorPoolSize()
+	if poolSize == 1 {
+		return &DefaultImpl{txmgr, db, statebasedValidator}
+	}
+	return &DefaultImpl{txmgr, db, &parallelValidator{statebasedValidator, db, poolSize}}
 }
 
 // ValidateAndPrepareBatch implements the function in interface validator.Validator
@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package valimpl
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/internal"
+)
+
+// dependencyGraph captures the read/write dependencies between the transactions
+// of a block. A transaction depends on the preceding transactions of the block
+// which write a key (or key hash) it reads, or write any key in a namespace it
+// performs range queries on. The mvcc validation of a transaction which depends
+// on no other transaction is not affected by the outcome of the validation of
+// the preceding transactions, hence it can be performed concurrently with the
+// validation of the other transactions.
+type dependencyGraph struct {
+	// dependencies holds, for each transaction of the block, the indexes in
+	// block.Txs of the transactions it depends on
+	dependencies [][]int
+}
+
+func newDependencyGraph(block *internal.Block) *dependencyGraph {
+	g := &dependencyGraph{dependencies: make([][]int, len(block.Txs))}
+
+	pubWriters := map[statedb.CompositeKey][]int{}
+	nsWriters := map[string][]int{}
+	hashedWriters := map[privacyenabledstate.HashedCompositeKey][]int{}
+
+	for i, tx := range block.Txs {
+		deps := map[int]struct{}{}
+		addDeps := func(writers []int) {
+			for _, w := range writers {
+				deps[w] = struct{}{}
+			}
+		}
+		for _, nsRWSet := range tx.RWSet.NsRwSets {
+			ns := nsRWSet.NameSpace
+			for _, kvRead := range nsRWSet.KvRwSet.GetReads() {
+				addDeps(pubWriters[statedb.CompositeKey{Namespace: ns, Key: kvRead.Key}])
+			}
+			if len(nsRWSet.KvRwSet.GetRangeQueriesInfo()) != 0 {
+				addDeps(nsWriters[ns])
+			}
+			for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+				for _, kvReadHash := range collHashedRWSet.HashedRwSet.GetHashedReads() {
+					addDeps(hashedWriters[hashedCompositeKey(ns, collHashedRWSet, kvReadHash.KeyHash)])
+				}
+			}
+		}
+		for d := range deps {
+			g.dependencies[i] = append(g.dependencies[i], d)
+		}
+		sort.Ints(g.dependencies[i])
+
+		for _, key := range writtenPubKeys(tx.RWSet) {
+			pubWriters[key] = appendWriter(pubWriters[key], i)
+			nsWriters[key.Namespace] = appendWriter(nsWriters[key.Namespace], i)
+		}
+		for _, key := range writtenHashedKeys(tx.RWSet) {
+			hashedWriters[key] = appendWriter(hashedWriters[key], i)
+		}
+	}
+	return g
+}
+
+// isIndependent returns true if the transaction at the given index in
+// block.Txs depends on no preceding transaction of the block
+func (g *dependencyGraph) isIndependent(txIndex int) bool {
+	return len(g.dependencies[txIndex]) == 0
+}
+
+// appendWriter appends the index of a transaction to the writers of a key,
+// unless the transaction is already the last writer of the key
+func appendWriter(writers []int, txIndex int) []int {
+	if len(writers) != 0 && writers[len(writers)-1] == txIndex {
+		return writers
+	}
+	return append(writers, txIndex)
+}
+
+func writtenPubKeys(txRWSet *rwsetutil.TxRwSet) []statedb.CompositeKey {
+	var keys []statedb.CompositeKey
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, kvWrite := range nsRWSet.KvRwSet.GetWrites() {
+			keys = append(keys, statedb.CompositeKey{Namespace: nsRWSet.NameSpace, Key: kvWrite.Key})
+		}
+		for _, kvMetadataWrite := range nsRWSet.KvRwSet.GetMetadataWrites() {
+			keys = append(keys, statedb.CompositeKey{Namespace: nsRWSet.NameSpace, Key: kvMetadataWrite.Key})
+		}
+	}
+	return keys
+}
+
+func writtenHashedKeys(txRWSet *rwsetutil.TxRwSet) []privacyenabledstate.HashedCompositeKey {
+	var keys []privacyenabledstate.HashedCompositeKey
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			for _, kvWriteHash := range collHashedRWSet.HashedRwSet.GetHashedWrites() {
+				keys = append(keys, hashedCompositeKey(nsRWSet.NameSpace, collHashedRWSet, kvWriteHash.KeyHash))
+			}
+			for _, kvMetadataWriteHash := range collHashedRWSet.HashedRwSet.GetMetadataWrites() {
+				keys = append(keys, hashedCompositeKey(nsRWSet.NameSpace, collHashedRWSet, kvMetadataWriteHash.KeyHash))
+			}
+		}
+	}
+	return keys
+}
+
+func hashedCompositeKey(ns string, collHashedRWSet *rwsetutil.CollHashedRwSet, keyHash []byte) privacyenabledstate.HashedCompositeKey {
+	return privacyenabledstate.HashedCompositeKey{
+		Namespace:      ns,
+		CollectionName: collHashedRWSet.CollectionName,
+		KeyHash:        string(keyHash),
+	}
+}
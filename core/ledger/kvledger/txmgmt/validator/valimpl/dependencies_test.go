@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package valimpl
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyGraph(t *testing.T) {
+	// tx0 writes ns1:key1 and ns1-coll1:key2
+	b0 := rwsetutil.NewRWSetBuilder()
+	b0.AddToWriteSet("ns1", "key1", []byte("value1"))
+	b0.AddToPvtAndHashedWriteSet("ns1", "coll1", "key2", []byte("value2"))
+
+	// tx1 reads ns1:key1, hence depends on tx0
+	b1 := rwsetutil.NewRWSetBuilder()
+	b1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+
+	// tx2 reads ns2:key1 and ns1-coll2:key2, which are written by no preceding tx
+	b2 := rwsetutil.NewRWSetBuilder()
+	b2.AddToReadSet("ns2", "key1", version.NewHeight(1, 0))
+	b2.AddToHashedReadSet("ns1", "coll2", "key2", version.NewHeight(1, 0))
+
+	// tx3 reads ns1-coll1:key2, hence depends on tx0, and deletes ns2:key3
+	b3 := rwsetutil.NewRWSetBuilder()
+	b3.AddToHashedReadSet("ns1", "coll1", "key2", version.NewHeight(1, 0))
+	b3.AddToWriteSet("ns2", "key3", nil)
+
+	// tx4 performs a range query on ns2, hence depends on tx3
+	b4 := rwsetutil.NewRWSetBuilder()
+	b4.AddToRangeQuerySet("ns2", &kvrwset.RangeQueryInfo{StartKey: "key5", EndKey: "key6", ItrExhausted: true})
+
+	// tx5 updates the metadata of ns1:key1
+	b5 := rwsetutil.NewRWSetBuilder()
+	b5.AddToMetadataWriteSet("ns1", "key1", map[string][]byte{"metadata": []byte("value")})
+
+	// tx6 reads ns1:key1, hence depends on tx0 and tx5
+	b6 := rwsetutil.NewRWSetBuilder()
+	b6.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+
+	block := testBlock(t, 1, b0, b1, b2, b3, b4, b5, b6)
+	g := newDependencyGraph(block)
+	assert.Equal(t, [][]int{nil, {0}, nil, {0}, {3}, nil, {0, 5}}, g.dependencies)
+	assert.True(t, g.isIndependent(0))
+	assert.False(t, g.isIndependent(1))
+	assert.True(t, g.isIndependent(2))
+	assert.False(t, g.isIndependent(6))
+}
@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package valimpl
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/internal"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+// txValidator performs the mvcc validation of the individual transactions of a
+// block, such as the statebased validator
+type txValidator interface {
+	PreLoadCommittedVersions(block *internal.Block) error
+	ValidateTx(tx *internal.Transaction, updates *internal.PubAndHashUpdates) (peer.TxValidationCode, error)
+}
+
+// parallelValidator implements the interface internal.Validator. It performs the
+// mvcc validation of the transactions of a block which do not depend on each
+// other concurrently, and the validation of the remaining transactions in the
+// order of the block, so that the outcome is the same as the one of the
+// sequential validation of the block
+type parallelValidator struct {
+	txValidator txValidator
+	db          privacyenabledstate.DB
+	poolSize    int
+}
+
+// ValidateAndPrepareBatch implements the function in interface internal.Validator
+func (v *parallelValidator) ValidateAndPrepareBatch(block *internal.Block, doMVCCValidation bool) (*internal.PubAndHashUpdates, error) {
+	var graph *dependencyGraph
+	var independentCodes []peer.TxValidationCode
+	if doMVCCValidation {
+		if err := v.txValidator.PreLoadCommittedVersions(block); err != nil {
+			return nil, err
+		}
+		graph = newDependencyGraph(block)
+		var err error
+		if independentCodes, err = v.validateIndependentTxs(block, graph); err != nil {
+			return nil, err
+		}
+	}
+
+	updates := internal.NewPubAndHashUpdates()
+	for i, tx := range block.Txs {
+		validationCode := peer.TxValidationCode_VALID
+		if doMVCCValidation {
+			if graph.isIndependent(i) {
+				validationCode = independentCodes[i]
+			} else {
+				// the validation of a transaction depending on preceding
+				// transactions requires the updates of the valid ones
+				var err error
+				if validationCode, err = v.txValidator.ValidateTx(tx, updates); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		tx.ValidationCode = validationCode
+		if validationCode == peer.TxValidationCode_VALID {
+			logger.Debugf("Block [%d] Transaction index [%d] TxId [%s] marked as valid by state validator", block.Num, tx.IndexInBlock, tx.ID)
+			committingTxHeight := version.NewHeight(block.Num, uint64(tx.IndexInBlock))
+			updates.ApplyWriteSet(tx.RWSet, committingTxHeight, v.db)
+		} else {
+			logger.Warningf("Block [%d] Transaction index [%d] TxId [%s] marked as invalid by state validator. Reason code [%s]",
+				block.Num, tx.IndexInBlock, tx.ID, validationCode.String())
+		}
+	}
+	return updates, nil
+}
+
+// validateIndependentTxs validates concurrently, against the latest committed
+// state, the transactions which do not depend on any preceding transaction
+func (v *parallelValidator) validateIndependentTxs(block *internal.Block, graph *dependencyGraph) ([]peer.TxValidationCode, error) {
+	codes := make([]peer.TxValidationCode, len(block.Txs))
+	errs := make([]error, len(block.Txs))
+
+	txIndexes := make(chan int, len(block.Txs))
+	for i := range block.Txs {
+		if graph.isIndependent(i) {
+			txIndexes <- i
+		}
+	}
+	close(txIndexes)
+
+	poolSize := v.poolSize
+	if poolSize > len(txIndexes) {
+		poolSize = len(txIndexes)
+	}
+	var wg sync.WaitGroup
+	wg.Add(poolSize)
+	for w := 0; w < poolSize; w++ {
+		go func() {
+			defer wg.Done()
+			// no updates from the preceding transactions are relevant to the
+			// validation of these transactions
+			noUpdates := internal.NewPubAndHashUpdates()
+			for i := range txIndexes {
+				codes[i], errs[i] = v.txValidator.ValidateTx(block.Txs[i], noUpdates)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return codes, nil
+}
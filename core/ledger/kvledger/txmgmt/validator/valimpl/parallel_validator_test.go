@@ -0,0 +1,392 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package valimpl
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/internal"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/statebasedval"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParallelValidator(t *testing.T) {
+	testDBEnv := &privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	batch := privacyenabledstate.NewUpdateBatch()
+	batch.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 0))
+	batch.PubUpdates.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 1))
+	batch.PubUpdates.Put("ns1", "key3", []byte("value3"), version.NewHeight(1, 2))
+	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 2))
+
+	// tx0 is valid and writes key1
+	b0 := rwsetutil.NewRWSetBuilder()
+	b0.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	b0.AddToWriteSet("ns1", "key1", []byte("value1_new"))
+	// tx1 reads key1 written by tx0, hence is invalid
+	b1 := rwsetutil.NewRWSetBuilder()
+	b1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	// tx2 reads a stale version of key2, hence is invalid
+	b2 := rwsetutil.NewRWSetBuilder()
+	b2.AddToReadSet("ns1", "key2", version.NewHeight(1, 0))
+	b2.AddToWriteSet("ns1", "key3", []byte("value3_new"))
+	// tx3 reads key3, only written by the invalid tx2, hence is valid
+	b3 := rwsetutil.NewRWSetBuilder()
+	b3.AddToReadSet("ns1", "key3", version.NewHeight(1, 2))
+
+	block := testBlock(t, 2, b0, b1, b2, b3)
+	validator := &parallelValidator{statebasedval.NewValidator(db), db, 4}
+	updates, err := validator.ValidateAndPrepareBatch(block, true)
+	require.NoError(t, err)
+	assert.Equal(t, []peer.TxValidationCode{
+		peer.TxValidationCode_VALID,
+		peer.TxValidationCode_MVCC_READ_CONFLICT,
+		peer.TxValidationCode_MVCC_READ_CONFLICT,
+		peer.TxValidationCode_VALID,
+	}, validationCodes(block))
+	assert.Equal(t, []byte("value1_new"), updates.PubUpdates.Get("ns1", "key1").Value)
+	assert.Nil(t, updates.PubUpdates.Get("ns1", "key3"))
+
+	// without mvcc validation, all the transactions are valid
+	block = testBlock(t, 2, b0, b1, b2, b3)
+	updates, err = validator.ValidateAndPrepareBatch(block, false)
+	require.NoError(t, err)
+	for _, code := range validationCodes(block) {
+		assert.Equal(t, peer.TxValidationCode_VALID, code)
+	}
+	assert.Equal(t, []byte("value3_new"), updates.PubUpdates.Get("ns1", "key3").Value)
+}
+
+type failingTxValidator struct {
+	*statebasedval.Validator
+	failingTx int
+}
+
+func (v *failingTxValidator) ValidateTx(tx *internal.Transaction, updates *internal.PubAndHashUpdates) (peer.TxValidationCode, error) {
+	if tx.IndexInBlock == v.failingTx {
+		return peer.TxValidationCode(-1), errors.New("validation error")
+	}
+	return v.Validator.ValidateTx(tx, updates)
+}
+
+func TestParallelValidatorError(t *testing.T) {
+	testDBEnv := &privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	b0 := rwsetutil.NewRWSetBuilder()
+	b0.AddToWriteSet("ns1", "key1", []byte("value1"))
+	b1 := rwsetutil.NewRWSetBuilder()
+	b1.AddToReadSet("ns1", "key1", nil)
+
+	// the validation of an independent transaction fails
+	validator := &parallelValidator{&failingTxValidator{statebasedval.NewValidator(db), 0}, db, 4}
+	_, err := validator.ValidateAndPrepareBatch(testBlock(t, 1, b0, b1), true)
+	assert.EqualError(t, err, "validation error")
+
+	// the validation of a dependent transaction fails
+	validator = &parallelValidator{&failingTxValidator{statebasedval.NewValidator(db), 1}, db, 4}
+	_, err = validator.ValidateAndPrepareBatch(testBlock(t, 1, b0, b1), true)
+	assert.EqualError(t, err, "validation error")
+}
+
+// TestParallelValidatorMatchesSequential validates the same blocks with the
+// sequential statebased validator and the parallel validator, and checks that
+// both yield the same validation codes and updates
+func TestParallelValidatorMatchesSequential(t *testing.T) {
+	testDBEnv := &privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+	committed := populateTestDB(db)
+
+	blocks := map[string][]*rwsetutil.RWSetBuilder{
+		"delete in range": testDeleteInRangeBlock(),
+		"chain":           testChainBlock(),
+	}
+	rnd := rand.New(rand.NewSource(0))
+	for i := 0; i < 50; i++ {
+		blocks[fmt.Sprintf("random block %d", i)] = testRandomBlock(rnd, committed, 40)
+	}
+
+	for name, builders := range blocks {
+		t.Run(name, func(t *testing.T) {
+			checkParallelMatchesSequential(t, db, builders)
+		})
+	}
+}
+
+// TestParallelValidatorMatchesSequentialOnStatebasedTestCases checks that the
+// parallel validator agrees with the statebased validator on the test cases of
+// the statebased validator
+func TestParallelValidatorMatchesSequentialOnStatebasedTestCases(t *testing.T) {
+	testDBEnv := &privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+
+	for i, testCase := range statebasedval.ValidationTestCases(t) {
+		t.Run(testCase.Name, func(t *testing.T) {
+			db := testDBEnv.GetDBHandle(fmt.Sprintf("TestDB%d", i))
+			testCase.PopulateDB(db)
+			codes := checkParallelMatchesSequential(t, db, testCase.Txs)
+			var invalidTxs []int
+			for txIndex, code := range codes {
+				if code != peer.TxValidationCode_VALID {
+					invalidTxs = append(invalidTxs, txIndex)
+				}
+			}
+			assert.ElementsMatch(t, testCase.ExpectedInvalidTxs, invalidTxs)
+		})
+	}
+}
+
+// TestParallelValidatorMatchesSequentialOnGeneratedBlocks validates the blocks
+// of the ledger test block generator, including a config block and blocks with
+// malformed rwsets, through the DefaultImpl with the sequential and with the
+// parallel validator, and checks that both yield the same results
+func TestParallelValidatorMatchesSequentialOnGeneratedBlocks(t *testing.T) {
+	testDBEnv := &privacyenabledstate.LevelDBCommonStorageTestEnv{}
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+	committed := populateTestDB(db)
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	blocks := []*common.Block{gb}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		var simulationResults [][]byte
+		for _, b := range testRandomBlock(rnd, committed, 20) {
+			simRes, err := b.GetTxSimulationResults()
+			require.NoError(t, err)
+			pubSimResBytes, err := simRes.GetPubSimulationBytes()
+			require.NoError(t, err)
+			simulationResults = append(simulationResults, pubSimResBytes)
+		}
+		blocks = append(blocks, bg.NextBlock(simulationResults))
+	}
+	blocks = append(blocks, bg.NextTestBlocks(5)...)
+
+	for _, block := range blocks {
+		t.Run(fmt.Sprintf("block %d", block.Header.Number), func(t *testing.T) {
+			for _, doMVCCValidation := range []bool{true, false} {
+				sequentialBlock := proto.Clone(block).(*common.Block)
+				sequentialValidator := &DefaultImpl{nil, db, statebasedval.NewValidator(db)}
+				sequentialUpdates, sequentialStats, err := sequentialValidator.ValidateAndPrepareBatch(
+					&ledger.BlockAndPvtData{Block: sequentialBlock}, doMVCCValidation)
+				require.NoError(t, err)
+
+				parallelBlock := proto.Clone(block).(*common.Block)
+				parallelValidator := &DefaultImpl{nil, db, &parallelValidator{statebasedval.NewValidator(db), db, 8}}
+				parallelUpdates, parallelStats, err := parallelValidator.ValidateAndPrepareBatch(
+					&ledger.BlockAndPvtData{Block: parallelBlock}, doMVCCValidation)
+				require.NoError(t, err)
+
+				assert.Equal(t, sequentialBlock.Metadata, parallelBlock.Metadata)
+				assert.Equal(t, sequentialUpdates, parallelUpdates)
+				assert.Equal(t, sequentialStats, parallelStats)
+			}
+		})
+	}
+}
+
+// populateTestDB commits ten keys and five hashed keys to each of the
+// namespaces ns1 and ns2, and returns the versions of the public keys
+func populateTestDB(db privacyenabledstate.DB) map[string]map[string]*version.Height {
+	committed := map[string]map[string]*version.Height{}
+	batch := privacyenabledstate.NewUpdateBatch()
+	txNum := uint64(0)
+	for _, ns := range []string{"ns1", "ns2"} {
+		committed[ns] = map[string]*version.Height{}
+		for i := 0; i < 10; i++ {
+			key := fmt.Sprintf("key%d", i)
+			ver := version.NewHeight(1, txNum)
+			batch.PubUpdates.Put(ns, key, []byte("value"), ver)
+			committed[ns][key] = ver
+			txNum++
+		}
+		for i := 0; i < 5; i++ {
+			key := fmt.Sprintf("hashedKey%d", i)
+			ver := version.NewHeight(1, txNum)
+			batch.HashUpdates.Put(ns, "coll1", []byte(key), []byte("value"), ver)
+			txNum++
+		}
+	}
+	db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, txNum))
+	return committed
+}
+
+// checkParallelMatchesSequential validates the transactions with the sequential
+// statebased validator and with the parallel validator, with and without mvcc
+// validation, checks that both yield the same validation codes and updates, and
+// returns the validation codes with mvcc validation
+func checkParallelMatchesSequential(t *testing.T, db privacyenabledstate.DB, builders []*rwsetutil.RWSetBuilder) []peer.TxValidationCode {
+	var codes []peer.TxValidationCode
+	for _, doMVCCValidation := range []bool{true, false} {
+		sequentialBlock := testBlock(t, 2, builders...)
+		sequentialUpdates, err := statebasedval.NewValidator(db).ValidateAndPrepareBatch(sequentialBlock, doMVCCValidation)
+		require.NoError(t, err)
+
+		parallelBlock := testBlock(t, 2, builders...)
+		validator := &parallelValidator{statebasedval.NewValidator(db), db, 8}
+		parallelUpdates, err := validator.ValidateAndPrepareBatch(parallelBlock, doMVCCValidation)
+		require.NoError(t, err)
+
+		assert.Equal(t, validationCodes(sequentialBlock), validationCodes(parallelBlock))
+		assert.Equal(t, sequentialUpdates, parallelUpdates)
+		if doMVCCValidation {
+			codes = validationCodes(parallelBlock)
+		}
+	}
+	return codes
+}
+
+// testDeleteInRangeBlock returns a block whose second transaction performs a
+// range query over a key deleted by the first one, and whose third transaction
+// performs a range query over a key only deleted by an invalid transaction
+func testDeleteInRangeBlock() []*rwsetutil.RWSetBuilder {
+	b0 := rwsetutil.NewRWSetBuilder()
+	b0.AddToWriteSet("ns1", "key3", nil)
+
+	b1 := rwsetutil.NewRWSetBuilder()
+	rqi1 := &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "key4", ItrExhausted: true}
+	rqi1.SetRawReads([]*kvrwset.KVRead{
+		rwsetutil.NewKVRead("key2", version.NewHeight(1, 2)),
+		rwsetutil.NewKVRead("key3", version.NewHeight(1, 3))})
+	b1.AddToRangeQuerySet("ns1", rqi1)
+
+	b2 := rwsetutil.NewRWSetBuilder()
+	b2.AddToReadSet("ns2", "key1", nil)
+	b2.AddToWriteSet("ns2", "key5", nil)
+
+	b3 := rwsetutil.NewRWSetBuilder()
+	rqi3 := &kvrwset.RangeQueryInfo{StartKey: "key4", EndKey: "key6", ItrExhausted: true}
+	rqi3.SetRawReads([]*kvrwset.KVRead{
+		rwsetutil.NewKVRead("key4", version.NewHeight(1, 19)),
+		rwsetutil.NewKVRead("key5", version.NewHeight(1, 20))})
+	b3.AddToRangeQuerySet("ns2", rqi3)
+	return []*rwsetutil.RWSetBuilder{b0, b1, b2, b3}
+}
+
+// testChainBlock returns a block whose transactions each read the key written
+// by the preceding transaction
+func testChainBlock() []*rwsetutil.RWSetBuilder {
+	var builders []*rwsetutil.RWSetBuilder
+	for i := 0; i < 9; i++ {
+		b := rwsetutil.NewRWSetBuilder()
+		b.AddToReadSet("ns1", fmt.Sprintf("key%d", i), version.NewHeight(1, uint64(i)))
+		b.AddToWriteSet("ns1", fmt.Sprintf("key%d", i+1), []byte("value"))
+		builders = append(builders, b)
+	}
+	return builders
+}
+
+// testRandomBlock returns a block of transactions performing random reads,
+// writes and range queries on a few keys, with current or stale versions
+func testRandomBlock(rnd *rand.Rand, committed map[string]map[string]*version.Height, numTxs int) []*rwsetutil.RWSetBuilder {
+	namespaces := []string{"ns1", "ns2"}
+	randomVersion := func(ns, key string) *version.Height {
+		if rnd.Intn(5) == 0 {
+			return version.NewHeight(1, 1000)
+		}
+		return committed[ns][key]
+	}
+
+	var builders []*rwsetutil.RWSetBuilder
+	for i := 0; i < numTxs; i++ {
+		b := rwsetutil.NewRWSetBuilder()
+		for op := rnd.Intn(4) + 1; op > 0; op-- {
+			ns := namespaces[rnd.Intn(len(namespaces))]
+			key := fmt.Sprintf("key%d", rnd.Intn(12))
+			hashedKey := fmt.Sprintf("hashedKey%d", rnd.Intn(6))
+			switch rnd.Intn(7) {
+			case 0, 1:
+				b.AddToReadSet(ns, key, randomVersion(ns, key))
+			case 2:
+				b.AddToWriteSet(ns, key, []byte(fmt.Sprintf("value-%d", i)))
+			case 3:
+				// the metadata is only written for the keys which are never
+				// deleted, as a metadata-only write requires an existing value
+				if rnd.Intn(2) == 0 {
+					b.AddToWriteSet(ns, fmt.Sprintf("key%d", rnd.Intn(8)+4), nil)
+				} else {
+					b.AddToMetadataWriteSet(ns, fmt.Sprintf("key%d", rnd.Intn(4)), map[string][]byte{"metadata": []byte("value")})
+				}
+			case 4:
+				startKey, endKey := fmt.Sprintf("key%d", rnd.Intn(5)), fmt.Sprintf("key%d", rnd.Intn(5)+5)
+				var keys []string
+				for k := range committed[ns] {
+					if k >= startKey && k < endKey {
+						keys = append(keys, k)
+					}
+				}
+				sort.Strings(keys)
+				var reads []*kvrwset.KVRead
+				for _, k := range keys {
+					reads = append(reads, rwsetutil.NewKVRead(k, randomVersion(ns, k)))
+				}
+				rqi := &kvrwset.RangeQueryInfo{StartKey: startKey, EndKey: endKey, ItrExhausted: true}
+				rqi.SetRawReads(reads)
+				b.AddToRangeQuerySet(ns, rqi)
+			case 5:
+				ver := version.NewHeight(1, uint64(rnd.Intn(30)))
+				if rnd.Intn(4) == 0 {
+					ver = nil
+				}
+				b.AddToHashedReadSet(ns, "coll1", hashedKey, ver)
+			case 6:
+				b.AddToPvtAndHashedWriteSet(ns, "coll1", hashedKey, []byte(fmt.Sprintf("value-%d", i)))
+			}
+		}
+		builders = append(builders, b)
+	}
+	return builders
+}
+
+func testBlock(t *testing.T, blockNum uint64, builders ...*rwsetutil.RWSetBuilder) *internal.Block {
+	block := &internal.Block{Num: blockNum}
+	for i, b := range builders {
+		simRes, err := b.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimResBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		txRWSet := &rwsetutil.TxRwSet{}
+		require.NoError(t, txRWSet.FromProtoBytes(pubSimResBytes))
+		block.Txs = append(block.Txs, &internal.Transaction{
+			IndexInBlock: i,
+			ID:           fmt.Sprintf("txid-%d", i),
+			RWSet:        txRWSet,
+		})
+	}
+	return block
+}
+
+func validationCodes(block *internal.Block) []peer.TxValidationCode {
+	var codes []peer.TxValidationCode
+	for _, tx := range block.Txs {
+		codes = append(codes, tx.ValidationCode)
+	}
+	return codes
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric-lib-go/healthz"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/metrics"
@@ -35,6 +36,10 @@ type PeerLedgerProvider interface {
 	// This function guarantees that the creation of ledger and committing the genesis block would an atomic action
 	// The chain id retrieved from the genesis block is treated as a ledger id
 	Create(genesisBlock *common.Block) (PeerLedger, error)
+	// CreateFromSnapshot creates a new ledger from a snapshot generated by function PeerLedger.GenerateSnapshot
+	// and returns the ledger along with the ledger id. The ledger starts at the block following the last block of the
+	// snapshot, and the blocks preceding the snapshot (except the last block and the last config block) are not available
+	CreateFromSnapshot(snapshotDir string) (PeerLedger, string, error)
 	// Open opens an already created ledger
 	Open(ledgerID string) (PeerLedger, error)
 	// Exists tells whether the ledger with given id exists
@@ -85,7 +90,8 @@ type PeerLedger interface {
 	PurgePrivateData(maxBlockNumToRetain uint64) error
 	// PrivateDataMinBlockNum returns the lowest retained endorsement block height
 	PrivateDataMinBlockNum() (uint64, error)
-	//Prune prunes the blocks/transactions that satisfy the given policy
+	// Prune prunes the blocks/transactions that satisfy the given policy. The supported policy is
+	// *BlockPrunePolicy. The state is not affected by the pruning
 	Prune(policy commonledger.PrunePolicy) error
 	// GetConfigHistoryRetriever returns the ConfigHistoryRetriever
 	GetConfigHistoryRetriever() (ConfigHistoryRetriever, error)
@@ -96,6 +102,21 @@ type PeerLedger interface {
 	CommitPvtDataOfOldBlocks(blockPvtData []*BlockPvtData) ([]*PvtdataHashMismatch, error)
 	// GetMissingPvtDataTracker return the MissingPvtDataTracker
 	GetMissingPvtDataTracker() (MissingPvtDataTracker, error)
+	// TxIDExists returns true if a transaction with the given id has been committed to the ledger,
+	// including the transactions committed before the snapshot that the ledger may have been created from
+	TxIDExists(txID string) (bool, error)
+	// GenerateSnapshot generates a snapshot of the ledger at the last committed block and returns the
+	// directory that contains the snapshot. The commit of the blocks is paused during the generation
+	GenerateSnapshot() (string, error)
+}
+
+// BlockPrunePolicy is the PrunePolicy for pruning the blocks of a PeerLedger. The block files that contain
+// only the blocks below RetainFromBlockNum are removed from the block store. If ArchiveDir is set, the removed
+// block files are moved to a sub-directory of ArchiveDir named after the ledger, instead of being deleted.
+// As the peer needs the last config block, the last config block is always retained
+type BlockPrunePolicy struct {
+	RetainFromBlockNum uint64
+	ArchiveDir         string
 }
 
 // ValidatedLedger represents the 'final ledger' after filtering out invalid transactions from PeerLedger.
@@ -173,6 +194,31 @@ type HistoryQueryExecutor interface {
 	// GetHistoryForKey retrieves the history of values for a key.
 	// The returned ResultsIterator contains results of type *KeyModification which is defined in protos/ledger/queryresult.
 	GetHistoryForKey(namespace string, key string) (commonledger.ResultsIterator, error)
+	// GetHistoryForKeyWithOptions retrieves the history of values for a key, restricted, ordered and paged
+	// as per the options.
+	// The returned ResultsIterator contains results of type *KeyModification which is defined in protos/ledger/queryresult.
+	GetHistoryForKeyWithOptions(namespace string, key string, options *HistoryQueryOptions) (QueryResultsIterator, error)
+	// GetPrivateDataHistoryForKey retrieves the history of the hashes of the values for a private data key.
+	// The returned ResultsIterator contains results of type *PrivateDataKeyModification which is defined in
+	// protos/ledger/queryresult. The value is set in a result only if the peer holds the private data of the transaction.
+	GetPrivateDataHistoryForKey(namespace string, collection string, key string) (commonledger.ResultsIterator, error)
+}
+
+// HistoryQueryOptions restricts the history of a key to the transactions of the blocks from StartBlock
+// to EndBlock, and with a timestamp from StartTime to EndTime, all bounds included. An EndBlock of zero
+// and nil times do not restrict the history. The time bounds are first resolved to the blocks that may
+// hold a transaction in the time range, so that only the modifications of these blocks are scanned.
+// The history is returned from the oldest to the newest modification, unless NewestFirst is set.
+// A non-zero Limit caps the number of modifications returned, in which case the bookmark of the
+// iterator marks the next modification. A Bookmark resumes the history from the modification it marks
+type HistoryQueryOptions struct {
+	StartBlock  uint64
+	EndBlock    uint64
+	StartTime   *timestamp.Timestamp
+	EndTime     *timestamp.Timestamp
+	NewestFirst bool
+	Limit       int32
+	Bookmark    string
 }
 
 // TxSimulator simulates a transaction on a consistent snapshot of the 'as recent state as possible'
@@ -430,6 +476,16 @@ func (NotFoundInIndexErr) Error() string {
 	return "Entry not found in index"
 }
 
+// ErrBlockArchived is returned by the block retrieval APIs if the requested block, or the block
+// containing the requested transaction, has been pruned from the block store
+type ErrBlockArchived struct {
+	Msg string
+}
+
+func (e *ErrBlockArchived) Error() string {
+	return e.Msg
+}
+
 // CollConfigNotDefinedError is returned whenever an operation
 // is requested on a collection whose config has not been defined
 type CollConfigNotDefinedError struct {
@@ -7,19 +7,30 @@ SPDX-License-Identifier: Apache-2.0
 package ledgerconfig
 
 import (
+	"encoding/hex"
 	"path/filepath"
 
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/core/config"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
 //IsCouchDBEnabled exposes the useCouchDB variable
 func IsCouchDBEnabled() bool {
+	return GetStateDatabase() == "CouchDB"
+}
+
+// GetStateDatabase returns the name of the state database implementation used by the peer.
+// It defaults to the LevelDB based implementation, named "goleveldb"
+func GetStateDatabase() string {
 	stateDatabase := viper.GetString("ledger.state.stateDatabase")
-	if stateDatabase == "CouchDB" {
-		return true
+	if stateDatabase == "" {
+		return "goleveldb"
 	}
-	return false
+	return stateDatabase
 }
 
 const confPeerFileSystemPath = "peer.fileSystemPath"
@@ -31,12 +42,26 @@ const confBookkeeper = "bookkeeper"
 const confConfigHistory = "configHistory"
 const confChains = "chains"
 const confPvtdataStore = "pvtdataStore"
+const confFileLock = "fileLock"
+const confSnapshots = "snapshots"
+const confEncryptionDataKeys = "encryptionDataKeys"
+const confSnapshotsRootDir = "ledger.snapshots.rootDir"
 const confTotalQueryLimit = "ledger.state.totalQueryLimit"
+const confStateValidatorPoolSize = "ledger.state.validatorPoolSize"
+const confEnableCommitPipeline = "ledger.state.enableCommitPipeline"
 const confInternalQueryLimit = "ledger.state.couchDBConfig.internalQueryLimit"
 const confEnableHistoryDatabase = "ledger.history.enableHistoryDatabase"
 const confMaxBatchSize = "ledger.state.couchDBConfig.maxBatchUpdateSize"
 const confAutoWarmIndexes = "ledger.state.couchDBConfig.autoWarmIndexes"
 const confWarmIndexesAfterNBlocks = "ledger.state.couchDBConfig.warmIndexesAfterNBlocks"
+const confMaxBlockfileSize = "ledger.blockchain.maxBlockfileSize"
+const confPruningRetainBlocks = "ledger.blockchain.pruning.retainBlocks"
+const confPruningArchiveDir = "ledger.blockchain.pruning.archiveDir"
+const confBlockCompression = "ledger.blockchain.compression.default"
+const confChannelBlockCompression = "ledger.blockchain.compression.channels"
+const confEncryptionEnabled = "ledger.encryption.enabled"
+const confEncryptionKey = "ledger.encryption.key"
+const confBCCSPProvider = "peer.BCCSP.Default"
 
 var confCollElgProcMaxDbBatchSize = &conf{"ledger.pvtdataStore.collElgProcMaxDbBatchSize", 5000}
 var confCollElgProcDbBatchesInterval = &conf{"ledger.pvtdataStore.collElgProcDbBatchesInterval", 1000}
@@ -83,11 +108,103 @@ func GetConfigHistoryPath() string {
 	return filepath.Join(GetRootPath(), confConfigHistory)
 }
 
-// GetMaxBlockfileSize returns maximum size of the block file
+// GetFileLockPath returns the filesystem path that is used to create a file lock, which
+// ensures that only one process at a time operates on the ledger data
+func GetFileLockPath() string {
+	return filepath.Join(GetRootPath(), confFileLock)
+}
+
+// GetSnapshotsRootDir returns the filesystem path under which the snapshots of the ledgers are generated.
+// If not configured, the snapshots are generated under the ledger root path
+func GetSnapshotsRootDir() string {
+	if viper.IsSet(confSnapshotsRootDir) && viper.GetString(confSnapshotsRootDir) != "" {
+		return config.GetPath(confSnapshotsRootDir)
+	}
+	return filepath.Join(GetRootPath(), confSnapshots)
+}
+
+// GetMaxBlockfileSize returns maximum size of the block file. It defaults to 64 MB
 func GetMaxBlockfileSize() int {
+	if size := viper.GetInt(confMaxBlockfileSize); size > 0 {
+		return size
+	}
 	return 64 * 1024 * 1024
 }
 
+// GetPruningRetainBlocks returns the number of the most recent blocks of a ledger that are retained when
+// the block files are pruned after the commit of a block. Zero means that the block files are not pruned
+func GetPruningRetainBlocks() uint64 {
+	retainBlocks := viper.GetInt(confPruningRetainBlocks)
+	if retainBlocks < 0 {
+		return 0
+	}
+	return uint64(retainBlocks)
+}
+
+// GetPruningArchiveDir returns the dir to which the block files removed by the pruning after the
+// commit of a block are moved. If not configured, the removed block files are deleted
+func GetPruningArchiveDir() string {
+	if viper.GetString(confPruningArchiveDir) == "" {
+		return ""
+	}
+	return config.GetPath(confPruningArchiveDir)
+}
+
+// GetBlockCompression returns the compression of the blocks appended to the block files
+// of the ledgers, and of the ledgers of specific channels. It defaults to no compression
+func GetBlockCompression() fsblkstorage.CompressionConf {
+	compression := fsblkstorage.CompressionConf{
+		Default: fsblkstorage.NoCompression,
+		Ledgers: map[string]fsblkstorage.Compression{},
+	}
+	if viper.IsSet(confBlockCompression) {
+		compression.Default = fsblkstorage.Compression(viper.GetString(confBlockCompression))
+	}
+	for channelID, channelCompression := range viper.GetStringMapString(confChannelBlockCompression) {
+		compression.Ledgers[channelID] = fsblkstorage.Compression(channelCompression)
+	}
+	return compression
+}
+
+// GetEncrypter returns the encrypter of the ledger data at rest, which looks the keys up in
+// the keystore of the default BCCSP, or nil if the encryption is not enabled. With the PKCS11
+// BCCSP, the key held by the HSM wraps the data keys the data is encrypted with, which are kept
+// in the ledger root directory. When the key is rotated, each store re-encrypts its data with the
+// current key, and fails to open if a key its data is still encrypted with is no longer in the keystore
+func GetEncrypter() (*encryption.Encrypter, error) {
+	if !viper.GetBool(confEncryptionEnabled) {
+		return nil, nil
+	}
+	key := viper.GetString(confEncryptionKey)
+	ski, err := hex.DecodeString(key)
+	if err != nil || len(ski) == 0 {
+		return nil, errors.Errorf("invalid ledger encryption key [%s], it should be the hex encoded subject key identifier of a key", key)
+	}
+	csp := factory.GetDefault()
+	keys := encryption.NewKeyProvider(csp, ski)
+	if viper.GetString(confBCCSPProvider) == "PKCS11" {
+		keys = encryption.NewWrappedKeyProvider(csp, ski, filepath.Join(GetRootPath(), confEncryptionDataKeys))
+	}
+	return encryption.NewEncrypter(csp, keys)
+}
+
+// GetStateValidatorPoolSize returns the number of goroutines performing the mvcc
+// validation of the transactions of a block concurrently. It defaults to 1, meaning
+// that the transactions are validated sequentially
+func GetStateValidatorPoolSize() int {
+	poolSize := viper.GetInt(confStateValidatorPoolSize)
+	if poolSize <= 0 {
+		poolSize = 1
+	}
+	return poolSize
+}
+
+// IsCommitPipelineEnabled returns whether the state and history databases are updated with a block
+// asynchronously, and the next block is validated in the meantime
+func IsCommitPipelineEnabled() bool {
+	return viper.GetBool(confEnableCommitPipeline)
+}
+
 // GetTotalQueryLimit exposes the totalLimit variable
 func GetTotalQueryLimit() int {
 	totalQueryLimit := viper.GetInt(confTotalQueryLimit)
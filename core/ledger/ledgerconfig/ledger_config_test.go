@@ -17,11 +17,19 @@ limitations under the License.
 package ledgerconfig
 
 import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestIsCouchDBEnabledDefault(t *testing.T) {
@@ -46,6 +54,16 @@ func TestIsCouchDBEnabled(t *testing.T) {
 	assert.True(t, updatedValue) //test config returns true
 }
 
+func TestGetStateDatabase(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.state.stateDatabase", "")
+	assert.Equal(t, "goleveldb", GetStateDatabase())
+	viper.Set("ledger.state.stateDatabase", "customdb")
+	assert.Equal(t, "customdb", GetStateDatabase())
+	assert.False(t, IsCouchDBEnabled())
+}
+
 func TestLedgerConfigPathDefault(t *testing.T) {
 	setUpCoreYAMLConfig()
 	assert.Equal(t, "/var/hyperledger/production/ledgersData", GetRootPath())
@@ -55,6 +73,8 @@ func TestLedgerConfigPathDefault(t *testing.T) {
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/chains", GetBlockStorePath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/pvtdataStore", GetPvtdataStorePath())
 	assert.Equal(t, "/var/hyperledger/production/ledgersData/bookkeeper", GetInternalBookkeeperPath())
+	assert.Equal(t, "/var/hyperledger/production/ledgersData/fileLock", GetFileLockPath())
+	assert.Equal(t, "/var/hyperledger/production/ledgersData/snapshots", GetSnapshotsRootDir())
 }
 
 func TestLedgerConfigPath(t *testing.T) {
@@ -68,6 +88,10 @@ func TestLedgerConfigPath(t *testing.T) {
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/chains", GetBlockStorePath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/pvtdataStore", GetPvtdataStorePath())
 	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/bookkeeper", GetInternalBookkeeperPath())
+	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/fileLock", GetFileLockPath())
+	assert.Equal(t, "/tmp/hyperledger/production/ledgersData/snapshots", GetSnapshotsRootDir())
+	viper.Set("ledger.snapshots.rootDir", "/tmp/hyperledger/snapshots")
+	assert.Equal(t, "/tmp/hyperledger/snapshots", GetSnapshotsRootDir())
 }
 
 func TestGetTotalLimitDefault(t *testing.T) {
@@ -90,6 +114,24 @@ func TestGetTotalLimit(t *testing.T) {
 	assert.Equal(t, 5000, updatedValue) //test config returns 5000
 }
 
+func TestGetStateValidatorPoolSize(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, 1, GetStateValidatorPoolSize())
+	viper.Set("ledger.state.validatorPoolSize", 0)
+	assert.Equal(t, 1, GetStateValidatorPoolSize())
+	viper.Set("ledger.state.validatorPoolSize", 8)
+	assert.Equal(t, 8, GetStateValidatorPoolSize())
+}
+
+func TestIsCommitPipelineEnabled(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.False(t, IsCommitPipelineEnabled())
+	viper.Set("ledger.state.enableCommitPipeline", true)
+	assert.True(t, IsCommitPipelineEnabled())
+}
+
 func TestGetQueryLimitDefault(t *testing.T) {
 	setUpCoreYAMLConfig()
 	defaultValue := GetInternalQueryLimit()
@@ -246,6 +288,87 @@ func TestGetWarmIndexesAfterNBlocks(t *testing.T) {
 
 func TestGetMaxBlockfileSize(t *testing.T) {
 	assert.Equal(t, 67108864, GetMaxBlockfileSize())
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, 67108864, GetMaxBlockfileSize())
+	viper.Set("ledger.blockchain.maxBlockfileSize", 1024)
+	assert.Equal(t, 1024, GetMaxBlockfileSize())
+}
+
+func TestGetPruningConfig(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, uint64(0), GetPruningRetainBlocks())
+	assert.Equal(t, "", GetPruningArchiveDir())
+	viper.Set("ledger.blockchain.pruning.retainBlocks", 1000)
+	viper.Set("ledger.blockchain.pruning.archiveDir", "/tmp/hyperledger/archive")
+	assert.Equal(t, uint64(1000), GetPruningRetainBlocks())
+	assert.Equal(t, "/tmp/hyperledger/archive", GetPruningArchiveDir())
+	viper.Set("ledger.blockchain.pruning.retainBlocks", -1)
+	assert.Equal(t, uint64(0), GetPruningRetainBlocks())
+}
+
+func TestGetBlockCompression(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	assert.Equal(t, fsblkstorage.CompressionConf{
+		Default: fsblkstorage.NoCompression,
+		Ledgers: map[string]fsblkstorage.Compression{},
+	}, GetBlockCompression())
+
+	viper.Set("ledger.blockchain.compression.default", "snappy")
+	viper.Set("ledger.blockchain.compression.channels", map[string]string{"mychannel": "none"})
+	assert.Equal(t, fsblkstorage.CompressionConf{
+		Default: fsblkstorage.SnappyCompression,
+		Ledgers: map[string]fsblkstorage.Compression{"mychannel": fsblkstorage.NoCompression},
+	}, GetBlockCompression())
+}
+
+func TestGetEncrypter(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	encrypter, err := GetEncrypter()
+	assert.NoError(t, err)
+	assert.Nil(t, encrypter)
+
+	viper.Set("ledger.encryption.enabled", true)
+	viper.Set("ledger.encryption.key", "not hex")
+	_, err = GetEncrypter()
+	assert.EqualError(t, err, "invalid ledger encryption key [not hex], it should be the hex encoded subject key identifier of a key")
+
+	viper.Set("ledger.encryption.key", "0123")
+	_, err = GetEncrypter()
+	assert.Contains(t, err.Error(), "error getting encryption key")
+
+	// With the PKCS11 BCCSP, the key wraps the data keys, which are kept in the ledger root directory
+	rootDir, err := ioutil.TempDir("", "ledgerconfig")
+	require.NoError(t, err)
+	defer os.RemoveAll(rootDir)
+	viper.Set("peer.fileSystemPath", rootDir)
+	require.NoError(t, factory.InitFactories(&factory.FactoryOpts{
+		ProviderName: "SW",
+		SwOpts: &factory.SwOpts{
+			SecLevel:      256,
+			HashFamily:    "SHA2",
+			InmemKeystore: &factory.InmemKeystoreOpts{},
+		},
+	}))
+	ski, err := encryption.GenerateKey(factory.GetDefault())
+	require.NoError(t, err)
+	viper.Set("ledger.encryption.key", hex.EncodeToString(ski))
+	defer viper.Set("peer.BCCSP.Default", viper.GetString("peer.BCCSP.Default"))
+	viper.Set("peer.BCCSP.Default", "PKCS11")
+	encrypter, err = GetEncrypter()
+	require.NoError(t, err)
+	encrypted, err := encrypter.Encrypt([]byte("value"))
+	require.NoError(t, err)
+	dataKeys, err := ioutil.ReadFile(filepath.Join(rootDir, "ledgersData", "encryptionDataKeys"))
+	require.NoError(t, err)
+	assert.Contains(t, string(dataKeys), " "+hex.EncodeToString(ski)+" ")
+
+	encrypter, err = GetEncrypter()
+	require.NoError(t, err)
+	assert.True(t, encrypter.IsCurrent(encrypted), "the data key should be unwrapped rather than generated again")
 }
 
 func setUpCoreYAMLConfig() {
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package ledgerstorage
 
 import (
+	"fmt"
 	"sync"
 
 	"github.com/hyperledger/fabric/common/flogging"
@@ -48,8 +49,14 @@ func NewProvider() *Provider {
 		blkstorage.IndexableAttrTxValidationCode,
 	}
 	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		panic(fmt.Sprintf("Error initializing the encryption of the block store: %s", err))
+	}
 	blockStoreProvider := fsblkstorage.NewProvider(
-		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
+		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()).
+			WithCompression(ledgerconfig.GetBlockCompression()).
+			WithEncryption(encrypter),
 		indexConfig)
 
 	pvtStoreProvider := pvtdatastorage.NewProvider()
@@ -81,6 +88,52 @@ func (p *Provider) Close() {
 	p.pvtdataStoreProvider.Close()
 }
 
+// ImportFromSnapshot bootstraps the block store of the ledger from a snapshot. The pvt data store
+// does not need to be bootstrapped, as it is initialized to the height of the block store when opened
+func (p *Provider) ImportFromSnapshot(ledgerid string, snapshotInfo *blkstorage.SnapshotInfo, txIDs blkstorage.TxIDsIterator) error {
+	return p.blkStoreProvider.ImportFromSnapshot(ledgerid, snapshotInfo, txIDs)
+}
+
+// ValidateRollbackParams checks that the ledger can be rolled back to the given block number
+func ValidateRollbackParams(blockStorageDir, ledgerID string, blockNum uint64) error {
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		return err
+	}
+	return fsblkstorage.ValidateRollbackParams(blockStorageDir, ledgerID, blockNum, encrypter)
+}
+
+// Rollback rolls back the block store of the ledger to the given block number.
+// The pvt data store is left untouched: as it is shared across channels and its
+// contents cannot be recovered from the blocks, the pvt data of the rolled back
+// blocks is retained and is not written again when the blocks are recommitted
+func Rollback(blockStorageDir, ledgerID string, blockNum uint64) error {
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		return err
+	}
+	return fsblkstorage.Rollback(blockStorageDir, ledgerID, blockNum, encrypter)
+}
+
+// ResetBlockStore rolls back the block stores of all the ledgers to their genesis blocks
+func ResetBlockStore(blockStorageDir string) error {
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		return err
+	}
+	return fsblkstorage.ResetBlockStore(blockStorageDir, encrypter)
+}
+
+// LedgersBootstrappedFromSnapshot returns the ids of the ledgers that were bootstrapped from a snapshot
+func LedgersBootstrappedFromSnapshot(blockStorageDir string) ([]string, error) {
+	return fsblkstorage.LedgersBootstrappedFromSnapshot(blockStorageDir)
+}
+
+// LedgersWithPrunedBlocks returns the ids of the ledgers whose blocks have been pruned
+func LedgersWithPrunedBlocks(blockStorageDir string) ([]string, error) {
+	return fsblkstorage.LedgersWithPrunedBlocks(blockStorageDir)
+}
+
 // Init initializes store with essential configurations
 func (s *Store) Init(btlPolicy pvtdatapolicy.BTLPolicy) {
 	s.pvtdataStore.Init(btlPolicy)
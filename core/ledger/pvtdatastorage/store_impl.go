@@ -108,7 +108,11 @@ type entriesForPvtDataOfOldBlocks struct {
 // NewProvider instantiates a StoreProvider
 func NewProvider() Provider {
 	dbPath := ledgerconfig.GetPvtdataStorePath()
-	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath})
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		panic(fmt.Sprintf("Error initializing the encryption of the pvtdata store: %s", err))
+	}
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: dbPath, Encrypter: encrypter})
 	return &provider{dbProvider: dbProvider}
 }
 
@@ -629,6 +633,9 @@ func (s *store) GetPvtDataByBlockNum(blockNum uint64, filter ledger.PvtNsCollFil
 			return v11RetrievePvtdata(itr, filter)
 		}
 		dataValueBytes := itr.Value()
+		if err := itr.Error(); err != nil {
+			return nil, err
+		}
 		dataKey := decodeDatakey(dataKeyBytes)
 		expired, err := isExpired(dataKey.nsCollBlk, s.btlPolicy, s.lastCommittedBlock)
 		if err != nil {
@@ -739,6 +746,9 @@ func (s *store) GetMissingPvtDataInfoForMostRecentBlocks(maxBlock int) (ledger.M
 		}
 
 		valueBytes := dbItr.Value()
+		if err := dbItr.Error(); err != nil {
+			return nil, err
+		}
 		bitmap, err := decodeMissingDataValue(valueBytes)
 		if err != nil {
 			return nil, err
@@ -820,6 +830,9 @@ func (s *store) retrieveExpiryEntries(minBlkNum, maxBlkNum uint64) ([]*expiryEnt
 	for itr.Next() {
 		expiryKeyBytes := itr.Key()
 		expiryValueBytes := itr.Value()
+		if err := itr.Error(); err != nil {
+			return nil, err
+		}
 		expiryKey := decodeExpiryKey(expiryKeyBytes)
 		expiryValue, err := decodeExpiryValue(expiryValueBytes)
 		if err != nil {
@@ -856,6 +869,10 @@ func (s *store) processCollElgEvents(maxBatchSize, batchesInterval int) {
 
 	for eventItr.Next() {
 		collElgKey, collElgVal := eventItr.Key(), eventItr.Value()
+		if err := eventItr.Error(); err != nil {
+			logger.Errorf("Error reading collection eligibility event: %s", err)
+			return
+		}
 		blkNum := decodeCollElgKey(collElgKey)
 		CollElgInfo, err := decodeCollElgVal(collElgVal)
 		logger.Debugf("Processing collection eligibility event [blkNum=%d], CollElgInfo=%s", blkNum, CollElgInfo)
@@ -873,6 +890,11 @@ func (s *store) processCollElgEvents(maxBatchSize, batchesInterval int) {
 
 				for collItr.Next() { // each entry
 					originalKey, originalVal := collItr.Key(), collItr.Value()
+					if err := collItr.Error(); err != nil {
+						collItr.Release()
+						logger.Errorf("Error reading missing data entry for [ns=%s, coll=%s]: %s", ns, coll, err)
+						return
+					}
 					modifiedKey := decodeMissingDataKey(originalKey)
 					modifiedKey.isEligible = true
 					batch.Delete(originalKey)
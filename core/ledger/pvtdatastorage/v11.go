@@ -32,13 +32,13 @@ func v11DecodePvtRwSet(encodedBytes []byte) (*rwset.TxPvtReadWriteSet, error) {
 
 func v11RetrievePvtdata(itr *leveldbhelper.Iterator, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error) {
 	var blkPvtData []*ledger.TxPvtData
-	txPvtData, err := v11DecodeKV(itr.Key(), itr.Value(), filter)
+	txPvtData, err := v11DecodeItr(itr, filter)
 	if err != nil {
 		return nil, err
 	}
 	blkPvtData = append(blkPvtData, txPvtData)
 	for itr.Next() {
-		pvtDatum, err := v11DecodeKV(itr.Key(), itr.Value(), filter)
+		pvtDatum, err := v11DecodeItr(itr, filter)
 		if err != nil {
 			return nil, err
 		}
@@ -47,6 +47,14 @@ func v11RetrievePvtdata(itr *leveldbhelper.Iterator, filter ledger.PvtNsCollFilt
 	return blkPvtData, nil
 }
 
+func v11DecodeItr(itr *leveldbhelper.Iterator, filter ledger.PvtNsCollFilter) (*ledger.TxPvtData, error) {
+	v := itr.Value()
+	if err := itr.Error(); err != nil {
+		return nil, err
+	}
+	return v11DecodeKV(itr.Key(), v, filter)
+}
+
 func v11DecodeKV(k, v []byte, filter ledger.PvtNsCollFilter) (*ledger.TxPvtData, error) {
 	bNum, tNum := v11DecodePK(k)
 	var pvtWSet *rwset.TxPvtReadWriteSet
@@ -81,6 +81,14 @@ func ResetConfigToDefaultValues() {
 	viper.Set("ledger.state.couchDBConfig.autoWarmIndexes", true)
 	viper.Set("ledger.state.couchDBConfig.warmIndexesAfterNBlocks", 1)
 	viper.Set("peer.fileSystemPath", "/var/hyperledger/production")
+	viper.Set("ledger.snapshots.rootDir", "")
+	viper.Set("ledger.encryption.enabled", false)
+	viper.Set("ledger.encryption.key", "")
+	viper.Set("ledger.state.validatorPoolSize", 1)
+	viper.Set("ledger.state.enableCommitPipeline", false)
+	viper.Set("ledger.blockchain.maxBlockfileSize", 0)
+	viper.Set("ledger.blockchain.pruning.retainBlocks", 0)
+	viper.Set("ledger.blockchain.pruning.archiveDir", "")
 }
 
 // ParseTestParams parses tests params
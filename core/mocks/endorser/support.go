@@ -33,7 +33,8 @@ type MockSupport struct {
 	GetTxSimulatorRv                 *mc.MockTxSim
 	GetTxSimulatorErr                error
 	CheckInstantiationPolicyError    error
-	GetTransactionByIDErr            error
+	TxIDExistsRv                     bool
+	TxIDExistsErr                    error
 	CheckACLErr                      error
 	SysCCMap                         map[string]struct{}
 	IsJavaRV                         bool
@@ -77,8 +78,8 @@ func (s *MockSupport) GetHistoryQueryExecutor(ledgername string) (ledger.History
 	return nil, nil
 }
 
-func (s *MockSupport) GetTransactionByID(chid, txID string) (*pb.ProcessedTransaction, error) {
-	return nil, s.GetTransactionByIDErr
+func (s *MockSupport) TxIDExists(chid, txID string) (bool, error) {
+	return s.TxIDExistsRv, s.TxIDExistsErr
 }
 
 func (s *MockSupport) GetLedgerHeight(channelID string) (uint64, error) {
@@ -20,6 +20,7 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
 	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -33,6 +34,13 @@ type Support struct {
 	ApplyVal      error
 	ACVal         channelconfig.ApplicationCapabilities
 
+	// PolicyManagerVal is returned by PolicyManager, which defaults to
+	// a mock policy manager when it is not set
+	PolicyManagerVal policies.Manager
+
+	// ConfigtxValidatorVal is returned by ConfigtxValidator
+	ConfigtxValidatorVal configtx.Validator
+
 	sync.Mutex
 	capabilitiesInvokeCount int
 	mspManagerInvokeCount   int
@@ -64,9 +72,17 @@ func (ms *Support) Apply(configtx *common.ConfigEnvelope) error {
 }
 
 func (ms *Support) PolicyManager() policies.Manager {
+	if ms.PolicyManagerVal != nil {
+		return ms.PolicyManagerVal
+	}
 	return &mockpolicies.Manager{}
 }
 
+// ConfigtxValidator returns ConfigtxValidatorVal
+func (ms *Support) ConfigtxValidator() configtx.Validator {
+	return ms.ConfigtxValidatorVal
+}
+
 func (ms *Support) GetMSPIDs(cid string) []string {
 	return []string{"SampleOrg"}
 }
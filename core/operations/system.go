@@ -26,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/common/metrics/statsd/goruntime"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/middleware"
+	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -142,6 +143,17 @@ func (s *System) RegisterChecker(component string, checker healthz.HealthChecker
 	return s.healthHandler.RegisterChecker(component, checker)
 }
 
+// RegisterHandler serves the given handler under the given pattern to the
+// clients which authenticate with a TLS certificate. An error is returned if
+// TLS is not enabled, as the clients could not authenticate.
+func (s *System) RegisterHandler(pattern string, handler http.Handler) error {
+	if !s.options.TLS.Enabled {
+		return errors.Errorf("TLS must be enabled to serve %s", pattern)
+	}
+	s.mux.Handle(pattern, s.handlerChain(handler, true))
+	return nil
+}
+
 func (s *System) initializeServer() {
 	s.mux = http.NewServeMux()
 	s.httpServer = &http.Server{
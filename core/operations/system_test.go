@@ -93,6 +93,26 @@ var _ = Describe("System", func() {
 		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
 	})
 
+	It("hosts secure endpoints for registered handlers", func() {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		})
+		err := system.RegisterHandler("/handler/", handler)
+		Expect(err).NotTo(HaveOccurred())
+		err = system.Start()
+		Expect(err).NotTo(HaveOccurred())
+
+		handlerURL := fmt.Sprintf("https://%s/handler/path", system.Addr())
+		resp, err := client.Get(handlerURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusTeapot))
+		resp.Body.Close()
+
+		resp, err = unauthClient.Get(handlerURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+	})
+
 	Context("when TLS is disabled", func() {
 		BeforeEach(func() {
 			options.TLS.Enabled = false
@@ -108,6 +128,11 @@ var _ = Describe("System", func() {
 			Expect(resp.StatusCode).To(Equal(http.StatusOK))
 			resp.Body.Close()
 		})
+
+		It("refuses to register handlers", func() {
+			err := system.RegisterHandler("/handler/", http.NotFoundHandler())
+			Expect(err).To(MatchError("TLS must be enabled to serve /handler/"))
+		})
 	})
 
 	Context("when ClientCertRequired is true", func() {
@@ -164,8 +164,8 @@ func (block *blockEvent) toFilteredBlock() (*peer.FilteredBlock, error) {
 		var env *common.Envelope
 		var err error
 
-		if ebytes == nil {
-			logger.Debugf("got nil data bytes for tx index %d, "+
+		if len(ebytes) == 0 {
+			logger.Debugf("got empty data bytes for tx index %d, "+
 				"block num %d", txIndex, block.Header.Number)
 			continue
 		}
@@ -392,6 +392,23 @@ func TestEventsServer_DeliverFiltered(t *testing.T) {
 		})
 	}
 }
+func TestFilteredBlockSkipsEmptyEntries(t *testing.T) {
+	chaincodeActionPayload, err := createChaincodeAction("mycc", "testEvent", "testID")
+	assert.NoError(t, err)
+	payload, err := createEndorsement("testChainID", "testID", chaincodeActionPayload)
+	assert.NoError(t, err)
+	block, err := createTestBlock([]*common.Envelope{{}, {Payload: utils.MarshalOrPanic(payload)}})
+	assert.NoError(t, err)
+
+	// Transactions filtered out by the deliver handler are replaced by empty entries
+	block.Data.Data[0] = []byte{}
+	b := blockEvent(*block)
+	filteredBlock, err := b.toFilteredBlock()
+	assert.NoError(t, err)
+	assert.Len(t, filteredBlock.FilteredTransactions, 1)
+	assert.Equal(t, "testID", filteredBlock.FilteredTransactions[0].Txid)
+}
+
 func createDefaultSupportMamangerMock(config testConfig, chaincodeActionPayload *peer.ChaincodeActionPayload) *mockChainManager {
 	chainManager := &mockChainManager{}
 	iter := &mockIterator{}
@@ -232,6 +232,7 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 				inproccontroller.ContainerType: inproccontroller.NewRegistry(),
 			},
 		),
+		nil,
 		mp,
 		platforms.NewRegistry(&golang.Platform{}),
 		peer.DefaultSupport,
@@ -353,6 +354,37 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	if len(cqr.GetChannels()) != 1 {
 		t.FailNow()
 	}
+
+	// generate a snapshot of the joined channel
+	args = [][]byte{[]byte(GenerateSnapshot), []byte(chainID)}
+	res = stub.MockInvokeWithSignedProposal("4", args, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	snapshotDir := string(res.Payload)
+	assert.DirExists(t, snapshotDir)
+
+	res = stub.MockInvokeWithSignedProposal("4", [][]byte{[]byte(GenerateSnapshot), []byte("unknownchainid")}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Unknown chain ID, unknownchainid", res.Message)
+
+	// joining by snapshot requires a snapshot dir
+	res = stub.MockInvokeWithSignedProposal("5", [][]byte{[]byte(JoinChainBySnapshot), nil}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Cannot join the channel, no snapshot directory provided", res.Message)
+
+	// the channel has already been joined
+	args = [][]byte{[]byte(JoinChainBySnapshot), []byte(snapshotDir)}
+	res = stub.MockInvokeWithSignedProposal("5", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "LedgerID already exists")
+
+	// both functions are restricted to the admins of the peer
+	sProp.Signature = nil
+	res = stub.MockInvokeWithSignedProposal("6", args, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "access denied for [JoinChainBySnapshot]")
+	res = stub.MockInvokeWithSignedProposal("6", [][]byte{[]byte(GenerateSnapshot), []byte(chainID)}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "access denied for [GenerateSnapshot][mytestchainid]")
 }
 
 func TestGetConfigTree(t *testing.T) {
@@ -8,12 +8,14 @@ package transientstore
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/transientstore"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
@@ -121,7 +123,11 @@ type RwsetScanner struct {
 
 // NewStoreProvider instantiates TransientStoreProvider
 func NewStoreProvider() StoreProvider {
-	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: GetTransientStorePath()})
+	encrypter, err := ledgerconfig.GetEncrypter()
+	if err != nil {
+		panic(fmt.Sprintf("Error initializing the encryption of the transient store: %s", err))
+	}
+	dbProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: GetTransientStorePath(), Encrypter: encrypter})
 	return &storeProvider{dbProvider: dbProvider}
 }
 
@@ -371,6 +377,9 @@ func (scanner *RwsetScanner) Next() (*EndorserPvtSimulationResults, error) {
 	}
 	dbKey := scanner.dbItr.Key()
 	dbVal := scanner.dbItr.Value()
+	if err := scanner.dbItr.Error(); err != nil {
+		return nil, err
+	}
 	_, blockHeight := splitCompositeKeyOfPvtRWSet(dbKey)
 
 	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
@@ -394,6 +403,9 @@ func (scanner *RwsetScanner) NextWithConfig() (*EndorserPvtSimulationResultsWith
 	}
 	dbKey := scanner.dbItr.Key()
 	dbVal := scanner.dbItr.Value()
+	if err := scanner.dbItr.Error(); err != nil {
+		return nil, err
+	}
 	_, blockHeight := splitCompositeKeyOfPvtRWSet(dbKey)
 
 	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
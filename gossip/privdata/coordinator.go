@@ -77,6 +77,13 @@ type Coordinator interface {
 	// returns missing transaction ids
 	StoreBlock(block *common.Block, data util.PvtDataCollections) error
 
+	// ValidateBlock validates the block and collects its private data, which are committed
+	// by CommitBlock. StoreBlock is the same as ValidateBlock followed by CommitBlock
+	ValidateBlock(block *common.Block, data util.PvtDataCollections) (*ValidatedBlock, error)
+
+	// CommitBlock commits the block validated by ValidateBlock with its private data
+	CommitBlock(validatedBlock *ValidatedBlock) error
+
 	// StorePvtData used to persist private data into transient store
 	StorePvtData(txid string, privData *transientstore2.TxPvtReadWriteSetWithConfigInfo, blckHeight uint64) error
 
@@ -120,6 +127,13 @@ type Support struct {
 	Fetcher
 }
 
+// ValidatedBlock is a block validated by the coordinator along with the private data of its
+// transactions, to be committed into the ledger
+type ValidatedBlock struct {
+	blockAndPvtData *ledger.BlockAndPvtData
+	txns            []string
+}
+
 type coordinator struct {
 	selfSignedData common.SignedData
 	Support
@@ -143,11 +157,21 @@ func (c *coordinator) StorePvtData(txID string, privData *transientstore2.TxPvtR
 
 // StoreBlock stores block with private data into the ledger
 func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDataCollections) error {
+	validatedBlock, err := c.ValidateBlock(block, privateDataSets)
+	if err != nil {
+		return err
+	}
+	return c.CommitBlock(validatedBlock)
+}
+
+// ValidateBlock validates the block and collects its private data from the given private data,
+// the transient store and the other peers
+func (c *coordinator) ValidateBlock(block *common.Block, privateDataSets util.PvtDataCollections) (*ValidatedBlock, error) {
 	if block.Data == nil {
-		return errors.New("Block data is empty")
+		return nil, errors.New("Block data is empty")
 	}
 	if block.Header == nil {
-		return errors.New("Block header is nil")
+		return nil, errors.New("Block header is nil")
 	}
 
 	logger.Infof("[%s] Received block [%d] from buffer", c.ChainID, block.Header.Number)
@@ -156,7 +180,7 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 	err := c.Validator.Validate(block)
 	if err != nil {
 		logger.Errorf("Validation failed: %+v", err)
-		return err
+		return nil, err
 	}
 
 	blockAndPvtData := &ledger.BlockAndPvtData{
@@ -168,13 +192,13 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 	ownedRWsets, err := computeOwnedRWsets(block, privateDataSets)
 	if err != nil {
 		logger.Warning("Failed computing owned RWSets", err)
-		return err
+		return nil, err
 	}
 
 	privateInfo, err := c.listMissingPrivateData(block, ownedRWsets)
 	if err != nil {
 		logger.Warning(err)
-		return err
+		return nil, err
 	}
 
 	retryThresh := viper.GetDuration("peer.gossip.pvtData.pullRetryThreshold")
@@ -229,20 +253,26 @@ func (c *coordinator) StoreBlock(block *common.Block, privateDataSets util.PvtDa
 		blockAndPvtData.MissingPvtData.Add(missingRWS.seqInBlock, missingRWS.namespace, missingRWS.collection, false)
 	}
 
+	return &ValidatedBlock{blockAndPvtData: blockAndPvtData, txns: privateInfo.txns}, nil
+}
+
+// CommitBlock commits the validated block and its private data into the ledger
+func (c *coordinator) CommitBlock(validatedBlock *ValidatedBlock) error {
+	blockAndPvtData := validatedBlock.blockAndPvtData
 	// commit block and private data
-	err = c.CommitWithPvtData(blockAndPvtData)
+	err := c.CommitWithPvtData(blockAndPvtData)
 	if err != nil {
 		return errors.Wrap(err, "commit failed")
 	}
 
 	if len(blockAndPvtData.PvtData) > 0 {
 		// Finally, purge all transactions in block - valid or not valid.
-		if err := c.PurgeByTxids(privateInfo.txns); err != nil {
-			logger.Error("Purging transactions", privateInfo.txns, "failed:", err)
+		if err := c.PurgeByTxids(validatedBlock.txns); err != nil {
+			logger.Error("Purging transactions", validatedBlock.txns, "failed:", err)
 		}
 	}
 
-	seq := block.Header.Number
+	seq := blockAndPvtData.Block.Header.Number
 	if seq%c.transientBlockRetention == 0 && seq > c.transientBlockRetention {
 		err := c.PurgeByHeight(seq - c.transientBlockRetention)
 		if err != nil {
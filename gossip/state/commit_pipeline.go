@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package state
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
+	"github.com/hyperledger/fabric/gossip/privdata"
+	"github.com/hyperledger/fabric/gossip/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// pipelinedLedgerResources is implemented by the ledger resources which validate a block apart from
+// committing it, so that a block is validated while the preceding block is committed
+type pipelinedLedgerResources interface {
+	// ValidateBlock validates the block and collects its private data
+	ValidateBlock(block *common.Block, data util.PvtDataCollections) (*privdata.ValidatedBlock, error)
+
+	// CommitBlock commits the block validated by ValidateBlock with its private data
+	CommitBlock(validatedBlock *privdata.ValidatedBlock) error
+}
+
+// pendingBlock is a validated block waiting to be committed
+type pendingBlock struct {
+	block          *common.Block
+	validatedBlock *privdata.ValidatedBlock
+	// committed is closed once the block is committed
+	committed chan struct{}
+}
+
+// commitPipeline validates the blocks in order and hands them to a goroutine that commits them, so that
+// the validation of a block overlaps with the commit of the preceding block. A block which depends on
+// the commit of the preceding block is validated once the preceding block is committed
+type commitPipeline struct {
+	s         *GossipStateProviderImpl
+	ledger    pipelinedLedgerResources
+	blocks    chan *pendingBlock
+	preceding *pendingBlock
+	done      sync.WaitGroup
+}
+
+func newCommitPipeline(s *GossipStateProviderImpl, ledger pipelinedLedgerResources) *commitPipeline {
+	p := &commitPipeline{s: s, ledger: ledger, blocks: make(chan *pendingBlock)}
+	p.done.Add(1)
+	go p.commitBlocks()
+	return p
+}
+
+// validateBlock validates the block and passes it on to be committed. It returns once the block is
+// validated and the preceding block is committed
+func (p *commitPipeline) validateBlock(block *common.Block, pvtData util.PvtDataCollections) error {
+	if p.preceding != nil && txvalidator.DependsOnPrecedingBlock(block, p.preceding.block) {
+		<-p.preceding.committed
+	}
+	validatedBlock, err := p.ledger.ValidateBlock(block, pvtData)
+	if err != nil {
+		logger.Errorf("Got error while validating(%+v)", errors.WithStack(err))
+		return err
+	}
+	pending := &pendingBlock{block: block, validatedBlock: validatedBlock, committed: make(chan struct{})}
+	p.blocks <- pending
+	p.preceding = pending
+	return nil
+}
+
+func (p *commitPipeline) commitBlocks() {
+	defer p.done.Done()
+	for pending := range p.blocks {
+		if err := p.ledger.CommitBlock(pending.validatedBlock); err != nil {
+			logger.Panicf("Cannot commit block to the ledger due to %+v", errors.WithStack(err))
+		}
+		p.s.blockCommitted(pending.block)
+		close(pending.committed)
+	}
+}
+
+// stop waits for the validated blocks to be committed
+func (p *commitPipeline) stop() {
+	close(p.blocks)
+	p.done.Wait()
+}
@@ -14,6 +14,7 @@ import (
 
 	pb "github.com/golang/protobuf/proto"
 	vsccErrors "github.com/hyperledger/fabric/common/errors"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/comm"
 	common2 "github.com/hyperledger/fabric/gossip/common"
@@ -527,6 +528,13 @@ func (s *GossipStateProviderImpl) queueNewMessage(msg *proto.GossipMessage) {
 func (s *GossipStateProviderImpl) deliverPayloads() {
 	defer s.done.Done()
 
+	commitBlock := s.commitBlock
+	if ledger, isPipelined := s.ledger.(pipelinedLedgerResources); isPipelined && ledgerconfig.IsCommitPipelineEnabled() {
+		pipeline := newCommitPipeline(s, ledger)
+		defer pipeline.stop()
+		commitBlock = pipeline.validateBlock
+	}
+
 	for {
 		select {
 		// Wait for notification that next seq has arrived
@@ -555,7 +563,7 @@ func (s *GossipStateProviderImpl) deliverPayloads() {
 						continue
 					}
 				}
-				if err := s.commitBlock(rawBlock, p); err != nil {
+				if err := commitBlock(rawBlock, p); err != nil {
 					if executionErr, isExecutionErr := err.(*vsccErrors.VSCCExecutionFailureError); isExecutionErr {
 						logger.Errorf("Failed executing VSCC due to %v. Aborting chain processing", executionErr)
 						return
@@ -773,12 +781,16 @@ func (s *GossipStateProviderImpl) commitBlock(block *common.Block, pvtData util.
 		return err
 	}
 
+	s.blockCommitted(block)
+	return nil
+}
+
+// blockCommitted advertises the ledger height once the block is committed
+func (s *GossipStateProviderImpl) blockCommitted(block *common.Block) {
 	// Update ledger height
 	s.mediator.UpdateLedgerHeight(block.Header.Number+1, common2.ChainID(s.chainID))
 	logger.Debugf("[%s] Committed block [%d] with %d transaction(s)",
 		s.chainID, block.Header.Number, len(block.Data.Data))
-
-	return nil
 }
 
 func min(a uint64, b uint64) uint64 {
@@ -39,6 +39,7 @@ import (
 	proto "github.com/hyperledger/fabric/protos/gossip"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	transientstore2 "github.com/hyperledger/fabric/protos/transientstore"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -729,6 +730,72 @@ func TestHaltChainProcessing(t *testing.T) {
 	assertLogged(t, recorder, "foobar")
 }
 
+func TestCommitPipeline(t *testing.T) {
+	// Scenario: the blocks are validated while the preceding block is committed, unless the preceding
+	// block changes what the validation reads
+	viper.Set("ledger.state.enableCommitPipeline", true)
+	defer viper.Set("ledger.state.enableCommitPipeline", false)
+
+	mc := &mockCommitter{Mock: &mock.Mock{}}
+	releaseCommit := make(chan struct{})
+	committed := make(chan uint64, 3)
+	mc.On("CommitWithPvtData", mock.Anything).Run(func(arg mock.Arguments) {
+		<-releaseCommit
+		committed <- arg.Get(0).(*pcomm.Block).Header.Number
+	})
+	mc.On("LedgerHeight", mock.Anything).Return(uint64(1), nil)
+	g := &mocks.GossipMock{}
+	g.On("Accept", mock.Anything, false).Return(make(<-chan *proto.GossipMessage), nil)
+	g.On("Accept", mock.Anything, true).Return(nil, make(chan proto.ReceivedMessage))
+	g.On("PeersOfChannel", mock.Anything).Return([]discovery.NetworkMember{})
+	v := &notifyingValidator{validated: make(chan uint64, 3)}
+	portPrefix := portStartRange + 550
+	p := newPeerNodeWithGossipWithValidator(newGossipConfig(portPrefix, 0), mc, noopPeerIdentityAcceptor, g, v)
+	defer p.shutdown()
+
+	// block 2 cannot be parsed, so block 3 is assumed to depend on it
+	for seq := uint64(1); seq <= 3; seq++ {
+		rawblock := pcomm.NewBlock(seq, []byte{})
+		if seq == 2 {
+			rawblock.Data.Data = [][]byte{[]byte("garbage")}
+			rawblock.Metadata.Metadata[pcomm.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{0}
+		}
+		b, _ := pb.Marshal(rawblock)
+		assert.NoError(t, p.s.AddPayload(&proto.Payload{SeqNum: seq, Data: b}))
+	}
+
+	assertNothingReceived := func(ch chan uint64) {
+		select {
+		case seq := <-ch:
+			t.Fatalf("unexpected block [%d]", seq)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+	assert.Equal(t, uint64(1), <-v.validated)
+	assert.Equal(t, uint64(2), <-v.validated)
+	assertNothingReceived(committed)
+
+	releaseCommit <- struct{}{}
+	assert.Equal(t, uint64(1), <-committed)
+	assertNothingReceived(v.validated)
+
+	releaseCommit <- struct{}{}
+	assert.Equal(t, uint64(2), <-committed)
+	assert.Equal(t, uint64(3), <-v.validated)
+	releaseCommit <- struct{}{}
+	assert.Equal(t, uint64(3), <-committed)
+}
+
+// notifyingValidator reports the blocks it validates
+type notifyingValidator struct {
+	validated chan uint64
+}
+
+func (v *notifyingValidator) Validate(block *pcomm.Block) error {
+	v.validated <- block.Header.Number
+	return nil
+}
+
 func TestFailures(t *testing.T) {
 	t.Parallel()
 	portPrefix := portStartRange + 400
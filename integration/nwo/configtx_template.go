@@ -132,6 +132,9 @@ Profiles:{{ range .Profiles }}
         Admins:
           Type: ImplicitMeta
           Rule: MAJORITY Admins
+        LifecycleEndorsement:
+          Type: ImplicitMeta
+          Rule: MAJORITY Writers
     Consortium: {{ .Consortium }}
     {{- end }}
 {{- end }}
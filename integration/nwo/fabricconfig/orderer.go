@@ -15,6 +15,8 @@ type Orderer struct {
 	Kafka      *Kafka             `yaml:"Kafka,omitempty"`
 	Operations *OrdererOperations `yaml:"Operations,omitempty"`
 
+	ChannelParticipation *ChannelParticipation `yaml:"ChannelParticipation,omitempty"`
+
 	ExtraProperties map[string]interface{} `yaml:",inline,omitempty"`
 }
 
@@ -32,6 +34,7 @@ type General struct {
 	Profile        *OrdererProfile        `yaml:"Profile,omitempty"`
 	BCCSP          *BCCSP                 `yaml:"BCCSP,omitempty"`
 	Authentication *OrdererAuthentication `yaml:"Authentication,omitempty"`
+	Throttling     *OrdererThrottling     `yaml:"Throttling,omitempty"`
 
 	ExtraProperties map[string]interface{} `yaml:",inline,omitempty"`
 }
@@ -66,6 +69,16 @@ type OrdererAuthentication struct {
 	TimeWindow time.Duration `yaml:"TimeWindow,omitempty"`
 }
 
+type OrdererThrottling struct {
+	Org    *OrdererRateLimit `yaml:"Org,omitempty"`
+	Client *OrdererRateLimit `yaml:"Client,omitempty"`
+}
+
+type OrdererRateLimit struct {
+	Rate  float64 `yaml:"Rate"`
+	Burst int     `yaml:"Burst,omitempty"`
+}
+
 type OrdererTopic struct {
 	ReplicationFactor int16
 }
@@ -126,3 +139,8 @@ type OrdererStatsd struct {
 	WriteInterval time.Duration `yaml:"WriteInterval,omitempty"`
 	Prefix        string        `yaml:"Prefix,omitempty"`
 }
+
+type ChannelParticipation struct {
+	Enabled            bool   `yaml:"Enabled"`
+	MaxRequestBodySize string `yaml:"MaxRequestBodySize,omitempty"`
+}
@@ -113,5 +113,8 @@ Metrics:
     Address: {{ if .StatsdEndpoint }}{{ .StatsdEndpoint }}{{ else }}127.0.0.1:8125{{ end }}
     WriteInterval: 5s
     Prefix: {{ ReplaceAll (ToLower Orderer.ID) "." "_" }}
+ChannelParticipation:
+  Enabled: false
+  MaxRequestBodySize: 1 MB
 {{- end }}
 `
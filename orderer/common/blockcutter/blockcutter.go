@@ -12,6 +12,7 @@ import (
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 )
 
 var logger = flogging.MustGetLogger("orderer.common.blockcutter")
@@ -27,14 +28,24 @@ type Receiver interface {
 	// `pending` indicates if there are still messages pending in the receiver.
 	Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, pending bool)
 
-	// Cut returns the current batch and starts a new one
+	// Cut returns the current batch and starts a new one.
+	// In FAIR mode, messages may remain pending after a batch is cut,
+	// so Cut must be called until it returns an empty batch to cut them all.
 	Cut() []*cb.Envelope
 }
 
+// Drain discards the messages pending in the receiver, cutting batches
+// until none remains.
+func Drain(r Receiver) {
+	for batch := r.Cut(); len(batch) > 0; batch = r.Cut() {
+	}
+}
+
 type receiver struct {
 	sharedConfigFetcher   OrdererConfigFetcher
 	pendingBatch          []*cb.Envelope
 	pendingBatchSizeBytes uint32
+	fairQueue             fairQueue
 
 	PendingBatchStartTime time.Time
 	ChannelID             string
@@ -66,17 +77,20 @@ func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher,
 //   - impossible
 //
 // Note that messageBatches can not be greater than 2.
+//
+// In FAIR mode, messages are held pending until they amount to BlockCutting.PendingBatches
+// batches, and then a batch is filled round-robin from the messages of each organization.
 func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, pending bool) {
+	ordererConfig := r.ordererConfig()
+	if ordererConfig.BlockCutting().GetMode() == ab.BlockCutting_FAIR || r.fairQueue.count > 0 {
+		return r.orderedFair(msg, ordererConfig)
+	}
+
 	if len(r.pendingBatch) == 0 {
 		// We are beginning a new batch, mark the time
 		r.PendingBatchStartTime = time.Now()
 	}
 
-	ordererConfig, ok := r.sharedConfigFetcher.OrdererConfig()
-	if !ok {
-		logger.Panicf("Could not retrieve orderer config to query batch parameters, block cutting is not possible")
-	}
-
 	batchSize := ordererConfig.BatchSize()
 
 	messageSizeBytes := messageSizeBytes(msg)
@@ -123,8 +137,51 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 	return
 }
 
+func (r *receiver) orderedFair(msg *cb.Envelope, ordererConfig channelconfig.Orderer) (messageBatches [][]*cb.Envelope, pending bool) {
+	if r.fairQueue.count == 0 {
+		r.PendingBatchStartTime = time.Now()
+	}
+	r.fairQueue.enqueue(msg)
+
+	pendingBatches := ordererConfig.BlockCutting().GetPendingBatches()
+	if pendingBatches == 0 {
+		pendingBatches = defaultPendingBatches
+	}
+	for r.fairQueue.exceeds(ordererConfig.BatchSize(), pendingBatches) {
+		logger.Debugf("Pending messages amount to %d batches, cutting batch", pendingBatches)
+		messageBatches = append(messageBatches, r.cutFair(ordererConfig))
+	}
+
+	return messageBatches, r.fairQueue.count > 0
+}
+
+func (r *receiver) cutFair(ordererConfig channelconfig.Orderer) []*cb.Envelope {
+	r.Metrics.BlockFillDuration.With("channel", r.ChannelID).Observe(time.Since(r.PendingBatchStartTime).Seconds())
+	batch := r.fairQueue.nextBatch(ordererConfig.BatchSize(), ordererConfig.BlockCutting().GetOrgWeights())
+	r.PendingBatchStartTime = time.Time{}
+	if r.fairQueue.count > 0 {
+		r.PendingBatchStartTime = time.Now()
+	}
+	return batch
+}
+
+func (r *receiver) ordererConfig() channelconfig.Orderer {
+	ordererConfig, ok := r.sharedConfigFetcher.OrdererConfig()
+	if !ok {
+		logger.Panicf("Could not retrieve orderer config to query batch parameters, block cutting is not possible")
+	}
+	return ordererConfig
+}
+
 // Cut returns the current batch and starts a new one
 func (r *receiver) Cut() []*cb.Envelope {
+	if r.fairQueue.count > 0 {
+		return r.cutFair(r.ordererConfig())
+	}
+	if len(r.pendingBatch) == 0 {
+		return nil
+	}
+
 	r.Metrics.BlockFillDuration.With("channel", r.ChannelID).Observe(time.Since(r.PendingBatchStartTime).Seconds())
 	r.PendingBatchStartTime = time.Time{}
 	batch := r.pendingBatch
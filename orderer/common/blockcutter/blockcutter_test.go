@@ -13,7 +13,9 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter/mock"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 var _ = Describe("Blockcutter", func() {
@@ -183,4 +185,130 @@ var _ = Describe("Blockcutter", func() {
 			})
 		})
 	})
+
+	Describe("Cut", func() {
+		It("returns an empty batch when no message is pending", func() {
+			Expect(bc.Cut()).To(BeEmpty())
+			Expect(fakeBlockFillDuration.ObserveCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("Ordered in FAIR mode", func() {
+		orgMessage := func(mspID, data string) *cb.Envelope {
+			return &cb.Envelope{Payload: utils.MarshalOrPanic(&cb.Payload{
+				Header: &cb.Header{SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{
+					Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID}),
+				})},
+				Data: []byte(data),
+			})}
+		}
+
+		var a1, a2, a3, a4, b1, b2 *cb.Envelope
+
+		BeforeEach(func() {
+			a1, a2, a3, a4 = orgMessage("OrgA", "a1"), orgMessage("OrgA", "a2"), orgMessage("OrgA", "a3"), orgMessage("OrgA", "a4")
+			b1, b2 = orgMessage("OrgB", "b1"), orgMessage("OrgB", "b2")
+
+			fakeConfig.BatchSizeReturns(&ab.BatchSize{
+				MaxMessageCount:   2,
+				PreferredMaxBytes: 1000,
+			})
+			fakeConfig.BlockCuttingReturns(&ab.BlockCutting{Mode: ab.BlockCutting_FAIR})
+		})
+
+		order := func(msgs ...*cb.Envelope) (batches [][]*cb.Envelope, pending bool) {
+			for _, msg := range msgs {
+				var cut [][]*cb.Envelope
+				cut, pending = bc.Ordered(msg)
+				batches = append(batches, cut...)
+			}
+			return batches, pending
+		}
+
+		It("fills batches round-robin across organizations once enough messages are pending", func() {
+			batches, pending := order(a1, a2, a3)
+			Expect(batches).To(BeEmpty())
+			Expect(pending).To(BeTrue())
+
+			batches, pending = order(b1)
+			Expect(batches).To(Equal([][]*cb.Envelope{{a1, b1}}))
+			Expect(pending).To(BeTrue())
+
+			Expect(bc.Cut()).To(Equal([]*cb.Envelope{a2, a3}))
+			Expect(bc.Cut()).To(BeEmpty())
+
+			Expect(fakeBlockFillDuration.ObserveCallCount()).To(Equal(2))
+			Expect(fakeBlockFillDuration.WithArgsForCall(0)).To(Equal([]string{"channel", "mychannel"}))
+		})
+
+		It("takes as many messages from an organization as its weight", func() {
+			fakeConfig.BatchSizeReturns(&ab.BatchSize{
+				MaxMessageCount:   3,
+				PreferredMaxBytes: 1000,
+			})
+			fakeConfig.BlockCuttingReturns(&ab.BlockCutting{
+				Mode:       ab.BlockCutting_FAIR,
+				OrgWeights: map[string]uint32{"OrgA": 2},
+			})
+
+			batches, pending := order(a1, a2, a3, a4, b1, b2)
+			Expect(batches).To(Equal([][]*cb.Envelope{{a1, a2, b1}}))
+			Expect(pending).To(BeTrue())
+
+			Expect(bc.Cut()).To(Equal([]*cb.Envelope{a3, a4, b2}))
+			Expect(bc.Cut()).To(BeEmpty())
+		})
+
+		It("holds as many batches as configured pending", func() {
+			fakeConfig.BlockCuttingReturns(&ab.BlockCutting{Mode: ab.BlockCutting_FAIR, PendingBatches: 3})
+
+			batches, _ := order(a1, a2, a3, a4, b1)
+			Expect(batches).To(BeEmpty())
+
+			batches, pending := order(b2)
+			Expect(batches).To(Equal([][]*cb.Envelope{{a1, b1}}))
+			Expect(pending).To(BeTrue())
+		})
+
+		It("isolates the messages larger than the preferred max bytes", func() {
+			fakeConfig.BatchSizeReturns(&ab.BatchSize{
+				MaxMessageCount:   2,
+				PreferredMaxBytes: 100,
+			})
+			bigMessage := orgMessage("OrgB", string(make([]byte, 1000)))
+
+			batches, pending := order(a1, bigMessage)
+			Expect(batches).To(Equal([][]*cb.Envelope{{a1}, {bigMessage}}))
+			Expect(pending).To(BeFalse())
+		})
+
+		It("cuts the same batches from the same messages", func() {
+			other := blockcutter.NewReceiverImpl("mychannel", fakeConfigFetcher, metrics)
+			msgs := []*cb.Envelope{a1, b1, a2, a3, b2, a4}
+			for _, msg := range msgs {
+				batches, pending := bc.Ordered(msg)
+				otherBatches, otherPending := other.Ordered(msg)
+				Expect(batches).To(Equal(otherBatches))
+				Expect(pending).To(Equal(otherPending))
+			}
+			Expect(bc.Cut()).To(Equal(other.Cut()))
+		})
+
+		It("discards all the pending messages when drained", func() {
+			order(a1, a2, a3, a4, b1, b2)
+
+			blockcutter.Drain(bc)
+			Expect(bc.Cut()).To(BeEmpty())
+		})
+
+		It("keeps cutting fairly the pending messages once FAIR mode is disabled", func() {
+			order(a1, a2)
+			fakeConfig.BlockCuttingReturns(&ab.BlockCutting{})
+
+			batches, pending := order(b1, a3)
+			Expect(batches).To(Equal([][]*cb.Envelope{{a1, b1}}))
+			Expect(pending).To(BeTrue())
+			Expect(bc.Cut()).To(Equal([]*cb.Envelope{a2, a3}))
+		})
+	})
 })
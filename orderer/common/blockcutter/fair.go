@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// defaultPendingBatches is the number of batches worth of messages held pending
+// in FAIR mode when the channel config does not say otherwise.
+const defaultPendingBatches = 2
+
+// orgQueue holds the pending messages of an organization in the order they were received.
+type orgQueue struct {
+	mspID    string
+	messages []*cb.Envelope
+}
+
+// fairQueue holds the pending messages of the receiver in FAIR mode, grouped by the
+// organization of their creator. Batches are filled from the organizations round-robin,
+// and the organization following the last one served starts the next batch. It only
+// depends on the sequence of messages it is given, so that every orderer which cuts
+// the same messages cuts the same batches.
+type fairQueue struct {
+	orgs      []*orgQueue
+	count     uint64
+	sizeBytes uint64
+}
+
+func (q *fairQueue) enqueue(msg *cb.Envelope) {
+	q.count++
+	q.sizeBytes += uint64(messageSizeBytes(msg))
+
+	mspID := creatorMSPID(msg)
+	for _, org := range q.orgs {
+		if org.mspID == mspID {
+			org.messages = append(org.messages, msg)
+			return
+		}
+	}
+	q.orgs = append(q.orgs, &orgQueue{mspID: mspID, messages: []*cb.Envelope{msg}})
+}
+
+// exceeds returns whether the queue holds more than the given number of batches worth of messages.
+func (q *fairQueue) exceeds(batchSize *ab.BatchSize, pendingBatches uint32) bool {
+	return q.count >= uint64(pendingBatches)*uint64(batchSize.MaxMessageCount) ||
+		q.sizeBytes >= uint64(pendingBatches)*uint64(batchSize.PreferredMaxBytes)
+}
+
+// nextBatch takes the next batch from the queue. In each round, every organization
+// contributes as many messages as its weight, as long as they fit in the batch. A
+// message larger than the preferred max bytes is isolated in its own batch.
+func (q *fairQueue) nextBatch(batchSize *ab.BatchSize, weights map[string]uint32) []*cb.Envelope {
+	var batch []*cb.Envelope
+	var batchSizeBytes uint32
+	lastServed := -1
+
+	for full := false; !full; {
+		progress := false
+		for i := 0; i < len(q.orgs) && !full; i++ {
+			org := q.orgs[i]
+			weight, ok := weights[org.mspID]
+			if !ok {
+				weight = 1
+			}
+			for taken := uint32(0); taken < weight && len(org.messages) > 0; taken++ {
+				msg := org.messages[0]
+				msgSizeBytes := messageSizeBytes(msg)
+				if len(batch) > 0 && batchSizeBytes+msgSizeBytes > batchSize.PreferredMaxBytes {
+					break
+				}
+
+				org.messages = org.messages[1:]
+				batch = append(batch, msg)
+				batchSizeBytes += msgSizeBytes
+				progress = true
+				lastServed = i
+
+				if msgSizeBytes > batchSize.PreferredMaxBytes || uint32(len(batch)) >= batchSize.MaxMessageCount {
+					full = true
+					break
+				}
+			}
+		}
+		if !progress {
+			break
+		}
+	}
+
+	q.count -= uint64(len(batch))
+	q.sizeBytes -= uint64(batchSizeBytes)
+
+	// Rotate the organizations so that the one following the last served starts the next batch
+	var orgs []*orgQueue
+	for i := range q.orgs {
+		if org := q.orgs[(lastServed+1+i)%len(q.orgs)]; len(org.messages) > 0 {
+			orgs = append(orgs, org)
+		}
+	}
+	q.orgs = orgs
+
+	return batch
+}
+
+// creatorMSPID returns the MSP ID of the creator of the message, or an empty string if it is malformed.
+func creatorMSPID(msg *cb.Envelope) string {
+	payload, err := utils.UnmarshalPayload(msg.Payload)
+	if err != nil || payload.Header == nil {
+		return ""
+	}
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return ""
+	}
+	identity := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(shdr.Creator, identity); err != nil {
+		return ""
+	}
+	return identity.Mspid
+}
@@ -30,6 +30,16 @@ type OrdererConfig struct {
 	batchTimeoutReturnsOnCall map[int]struct {
 		result1 time.Duration
 	}
+	BlockCuttingStub        func() *orderer.BlockCutting
+	blockCuttingMutex       sync.RWMutex
+	blockCuttingArgsForCall []struct {
+	}
+	blockCuttingReturns struct {
+		result1 *orderer.BlockCutting
+	}
+	blockCuttingReturnsOnCall map[int]struct {
+		result1 *orderer.BlockCutting
+	}
 	CapabilitiesStub        func() channelconfig.OrdererCapabilities
 	capabilitiesMutex       sync.RWMutex
 	capabilitiesArgsForCall []struct {
@@ -218,6 +228,58 @@ func (fake *OrdererConfig) BatchTimeoutReturnsOnCall(i int, result1 time.Duratio
 	}{result1}
 }
 
+func (fake *OrdererConfig) BlockCutting() *orderer.BlockCutting {
+	fake.blockCuttingMutex.Lock()
+	ret, specificReturn := fake.blockCuttingReturnsOnCall[len(fake.blockCuttingArgsForCall)]
+	fake.blockCuttingArgsForCall = append(fake.blockCuttingArgsForCall, struct {
+	}{})
+	fake.recordInvocation("BlockCutting", []interface{}{})
+	fake.blockCuttingMutex.Unlock()
+	if fake.BlockCuttingStub != nil {
+		return fake.BlockCuttingStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.blockCuttingReturns
+	return fakeReturns.result1
+}
+
+func (fake *OrdererConfig) BlockCuttingCallCount() int {
+	fake.blockCuttingMutex.RLock()
+	defer fake.blockCuttingMutex.RUnlock()
+	return len(fake.blockCuttingArgsForCall)
+}
+
+func (fake *OrdererConfig) BlockCuttingCalls(stub func() *orderer.BlockCutting) {
+	fake.blockCuttingMutex.Lock()
+	defer fake.blockCuttingMutex.Unlock()
+	fake.BlockCuttingStub = stub
+}
+
+func (fake *OrdererConfig) BlockCuttingReturns(result1 *orderer.BlockCutting) {
+	fake.blockCuttingMutex.Lock()
+	defer fake.blockCuttingMutex.Unlock()
+	fake.BlockCuttingStub = nil
+	fake.blockCuttingReturns = struct {
+		result1 *orderer.BlockCutting
+	}{result1}
+}
+
+func (fake *OrdererConfig) BlockCuttingReturnsOnCall(i int, result1 *orderer.BlockCutting) {
+	fake.blockCuttingMutex.Lock()
+	defer fake.blockCuttingMutex.Unlock()
+	fake.BlockCuttingStub = nil
+	if fake.blockCuttingReturnsOnCall == nil {
+		fake.blockCuttingReturnsOnCall = make(map[int]struct {
+			result1 *orderer.BlockCutting
+		})
+	}
+	fake.blockCuttingReturnsOnCall[i] = struct {
+		result1 *orderer.BlockCutting
+	}{result1}
+}
+
 func (fake *OrdererConfig) Capabilities() channelconfig.OrdererCapabilities {
 	fake.capabilitiesMutex.Lock()
 	ret, specificReturn := fake.capabilitiesReturnsOnCall[len(fake.capabilitiesArgsForCall)]
@@ -641,6 +703,8 @@ func (fake *OrdererConfig) Invocations() map[string][][]interface{} {
 	defer fake.batchSizeMutex.RUnlock()
 	fake.batchTimeoutMutex.RLock()
 	defer fake.batchTimeoutMutex.RUnlock()
+	fake.blockCuttingMutex.RLock()
+	defer fake.blockCuttingMutex.RUnlock()
 	fake.capabilitiesMutex.RLock()
 	defer fake.capabilitiesMutex.RUnlock()
 	fake.consensusMetadataMutex.RLock()
@@ -675,3 +739,5 @@ func (fake *OrdererConfig) recordInvocation(key string, args []interface{}) {
 	}
 	fake.invocations[key] = append(fake.invocations[key], args)
 }
+
+var _ channelconfig.Orderer = new(OrdererConfig)
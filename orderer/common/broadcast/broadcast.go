@@ -7,14 +7,19 @@ SPDX-License-Identifier: Apache-2.0
 package broadcast
 
 import (
+	"fmt"
 	"io"
+	"net"
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 )
 
@@ -36,6 +41,10 @@ type ChannelSupportRegistrar interface {
 type ChannelSupport interface {
 	msgprocessor.Processor
 	Consenter
+
+	// MSPManager returns the MSP manager of the channel, which authenticates
+	// the creators of the messages
+	MSPManager() msp.MSPManager
 }
 
 // Consenter provides methods to send messages through consensus
@@ -60,11 +69,14 @@ type Consenter interface {
 type Handler struct {
 	SupportRegistrar ChannelSupportRegistrar
 	Metrics          *Metrics
+	// Throttle, if set, limits the rate of the envelopes admitted from each organization and client
+	Throttle *Throttle
 }
 
 // Handle reads requests from a Broadcast stream, processes them, and returns the responses to the stream
 func (bh *Handler) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 	addr := util.ExtractRemoteAddress(srv.Context())
+	tlsCertHash := comm.ExtractCertificateHashFromContext(srv.Context())
 	logger.Debugf("Starting new broadcast loop for %s", addr)
 	for {
 		msg, err := srv.Recv()
@@ -77,7 +89,7 @@ func (bh *Handler) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 			return err
 		}
 
-		resp := bh.ProcessMessage(msg, addr)
+		resp := bh.processMessage(msg, addr, tlsCertHash)
 		err = srv.Send(resp)
 		if resp.Status != cb.Status_SUCCESS {
 			return err
@@ -134,6 +146,10 @@ func (mt *MetricsTracker) BeginEnqueue() {
 
 // ProcessMessage validates and enqueues a single message
 func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.BroadcastResponse) {
+	return bh.processMessage(msg, addr, nil)
+}
+
+func (bh *Handler) processMessage(msg *cb.Envelope, addr string, tlsCertHash []byte) (resp *ab.BroadcastResponse) {
 	tracker := &MetricsTracker{
 		ChannelID: "unknown",
 		TxType:    "unknown",
@@ -157,6 +173,10 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 		return &ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()}
 	}
 
+	if resp := bh.throttle(msg, chdr, processor, addr, tlsCertHash); resp != nil {
+		return resp
+	}
+
 	if !isConfig {
 		logger.Debugf("[channel: %s] Broadcast is processing normal message from %s with txid '%s' of type %s", chdr.ChannelId, addr, chdr.TxId, cb.HeaderType_name[chdr.Type])
 
@@ -206,6 +226,83 @@ func (bh *Handler) ProcessMessage(msg *cb.Envelope, addr string) (resp *ab.Broad
 	return &ab.BroadcastResponse{Status: cb.Status_SUCCESS}
 }
 
+// throttle returns a SERVICE_UNAVAILABLE response if the message exceeds the limit
+// of its client or organization, and nil otherwise. A client is identified by its
+// TLS certificate hash or, in the absence of one, by its address. The organization
+// is charged only once the creator of the message is authenticated, so that a
+// client cannot spend the budget of another organization by claiming to belong to it.
+func (bh *Handler) throttle(msg *cb.Envelope, chdr *cb.ChannelHeader, processor ChannelSupport, addr string, tlsCertHash []byte) *ab.BroadcastResponse {
+	if bh.Throttle == nil {
+		return nil
+	}
+
+	clientID := string(tlsCertHash)
+	if clientID == "" {
+		clientID = hostOf(addr)
+	}
+	if retryAfter := bh.Throttle.AdmitClient(clientID); retryAfter > 0 {
+		return bh.throttled(chdr, addr, "client", fmt.Sprintf("rate limit of client exceeded, retry after %s", retryAfter))
+	}
+
+	mspID, err := authenticateCreator(msg, processor.MSPManager())
+	if err != nil {
+		// the message is rejected by the processor, its organization is not charged for it
+		logger.Debugf("[channel: %s] Not charging the organization of the creator of the message from %s: %s", chdr.ChannelId, addr, err)
+		return nil
+	}
+	if retryAfter := bh.Throttle.AdmitOrg(mspID, clientID); retryAfter > 0 {
+		return bh.throttled(chdr, addr, "org", fmt.Sprintf("rate limit of organization %s exceeded, retry after %s", mspID, retryAfter))
+	}
+	return nil
+}
+
+func (bh *Handler) throttled(chdr *cb.ChannelHeader, addr string, exceeded string, info string) *ab.BroadcastResponse {
+	bh.Metrics.ThrottledCount.With("channel", chdr.ChannelId, "limit", exceeded).Add(1)
+	logger.Debugf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: %s", chdr.ChannelId, addr, info)
+	return &ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: info}
+}
+
+// hostOf returns the host of the given remote address, so that the connections
+// of a client without a TLS certificate share the same limit.
+func hostOf(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+// authenticateCreator returns the MSP ID of the creator of the message, once the
+// creator is deserialized and validated by the MSPs of the channel, and the
+// signature of the message is verified against it.
+func authenticateCreator(msg *cb.Envelope, mspManager msp.MSPManager) (string, error) {
+	if mspManager == nil {
+		return "", errors.New("no MSP manager for the channel")
+	}
+	payload, err := utils.UnmarshalPayload(msg.Payload)
+	if err != nil {
+		return "", err
+	}
+	if payload.Header == nil {
+		return "", errors.New("missing header")
+	}
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return "", err
+	}
+	identity, err := mspManager.DeserializeIdentity(shdr.Creator)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to deserialize the creator")
+	}
+	if err := identity.Validate(); err != nil {
+		return "", errors.WithMessage(err, "the creator is not valid")
+	}
+	if err := identity.Verify(msg.Payload, msg.Signature); err != nil {
+		return "", errors.WithMessage(err, "the signature of the creator is not valid")
+	}
+	return identity.GetMSPIdentifier(), nil
+}
+
 // ClassifyError converts an error type into a status code.
 func ClassifyError(err error) cb.Status {
 	switch errors.Cause(err) {
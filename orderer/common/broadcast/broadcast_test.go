@@ -15,11 +15,16 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"github.com/hyperledger/fabric/common/util"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/orderer/common/broadcast"
 	"github.com/hyperledger/fabric/orderer/common/broadcast/mock"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
 	ab "github.com/hyperledger/fabric/protos/orderer"
+	protoutil "github.com/hyperledger/fabric/protos/utils"
 )
 
 var _ = Describe("Broadcast", func() {
@@ -169,6 +174,143 @@ var _ = Describe("Broadcast", func() {
 
 		})
 
+		Context("when throttling is enabled", func() {
+			var fakeThrottledCounter *mock.MetricsCounter
+
+			BeforeEach(func() {
+				Expect(msptesttools.LoadMSPSetupForTesting()).To(Succeed())
+				fakeSupport.MSPManagerReturns(mspmgmt.GetManagerForChain(util.GetTestChainID()))
+
+				signer := mspmgmt.GetLocalSigningIdentityOrPanic()
+				creator, err := signer.Serialize()
+				Expect(err).NotTo(HaveOccurred())
+				fakeMsg.Payload = protoutil.MarshalOrPanic(&cb.Payload{
+					Header: &cb.Header{
+						SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{Creator: creator}),
+					},
+				})
+				fakeMsg.Signature, err = signer.Sign(fakeMsg.Payload)
+				Expect(err).NotTo(HaveOccurred())
+				fakeABServer.RecvReturnsOnCall(1, fakeMsg, nil)
+				fakeABServer.RecvReturnsOnCall(2, nil, io.EOF)
+
+				fakeThrottledCounter = &mock.MetricsCounter{}
+				fakeThrottledCounter.WithReturns(fakeThrottledCounter)
+				handler.Metrics.ThrottledCount = fakeThrottledCounter
+				handler.Throttle = broadcast.NewThrottle(broadcast.Limit{Rate: 0.001, Burst: 1}, broadcast.Limit{})
+			})
+
+			It("rejects the messages which exceed the limit with a service unavailable status", func() {
+				err := handler.Handle(fakeABServer)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.OrderCallCount()).To(Equal(1))
+				Expect(fakeSupport.ProcessNormalMsgCallCount()).To(Equal(1))
+
+				Expect(fakeABServer.SendCallCount()).To(Equal(2))
+				Expect(proto.Equal(fakeABServer.SendArgsForCall(0), &ab.BroadcastResponse{Status: cb.Status_SUCCESS})).To(BeTrue())
+				resp := fakeABServer.SendArgsForCall(1)
+				Expect(resp.Status).To(Equal(cb.Status_SERVICE_UNAVAILABLE))
+				Expect(resp.Info).To(MatchRegexp(`^rate limit of organization SampleOrg exceeded, retry after \d+(\.\d+)?m\d+(\.\d+)?s$`))
+
+				Expect(fakeThrottledCounter.WithCallCount()).To(Equal(1))
+				Expect(fakeThrottledCounter.WithArgsForCall(0)).To(Equal([]string{"channel", "fake-channel", "limit", "org"}))
+				Expect(fakeThrottledCounter.AddArgsForCall(0)).To(Equal(float64(1)))
+				Expect(fakeProcessedCounter.WithArgsForCall(1)).To(Equal([]string{
+					"status", "SERVICE_UNAVAILABLE",
+					"channel", "fake-channel",
+					"type", "ENDORSER_TRANSACTION",
+				}))
+			})
+
+			It("rejects the messages which exceed the limit before processing them", func() {
+				fakeSupport.ProcessNormalMsgReturnsOnCall(1, 0, fmt.Errorf("normal-message-processing-error"))
+
+				err := handler.Handle(fakeABServer)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.ProcessNormalMsgCallCount()).To(Equal(1))
+				Expect(fakeABServer.SendCallCount()).To(Equal(2))
+				Expect(fakeABServer.SendArgsForCall(1).Status).To(Equal(cb.Status_SERVICE_UNAVAILABLE))
+			})
+
+			Context("when the signature of the message is not valid", func() {
+				BeforeEach(func() {
+					fakeMsg.Signature = []byte("forged-signature")
+				})
+
+				It("does not charge the organization claimed by the creator", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeSupport.ProcessNormalMsgCallCount()).To(Equal(2))
+					Expect(fakeABServer.SendCallCount()).To(Equal(2))
+					Expect(fakeABServer.SendArgsForCall(1).Status).To(Equal(cb.Status_SUCCESS))
+					Expect(fakeThrottledCounter.WithCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the creator is not a member of the channel", func() {
+				BeforeEach(func() {
+					fakeMsg.Payload = protoutil.MarshalOrPanic(&cb.Payload{
+						Header: &cb.Header{
+							SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{
+								Creator: protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("cert")}),
+							}),
+						},
+					})
+				})
+
+				It("does not charge the organization claimed by the creator", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeSupport.ProcessNormalMsgCallCount()).To(Equal(2))
+					Expect(fakeABServer.SendArgsForCall(1).Status).To(Equal(cb.Status_SUCCESS))
+					Expect(fakeThrottledCounter.WithCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("when the client limit is enabled", func() {
+				BeforeEach(func() {
+					handler.Throttle = broadcast.NewThrottle(broadcast.Limit{}, broadcast.Limit{Rate: 0.001, Burst: 1})
+					fakeMsg.Signature = []byte("forged-signature")
+				})
+
+				It("charges the client for the messages whose creator is not authenticated", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeSupport.ProcessNormalMsgCallCount()).To(Equal(1))
+					Expect(fakeABServer.SendCallCount()).To(Equal(2))
+					resp := fakeABServer.SendArgsForCall(1)
+					Expect(resp.Status).To(Equal(cb.Status_SERVICE_UNAVAILABLE))
+					Expect(resp.Info).To(HavePrefix("rate limit of client exceeded"))
+					Expect(fakeThrottledCounter.WithArgsForCall(0)).To(Equal([]string{"channel", "fake-channel", "limit", "client"}))
+				})
+			})
+
+			Context("when the message is a config update", func() {
+				BeforeEach(func() {
+					fakeSupportRegistrar.BroadcastChannelSupportReturns(&cb.ChannelHeader{
+						Type:      2,
+						ChannelId: "fake-channel",
+					}, true, fakeSupport, nil)
+					fakeSupport.ProcessConfigUpdateMsgReturns(&cb.Envelope{}, 3, nil)
+				})
+
+				It("rejects the config updates which exceed the limit before processing them", func() {
+					err := handler.Handle(fakeABServer)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeSupport.ProcessConfigUpdateMsgCallCount()).To(Equal(1))
+					Expect(fakeSupport.ConfigureCallCount()).To(Equal(1))
+					Expect(fakeABServer.SendCallCount()).To(Equal(2))
+					Expect(fakeABServer.SendArgsForCall(1).Status).To(Equal(cb.Status_SERVICE_UNAVAILABLE))
+				})
+			})
+		})
+
 		Context("when the receive from the client fails", func() {
 			BeforeEach(func() {
 				fakeABServer.RecvReturns(nil, fmt.Errorf("recv-error"))
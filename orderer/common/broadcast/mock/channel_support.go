@@ -4,6 +4,7 @@ package mock
 import (
 	"sync"
 
+	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/broadcast"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -97,6 +98,15 @@ type ChannelSupport struct {
 	waitReadyReturnsOnCall map[int]struct {
 		result1 error
 	}
+	MSPManagerStub        func() msp.MSPManager
+	mSPManagerMutex       sync.RWMutex
+	mSPManagerArgsForCall []struct{}
+	mSPManagerReturns     struct {
+		result1 msp.MSPManager
+	}
+	mSPManagerReturnsOnCall map[int]struct {
+		result1 msp.MSPManager
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -446,6 +456,46 @@ func (fake *ChannelSupport) WaitReadyReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *ChannelSupport) MSPManager() msp.MSPManager {
+	fake.mSPManagerMutex.Lock()
+	ret, specificReturn := fake.mSPManagerReturnsOnCall[len(fake.mSPManagerArgsForCall)]
+	fake.mSPManagerArgsForCall = append(fake.mSPManagerArgsForCall, struct{}{})
+	fake.recordInvocation("MSPManager", []interface{}{})
+	fake.mSPManagerMutex.Unlock()
+	if fake.MSPManagerStub != nil {
+		return fake.MSPManagerStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.mSPManagerReturns.result1
+}
+
+func (fake *ChannelSupport) MSPManagerCallCount() int {
+	fake.mSPManagerMutex.RLock()
+	defer fake.mSPManagerMutex.RUnlock()
+	return len(fake.mSPManagerArgsForCall)
+}
+
+func (fake *ChannelSupport) MSPManagerReturns(result1 msp.MSPManager) {
+	fake.MSPManagerStub = nil
+	fake.mSPManagerReturns = struct {
+		result1 msp.MSPManager
+	}{result1}
+}
+
+func (fake *ChannelSupport) MSPManagerReturnsOnCall(i int, result1 msp.MSPManager) {
+	fake.MSPManagerStub = nil
+	if fake.mSPManagerReturnsOnCall == nil {
+		fake.mSPManagerReturnsOnCall = make(map[int]struct {
+			result1 msp.MSPManager
+		})
+	}
+	fake.mSPManagerReturnsOnCall[i] = struct {
+		result1 msp.MSPManager
+	}{result1}
+}
+
 func (fake *ChannelSupport) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -463,6 +513,8 @@ func (fake *ChannelSupport) Invocations() map[string][][]interface{} {
 	defer fake.configureMutex.RUnlock()
 	fake.waitReadyMutex.RLock()
 	defer fake.waitReadyMutex.RUnlock()
+	fake.mSPManagerMutex.RLock()
+	defer fake.mSPManagerMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// minSweepSize is the number of token buckets above which a limiter starts
+// forgetting the buckets which are full.
+const minSweepSize = 1024
+
+// Limit is the rate, in envelopes per second, at which a Throttle admits the
+// envelopes of an organization or a client, and the number of envelopes it
+// admits in a burst. A limit whose rate is not positive admits all envelopes.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Throttle limits the rate of the envelopes broadcast by each client, as
+// identified by its TLS certificate hash or its address, and by each
+// organization, as identified by the MSP ID of the authenticated creator of
+// the envelope. Each of them is given a token bucket, which an envelope is
+// admitted from only if it holds a token.
+type Throttle struct {
+	orgs    *limiter
+	clients *limiter
+}
+
+// NewThrottle returns a Throttle enforcing the given organization and client limits.
+func NewThrottle(orgLimit, clientLimit Limit) *Throttle {
+	return &Throttle{
+		orgs:    newLimiter(orgLimit),
+		clients: newLimiter(clientLimit),
+	}
+}
+
+// AdmitClient takes a token from the bucket of the given client and returns
+// zero, or returns the time until a token is available again if the bucket is
+// empty.
+func (t *Throttle) AdmitClient(clientID string) (retryAfter time.Duration) {
+	return t.clients.take(clientID, time.Now())
+}
+
+// AdmitOrg takes a token from the bucket of the given organization and returns
+// zero. If the bucket is empty, it gives back the token taken by AdmitClient
+// from the bucket of the given client, and returns the time until a token is
+// available again.
+func (t *Throttle) AdmitOrg(mspID string, clientID string) (retryAfter time.Duration) {
+	if retryAfter := t.orgs.take(mspID, time.Now()); retryAfter > 0 {
+		t.clients.giveBack(clientID)
+		return retryAfter
+	}
+	return 0
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+type limiter struct {
+	limit     Limit
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	sweepSize int
+}
+
+func newLimiter(limit Limit) *limiter {
+	if limit.Rate <= 0 {
+		return nil
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	return &limiter{
+		limit:     limit,
+		buckets:   make(map[string]*tokenBucket),
+		sweepSize: minSweepSize,
+	}
+}
+
+// take takes a token from the bucket of the given key and returns zero,
+// or returns the time until the bucket holds a token if it is empty.
+func (l *limiter) take(key string, now time.Time) time.Duration {
+	if l == nil {
+		return 0
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	bucket, exists := l.buckets[key]
+	if !exists {
+		l.sweep(now)
+		bucket = &tokenBucket{tokens: float64(l.limit.Burst), last: now}
+		l.buckets[key] = bucket
+	}
+	l.refill(bucket, now)
+
+	if bucket.tokens < 1 {
+		missing := (1 - bucket.tokens) / l.limit.Rate
+		return time.Duration(math.Ceil(missing * float64(time.Second)))
+	}
+	bucket.tokens--
+	return 0
+}
+
+// giveBack returns a token taken from the bucket of the given key.
+func (l *limiter) giveBack(key string) {
+	if l == nil {
+		return
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if bucket, exists := l.buckets[key]; exists {
+		bucket.tokens = math.Min(bucket.tokens+1, float64(l.limit.Burst))
+	}
+}
+
+func (l *limiter) refill(bucket *tokenBucket, now time.Time) {
+	elapsed := now.Sub(bucket.last).Seconds()
+	if elapsed <= 0 {
+		return
+	}
+	bucket.tokens = math.Min(bucket.tokens+elapsed*l.limit.Rate, float64(l.limit.Burst))
+	bucket.last = now
+}
+
+// sweep forgets the buckets which are full, as they are no different from new
+// ones, once there are too many of them. Keys are taken from clients which
+// are not authenticated, so they must not be remembered for good.
+func (l *limiter) sweep(now time.Time) {
+	if len(l.buckets) < l.sweepSize {
+		return
+	}
+	for key, bucket := range l.buckets {
+		l.refill(bucket, now)
+		if bucket.tokens >= float64(l.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+	l.sweepSize = 2 * len(l.buckets)
+	if l.sweepSize < minSweepSize {
+		l.sweepSize = minSweepSize
+	}
+}
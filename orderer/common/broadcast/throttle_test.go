@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package broadcast_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/hyperledger/fabric/orderer/common/broadcast"
+)
+
+var _ = Describe("Throttle", func() {
+	admit := func(throttle *broadcast.Throttle, mspID, clientID string) (exceeded string, retryAfter time.Duration) {
+		if retryAfter := throttle.AdmitClient(clientID); retryAfter > 0 {
+			return "client", retryAfter
+		}
+		if retryAfter := throttle.AdmitOrg(mspID, clientID); retryAfter > 0 {
+			return "org", retryAfter
+		}
+		return "", 0
+	}
+
+	admitted := func(throttle *broadcast.Throttle, mspID, clientID string, count int) int {
+		var n int
+		for i := 0; i < count; i++ {
+			if exceeded, _ := admit(throttle, mspID, clientID); exceeded == "" {
+				n++
+			}
+		}
+		return n
+	}
+
+	It("admits everything when no limit is enabled", func() {
+		throttle := broadcast.NewThrottle(broadcast.Limit{}, broadcast.Limit{Burst: 1})
+		Expect(admitted(throttle, "Org1MSP", "client1", 100)).To(Equal(100))
+	})
+
+	It("limits each organization to its burst", func() {
+		throttle := broadcast.NewThrottle(broadcast.Limit{Rate: 0.001, Burst: 3}, broadcast.Limit{})
+		Expect(admitted(throttle, "Org1MSP", "client1", 2)).To(Equal(2))
+		Expect(admitted(throttle, "Org1MSP", "client2", 2)).To(Equal(1))
+		Expect(admitted(throttle, "Org2MSP", "client3", 5)).To(Equal(3))
+
+		exceeded, retryAfter := admit(throttle, "Org1MSP", "client1")
+		Expect(exceeded).To(Equal("org"))
+		Expect(retryAfter).To(BeNumerically("~", 1000*time.Second, time.Second))
+	})
+
+	It("limits each client to its burst", func() {
+		throttle := broadcast.NewThrottle(broadcast.Limit{}, broadcast.Limit{Rate: 0.001, Burst: 2})
+		Expect(admitted(throttle, "Org1MSP", "client1", 5)).To(Equal(2))
+		Expect(admitted(throttle, "Org1MSP", "client2", 5)).To(Equal(2))
+
+		exceeded, _ := admit(throttle, "Org1MSP", "client1")
+		Expect(exceeded).To(Equal("client"))
+	})
+
+	It("does not charge a client for the messages its organization rejects", func() {
+		throttle := broadcast.NewThrottle(broadcast.Limit{Rate: 0.001, Burst: 1}, broadcast.Limit{Rate: 0.001, Burst: 1})
+		Expect(admitted(throttle, "Org1MSP", "client1", 1)).To(Equal(1))
+
+		exceeded, _ := admit(throttle, "Org1MSP", "client2")
+		Expect(exceeded).To(Equal("org"))
+		exceeded, _ = admit(throttle, "Org2MSP", "client2")
+		Expect(exceeded).To(Equal(""))
+	})
+
+	It("refills the buckets at the rate of the limit", func() {
+		throttle := broadcast.NewThrottle(broadcast.Limit{Rate: 100, Burst: 1}, broadcast.Limit{})
+		Expect(admitted(throttle, "Org1MSP", "client1", 2)).To(Equal(1))
+		Eventually(func() string {
+			exceeded, _ := admit(throttle, "Org1MSP", "client1")
+			return exceeded
+		}).Should(BeEmpty())
+	})
+
+	It("forgets the buckets which are full", func() {
+		throttle := broadcast.NewThrottle(broadcast.Limit{}, broadcast.Limit{Rate: 1000, Burst: 1})
+		for i := 0; i < 5000; i++ {
+			admit(throttle, "Org1MSP", string(rune(i)))
+		}
+		Expect(admitted(throttle, "Org1MSP", string(rune(0)), 1)).To(Equal(1))
+	})
+})
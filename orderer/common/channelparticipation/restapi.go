@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+const (
+	// URLBaseV1 is the path under which the HTTPHandler is served.
+	URLBaseV1 = "/participation/v1/"
+	// URLBaseV1Channels is the path of the channels of the orderer.
+	URLBaseV1Channels = URLBaseV1 + "channels"
+)
+
+//go:generate counterfeiter -o mocks/channel_management.go -fake-name ChannelManagement . ChannelManagement
+
+// ChannelManagement joins, lists and removes the channels of an orderer.
+type ChannelManagement interface {
+	ChannelList() types.ChannelList
+	ChannelInfo(channelID string) (types.ChannelInfo, error)
+	JoinChannel(configBlock *cb.Block) (types.ChannelInfo, error)
+	RemoveChannel(channelID string) error
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// HTTPHandler serves the channel participation API:
+//
+//	GET    /participation/v1/channels          returns the ChannelList of the orderer
+//	POST   /participation/v1/channels          joins the channel of the config block in the body
+//	GET    /participation/v1/channels/<name>   returns the ChannelInfo of the channel
+//	DELETE /participation/v1/channels/<name>   removes the channel
+type HTTPHandler struct {
+	config    localconfig.ChannelParticipation
+	registrar ChannelManagement
+	logger    *flogging.FabricLogger
+}
+
+// NewHTTPHandler returns an HTTPHandler which manages the channels of the given registrar.
+func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelManagement) *HTTPHandler {
+	return &HTTPHandler{
+		config:    config,
+		registrar: registrar,
+		logger:    flogging.MustGetLogger("orderer.common.channelparticipation"),
+	}
+}
+
+func (h *HTTPHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	reqPath := strings.TrimSuffix(req.URL.Path, "/")
+	switch {
+	case reqPath == URLBaseV1Channels:
+		switch req.Method {
+		case http.MethodGet:
+			h.serveListChannels(resp)
+		case http.MethodPost:
+			h.serveJoinChannel(resp, req)
+		default:
+			h.sendNotAllowed(resp, req, http.MethodGet, http.MethodPost)
+		}
+
+	case path.Dir(reqPath) == URLBaseV1Channels:
+		channelID := path.Base(reqPath)
+		switch req.Method {
+		case http.MethodGet:
+			h.serveChannelInfo(resp, channelID)
+		case http.MethodDelete:
+			h.serveRemoveChannel(resp, channelID)
+		default:
+			h.sendNotAllowed(resp, req, http.MethodGet, http.MethodDelete)
+		}
+
+	default:
+		h.sendResponse(resp, http.StatusNotFound, errors.Errorf("invalid path: %s", req.URL.Path))
+	}
+}
+
+func (h *HTTPHandler) serveListChannels(resp http.ResponseWriter) {
+	list := h.registrar.ChannelList()
+	if list.SystemChannel != nil {
+		list.SystemChannel.URL = channelURL(list.SystemChannel.Name)
+	}
+	for i := range list.Channels {
+		list.Channels[i].URL = channelURL(list.Channels[i].Name)
+	}
+	h.sendResponse(resp, http.StatusOK, list)
+}
+
+func (h *HTTPHandler) serveChannelInfo(resp http.ResponseWriter, channelID string) {
+	info, err := h.registrar.ChannelInfo(channelID)
+	if err != nil {
+		h.sendResponse(resp, statusCode(err, http.StatusInternalServerError), err)
+		return
+	}
+	info.URL = channelURL(info.Name)
+	h.sendResponse(resp, http.StatusOK, info)
+}
+
+func (h *HTTPHandler) serveJoinChannel(resp http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(resp, req.Body, int64(h.config.MaxRequestBodySize)))
+	if err != nil {
+		h.sendResponse(resp, http.StatusBadRequest, errors.Wrap(err, "cannot read request body"))
+		return
+	}
+	configBlock := &cb.Block{}
+	if err := proto.Unmarshal(body, configBlock); err != nil {
+		h.sendResponse(resp, http.StatusBadRequest, errors.Wrap(err, "cannot unmarshal config block"))
+		return
+	}
+
+	info, err := h.registrar.JoinChannel(configBlock)
+	if err != nil {
+		h.sendResponse(resp, statusCode(err, http.StatusBadRequest), errors.WithMessage(err, "cannot join"))
+		return
+	}
+	info.URL = channelURL(info.Name)
+	resp.Header().Set("Location", info.URL)
+	h.sendResponse(resp, http.StatusCreated, info)
+}
+
+func (h *HTTPHandler) serveRemoveChannel(resp http.ResponseWriter, channelID string) {
+	if err := h.registrar.RemoveChannel(channelID); err != nil {
+		h.sendResponse(resp, statusCode(err, http.StatusInternalServerError), errors.WithMessage(err, "cannot remove"))
+		return
+	}
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) sendNotAllowed(resp http.ResponseWriter, req *http.Request, allowedMethods ...string) {
+	resp.Header().Set("Allow", strings.Join(allowedMethods, ", "))
+	h.sendResponse(resp, http.StatusMethodNotAllowed, errors.Errorf("invalid request method: %s", req.Method))
+}
+
+func (h *HTTPHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	if err, ok := payload.(error); ok {
+		payload = &errorResponse{Error: err.Error()}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		h.logger.Errorw("failed to encode payload", "error", err)
+	}
+}
+
+func channelURL(channelID string) string {
+	return path.Join(URLBaseV1Channels, channelID)
+}
+
+// statusCode returns the status code of the given error of the registrar,
+// or the given default code if the error is not a known one.
+func statusCode(err error, defaultCode int) int {
+	switch errors.Cause(err) {
+	case types.ErrChannelNotExist:
+		return http.StatusNotFound
+	case types.ErrChannelAlreadyExists, types.ErrChannelOnboarding:
+		return http.StatusConflict
+	case types.ErrSystemChannelExists, types.ErrChannelParticipationDisabled:
+		return http.StatusMethodNotAllowed
+	default:
+		return defaultCode
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation/mocks"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHandler(registrar *mocks.ChannelManagement) *channelparticipation.HTTPHandler {
+	config := localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024}
+	return channelparticipation.NewHTTPHandler(config, registrar)
+}
+
+func serve(handler http.Handler, method, path string, body []byte) *httptest.ResponseRecorder {
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(method, path, bytes.NewReader(body)))
+	return resp
+}
+
+func assertError(t *testing.T, resp *httptest.ResponseRecorder, code int, msg string) {
+	assert.Equal(t, code, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	errResp := map[string]string{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errResp))
+	assert.Equal(t, map[string]string{"error": msg}, errResp)
+}
+
+func TestListChannels(t *testing.T) {
+	registrar := &mocks.ChannelManagement{}
+	registrar.ChannelListReturns(types.ChannelList{
+		Channels: []types.ChannelInfoShort{{Name: "app1"}, {Name: "app2"}},
+	})
+
+	resp := serve(newHandler(registrar), http.MethodGet, "/participation/v1/channels", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"systemChannel": null,
+		"channels": [
+			{"name": "app1", "url": "/participation/v1/channels/app1"},
+			{"name": "app2", "url": "/participation/v1/channels/app2"}
+		]
+	}`, resp.Body.String())
+
+	registrar.ChannelListReturns(types.ChannelList{SystemChannel: &types.ChannelInfoShort{Name: "system"}})
+	resp = serve(newHandler(registrar), http.MethodGet, "/participation/v1/channels/", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{
+		"systemChannel": {"name": "system", "url": "/participation/v1/channels/system"},
+		"channels": null
+	}`, resp.Body.String())
+}
+
+func TestChannelInfo(t *testing.T) {
+	registrar := &mocks.ChannelManagement{}
+	registrar.ChannelInfoReturns(types.ChannelInfo{Name: "app1", Status: types.StatusActive, Height: 5}, nil)
+
+	resp := serve(newHandler(registrar), http.MethodGet, "/participation/v1/channels/app1", nil)
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{"name": "app1", "url": "/participation/v1/channels/app1", "status": "active", "height": 5}`, resp.Body.String())
+	assert.Equal(t, "app1", registrar.ChannelInfoArgsForCall(0))
+
+	registrar.ChannelInfoReturns(types.ChannelInfo{}, types.ErrChannelNotExist)
+	resp = serve(newHandler(registrar), http.MethodGet, "/participation/v1/channels/app2", nil)
+	assertError(t, resp, http.StatusNotFound, "channel does not exist")
+}
+
+func TestJoinChannel(t *testing.T) {
+	block := utils.MarshalOrPanic(cb.NewBlock(0, nil))
+
+	t.Run("joined", func(t *testing.T) {
+		registrar := &mocks.ChannelManagement{}
+		registrar.JoinChannelReturns(types.ChannelInfo{Name: "app1", Status: types.StatusActive, Height: 1}, nil)
+
+		resp := serve(newHandler(registrar), http.MethodPost, "/participation/v1/channels", block)
+		assert.Equal(t, http.StatusCreated, resp.Code)
+		assert.Equal(t, "/participation/v1/channels/app1", resp.Header().Get("Location"))
+		assert.JSONEq(t, `{"name": "app1", "url": "/participation/v1/channels/app1", "status": "active", "height": 1}`, resp.Body.String())
+		assert.True(t, proto.Equal(cb.NewBlock(0, nil), registrar.JoinChannelArgsForCall(0)))
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		registrar := &mocks.ChannelManagement{}
+		resp := serve(newHandler(registrar), http.MethodPost, "/participation/v1/channels", make([]byte, 1025))
+		assertError(t, resp, http.StatusBadRequest, "cannot read request body: http: request body too large")
+		assert.Zero(t, registrar.JoinChannelCallCount())
+	})
+
+	t.Run("not a block", func(t *testing.T) {
+		registrar := &mocks.ChannelManagement{}
+		resp := serve(newHandler(registrar), http.MethodPost, "/participation/v1/channels", []byte("garbage"))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, resp.Body.String(), "cannot unmarshal config block")
+		assert.Zero(t, registrar.JoinChannelCallCount())
+	})
+
+	for _, tc := range []struct {
+		err  error
+		code int
+		msg  string
+	}{
+		{err: errors.New("invalid join block: block is empty"), code: http.StatusBadRequest, msg: "cannot join: invalid join block: block is empty"},
+		{err: types.ErrChannelAlreadyExists, code: http.StatusConflict, msg: "cannot join: channel already exists"},
+		{err: types.ErrSystemChannelExists, code: http.StatusMethodNotAllowed, msg: "cannot join: system channel exists"},
+	} {
+		t.Run(tc.msg, func(t *testing.T) {
+			registrar := &mocks.ChannelManagement{}
+			registrar.JoinChannelReturns(types.ChannelInfo{}, tc.err)
+			resp := serve(newHandler(registrar), http.MethodPost, "/participation/v1/channels", block)
+			assertError(t, resp, tc.code, tc.msg)
+		})
+	}
+}
+
+func TestRemoveChannel(t *testing.T) {
+	registrar := &mocks.ChannelManagement{}
+	resp := serve(newHandler(registrar), http.MethodDelete, "/participation/v1/channels/app1", nil)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "app1", registrar.RemoveChannelArgsForCall(0))
+
+	for _, tc := range []struct {
+		err  error
+		code int
+		msg  string
+	}{
+		{err: types.ErrChannelNotExist, code: http.StatusNotFound, msg: "cannot remove: channel does not exist"},
+		{err: types.ErrChannelOnboarding, code: http.StatusConflict, msg: "cannot remove: channel is being onboarded"},
+		{err: errors.New("failed removing the ledger: disk error"), code: http.StatusInternalServerError, msg: "cannot remove: failed removing the ledger: disk error"},
+	} {
+		t.Run(tc.msg, func(t *testing.T) {
+			registrar.RemoveChannelReturns(tc.err)
+			resp := serve(newHandler(registrar), http.MethodDelete, "/participation/v1/channels/app1", nil)
+			assertError(t, resp, tc.code, tc.msg)
+		})
+	}
+}
+
+func TestInvalidRequests(t *testing.T) {
+	handler := newHandler(&mocks.ChannelManagement{})
+
+	resp := serve(handler, http.MethodPut, "/participation/v1/channels", nil)
+	assertError(t, resp, http.StatusMethodNotAllowed, "invalid request method: PUT")
+	assert.Equal(t, "GET, POST", resp.Header().Get("Allow"))
+
+	resp = serve(handler, http.MethodPost, "/participation/v1/channels/app1", nil)
+	assertError(t, resp, http.StatusMethodNotAllowed, "invalid request method: POST")
+	assert.Equal(t, "GET, DELETE", resp.Header().Get("Allow"))
+
+	for _, path := range []string{"/participation/v1/", "/participation/v1/peers", "/participation/v1/channels/app1/height"} {
+		resp = serve(handler, http.MethodGet, path, nil)
+		assertError(t, resp, http.StatusNotFound, "invalid path: "+path)
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -90,6 +92,9 @@ type Comm struct {
 	Connections  *ConnectionStore
 	Chan2Members MembersByChannel
 	RPCTimeout   time.Duration
+	// Metrics are used to report the messages sent to remote nodes.
+	// Metrics are not reported if nil.
+	Metrics *Metrics
 }
 
 type requestContext struct {
@@ -170,7 +175,7 @@ func (c *Comm) Remote(channel string, id uint64) (*RemoteContext, error) {
 		return stub.RemoteContext, nil
 	}
 
-	err := stub.Activate(c.createRemoteContext(stub))
+	err := stub.Activate(c.createRemoteContext(stub, channel))
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -239,7 +244,7 @@ func (c *Comm) applyMembershipConfig(channel string, newNodes []RemoteNode) {
 
 	for _, node := range newNodes {
 		newNodeIDs[node.ID] = struct{}{}
-		c.updateStubInMapping(mapping, node)
+		c.updateStubInMapping(channel, mapping, node)
 	}
 
 	// Remove all stubs without a corresponding node
@@ -256,7 +261,7 @@ func (c *Comm) applyMembershipConfig(channel string, newNodes []RemoteNode) {
 }
 
 // updateStubInMapping updates the given RemoteNode and adds it to the MemberMapping
-func (c *Comm) updateStubInMapping(mapping MemberMapping, node RemoteNode) {
+func (c *Comm) updateStubInMapping(channel string, mapping MemberMapping, node RemoteNode) {
 	stub := mapping.ByID(node.ID)
 	if stub == nil {
 		c.Logger.Info("Allocating a new stub for node", node.ID, "with endpoint of", node.Endpoint)
@@ -283,13 +288,13 @@ func (c *Comm) updateStubInMapping(mapping MemberMapping, node RemoteNode) {
 	}
 
 	// Activate the stub
-	stub.Activate(c.createRemoteContext(stub))
+	stub.Activate(c.createRemoteContext(stub, channel))
 }
 
 // createRemoteStub returns a function that creates a RemoteContext.
 // It is used as a parameter to Stub.Activate() in order to activate
 // a stub atomically.
-func (c *Comm) createRemoteContext(stub *Stub) func() (*RemoteContext, error) {
+func (c *Comm) createRemoteContext(stub *Stub, channel string) func() (*RemoteContext, error) {
 	return func() (*RemoteContext, error) {
 		timeout := c.RPCTimeout
 		if timeout == time.Duration(0) {
@@ -315,10 +320,13 @@ func (c *Comm) createRemoteContext(stub *Stub) func() (*RemoteContext, error) {
 		clusterClient := orderer.NewClusterClient(conn)
 
 		rc := &RemoteContext{
-			ProbeConn:  probeConnection,
-			conn:       conn,
-			RPCTimeout: timeout,
-			Client:     clusterClient,
+			Channel:     channel,
+			Destination: stub.ID,
+			Metrics:     c.Metrics,
+			ProbeConn:   probeConnection,
+			conn:        conn,
+			RPCTimeout:  timeout,
+			Client:      clusterClient,
 			onAbort: func() {
 				c.Logger.Info("Aborted connection to", stub.ID, stub.Endpoint)
 				stub.RemoteContext = nil
@@ -398,6 +406,10 @@ func (stub *Stub) Activate(createRemoteContext func() (*RemoteContext, error)) e
 // RemoteContext interacts with remote cluster
 // nodes. Every call can be aborted via call to Abort()
 type RemoteContext struct {
+	Channel            string
+	Destination        uint64
+	Metrics            *Metrics
+	inFlight           int32
 	RPCTimeout         time.Duration
 	onAbort            func()
 	Client             orderer.ClusterClient
@@ -423,11 +435,23 @@ func (rc *RemoteContext) SubmitStream() (orderer.Cluster_SubmitClient, error) {
 		cancel()
 		return nil, errors.WithStack(err)
 	}
-	rc.submitStream = submitStream
+	rc.submitStream = &meteredSubmitStream{Cluster_SubmitClient: submitStream, rc: rc}
 	rc.cancelSubmitStream = cancel
 	return rc.submitStream, nil
 }
 
+// meteredSubmitStream reports the requests sent through a Submit stream.
+type meteredSubmitStream struct {
+	orderer.Cluster_SubmitClient
+	rc *RemoteContext
+}
+
+// Send sends the given request to the remote node.
+func (s *meteredSubmitStream) Send(request *orderer.SubmitRequest) error {
+	defer s.rc.reportSend()()
+	return s.Cluster_SubmitClient.Send(request)
+}
+
 // Step passes an implementation-specific message to another cluster member.
 func (rc *RemoteContext) Step(req *orderer.StepRequest) (*orderer.StepResponse, error) {
 	if err := rc.ProbeConn(rc.conn); err != nil {
@@ -441,9 +465,35 @@ func (rc *RemoteContext) Step(req *orderer.StepRequest) (*orderer.StepResponse,
 	rc.cancelStep = abort
 	rc.stepLock.Unlock()
 
+	defer rc.reportSend()()
 	return rc.Client.Step(ctx, req)
 }
 
+// reportSend reports a message being sent to the remote node,
+// and returns a function which reports that the sending is over.
+func (rc *RemoteContext) reportSend() func() {
+	if rc.Metrics == nil {
+		return func() {}
+	}
+
+	labels := []string{"channel", rc.Channel, "destination", strconv.FormatUint(rc.Destination, 10)}
+	queueLength := rc.Metrics.EgressQueueLength.With(labels...)
+	queueLength.Set(float64(atomic.AddInt32(&rc.inFlight, 1)))
+	start := time.Now()
+	return func() {
+		queueLength.Set(float64(atomic.AddInt32(&rc.inFlight, -1)))
+		rc.Metrics.MessageSendTime.With(labels...).Observe(time.Since(start).Seconds())
+	}
+}
+
+// ConnectionState returns the state of the connection to the remote node.
+func (rc *RemoteContext) ConnectionState() connectivity.State {
+	if rc.conn == nil {
+		return connectivity.Shutdown
+	}
+	return rc.conn.GetState()
+}
+
 // Abort aborts the contexts the RemoteContext uses,
 // thus effectively causes all operations on the embedded
 // ClusterClient to end.
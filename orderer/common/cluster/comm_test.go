@@ -19,6 +19,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	comm_utils "github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/cluster/mocks"
@@ -244,6 +245,52 @@ func TestBasic(t *testing.T) {
 	assertBiDiCommunication(t, node1, node2, testStepReq)
 }
 
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+	// Scenario: A node sends a message to another node,
+	// and reports the time it took and the number of messages in flight
+
+	node1 := newTestNode(t)
+	node2 := newTestNode(t)
+
+	defer node1.stop()
+	defer node2.stop()
+
+	queueLength := &metricsfakes.Gauge{}
+	queueLength.WithReturns(queueLength)
+	sendTime := &metricsfakes.Histogram{}
+	sendTime.WithReturns(sendTime)
+	node1.c.Metrics = &cluster.Metrics{EgressQueueLength: queueLength, MessageSendTime: sendTime}
+
+	node2.handler.On("OnStep", testChannel, node1.nodeInfo.ID, mock.Anything).Return(testStepRes, nil)
+
+	config := []cluster.RemoteNode{node1.nodeInfo, node2.nodeInfo}
+	node1.c.Configure(testChannel, config)
+	node2.c.Configure(testChannel, config)
+
+	remote, err := node1.c.Remote(testChannel, node2.nodeInfo.ID)
+	assert.NoError(t, err)
+	assertEventuallyConnect(t, remote, testStepReq)
+	sent := sendTime.ObserveCallCount()
+	assert.NotZero(t, sent)
+
+	node2.handler.On("OnSubmit", testChannel, node1.nodeInfo.ID, mock.Anything).Return(&orderer.SubmitResponse{}, nil).Once()
+	stream, err := remote.SubmitStream()
+	assert.NoError(t, err)
+	assert.NoError(t, stream.Send(testSubReq))
+	_, err = stream.Recv()
+	assert.NoError(t, err)
+
+	destination := []string{"channel", testChannel, "destination", fmt.Sprintf("%d", node2.nodeInfo.ID)}
+	assert.Equal(t, destination, queueLength.WithArgsForCall(0))
+	assert.Equal(t, destination, sendTime.WithArgsForCall(0))
+	assert.Equal(t, sent+1, sendTime.ObserveCallCount())
+	// Every message sent raises the queue length, and then lowers it back
+	assert.Equal(t, 2*(sent+1), queueLength.SetCallCount())
+	assert.Equal(t, float64(1), queueLength.SetArgsForCall(0))
+	assert.Equal(t, float64(0), queueLength.SetArgsForCall(queueLength.SetCallCount()-1))
+}
+
 func TestUnavailableHosts(t *testing.T) {
 	t.Parallel()
 	// Scenario: A node is configured to connect
@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cluster
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+var (
+	egressQueueLengthOpts = metrics.GaugeOpts{
+		Namespace:    "cluster",
+		Subsystem:    "comm",
+		Name:         "egress_queue_length",
+		Help:         "The number of messages being sent to a remote node.",
+		LabelNames:   []string{"channel", "destination"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{destination}",
+	}
+	msgSendTimeOpts = metrics.HistogramOpts{
+		Namespace:    "cluster",
+		Subsystem:    "comm",
+		Name:         "msg_send_time",
+		Help:         "The time it takes to send a message to a remote node in seconds.",
+		LabelNames:   []string{"channel", "destination"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{destination}",
+	}
+)
+
+type Metrics struct {
+	EgressQueueLength metrics.Gauge
+	MessageSendTime   metrics.Histogram
+}
+
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		EgressQueueLength: p.NewGauge(egressQueueLengthOpts),
+		MessageSendTime:   p.NewHistogram(msgSendTimeOpts),
+	}
+}
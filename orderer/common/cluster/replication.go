@@ -229,6 +229,36 @@ func (r *Replicator) pullChannelBlocks(channel string, puller ChainPuller, lates
 	return nil
 }
 
+// PullChannelUntil pulls the blocks of the puller's channel which precede the given block, and commits
+// them to the given empty ledger along with the given block, after verifying that they lead to it.
+func PullChannelUntil(puller ChainPuller, ledger LedgerWriter, block *common.Block) error {
+	if height := ledger.Height(); height != 0 {
+		return errors.Errorf("ledger is not empty, its height is %d", height)
+	}
+
+	var actualPrevHash []byte
+	for seq := uint64(0); seq < block.Header.Number; seq++ {
+		pulledBlock := puller.PullBlock(seq)
+		if pulledBlock == nil {
+			return ErrRetryCountExhausted
+		}
+		if seq > 0 && !bytes.Equal(pulledBlock.Header.PreviousHash, actualPrevHash) {
+			return errors.Errorf("block header mismatch on sequence %d, expected %x, got %x",
+				seq, actualPrevHash, pulledBlock.Header.PreviousHash)
+		}
+		actualPrevHash = pulledBlock.Header.Hash()
+		if err := ledger.Append(pulledBlock); err != nil {
+			return errors.Wrapf(err, "failed committing block %d", seq)
+		}
+	}
+
+	if !bytes.Equal(block.Header.PreviousHash, actualPrevHash) {
+		return errors.Errorf("block header mismatch on sequence %d, expected %x, got %x",
+			block.Header.Number, actualPrevHash, block.Header.PreviousHash)
+	}
+	return errors.Wrapf(ledger.Append(block), "failed committing block %d", block.Header.Number)
+}
+
 func (r *Replicator) appendBlockIfNeeded(block *common.Block, ledger LedgerWriter, channel string) {
 	currHeight := ledger.Height()
 	if currHeight >= block.Header.Number+1 {
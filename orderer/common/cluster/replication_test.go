@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package cluster_test
 
 import (
+	"fmt"
 	"io/ioutil"
 	"path/filepath"
 	"testing"
@@ -348,6 +349,70 @@ func TestPullChannelFailure(t *testing.T) {
 
 }
 
+func TestPullChannelUntil(t *testing.T) {
+	blockchain := createBlockChain(0, 5)
+
+	newPuller := func(blocks ...*common.Block) *mocks.ChainPuller {
+		puller := &mocks.ChainPuller{}
+		for _, block := range blocks {
+			puller.On("PullBlock", block.Header.Number).Return(block)
+		}
+		puller.On("PullBlock", mock.Anything).Return(nil)
+		return puller
+	}
+
+	t.Run("green path", func(t *testing.T) {
+		var committed []uint64
+		lw := &mocks.LedgerWriter{}
+		lw.On("Height").Return(uint64(0))
+		lw.On("Append", mock.Anything).Return(nil).Run(func(arg mock.Arguments) {
+			committed = append(committed, arg.Get(0).(*common.Block).Header.Number)
+		})
+
+		err := cluster.PullChannelUntil(newPuller(blockchain[:5]...), lw, blockchain[5])
+		assert.NoError(t, err)
+		assert.Equal(t, []uint64{0, 1, 2, 3, 4, 5}, committed)
+	})
+
+	t.Run("ledger not empty", func(t *testing.T) {
+		lw := &mocks.LedgerWriter{}
+		lw.On("Height").Return(uint64(2))
+
+		err := cluster.PullChannelUntil(newPuller(blockchain[:5]...), lw, blockchain[5])
+		assert.EqualError(t, err, "ledger is not empty, its height is 2")
+	})
+
+	t.Run("block not pulled", func(t *testing.T) {
+		lw := &mocks.LedgerWriter{}
+		lw.On("Height").Return(uint64(0))
+		lw.On("Append", mock.Anything).Return(nil)
+
+		err := cluster.PullChannelUntil(newPuller(blockchain[:3]...), lw, blockchain[5])
+		assert.Equal(t, cluster.ErrRetryCountExhausted, err)
+	})
+
+	t.Run("pulled blocks do not lead to the block", func(t *testing.T) {
+		lw := &mocks.LedgerWriter{}
+		lw.On("Height").Return(uint64(0))
+		lw.On("Append", mock.Anything).Return(nil)
+
+		forged := proto.Clone(blockchain[5]).(*common.Block)
+		forged.Header.PreviousHash = []byte{1, 2, 3}
+		err := cluster.PullChannelUntil(newPuller(blockchain[:5]...), lw, forged)
+		assert.EqualError(t, err, fmt.Sprintf("block header mismatch on sequence 5, expected %x, got 010203",
+			blockchain[4].Header.Hash()))
+	})
+
+	t.Run("commit failure", func(t *testing.T) {
+		lw := &mocks.LedgerWriter{}
+		lw.On("Height").Return(uint64(0))
+		lw.On("Append", mock.Anything).Return(errors.New("disk full"))
+
+		err := cluster.PullChannelUntil(newPuller(blockchain[:5]...), lw, blockchain[5])
+		assert.EqualError(t, err, "failed committing block 0: disk full")
+	})
+}
+
 func TestPullerConfigFromTopLevelConfig(t *testing.T) {
 	signer := &crypto.LocalSigner{}
 	expected := cluster.PullerConfig{
@@ -5,6 +5,7 @@ package localconfig
 
 import (
 	"fmt"
+	"math"
 	"path/filepath"
 	"strings"
 	"time"
@@ -36,6 +37,8 @@ type TopLevel struct {
 	Consensus  interface{}
 	Operations Operations
 	Metrics    Metrics
+
+	ChannelParticipation ChannelParticipation
 }
 
 // General contains config which should be common among all orderer types.
@@ -55,6 +58,8 @@ type General struct {
 	LocalMSPID     string
 	BCCSP          *bccsp.FactoryOpts
 	Authentication Authentication
+	Throttling     Throttling
+	Encryption     Encryption
 }
 
 type Cluster struct {
@@ -104,6 +109,27 @@ type Authentication struct {
 	TimeWindow time.Duration
 }
 
+// Throttling contains configuration for limiting the rate of the transactions
+// broadcast by each organization and each client.
+type Throttling struct {
+	Org    RateLimit
+	Client RateLimit
+}
+
+// RateLimit contains the rate, in transactions per second, and the burst of a
+// limit. A limit whose rate is zero is disabled.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// Encryption contains configuration for the encryption at rest of the blocks
+// and of the Raft WAL and snapshots, with a key of the BCCSP keystore.
+type Encryption struct {
+	Enabled bool
+	Key     string
+}
+
 // Profile contains configuration for Go pprof profiling.
 type Profile struct {
 	Enabled bool
@@ -112,8 +138,16 @@ type Profile struct {
 
 // FileLedger contains configuration for the file-based ledger.
 type FileLedger struct {
-	Location string
-	Prefix   string
+	Location    string
+	Prefix      string
+	Compression FileLedgerCompression
+}
+
+// FileLedgerCompression contains configuration for the compression of the blocks
+// appended to the block files, by default and for specific channels.
+type FileLedgerCompression struct {
+	Default  string
+	Channels map[string]string
 }
 
 // RAMLedger contains configuration for the RAM ledger.
@@ -197,6 +231,12 @@ type Metrics struct {
 	Statsd   Statsd
 }
 
+// ChannelParticipation configures the channel participation API of the orderer.
+type ChannelParticipation struct {
+	Enabled            bool
+	MaxRequestBodySize uint32
+}
+
 // Statsd provides the configuration required to emit statsd metrics from the orderer.
 type Statsd struct {
 	Network       string
@@ -235,6 +275,9 @@ var Defaults = TopLevel{
 	FileLedger: FileLedger{
 		Location: "/var/hyperledger/production/orderer",
 		Prefix:   "hyperledger-fabric-ordererledger",
+		Compression: FileLedgerCompression{
+			Default: "none",
+		},
 	},
 	Kafka: Kafka{
 		Retry: Retry{
@@ -278,6 +321,10 @@ var Defaults = TopLevel{
 	Metrics: Metrics{
 		Provider: "disabled",
 	},
+	ChannelParticipation: ChannelParticipation{
+		Enabled:            false,
+		MaxRequestBodySize: 1024 * 1024,
+	},
 }
 
 // Load parses the orderer YAML file and environment, producing
@@ -345,6 +392,10 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			c.General.SystemChannel = Defaults.General.SystemChannel
 		case c.General.Cluster.ReplicationMaxRetries == 0:
 			c.General.Cluster.ReplicationMaxRetries = 12
+		case c.General.GenesisMethod == "none" && !c.ChannelParticipation.Enabled:
+			logger.Panic("ChannelParticipation.Enabled must be set to true if General.GenesisMethod is set to none.")
+		case c.ChannelParticipation.MaxRequestBodySize == 0:
+			c.ChannelParticipation.MaxRequestBodySize = Defaults.ChannelParticipation.MaxRequestBodySize
 
 		case c.Kafka.TLS.Enabled && c.Kafka.TLS.Certificate == "":
 			logger.Panicf("General.Kafka.TLS.Certificate must be set if General.Kafka.TLS.Enabled is set to true.")
@@ -373,9 +424,19 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("General.Authentication.TimeWindow unset, setting to %s", Defaults.General.Authentication.TimeWindow)
 			c.General.Authentication.TimeWindow = Defaults.General.Authentication.TimeWindow
 
+		case c.General.Throttling.Org.Rate > 0 && c.General.Throttling.Org.Burst == 0:
+			c.General.Throttling.Org.Burst = int(math.Ceil(c.General.Throttling.Org.Rate))
+			logger.Infof("General.Throttling.Org.Burst unset, setting to %d", c.General.Throttling.Org.Burst)
+		case c.General.Throttling.Client.Rate > 0 && c.General.Throttling.Client.Burst == 0:
+			c.General.Throttling.Client.Burst = int(math.Ceil(c.General.Throttling.Client.Rate))
+			logger.Infof("General.Throttling.Client.Burst unset, setting to %d", c.General.Throttling.Client.Burst)
+
 		case c.FileLedger.Prefix == "":
 			logger.Infof("FileLedger.Prefix unset, setting to %s", Defaults.FileLedger.Prefix)
 			c.FileLedger.Prefix = Defaults.FileLedger.Prefix
+		case c.FileLedger.Compression.Default == "":
+			logger.Infof("FileLedger.Compression.Default unset, setting to %s", Defaults.FileLedger.Compression.Default)
+			c.FileLedger.Compression.Default = Defaults.FileLedger.Compression.Default
 
 		case c.Kafka.Retry.ShortInterval == 0:
 			logger.Infof("Kafka.Retry.ShortInterval unset, setting to %v", Defaults.Kafka.Retry.ShortInterval)
@@ -141,6 +141,39 @@ func TestClusterDefaults(t *testing.T) {
 	assert.Equal(t, cfg.General.Cluster.ReplicationMaxRetries, Defaults.General.Cluster.ReplicationMaxRetries)
 }
 
+func TestChannelParticipation(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+	cfg, err := Load()
+
+	assert.NoError(t, err)
+	assert.False(t, cfg.ChannelParticipation.Enabled)
+	assert.Equal(t, Defaults.ChannelParticipation.MaxRequestBodySize, cfg.ChannelParticipation.MaxRequestBodySize)
+
+	uconf := &TopLevel{General: General{GenesisMethod: "none"}}
+	assert.Panics(t, func() { uconf.completeInitialization("/dummy/path") }, "Should panic")
+
+	uconf = &TopLevel{General: General{GenesisMethod: "none"}, ChannelParticipation: ChannelParticipation{Enabled: true}}
+	assert.NotPanics(t, func() { uconf.completeInitialization("/dummy/path") }, "Should not panic")
+}
+
+func TestThrottling(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+	cfg, err := Load()
+
+	assert.NoError(t, err)
+	assert.Equal(t, Throttling{}, cfg.General.Throttling)
+
+	uconf := &TopLevel{General: General{Throttling: Throttling{
+		Org:    RateLimit{Rate: 2.5},
+		Client: RateLimit{Rate: 1, Burst: 5},
+	}}}
+	uconf.completeInitialization("/dummy/path")
+	assert.Equal(t, RateLimit{Rate: 2.5, Burst: 3}, uconf.General.Throttling.Org)
+	assert.Equal(t, RateLimit{Rate: 1, Burst: 5}, uconf.General.Throttling.Client)
+}
+
 func TestSystemChannel(t *testing.T) {
 	cleanup := configtest.SetDevFabricConfigPath(t)
 	defer cleanup()
@@ -0,0 +1,60 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"github.com/hyperledger/fabric/orderer/consensus/migration"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// NewMigrationRejectRule returns a rule that rejects the normal messages while a consensus-type migration
+// is in progress on the channel, and the config messages which are a migration step not permitted on the
+// channel. It only depends on the config of the channel, as the consenters re-validate the config messages
+// with it; the coordination of the steps across the channels is checked when the config update is broadcast.
+func NewMigrationRejectRule(filterSupport resources, isSystemChannel bool) Rule {
+	return &migrationRejectRule{
+		filterSupport:   filterSupport,
+		isSystemChannel: isSystemChannel,
+	}
+}
+
+type migrationRejectRule struct {
+	filterSupport   resources
+	isSystemChannel bool
+}
+
+// Apply checks the message against the consensus-type migration status of the channel
+func (mr *migrationRejectRule) Apply(message *common.Envelope) error {
+	ordererConf, ok := mr.filterSupport.OrdererConfig()
+	if !ok {
+		logger.Panic("Programming error: orderer config not found")
+	}
+
+	chdr, err := utils.ChannelHeader(message)
+	if err != nil {
+		return errors.Errorf("bad channel header: %s", err)
+	}
+
+	switch chdr.Type {
+	case int32(common.HeaderType_CONFIG_UPDATE), int32(common.HeaderType_ORDERER_TRANSACTION):
+		// The config updates are checked once turned into config messages
+		return nil
+	case int32(common.HeaderType_CONFIG):
+		next, err := migration.ConsensusTypeFromConfigEnvelope(message)
+		if err != nil {
+			return errors.WithMessage(err, "could not extract the consensus type of the config")
+		}
+		return migration.ValidatePermittedStep(mr.isSystemChannel, ordererConf, next)
+	default:
+		if migration.InProgress(ordererConf.ConsensusMigrationState()) {
+			return errors.WithMessage(ErrMaintenanceMode, "normal transactions are rejected during a consensus-type migration")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/mocks/config"
+	"github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeConsensusTypeConfigEnvelope(t *testing.T, consensusType *ab.ConsensusType) *common.Envelope {
+	env, err := utils.CreateSignedEnvelope(common.HeaderType_CONFIG, "foo", nil, &common.ConfigEnvelope{
+		Config: &common.Config{
+			ChannelGroup: &common.ConfigGroup{
+				Groups: map[string]*common.ConfigGroup{
+					channelconfig.OrdererGroupKey: {
+						Values: map[string]*common.ConfigValue{
+							channelconfig.ConsensusTypeKey: {Value: utils.MarshalOrPanic(consensusType)},
+						},
+					},
+				},
+			},
+		},
+	}, 0, 0)
+	assert.NoError(t, err)
+	return env
+}
+
+func TestMigrationRejectRule(t *testing.T) {
+	normalEnv, err := utils.CreateSignedEnvelope(common.HeaderType_ENDORSER_TRANSACTION, "foo", nil, &common.ConfigEnvelope{}, 0, 0)
+	assert.NoError(t, err)
+	configUpdateEnv, err := utils.CreateSignedEnvelope(common.HeaderType_CONFIG_UPDATE, "foo", nil, &common.ConfigEnvelope{}, 0, 0)
+	assert.NoError(t, err)
+
+	t.Run("NormalMessage", func(t *testing.T) {
+		mockResources := &resourcesMock{}
+		mockResources.On("OrdererConfig").Return(&config.Orderer{
+			ConsensusTypeVal: "kafka",
+		}, true)
+		rule := NewMigrationRejectRule(mockResources, false)
+		assert.NoError(t, rule.Apply(normalEnv))
+		assert.NoError(t, rule.Apply(configUpdateEnv))
+	})
+
+	t.Run("NormalMessageInMaintenance", func(t *testing.T) {
+		mockResources := &resourcesMock{}
+		mockResources.On("OrdererConfig").Return(&config.Orderer{
+			ConsensusTypeVal:               "etcdraft",
+			ConsensusTypeMigrationStateVal: ab.ConsensusType_MIG_STATE_CONTEXT,
+		}, true)
+		rule := NewMigrationRejectRule(mockResources, false)
+		err := rule.Apply(normalEnv)
+		assert.Equal(t, ErrMaintenanceMode, errors.Cause(err))
+		assert.NoError(t, rule.Apply(configUpdateEnv))
+	})
+
+	t.Run("ConfigMessage", func(t *testing.T) {
+		mockResources := &resourcesMock{}
+		mockResources.On("OrdererConfig").Return(&config.Orderer{
+			ConsensusTypeVal: "kafka",
+		}, true)
+		context := makeConsensusTypeConfigEnvelope(t, &ab.ConsensusType{
+			Type:             "etcdraft",
+			MigrationState:   ab.ConsensusType_MIG_STATE_CONTEXT,
+			MigrationContext: 4,
+		})
+
+		assert.NoError(t, NewMigrationRejectRule(mockResources, false).Apply(context))
+
+		err := NewMigrationRejectRule(mockResources, true).Apply(context)
+		assert.EqualError(t, err, "consensus-type migration state MIG_STATE_CONTEXT is not permitted on the system channel")
+	})
+
+	t.Run("BadMessage", func(t *testing.T) {
+		mockResources := &resourcesMock{}
+		mockResources.On("OrdererConfig").Return(&config.Orderer{}, true)
+		rule := NewMigrationRejectRule(mockResources, false)
+		assert.Error(t, rule.Apply(&common.Envelope{Payload: []byte("garbage")}))
+	})
+}
@@ -31,6 +31,10 @@ var ErrChannelDoesNotExist = errors.New("channel does not exist")
 // which are not permitted due to an authorization failure.
 var ErrPermissionDenied = errors.New("permission denied")
 
+// ErrMaintenanceMode is returned by the channels for transactions which are not permitted
+// while the channel is in maintenance, for a consensus-type migration.
+var ErrMaintenanceMode = errors.New("maintenance mode")
+
 // Classification represents the possible message types for the system.
 type Classification int
 
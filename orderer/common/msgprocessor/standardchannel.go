@@ -55,6 +55,7 @@ func CreateStandardChannelFilters(filterSupport channelconfig.Resources) *RuleSe
 		NewExpirationRejectRule(filterSupport),
 		NewSizeFilter(ordererConfig),
 		NewSigFilter(policies.ChannelWriters, filterSupport),
+		NewMigrationRejectRule(filterSupport, false),
 	})
 }
 
@@ -51,6 +51,7 @@ func CreateSystemChannelFilters(chainCreator ChainCreator, ledgerResources chann
 		NewSizeFilter(ordererConfig),
 		NewSigFilter(policies.ChannelWriters, ledgerResources),
 		NewSystemChannelFilter(ledgerResources, chainCreator),
+		NewMigrationRejectRule(ledgerResources, true),
 	})
 }
 
@@ -9,6 +9,7 @@ package msgprocessor
 import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/orderer/consensus/migration"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
@@ -72,6 +73,10 @@ func (scf *SystemChainFilter) Apply(env *cb.Envelope) error {
 		logger.Panicf("System channel does not have orderer config")
 	}
 
+	if migration.InProgress(ordererConfig.ConsensusMigrationState()) {
+		return errors.WithMessage(ErrMaintenanceMode, "channel creation is rejected during a consensus-type migration")
+	}
+
 	maxChannels := ordererConfig.MaxChannelsCount()
 	if maxChannels > 0 {
 		// We check for strictly greater than to accommodate the system channel
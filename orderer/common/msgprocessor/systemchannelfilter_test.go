@@ -20,7 +20,9 @@ import (
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -180,6 +182,25 @@ func TestNumChainsExceeded(t *testing.T) {
 	assert.Regexp(t, "exceed maximimum number", err)
 }
 
+func TestChannelCreationDuringMigration(t *testing.T) {
+	newChainID := "NewChainID"
+
+	mcc := newMockChainCreator()
+	mcc.ms.msc.ConsensusTypeVal = "kafka"
+	mcc.ms.msc.ConsensusTypeMigrationStateVal = ab.ConsensusType_MIG_STATE_START
+
+	configUpdate, err := encoder.MakeChannelCreationTransaction(newChainID, nil, configtxgentest.Load(genesisconfig.SampleSingleMSPChannelProfile))
+	assert.Nil(t, err, "Error constructing configtx")
+	ingressTx := makeConfigTxFromConfigUpdateTx(configUpdate)
+
+	wrapped := wrapConfigTx(ingressTx)
+
+	err = NewSystemChannelFilter(mcc.ms, mcc).Apply(wrapped)
+
+	assert.Equal(t, ErrMaintenanceMode, errors.Cause(err))
+	assert.Len(t, mcc.newChains, 0, "Proposal should not have created a new chain")
+}
+
 func TestBadProposal(t *testing.T) {
 	mcc := newMockChainCreator()
 	sysFilter := NewSystemChannelFilter(mcc.ms, mcc)
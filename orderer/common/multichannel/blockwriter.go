@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package multichannel
 
 import (
+	"bytes"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
@@ -172,25 +173,47 @@ func (bw *BlockWriter) commitBlock(encodedMetadataValue []byte) {
 	logger.Debugf("[channel: %s] Wrote block %d", bw.support.ChainID(), bw.lastBlock.GetHeader().Number)
 }
 
+// addBlockSignature signs the block. The signatures already gathered by the consenter,
+// such as the signatures of a quorum of BFT consenters, are preserved.
 func (bw *BlockWriter) addBlockSignature(block *cb.Block) {
+	signatureHeader := utils.NewSignatureHeaderOrPanic(bw.support)
 	blockSignature := &cb.MetadataSignature{
-		SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(bw.support)),
+		SignatureHeader: utils.MarshalOrPanic(signatureHeader),
 	}
 
 	// Note, this value is intentionally nil, as this metadata is only about the signature, there is no additional metadata
 	// information required beyond the fact that the metadata item is signed.
 	blockSignatureValue := []byte(nil)
 
+	existing := bw.existingBlockSignatures(block)
+	for _, sig := range existing {
+		shdr, err := utils.GetSignatureHeader(sig.SignatureHeader)
+		if err == nil && bytes.Equal(shdr.Creator, signatureHeader.Creator) {
+			// The block is already signed by this orderer
+			return
+		}
+	}
+
 	blockSignature.Signature = utils.SignOrPanic(bw.support, util.ConcatenateBytes(blockSignatureValue, blockSignature.SignatureHeader, block.Header.Bytes()))
 
 	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{
-		Value: blockSignatureValue,
-		Signatures: []*cb.MetadataSignature{
-			blockSignature,
-		},
+		Value:      blockSignatureValue,
+		Signatures: append(existing, blockSignature),
 	})
 }
 
+func (bw *BlockWriter) existingBlockSignatures(block *cb.Block) []*cb.MetadataSignature {
+	if len(block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES]) == 0 {
+		return nil
+	}
+	md, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		logger.Warningf("[channel: %s] Discarding malformed signatures of block %d: %s", bw.support.ChainID(), block.Header.Number, err)
+		return nil
+	}
+	return md.Signatures
+}
+
 func (bw *BlockWriter) addLastConfigSignature(block *cb.Block) {
 	configSeq := bw.support.Sequence()
 	if configSeq > bw.lastConfigSeq {
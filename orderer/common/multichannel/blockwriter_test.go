@@ -19,10 +19,12 @@ package multichannel
 import (
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	newchannelconfig "github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
+	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -70,6 +72,50 @@ func TestBlockSignature(t *testing.T) {
 	assert.NotNil(t, md.Signatures, "Should have signature")
 }
 
+func TestBlockSignaturePreservesConsenterSignatures(t *testing.T) {
+	bw := &BlockWriter{
+		support: &mockBlockWriterSupport{
+			LocalSigner: &mockcrypto.LocalSigner{Identity: []byte("orderer1")},
+		},
+	}
+
+	otherSignature := &cb.MetadataSignature{
+		SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte("orderer2")}),
+		Signature:       []byte("signature2"),
+	}
+
+	t.Run("signed by other orderers", func(t *testing.T) {
+		block := cb.NewBlock(7, []byte("foo"))
+		block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{
+			Signatures: []*cb.MetadataSignature{otherSignature},
+		})
+		bw.addBlockSignature(block)
+
+		md := utils.GetMetadataFromBlockOrPanic(block, cb.BlockMetadataIndex_SIGNATURES)
+		assert.Len(t, md.Signatures, 2)
+		assert.True(t, proto.Equal(otherSignature, md.Signatures[0]))
+		shdr, err := utils.GetSignatureHeader(md.Signatures[1].SignatureHeader)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("orderer1"), shdr.Creator)
+	})
+
+	t.Run("already signed by this orderer", func(t *testing.T) {
+		ownSignature := &cb.MetadataSignature{
+			SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte("orderer1"), Nonce: []byte("nonce")}),
+			Signature:       []byte("signature1"),
+		}
+		block := cb.NewBlock(7, []byte("foo"))
+		block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&cb.Metadata{
+			Signatures: []*cb.MetadataSignature{otherSignature, ownSignature},
+		})
+		bw.addBlockSignature(block)
+
+		md := utils.GetMetadataFromBlockOrPanic(block, cb.BlockMetadataIndex_SIGNATURES)
+		assert.Len(t, md.Signatures, 2)
+		assert.True(t, proto.Equal(ownSignature, md.Signatures[1]))
+	})
+}
+
 func TestBlockLastConfig(t *testing.T) {
 	lastConfigSeq := uint64(6)
 	newConfigSeq := lastConfigSeq + 1
@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/migration"
 	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
@@ -27,6 +28,7 @@ type ChainSupport struct {
 	consensus.Chain
 	cutter blockcutter.Receiver
 	crypto.LocalSigner
+	migrationController migration.Controller
 }
 
 func newChainSupport(
@@ -48,8 +50,9 @@ func newChainSupport(
 
 	// Construct limited support needed as a parameter for additional support
 	cs := &ChainSupport{
-		ledgerResources: ledgerResources,
-		LocalSigner:     signer,
+		ledgerResources:     ledgerResources,
+		LocalSigner:         signer,
+		migrationController: registrar,
 		cutter: blockcutter.NewReceiverImpl(
 			ledgerResources.ConfigtxValidator().ChainID(),
 			ledgerResources,
@@ -64,7 +67,7 @@ func newChainSupport(
 	cs.BlockWriter = newBlockWriter(lastBlock, registrar, cs)
 
 	// Set up the consenter
-	consenterType := ledgerResources.SharedConfig().ConsensusType()
+	consenterType, metadata := registrar.consenterType(ledgerResources, metadata)
 	consenter, ok := consenters[consenterType]
 	if !ok {
 		logger.Panicf("Error retrieving consenter of type: %s", consenterType)
@@ -107,6 +110,28 @@ func (cs *ChainSupport) BlockCutter() blockcutter.Receiver {
 	return cs.cutter
 }
 
+// ProcessConfigUpdateMsg passes through to the message processor of the channel, and additionally
+// checks that a consensus-type migration step resulting from the config update is coordinated with
+// the other channels. As the status of the other channels differs between the orderers, this is only
+// checked when the config update is broadcast, and not when the consenter re-validates the config.
+func (cs *ChainSupport) ProcessConfigUpdateMsg(env *cb.Envelope) (*cb.Envelope, uint64, error) {
+	config, configSeq, err := cs.Processor.ProcessConfigUpdateMsg(env)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	next, err := migration.ConsensusTypeFromConfigEnvelope(config)
+	if err != nil {
+		return nil, 0, errors.WithMessage(err, "could not extract the consensus type of the config")
+	}
+	_, isSystemChannel := cs.Processor.(*msgprocessor.SystemChannel)
+	if err := migration.ValidateStep(isSystemChannel, cs.SharedConfig(), next, cs.migrationController); err != nil {
+		return nil, 0, err
+	}
+
+	return config, configSeq, nil
+}
+
 // Validate passes through to the underlying configtx.Validator
 func (cs *ChainSupport) Validate(configEnv *cb.ConfigEnvelope) error {
 	return cs.ConfigtxValidator().Validate(configEnv)
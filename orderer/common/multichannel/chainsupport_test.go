@@ -19,8 +19,11 @@ import (
 	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/consensus/migration"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -106,3 +109,68 @@ func testConfigEnvelope(t *testing.T) *common.ConfigEnvelope {
 		},
 	}
 }
+
+type migrationStatusMock struct {
+	sysStatus migration.Status
+}
+
+func (msm *migrationStatusMock) SystemChannelStatus() (migration.Status, error) {
+	return msm.sysStatus, nil
+}
+
+func (msm *migrationStatusMock) StandardChannelsStatus() map[string]migration.Status {
+	return nil
+}
+
+type configUpdateProcessorMock struct {
+	msgprocessor.Processor
+	config *common.Envelope
+}
+
+func (cupm *configUpdateProcessorMock) ProcessConfigUpdateMsg(env *common.Envelope) (*common.Envelope, uint64, error) {
+	return cupm.config, 7, nil
+}
+
+func TestChainSupportProcessConfigUpdateMsgMigration(t *testing.T) {
+	context, err := utils.CreateSignedEnvelope(common.HeaderType_CONFIG, "foo", nil, &common.ConfigEnvelope{
+		Config: &common.Config{
+			ChannelGroup: &common.ConfigGroup{
+				Groups: map[string]*common.ConfigGroup{
+					channelconfig.OrdererGroupKey: {
+						Values: map[string]*common.ConfigValue{
+							channelconfig.ConsensusTypeKey: {Value: utils.MarshalOrPanic(&orderer.ConsensusType{
+								Type:             "etcdraft",
+								MigrationState:   orderer.ConsensusType_MIG_STATE_CONTEXT,
+								MigrationContext: 4,
+							})},
+						},
+					},
+				},
+			},
+		},
+	}, 0, 0)
+	assert.NoError(t, err)
+
+	controller := &migrationStatusMock{}
+	cs := &ChainSupport{
+		ledgerResources: &ledgerResources{
+			configResources: &configResources{
+				mutableResources: &mutableResourcesMock{
+					Resources: config.Resources{OrdererConfigVal: &config.Orderer{ConsensusTypeVal: "kafka"}},
+				},
+			},
+		},
+		Processor:           &configUpdateProcessorMock{config: context},
+		migrationController: controller,
+	}
+
+	// The migration is not started on the system channel as seen by this orderer
+	_, _, err = cs.ProcessConfigUpdateMsg(&common.Envelope{})
+	assert.EqualError(t, err, "cannot prepare consensus-type migration with context 4, system channel status is MIG_STATE_NONE/0")
+
+	controller.sysStatus = migration.Status{State: orderer.ConsensusType_MIG_STATE_START, Context: 4}
+	config, configSeq, err := cs.ProcessConfigUpdateMsg(&common.Envelope{})
+	assert.NoError(t, err)
+	assert.Equal(t, context, config)
+	assert.Equal(t, uint64(7), configSeq)
+}
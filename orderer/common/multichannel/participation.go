@@ -0,0 +1,252 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/inactive"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// ChannelReplicator replicates the channels which are joined with a config block other than their genesis block.
+type ChannelReplicator interface {
+	// ReplicateChannel pulls the blocks of the channel which precede the given config block,
+	// and commits them to the ledger of the channel along with the config block.
+	ReplicateChannel(channelID string, configBlock *cb.Block) error
+}
+
+// EnableChannelParticipation allows channels to be joined and removed without the system channel,
+// in which case the Registrar is initialized even if there is none. It must be called before Initialize.
+func (r *Registrar) EnableChannelParticipation(replicator ChannelReplicator) {
+	r.channelReplicator = replicator
+}
+
+// ChannelList returns the system channel, if there is one, and the other channels of the orderer,
+// including the ones which are onboarded.
+func (r *Registrar) ChannelList() types.ChannelList {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	list := types.ChannelList{}
+	if r.systemChannelID != "" {
+		list.SystemChannel = &types.ChannelInfoShort{Name: r.systemChannelID}
+	}
+	for channelID := range r.chains {
+		if channelID != r.systemChannelID {
+			list.Channels = append(list.Channels, types.ChannelInfoShort{Name: channelID})
+		}
+	}
+	for channelID := range r.onboarding {
+		list.Channels = append(list.Channels, types.ChannelInfoShort{Name: channelID})
+	}
+
+	sort.Slice(list.Channels, func(i, j int) bool {
+		return list.Channels[i].Name < list.Channels[j].Name
+	})
+	return list
+}
+
+// ChannelInfo returns the status and the height of the given channel.
+func (r *Registrar) ChannelInfo(channelID string) (types.ChannelInfo, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if info, exists := r.onboarding[channelID]; exists {
+		infoCopy := *info
+		if info.Status == types.StatusOnboarding {
+			ledger, err := r.ledgerFactory.GetOrCreate(channelID)
+			if err != nil {
+				return types.ChannelInfo{}, errors.WithMessage(err, "failed obtaining the ledger")
+			}
+			infoCopy.Height = ledger.Height()
+		}
+		return infoCopy, nil
+	}
+
+	cs, exists := r.chains[channelID]
+	if !exists {
+		return types.ChannelInfo{}, types.ErrChannelNotExist
+	}
+
+	info := types.ChannelInfo{
+		Name:   channelID,
+		Status: types.StatusActive,
+		Height: cs.Height(),
+	}
+	if _, isInactive := cs.Chain.(*inactive.Chain); isInactive {
+		info.Status = types.StatusInactive
+	}
+	return info, nil
+}
+
+// JoinChannel joins the channel of the given config block. A channel joined with its genesis block is
+// started right away, otherwise it is onboarded: the blocks which precede the config block are pulled
+// from the orderers of the channel in the background, and the channel is started once they are.
+func (r *Registrar) JoinChannel(configBlock *cb.Block) (types.ChannelInfo, error) {
+	if r.channelReplicator == nil {
+		return types.ChannelInfo{}, types.ErrChannelParticipationDisabled
+	}
+
+	channelID, err := r.validateJoinBlock(configBlock)
+	if err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "invalid join block")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.systemChannelID != "" {
+		return types.ChannelInfo{}, types.ErrSystemChannelExists
+	}
+	if _, exists := r.chains[channelID]; exists {
+		return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+	}
+	if _, exists := r.onboarding[channelID]; exists {
+		return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+	}
+	// The ledger of a channel outlives its chain until the channel is removed
+	for _, existingID := range r.ledgerFactory.ChainIDs() {
+		if existingID == channelID {
+			return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+		}
+	}
+
+	if configBlock.Header.Number != 0 {
+		logger.Infof("Joining channel %s with config block %d, onboarding it", channelID, configBlock.Header.Number)
+		info := &types.ChannelInfo{Name: channelID, Status: types.StatusOnboarding}
+		r.onboarding[channelID] = info
+		go r.onboard(channelID, configBlock)
+		return *info, nil
+	}
+
+	ledger, err := r.ledgerFactory.GetOrCreate(channelID)
+	if err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "failed creating the ledger")
+	}
+	if err := ledger.Append(configBlock); err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "failed appending the genesis block")
+	}
+
+	logger.Infof("Joining channel %s with its genesis block", channelID)
+	r.addChain(utils.ExtractEnvelopeOrPanic(configBlock, 0))
+	return types.ChannelInfo{Name: channelID, Status: types.StatusActive, Height: 1}, nil
+}
+
+func (r *Registrar) onboard(channelID string, configBlock *cb.Block) {
+	err := r.channelReplicator.ReplicateChannel(channelID, configBlock)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	info := r.onboarding[channelID]
+	if err != nil {
+		logger.Errorf("Failed onboarding channel %s: %s", channelID, err)
+		if err := r.ledgerFactory.Remove(channelID); err != nil {
+			logger.Errorf("Failed removing the ledger of channel %s: %s", channelID, err)
+		}
+		info.Status = types.StatusFailed
+		info.Error = err.Error()
+		return
+	}
+
+	logger.Infof("Onboarded channel %s", channelID)
+	delete(r.onboarding, channelID)
+	r.addChain(utils.ExtractEnvelopeOrPanic(configBlock, 0))
+}
+
+// validateJoinBlock returns the channel of the given config block, if it can be joined.
+func (r *Registrar) validateJoinBlock(configBlock *cb.Block) (string, error) {
+	if configBlock == nil || configBlock.Header == nil || configBlock.Data == nil || len(configBlock.Data.Data) == 0 {
+		return "", errors.New("block is empty")
+	}
+
+	env, err := utils.ExtractEnvelope(configBlock, 0)
+	if err != nil {
+		return "", err
+	}
+	chdr, err := utils.ChannelHeader(env)
+	if err != nil {
+		return "", err
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		return "", errors.Errorf("block is not a config block, its type is %s", cb.HeaderType(chdr.Type))
+	}
+
+	bundle, err := channelconfig.NewBundleFromEnvelope(env)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed creating the channel config")
+	}
+	if err := checkResources(bundle); err != nil {
+		return "", err
+	}
+	if _, isSystemChannel := bundle.ConsortiumsConfig(); isSystemChannel {
+		return "", errors.New("the system channel cannot be joined")
+	}
+	oc, _ := bundle.OrdererConfig()
+	if _, exists := r.consenters[oc.ConsensusType()]; !exists {
+		return "", errors.Errorf("consensus type %s is not supported", oc.ConsensusType())
+	}
+
+	return chdr.ChannelId, nil
+}
+
+// RemoveChannel halts the chain of the given channel and removes its ledger, along with
+// the data its consenter keeps outside of the ledger.
+// A channel whose onboarding failed is forgotten.
+func (r *Registrar) RemoveChannel(channelID string) error {
+	if r.channelReplicator == nil {
+		return types.ErrChannelParticipationDisabled
+	}
+
+	r.lock.Lock()
+	if r.systemChannelID != "" {
+		r.lock.Unlock()
+		return types.ErrSystemChannelExists
+	}
+	if info, exists := r.onboarding[channelID]; exists {
+		defer r.lock.Unlock()
+		if info.Status == types.StatusOnboarding {
+			return types.ErrChannelOnboarding
+		}
+		delete(r.onboarding, channelID)
+		return nil
+	}
+
+	cs, exists := r.chains[channelID]
+	if !exists {
+		r.lock.Unlock()
+		return types.ErrChannelNotExist
+	}
+	newChains := make(map[string]*ChainSupport)
+	for key, value := range r.chains {
+		if key != channelID {
+			newChains[key] = value
+		}
+	}
+	r.chains = newChains
+	r.lock.Unlock()
+
+	// The chain is halted without the lock held, as it may consult the registrar until it halts.
+	// Until its ledger is removed, the channel cannot be joined again.
+	logger.Infof("Removing channel %s", channelID)
+	cs.Halt()
+	if remover, ok := r.consenters[cs.SharedConfig().ConsensusType()].(consensus.ChannelRemover); ok {
+		if err := remover.RemoveChannel(channelID); err != nil {
+			return errors.WithMessage(err, "failed removing the consensus data")
+		}
+	}
+	if err := r.ledgerFactory.Remove(channelID); err != nil {
+		return errors.WithMessage(err, "failed removing the ledger")
+	}
+	return nil
+}
@@ -0,0 +1,271 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	ramledger "github.com/hyperledger/fabric/common/ledger/blockledger/ram"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/configtxgentest"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type replicatorFunc func(channelID string, configBlock *cb.Block) error
+
+func (rf replicatorFunc) ReplicateChannel(channelID string, configBlock *cb.Block) error {
+	return rf(channelID, configBlock)
+}
+
+// appChannelBlocks returns the blocks of an application channel,
+// the last of which is a config block.
+func appChannelBlocks(t *testing.T, channelID string, height int) []*cb.Block {
+	appConf := configtxgentest.Load(genesisconfig.SampleInsecureSoloProfile)
+	appConf.Consortiums = nil
+	appConf.Application = configtxgentest.Load(genesisconfig.SampleSingleMSPChannelProfile).Application
+	appConf.Application.Organizations = nil
+	genesis := encoder.New(appConf).GenesisBlockForChannel(channelID)
+
+	rl, err := ramledger.New(height).GetOrCreate(channelID)
+	require.NoError(t, err)
+	require.NoError(t, rl.Append(genesis))
+	for i := 1; i < height-1; i++ {
+		require.NoError(t, rl.Append(blockledger.CreateNextBlock(rl, []*cb.Envelope{makeNormalTx(channelID, i)})))
+	}
+	if height > 1 {
+		require.NoError(t, rl.Append(blockledger.CreateNextBlock(rl, []*cb.Envelope{utils.ExtractEnvelopeOrPanic(genesis, 0)})))
+	}
+
+	var blocks []*cb.Block
+	for i := 0; i < height; i++ {
+		blocks = append(blocks, blockledger.GetBlock(rl, uint64(i)))
+	}
+	return blocks
+}
+
+// waitFor fails the test if the given condition is not met within a minute.
+func waitFor(t *testing.T, condition func() bool) {
+	for deadline := time.Now().Add(time.Minute); !condition(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+	}
+}
+
+func newParticipationRegistrar(lf blockledger.Factory, replicator ChannelReplicator) *Registrar {
+	consenters := make(map[string]consensus.Consenter)
+	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
+
+	registrar := NewRegistrar(lf, mockCrypto(), &disabled.Provider{})
+	registrar.EnableChannelParticipation(replicator)
+	registrar.Initialize(consenters)
+	return registrar
+}
+
+func TestJoinChannel(t *testing.T) {
+	lf := ramledger.New(10)
+	replicator := replicatorFunc(func(string, *cb.Block) error {
+		return errors.New("not expected to replicate")
+	})
+	registrar := newParticipationRegistrar(lf, replicator)
+	assert.Equal(t, types.ChannelList{}, registrar.ChannelList())
+
+	genesis := appChannelBlocks(t, "mychannel", 1)[0]
+	info, err := registrar.JoinChannel(genesis)
+	require.NoError(t, err)
+	assert.Equal(t, types.ChannelInfo{Name: "mychannel", Status: types.StatusActive, Height: 1}, info)
+
+	cs := registrar.GetChain("mychannel")
+	require.NotNil(t, cs)
+	defer cs.Halt()
+	info, err = registrar.ChannelInfo("mychannel")
+	assert.NoError(t, err)
+	assert.Equal(t, types.ChannelInfo{Name: "mychannel", Status: types.StatusActive, Height: 1}, info)
+	assert.Equal(t, types.ChannelList{Channels: []types.ChannelInfoShort{{Name: "mychannel"}}}, registrar.ChannelList())
+
+	_, err = registrar.JoinChannel(genesis)
+	assert.Equal(t, types.ErrChannelAlreadyExists, err)
+
+	_, err = registrar.ChannelInfo("notmychannel")
+	assert.Equal(t, types.ErrChannelNotExist, err)
+
+	_, _, _, err = registrar.BroadcastChannelSupport(makeNormalTx("notmychannel", 0))
+	assert.EqualError(t, err, "channel notmychannel does not exist")
+
+	t.Run("invalid join blocks", func(t *testing.T) {
+		_, err := registrar.JoinChannel(nil)
+		assert.EqualError(t, err, "invalid join block: block is empty")
+
+		normalBlock := cb.NewBlock(0, nil)
+		normalBlock.Data.Data = [][]byte{utils.MarshalOrPanic(makeNormalTx("yourchannel", 0))}
+		_, err = registrar.JoinChannel(normalBlock)
+		assert.EqualError(t, err, "invalid join block: block is not a config block, its type is ENDORSER_TRANSACTION")
+
+		_, err = registrar.JoinChannel(encoder.New(conf).GenesisBlockForChannel("yourchannel"))
+		assert.EqualError(t, err, "invalid join block: the system channel cannot be joined")
+
+		kafkaConf := configtxgentest.Load(genesisconfig.SampleInsecureSoloProfile)
+		kafkaConf.Consortiums = nil
+		kafkaConf.Orderer.OrdererType = "kafka"
+		_, err = registrar.JoinChannel(encoder.New(kafkaConf).GenesisBlockForChannel("yourchannel"))
+		assert.EqualError(t, err, "invalid join block: consensus type kafka is not supported")
+
+		assert.Nil(t, registrar.GetChain("yourchannel"))
+		assert.NotContains(t, lf.ChainIDs(), "yourchannel")
+	})
+}
+
+func TestOnboardChannel(t *testing.T) {
+	blocks := appChannelBlocks(t, "mychannel", 3)
+
+	t.Run("green path", func(t *testing.T) {
+		lf := ramledger.New(10)
+		release := make(chan struct{})
+		replicator := replicatorFunc(func(channelID string, configBlock *cb.Block) error {
+			assert.Equal(t, "mychannel", channelID)
+			assert.Equal(t, blocks[2], configBlock)
+			ledger, err := lf.GetOrCreate(channelID)
+			require.NoError(t, err)
+			ledger.Append(blocks[0])
+			<-release
+			ledger.Append(blocks[1])
+			ledger.Append(blocks[2])
+			return nil
+		})
+		registrar := newParticipationRegistrar(lf, replicator)
+
+		info, err := registrar.JoinChannel(blocks[2])
+		require.NoError(t, err)
+		assert.Equal(t, types.ChannelInfo{Name: "mychannel", Status: types.StatusOnboarding}, info)
+
+		channelInfo := func() types.ChannelInfo {
+			info, err := registrar.ChannelInfo("mychannel")
+			require.NoError(t, err)
+			return info
+		}
+		waitFor(t, func() bool { return channelInfo().Height == 1 })
+		assert.Equal(t, types.ChannelInfo{Name: "mychannel", Status: types.StatusOnboarding, Height: 1}, channelInfo())
+		assert.Equal(t, types.ChannelList{Channels: []types.ChannelInfoShort{{Name: "mychannel"}}}, registrar.ChannelList())
+		assert.Nil(t, registrar.GetChain("mychannel"))
+
+		_, err = registrar.JoinChannel(blocks[2])
+		assert.Equal(t, types.ErrChannelAlreadyExists, err)
+		assert.Equal(t, types.ErrChannelOnboarding, registrar.RemoveChannel("mychannel"))
+
+		close(release)
+		waitFor(t, func() bool { return registrar.GetChain("mychannel") != nil })
+		defer registrar.GetChain("mychannel").Halt()
+		assert.Equal(t, types.ChannelInfo{Name: "mychannel", Status: types.StatusActive, Height: 3}, channelInfo())
+	})
+
+	t.Run("replication failure", func(t *testing.T) {
+		lf := ramledger.New(10)
+		replicator := replicatorFunc(func(channelID string, configBlock *cb.Block) error {
+			ledger, err := lf.GetOrCreate(channelID)
+			require.NoError(t, err)
+			ledger.Append(blocks[0])
+			return errors.New("no orderer is reachable")
+		})
+		registrar := newParticipationRegistrar(lf, replicator)
+
+		_, err := registrar.JoinChannel(blocks[2])
+		require.NoError(t, err)
+
+		failed := types.ChannelInfo{Name: "mychannel", Status: types.StatusFailed, Error: "no orderer is reachable"}
+		waitFor(t, func() bool {
+			info, err := registrar.ChannelInfo("mychannel")
+			return err == nil && info == failed
+		})
+		assert.Empty(t, lf.ChainIDs())
+
+		assert.NoError(t, registrar.RemoveChannel("mychannel"))
+		assert.Equal(t, types.ChannelList{}, registrar.ChannelList())
+		_, err = registrar.ChannelInfo("mychannel")
+		assert.Equal(t, types.ErrChannelNotExist, err)
+	})
+}
+
+// removingConsenter records the channels whose consensus data is removed.
+type removingConsenter struct {
+	mockConsenter
+	removed []string
+	err     error
+}
+
+func (rc *removingConsenter) RemoveChannel(channelID string) error {
+	rc.removed = append(rc.removed, channelID)
+	return rc.err
+}
+
+func TestRemoveChannel(t *testing.T) {
+	lf := ramledger.New(10)
+	consenter := &removingConsenter{}
+	registrar := NewRegistrar(lf, mockCrypto(), &disabled.Provider{})
+	registrar.EnableChannelParticipation(replicatorFunc(func(string, *cb.Block) error { return nil }))
+	registrar.Initialize(map[string]consensus.Consenter{conf.Orderer.OrdererType: consenter})
+
+	genesis := appChannelBlocks(t, "mychannel", 1)[0]
+	_, err := registrar.JoinChannel(genesis)
+	require.NoError(t, err)
+	chain := registrar.GetChain("mychannel").Chain.(*mockChain)
+
+	assert.NoError(t, registrar.RemoveChannel("mychannel"))
+	assert.Nil(t, registrar.GetChain("mychannel"))
+	assert.Empty(t, lf.ChainIDs())
+	_, ok := <-chain.queue
+	assert.False(t, ok, "chain should have been halted")
+	assert.Equal(t, []string{"mychannel"}, consenter.removed)
+
+	assert.Equal(t, types.ErrChannelNotExist, registrar.RemoveChannel("mychannel"))
+
+	// The channel can be joined again once it is removed
+	_, err = registrar.JoinChannel(genesis)
+	assert.NoError(t, err)
+
+	// The ledger is kept if the consensus data cannot be removed
+	consenter.err = errors.New("permission denied")
+	assert.EqualError(t, registrar.RemoveChannel("mychannel"), "failed removing the consensus data: permission denied")
+	assert.Equal(t, []string{"mychannel"}, lf.ChainIDs())
+}
+
+func TestChannelParticipationWithSystemChannel(t *testing.T) {
+	lf, _ := NewRAMLedgerAndFactory(10)
+	registrar := newParticipationRegistrar(lf, replicatorFunc(func(string, *cb.Block) error { return nil }))
+	defer registrar.GetChain(genesisconfig.TestChainID).Halt()
+
+	assert.Equal(t, types.ChannelList{SystemChannel: &types.ChannelInfoShort{Name: genesisconfig.TestChainID}}, registrar.ChannelList())
+	info, err := registrar.ChannelInfo(genesisconfig.TestChainID)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ChannelInfo{Name: genesisconfig.TestChainID, Status: types.StatusActive, Height: 1}, info)
+
+	_, err = registrar.JoinChannel(appChannelBlocks(t, "mychannel", 1)[0])
+	assert.Equal(t, types.ErrSystemChannelExists, err)
+	assert.Equal(t, types.ErrSystemChannelExists, registrar.RemoveChannel(genesisconfig.TestChainID))
+}
+
+func TestChannelParticipationDisabled(t *testing.T) {
+	lf, _ := NewRAMLedgerAndFactory(10)
+	consenters := make(map[string]consensus.Consenter)
+	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
+	registrar := NewRegistrar(lf, mockCrypto(), &disabled.Provider{})
+	registrar.Initialize(consenters)
+	defer registrar.GetChain(genesisconfig.TestChainID).Halt()
+
+	_, err := registrar.JoinChannel(appChannelBlocks(t, "mychannel", 1)[0])
+	assert.Equal(t, types.ErrChannelParticipationDisabled, err)
+	assert.Equal(t, types.ErrChannelParticipationDisabled, registrar.RemoveChannel("mychannel"))
+}
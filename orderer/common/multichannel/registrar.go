@@ -21,7 +21,9 @@ import (
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/migration"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -102,6 +104,8 @@ type Registrar struct {
 	systemChannel      *ChainSupport
 	templator          msgprocessor.ChannelConfigTemplator
 	callbacks          []channelconfig.BundleActor
+	channelReplicator  ChannelReplicator
+	onboarding         map[string]*types.ChannelInfo
 }
 
 // ConfigBlock retrieves the last configuration block from the given ledger.
@@ -133,6 +137,7 @@ func NewRegistrar(ledgerFactory blockledger.Factory,
 		signer:             signer,
 		blockcutterMetrics: blockcutter.NewMetrics(metricsProvider),
 		callbacks:          callbacks,
+		onboarding:         make(map[string]*types.ChannelInfo),
 	}
 
 	return r
@@ -141,6 +146,7 @@ func NewRegistrar(ledgerFactory blockledger.Factory,
 func (r *Registrar) Initialize(consenters map[string]consensus.Consenter) {
 	r.consenters = consenters
 	existingChains := r.ledgerFactory.ChainIDs()
+	var standardChains []*ledgerResources
 	for _, chainID := range existingChains {
 		rl, err := r.ledgerFactory.GetOrCreate(chainID)
 		if err != nil {
@@ -184,21 +190,30 @@ func (r *Registrar) Initialize(consenters map[string]consensus.Consenter) {
 			// We delay starting this chain, as it might try to copy and replace the chains map via newChain before the map is fully built
 			defer chain.start()
 		} else {
-			logger.Debugf("Starting chain: %s", chainID)
-			chain := newChainSupport(
-				r,
-				ledgerResources,
-				r.consenters,
-				r.signer,
-				r.blockcutterMetrics)
-			r.chains[chainID] = chain
-			chain.start()
+			// The standard chains are created once the system chain is known, as the consenter
+			// of a standard chain depends on the consensus-type migration status of the system chain
+			standardChains = append(standardChains, ledgerResources)
 		}
-
 	}
 
 	if r.systemChannelID == "" {
-		logger.Panicf("No system chain found.  If bootstrapping, does your system channel contain a consortiums group definition?")
+		if r.channelReplicator == nil {
+			logger.Panicf("No system chain found.  If bootstrapping, does your system channel contain a consortiums group definition?")
+		}
+		logger.Infof("Starting without a system channel, channels are joined through the channel participation API")
+	}
+
+	for _, ledgerResources := range standardChains {
+		chainID := ledgerResources.ConfigtxValidator().ChainID()
+		logger.Debugf("Starting chain: %s", chainID)
+		chain := newChainSupport(
+			r,
+			ledgerResources,
+			r.consenters,
+			r.signer,
+			r.blockcutterMetrics)
+		r.chains[chainID] = chain
+		chain.start()
 	}
 }
 
@@ -218,6 +233,9 @@ func (r *Registrar) BroadcastChannelSupport(msg *cb.Envelope) (*cb.ChannelHeader
 
 	cs := r.GetChain(chdr.ChannelId)
 	if cs == nil {
+		if r.systemChannel == nil {
+			return nil, false, nil, errors.Errorf("channel %s does not exist", chdr.ChannelId)
+		}
 		cs = r.systemChannel
 	}
 
@@ -300,6 +318,11 @@ func (r *Registrar) newChain(configtx *cb.Envelope) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
+	r.addChain(configtx)
+}
+
+// addChain must be called with the lock held.
+func (r *Registrar) addChain(configtx *cb.Envelope) {
 	ledgerResources := r.newLedgerResources(configtx)
 	// If we have no blocks, we need to create the genesis block ourselves.
 	if ledgerResources.Height() == 0 {
@@ -331,6 +354,83 @@ func (r *Registrar) ChannelsCount() int {
 	return len(r.chains)
 }
 
+// SystemChannelStatus returns the consensus-type migration status of the system channel.
+func (r *Registrar) SystemChannelStatus() (migration.Status, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.systemChannelStatus()
+}
+
+// systemChannelStatus must be called with the lock held, or before the registrar is initialized.
+func (r *Registrar) systemChannelStatus() (migration.Status, error) {
+	if r.systemChannel == nil {
+		return migration.Status{}, errors.New("system channel does not exist")
+	}
+
+	oc := r.systemChannel.SharedConfig()
+	status := migration.Status{
+		State:   oc.ConsensusMigrationState(),
+		Context: oc.ConsensusMigrationContext(),
+	}
+
+	if status.State == ab.ConsensusType_MIG_STATE_START {
+		// The context of the migration is the number of the config block that started it
+		lastBlock := blockledger.GetBlock(r.systemChannel, r.systemChannel.Height()-1)
+		index, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+		if err != nil {
+			return migration.Status{}, errors.WithMessage(err, "failed to retrieve the last config index of the system channel")
+		}
+		status.Context = index
+	}
+
+	return status, nil
+}
+
+// StandardChannelsStatus returns the consensus-type migration status of every standard channel.
+func (r *Registrar) StandardChannelsStatus() map[string]migration.Status {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	statuses := make(map[string]migration.Status)
+	for chainID, cs := range r.chains {
+		if chainID == r.systemChannelID {
+			continue
+		}
+		oc := cs.SharedConfig()
+		statuses[chainID] = migration.Status{
+			State:   oc.ConsensusMigrationState(),
+			Context: oc.ConsensusMigrationContext(),
+		}
+	}
+	return statuses
+}
+
+// consenterType returns the type of the consenter of a chain, along with the consenter metadata
+// of its last block. A chain on which Kafka committed a migration to Raft is handed to Raft
+// without the Kafka metadata, as long as the migration was committed on the system channel.
+func (r *Registrar) consenterType(ledgerResources *ledgerResources, metadata *cb.Metadata) (string, *cb.Metadata) {
+	oc := ledgerResources.SharedConfig()
+	consenterType := oc.ConsensusType()
+	if !migration.IsMigrated(oc) {
+		return consenterType, metadata
+	}
+
+	chainID := ledgerResources.ConfigtxValidator().ChainID()
+	if oc.ConsensusMigrationState() == ab.ConsensusType_MIG_STATE_CONTEXT {
+		sysStatus, err := r.systemChannelStatus()
+		if err != nil || sysStatus.State != ab.ConsensusType_MIG_STATE_COMMIT || sysStatus.Context != oc.ConsensusMigrationContext() {
+			logger.Warningf("[channel: %s] Consensus-type migration to %s with context %d is not committed on the system channel, "+
+				"staying on %s", chainID, consenterType, oc.ConsensusMigrationContext(), migration.FromType)
+			return migration.FromType, metadata
+		}
+	}
+
+	logger.Infof("[channel: %s] Consensus-type migration to %s with context %d is committed, discarding the %s metadata",
+		chainID, consenterType, oc.ConsensusMigrationContext(), migration.FromType)
+	return consenterType, nil
+}
+
 // NewChannelConfig produces a new template channel configuration based on the system channel's current config.
 func (r *Registrar) NewChannelConfig(envConfigUpdate *cb.Envelope) (channelconfig.Resources, error) {
 	return r.templator.NewChannelConfig(envConfigUpdate)
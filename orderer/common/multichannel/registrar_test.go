@@ -16,6 +16,7 @@ import (
 	ramledger "github.com/hyperledger/fabric/common/ledger/blockledger/ram"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	mockchannelconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
 	mockcrypto "github.com/hyperledger/fabric/common/mocks/crypto"
 	mmsp "github.com/hyperledger/fabric/common/mocks/msp"
 	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
@@ -25,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/migration"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -378,3 +380,82 @@ func TestBroadcastChannelSupportRejection(t *testing.T) {
 	_, _, _, err := registrar.BroadcastChannelSupport(configTx)
 	assert.Error(t, err, "Messages of type HeaderType_CONFIG should return an error.")
 }
+
+func TestConsensusMigrationStatus(t *testing.T) {
+	newLedgerResources := func(chainID string, oc *mockchannelconfig.Orderer, rl blockledger.ReadWriter) *ledgerResources {
+		return &ledgerResources{
+			configResources: &configResources{
+				mutableResources: &mutableResourcesMock{
+					Resources: mockchannelconfig.Resources{
+						ConfigtxValidatorVal: &mockconfigtx.Validator{ChainIDVal: chainID},
+						OrdererConfigVal:     oc,
+					},
+				},
+			},
+			ReadWriter: rl,
+		}
+	}
+
+	sysLedger := NewRAMLedger(10)
+	block := blockledger.CreateNextBlock(sysLedger, []*cb.Envelope{makeConfigTx(genesisconfig.TestChainID, 1)})
+	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{Value: utils.MarshalOrPanic(&cb.LastConfig{Index: 1})})
+	sysLedger.Append(block)
+
+	sysConfig := &mockchannelconfig.Orderer{
+		ConsensusTypeVal:               "kafka",
+		ConsensusTypeMigrationStateVal: ab.ConsensusType_MIG_STATE_START,
+	}
+	fooConfig := &mockchannelconfig.Orderer{
+		ConsensusTypeVal:                 "etcdraft",
+		ConsensusTypeMigrationStateVal:   ab.ConsensusType_MIG_STATE_CONTEXT,
+		ConsensusTypeMigrationContextVal: 1,
+	}
+
+	r := &Registrar{chains: make(map[string]*ChainSupport)}
+	_, err := r.SystemChannelStatus()
+	assert.EqualError(t, err, "system channel does not exist")
+
+	sysChain := &ChainSupport{ledgerResources: newLedgerResources(genesisconfig.TestChainID, sysConfig, sysLedger)}
+	fooChain := &ChainSupport{ledgerResources: newLedgerResources("foo", fooConfig, nil)}
+	r.systemChannelID = genesisconfig.TestChainID
+	r.systemChannel = sysChain
+	r.chains[genesisconfig.TestChainID] = sysChain
+	r.chains["foo"] = fooChain
+
+	t.Run("Started", func(t *testing.T) {
+		status, err := r.SystemChannelStatus()
+		assert.NoError(t, err)
+		assert.Equal(t, migration.Status{State: ab.ConsensusType_MIG_STATE_START, Context: 1}, status)
+		assert.Equal(t, map[string]migration.Status{
+			"foo": {State: ab.ConsensusType_MIG_STATE_CONTEXT, Context: 1},
+		}, r.StandardChannelsStatus())
+
+		// The migration is not committed, the chain stays on kafka
+		consenterType, metadata := r.consenterType(fooChain.ledgerResources, &cb.Metadata{Value: []byte("kafka")})
+		assert.Equal(t, "kafka", consenterType)
+		assert.Equal(t, []byte("kafka"), metadata.Value)
+	})
+
+	t.Run("Committed", func(t *testing.T) {
+		sysConfig.ConsensusTypeVal = "etcdraft"
+		sysConfig.ConsensusTypeMigrationStateVal = ab.ConsensusType_MIG_STATE_COMMIT
+		sysConfig.ConsensusTypeMigrationContextVal = 1
+
+		status, err := r.SystemChannelStatus()
+		assert.NoError(t, err)
+		assert.Equal(t, migration.Status{State: ab.ConsensusType_MIG_STATE_COMMIT, Context: 1}, status)
+
+		consenterType, metadata := r.consenterType(sysChain.ledgerResources, &cb.Metadata{Value: []byte("kafka")})
+		assert.Equal(t, "etcdraft", consenterType)
+		assert.Nil(t, metadata)
+
+		consenterType, metadata = r.consenterType(fooChain.ledgerResources, &cb.Metadata{Value: []byte("kafka")})
+		assert.Equal(t, "etcdraft", consenterType)
+		assert.Nil(t, metadata)
+
+		fooConfig.ConsensusTypeMigrationContextVal = 2
+		consenterType, metadata = r.consenterType(fooChain.ledgerResources, &cb.Metadata{Value: []byte("kafka")})
+		assert.Equal(t, "kafka", consenterType)
+		assert.Equal(t, []byte("kafka"), metadata.Value)
+	})
+}
@@ -9,6 +9,7 @@ package server
 import (
 	"bytes"
 	"context"
+	"encoding/hex"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -21,8 +22,10 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-lib-go/healthz"
+	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/flogging"
 	floggingmetrics "github.com/hyperledger/fabric/common/flogging/metrics"
 	"github.com/hyperledger/fabric/common/grpclogging"
@@ -39,11 +42,13 @@ import (
 	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/metadata"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
 	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/bft"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
 	"github.com/hyperledger/fabric/orderer/consensus/kafka"
 	"github.com/hyperledger/fabric/orderer/consensus/solo"
@@ -64,8 +69,9 @@ var (
 	start     = app.Command("start", "Start the orderer node").Default()
 	version   = app.Command("version", "Show version information")
 	benchmark = app.Command("benchmark", "Run orderer in benchmark mode")
+	genKey    = app.Command("generate-key", "Generate a key to encrypt the data at rest with and print its identifier")
 
-	clusterTypes = map[string]struct{}{"etcdraft": {}}
+	clusterTypes = map[string]struct{}{"etcdraft": {}, "bft": {}}
 )
 
 // Main is the entry point of orderer process
@@ -86,6 +92,16 @@ func Main() {
 	initializeLogging()
 	initializeLocalMsp(conf)
 
+	// "generate-key" command
+	if fullCmd == genKey.FullCommand() {
+		ski, err := encryption.GenerateKey(factory.GetDefault())
+		if err != nil {
+			logger.Fatal("Failed to generate the encryption key:", err)
+		}
+		fmt.Println(hex.EncodeToString(ski))
+		return
+	}
+
 	prettyPrintStruct(conf)
 	Start(fullCmd, conf)
 }
@@ -158,7 +174,7 @@ func Start(cmd string, conf *localconfig.TopLevel) {
 
 	manager := initializeMultichannelRegistrar(bootstrapBlock, r, clusterDialer, clusterServerConfig, clusterGRPCServer, conf, signer, metricsProvider, opsSystem, lf, tlsCallback)
 	mutualTLS := serverConfig.SecOpts.UseTLS && serverConfig.SecOpts.RequireClientCert
-	server := NewServer(manager, metricsProvider, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS)
+	server := NewServer(manager, metricsProvider, &conf.Debug, conf.General.Authentication.TimeWindow, mutualTLS, conf.General.Throttling)
 
 	logger.Infof("Starting %s", metadata.GetVersionInfo())
 	go handleSignals(addPlatformSignals(map[os.Signal]func(){
@@ -427,6 +443,8 @@ func extractBootstrapBlock(conf *localconfig.TopLevel) *cb.Block {
 		bootstrapBlock = encoder.New(genesisconfig.Load(conf.General.GenesisProfile)).GenesisBlockForChannel(conf.General.SystemChannel)
 	case "file":
 		bootstrapBlock = file.New(conf.General.GenesisFile).GenesisBlock()
+	case "none":
+		logger.Info("Starting without a genesis block")
 	default:
 		logger.Panic("Unknown genesis method:", conf.General.GenesisMethod)
 	}
@@ -449,7 +467,13 @@ func initializeBootstrapChannel(genesisBlock *cb.Block, lf blockledger.Factory)
 	}
 }
 
+// isClusterType returns whether the system channel of the given genesis block is of a
+// cluster consensus type. Without a genesis block, any channel may be joined, including
+// channels of a cluster consensus type.
 func isClusterType(genesisBlock *cb.Block) bool {
+	if genesisBlock == nil {
+		return true
+	}
 	if genesisBlock.Data == nil || len(genesisBlock.Data.Data) == 0 {
 		logger.Fatalf("Empty genesis block")
 	}
@@ -499,6 +523,21 @@ type healthChecker interface {
 	RegisterChecker(component string, checker healthz.HealthChecker) error
 }
 
+//go:generate counterfeiter -o mocks/admin_server.go -fake-name AdminServer . adminServer
+
+// adminServer serves the administration endpoints of the orderer
+type adminServer interface {
+	RegisterHandler(pattern string, handler http.Handler) error
+}
+
+//go:generate counterfeiter -o mocks/operations_system.go -fake-name OperationsSystem . operationsSystem
+
+// operationsSystem registers the health checkers and serves the administration endpoints of the orderer
+type operationsSystem interface {
+	healthChecker
+	adminServer
+}
+
 func initializeMultichannelRegistrar(
 	bootstrapBlock *cb.Block,
 	ri *replicationInitiator,
@@ -508,13 +547,15 @@ func initializeMultichannelRegistrar(
 	conf *localconfig.TopLevel,
 	signer crypto.LocalSigner,
 	metricsProvider metrics.Provider,
-	healthChecker healthChecker,
+	opsSystem operationsSystem,
 	lf blockledger.Factory,
 	callbacks ...channelconfig.BundleActor,
 ) *multichannel.Registrar {
 	genesisBlock := extractBootstrapBlock(conf)
 	// Are we bootstrapping?
-	if len(lf.ChainIDs()) == 0 {
+	if genesisBlock == nil {
+		logger.Info("Not bootstrapping because there is no genesis block")
+	} else if len(lf.ChainIDs()) == 0 {
 		initializeBootstrapChannel(genesisBlock, lf)
 	} else {
 		logger.Info("Not bootstrapping because of existing chains")
@@ -523,15 +564,22 @@ func initializeMultichannelRegistrar(
 	consenters := make(map[string]consensus.Consenter)
 
 	registrar := multichannel.NewRegistrar(lf, signer, metricsProvider, callbacks...)
+	if conf.ChannelParticipation.Enabled {
+		registrar.EnableChannelParticipation(ri)
+		handler := channelparticipation.NewHTTPHandler(conf.ChannelParticipation, registrar)
+		if err := opsSystem.RegisterHandler(channelparticipation.URLBaseV1, handler); err != nil {
+			logger.Panicf("Failed to serve the channel participation API: %s", err)
+		}
+	}
 
 	consenters["solo"] = solo.New()
 	var kafkaMetrics *kafka.Metrics
-	consenters["kafka"], kafkaMetrics = kafka.New(conf.Kafka, metricsProvider, healthChecker)
+	consenters["kafka"], kafkaMetrics = kafka.New(conf.Kafka, metricsProvider, opsSystem)
 	// Note, we pass a 'nil' channel here, we could pass a channel that
 	// closes if we wished to cleanup this routine on exit.
 	go kafkaMetrics.PollGoMetricsUntilStop(time.Minute, nil)
 	if isClusterType(bootstrapBlock) {
-		initializeEtcdraftConsenter(consenters, conf, lf, clusterDialer, bootstrapBlock, ri, srvConf, srv, registrar)
+		initializeEtcdraftConsenter(consenters, conf, lf, clusterDialer, bootstrapBlock, ri, srvConf, srv, registrar, metricsProvider, opsSystem)
 	}
 	registrar.Initialize(consenters)
 	return registrar
@@ -547,22 +595,27 @@ func initializeEtcdraftConsenter(
 	srvConf comm.ServerConfig,
 	srv *comm.GRPCServer,
 	registrar *multichannel.Registrar,
+	metricsProvider metrics.Provider,
+	admin adminServer,
 ) {
 	replicationRefreshInterval := conf.General.Cluster.ReplicationBackgroundRefreshInterval
 	if replicationRefreshInterval == 0 {
 		replicationRefreshInterval = defaultReplicationBackgroundRefreshInterval
 	}
 
-	systemChannelName, err := utils.GetChainIDFromBlock(bootstrapBlock)
-	if err != nil {
-		ri.logger.Panicf("Failed extracting system channel name from bootstrap block: %v", err)
-	}
-	systemLedger, err := lf.GetOrCreate(systemChannelName)
-	if err != nil {
-		ri.logger.Panicf("Failed obtaining system channel (%s) ledger: %v", systemChannelName, err)
-	}
-	getConfigBlock := func() *cb.Block {
-		return multichannel.ConfigBlock(systemLedger)
+	var getConfigBlock func() *cb.Block
+	if bootstrapBlock != nil {
+		systemChannelName, err := utils.GetChainIDFromBlock(bootstrapBlock)
+		if err != nil {
+			ri.logger.Panicf("Failed extracting system channel name from bootstrap block: %v", err)
+		}
+		systemLedger, err := lf.GetOrCreate(systemChannelName)
+		if err != nil {
+			ri.logger.Panicf("Failed obtaining system channel (%s) ledger: %v", systemChannelName, err)
+		}
+		getConfigBlock = func() *cb.Block {
+			return multichannel.ConfigBlock(systemLedger)
+		}
 	}
 
 	exponentialSleep := exponentialDurationSeries(replicationBackgroundInitialRefreshInterval, replicationRefreshInterval)
@@ -575,6 +628,13 @@ func initializeEtcdraftConsenter(
 		replicator:                        ri,
 		chains2CreationCallbacks:          make(map[string]chainCreation),
 		retrieveLastSysChannelConfigBlock: getConfigBlock,
+		retrieveLastConfigBlock: func(channelID string) *cb.Block {
+			ledger, err := lf.GetOrCreate(channelID)
+			if err != nil {
+				logger.Panicf("Failed obtaining the ledger of channel %s: %v", channelID, err)
+			}
+			return multichannel.ConfigBlock(ledger)
+		},
 	}
 
 	// Use the inactiveChainReplicator as a channel lister, since it has knowledge
@@ -583,9 +643,16 @@ func initializeEtcdraftConsenter(
 	// the channels in the system.
 	ri.channelLister = icr
 
+	// Inactive chains are replicated with the help of the system channel if there is one,
+	// and from the orderers of their own last config block otherwise
 	go icr.run()
-	raftConsenter := etcdraft.New(clusterDialer, conf, srvConf, srv, registrar, icr)
+	raftConsenter := etcdraft.New(clusterDialer, conf, srvConf, srv, registrar, icr, metricsProvider, createEncrypter(conf, conf.FileLedger.Location))
 	consenters["etcdraft"] = raftConsenter
+	if err := admin.RegisterHandler(etcdraft.AdminPath, etcdraft.NewAdminHandler(raftConsenter)); err != nil {
+		logger.Warningf("Raft administration endpoint is not served: %s", err)
+	}
+	// The BFT chains share the cluster communication of the etcdraft chains
+	consenters["bft"] = bft.New(clusterDialer, conf, srvConf, raftConsenter.Communication)
 }
 
 func newOperationsSystem(ops localconfig.Operations, metrics localconfig.Metrics) *operations.System {
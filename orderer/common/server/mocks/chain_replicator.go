@@ -10,6 +10,20 @@ type ChainReplicator struct {
 	mock.Mock
 }
 
+// PullChannel provides a mock function with given fields: channelID, lastConfigBlock
+func (_m *ChainReplicator) PullChannel(channelID string, lastConfigBlock *common.Block) error {
+	ret := _m.Called(channelID, lastConfigBlock)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string, *common.Block) error); ok {
+		r0 = rf(channelID, lastConfigBlock)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // ReplicateChains provides a mock function with given fields: lastConfigBlock, chains
 func (_m *ChainReplicator) ReplicateChains(lastConfigBlock *common.Block, chains []string) []string {
 	ret := _m.Called(lastConfigBlock, chains)
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
 )
 
 const (
@@ -115,6 +116,58 @@ func (ri *replicationInitiator) ReplicateChains(lastConfigBlock *common.Block, c
 	return replicator.ReplicateChains()
 }
 
+// ReplicateChannel pulls the blocks of the given channel which precede the given config block
+// from the orderers of the channel, and commits them along with the config block.
+func (ri *replicationInitiator) ReplicateChannel(channelID string, configBlock *common.Block) error {
+	pullerConfig := cluster.PullerConfigFromTopLevelConfig(channelID, ri.conf, ri.secOpts.Key, ri.secOpts.Certificate, ri.signer)
+	puller, err := cluster.BlockPullerFromConfigBlock(pullerConfig, configBlock)
+	if err != nil {
+		return errors.WithMessage(err, "failed creating a block puller from the config block")
+	}
+	defer puller.Close()
+	puller.MaxPullBlockRetries = uint64(ri.conf.General.Cluster.ReplicationMaxRetries)
+	puller.RetryTimeout = ri.conf.General.Cluster.ReplicationRetryTimeout
+
+	ledger, err := ri.lf.GetOrCreate(channelID)
+	if err != nil {
+		return errors.WithMessage(err, "failed obtaining the ledger")
+	}
+
+	ri.logger.Infof("Pulling the %d blocks of channel %s which precede its config block", configBlock.Header.Number, channelID)
+	return cluster.PullChannelUntil(puller, ledger, configBlock)
+}
+
+// PullChannel pulls the blocks of the given channel which its ledger lacks from the orderers of
+// the given config block of the channel, if this orderer is one of the consenters of the channel.
+func (ri *replicationInitiator) PullChannel(channelID string, lastConfigBlock *common.Block) error {
+	consenterCert := etcdraft.ConsenterCertificate(ri.secOpts.Certificate)
+	pullerConfig := cluster.PullerConfigFromTopLevelConfig(channelID, ri.conf, ri.secOpts.Key, ri.secOpts.Certificate, ri.signer)
+	puller, err := cluster.BlockPullerFromConfigBlock(pullerConfig, lastConfigBlock)
+	if err != nil {
+		return errors.WithMessage(err, "failed creating a block puller from the last config block")
+	}
+	defer puller.Close()
+	puller.MaxPullBlockRetries = uint64(ri.conf.General.Cluster.ReplicationMaxRetries)
+	puller.RetryTimeout = ri.conf.General.Cluster.ReplicationRetryTimeout
+
+	// Only a single block is needed to find out whether this orderer is in the channel
+	probe := puller.Clone()
+	probe.MaxTotalBufferBytes = 1
+	err = cluster.Participant(probe, consenterCert.IsConsenterOfChannel)
+	probe.Close()
+	if err != nil {
+		return err
+	}
+
+	replicator := &cluster.Replicator{
+		Filter:        cluster.AnyChannel,
+		LedgerFactory: ri.lf,
+		Logger:        ri.logger,
+		Puller:        puller,
+	}
+	return replicator.PullChannel(channelID)
+}
+
 type ledgerFactory struct {
 	blockledger.Factory
 }
@@ -130,12 +183,17 @@ type ChainReplicator interface {
 	// ReplicateChains replicates the given chains using the given last system channel config block.
 	// It returns the names of the chains that were successfully replicated.
 	ReplicateChains(lastConfigBlock *common.Block, chains []string) []string
+
+	// PullChannel pulls the blocks of the given channel using its given last config block, if this
+	// orderer is one of the consenters of the channel. It is used when there is no system channel.
+	PullChannel(channelID string, lastConfigBlock *common.Block) error
 }
 
 // inactiveChainReplicator tracks disabled chains and replicates them upon demand
 type inactiveChainReplicator struct {
 	logger                            *flogging.FabricLogger
 	retrieveLastSysChannelConfigBlock func() *common.Block
+	retrieveLastConfigBlock           func(channelID string) *common.Block
 	replicator                        ChainReplicator
 	scheduleChan                      <-chan time.Time
 	quitChan                          chan struct{}
@@ -179,6 +237,16 @@ func (dc *inactiveChainReplicator) TrackChain(chain string, genesisBlock *common
 	}
 }
 
+// UntrackChain stops tracking the chain with the given name, if it is tracked.
+func (dc *inactiveChainReplicator) UntrackChain(chain string) {
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
+	if _, exists := dc.chains2CreationCallbacks[chain]; exists {
+		dc.logger.Infof("Removing %s from the set of chains to track", chain)
+		delete(dc.chains2CreationCallbacks, chain)
+	}
+}
+
 func (dc *inactiveChainReplicator) run() {
 	for {
 		select {
@@ -197,18 +265,46 @@ func (dc *inactiveChainReplicator) replicateDisabledChains() {
 		return
 	}
 	dc.logger.Infof("Found %d inactive chains: %v", len(chains), chains)
-	lastSystemChannelConfigBlock := dc.retrieveLastSysChannelConfigBlock()
-	replicatedChains := dc.replicator.ReplicateChains(lastSystemChannelConfigBlock, chains)
+	var replicatedChains []string
+	if dc.retrieveLastSysChannelConfigBlock != nil {
+		lastSystemChannelConfigBlock := dc.retrieveLastSysChannelConfigBlock()
+		replicatedChains = dc.replicator.ReplicateChains(lastSystemChannelConfigBlock, chains)
+	} else {
+		replicatedChains = dc.pullChannels(chains)
+	}
 	dc.logger.Infof("Successfully replicated %d chains: %v", len(replicatedChains), replicatedChains)
 	dc.lock.Lock()
 	defer dc.lock.Unlock()
 	for _, chainName := range replicatedChains {
-		chain := dc.chains2CreationCallbacks[chainName]
+		chain, exists := dc.chains2CreationCallbacks[chainName]
+		if !exists {
+			// The chain was removed while it was replicated
+			continue
+		}
 		delete(dc.chains2CreationCallbacks, chainName)
 		chain.create()
 	}
 }
 
+// pullChannels pulls each of the given chains using its own last config block, as there is
+// no system channel, and returns the names of the chains this orderer is now a consenter of.
+func (dc *inactiveChainReplicator) pullChannels(chains []string) []string {
+	var replicatedChains []string
+	for _, chain := range chains {
+		err := dc.replicator.PullChannel(chain, dc.retrieveLastConfigBlock(chain))
+		if err == cluster.ErrNotInChannel {
+			dc.logger.Debugf("Not a consenter of channel %s yet", chain)
+			continue
+		}
+		if err != nil {
+			dc.logger.Warningf("Failed pulling channel %s: %v", chain, err)
+			continue
+		}
+		replicatedChains = append(replicatedChains, chain)
+	}
+	return replicatedChains
+}
+
 func (dc *inactiveChainReplicator) stop() {
 	close(dc.quitChan)
 }
@@ -802,6 +802,47 @@ func TestInactiveChainReplicator(t *testing.T) {
 	}
 }
 
+func TestInactiveChainReplicatorWithoutSystemChannel(t *testing.T) {
+	configBlocks := map[string]*common.Block{
+		"foo": {Header: &common.BlockHeader{Number: 1}},
+		"bar": {Header: &common.BlockHeader{Number: 2}},
+		"baz": {Header: &common.BlockHeader{Number: 3}},
+	}
+	replicator := &server_mocks.ChainReplicator{}
+	replicator.On("PullChannel", "foo", configBlocks["foo"]).Return(nil)
+	replicator.On("PullChannel", "bar", configBlocks["bar"]).Return(cluster.ErrNotInChannel)
+	replicator.On("PullChannel", "baz", configBlocks["baz"]).Return(errors.New("unreachable"))
+
+	icr := &inactiveChainReplicator{
+		logger:                   flogging.MustGetLogger("test"),
+		replicator:               replicator,
+		chains2CreationCallbacks: make(map[string]chainCreation),
+		retrieveLastConfigBlock: func(channelID string) *common.Block {
+			return configBlocks[channelID]
+		},
+	}
+
+	var createdChains []string
+	for _, chain := range []string{"foo", "bar", "baz"} {
+		chain := chain
+		icr.TrackChain(chain, &common.Block{}, func() {
+			createdChains = append(createdChains, chain)
+		})
+	}
+
+	icr.replicateDisabledChains()
+	assert.Equal(t, []string{"foo"}, createdChains)
+	assert.ElementsMatch(t, []string{"bar", "baz"}, icr.listInactiveChains())
+	replicator.AssertNumberOfCalls(t, "PullChannel", 3)
+	replicator.AssertNotCalled(t, "ReplicateChains", mock.Anything, mock.Anything)
+
+	// A removed chain is no longer replicated
+	icr.UntrackChain("bar")
+	icr.UntrackChain("baz")
+	icr.replicateDisabledChains()
+	replicator.AssertNumberOfCalls(t, "PullChannel", 3)
+}
+
 func TestInactiveChainReplicatorChannels(t *testing.T) {
 	icr := &inactiveChainReplicator{
 		logger:                   flogging.MustGetLogger("test"),
@@ -72,12 +72,13 @@ func (rs *responseSender) SendBlockResponse(block *cb.Block) error {
 }
 
 // NewServer creates an ab.AtomicBroadcastServer based on the broadcast target and ledger Reader
-func NewServer(r *multichannel.Registrar, metricsProvider metrics.Provider, debug *localconfig.Debug, timeWindow time.Duration, mutualTLS bool) ab.AtomicBroadcastServer {
+func NewServer(r *multichannel.Registrar, metricsProvider metrics.Provider, debug *localconfig.Debug, timeWindow time.Duration, mutualTLS bool, throttling localconfig.Throttling) ab.AtomicBroadcastServer {
 	s := &server{
 		dh: deliver.NewHandler(deliverSupport{Registrar: r}, timeWindow, mutualTLS, deliver.NewMetrics(metricsProvider)),
 		bh: &broadcast.Handler{
 			SupportRegistrar: broadcastSupport{Registrar: r},
 			Metrics:          broadcast.NewMetrics(metricsProvider),
+			Throttle:         newThrottle(throttling),
 		},
 		debug:     debug,
 		Registrar: r,
@@ -85,6 +86,19 @@ func NewServer(r *multichannel.Registrar, metricsProvider metrics.Provider, debu
 	return s
 }
 
+// newThrottle returns the broadcast.Throttle enforcing the given limits, or nil if none is enabled.
+func newThrottle(throttling localconfig.Throttling) *broadcast.Throttle {
+	if throttling.Org.Rate <= 0 && throttling.Client.Rate <= 0 {
+		return nil
+	}
+	logger.Infof("Throttling broadcast at %v transactions per second per organization and %v per client",
+		throttling.Org.Rate, throttling.Client.Rate)
+	return broadcast.NewThrottle(
+		broadcast.Limit{Rate: throttling.Org.Rate, Burst: throttling.Org.Burst},
+		broadcast.Limit{Rate: throttling.Client.Rate, Burst: throttling.Client.Burst},
+	)
+}
+
 type msgTracer struct {
 	function string
 	debug    *localconfig.Debug
@@ -7,10 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package server
 
 import (
+	"encoding/hex"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/ledger/blockledger/file"
@@ -19,6 +22,10 @@ import (
 	config "github.com/hyperledger/fabric/orderer/common/localconfig"
 )
 
+// encryptionDataKeysFile holds, in the ledger directory, the data keys wrapped by the keys
+// of the HSM when the BCCSP is PKCS11.
+const encryptionDataKeysFile = "encryptionDataKeys"
+
 func createLedgerFactory(conf *config.TopLevel) (blockledger.Factory, string) {
 	var lf blockledger.Factory
 	var ld string
@@ -29,7 +36,7 @@ func createLedgerFactory(conf *config.TopLevel) (blockledger.Factory, string) {
 			ld = createTempDir(conf.FileLedger.Prefix)
 		}
 		logger.Debug("Ledger dir:", ld)
-		lf = fileledger.New(ld)
+		lf = fileledger.New(ld, fileLedgerCompression(conf.FileLedger.Compression), createEncrypter(conf, ld))
 		// The file-based ledger stores the blocks for each channel
 		// in a fsblkstorage.ChainsDir sub-directory that we have
 		// to create separately. Otherwise the call to the ledger
@@ -73,3 +80,41 @@ func createSubDir(parentDirPath string, subDir string) (string, bool) {
 	}
 	return subDirPath, created
 }
+
+func fileLedgerCompression(conf config.FileLedgerCompression) fsblkstorage.CompressionConf {
+	compression := fsblkstorage.CompressionConf{
+		Default: fsblkstorage.Compression(conf.Default),
+		Ledgers: make(map[string]fsblkstorage.Compression),
+	}
+	for channelID, channelCompression := range conf.Channels {
+		compression.Ledgers[channelID] = fsblkstorage.Compression(channelCompression)
+	}
+	return compression
+}
+
+// createEncrypter returns the encrypter of the data at rest, which looks the keys
+// up in the keystore of the default BCCSP, or nil if the encryption is not enabled.
+// With the PKCS11 BCCSP, the key held by the HSM wraps the data keys the data is
+// encrypted with, which are kept in the given ledger directory.
+func createEncrypter(conf *config.TopLevel, ledgerDir string) *encryption.Encrypter {
+	if !conf.General.Encryption.Enabled {
+		return nil
+	}
+	ski, err := hex.DecodeString(conf.General.Encryption.Key)
+	if err != nil || len(ski) == 0 {
+		logger.Panicf("Invalid encryption key [%s], it should be the hex encoded subject key identifier of a key", conf.General.Encryption.Key)
+	}
+	csp := factory.GetDefault()
+	keys := encryption.NewKeyProvider(csp, ski)
+	if conf.General.BCCSP != nil && conf.General.BCCSP.ProviderName == "PKCS11" {
+		if ledgerDir == "" {
+			logger.Panic("FileLedger.Location must be set to keep the data keys wrapped by the PKCS11 BCCSP")
+		}
+		keys = encryption.NewWrappedKeyProvider(csp, ski, filepath.Join(ledgerDir, encryptionDataKeysFile))
+	}
+	encrypter, err := encryption.NewEncrypter(csp, keys)
+	if err != nil {
+		logger.Panicf("Failed to initialize the encryption: %s", err)
+	}
+	return encrypter
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	config "github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/stretchr/testify/assert"
@@ -121,3 +122,16 @@ func TestCreateTempDir(t *testing.T) {
 	})
 
 }
+
+func TestCreateEncrypter(t *testing.T) {
+	conf := &config.TopLevel{}
+	assert.Nil(t, createEncrypter(conf, ""))
+
+	conf.General.Encryption = config.Encryption{Enabled: true, Key: "not hex"}
+	assert.Panics(t, func() { createEncrypter(conf, "") })
+
+	// With the PKCS11 BCCSP, the data keys are kept in the ledger directory
+	conf.General.Encryption.Key = "0123"
+	conf.General.BCCSP = &factory.FactoryOpts{ProviderName: "PKCS11"}
+	assert.PanicsWithValue(t, "FileLedger.Location must be set to keep the data keys wrapped by the PKCS11 BCCSP", func() { createEncrypter(conf, "") })
+}
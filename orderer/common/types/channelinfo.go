@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package types holds the types which are exchanged through the channel participation API.
+package types
+
+// ChannelStatus is the status of a channel on an orderer.
+type ChannelStatus string
+
+const (
+	// StatusOnboarding means the blocks which precede the join block are being pulled.
+	StatusOnboarding ChannelStatus = "onboarding"
+	// StatusActive means the channel is serviced by the orderer.
+	StatusActive ChannelStatus = "active"
+	// StatusInactive means the orderer is not a consenter of the channel.
+	StatusInactive ChannelStatus = "inactive"
+	// StatusFailed means the channel could not be onboarded.
+	StatusFailed ChannelStatus = "failed"
+)
+
+// ChannelInfoShort refers to a channel in a ChannelList.
+type ChannelInfoShort struct {
+	Name string `json:"name"`
+	// URL is the path of the channel's ChannelInfo.
+	URL string `json:"url"`
+}
+
+// ChannelList lists the channels of an orderer.
+type ChannelList struct {
+	// SystemChannel is nil if the orderer has no system channel.
+	SystemChannel *ChannelInfoShort  `json:"systemChannel"`
+	Channels      []ChannelInfoShort `json:"channels"`
+}
+
+// ChannelInfo is the state of a channel on an orderer.
+type ChannelInfo struct {
+	Name   string        `json:"name"`
+	URL    string        `json:"url"`
+	Status ChannelStatus `json:"status"`
+	// Error is the reason a channel could not be onboarded.
+	Error  string `json:"error,omitempty"`
+	Height uint64 `json:"height"`
+}
@@ -0,0 +1,28 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package types
+
+import "github.com/pkg/errors"
+
+var (
+	// ErrSystemChannelExists is returned when channels are joined or removed
+	// by an orderer which has a system channel.
+	ErrSystemChannelExists = errors.New("system channel exists")
+
+	// ErrChannelAlreadyExists is returned when a channel is joined twice.
+	ErrChannelAlreadyExists = errors.New("channel already exists")
+
+	// ErrChannelNotExist is returned when a channel is not known to the orderer.
+	ErrChannelNotExist = errors.New("channel does not exist")
+
+	// ErrChannelOnboarding is returned when a channel is removed while it is onboarded.
+	ErrChannelOnboarding = errors.New("channel is being onboarded")
+
+	// ErrChannelParticipationDisabled is returned when channels are joined or
+	// removed by an orderer on which the channel participation API is disabled.
+	ErrChannelParticipationDisabled = errors.New("channel participation is disabled")
+)
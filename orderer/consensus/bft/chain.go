@@ -0,0 +1,1356 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultRequestTimeout is the time a request may stay unordered before the leader is suspected.
+	DefaultRequestTimeout = 10 * time.Second
+	// DefaultViewChangeTimeout is the time a view change may take before the next view is tried.
+	DefaultViewChangeTimeout = 20 * time.Second
+
+	// Size of the queues of the messages received from and sent to the other nodes
+	messageBufferSize = 1000
+
+	// Number of sequences ahead of the current one for which messages are kept
+	futureWindow = 10
+)
+
+//go:generate counterfeiter -o mocks/mock_rpc.go . RPC
+
+// RPC is used to mock the transport layer in tests.
+type RPC interface {
+	Step(dest uint64, msg *orderer.StepRequest) (*orderer.StepResponse, error)
+	SendSubmit(dest uint64, request *orderer.SubmitRequest) error
+}
+
+//go:generate counterfeiter -o mocks/mock_blockpuller.go . BlockPuller
+
+// BlockPuller is used to pull blocks from other OSN
+type BlockPuller interface {
+	PullBlock(seq uint64) *common.Block
+	Close()
+}
+
+//go:generate counterfeiter -o mocks/mock_configurator.go . Configurator
+
+// Configurator is used to configure the communication layer
+// when the chain starts.
+type Configurator interface {
+	Configure(channel string, newNodes []cluster.RemoteNode)
+}
+
+// Options contains all the configurations relevant to the chain.
+type Options struct {
+	SelfID uint64
+
+	Clock  clock.Clock
+	Logger *flogging.FabricLogger
+
+	RequestTimeout    time.Duration
+	ViewChangeTimeout time.Duration
+
+	Membership *Membership
+
+	// View in which the last block was decided
+	View uint64
+}
+
+// request is a transaction submitted to the chain, either by a client of this node
+// or forwarded by another node.
+type request struct {
+	req   *orderer.SubmitRequest
+	local bool
+}
+
+type pendingRequest struct {
+	req      *orderer.SubmitRequest
+	received time.Time
+}
+
+// batch is a set of envelopes cut by the leader, to be proposed as a block.
+type batch struct {
+	envs      []*common.Envelope
+	keys      []string
+	config    bool
+	configSeq uint64
+}
+
+type openedMessage struct {
+	sm  *bft.SignedMessage
+	msg *bft.Message
+}
+
+// round is the state of the agreement on the block of a sequence in a view.
+type round struct {
+	seq        uint64
+	view       uint64
+	prePrepare *bft.SignedMessage
+	proposal   *common.Block
+	digest     []byte
+	prepares   map[uint64]*bft.SignedMessage
+	commits    map[uint64]*bft.Commit
+	prepared   bool
+}
+
+func newRound(seq, view uint64) *round {
+	return &round{
+		seq:      seq,
+		view:     view,
+		prepares: make(map[uint64]*bft.SignedMessage),
+		commits:  make(map[uint64]*bft.Commit),
+	}
+}
+
+// Chain implements consensus.Chain interface with a PBFT-style protocol which
+// tolerates f byzantine nodes out of 3f+1. The leader of a view cuts the blocks
+// and proposes them one at a time; a block is written once a quorum of nodes
+// committed it, along with the signatures of the quorum.
+type Chain struct {
+	configurator Configurator
+	rpc          RPC
+	puller       BlockPuller
+
+	support   consensus.ConsenterSupport
+	channelID string
+	opts      Options
+	selfID    uint64
+	logger    *flogging.FabricLogger
+	clock     clock.Clock
+
+	submitC chan *request
+	stepC   chan *bft.SignedMessage
+	startC  chan struct{}
+	haltC   chan struct{}
+	doneC   chan struct{}
+
+	// The fields below are only accessed by the serving goroutine
+	membership   *Membership
+	egress       map[uint64]chan func()
+	view         uint64
+	lastBlock    *common.Block
+	round        *round
+	future       []*openedMessage
+	preparedCert *bft.PreparedCertificate
+
+	inViewChange    bool
+	nextView        uint64
+	viewChangeStart time.Time
+	viewChanges     map[uint64]map[uint64]*bft.SignedMessage
+	newViewSent     map[uint64]bool
+	expectedDigest  []byte
+	ahead           map[uint64]uint64
+
+	pending    map[string]*pendingRequest
+	decided    map[string]time.Time
+	inQueue    map[string]bool
+	queue      []*batch
+	batchKeys  map[*common.Envelope]string
+	batchTimer clock.Timer
+}
+
+// NewChain constructs a chain object.
+func NewChain(
+	support consensus.ConsenterSupport,
+	opts Options,
+	conf Configurator,
+	rpc RPC,
+	puller BlockPuller,
+) (*Chain, error) {
+	if !opts.Membership.Contains(opts.SelfID) {
+		return nil, errors.Errorf("node %d is not a consenter of channel %s", opts.SelfID, support.ChainID())
+	}
+
+	lastBlock := support.Block(support.Height() - 1)
+	if lastBlock == nil {
+		return nil, errors.Errorf("failed to retrieve block %d", support.Height()-1)
+	}
+
+	if opts.RequestTimeout == 0 {
+		opts.RequestTimeout = DefaultRequestTimeout
+	}
+	if opts.ViewChangeTimeout == 0 {
+		opts.ViewChangeTimeout = DefaultViewChangeTimeout
+	}
+
+	lg := opts.Logger.With("channel", support.ChainID(), "node", opts.SelfID)
+
+	c := &Chain{
+		configurator: conf,
+		rpc:          rpc,
+		puller:       puller,
+		support:      support,
+		channelID:    support.ChainID(),
+		opts:         opts,
+		selfID:       opts.SelfID,
+		logger:       lg,
+		clock:        opts.Clock,
+		submitC:      make(chan *request, messageBufferSize),
+		stepC:        make(chan *bft.SignedMessage, messageBufferSize),
+		startC:       make(chan struct{}),
+		haltC:        make(chan struct{}),
+		doneC:        make(chan struct{}),
+		membership:   opts.Membership,
+		egress:       make(map[uint64]chan func()),
+		view:         opts.View,
+		nextView:     opts.View,
+		lastBlock:    lastBlock,
+		viewChanges:  make(map[uint64]map[uint64]*bft.SignedMessage),
+		newViewSent:  make(map[uint64]bool),
+		ahead:        make(map[uint64]uint64),
+		pending:      make(map[string]*pendingRequest),
+		decided:      make(map[string]time.Time),
+		inQueue:      make(map[string]bool),
+		batchKeys:    make(map[*common.Envelope]string),
+	}
+	c.round = newRound(lastBlock.Header.Number+1, c.view)
+
+	return c, nil
+}
+
+// Start instructs the orderer to begin serving the chain and keep it current.
+func (c *Chain) Start() {
+	c.logger.Infof("Starting BFT node in view %d at height %d", c.view, c.lastBlock.Header.Number+1)
+
+	if err := c.configureComm(); err != nil {
+		c.logger.Errorf("Failed to start chain, aborting: +%v", err)
+		close(c.doneC)
+		return
+	}
+
+	close(c.startC)
+	go c.serveRequest()
+}
+
+// Order submits normal type transactions for ordering.
+func (c *Chain) Order(env *common.Envelope, configSeq uint64) error {
+	return c.submit(&request{
+		req:   &orderer.SubmitRequest{LastValidationSeq: configSeq, Content: env, Channel: c.channelID},
+		local: true,
+	})
+}
+
+// Configure submits config type transactions for ordering.
+func (c *Chain) Configure(env *common.Envelope, configSeq uint64) error {
+	if err := verifyBlockValidationPolicy(env); err != nil {
+		return err
+	}
+	return c.submit(&request{
+		req:   &orderer.SubmitRequest{LastValidationSeq: configSeq, Content: env, Channel: c.channelID},
+		local: true,
+	})
+}
+
+// WaitReady returns an error if the chain is stopped, and nil otherwise.
+func (c *Chain) WaitReady() error {
+	return c.isRunning()
+}
+
+// Errored returns a channel that closes when the chain stops.
+func (c *Chain) Errored() <-chan struct{} {
+	return c.doneC
+}
+
+// Halt stops the chain.
+func (c *Chain) Halt() {
+	select {
+	case <-c.startC:
+	default:
+		c.logger.Warning("Attempted to halt a chain that has not started")
+		return
+	}
+
+	select {
+	case c.haltC <- struct{}{}:
+	case <-c.doneC:
+		return
+	}
+	<-c.doneC
+
+	if c.puller != nil {
+		c.puller.Close()
+	}
+}
+
+// Submit forwards the incoming request to the chain, which orders it if it is the leader.
+func (c *Chain) Submit(req *orderer.SubmitRequest, sender uint64) error {
+	if req.Content == nil {
+		return errors.Errorf("request from node %d has no content", sender)
+	}
+	return c.submit(&request{req: req})
+}
+
+// Step passes the given consensus message of another node to the chain.
+func (c *Chain) Step(req *orderer.StepRequest, sender uint64) error {
+	sm := &bft.SignedMessage{}
+	if err := proto.Unmarshal(req.Payload, sm); err != nil {
+		return errors.Wrap(err, "failed to unmarshal StepRequest payload to BFT message")
+	}
+	if sm.Sender != sender {
+		return errors.Errorf("message of node %d was sent by node %d", sm.Sender, sender)
+	}
+
+	if err := c.isRunning(); err != nil {
+		return err
+	}
+
+	select {
+	case c.stepC <- sm:
+		return nil
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	}
+}
+
+func (c *Chain) submit(r *request) error {
+	if err := c.isRunning(); err != nil {
+		return err
+	}
+
+	select {
+	case c.submitC <- r:
+		return nil
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	}
+}
+
+func (c *Chain) isRunning() error {
+	select {
+	case <-c.startC:
+	default:
+		return errors.Errorf("chain is not started")
+	}
+
+	select {
+	case <-c.doneC:
+		return errors.Errorf("chain is stopped")
+	default:
+	}
+
+	return nil
+}
+
+func (c *Chain) serveRequest() {
+	ticker := c.clock.NewTicker(c.opts.RequestTimeout / 10)
+	defer ticker.Stop()
+
+	for {
+		var batchTimeoutC <-chan time.Time
+		if c.batchTimer != nil {
+			batchTimeoutC = c.batchTimer.C()
+		}
+
+		select {
+		case r := <-c.submitC:
+			c.onRequest(r)
+
+		case sm := <-c.stepC:
+			c.onMessage(sm)
+
+		case <-batchTimeoutC:
+			c.batchTimer = nil
+			if batch := c.support.BlockCutter().Cut(); len(batch) > 0 {
+				c.logger.Debugf("Batch timer expired, cutting %d envelopes", len(c.batchKeys))
+				for ; len(batch) > 0; batch = c.support.BlockCutter().Cut() {
+					c.enqueueCut(batch)
+				}
+				c.propose()
+			}
+
+		case <-ticker.C():
+			c.checkTimeouts()
+
+		case <-c.haltC:
+			c.stopBatchTimer()
+			close(c.doneC)
+			c.logger.Infof("Stop serving requests")
+			return
+		}
+	}
+}
+
+// requestKey identifies a transaction across the nodes.
+func requestKey(env *common.Envelope) string {
+	h := sha256.New()
+	h.Write(env.Payload)
+	h.Write(env.Signature)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Chain) isLeader() bool {
+	return c.membership.Leader(c.view) == c.selfID
+}
+
+func (c *Chain) onRequest(r *request) {
+	key := requestKey(r.req.Content)
+	if _, decided := c.decided[key]; decided {
+		return
+	}
+
+	if !r.local {
+		// A request relayed by another node is validated, as the node may be faulty
+		if err := c.validate(r.req); err != nil {
+			c.logger.Warningf("Discarding request relayed by another node: %s", err)
+			return
+		}
+	}
+
+	if _, exists := c.pending[key]; !exists {
+		c.pending[key] = &pendingRequest{req: r.req, received: c.clock.Now()}
+	}
+
+	if c.inViewChange {
+		return
+	}
+
+	if c.isLeader() {
+		c.order(key, r.req)
+		c.propose()
+		return
+	}
+
+	if r.local {
+		// The request is relayed to every node, so that enough of them suspect
+		// a leader which does not order it
+		for _, id := range c.membership.IDs {
+			if id != c.selfID {
+				c.forward(id, r.req)
+			}
+		}
+	}
+}
+
+func (c *Chain) validate(req *orderer.SubmitRequest) error {
+	chdr, err := utils.ChannelHeader(req.Content)
+	if err != nil {
+		return errors.WithMessage(err, "bad channel header")
+	}
+	if c.support.ClassifyMsg(chdr) != msgprocessor.ConfigMsg {
+		req.LastValidationSeq, err = c.support.ProcessNormalMsg(req.Content)
+		return err
+	}
+
+	configEnv, seq, err := c.support.ProcessConfigMsg(req.Content)
+	if err != nil {
+		return err
+	}
+	req.LastValidationSeq = seq
+	return verifyBlockValidationPolicy(configEnv)
+}
+
+func (c *Chain) forward(dest uint64, req *orderer.SubmitRequest) {
+	c.send(dest, func() {
+		if err := c.rpc.SendSubmit(dest, req); err != nil {
+			c.logger.Warningf("Failed to forward request to node %d: %s", dest, err)
+		}
+	})
+}
+
+// order passes a request to the block cutter of the leader.
+func (c *Chain) order(key string, req *orderer.SubmitRequest) {
+	if c.inQueue[key] {
+		return
+	}
+
+	env := req.Content
+	seq := c.support.Sequence()
+
+	chdr, err := utils.ChannelHeader(env)
+	if err != nil {
+		c.logger.Warningf("Discarding request with bad channel header: %s", err)
+		delete(c.pending, key)
+		return
+	}
+
+	if c.support.ClassifyMsg(chdr) == msgprocessor.ConfigMsg {
+		if req.LastValidationSeq < seq {
+			c.logger.Warningf("Config sequence has advanced since this config message got validated, re-validating")
+			if env, _, err = c.support.ProcessConfigMsg(env); err == nil {
+				err = verifyBlockValidationPolicy(env)
+			}
+			if err != nil {
+				c.logger.Warningf("Discarding bad config message: %s", err)
+				delete(c.pending, key)
+				return
+			}
+		}
+
+		c.stopBatchTimer()
+		for batch := c.support.BlockCutter().Cut(); len(batch) > 0; batch = c.support.BlockCutter().Cut() {
+			c.enqueueCut(batch)
+		}
+		c.inQueue[key] = true
+		c.enqueue([]*common.Envelope{env}, []string{key}, true)
+		return
+	}
+
+	if req.LastValidationSeq < seq {
+		if _, err := c.support.ProcessNormalMsg(env); err != nil {
+			c.logger.Warningf("Discarding bad normal message: %s", err)
+			delete(c.pending, key)
+			return
+		}
+	}
+
+	c.inQueue[key] = true
+	c.batchKeys[env] = key
+	batches, pending := c.support.BlockCutter().Ordered(env)
+	for _, batch := range batches {
+		c.enqueueCut(batch)
+	}
+
+	if !pending {
+		c.stopBatchTimer()
+		return
+	}
+	if c.batchTimer == nil {
+		c.batchTimer = c.clock.NewTimer(c.support.SharedConfig().BatchTimeout())
+	}
+}
+
+func (c *Chain) stopBatchTimer() {
+	if c.batchTimer != nil {
+		c.batchTimer.Stop()
+		c.batchTimer = nil
+	}
+}
+
+// enqueueCut enqueues a batch cut by the block cutter along with the keys of its envelopes,
+// which the block cutter may have reordered.
+func (c *Chain) enqueueCut(envs []*common.Envelope) {
+	keys := make([]string, len(envs))
+	for i, env := range envs {
+		keys[i] = c.batchKeys[env]
+		delete(c.batchKeys, env)
+	}
+	c.enqueue(envs, keys, false)
+}
+
+func (c *Chain) enqueue(envs []*common.Envelope, keys []string, config bool) {
+	c.queue = append(c.queue, &batch{
+		envs:      envs,
+		keys:      keys,
+		config:    config,
+		configSeq: c.support.Sequence(),
+	})
+}
+
+// dropQueue discards the batches of a former leader, which are ordered by the new leader
+// as the nodes which received them forward them again.
+func (c *Chain) dropQueue() {
+	c.stopBatchTimer()
+	blockcutter.Drain(c.support.BlockCutter())
+	c.batchKeys = make(map[*common.Envelope]string)
+	c.queue = nil
+	c.inQueue = make(map[string]bool)
+}
+
+// propose proposes the next block if this node is the leader and no block is in flight.
+func (c *Chain) propose() {
+	for c.isLeader() && !c.inViewChange && c.round.prePrepare == nil && len(c.queue) > 0 {
+		b := c.undecided(c.queue[0])
+		c.queue = c.queue[1:]
+
+		envs := b.envs
+		if b.configSeq < c.support.Sequence() {
+			envs = c.revalidate(b)
+		}
+		if len(envs) == 0 {
+			continue
+		}
+
+		c.sendPrePrepare(c.newBlock(envs))
+	}
+}
+
+// undecided returns the batch without the requests that were decided since it was cut,
+// as a proposal selected during a view change may have included them.
+func (c *Chain) undecided(b *batch) *batch {
+	filtered := &batch{config: b.config, configSeq: b.configSeq}
+	for i, key := range b.keys {
+		if _, decided := c.decided[key]; decided {
+			continue
+		}
+		filtered.envs = append(filtered.envs, b.envs[i])
+		filtered.keys = append(filtered.keys, key)
+	}
+	return filtered
+}
+
+// revalidate re-validates the envelopes of a batch cut before the config sequence advanced.
+func (c *Chain) revalidate(b *batch) []*common.Envelope {
+	var envs []*common.Envelope
+	for i, env := range b.envs {
+		var err error
+		if b.config {
+			env, _, err = c.support.ProcessConfigMsg(env)
+		} else {
+			_, err = c.support.ProcessNormalMsg(env)
+		}
+		if err != nil {
+			c.logger.Warningf("Discarding message which became invalid: %s", err)
+			delete(c.pending, b.keys[i])
+			delete(c.inQueue, b.keys[i])
+			continue
+		}
+		envs = append(envs, env)
+	}
+	return envs
+}
+
+func (c *Chain) newBlock(envs []*common.Envelope) *common.Block {
+	data := &common.BlockData{Data: make([][]byte, len(envs))}
+	for i, env := range envs {
+		data.Data[i] = utils.MarshalOrPanic(env)
+	}
+
+	block := common.NewBlock(c.lastBlock.Header.Number+1, c.lastBlock.Header.Hash())
+	block.Header.DataHash = data.Hash()
+	block.Data = data
+	return block
+}
+
+func (c *Chain) sendPrePrepare(block *common.Block) {
+	pp := &bft.PrePrepare{View: c.view, Seq: block.Header.Number, Proposal: block}
+	sm, err := c.broadcast(&bft.Message{Type: &bft.Message_PrePrepare{PrePrepare: pp}})
+	if err != nil {
+		c.logger.Errorf("Failed to propose block %d: %s", block.Header.Number, err)
+		return
+	}
+	c.logger.Debugf("Proposed block %d with %d transactions in view %d", block.Header.Number, len(block.Data.Data), c.view)
+	c.onPrePrepare(sm, pp)
+}
+
+// broadcast signs a message and sends it to every other node.
+func (c *Chain) broadcast(msg *bft.Message) (*bft.SignedMessage, error) {
+	sm, err := signMessage(c.support, c.selfID, msg)
+	if err != nil {
+		return nil, err
+	}
+	payload := utils.MarshalOrPanic(sm)
+
+	for _, id := range c.membership.IDs {
+		if id == c.selfID {
+			continue
+		}
+		dest := id
+		c.send(dest, func() {
+			if _, err := c.rpc.Step(dest, &orderer.StepRequest{Channel: c.channelID, Payload: payload}); err != nil {
+				c.logger.Debugf("Failed to send message to node %d: %s", dest, err)
+			}
+		})
+	}
+	return sm, nil
+}
+
+// send runs a function on the egress queue of a node, so that the messages
+// to each node are sent in order without blocking the chain.
+func (c *Chain) send(dest uint64, f func()) {
+	q, exists := c.egress[dest]
+	if !exists {
+		q = make(chan func(), messageBufferSize)
+		c.egress[dest] = q
+		go func() {
+			for {
+				select {
+				case f := <-q:
+					f()
+				case <-c.doneC:
+					return
+				}
+			}
+		}()
+	}
+
+	select {
+	case q <- f:
+	default:
+		c.logger.Warningf("Egress queue of node %d is full, dropping message", dest)
+	}
+}
+
+func (c *Chain) onMessage(sm *bft.SignedMessage) {
+	msg, err := openMessage(c.membership, sm)
+	if err != nil {
+		c.logger.Warningf("Discarding message of node %d: %s", sm.Sender, err)
+		return
+	}
+
+	if view, seq, isRoundMsg := roundOf(msg); isRoundMsg && (view > c.view || view == c.view && seq > c.round.seq) {
+		// The message is processed once its view is installed and the blocks preceding its sequence are decided
+		if seq <= c.round.seq+futureWindow && len(c.future) < messageBufferSize {
+			c.future = append(c.future, &openedMessage{sm: sm, msg: msg})
+		}
+		c.noteSeq(sm.Sender, seq-1)
+		return
+	}
+
+	c.dispatch(sm, msg)
+}
+
+func (c *Chain) dispatch(sm *bft.SignedMessage, msg *bft.Message) {
+	switch m := msg.Type.(type) {
+	case *bft.Message_PrePrepare:
+		c.onPrePrepare(sm, m.PrePrepare)
+	case *bft.Message_Prepare:
+		c.onPrepare(sm, m.Prepare)
+	case *bft.Message_Commit:
+		c.onCommit(sm.Sender, m.Commit)
+	case *bft.Message_ViewChange:
+		c.noteSeq(sm.Sender, m.ViewChange.LastDecidedSeq)
+		c.onViewChange(sm, m.ViewChange)
+	case *bft.Message_NewView:
+		c.onNewView(sm, m.NewView)
+	default:
+		c.logger.Warningf("Discarding message of unknown type %T of node %d", m, sm.Sender)
+	}
+}
+
+// roundOf returns the view and sequence of the messages exchanged to agree on a block.
+func roundOf(msg *bft.Message) (view uint64, seq uint64, isRoundMsg bool) {
+	switch m := msg.Type.(type) {
+	case *bft.Message_PrePrepare:
+		return m.PrePrepare.View, m.PrePrepare.Seq, true
+	case *bft.Message_Prepare:
+		return m.Prepare.View, m.Prepare.Seq, true
+	case *bft.Message_Commit:
+		return m.Commit.View, m.Commit.Seq, true
+	default:
+		return 0, 0, false
+	}
+}
+
+// startRound starts the agreement on the next block, and processes the messages
+// about it which were received in advance.
+func (c *Chain) startRound() {
+	c.round = newRound(c.lastBlock.Header.Number+1, c.view)
+	c.replayFuture()
+}
+
+// replayFuture dispatches the buffered messages of the current round, and discards
+// the ones of rounds that are over.
+func (c *Chain) replayFuture() {
+	future := c.future
+	c.future = nil
+	for _, om := range future {
+		view, seq, _ := roundOf(om.msg)
+		switch {
+		case view < c.view || view == c.view && seq < c.round.seq:
+		case view == c.view && seq == c.round.seq:
+			c.dispatch(om.sm, om.msg)
+		default:
+			c.future = append(c.future, om)
+		}
+	}
+}
+
+// noteSeq records the sequence decided by a node according to its messages, and
+// synchronizes the chain once enough nodes are ahead for one of them to be correct.
+// A lag of a single block is caught up through the messages of the next round.
+func (c *Chain) noteSeq(sender, seq uint64) {
+	last := c.lastBlock.Header.Number
+	if seq <= last+1 {
+		return
+	}
+	if seq > c.ahead[sender] {
+		c.ahead[sender] = seq
+	}
+
+	var target uint64
+	var count int
+	for _, s := range c.ahead {
+		if s > last+1 {
+			count++
+			if target == 0 || s < target {
+				target = s
+			}
+		}
+	}
+	if count > bft.MaxFaulty(len(c.membership.IDs)) {
+		c.sync(target)
+	}
+}
+
+func (c *Chain) onPrePrepare(sm *bft.SignedMessage, pp *bft.PrePrepare) {
+	if c.inViewChange || pp.View != c.view || pp.Seq != c.round.seq {
+		return
+	}
+	if sm.Sender != c.membership.Leader(pp.View) {
+		c.logger.Warningf("Discarding pre-prepare of node %d, which is not the leader of view %d", sm.Sender, pp.View)
+		return
+	}
+
+	if c.round.prePrepare != nil {
+		if pp.Proposal == nil || pp.Proposal.Header == nil || !bytes.Equal(pp.Proposal.Header.Hash(), c.round.digest) {
+			c.logger.Warningf("Leader %d proposed two blocks for sequence %d in view %d", sm.Sender, pp.Seq, pp.View)
+			c.startViewChange(c.view + 1)
+		}
+		return
+	}
+
+	if err := c.verifyProposal(pp.Proposal); err != nil {
+		c.logger.Warningf("Leader %d proposed an invalid block: %s", sm.Sender, err)
+		c.startViewChange(c.view + 1)
+		return
+	}
+
+	digest := pp.Proposal.Header.Hash()
+	if c.expectedDigest != nil && !bytes.Equal(digest, c.expectedDigest) {
+		c.logger.Warningf("Leader %d did not propose again the block prepared in the former view", sm.Sender)
+		c.startViewChange(c.view + 1)
+		return
+	}
+
+	c.round.prePrepare = sm
+	c.round.proposal = pp.Proposal
+	c.round.digest = digest
+
+	prepare := &bft.Prepare{View: pp.View, Seq: pp.Seq, Digest: digest}
+	psm, err := c.broadcast(&bft.Message{Type: &bft.Message_Prepare{Prepare: prepare}})
+	if err != nil {
+		c.logger.Errorf("Failed to prepare block %d: %s", pp.Seq, err)
+		return
+	}
+	c.onPrepare(psm, prepare)
+	c.maybeDecide()
+}
+
+func (c *Chain) onPrepare(sm *bft.SignedMessage, p *bft.Prepare) {
+	if p.View != c.round.view || p.Seq != c.round.seq {
+		return
+	}
+	c.round.prepares[sm.Sender] = sm
+
+	if c.round.proposal == nil || c.round.prepared {
+		return
+	}
+
+	var prepares []*bft.SignedMessage
+	for _, psm := range c.round.prepares {
+		msg, _ := openMessage(c.membership, psm)
+		if bytes.Equal(msg.GetPrepare().Digest, c.round.digest) {
+			prepares = append(prepares, psm)
+		}
+	}
+	if len(prepares) < c.membership.Quorum() {
+		return
+	}
+
+	c.round.prepared = true
+	c.preparedCert = &bft.PreparedCertificate{PrePrepare: c.round.prePrepare, Prepares: prepares}
+
+	sig, err := signBlock(c.support, c.round.proposal.Header)
+	if err != nil {
+		c.logger.Errorf("Failed to commit block %d: %s", p.Seq, err)
+		return
+	}
+	commit := &bft.Commit{View: p.View, Seq: p.Seq, Digest: c.round.digest, Signature: sig}
+	if _, err := c.broadcast(&bft.Message{Type: &bft.Message_Commit{Commit: commit}}); err != nil {
+		c.logger.Errorf("Failed to commit block %d: %s", p.Seq, err)
+		return
+	}
+	c.onCommit(c.selfID, commit)
+}
+
+func (c *Chain) onCommit(sender uint64, cm *bft.Commit) {
+	if cm.View != c.round.view || cm.Seq != c.round.seq {
+		return
+	}
+	c.round.commits[sender] = cm
+	c.maybeDecide()
+}
+
+// maybeDecide writes the proposed block once a quorum committed it.
+func (c *Chain) maybeDecide() {
+	if c.round.proposal == nil {
+		return
+	}
+
+	var sigs []*common.MetadataSignature
+	for sender, cm := range c.round.commits {
+		if !bytes.Equal(cm.Digest, c.round.digest) {
+			continue
+		}
+		if err := c.membership.VerifyBlockSignature(sender, c.round.proposal.Header, cm.Signature); err != nil {
+			c.logger.Warningf("Discarding commit of node %d: %s", sender, err)
+			delete(c.round.commits, sender)
+			continue
+		}
+		sigs = append(sigs, cm.Signature)
+	}
+	if len(sigs) < c.membership.Quorum() {
+		return
+	}
+
+	c.decide(c.round.proposal, sigs)
+}
+
+func (c *Chain) decide(block *common.Block, sigs []*common.MetadataSignature) {
+	c.logger.Debugf("Block %d decided in view %d with %d signatures", block.Header.Number, c.view, len(sigs))
+
+	block.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&common.Metadata{
+		Signatures: sigs,
+	})
+	c.writeBlock(block, utils.MarshalOrPanic(&bft.BlockMetadata{View: c.view}))
+
+	c.preparedCert = nil
+	c.expectedDigest = nil
+	c.startRound()
+
+	c.propose()
+}
+
+// writeBlock writes a decided block, and applies the config it contains.
+func (c *Chain) writeBlock(block *common.Block, metadata []byte) {
+	envs := make([]*common.Envelope, 0, len(block.Data.Data))
+	for _, data := range block.Data.Data {
+		env, err := utils.UnmarshalEnvelope(data)
+		if err != nil {
+			c.logger.Panicf("Decided block %d contains an invalid envelope: %s", block.Header.Number, err)
+		}
+		key := requestKey(env)
+		delete(c.pending, key)
+		delete(c.inQueue, key)
+		c.decided[key] = c.clock.Now()
+		envs = append(envs, env)
+	}
+
+	c.lastBlock = block
+	if !isConfigBlock(envs) {
+		c.support.WriteBlock(block, metadata)
+		return
+	}
+
+	c.support.WriteConfigBlock(block, metadata)
+	if err := c.reconfigure(); err != nil {
+		c.logger.Panicf("Failed to apply the config of block %d: %s", block.Header.Number, err)
+	}
+}
+
+func isConfigBlock(envs []*common.Envelope) bool {
+	if len(envs) != 1 {
+		return false
+	}
+	chdr, err := utils.ChannelHeader(envs[0])
+	if err != nil {
+		return false
+	}
+	return chdr.Type == int32(common.HeaderType_CONFIG) || chdr.Type == int32(common.HeaderType_ORDERER_TRANSACTION)
+}
+
+// reconfigure applies the consenters of the current config.
+func (c *Chain) reconfigure() error {
+	m := &bft.Metadata{}
+	if err := proto.Unmarshal(c.support.SharedConfig().ConsensusMetadata(), m); err != nil {
+		return errors.Wrap(err, "failed to unmarshal consensus metadata")
+	}
+	membership, err := NewMembership(m.Consenters)
+	if err != nil {
+		return err
+	}
+
+	if !membership.Contains(c.selfID) {
+		c.logger.Warningf("This node was removed from the consenters of the channel, halting")
+		go c.Halt()
+		return nil
+	}
+
+	c.membership = membership
+	return c.configureComm()
+}
+
+func (c *Chain) configureComm() error {
+	var nodes []cluster.RemoteNode
+	for _, id := range c.membership.IDs {
+		// No need to know yourself
+		if id == c.selfID {
+			continue
+		}
+		consenter := c.membership.Consenters[id]
+		serverCertAsDER, err := pemToDER(consenter.ServerTlsCert)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("invalid server TLS cert of node %d", id))
+		}
+		clientCertAsDER, err := pemToDER(consenter.ClientTlsCert)
+		if err != nil {
+			return errors.WithMessage(err, fmt.Sprintf("invalid client TLS cert of node %d", id))
+		}
+		nodes = append(nodes, cluster.RemoteNode{
+			ID:            id,
+			Endpoint:      fmt.Sprintf("%s:%d", consenter.Host, consenter.Port),
+			ServerTLSCert: serverCertAsDER,
+			ClientTLSCert: clientCertAsDER,
+		})
+	}
+
+	c.configurator.Configure(c.channelID, nodes)
+	return nil
+}
+
+func pemToDER(pemBytes []byte) ([]byte, error) {
+	bl, _ := pem.Decode(pemBytes)
+	if bl == nil {
+		return nil, errors.Errorf("invalid PEM block")
+	}
+	return bl.Bytes, nil
+}
+
+// verifyProposal checks that a proposed block extends the chain and that its
+// transactions are valid.
+func (c *Chain) verifyProposal(block *common.Block) error {
+	if block == nil || block.Header == nil || block.Data == nil || len(block.Data.Data) == 0 {
+		return errors.New("empty block")
+	}
+	if block.Metadata == nil || len(block.Metadata.Metadata) != len(common.BlockMetadataIndex_name) {
+		return errors.New("block has malformed metadata")
+	}
+	if block.Header.Number != c.lastBlock.Header.Number+1 {
+		return errors.Errorf("block number is %d, expected %d", block.Header.Number, c.lastBlock.Header.Number+1)
+	}
+	if !bytes.Equal(block.Header.PreviousHash, c.lastBlock.Header.Hash()) {
+		return errors.Errorf("block %d does not extend block %d", block.Header.Number, c.lastBlock.Header.Number)
+	}
+	if !bytes.Equal(block.Header.DataHash, block.Data.Hash()) {
+		return errors.Errorf("data hash of block %d does not match its data", block.Header.Number)
+	}
+
+	envs := make([]*common.Envelope, 0, len(block.Data.Data))
+	for _, data := range block.Data.Data {
+		env, err := utils.UnmarshalEnvelope(data)
+		if err != nil {
+			return err
+		}
+		envs = append(envs, env)
+	}
+
+	for _, env := range envs {
+		chdr, err := utils.ChannelHeader(env)
+		if err != nil {
+			return err
+		}
+		if c.support.ClassifyMsg(chdr) != msgprocessor.ConfigMsg {
+			if _, err := c.support.ProcessNormalMsg(env); err != nil {
+				return errors.WithMessage(err, "invalid transaction")
+			}
+			continue
+		}
+
+		if len(envs) != 1 {
+			return errors.Errorf("config transaction is not isolated in block %d", block.Header.Number)
+		}
+		return c.verifyConfig(env)
+	}
+	return nil
+}
+
+// verifyConfig checks that a proposed config is the one this node computes from its update.
+func (c *Chain) verifyConfig(env *common.Envelope) error {
+	recomputed, _, err := c.support.ProcessConfigMsg(env)
+	if err != nil {
+		return errors.WithMessage(err, "invalid config transaction")
+	}
+
+	proposedConfig, err := configFromEnvelope(env)
+	if err != nil {
+		return err
+	}
+	recomputedConfig, err := configFromEnvelope(recomputed)
+	if err != nil {
+		return err
+	}
+	if !proto.Equal(proposedConfig, recomputedConfig) {
+		return errors.New("proposed config does not match the config update")
+	}
+	return checkBlockValidationPolicy(proposedConfig)
+}
+
+// verifyBlockValidationPolicy checks that the config carried by a config transaction
+// requires the blocks to be signed by a quorum of its consenters.
+func verifyBlockValidationPolicy(env *common.Envelope) error {
+	config, err := configFromEnvelope(env)
+	if err != nil {
+		return err
+	}
+	if err := checkBlockValidationPolicy(config); err != nil {
+		return errors.WithMessage(err, "invalid config transaction")
+	}
+	return nil
+}
+
+// configFromEnvelope returns the config carried by a config transaction, or by the
+// config transaction embedded in a channel creation transaction.
+func configFromEnvelope(env *common.Envelope) (*common.Config, error) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("missing payload header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	if chdr.Type == int32(common.HeaderType_ORDERER_TRANSACTION) {
+		inner, err := utils.UnmarshalEnvelope(payload.Data)
+		if err != nil {
+			return nil, err
+		}
+		return configFromEnvelope(inner)
+	}
+
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	return configEnv.Config, nil
+}
+
+// checkTimeouts suspects the leader when a request is not ordered in time, and tries
+// the next view when a view change is not completed in time.
+func (c *Chain) checkTimeouts() {
+	now := c.clock.Now()
+
+	// The requests decided a while ago are not relayed anymore
+	for key, decidedAt := range c.decided {
+		if now.Sub(decidedAt) > 2*c.opts.RequestTimeout {
+			delete(c.decided, key)
+		}
+	}
+
+	if c.inViewChange {
+		if now.Sub(c.viewChangeStart) > c.opts.ViewChangeTimeout {
+			c.logger.Warningf("View change to view %d timed out", c.nextView)
+			c.startViewChange(c.nextView + 1)
+		}
+		return
+	}
+
+	for _, p := range c.pending {
+		if now.Sub(p.received) > c.opts.RequestTimeout {
+			c.logger.Warningf("Request was not ordered in %s, suspecting leader %d of view %d",
+				c.opts.RequestTimeout, c.membership.Leader(c.view), c.view)
+			c.startViewChange(c.view + 1)
+			return
+		}
+	}
+}
+
+func (c *Chain) startViewChange(next uint64) {
+	if next <= c.view || (c.inViewChange && next <= c.nextView) {
+		return
+	}
+
+	c.logger.Infof("Starting view change from view %d to view %d", c.view, next)
+	c.inViewChange = true
+	c.nextView = next
+	c.viewChangeStart = c.clock.Now()
+
+	vc := &bft.ViewChange{
+		NextView:       next,
+		LastDecidedSeq: c.lastBlock.Header.Number,
+		Prepared:       c.preparedCert,
+	}
+	sm, err := c.broadcast(&bft.Message{Type: &bft.Message_ViewChange{ViewChange: vc}})
+	if err != nil {
+		c.logger.Errorf("Failed to send view change: %s", err)
+		return
+	}
+	c.onViewChange(sm, vc)
+}
+
+func (c *Chain) onViewChange(sm *bft.SignedMessage, vc *bft.ViewChange) {
+	if vc.NextView <= c.view {
+		return
+	}
+
+	if c.viewChanges[vc.NextView] == nil {
+		c.viewChanges[vc.NextView] = make(map[uint64]*bft.SignedMessage)
+	}
+	c.viewChanges[vc.NextView][sm.Sender] = sm
+
+	// Join the view change once enough nodes asked for it for one of them to be correct
+	floor := c.view
+	if c.inViewChange {
+		floor = c.nextView
+	}
+	senders := make(map[uint64]struct{})
+	var lowest uint64
+	for view, vcs := range c.viewChanges {
+		if view <= floor {
+			continue
+		}
+		for sender := range vcs {
+			senders[sender] = struct{}{}
+		}
+		if lowest == 0 || view < lowest {
+			lowest = view
+		}
+	}
+	if len(senders) > bft.MaxFaulty(len(c.membership.IDs)) {
+		c.startViewChange(lowest)
+	}
+
+	vcs := c.viewChanges[vc.NextView]
+	if len(vcs) < c.membership.Quorum() || c.membership.Leader(vc.NextView) != c.selfID || c.newViewSent[vc.NextView] {
+		return
+	}
+
+	var signed []*bft.SignedMessage
+	for _, vcsm := range vcs {
+		signed = append(signed, vcsm)
+	}
+	nv := &bft.NewView{View: vc.NextView, ViewChanges: signed}
+	nsm, err := c.broadcast(&bft.Message{Type: &bft.Message_NewView{NewView: nv}})
+	if err != nil {
+		c.logger.Errorf("Failed to send new view: %s", err)
+		return
+	}
+	c.newViewSent[vc.NextView] = true
+	c.onNewView(nsm, nv)
+}
+
+func (c *Chain) onNewView(sm *bft.SignedMessage, nv *bft.NewView) {
+	if nv.View <= c.view {
+		return
+	}
+	if sm.Sender != c.membership.Leader(nv.View) {
+		c.logger.Warningf("Discarding new view of node %d, which is not the leader of view %d", sm.Sender, nv.View)
+		return
+	}
+
+	var viewChanges []*bft.ViewChange
+	senders := make(map[uint64]struct{})
+	for _, vcsm := range nv.ViewChanges {
+		msg, err := openMessage(c.membership, vcsm)
+		if err != nil || msg.GetViewChange() == nil || msg.GetViewChange().NextView != nv.View {
+			c.logger.Warningf("Discarding new view of node %d with an invalid view change", sm.Sender)
+			return
+		}
+		if _, exists := senders[vcsm.Sender]; exists {
+			continue
+		}
+		senders[vcsm.Sender] = struct{}{}
+		viewChanges = append(viewChanges, msg.GetViewChange())
+	}
+	if len(senders) < c.membership.Quorum() {
+		c.logger.Warningf("Discarding new view of node %d with %d view changes out of a quorum of %d",
+			sm.Sender, len(senders), c.membership.Quorum())
+		return
+	}
+
+	c.installView(nv.View, viewChanges)
+}
+
+// installView moves the chain to a new view, in which the leader proposes again the block
+// prepared in the former views, if any.
+func (c *Chain) installView(view uint64, viewChanges []*bft.ViewChange) {
+	c.logger.Infof("Installing view %d, leader is node %d", view, c.membership.Leader(view))
+
+	c.view = view
+	c.nextView = view
+	c.inViewChange = false
+	for v := range c.viewChanges {
+		if v <= view {
+			delete(c.viewChanges, v)
+		}
+	}
+
+	seq := c.lastBlock.Header.Number + 1
+	c.round = newRound(seq, view)
+	c.expectedDigest = nil
+	selected := selectPrepared(c.membership, viewChanges, seq)
+	if selected != nil {
+		c.expectedDigest = selected.Proposal.Header.Hash()
+	}
+	defer c.replayFuture()
+
+	// Give the new leader time to order the pending requests
+	now := c.clock.Now()
+	for _, p := range c.pending {
+		p.received = now
+	}
+
+	c.dropQueue()
+	if !c.isLeader() {
+		leader := c.membership.Leader(view)
+		for _, p := range c.pending {
+			c.forward(leader, p.req)
+		}
+		return
+	}
+
+	if selected != nil {
+		c.sendPrePrepare(selected.Proposal)
+		return
+	}
+	for key, p := range c.pending {
+		c.order(key, p.req)
+	}
+	c.propose()
+}
+
+// sync pulls the blocks decided by the other nodes up to the given sequence.
+func (c *Chain) sync(target uint64) {
+	if c.puller == nil {
+		return
+	}
+
+	c.logger.Infof("Synchronizing blocks %d to %d", c.lastBlock.Header.Number+1, target)
+	for seq := c.lastBlock.Header.Number + 1; seq <= target; seq++ {
+		block := c.puller.PullBlock(seq)
+		if block == nil {
+			c.logger.Warningf("Failed to pull block %d", seq)
+			break
+		}
+
+		var metadata []byte
+		if md, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_ORDERER); err == nil && len(md.Value) > 0 {
+			metadata = md.Value
+			bm := &bft.BlockMetadata{}
+			if err := proto.Unmarshal(md.Value, bm); err == nil && bm.View > c.view {
+				c.view = bm.View
+				c.nextView = bm.View
+			}
+		}
+		c.writeBlock(block, metadata)
+	}
+
+	for sender, seq := range c.ahead {
+		if seq <= c.lastBlock.Header.Number {
+			delete(c.ahead, sender)
+		}
+	}
+	c.preparedCert = nil
+	c.expectedDigest = nil
+	c.startRound()
+	c.propose()
+}
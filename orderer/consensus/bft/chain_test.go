@@ -0,0 +1,414 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/flogging"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/consensus/bft"
+	"github.com/hyperledger/fabric/orderer/consensus/bft/mocks"
+	consensusmocks "github.com/hyperledger/fabric/orderer/consensus/mocks"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer"
+	bftproto "github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	channelID      = "mychannel"
+	requestTimeout = time.Second
+)
+
+// signer signs with the key of the enrollment certificate of a consenter.
+type signer struct {
+	key      *ecdsa.PrivateKey
+	identity []byte
+}
+
+func (s *signer) Sign(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	return s.key.Sign(rand.Reader, digest[:], nil)
+}
+
+func (s *signer) NewSignatureHeader() (*common.SignatureHeader, error) {
+	return &common.SignatureHeader{Creator: s.identity, Nonce: []byte("nonce")}, nil
+}
+
+func newConsenters(t *testing.T, n int) ([]*bftproto.Consenter, map[uint64]*signer) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+
+	var consenters []*bftproto.Consenter
+	signers := make(map[uint64]*signer)
+	for i := 1; i <= n; i++ {
+		kp, err := ca.NewServerCertKeyPair("127.0.0.1")
+		require.NoError(t, err)
+		bl, _ := pem.Decode(kp.Key)
+		key, err := x509.ParsePKCS8PrivateKey(bl.Bytes)
+		require.NoError(t, err)
+
+		id := uint64(i)
+		consenters = append(consenters, &bftproto.Consenter{
+			ConsenterId:   id,
+			Host:          fmt.Sprintf("node-%d.example.com", i),
+			Port:          7050,
+			MspId:         "OrdererMSP",
+			Identity:      kp.Cert,
+			ClientTlsCert: kp.Cert,
+			ServerTlsCert: kp.Cert,
+		})
+		signers[id] = &signer{
+			key:      key.(*ecdsa.PrivateKey),
+			identity: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: "OrdererMSP", IdBytes: kp.Cert}),
+		}
+	}
+	return consenters, signers
+}
+
+// cutEach cuts a batch for every envelope.
+type cutEach struct{}
+
+func (cutEach) Ordered(env *common.Envelope) ([][]*common.Envelope, bool) {
+	return [][]*common.Envelope{{env}}, false
+}
+
+func (cutEach) Cut() []*common.Envelope {
+	return nil
+}
+
+type testNode struct {
+	id      uint64
+	chain   *bft.Chain
+	support *consensusmocks.FakeConsenterSupport
+	rpc     *mocks.FakeRPC
+	puller  *mocks.FakeBlockPuller
+	signer  *signer
+
+	lock    sync.Mutex
+	written []*common.Block
+	meta    [][]byte
+}
+
+func (n *testNode) blocks() []*common.Block {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	return append([]*common.Block{}, n.written...)
+}
+
+func (n *testNode) views() []uint64 {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	var views []uint64
+	for _, md := range n.meta {
+		bm := &bftproto.BlockMetadata{}
+		if err := proto.Unmarshal(md, bm); err != nil {
+			panic(err)
+		}
+		views = append(views, bm.View)
+	}
+	return views
+}
+
+// network connects test nodes in memory.
+type network struct {
+	t          *testing.T
+	clock      *fakeclock.FakeClock
+	membership *bft.Membership
+	nodes      map[uint64]*testNode
+
+	lock         sync.RWMutex
+	disconnected map[uint64]bool
+}
+
+func newNetwork(t *testing.T, n int) *network {
+	consenters, signers := newConsenters(t, n)
+	membership, err := bft.NewMembership(consenters)
+	require.NoError(t, err)
+
+	net := &network{
+		t:            t,
+		clock:        fakeclock.NewFakeClock(time.Now()),
+		membership:   membership,
+		nodes:        make(map[uint64]*testNode),
+		disconnected: make(map[uint64]bool),
+	}
+
+	genesis := common.NewBlock(0, nil)
+	genesis.Data.Data = [][]byte{[]byte("genesis")}
+	genesis.Header.DataHash = genesis.Data.Hash()
+
+	for _, id := range membership.IDs {
+		node := &testNode{id: id, signer: signers[id], puller: &mocks.FakeBlockPuller{}}
+
+		support := &consensusmocks.FakeConsenterSupport{}
+		support.ChainIDReturns(channelID)
+		support.HeightReturns(1)
+		support.BlockReturns(genesis)
+		support.BlockCutterReturns(cutEach{})
+		support.SharedConfigReturns(&mockconfig.Orderer{BatchTimeoutVal: time.Second})
+		support.ClassifyMsgReturns(msgprocessor.NormalMsg)
+		support.SignStub = node.signer.Sign
+		support.NewSignatureHeaderStub = node.signer.NewSignatureHeader
+		support.WriteBlockStub = func(block *common.Block, metadata []byte) {
+			node.lock.Lock()
+			defer node.lock.Unlock()
+			node.written = append(node.written, block)
+			node.meta = append(node.meta, metadata)
+		}
+		node.support = support
+
+		node.rpc = &mocks.FakeRPC{}
+		src := id
+		node.rpc.StepStub = func(dest uint64, msg *orderer.StepRequest) (*orderer.StepResponse, error) {
+			target, err := net.link(src, dest)
+			if err != nil {
+				return nil, err
+			}
+			return &orderer.StepResponse{}, target.chain.Step(msg, src)
+		}
+		node.rpc.SendSubmitStub = func(dest uint64, request *orderer.SubmitRequest) error {
+			target, err := net.link(src, dest)
+			if err != nil {
+				return err
+			}
+			return target.chain.Submit(proto.Clone(request).(*orderer.SubmitRequest), src)
+		}
+
+		node.chain, err = bft.NewChain(support, bft.Options{
+			SelfID:            id,
+			Clock:             net.clock,
+			Logger:            flogging.NewFabricLogger(flogging.MustGetLogger("orderer.consensus.bft").Zap()),
+			RequestTimeout:    requestTimeout,
+			ViewChangeTimeout: 10 * requestTimeout,
+			Membership:        membership,
+		}, &mocks.FakeConfigurator{}, node.rpc, node.puller)
+		require.NoError(t, err)
+
+		net.nodes[id] = node
+	}
+
+	return net
+}
+
+func (net *network) link(src, dest uint64) (*testNode, error) {
+	net.lock.RLock()
+	defer net.lock.RUnlock()
+	if net.disconnected[src] || net.disconnected[dest] {
+		return nil, errors.Errorf("node %d is unreachable from node %d", dest, src)
+	}
+	return net.nodes[dest], nil
+}
+
+func (net *network) disconnect(id uint64) {
+	net.lock.Lock()
+	defer net.lock.Unlock()
+	net.disconnected[id] = true
+}
+
+func (net *network) start() {
+	for _, node := range net.nodes {
+		node.chain.Start()
+	}
+}
+
+func (net *network) halt() {
+	for _, node := range net.nodes {
+		node.chain.Halt()
+	}
+}
+
+// waitForBlocks waits until the given nodes wrote the given number of blocks.
+func (net *network) waitForBlocks(count int, ids ...uint64) {
+	net.waitFor(count, false, ids...)
+}
+
+// waitForBlocksTicking waits until the given nodes wrote the given number of blocks,
+// advancing the clock so that the timeouts expire.
+func (net *network) waitForBlocksTicking(count int, ids ...uint64) {
+	net.waitFor(count, true, ids...)
+}
+
+func (net *network) waitFor(count int, tick bool, ids ...uint64) {
+	gt := NewGomegaWithT(net.t)
+	gt.Eventually(func() bool {
+		if tick {
+			net.clock.Increment(requestTimeout / 5)
+		}
+		for _, id := range ids {
+			if len(net.nodes[id].blocks()) < count {
+				return false
+			}
+		}
+		return true
+	}, 10*time.Second, 50*time.Millisecond).Should(BeTrue())
+}
+
+func envelope(data string) *common.Envelope {
+	return &common.Envelope{
+		Payload: utils.MarshalOrPanic(&common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: channelID,
+				}),
+			},
+			Data: []byte(data),
+		}),
+	}
+}
+
+// assertQuorumSigned checks that a block is signed by a quorum of distinct consenters.
+func assertQuorumSigned(t *testing.T, m *bft.Membership, block *common.Block) {
+	md, err := utils.GetMetadataFromBlock(block, common.BlockMetadataIndex_SIGNATURES)
+	require.NoError(t, err)
+
+	signers := make(map[uint64]struct{})
+	for _, sig := range md.Signatures {
+		for _, id := range m.IDs {
+			if m.VerifyBlockSignature(id, block.Header, sig) == nil {
+				signers[id] = struct{}{}
+			}
+		}
+	}
+	assert.True(t, len(signers) >= m.Quorum(), "block %d is signed by %d consenters", block.Header.Number, len(signers))
+}
+
+func TestChainOrdersWithQuorumSignatures(t *testing.T) {
+	net := newNetwork(t, 4)
+	net.start()
+	defer net.halt()
+
+	// Node 2 is not the leader of view 0, and relays the requests to the leader
+	require.NoError(t, net.nodes[2].chain.Order(envelope("tx1"), 0))
+	require.NoError(t, net.nodes[1].chain.Order(envelope("tx2"), 0))
+	net.waitForBlocks(2, 1, 2, 3, 4)
+
+	reference := net.nodes[1].blocks()
+	for _, node := range net.nodes {
+		blocks := node.blocks()
+		require.Len(t, blocks, 2)
+		for i, block := range blocks {
+			assert.Equal(t, uint64(i+1), block.Header.Number)
+			assert.Equal(t, reference[i].Header.Hash(), block.Header.Hash())
+			assertQuorumSigned(t, net.membership, block)
+		}
+		assert.Equal(t, []uint64{0, 0}, node.views())
+	}
+	assert.Equal(t, reference[0].Header.Hash(), reference[1].Header.PreviousHash)
+}
+
+func TestChainChangesViewWhenLeaderFails(t *testing.T) {
+	net := newNetwork(t, 4)
+	net.start()
+	defer net.halt()
+
+	net.disconnect(1)
+
+	require.NoError(t, net.nodes[3].chain.Order(envelope("tx1"), 0))
+	net.waitForBlocksTicking(1, 2, 3, 4)
+
+	for _, id := range []uint64{2, 3, 4} {
+		blocks := net.nodes[id].blocks()
+		require.Len(t, blocks, 1)
+		assertQuorumSigned(t, net.membership, blocks[0])
+		// Node 2 is the leader of view 1
+		assert.Equal(t, []uint64{1}, net.nodes[id].views())
+	}
+	assert.Empty(t, net.nodes[1].blocks())
+}
+
+func TestChainRejectsInvalidProposal(t *testing.T) {
+	net := newNetwork(t, 4)
+	leader := net.nodes[1]
+	replica := net.nodes[2]
+	net.disconnect(1)
+	net.start()
+	defer net.halt()
+
+	block := common.NewBlock(1, []byte("not the genesis hash"))
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(envelope("tx1"))}
+	block.Header.DataHash = block.Data.Hash()
+	pp := &bftproto.PrePrepare{View: 0, Seq: 1, Proposal: block}
+
+	msgBytes := utils.MarshalOrPanic(&bftproto.Message{Type: &bftproto.Message_PrePrepare{PrePrepare: pp}})
+	sig, err := leader.signer.Sign(msgBytes)
+	require.NoError(t, err)
+	payload := utils.MarshalOrPanic(&bftproto.SignedMessage{Message: msgBytes, Sender: 1, Signature: sig})
+
+	assert.EqualError(t, replica.chain.Step(&orderer.StepRequest{Channel: channelID, Payload: payload}, 3),
+		"message of node 1 was sent by node 3")
+	require.NoError(t, replica.chain.Step(&orderer.StepRequest{Channel: channelID, Payload: payload}, 1))
+
+	// The replica suspects the leader and asks for a view change, without preparing the block
+	gt := NewGomegaWithT(t)
+	gt.Eventually(replica.rpc.StepCallCount, 5*time.Second, 10*time.Millisecond).Should(Equal(3))
+	for i := 0; i < replica.rpc.StepCallCount(); i++ {
+		_, req := replica.rpc.StepArgsForCall(i)
+		sent := &bftproto.SignedMessage{}
+		require.NoError(t, proto.Unmarshal(req.Payload, sent))
+		require.NoError(t, net.membership.Verify(sent.Sender, sent.Message, sent.Signature))
+		msg := &bftproto.Message{}
+		require.NoError(t, proto.Unmarshal(sent.Message, msg))
+		require.NotNil(t, msg.GetViewChange(), "expected a view change, got %v", msg)
+		assert.Equal(t, uint64(1), msg.GetViewChange().NextView)
+	}
+	assert.Empty(t, replica.blocks())
+}
+
+func TestChainSynchronizesWhenBehind(t *testing.T) {
+	net := newNetwork(t, 4)
+	net.disconnect(4)
+	net.start()
+	defer net.halt()
+
+	for _, data := range []string{"tx1", "tx2", "tx3"} {
+		require.NoError(t, net.nodes[1].chain.Order(envelope(data), 0))
+	}
+	net.waitForBlocks(3, 1, 2, 3)
+
+	decided := net.nodes[1].blocks()
+	lagging := net.nodes[4]
+	puller := lagging.puller
+	puller.PullBlockStub = func(seq uint64) *common.Block {
+		return decided[seq-1]
+	}
+
+	net.lock.Lock()
+	delete(net.disconnected, 4)
+	net.lock.Unlock()
+
+	// The next proposal tells the lagging node that the others are ahead
+	require.NoError(t, net.nodes[1].chain.Order(envelope("tx4"), 0))
+	net.waitForBlocks(4, 1, 2, 3, 4)
+
+	blocks := lagging.blocks()
+	require.Len(t, blocks, 4)
+	for i, block := range blocks[:3] {
+		assert.Equal(t, decided[i].Header.Hash(), block.Header.Hash())
+	}
+	assert.Equal(t, net.nodes[1].blocks()[3].Header.Hash(), blocks[3].Header.Hash())
+	assert.True(t, puller.PullBlockCallCount() >= 3)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"encoding/pem"
+	"time"
+
+	"code.cloudfoundry.org/clock"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/hyperledger/fabric/orderer/consensus/inactive"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/pkg/errors"
+)
+
+// Consenter implements the BFT consenter.
+// It shares the cluster communication of the etcdraft consenter, which
+// dispatches the messages of the BFT chains to them.
+type Consenter struct {
+	Dialer        *cluster.PredicateDialer
+	Communication cluster.Communicator
+	Logger        *flogging.FabricLogger
+	OrdererConfig localconfig.TopLevel
+	Cert          []byte
+}
+
+// HandleChain returns a new Chain instance or an error upon failure
+func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *common.Metadata) (consensus.Chain, error) {
+	m := &bft.Metadata{}
+	if err := proto.Unmarshal(support.SharedConfig().ConsensusMetadata(), m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consensus metadata")
+	}
+
+	membership, err := NewMembership(m.Consenters)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid BFT consenters")
+	}
+
+	id, isConsenter := membership.SelfID(c.Cert)
+	if !isConsenter {
+		c.Logger.Warningf("Channel %s is not serviced by this node", support.ChainID())
+		return &inactive.Chain{Err: errors.Errorf("channel %s is not serviced by me", support.ChainID())}, nil
+	}
+
+	// The view in which the last block was decided is recorded in its metadata
+	blockMetadata := &bft.BlockMetadata{}
+	if metadata != nil && len(metadata.Value) != 0 {
+		if err := proto.Unmarshal(metadata.Value, blockMetadata); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal block's metadata")
+		}
+	}
+
+	bp, err := newBlockPuller(support, c.Dialer, c.OrdererConfig.General.Cluster)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	opts := Options{
+		SelfID:     id,
+		Clock:      clock.NewClock(),
+		Logger:     c.Logger,
+		Membership: membership,
+		View:       blockMetadata.View,
+	}
+	if m.Options != nil {
+		opts.RequestTimeout = time.Duration(m.Options.RequestTimeout) * time.Millisecond
+		opts.ViewChangeTimeout = time.Duration(m.Options.ViewChangeTimeout) * time.Millisecond
+	}
+
+	rpc := &cluster.RPC{
+		Channel:             support.ChainID(),
+		Comm:                c.Communication,
+		DestinationToStream: make(map[uint64]orderer.Cluster_SubmitClient),
+	}
+	return NewChain(support, opts, c.Communication, rpc, bp)
+}
+
+// newBlockPuller creates a puller of the blocks decided by the other consenters.
+// The blocks are verified against the block validation policy of the channel,
+// which requires the signatures of a quorum of consenters.
+func newBlockPuller(support consensus.ConsenterSupport,
+	baseDialer *cluster.PredicateDialer,
+	clusterConfig localconfig.Cluster) (*cluster.BlockPuller, error) {
+
+	verifyBlockSequence := func(blocks []*common.Block) error {
+		return cluster.VerifyBlocks(blocks, support)
+	}
+
+	secureConfig, err := baseDialer.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	secureConfig.AsyncConnect = false
+	stdDialer := &cluster.StandardDialer{
+		Dialer: cluster.NewTLSPinningDialer(secureConfig),
+	}
+
+	endpointConfig, err := etcdraft.EndpointconfigFromFromSupport(support)
+	if err != nil {
+		return nil, err
+	}
+	secureConfig.SecOpts.ServerRootCAs = endpointConfig.TLSRootCAs
+	stdDialer.Dialer.SetConfig(secureConfig)
+
+	der, _ := pem.Decode(secureConfig.SecOpts.Certificate)
+	if der == nil {
+		return nil, errors.Errorf("client certificate isn't in PEM format: %v",
+			string(secureConfig.SecOpts.Certificate))
+	}
+
+	return &cluster.BlockPuller{
+		VerifyBlockSequence: verifyBlockSequence,
+		Logger:              flogging.MustGetLogger("orderer.common.cluster.puller"),
+		RetryTimeout:        clusterConfig.ReplicationRetryTimeout,
+		MaxTotalBufferBytes: clusterConfig.ReplicationBufferSize,
+		FetchTimeout:        clusterConfig.ReplicationPullTimeout,
+		// The chain cannot serve requests while pulling, so it gives up on unavailable blocks
+		MaxPullBlockRetries: uint64(clusterConfig.ReplicationMaxRetries),
+		Endpoints:           endpointConfig.Endpoints,
+		Signer:              support,
+		TLSCert:             der.Bytes,
+		Channel:             support.ChainID(),
+		Dialer:              stdDialer,
+	}, nil
+}
+
+// New creates a BFT Consenter, which communicates with the other nodes
+// through the given cluster communication.
+func New(
+	clusterDialer *cluster.PredicateDialer,
+	conf *localconfig.TopLevel,
+	srvConf comm.ServerConfig,
+	communication cluster.Communicator,
+) *Consenter {
+	return &Consenter{
+		Dialer:        clusterDialer,
+		Communication: communication,
+		Logger:        flogging.MustGetLogger("orderer.consensus.bft"),
+		OrdererConfig: *conf,
+		Cert:          srvConf.SecOpts.Certificate,
+	}
+}
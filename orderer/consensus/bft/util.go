@@ -0,0 +1,285 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// blockValidationPolicyKey is the name of the orderer policy which the signatures of
+// the blocks must satisfy.
+const blockValidationPolicyKey = "BlockValidation"
+
+// Membership is the set of consenters of a channel, by ID.
+type Membership struct {
+	IDs        []uint64
+	Consenters map[uint64]*bft.Consenter
+	certs      map[uint64]*x509.Certificate
+}
+
+// NewMembership validates the consenters of a channel and returns their membership.
+func NewMembership(consenters []*bft.Consenter) (*Membership, error) {
+	m := &Membership{
+		Consenters: make(map[uint64]*bft.Consenter),
+		certs:      make(map[uint64]*x509.Certificate),
+	}
+	for _, c := range consenters {
+		if c.ConsenterId == 0 {
+			return nil, errors.Errorf("consenter %s:%d has no ID", c.Host, c.Port)
+		}
+		if _, exists := m.Consenters[c.ConsenterId]; exists {
+			return nil, errors.Errorf("consenter ID %d is not unique", c.ConsenterId)
+		}
+		cert, err := parseCertificate(c.Identity)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid identity of consenter "+c.Host)
+		}
+		m.IDs = append(m.IDs, c.ConsenterId)
+		m.Consenters[c.ConsenterId] = c
+		m.certs[c.ConsenterId] = cert
+	}
+	if len(m.IDs) == 0 {
+		return nil, errors.New("no consenters")
+	}
+	sort.Slice(m.IDs, func(i, j int) bool { return m.IDs[i] < m.IDs[j] })
+	return m, nil
+}
+
+// Quorum returns the quorum size of the membership.
+func (m *Membership) Quorum() int {
+	return bft.Quorum(len(m.IDs))
+}
+
+// Leader returns the ID of the leader of the given view, the leadership rotating
+// among the consenters in the order of their IDs.
+func (m *Membership) Leader(view uint64) uint64 {
+	return m.IDs[view%uint64(len(m.IDs))]
+}
+
+// Contains returns whether the given ID belongs to a consenter.
+func (m *Membership) Contains(id uint64) bool {
+	_, exists := m.Consenters[id]
+	return exists
+}
+
+// SelfID returns the ID of the consenter with the given server TLS certificate.
+func (m *Membership) SelfID(serverCert []byte) (uint64, bool) {
+	for _, id := range m.IDs {
+		if bytes.Equal(m.Consenters[id].ServerTlsCert, serverCert) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Verify checks that the signature was produced by the given consenter over the data.
+func (m *Membership) Verify(id uint64, data, signature []byte) error {
+	cert, exists := m.certs[id]
+	if !exists {
+		return errors.Errorf("node %d is not a consenter", id)
+	}
+	if err := cert.CheckSignature(x509.ECDSAWithSHA256, data, signature); err != nil {
+		return errors.Wrapf(err, "invalid signature of node %d", id)
+	}
+	return nil
+}
+
+// VerifyBlockSignature checks that a block signature was produced by the given consenter.
+func (m *Membership) VerifyBlockSignature(id uint64, header *common.BlockHeader, sig *common.MetadataSignature) error {
+	if sig == nil {
+		return errors.Errorf("missing block signature of node %d", id)
+	}
+	shdr, err := utils.GetSignatureHeader(sig.SignatureHeader)
+	if err != nil {
+		return errors.WithMessage(err, "invalid block signature header")
+	}
+	creator := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(shdr.Creator, creator); err != nil {
+		return errors.Wrap(err, "invalid block signature creator")
+	}
+	cert, err := parseCertificate(creator.IdBytes)
+	if err != nil {
+		return errors.WithMessage(err, "invalid block signature creator")
+	}
+	if creator.Mspid != m.Consenters[id].MspId || !bytes.Equal(cert.Raw, m.certs[id].Raw) {
+		return errors.Errorf("block signature of node %d was created by another identity", id)
+	}
+	return m.Verify(id, util.ConcatenateBytes(nil, sig.SignatureHeader, header.Bytes()), sig.Signature)
+}
+
+func parseCertificate(pemBytes []byte) (*x509.Certificate, error) {
+	bl, _ := pem.Decode(pemBytes)
+	if bl == nil {
+		return nil, errors.New("identity is not PEM encoded")
+	}
+	cert, err := x509.ParseCertificate(bl.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "identity is not a certificate")
+	}
+	return cert, nil
+}
+
+// signMessage wraps a consensus message along with the signature of the sender.
+func signMessage(signer crypto.LocalSigner, sender uint64, msg *bft.Message) (*bft.SignedMessage, error) {
+	msgBytes, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal message")
+	}
+	sig, err := signer.Sign(msgBytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign message")
+	}
+	return &bft.SignedMessage{Message: msgBytes, Sender: sender, Signature: sig}, nil
+}
+
+// openMessage verifies the signature of a signed message and returns the consensus message.
+func openMessage(m *Membership, sm *bft.SignedMessage) (*bft.Message, error) {
+	if sm == nil {
+		return nil, errors.New("missing message")
+	}
+	if err := m.Verify(sm.Sender, sm.Message, sm.Signature); err != nil {
+		return nil, err
+	}
+	msg := &bft.Message{}
+	if err := proto.Unmarshal(sm.Message, msg); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal message")
+	}
+	return msg, nil
+}
+
+// signBlock returns the signature of the block header by the local signer, in the
+// form it is stored in the SIGNATURES metadata of the block.
+func signBlock(signer crypto.LocalSigner, header *common.BlockHeader) (*common.MetadataSignature, error) {
+	shdr, err := signer.NewSignatureHeader()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create signature header")
+	}
+	shdrBytes, err := proto.Marshal(shdr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal signature header")
+	}
+	// The value of the SIGNATURES metadata is nil, as it is for the blocks signed by the block writer
+	sig, err := signer.Sign(util.ConcatenateBytes(nil, shdrBytes, header.Bytes()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign block")
+	}
+	return &common.MetadataSignature{SignatureHeader: shdrBytes, Signature: sig}, nil
+}
+
+// verifyPreparedCertificate checks that a quorum of consenters prepared the proposal
+// of a certificate, and returns the proposal.
+func verifyPreparedCertificate(m *Membership, cert *bft.PreparedCertificate) (*bft.PrePrepare, error) {
+	msg, err := openMessage(m, cert.PrePrepare)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid pre-prepare")
+	}
+	pp := msg.GetPrePrepare()
+	if pp == nil || pp.Proposal == nil || pp.Proposal.Header == nil {
+		return nil, errors.New("invalid pre-prepare")
+	}
+	if cert.PrePrepare.Sender != m.Leader(pp.View) {
+		return nil, errors.Errorf("pre-prepare was sent by node %d which is not the leader of view %d", cert.PrePrepare.Sender, pp.View)
+	}
+	digest := pp.Proposal.Header.Hash()
+
+	senders := make(map[uint64]struct{})
+	for _, sm := range cert.Prepares {
+		msg, err := openMessage(m, sm)
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid prepare")
+		}
+		p := msg.GetPrepare()
+		if p == nil || p.View != pp.View || p.Seq != pp.Seq || !bytes.Equal(p.Digest, digest) {
+			return nil, errors.Errorf("prepare of node %d does not match the pre-prepare", sm.Sender)
+		}
+		senders[sm.Sender] = struct{}{}
+	}
+	if len(senders) < m.Quorum() {
+		return nil, errors.Errorf("%d prepares out of a quorum of %d", len(senders), m.Quorum())
+	}
+	return pp, nil
+}
+
+// selectPrepared returns the proposal of the given sequence prepared in the highest view
+// among the view changes, if any.
+func selectPrepared(m *Membership, viewChanges []*bft.ViewChange, seq uint64) *bft.PrePrepare {
+	var selected *bft.PrePrepare
+	for _, vc := range viewChanges {
+		if vc.Prepared == nil {
+			continue
+		}
+		pp, err := verifyPreparedCertificate(m, vc.Prepared)
+		if err != nil || pp.Seq != seq {
+			continue
+		}
+		if selected == nil || pp.View > selected.View {
+			selected = pp
+		}
+	}
+	return selected
+}
+
+// checkBlockValidationPolicy checks that the BlockValidation policy of a config requires
+// the blocks to be signed by a quorum of the consenters of that config, so that a config
+// update which changes the consenters cannot leave the peers trusting a stale quorum.
+func checkBlockValidationPolicy(config *common.Config) error {
+	ordererGroup := config.GetChannelGroup().GetGroups()[channelconfig.OrdererGroupKey]
+	if ordererGroup == nil {
+		return errors.New("config has no orderer group")
+	}
+
+	consensusTypeValue := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+	if consensusTypeValue == nil {
+		return errors.New("config has no consensus type")
+	}
+	consensusType := &orderer.ConsensusType{}
+	if err := proto.Unmarshal(consensusTypeValue.Value, consensusType); err != nil {
+		return errors.Wrap(err, "failed to unmarshal consensus type")
+	}
+	if consensusType.Type != bft.TypeKey {
+		return nil
+	}
+	metadata := &bft.Metadata{}
+	if err := proto.Unmarshal(consensusType.Metadata, metadata); err != nil {
+		return errors.Wrap(err, "failed to unmarshal consensus metadata")
+	}
+	expected, err := bft.BlockValidationPolicy(metadata.Consenters)
+	if err != nil {
+		return err
+	}
+
+	configPolicy := ordererGroup.Policies[blockValidationPolicyKey]
+	if configPolicy == nil || configPolicy.Policy == nil {
+		return errors.Errorf("config has no %s policy", blockValidationPolicyKey)
+	}
+	if configPolicy.Policy.Type != int32(common.Policy_SIGNATURE) {
+		return errors.Errorf("%s policy must be a signature policy", blockValidationPolicyKey)
+	}
+	actual := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(configPolicy.Policy.Value, actual); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal %s policy", blockValidationPolicyKey)
+	}
+	if !proto.Equal(actual, expected) {
+		return errors.Errorf("%s policy does not require a quorum of %d out of the %d consenters",
+			blockValidationPolicyKey, bft.Quorum(len(metadata.Consenters)), len(metadata.Consenters))
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSigner signs with the key of an enrollment certificate.
+type testSigner struct {
+	key      *ecdsa.PrivateKey
+	identity []byte
+}
+
+func (s *testSigner) Sign(message []byte) ([]byte, error) {
+	digest := sha256.Sum256(message)
+	return s.key.Sign(rand.Reader, digest[:], nil)
+}
+
+func (s *testSigner) NewSignatureHeader() (*common.SignatureHeader, error) {
+	return &common.SignatureHeader{Creator: s.identity, Nonce: []byte("nonce")}, nil
+}
+
+// newTestConsenters returns n consenters with IDs 1 to n, along with their signers.
+func newTestConsenters(t *testing.T, n int) ([]*bft.Consenter, map[uint64]*testSigner) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+
+	var consenters []*bft.Consenter
+	signers := make(map[uint64]*testSigner)
+	for i := 1; i <= n; i++ {
+		kp, err := ca.NewServerCertKeyPair("127.0.0.1")
+		require.NoError(t, err)
+		bl, _ := pem.Decode(kp.Key)
+		key, err := x509.ParsePKCS8PrivateKey(bl.Bytes)
+		require.NoError(t, err)
+
+		id := uint64(i)
+		consenters = append(consenters, &bft.Consenter{
+			ConsenterId:   id,
+			Host:          fmt.Sprintf("node-%d.example.com", i),
+			Port:          7050,
+			MspId:         "OrdererMSP",
+			Identity:      kp.Cert,
+			ClientTlsCert: kp.Cert,
+			ServerTlsCert: kp.Cert,
+		})
+		identity, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "OrdererMSP", IdBytes: kp.Cert})
+		require.NoError(t, err)
+		signers[id] = &testSigner{key: key.(*ecdsa.PrivateKey), identity: identity}
+	}
+	return consenters, signers
+}
+
+func TestNewMembership(t *testing.T) {
+	consenters, _ := newTestConsenters(t, 4)
+
+	m, err := NewMembership([]*bft.Consenter{consenters[3], consenters[1], consenters[0], consenters[2]})
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3, 4}, m.IDs)
+	assert.Equal(t, 3, m.Quorum())
+	assert.True(t, m.Contains(4))
+	assert.False(t, m.Contains(5))
+
+	// The leadership rotates among the consenters in the order of their IDs
+	assert.Equal(t, uint64(1), m.Leader(0))
+	assert.Equal(t, uint64(2), m.Leader(1))
+	assert.Equal(t, uint64(1), m.Leader(4))
+
+	id, exists := m.SelfID(consenters[2].ServerTlsCert)
+	assert.True(t, exists)
+	assert.Equal(t, uint64(3), id)
+	_, exists = m.SelfID([]byte("unknown"))
+	assert.False(t, exists)
+
+	t.Run("no consenters", func(t *testing.T) {
+		_, err := NewMembership(nil)
+		assert.EqualError(t, err, "no consenters")
+	})
+
+	t.Run("missing ID", func(t *testing.T) {
+		_, err := NewMembership([]*bft.Consenter{{Host: "node-1.example.com", Port: 7050, Identity: consenters[0].Identity}})
+		assert.EqualError(t, err, "consenter node-1.example.com:7050 has no ID")
+	})
+
+	t.Run("duplicate ID", func(t *testing.T) {
+		_, err := NewMembership([]*bft.Consenter{consenters[0], consenters[0]})
+		assert.EqualError(t, err, "consenter ID 1 is not unique")
+	})
+
+	t.Run("invalid identity", func(t *testing.T) {
+		_, err := NewMembership([]*bft.Consenter{{ConsenterId: 1, Host: "node-1.example.com", Identity: []byte("garbage")}})
+		assert.EqualError(t, err, "invalid identity of consenter node-1.example.com: identity is not PEM encoded")
+	})
+}
+
+func TestSignedMessages(t *testing.T) {
+	consenters, signers := newTestConsenters(t, 4)
+	m, err := NewMembership(consenters)
+	require.NoError(t, err)
+
+	msg := &bft.Message{Type: &bft.Message_Prepare{Prepare: &bft.Prepare{View: 1, Seq: 2, Digest: []byte("digest")}}}
+	sm, err := signMessage(signers[2], 2, msg)
+	require.NoError(t, err)
+
+	opened, err := openMessage(m, sm)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(msg, opened))
+
+	t.Run("impersonated sender", func(t *testing.T) {
+		forged := *sm
+		forged.Sender = 3
+		_, err := openMessage(m, &forged)
+		assert.Contains(t, err.Error(), "invalid signature of node 3")
+	})
+
+	t.Run("unknown sender", func(t *testing.T) {
+		forged := *sm
+		forged.Sender = 5
+		_, err := openMessage(m, &forged)
+		assert.EqualError(t, err, "node 5 is not a consenter")
+	})
+
+	t.Run("tampered message", func(t *testing.T) {
+		tampered := *sm
+		tampered.Message = append([]byte{}, sm.Message...)
+		tampered.Message[len(tampered.Message)-1]++
+		_, err := openMessage(m, &tampered)
+		assert.Contains(t, err.Error(), "invalid signature of node 2")
+	})
+}
+
+func TestVerifyBlockSignature(t *testing.T) {
+	consenters, signers := newTestConsenters(t, 4)
+	m, err := NewMembership(consenters)
+	require.NoError(t, err)
+
+	header := common.NewBlock(5, []byte("previous")).Header
+	sig, err := signBlock(signers[1], header)
+	require.NoError(t, err)
+
+	assert.NoError(t, m.VerifyBlockSignature(1, header, sig))
+	assert.EqualError(t, m.VerifyBlockSignature(2, header, sig), "block signature of node 2 was created by another identity")
+	assert.EqualError(t, m.VerifyBlockSignature(1, header, nil), "missing block signature of node 1")
+
+	otherHeader := common.NewBlock(6, []byte("previous")).Header
+	assert.Contains(t, m.VerifyBlockSignature(1, otherHeader, sig).Error(), "invalid signature of node 1")
+}
+
+func TestSelectPrepared(t *testing.T) {
+	consenters, signers := newTestConsenters(t, 4)
+	m, err := NewMembership(consenters)
+	require.NoError(t, err)
+
+	preparedCert := func(view uint64, proposal *common.Block, preparers ...uint64) *bft.PreparedCertificate {
+		pp := &bft.PrePrepare{View: view, Seq: proposal.Header.Number, Proposal: proposal}
+		ppsm, err := signMessage(signers[m.Leader(view)], m.Leader(view), &bft.Message{Type: &bft.Message_PrePrepare{PrePrepare: pp}})
+		require.NoError(t, err)
+		cert := &bft.PreparedCertificate{PrePrepare: ppsm}
+		for _, id := range preparers {
+			p := &bft.Prepare{View: view, Seq: pp.Seq, Digest: proposal.Header.Hash()}
+			psm, err := signMessage(signers[id], id, &bft.Message{Type: &bft.Message_Prepare{Prepare: p}})
+			require.NoError(t, err)
+			cert.Prepares = append(cert.Prepares, psm)
+		}
+		return cert
+	}
+
+	block := func(data string) *common.Block {
+		b := common.NewBlock(5, []byte("previous"))
+		b.Data.Data = [][]byte{[]byte(data)}
+		b.Header.DataHash = b.Data.Hash()
+		return b
+	}
+
+	t.Run("highest view", func(t *testing.T) {
+		selected := selectPrepared(m, []*bft.ViewChange{
+			{NextView: 3, Prepared: preparedCert(0, block("a"), 1, 2, 3)},
+			{NextView: 3, Prepared: preparedCert(1, block("b"), 2, 3, 4)},
+			{NextView: 3},
+		}, 5)
+		require.NotNil(t, selected)
+		assert.Equal(t, uint64(1), selected.View)
+		assert.Equal(t, block("b").Header.Hash(), selected.Proposal.Header.Hash())
+	})
+
+	t.Run("no quorum of prepares", func(t *testing.T) {
+		cert := preparedCert(1, block("b"), 2, 3)
+		_, err := verifyPreparedCertificate(m, cert)
+		assert.EqualError(t, err, "2 prepares out of a quorum of 3")
+		assert.Nil(t, selectPrepared(m, []*bft.ViewChange{{NextView: 3, Prepared: cert}}, 5))
+	})
+
+	t.Run("duplicate prepares", func(t *testing.T) {
+		cert := preparedCert(1, block("b"), 2, 3, 3)
+		_, err := verifyPreparedCertificate(m, cert)
+		assert.EqualError(t, err, "2 prepares out of a quorum of 3")
+	})
+
+	t.Run("pre-prepare not sent by the leader", func(t *testing.T) {
+		cert := preparedCert(1, block("b"), 2, 3, 4)
+		pp, err := signMessage(signers[3], 3, &bft.Message{Type: &bft.Message_PrePrepare{
+			PrePrepare: &bft.PrePrepare{View: 1, Seq: 5, Proposal: block("b")},
+		}})
+		require.NoError(t, err)
+		cert.PrePrepare = pp
+		_, err = verifyPreparedCertificate(m, cert)
+		assert.EqualError(t, err, "pre-prepare was sent by node 3 which is not the leader of view 1")
+	})
+
+	t.Run("prepares of another proposal", func(t *testing.T) {
+		cert := preparedCert(1, block("b"), 2, 3, 4)
+		cert.Prepares[0] = preparedCert(1, block("c"), 2).Prepares[0]
+		_, err := verifyPreparedCertificate(m, cert)
+		assert.EqualError(t, err, "prepare of node 2 does not match the pre-prepare")
+	})
+
+	t.Run("another sequence", func(t *testing.T) {
+		assert.Nil(t, selectPrepared(m, []*bft.ViewChange{
+			{NextView: 3, Prepared: preparedCert(1, block("b"), 2, 3, 4)},
+		}, 6))
+	})
+}
+
+// testConfig returns a config of the given consenters with the given BlockValidation policy.
+func testConfig(t *testing.T, consenters []*bft.Consenter, policy *common.SignaturePolicyEnvelope) *common.Config {
+	metadata, err := proto.Marshal(&bft.Metadata{Consenters: consenters})
+	require.NoError(t, err)
+	consensusType, err := proto.Marshal(&orderer.ConsensusType{Type: bft.TypeKey, Metadata: metadata})
+	require.NoError(t, err)
+	policyBytes, err := proto.Marshal(policy)
+	require.NoError(t, err)
+
+	return &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				channelconfig.OrdererGroupKey: {
+					Values: map[string]*common.ConfigValue{
+						channelconfig.ConsensusTypeKey: {Value: consensusType},
+					},
+					Policies: map[string]*common.ConfigPolicy{
+						blockValidationPolicyKey: {
+							Policy: &common.Policy{Type: int32(common.Policy_SIGNATURE), Value: policyBytes},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestCheckBlockValidationPolicy(t *testing.T) {
+	consenters, _ := newTestConsenters(t, 5)
+	policy, err := bft.BlockValidationPolicy(consenters[:4])
+	require.NoError(t, err)
+
+	config := testConfig(t, consenters[:4], policy)
+	assert.NoError(t, checkBlockValidationPolicy(config))
+
+	t.Run("consenters changed without the policy", func(t *testing.T) {
+		config := testConfig(t, consenters, policy)
+		assert.EqualError(t, checkBlockValidationPolicy(config), "BlockValidation policy does not require a quorum of 4 out of the 5 consenters")
+	})
+
+	t.Run("consenters changed along with the policy", func(t *testing.T) {
+		policy, err := bft.BlockValidationPolicy(consenters)
+		require.NoError(t, err)
+		assert.NoError(t, checkBlockValidationPolicy(testConfig(t, consenters, policy)))
+	})
+
+	t.Run("implicit meta policy", func(t *testing.T) {
+		config := testConfig(t, consenters[:4], policy)
+		config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Policies[blockValidationPolicyKey].Policy.Type = int32(common.Policy_IMPLICIT_META)
+		assert.EqualError(t, checkBlockValidationPolicy(config), "BlockValidation policy must be a signature policy")
+	})
+
+	t.Run("missing policy", func(t *testing.T) {
+		config := testConfig(t, consenters[:4], policy)
+		delete(config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Policies, blockValidationPolicyKey)
+		assert.EqualError(t, checkBlockValidationPolicy(config), "config has no BlockValidation policy")
+	})
+
+	t.Run("other consensus type", func(t *testing.T) {
+		config := testConfig(t, consenters, policy)
+		consensusType, err := proto.Marshal(&orderer.ConsensusType{Type: "etcdraft"})
+		require.NoError(t, err)
+		config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey].Value = consensusType
+		assert.NoError(t, checkBlockValidationPolicy(config))
+	})
+}
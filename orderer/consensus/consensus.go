@@ -25,6 +25,13 @@ type Consenter interface {
 	HandleChain(support ConsenterSupport, metadata *cb.Metadata) (Chain, error)
 }
 
+// ChannelRemover is implemented by the consenters which keep data of a channel outside
+// of its ledger, so that the data is removed along with the channel.
+type ChannelRemover interface {
+	// RemoveChannel removes the data kept for the given channel, whose chain is halted.
+	RemoveChannel(channelID string) error
+}
+
 // Chain defines a way to inject messages for ordering.
 // Note, that in order to allow flexibility in the implementation, it is the responsibility of the implementer
 // to take the ordered messages, send them through the blockcutter.Receiver supplied via HandleChain to cut blocks,
@@ -0,0 +1,217 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/orderer/common/cluster"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/connectivity"
+)
+
+// AdminPath is the path under which the AdminHandler is served.
+const AdminPath = "/raft/"
+
+// Status is the state of the Raft node of a chain.
+type Status struct {
+	Channel        string            `json:"channel"`
+	ID             uint64            `json:"id"`
+	Leader         uint64            `json:"leader"`
+	Term           uint64            `json:"term"`
+	CommittedIndex uint64            `json:"committed_index"`
+	AppliedIndex   uint64            `json:"applied_index"`
+	Consenters     []ConsenterStatus `json:"consenters"`
+}
+
+// ConsenterStatus is the state of a consenter of a chain, as seen by this node.
+type ConsenterStatus struct {
+	ID        uint64 `json:"id"`
+	Endpoint  string `json:"endpoint"`
+	Reachable bool   `json:"reachable"`
+	// Connection is the state of the connection to the consenter, and is
+	// empty for this node or if no connection could be established.
+	Connection string `json:"connection,omitempty"`
+}
+
+// LeadershipTransfer is a request to transfer the leadership of a chain.
+type LeadershipTransfer struct {
+	To uint64 `json:"to"`
+}
+
+// Status returns the state of the Raft node. The reachability of the
+// consenters is not determined by the chain, and is left for the caller.
+func (c *Chain) Status() (*Status, error) {
+	if err := c.isRunning(); err != nil {
+		return nil, err
+	}
+
+	rs := c.node.Status()
+	status := &Status{
+		Channel:        c.channelID,
+		ID:             c.raftID,
+		Leader:         rs.Lead,
+		Term:           rs.Term,
+		CommittedIndex: rs.Commit,
+		AppliedIndex:   rs.Applied,
+	}
+
+	c.raftMetadataLock.RLock()
+	for id, consenter := range c.opts.RaftMetadata.Consenters {
+		status.Consenters = append(status.Consenters, ConsenterStatus{
+			ID:        id,
+			Endpoint:  fmt.Sprintf("%s:%d", consenter.Host, consenter.Port),
+			Reachable: id == c.raftID,
+		})
+	}
+	c.raftMetadataLock.RUnlock()
+
+	sort.Slice(status.Consenters, func(i, j int) bool {
+		return status.Consenters[i].ID < status.Consenters[j].ID
+	})
+	return status, nil
+}
+
+// TransferLeadership asks the Raft node, which must be the leader,
+// to transfer the leadership to the given consenter.
+func (c *Chain) TransferLeadership(to uint64) error {
+	if err := c.isRunning(); err != nil {
+		return err
+	}
+
+	c.raftMetadataLock.RLock()
+	_, exists := c.opts.RaftMetadata.Consenters[to]
+	c.raftMetadataLock.RUnlock()
+	if !exists {
+		return errors.Errorf("node %d is not a consenter of channel %s", to, c.channelID)
+	}
+
+	lead := c.node.Status().Lead
+	if lead != c.raftID {
+		return errors.Errorf("node %d is not the leader of channel %s, the leader is node %d", c.raftID, c.channelID, lead)
+	}
+	if to == lead {
+		return errors.Errorf("node %d is already the leader of channel %s", to, c.channelID)
+	}
+
+	c.logger.Infof("Transferring leadership to node %d", to)
+	c.node.TransferLeadership(context.TODO(), lead, to)
+	return nil
+}
+
+// NewAdminHandler returns an AdminHandler of the chains of the given consenter.
+func NewAdminHandler(c *Consenter) *AdminHandler {
+	return &AdminHandler{
+		Chains:        c.Chains,
+		Communication: c.Communication,
+		Logger:        flogging.MustGetLogger("orderer.consensus.etcdraft.admin"),
+	}
+}
+
+// AdminHandler serves the status of the Raft chains, and the transfer
+// of their leadership:
+//
+//	GET  /raft/<channel>          returns the Status of the chain
+//	PUT  /raft/<channel>/leader   transfers the leadership as given by a LeadershipTransfer
+type AdminHandler struct {
+	Chains        ChainGetter
+	Communication cluster.Communicator
+	Logger        *flogging.FabricLogger
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *AdminHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	path := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, AdminPath), "/"), "/")
+	channel := path[0]
+	if channel == "" || len(path) > 2 || len(path) == 2 && path[1] != "leader" {
+		h.sendResponse(resp, http.StatusNotFound, errors.Errorf("invalid path: %s", req.URL.Path))
+		return
+	}
+
+	chain, err := h.chain(channel)
+	if err != nil {
+		h.sendResponse(resp, http.StatusNotFound, err)
+		return
+	}
+
+	switch {
+	case len(path) == 1 && req.Method == http.MethodGet:
+		status, err := chain.Status()
+		if err != nil {
+			h.sendResponse(resp, http.StatusServiceUnavailable, err)
+			return
+		}
+		for i, consenter := range status.Consenters {
+			if consenter.ID != status.ID {
+				status.Consenters[i].Reachable, status.Consenters[i].Connection = h.reachability(channel, consenter.ID)
+			}
+		}
+		h.sendResponse(resp, http.StatusOK, status)
+
+	case len(path) == 2 && req.Method == http.MethodPut:
+		var transfer LeadershipTransfer
+		if err := json.NewDecoder(req.Body).Decode(&transfer); err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, err)
+			return
+		}
+		req.Body.Close()
+
+		if err := chain.TransferLeadership(transfer.To); err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, err)
+			return
+		}
+		resp.WriteHeader(http.StatusAccepted)
+
+	default:
+		h.sendResponse(resp, http.StatusMethodNotAllowed, errors.Errorf("invalid request method: %s", req.Method))
+	}
+}
+
+func (h *AdminHandler) chain(channel string) (*Chain, error) {
+	cs := h.Chains.GetChain(channel)
+	if cs == nil {
+		return nil, errors.Errorf("channel %s does not exist", channel)
+	}
+	chain, isRaft := cs.Chain.(*Chain)
+	if !isRaft {
+		return nil, errors.Errorf("channel %s is not serviced by a Raft node", channel)
+	}
+	return chain, nil
+}
+
+// reachability returns whether the given consenter can be reached,
+// along with the state of the connection to it.
+func (h *AdminHandler) reachability(channel string, id uint64) (bool, string) {
+	remote, err := h.Communication.Remote(channel, id)
+	if err != nil {
+		h.Logger.Debugf("Consenter %d of channel %s is unreachable: %s", id, channel, err)
+		return false, ""
+	}
+	state := remote.ConnectionState()
+	return state == connectivity.Ready, state.String()
+}
+
+func (h *AdminHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	if err, ok := payload.(error); ok {
+		payload = &errorResponse{Error: err.Error()}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		h.Logger.Errorw("failed to encode payload", "error", err)
+	}
+}
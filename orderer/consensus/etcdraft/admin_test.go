@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	clustermocks "github.com/hyperledger/fabric/orderer/common/cluster/mocks"
+	"github.com/hyperledger/fabric/orderer/common/multichannel"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
+	"github.com/hyperledger/fabric/orderer/consensus/etcdraft/mocks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AdminHandler", func() {
+	var (
+		chainGetter *mocks.ChainGetter
+		handler     *etcdraft.AdminHandler
+	)
+
+	BeforeEach(func() {
+		chainGetter = &mocks.ChainGetter{}
+		chainGetter.On("GetChain", "mychannel").Return(&multichannel.ChainSupport{Chain: &etcdraft.Chain{}})
+		chainGetter.On("GetChain", "notmychannel").Return(nil)
+		chainGetter.On("GetChain", "notraftchain").Return(&multichannel.ChainSupport{
+			Chain: &multichannel.ChainSupport{},
+		})
+
+		handler = &etcdraft.AdminHandler{
+			Chains:        chainGetter,
+			Communication: &clustermocks.Communicator{},
+			Logger:        flogging.MustGetLogger("test"),
+		}
+	})
+
+	serve := func(method, path, body string) (int, string) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		if resp.Code != http.StatusAccepted {
+			Expect(resp.Header().Get("Content-Type")).To(Equal("application/json"))
+		}
+
+		var errResp struct{ Error string }
+		if resp.Code >= http.StatusBadRequest {
+			Expect(json.Unmarshal(resp.Body.Bytes(), &errResp)).To(Succeed())
+		}
+		return resp.Code, errResp.Error
+	}
+
+	It("rejects invalid paths", func() {
+		for _, path := range []string{"/raft/", "/raft/mychannel/follower", "/raft/mychannel/leader/1"} {
+			code, msg := serve(http.MethodGet, path, "")
+			Expect(code).To(Equal(http.StatusNotFound))
+			Expect(msg).To(Equal("invalid path: " + path))
+		}
+	})
+
+	It("rejects unknown channels", func() {
+		code, msg := serve(http.MethodGet, "/raft/notmychannel", "")
+		Expect(code).To(Equal(http.StatusNotFound))
+		Expect(msg).To(Equal("channel notmychannel does not exist"))
+	})
+
+	It("rejects channels which are not serviced by Raft", func() {
+		code, msg := serve(http.MethodGet, "/raft/notraftchain", "")
+		Expect(code).To(Equal(http.StatusNotFound))
+		Expect(msg).To(Equal("channel notraftchain is not serviced by a Raft node"))
+	})
+
+	It("rejects unsupported methods", func() {
+		code, msg := serve(http.MethodPost, "/raft/mychannel", "")
+		Expect(code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(msg).To(Equal("invalid request method: POST"))
+
+		code, msg = serve(http.MethodGet, "/raft/mychannel/leader", "")
+		Expect(code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(msg).To(Equal("invalid request method: GET"))
+	})
+
+	It("reports chains which are not started as unavailable", func() {
+		code, msg := serve(http.MethodGet, "/raft/mychannel", "")
+		Expect(code).To(Equal(http.StatusServiceUnavailable))
+		Expect(msg).To(Equal("chain is not started"))
+	})
+
+	It("rejects malformed leadership transfers", func() {
+		code, msg := serve(http.MethodPut, "/raft/mychannel/leader", "{{")
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(msg).To(ContainSubstring("invalid character"))
+
+		code, msg = serve(http.MethodPut, "/raft/mychannel/leader", `{"to": 2}`)
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(msg).To(Equal("chain is not started"))
+	})
+})
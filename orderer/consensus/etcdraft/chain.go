@@ -20,7 +20,10 @@ import (
 	"github.com/coreos/etcd/wal"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	"github.com/hyperledger/fabric/protos/common"
@@ -83,6 +86,18 @@ type Options struct {
 	MaxInflightMsgs int
 
 	RaftMetadata *etcdraft.RaftMetadata
+
+	// MigrationInit is set when the chain is started on a ledger written by Kafka
+	// in a consensus-type migration, in which case the Raft cluster is bootstrapped
+	// rather than joined, despite the ledger height.
+	MigrationInit bool
+
+	// Metrics of the chain, which are discarded when nil
+	Metrics *Metrics
+
+	// Encrypter encrypts the WAL entries and the snapshots, which are persisted
+	// in plaintext when nil
+	Encrypter *encryption.Encrypter
 }
 
 type submit struct {
@@ -128,7 +143,8 @@ type Chain struct {
 	node *node
 	opts Options
 
-	logger *flogging.FabricLogger
+	metrics *Metrics
+	logger  *flogging.FabricLogger
 }
 
 // NewChain constructs a chain object.
@@ -142,8 +158,12 @@ func NewChain(
 
 	lg := opts.Logger.With("channel", support.ChainID(), "node", opts.RaftID)
 
+	// A WAL replaced by its re-encrypted copy is restored before telling whether the node is fresh
+	if err := recoverReencryptedWAL(opts.WALDir); err != nil {
+		return nil, errors.Errorf("failed to restore persisted raft data: %s", err)
+	}
 	fresh := !wal.Exist(opts.WALDir)
-	storage, err := CreateStorage(lg, opts.WALDir, opts.SnapDir, opts.MemoryStorage)
+	storage, err := CreateStorage(lg, opts.WALDir, opts.SnapDir, opts.MemoryStorage, opts.Encrypter)
 	if err != nil {
 		return nil, errors.Errorf("failed to restore persisted raft data: %s", err)
 	}
@@ -161,6 +181,10 @@ func NewChain(
 		snapBlkNum = b.Header.Number
 	}
 
+	if opts.Metrics == nil {
+		opts.Metrics = NewMetrics(&disabled.Provider{})
+	}
+
 	c := &Chain{
 		configurator:     conf,
 		rpc:              rpc,
@@ -181,8 +205,13 @@ func NewChain(
 		clock:            opts.Clock,
 		logger:           lg,
 		opts:             opts,
+		metrics:          opts.Metrics.forChannel(support.ChainID()),
 	}
 
+	c.metrics.IsLeader.Set(0)
+	c.metrics.AppliedIndex.Set(float64(c.appliedIndex))
+	c.metrics.ConfigChangeInFlight.Set(0)
+
 	// DO NOT use Applied option in config, see https://github.com/etcd-io/etcd/issues/10217
 	// We guard against replay of written blocks in `entriesToApply` instead.
 	config := &raft.Config{
@@ -224,7 +253,12 @@ func (c *Chain) Start() {
 		return
 	}
 
-	c.node.start(c.fresh, c.support.Height() > 1)
+	isJoin := c.support.Height() > 1
+	if isJoin && c.opts.MigrationInit {
+		isJoin = false
+		c.logger.Infof("Consensus-type migration detected, starting new Raft node on an existing channel; height=%d", c.support.Height())
+	}
+	c.node.start(c.fresh, isJoin)
 	close(c.startC)
 
 	go c.serveRequest()
@@ -419,6 +453,7 @@ func (c *Chain) serveRequest() {
 		if cc := c.getInFlightConfChange(); cc != nil {
 			if err := c.node.ProposeConfChange(context.TODO(), *cc); err != nil {
 				c.logger.Warnf("Failed to propose configuration update to Raft node: %s", err)
+				c.metrics.ProposalFailures.Add(1)
 			}
 
 			c.confChangeInProgress = cc
@@ -427,7 +462,7 @@ func (c *Chain) serveRequest() {
 	}
 
 	becomeFollower := func() {
-		_ = c.support.BlockCutter().Cut()
+		blockcutter.Drain(c.support.BlockCutter())
 		stop()
 		submitC = c.submitC
 		bc = nil
@@ -475,12 +510,15 @@ func (c *Chain) serveRequest() {
 				newLeader := atomic.LoadUint64(&app.soft.Lead) // etcdraft requires atomic access
 				if newLeader != leader {
 					c.logger.Infof("Raft leader changed: %d -> %d", leader, newLeader)
+					c.metrics.LeaderChanges.Add(1)
 
 					if newLeader == c.raftID {
+						c.metrics.IsLeader.Set(1)
 						becomeLeader()
 					}
 
 					if leader == c.raftID {
+						c.metrics.IsLeader.Set(0)
 						becomeFollower()
 					}
 
@@ -509,8 +547,13 @@ func (c *Chain) serveRequest() {
 				continue
 			}
 
+			batches := [][]*common.Envelope{batch}
+			for batch = c.support.BlockCutter().Cut(); len(batch) > 0; batch = c.support.BlockCutter().Cut() {
+				batches = append(batches, batch)
+			}
+
 			c.logger.Debugf("Batch timer expired, creating block")
-			c.propose(bc, batch) // we are certain this is normal block, no need to block
+			c.propose(bc, batches...) // we are certain these are normal blocks, no need to block
 
 		case sn := <-c.snapC:
 			if sn.Metadata.Index <= c.appliedIndex {
@@ -527,11 +570,18 @@ func (c *Chain) serveRequest() {
 				c.logger.Errorf("Failed to recover from snapshot taken at Term %d and Index %d: %s",
 					sn.Metadata.Term, sn.Metadata.Index, err)
 			}
+			c.metrics.AppliedIndex.Set(float64(c.appliedIndex))
 
 		case <-c.doneC:
 			c.logger.Infof("Stop serving requests")
 			return
 		}
+
+		if c.configInflight {
+			c.metrics.ConfigChangeInFlight.Set(1)
+		} else {
+			c.metrics.ConfigChangeInFlight.Set(0)
+		}
 	}
 }
 
@@ -566,9 +616,8 @@ func (c *Chain) ordered(msg *orderer.SubmitRequest) (batches [][]*common.Envelop
 				return nil, true, errors.Errorf("bad config message: %s", err)
 			}
 		}
-		batch := c.support.BlockCutter().Cut()
 		batches = [][]*common.Envelope{}
-		if len(batch) != 0 {
+		for batch := c.support.BlockCutter().Cut(); len(batch) != 0; batch = c.support.BlockCutter().Cut() {
 			batches = append(batches, batch)
 		}
 		batches = append(batches, []*common.Envelope{msg.Content})
@@ -591,6 +640,7 @@ func (c *Chain) propose(bc *blockCreator, batches ...[]*common.Envelope) {
 		data := utils.MarshalOrPanic(b)
 		if err := c.node.Propose(context.TODO(), data); err != nil {
 			c.logger.Errorf("Failed to propose block to raft: %s", err)
+			c.metrics.ProposalFailures.Add(1)
 			return // don't bother continue proposing next batch
 		}
 
@@ -700,6 +750,7 @@ func (c *Chain) apply(ents []raftpb.Entry) {
 			c.appliedIndex = ents[i].Index
 		}
 	}
+	c.metrics.AppliedIndex.Set(float64(c.appliedIndex))
 
 	if c.opts.SnapInterval == 0 || appliedb == 0 {
 		// snapshot is not enabled (SnapInterval == 0) or
@@ -732,6 +783,7 @@ func (c *Chain) configureComm() error {
 	}
 
 	c.configurator.Configure(c.channelID, nodes)
+	c.metrics.ClusterSize.Set(float64(len(nodes) + 1))
 	return nil
 }
 
@@ -813,6 +865,7 @@ func (c *Chain) writeConfigBlock(block *common.Block, index uint64) {
 		// This proposal is dropped by followers because DisableProposalForwarding is enabled.
 		if err := c.node.ProposeConfChange(context.TODO(), *confChange); err != nil {
 			c.logger.Warnf("Failed to propose configuration update to Raft node: %s", err)
+			c.metrics.ProposalFailures.Add(1)
 		}
 
 		c.confChangeInProgress = confChange
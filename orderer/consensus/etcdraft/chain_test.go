@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/consensus/etcdraft"
@@ -204,6 +205,65 @@ var _ = Describe("Chain", func() {
 			})
 		})
 
+		Context("when metrics are provided", func() {
+			var (
+				clusterSize   *metricsfakes.Gauge
+				isLeader      *metricsfakes.Gauge
+				leaderChanges *metricsfakes.Counter
+				appliedIndex  *metricsfakes.Gauge
+			)
+
+			newGauge := func() *metricsfakes.Gauge {
+				g := &metricsfakes.Gauge{}
+				g.WithReturns(g)
+				return g
+			}
+
+			newCounter := func() *metricsfakes.Counter {
+				c := &metricsfakes.Counter{}
+				c.WithReturns(c)
+				return c
+			}
+
+			BeforeEach(func() {
+				clusterSize = newGauge()
+				isLeader = newGauge()
+				leaderChanges = newCounter()
+				appliedIndex = newGauge()
+				opts.Metrics = &etcdraft.Metrics{
+					ClusterSize:          clusterSize,
+					IsLeader:             isLeader,
+					LeaderChanges:        leaderChanges,
+					CommittedIndex:       newGauge(),
+					AppliedIndex:         appliedIndex,
+					ProposalFailures:     newCounter(),
+					SnapshotCount:        newCounter(),
+					SnapshotSize:         newGauge(),
+					ConfigChangeInFlight: newGauge(),
+				}
+			})
+
+			It("reports the state of the chain", func() {
+				Expect(clusterSize.WithArgsForCall(0)).To(Equal([]string{"channel", channelID}))
+				Expect(clusterSize.SetCallCount()).To(Equal(1))
+				Expect(clusterSize.SetArgsForCall(0)).To(Equal(float64(1)))
+
+				campaign(clock, observeC)
+				Eventually(leaderChanges.AddCallCount, LongEventualTimeout).Should(Equal(1))
+				Expect(isLeader.SetArgsForCall(isLeader.SetCallCount() - 1)).To(Equal(float64(1)))
+
+				close(cutter.Block)
+				cutter.CutNext = true
+				appliedBefore := appliedIndex.SetArgsForCall(appliedIndex.SetCallCount() - 1)
+				err := chain.Order(env, 0)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(1))
+				Eventually(func() float64 {
+					return appliedIndex.SetArgsForCall(appliedIndex.SetCallCount() - 1)
+				}, LongEventualTimeout).Should(BeNumerically(">", appliedBefore))
+			})
+		})
+
 		Context("when Raft leader is elected", func() {
 			JustBeforeEach(func() {
 				campaign(clock, observeC)
@@ -1265,6 +1325,59 @@ var _ = Describe("Chain", func() {
 			})
 		})
 
+		When("3/3 nodes are running", func() {
+			JustBeforeEach(func() {
+				network.init()
+				network.start()
+				network.elect(1)
+			})
+
+			AfterEach(func() {
+				network.stop()
+			})
+
+			It("reports the status of the Raft node", func() {
+				status, err := c2.Status()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Channel).To(Equal(channelID))
+				Expect(status.ID).To(Equal(uint64(2)))
+				Expect(status.Leader).To(Equal(uint64(1)))
+				Expect(status.Term).NotTo(BeZero())
+				Expect(status.Consenters).To(Equal([]etcdraft.ConsenterStatus{
+					{ID: 1, Endpoint: "localhost:7051"},
+					{ID: 2, Endpoint: "localhost:7051", Reachable: true},
+					{ID: 3, Endpoint: "localhost:7051"},
+				}))
+			})
+
+			It("transfers the leadership to another consenter", func() {
+				Expect(c1.TransferLeadership(3)).To(Succeed())
+
+				Eventually(c1.observe, LongEventualTimeout).Should(Receive(Equal(uint64(3))))
+				Eventually(c2.observe, LongEventualTimeout).Should(Receive(Equal(uint64(3))))
+				Eventually(c3.observe, LongEventualTimeout).Should(Receive(Equal(uint64(3))))
+
+				status, err := c1.Status()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(status.Leader).To(Equal(uint64(3)))
+			})
+
+			It("refuses to transfer the leadership from a follower", func() {
+				err := c2.TransferLeadership(3)
+				Expect(err).To(MatchError("node 2 is not the leader of channel multi-node-channel, the leader is node 1"))
+			})
+
+			It("refuses to transfer the leadership to a node which is not a consenter", func() {
+				err := c1.TransferLeadership(4)
+				Expect(err).To(MatchError("node 4 is not a consenter of channel multi-node-channel"))
+			})
+
+			It("refuses to transfer the leadership to the leader", func() {
+				err := c1.TransferLeadership(1)
+				Expect(err).To(MatchError("node 1 is already the leader of channel multi-node-channel"))
+			})
+		})
+
 		When("reconfiguring raft cluster", func() {
 			const (
 				defaultTimeout = 5 * time.Second
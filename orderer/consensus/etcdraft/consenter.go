@@ -8,6 +8,7 @@ package etcdraft
 
 import (
 	"bytes"
+	"os"
 	"path"
 	"reflect"
 	"time"
@@ -15,7 +16,9 @@ import (
 	"code.cloudfoundry.org/clock"
 	"github.com/coreos/etcd/raft"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/viperutil"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
@@ -23,6 +26,7 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	"github.com/hyperledger/fabric/orderer/consensus/inactive"
+	"github.com/hyperledger/fabric/orderer/consensus/migration"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/orderer/etcdraft"
@@ -39,6 +43,9 @@ type InactiveChainRegistry interface {
 	// TrackChain tracks a chain with the given name, and calls the given callback
 	// when this chain should be created.
 	TrackChain(chainName string, genesisBlock *common.Block, createChain CreateChainCallback)
+
+	// UntrackChain stops tracking the chain with the given name, if it is tracked.
+	UntrackChain(chainName string)
 }
 
 //go:generate mockery -dir . -name ChainGetter -case underscore -output mocks
@@ -69,6 +76,8 @@ type Consenter struct {
 	EtcdRaftConfig Config
 	OrdererConfig  localconfig.TopLevel
 	Cert           []byte
+	Metrics        *Metrics
+	Encrypter      *encryption.Encrypter
 }
 
 // TargetChannel extracts the channel from the given proto.Message.
@@ -85,7 +94,8 @@ func (c *Consenter) TargetChannel(message proto.Message) string {
 }
 
 // ReceiverByChain returns the MessageReceiver for the given channelID or nil
-// if not found.
+// if not found. Chains of other consenters which share the cluster communication,
+// such as the BFT chains, are MessageReceivers as well.
 func (c *Consenter) ReceiverByChain(channelID string) MessageReceiver {
 	cs := c.Chains.GetChain(channelID)
 	if cs == nil {
@@ -94,10 +104,10 @@ func (c *Consenter) ReceiverByChain(channelID string) MessageReceiver {
 	if cs.Chain == nil {
 		c.Logger.Panicf("Programming error - Chain %s is nil although it exists in the mapping", channelID)
 	}
-	if etcdRaftChain, isEtcdRaftChain := cs.Chain.(*Chain); isEtcdRaftChain {
-		return etcdRaftChain
+	if receiver, isReceiver := cs.Chain.(MessageReceiver); isReceiver {
+		return receiver
 	}
-	c.Logger.Warningf("Chain %s is of type %v and not etcdraft.Chain", channelID, reflect.TypeOf(cs.Chain))
+	c.Logger.Warningf("Chain %s is of type %v and not a cluster MessageReceiver", channelID, reflect.TypeOf(cs.Chain))
 	return nil
 }
 
@@ -163,9 +173,21 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 		SnapInterval:    m.Options.SnapshotInterval,
 
 		RaftMetadata: raftMetadata,
+		Metrics:      c.Metrics,
+		Encrypter:    c.Encrypter,
 
 		WALDir:  path.Join(c.EtcdRaftConfig.WALDir, support.ChainID()),
 		SnapDir: path.Join(c.EtcdRaftConfig.SnapDir, support.ChainID()),
+
+		// A chain without Raft metadata past its genesis block was written by Kafka,
+		// and is handed to Raft as part of a consensus-type migration
+		MigrationInit: (metadata == nil || len(metadata.Value) == 0) &&
+			support.Height() > 1 && migration.IsMigrated(support.SharedConfig()),
+	}
+
+	if opts.MigrationInit {
+		c.Logger.Infof("Channel %s is migrated from %s, bootstrapping the Raft cluster at height %d",
+			support.ChainID(), migration.FromType, support.Height())
 	}
 
 	rpc := &cluster.RPC{
@@ -176,6 +198,19 @@ func (c *Consenter) HandleChain(support consensus.ConsenterSupport, metadata *co
 	return NewChain(support, opts, c.Communication, rpc, bp, nil)
 }
 
+// RemoveChannel removes the WAL and the snapshots of the given channel, whose chain is halted,
+// and stops tracking the channel if this node is not one of its consenters.
+func (c *Consenter) RemoveChannel(channelID string) error {
+	c.InactiveChainRegistry.UntrackChain(channelID)
+	if err := os.RemoveAll(path.Join(c.EtcdRaftConfig.WALDir, channelID)); err != nil {
+		return errors.Wrapf(err, "failed removing the WAL of channel %s", channelID)
+	}
+	if err := os.RemoveAll(path.Join(c.EtcdRaftConfig.SnapDir, channelID)); err != nil {
+		return errors.Wrapf(err, "failed removing the snapshots of channel %s", channelID)
+	}
+	return nil
+}
+
 // ReadRaftMetadata attempts to read raft metadata from block metadata, if available.
 // otherwise, it reads raft metadata from config metadata supplied.
 func ReadRaftMetadata(blockMetadata *common.Metadata, configMetadata *etcdraft.Metadata) (*etcdraft.RaftMetadata, error) {
@@ -207,6 +242,8 @@ func New(
 	srv *comm.GRPCServer,
 	r *multichannel.Registrar,
 	icr InactiveChainRegistry,
+	metricsProvider metrics.Provider,
+	encrypter *encryption.Encrypter,
 ) *Consenter {
 	logger := flogging.MustGetLogger("orderer.consensus.etcdraft")
 
@@ -224,13 +261,15 @@ func New(
 		EtcdRaftConfig:        cfg,
 		OrdererConfig:         *conf,
 		Dialer:                clusterDialer,
+		Metrics:               NewMetrics(metricsProvider),
+		Encrypter:             encrypter,
 	}
 	consenter.Dispatcher = &Dispatcher{
 		Logger:        logger,
 		ChainSelector: consenter,
 	}
 
-	comm := createComm(clusterDialer, conf, consenter)
+	comm := createComm(clusterDialer, conf, consenter, metricsProvider)
 	consenter.Communication = comm
 	svc := &cluster.Service{
 		StepLogger: flogging.MustGetLogger("orderer.common.cluster.step"),
@@ -243,7 +282,8 @@ func New(
 
 func createComm(clusterDialer *cluster.PredicateDialer,
 	conf *localconfig.TopLevel,
-	c *Consenter) *cluster.Comm {
+	c *Consenter,
+	metricsProvider metrics.Provider) *cluster.Comm {
 	comm := &cluster.Comm{
 		Logger:       flogging.MustGetLogger("orderer.common.cluster"),
 		Chan2Members: make(map[string]cluster.MemberMapping),
@@ -251,6 +291,7 @@ func createComm(clusterDialer *cluster.PredicateDialer,
 		RPCTimeout:   conf.General.Cluster.RPCTimeout,
 		ChanExt:      c,
 		H:            c,
+		Metrics:      cluster.NewMetrics(metricsProvider),
 	}
 	c.Communication = comm
 	return comm
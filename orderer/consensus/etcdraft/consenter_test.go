@@ -165,6 +165,76 @@ var _ = Describe("Consenter", func() {
 		Expect(chain.Start).NotTo(Panic())
 	})
 
+	It("removes the WAL and the snapshots of a removed channel", func() {
+		m := &etcdraftproto.Metadata{
+			Consenters: []*etcdraftproto.Consenter{
+				{ServerTlsCert: []byte("cert.orderer0.org0")},
+			},
+			Options: &etcdraftproto.Options{
+				TickInterval:    100,
+				ElectionTick:    10,
+				HeartbeatTick:   1,
+				MaxInflightMsgs: 256,
+				MaxSizePerMsg:   1048576,
+			},
+		}
+		support.SharedConfigReturns(&mockconfig.Orderer{ConsensusMetadataVal: utils.MarshalOrPanic(m)})
+		support.ChainIDReturns("foo")
+
+		consenter := newConsenter(chainGetter)
+		consenter.EtcdRaftConfig.WALDir = walDir
+		consenter.EtcdRaftConfig.SnapDir = snapDir
+
+		chain, err := consenter.HandleChain(support, nil)
+		Expect(err).NotTo(HaveOccurred())
+		chain.Start()
+		chain.Halt()
+		Expect(path.Join(walDir, "foo")).To(BeADirectory())
+		Expect(os.MkdirAll(path.Join(snapDir, "foo"), 0755)).To(Succeed())
+
+		Expect(consenter.RemoveChannel("foo")).To(Succeed())
+		Expect(path.Join(walDir, "foo")).NotTo(BeAnExistingFile())
+		Expect(path.Join(snapDir, "foo")).NotTo(BeAnExistingFile())
+		consenter.icr.AssertCalled(testingInstance, "UntrackChain", "foo")
+	})
+
+	It("successfully constructs a Chain on a ledger migrated from kafka", func() {
+		certBytes := []byte("cert.orderer0.org0")
+		m := &etcdraftproto.Metadata{
+			Consenters: []*etcdraftproto.Consenter{
+				{ServerTlsCert: certBytes},
+			},
+			Options: &etcdraftproto.Options{
+				TickInterval:    100,
+				ElectionTick:    10,
+				HeartbeatTick:   1,
+				MaxInflightMsgs: 256,
+				MaxSizePerMsg:   1048576,
+			},
+		}
+		metadata := utils.MarshalOrPanic(m)
+		support.SharedConfigReturns(&mockconfig.Orderer{
+			ConsensusTypeVal:                 "etcdraft",
+			ConsensusMetadataVal:             metadata,
+			ConsensusTypeMigrationStateVal:   orderer.ConsensusType_MIG_STATE_COMMIT,
+			ConsensusTypeMigrationContextVal: 1,
+		})
+		support.HeightReturns(2)
+
+		consenter := newConsenter(chainGetter)
+		consenter.EtcdRaftConfig.WALDir = walDir
+		consenter.EtcdRaftConfig.SnapDir = snapDir
+
+		// The Kafka metadata is discarded by the registrar
+		chain, err := consenter.HandleChain(support, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(chain).NotTo(BeNil())
+
+		Expect(chain.Start).NotTo(Panic())
+		// The chain is halted before it elects itself and reads the ledger of the fake support
+		chain.Halt()
+	})
+
 	It("fails to handle chain if no matching cert found", func() {
 		m := &etcdraftproto.Metadata{
 			Consenters: []*etcdraftproto.Consenter{
@@ -222,6 +292,7 @@ func newConsenter(chainGetter *mocks.ChainGetter) *consenter {
 	communicator.On("Configure", mock.Anything, mock.Anything)
 	icr := &mocks.InactiveChainRegistry{}
 	icr.On("TrackChain", "foo", mock.Anything, mock.Anything)
+	icr.On("UntrackChain", "foo")
 	c := &etcdraft.Consenter{
 		InactiveChainRegistry: icr,
 		Communication:         communicator,
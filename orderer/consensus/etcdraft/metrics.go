@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+var (
+	clusterSizeOpts = metrics.GaugeOpts{
+		Namespace:    "consensus",
+		Subsystem:    "etcdraft",
+		Name:         "cluster_size",
+		Help:         "Number of nodes in this channel.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	isLeaderOpts = metrics.GaugeOpts{
+		Namespace:    "consensus",
+		Subsystem:    "etcdraft",
+		Name:         "is_leader",
+		Help:         "The leadership status of the current node: 1 if it is the leader else 0.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	leaderChangesOpts = metrics.CounterOpts{
+		Namespace:    "consensus",
+		Subsystem:    "etcdraft",
+		Name:         "leader_changes",
+		Help:         "The number of leader changes since process start.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	committedIndexOpts = metrics.GaugeOpts{
+		Namespace:    "consensus",
+		Subsystem:    "etcdraft",
+		Name:         "committed_index",
+		Help:         "The index of the latest Raft entry known to be committed.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	appliedIndexOpts = metrics.GaugeOpts{
+		Namespace:    "consensus",
+		Subsystem:    "etcdraft",
+		Name:         "applied_index",
+		Help:         "The index of the latest Raft entry applied to the ledger.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	proposalFailuresOpts = metrics.CounterOpts{
+		Namespace:    "consensus",
+		Subsystem:    "etcdraft",
+		Name:         "proposal_failures",
+		Help:         "The number of proposal failures.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	snapshotCountOpts = metrics.CounterOpts{
+		Namespace:    "consensus",
+		Subsystem:    "etcdraft",
+		Name:         "snapshot_count",
+		Help:         "The number of snapshots taken since process start.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	snapshotSizeOpts = metrics.GaugeOpts{
+		Namespace:    "consensus",
+		Subsystem:    "etcdraft",
+		Name:         "snapshot_size",
+		Help:         "The size in bytes of the latest snapshot taken.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	configChangeInFlightOpts = metrics.GaugeOpts{
+		Namespace:    "consensus",
+		Subsystem:    "etcdraft",
+		Name:         "config_change_in_flight",
+		Help:         "Whether a config block or a Raft configuration change is in flight: 1 if it is else 0.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+)
+
+type Metrics struct {
+	ClusterSize          metrics.Gauge
+	IsLeader             metrics.Gauge
+	LeaderChanges        metrics.Counter
+	CommittedIndex       metrics.Gauge
+	AppliedIndex         metrics.Gauge
+	ProposalFailures     metrics.Counter
+	SnapshotCount        metrics.Counter
+	SnapshotSize         metrics.Gauge
+	ConfigChangeInFlight metrics.Gauge
+}
+
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		ClusterSize:          p.NewGauge(clusterSizeOpts),
+		IsLeader:             p.NewGauge(isLeaderOpts),
+		LeaderChanges:        p.NewCounter(leaderChangesOpts),
+		CommittedIndex:       p.NewGauge(committedIndexOpts),
+		AppliedIndex:         p.NewGauge(appliedIndexOpts),
+		ProposalFailures:     p.NewCounter(proposalFailuresOpts),
+		SnapshotCount:        p.NewCounter(snapshotCountOpts),
+		SnapshotSize:         p.NewGauge(snapshotSizeOpts),
+		ConfigChangeInFlight: p.NewGauge(configChangeInFlightOpts),
+	}
+}
+
+// forChannel returns the metrics of the given channel.
+func (m *Metrics) forChannel(channel string) *Metrics {
+	return &Metrics{
+		ClusterSize:          m.ClusterSize.With("channel", channel),
+		IsLeader:             m.IsLeader.With("channel", channel),
+		LeaderChanges:        m.LeaderChanges.With("channel", channel),
+		CommittedIndex:       m.CommittedIndex.With("channel", channel),
+		AppliedIndex:         m.AppliedIndex.With("channel", channel),
+		ProposalFailures:     m.ProposalFailures.With("channel", channel),
+		SnapshotCount:        m.SnapshotCount.With("channel", channel),
+		SnapshotSize:         m.SnapshotSize.With("channel", channel),
+		ConfigChangeInFlight: m.ConfigChangeInFlight.With("channel", channel),
+	}
+}
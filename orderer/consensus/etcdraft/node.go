@@ -73,6 +73,10 @@ func (n *node) run() {
 				n.logger.Panicf("Failed to persist etcd/raft data: %s", err)
 			}
 
+			if !raft.IsEmptyHardState(rd.HardState) {
+				n.chain.metrics.CommittedIndex.Set(float64(rd.HardState.Commit))
+			}
+
 			if !raft.IsEmptySnap(rd.Snapshot) {
 				n.chain.snapC <- &rd.Snapshot
 			}
@@ -122,4 +126,8 @@ func (n *node) takeSnapshot(index uint64, cs *raftpb.ConfState, data []byte) {
 	if err := n.storage.TakeSnapshot(index, cs, data); err != nil {
 		n.logger.Panicf("Failed to create snapshot at index %d: %s", index, err)
 	}
+
+	snap := n.storage.Snapshot()
+	n.chain.metrics.SnapshotCount.Add(1)
+	n.chain.metrics.SnapshotSize.Set(float64(snap.Size()))
 }
@@ -0,0 +1,239 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/coreos/etcd/pkg/fileutil"
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/coreos/etcd/snap"
+	"github.com/coreos/etcd/wal"
+	"github.com/coreos/etcd/wal/walpb"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+const (
+	// reencryptedSuffix suffixes the dirs where the re-encrypted copies of the WAL and snapshots are written
+	reencryptedSuffix = ".reencrypting"
+	// retiredWALSuffix suffixes the dir of the WAL while it is replaced by its re-encrypted copy
+	retiredWALSuffix = ".retired"
+	snapSuffix       = ".snap"
+)
+
+// hasDataToReencrypt returns whether the last snapshot or the entries of the WAL following it are
+// encrypted with the previous keys, in which case the encryption key was rotated since the raft data
+// was last persisted. The older snapshots and WAL entries are re-encrypted before these, so that they
+// tell whether the re-encryption was completed.
+func hasDataToReencrypt(snapshot *raftpb.Snapshot, ents []raftpb.Entry, encrypter *encryption.Encrypter) bool {
+	if snapshot != nil && isEncryptedWithPreviousKey(snapshot.Data, encrypter) {
+		return true
+	}
+	for _, entry := range ents {
+		if isEncryptedWithPreviousKey(entry.Data, encrypter) {
+			return true
+		}
+	}
+	return false
+}
+
+func isEncryptedWithPreviousKey(data []byte, encrypter *encryption.Encrypter) bool {
+	return encryption.IsEncrypted(data) && !encrypter.IsCurrent(data)
+}
+
+// reencryptStorage re-encrypts the persisted raft data with the current key: the snapshots preceding
+// the given last snapshot, then the WAL, which is replaced by a WAL holding the last snapshot record,
+// the HardState and the entries following the last snapshot, and finally the last snapshot. The older
+// WAL entries, which precede the last snapshot, are dropped along with the WAL files holding them.
+func reencryptStorage(
+	lg *flogging.FabricLogger,
+	walDir string,
+	snapDir string,
+	snapshot *raftpb.Snapshot,
+	metadata []byte,
+	st raftpb.HardState,
+	ents []raftpb.Entry,
+	encrypter *encryption.Encrypter,
+) error {
+	lg.Infof("Re-encrypting the raft data persisted at '%s' and '%s' with the current key", walDir, snapDir)
+
+	lastSnapName := ""
+	walsnap := walpb.Snapshot{}
+	if snapshot != nil {
+		lastSnapName = fmt.Sprintf("%016x-%016x%s", snapshot.Metadata.Term, snapshot.Metadata.Index, snapSuffix)
+		walsnap.Index, walsnap.Term = snapshot.Metadata.Index, snapshot.Metadata.Term
+	}
+
+	files, err := ioutil.ReadDir(snapDir)
+	if err != nil {
+		return errors.Errorf("failed to read snapshot dir: %s", err)
+	}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), snapSuffix) || file.Name() == lastSnapName {
+			continue
+		}
+		if err := reencryptSnapshotFile(lg, snapDir, file.Name(), encrypter); err != nil {
+			return err
+		}
+	}
+
+	if err := reencryptWAL(walDir, walsnap, metadata, st, ents, encrypter); err != nil {
+		return err
+	}
+
+	if lastSnapName != "" {
+		if err := reencryptSnapshotFile(lg, snapDir, lastSnapName, encrypter); err != nil {
+			return err
+		}
+	}
+	lg.Infof("Re-encrypted the raft data with the current key, the previous keys are no longer needed")
+	return nil
+}
+
+// reencryptSnapshotFile replaces the given snapshot file with a copy of the snapshot re-encrypted with
+// the current key, if it is encrypted with a previous key. A broken snapshot, which is never loaded,
+// is left as it is.
+func reencryptSnapshotFile(lg *flogging.FabricLogger, snapDir string, name string, encrypter *encryption.Encrypter) error {
+	snapshot, err := snap.Read(filepath.Join(snapDir, name))
+	if err != nil {
+		lg.Warnf("Snapshot '%s' is not re-encrypted as it cannot be read: %s", name, err)
+		return nil
+	}
+	if !isEncryptedWithPreviousKey(snapshot.Data, encrypter) {
+		return nil
+	}
+	if snapshot.Data, err = reencryptData(snapshot.Data, encrypter); err != nil {
+		return errors.Errorf("failed to re-encrypt snapshot '%s': %s", name, err)
+	}
+
+	// the snapshot is written in another dir, and then moved over the snapshot file
+	tmpDir := filepath.Clean(snapDir) + reencryptedSuffix
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return errors.Errorf("failed to remove dir '%s': %s", tmpDir, err)
+	}
+	if err := os.MkdirAll(tmpDir, os.ModePerm); err != nil {
+		return errors.Errorf("failed to mkdir '%s': %s", tmpDir, err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := snap.New(tmpDir).SaveSnap(*snapshot); err != nil {
+		return errors.Errorf("failed to save re-encrypted snapshot '%s': %s", name, err)
+	}
+	if err := os.Rename(filepath.Join(tmpDir, name), filepath.Join(snapDir, name)); err != nil {
+		return errors.Errorf("failed to replace snapshot '%s': %s", name, err)
+	}
+	return syncDir(snapDir)
+}
+
+// reencryptWAL creates a WAL holding the given snapshot record, HardState and entries, with the data
+// of the entries encrypted with the current key, and replaces the WAL with it. The WAL being replaced
+// is moved aside, so that it is restored by recoverReencryptedWAL if the replacement is interrupted.
+func reencryptWAL(
+	walDir string,
+	walsnap walpb.Snapshot,
+	metadata []byte,
+	st raftpb.HardState,
+	ents []raftpb.Entry,
+	encrypter *encryption.Encrypter,
+) error {
+	walEntries := make([]raftpb.Entry, len(ents))
+	for i, entry := range ents {
+		walEntries[i] = entry
+		if len(entry.Data) == 0 || (encryption.IsEncrypted(entry.Data) && encrypter.IsCurrent(entry.Data)) {
+			continue
+		}
+		data, err := reencryptData(entry.Data, encrypter)
+		if err != nil {
+			return errors.Errorf("failed to re-encrypt WAL entry at Index %d: %s", entry.Index, err)
+		}
+		walEntries[i].Data = data
+	}
+
+	newDir := filepath.Clean(walDir) + reencryptedSuffix
+	if err := os.RemoveAll(newDir); err != nil {
+		return errors.Errorf("failed to remove dir '%s': %s", newDir, err)
+	}
+	w, err := wal.Create(newDir, metadata)
+	if err != nil {
+		return errors.Errorf("failed to create re-encrypted WAL: %s", err)
+	}
+	if err := w.SaveSnapshot(walsnap); err != nil {
+		w.Close()
+		return errors.Errorf("failed to save snapshot to re-encrypted WAL: %s", err)
+	}
+	if err := w.Save(st, walEntries); err != nil {
+		w.Close()
+		return errors.Errorf("failed to save entries to re-encrypted WAL: %s", err)
+	}
+	if err := w.Close(); err != nil {
+		return errors.Errorf("failed to close re-encrypted WAL: %s", err)
+	}
+
+	retiredDir := filepath.Clean(walDir) + retiredWALSuffix
+	if err := os.Rename(walDir, retiredDir); err != nil {
+		return errors.Errorf("failed to move WAL aside: %s", err)
+	}
+	if err := os.Rename(newDir, walDir); err != nil {
+		return errors.Errorf("failed to replace WAL with re-encrypted WAL: %s", err)
+	}
+	if err := syncDir(filepath.Dir(filepath.Clean(walDir))); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(retiredDir); err != nil {
+		return errors.Errorf("failed to remove dir '%s': %s", retiredDir, err)
+	}
+	return nil
+}
+
+// recoverReencryptedWAL completes the replacement of the WAL by its re-encrypted copy, if it was
+// interrupted by a crash: the WAL moved aside is restored unless its copy replaced it already, in
+// which case it is removed. A copy which did not replace the WAL is removed, the re-encryption
+// is done again.
+func recoverReencryptedWAL(walDir string) error {
+	retiredDir := filepath.Clean(walDir) + retiredWALSuffix
+	if _, err := os.Stat(retiredDir); err == nil {
+		if _, err := os.Stat(walDir); os.IsNotExist(err) {
+			if err := os.Rename(retiredDir, walDir); err != nil {
+				return errors.Errorf("failed to restore WAL: %s", err)
+			}
+		} else if err := os.RemoveAll(retiredDir); err != nil {
+			return errors.Errorf("failed to remove dir '%s': %s", retiredDir, err)
+		}
+	}
+	newDir := filepath.Clean(walDir) + reencryptedSuffix
+	if err := os.RemoveAll(newDir); err != nil {
+		return errors.Errorf("failed to remove dir '%s': %s", newDir, err)
+	}
+	return nil
+}
+
+// reencryptData encrypts the data of an entry or snapshot with the current key, whether it
+// is encrypted with a previous key or not encrypted.
+func reencryptData(data []byte, encrypter *encryption.Encrypter) ([]byte, error) {
+	plaintext, err := decryptData(data, encrypter)
+	if err != nil {
+		return nil, err
+	}
+	return encryptData(plaintext, encrypter)
+}
+
+func syncDir(dir string) error {
+	d, err := fileutil.OpenDir(dir)
+	if err != nil {
+		return errors.Errorf("failed to open dir '%s': %s", dir, err)
+	}
+	defer d.Close()
+	if err := fileutil.Fsync(d); err != nil {
+		return errors.Errorf("failed to sync dir '%s': %s", dir, err)
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/coreos/etcd/snap"
 	"github.com/coreos/etcd/wal"
 	"github.com/coreos/etcd/wal/walpb"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/pkg/errors"
 )
@@ -40,45 +41,54 @@ type RaftStorage struct {
 	ram  MemoryStorage
 	wal  *wal.WAL
 	snap *snap.Snapshotter
+
+	// encrypter encrypts the data of the entries and snapshots persisted to disk,
+	// they are left as they are when it is nil
+	encrypter *encryption.Encrypter
 }
 
 // CreateStorage attempts to create a storage to persist etcd/raft data.
 // If data presents in specified disk, they are loaded to reconstruct storage state.
+// The data persisted is encrypted with the given encrypter, if not nil. If the encryption key was
+// rotated, the data persisted is re-encrypted with the current key before it is loaded.
 func CreateStorage(
 	lg *flogging.FabricLogger,
 	walDir string,
 	snapDir string,
 	ram MemoryStorage,
+	encrypter *encryption.Encrypter,
 ) (*RaftStorage, error) {
 
-	sn, err := createSnapshotter(snapDir)
-	if err != nil {
+	if err := recoverReencryptedWAL(walDir); err != nil {
 		return nil, err
 	}
 
-	snapshot, err := sn.Load()
+	sn, err := createSnapshotter(snapDir)
 	if err != nil {
-		if err == snap.ErrNoSnapshot {
-			lg.Debugf("No snapshot found at %s", snapDir)
-		} else {
-			return nil, errors.Errorf("failed to load snapshot: %s", err)
-		}
-	} else {
-		// snapshot found
-		lg.Debugf("Loaded snapshot at Term %d and Index %d", snapshot.Metadata.Term, snapshot.Metadata.Index)
+		return nil, err
 	}
 
-	w, err := createWAL(lg, walDir, snapshot)
+	snapshot, w, metadata, st, ents, err := loadStorage(lg, walDir, snapDir, sn)
 	if err != nil {
 		return nil, err
 	}
 
-	_, st, ents, err := w.ReadAll()
-	if err != nil {
-		return nil, errors.Errorf("failed to read WAL: %s", err)
+	if encrypter != nil && hasDataToReencrypt(snapshot, ents, encrypter) {
+		if err := w.Close(); err != nil {
+			return nil, errors.Errorf("failed to close WAL: %s", err)
+		}
+		if err := reencryptStorage(lg, walDir, snapDir, snapshot, metadata, st, ents, encrypter); err != nil {
+			return nil, errors.Errorf("failed to re-encrypt the persisted raft data with the current key: %s", err)
+		}
+		if snapshot, w, _, st, ents, err = loadStorage(lg, walDir, snapDir, sn); err != nil {
+			return nil, err
+		}
 	}
 
 	if snapshot != nil {
+		if snapshot.Data, err = decryptData(snapshot.Data, encrypter); err != nil {
+			return nil, errors.Errorf("failed to decrypt snapshot: %s", err)
+		}
 		lg.Debugf("Applying snapshot to raft MemoryStorage")
 		if err := ram.ApplySnapshot(*snapshot); err != nil {
 			return nil, errors.Errorf("Failed to apply snapshot to memory: %s", err)
@@ -88,10 +98,65 @@ func CreateStorage(
 	lg.Debugf("Setting HardState to {Term: %d, Commit: %d}", st.Term, st.Commit)
 	ram.SetHardState(st) // MemoryStorage.SetHardState always returns nil
 
+	for i := range ents {
+		if ents[i].Data, err = decryptData(ents[i].Data, encrypter); err != nil {
+			return nil, errors.Errorf("failed to decrypt WAL entry at Index %d: %s", ents[i].Index, err)
+		}
+	}
+
 	lg.Debugf("Appending %d entries to memory storage", len(ents))
 	ram.Append(ents) // MemoryStorage.Append always return nil
 
-	return &RaftStorage{lg: lg, ram: ram, wal: w, snap: sn}, nil
+	return &RaftStorage{lg: lg, ram: ram, wal: w, snap: sn, encrypter: encrypter}, nil
+}
+
+// loadStorage loads the last snapshot, if any, and reads the WAL from it.
+func loadStorage(lg *flogging.FabricLogger, walDir string, snapDir string, sn *snap.Snapshotter) (
+	*raftpb.Snapshot, *wal.WAL, []byte, raftpb.HardState, []raftpb.Entry, error) {
+
+	snapshot, err := sn.Load()
+	if err != nil {
+		if err == snap.ErrNoSnapshot {
+			lg.Debugf("No snapshot found at %s", snapDir)
+		} else {
+			return nil, nil, nil, raftpb.HardState{}, nil, errors.Errorf("failed to load snapshot: %s", err)
+		}
+	} else {
+		// snapshot found
+		lg.Debugf("Loaded snapshot at Term %d and Index %d", snapshot.Metadata.Term, snapshot.Metadata.Index)
+	}
+
+	w, err := createWAL(lg, walDir, snapshot)
+	if err != nil {
+		return nil, nil, nil, raftpb.HardState{}, nil, err
+	}
+
+	metadata, st, ents, err := w.ReadAll()
+	if err != nil {
+		return nil, nil, nil, raftpb.HardState{}, nil, errors.Errorf("failed to read WAL: %s", err)
+	}
+	return snapshot, w, metadata, st, ents, nil
+}
+
+// encryptData encrypts the data of an entry or snapshot, unless it is empty.
+func encryptData(data []byte, encrypter *encryption.Encrypter) ([]byte, error) {
+	if encrypter == nil || len(data) == 0 {
+		return data, nil
+	}
+	return encrypter.Encrypt(data)
+}
+
+// decryptData decrypts the data of an entry or snapshot, if it is encrypted. The data
+// is a marshaled protobuf message, which is told apart from the encrypted data, so that
+// the entries and snapshots persisted before the encryption was enabled are still read.
+func decryptData(data []byte, encrypter *encryption.Encrypter) ([]byte, error) {
+	if !encryption.IsEncrypted(data) {
+		return data, nil
+	}
+	if encrypter == nil {
+		return nil, errors.New("data is encrypted, the encryption must be enabled to read it")
+	}
+	return encrypter.Decrypt(data)
 }
 
 func createSnapshotter(snapDir string) (*snap.Snapshotter, error) {
@@ -146,7 +211,21 @@ func (rs *RaftStorage) Snapshot() raftpb.Snapshot {
 
 // Store persists etcd/raft data
 func (rs *RaftStorage) Store(entries []raftpb.Entry, hardstate raftpb.HardState, snapshot raftpb.Snapshot) error {
-	if err := rs.wal.Save(hardstate, entries); err != nil {
+	walEntries := entries
+	if rs.encrypter != nil {
+		// The entries appended to the MemoryStorage are kept in plaintext
+		walEntries = make([]raftpb.Entry, len(entries))
+		for i, entry := range entries {
+			data, err := encryptData(entry.Data, rs.encrypter)
+			if err != nil {
+				return errors.Errorf("failed to encrypt entry at Index %d: %s", entry.Index, err)
+			}
+			walEntries[i] = entry
+			walEntries[i].Data = data
+		}
+	}
+
+	if err := rs.wal.Save(hardstate, walEntries); err != nil {
 		return err
 	}
 
@@ -186,6 +265,12 @@ func (rs *RaftStorage) saveSnap(snap raftpb.Snapshot) error {
 		return errors.Errorf("failed to save snapshot to WAL: %s", err)
 	}
 
+	data, err := encryptData(snap.Data, rs.encrypter)
+	if err != nil {
+		return errors.Errorf("failed to encrypt snapshot: %s", err)
+	}
+	snap.Data = data
+
 	rs.lg.Debugf("Saving snapshot to disk")
 	if err := rs.snap.SaveSnap(snap); err != nil {
 		return errors.Errorf("failed to save snapshot to disk: %s", err)
@@ -0,0 +1,173 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package etcdraft
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/coreos/etcd/snap"
+	"github.com/coreos/etcd/wal"
+	"github.com/coreos/etcd/wal/walpb"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStorageEncryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "etcdraft-storage-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	walDir, snapDir := filepath.Join(dir, "wal"), filepath.Join(dir, "snap")
+	lg := flogging.NewFabricLogger(zap.NewNop())
+
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	require.NoError(t, err)
+	ski, err := encryption.GenerateKey(csp)
+	require.NoError(t, err)
+	encrypter, err := encryption.NewEncrypter(csp, encryption.NewKeyProvider(csp, ski))
+	require.NoError(t, err)
+
+	entries := []raftpb.Entry{
+		{Term: 1, Index: 1, Data: []byte("\x0aplain block")},
+		{Term: 1, Index: 2},
+		{Term: 1, Index: 3, Data: []byte("\x0aencrypted block")},
+		{Term: 1, Index: 4, Data: []byte("\x0aanother encrypted block")},
+	}
+
+	// The entries persisted before the encryption was enabled are still read
+	storage, err := CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage(), nil)
+	require.NoError(t, err)
+	require.NoError(t, storage.Store(entries[:2], raftpb.HardState{Term: 1, Commit: 2}, raftpb.Snapshot{}))
+	require.NoError(t, storage.Close())
+
+	storage, err = CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage(), encrypter)
+	require.NoError(t, err)
+	require.NoError(t, storage.Store(entries[2:], raftpb.HardState{Term: 1, Commit: 4}, raftpb.Snapshot{}))
+	require.NoError(t, storage.Close())
+
+	// The data of the entries persisted since is not readable on disk
+	w, err := wal.OpenForRead(walDir, walpb.Snapshot{})
+	require.NoError(t, err)
+	_, _, walEntries, err := w.ReadAll()
+	require.NoError(t, err)
+	w.Close()
+	require.Len(t, walEntries, 4)
+	assert.Equal(t, entries[:2], walEntries[:2])
+	for _, entry := range walEntries[2:] {
+		assert.True(t, encryption.IsEncrypted(entry.Data))
+		assert.False(t, bytes.Contains(entry.Data, []byte("block")))
+	}
+
+	storage, err = CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage(), encrypter)
+	require.NoError(t, err)
+	stored, err := storage.ram.Entries(1, 5, 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, entries, stored)
+	require.NoError(t, storage.TakeSnapshot(4, &raftpb.ConfState{Nodes: []uint64{1}}, []byte("\x0asnapshot block")))
+	require.NoError(t, storage.Close())
+
+	// Neither is the data of the snapshots
+	snapshot, err := snap.New(snapDir).Load()
+	require.NoError(t, err)
+	assert.True(t, encryption.IsEncrypted(snapshot.Data))
+
+	storage, err = CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage(), encrypter)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("\x0asnapshot block"), storage.Snapshot().Data)
+	require.NoError(t, storage.Close())
+
+	_, err = CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage(), nil)
+	assert.EqualError(t, err, "failed to decrypt snapshot: data is encrypted, the encryption must be enabled to read it")
+}
+
+func TestStorageReencryption(t *testing.T) {
+	dir, err := ioutil.TempDir("", "etcdraft-storage-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	walDir, snapDir := filepath.Join(dir, "wal"), filepath.Join(dir, "snap")
+	lg := flogging.NewFabricLogger(zap.NewNop())
+
+	csp, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewInMemoryKeyStore())
+	require.NoError(t, err)
+	newEncrypter := func() *encryption.Encrypter {
+		ski, err := encryption.GenerateKey(csp)
+		require.NoError(t, err)
+		encrypter, err := encryption.NewEncrypter(csp, encryption.NewKeyProvider(csp, ski))
+		require.NoError(t, err)
+		return encrypter
+	}
+	oldEncrypter := newEncrypter()
+
+	var entries []raftpb.Entry
+	for i := uint64(1); i <= 6; i++ {
+		entries = append(entries, raftpb.Entry{Term: 1, Index: i, Data: []byte(fmt.Sprintf("\x0ablock %d", i))})
+	}
+	storage, err := CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage(), oldEncrypter)
+	require.NoError(t, err)
+	require.NoError(t, storage.Store(entries[:2], raftpb.HardState{Term: 1, Commit: 2}, raftpb.Snapshot{}))
+	require.NoError(t, storage.TakeSnapshot(2, &raftpb.ConfState{Nodes: []uint64{1}}, []byte("\x0asnapshot 2")))
+	require.NoError(t, storage.Store(entries[2:4], raftpb.HardState{Term: 1, Commit: 4}, raftpb.Snapshot{}))
+	require.NoError(t, storage.TakeSnapshot(4, &raftpb.ConfState{Nodes: []uint64{1}}, []byte("\x0asnapshot 4")))
+	require.NoError(t, storage.Store(entries[4:], raftpb.HardState{Term: 1, Commit: 6}, raftpb.Snapshot{}))
+	require.NoError(t, storage.Close())
+
+	// the key is rotated, the previous key remains in the keystore
+	currentEncrypter := newEncrypter()
+	storage, err = CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage(), currentEncrypter)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("\x0asnapshot 4"), storage.Snapshot().Data)
+	stored, err := storage.ram.Entries(5, 7, 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, entries[4:], stored)
+	require.NoError(t, storage.Close())
+
+	// all the snapshots and the WAL are encrypted with the current key
+	snapFiles, err := ioutil.ReadDir(snapDir)
+	require.NoError(t, err)
+	require.Len(t, snapFiles, 2)
+	for _, file := range snapFiles {
+		snapshot, err := snap.Read(filepath.Join(snapDir, file.Name()))
+		require.NoError(t, err)
+		assert.True(t, currentEncrypter.IsCurrent(snapshot.Data), "snapshot %s should be re-encrypted", file.Name())
+	}
+	walFiles, err := ioutil.ReadDir(walDir)
+	require.NoError(t, err)
+	require.Len(t, walFiles, 1)
+	w, err := wal.OpenForRead(walDir, walpb.Snapshot{Term: 1, Index: 4})
+	require.NoError(t, err)
+	_, _, walEntries, err := w.ReadAll()
+	require.NoError(t, err)
+	w.Close()
+	require.Len(t, walEntries, 2)
+	for _, entry := range walEntries {
+		assert.True(t, currentEncrypter.IsCurrent(entry.Data))
+	}
+
+	// a WAL moved aside by an interrupted re-encryption is restored
+	require.NoError(t, os.Rename(walDir, walDir+retiredWALSuffix))
+	require.NoError(t, os.MkdirAll(walDir+reencryptedSuffix, os.ModePerm))
+	storage, err = CreateStorage(lg, walDir, snapDir, raft.NewMemoryStorage(), currentEncrypter)
+	require.NoError(t, err)
+	stored, err = storage.ram.Entries(5, 7, 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, entries[4:], stored)
+	require.NoError(t, storage.Close())
+	for _, leftover := range []string{walDir + retiredWALSuffix, walDir + reencryptedSuffix} {
+		_, err := os.Stat(leftover)
+		assert.True(t, os.IsNotExist(err))
+	}
+}
@@ -18,6 +18,7 @@ import (
 	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/consensus"
+	"github.com/hyperledger/fabric/orderer/consensus/migration"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -120,6 +121,12 @@ type chainImpl struct {
 	timer <-chan time.Time
 
 	replicaIDs []int32
+
+	// mutex used when changing the migrationCommitted
+	migrationMutex sync.Mutex
+	// set once a consensus-type migration to Raft is committed on the channel,
+	// after which the chain stops ordering until the orderer is restarted
+	migrationCommitted bool
 }
 
 // Errored returns a channel which will close when a partition consumer error
@@ -212,6 +219,9 @@ func (chain *chainImpl) Order(env *cb.Envelope, configSeq uint64) error {
 }
 
 func (chain *chainImpl) order(env *cb.Envelope, configSeq uint64, originalOffset int64) error {
+	if chain.isMigrationCommitted() {
+		return errors.Errorf("cannot enqueue, consensus-type migration to %s is committed", migration.ToType)
+	}
 	marshaledEnv, err := utils.Marshal(env)
 	if err != nil {
 		return fmt.Errorf("cannot enqueue, unable to marshal envelope because = %s", err)
@@ -228,6 +238,9 @@ func (chain *chainImpl) Configure(config *cb.Envelope, configSeq uint64) error {
 }
 
 func (chain *chainImpl) configure(config *cb.Envelope, configSeq uint64, originalOffset int64) error {
+	if chain.isMigrationCommitted() {
+		return errors.Errorf("cannot enqueue, consensus-type migration to %s is committed", migration.ToType)
+	}
 	marshaledConfig, err := utils.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("cannot enqueue, unable to marshal config because %s", err)
@@ -238,6 +251,30 @@ func (chain *chainImpl) configure(config *cb.Envelope, configSeq uint64, origina
 	return nil
 }
 
+func (chain *chainImpl) isMigrationCommitted() bool {
+	chain.migrationMutex.Lock()
+	defer chain.migrationMutex.Unlock()
+	return chain.migrationCommitted
+}
+
+// updateMigrationCommitted marks the migration as committed if the current config is the
+// commit of a consensus-type migration to Raft on the system channel. A standard channel
+// which prepared the migration keeps accepting config, as the migration may be aborted.
+func (chain *chainImpl) updateMigrationCommitted() {
+	oc := chain.SharedConfig()
+	if oc.ConsensusType() != migration.ToType || oc.ConsensusMigrationState() != ab.ConsensusType_MIG_STATE_COMMIT {
+		return
+	}
+
+	chain.migrationMutex.Lock()
+	defer chain.migrationMutex.Unlock()
+	if !chain.migrationCommitted {
+		logger.Warningf("[channel: %s] Consensus-type migration to %s is committed, restart the orderer to complete it",
+			chain.ChainID(), migration.ToType)
+	}
+	chain.migrationCommitted = true
+}
+
 // enqueue accepts a message and returns true on acceptance, or false otheriwse.
 func (chain *chainImpl) enqueue(kafkaMsg *ab.KafkaMessage) bool {
 	logger.Debugf("[channel: %s] Enqueueing envelope...", chain.ChainID())
@@ -288,6 +325,8 @@ func (chain *chainImpl) HealthCheck(ctx context.Context) error {
 func startThread(chain *chainImpl) {
 	var err error
 
+	chain.updateMigrationCommitted()
+
 	// Create topic if it does not exist (requires Kafka v0.10.1.0)
 	err = setupTopicForChannel(chain.consenter.retryOptions(), chain.haltChan, chain.SharedConfig().KafkaBrokers(), chain.consenter.brokerConfig(), chain.consenter.topicDetail(), chain.channel)
 	if err != nil {
@@ -713,6 +752,12 @@ func (chain *chainImpl) processRegular(regularMessage *ab.KafkaMessageRegular, r
 		chain.WriteConfigBlock(block, metadata)
 		chain.lastCutBlockNumber++
 		chain.timer = nil
+		chain.updateMigrationCommitted()
+	}
+
+	if chain.isMigrationCommitted() {
+		logger.Debugf("[channel: %s] Consensus-type migration is committed, discarding regular message", chain.ChainID())
+		return nil
 	}
 
 	seq := chain.Sequence()
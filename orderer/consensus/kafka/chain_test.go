@@ -18,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	lmock "github.com/hyperledger/fabric/orderer/consensus/kafka/mock"
+	"github.com/hyperledger/fabric/orderer/consensus/migration"
 	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/common/blockcutter"
 	mockmultichannel "github.com/hyperledger/fabric/orderer/mocks/common/multichannel"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -406,6 +407,28 @@ func TestChain(t *testing.T) {
 			// We don't need to create a legit envelope here as it's not inspected during this test
 			assert.NoError(t, chain.Order(&cb.Envelope{}, uint64(0)), "Expect Order successfully")
 		})
+
+		t.Run("ErrorIfMigrationCommitted", func(t *testing.T) {
+			_, mockBroker, mockSupport := newMocks(t)
+			defer func() { mockBroker.Close() }()
+			mockSupport.SharedConfigVal.ConsensusTypeVal = "etcdraft"
+			mockSupport.SharedConfigVal.ConsensusTypeMigrationStateVal = ab.ConsensusType_MIG_STATE_COMMIT
+			chain, _ := newChain(mockConsenter, mockSupport, newestOffset-1, lastOriginalOffsetProcessed, lastResubmittedConfigOffset)
+
+			chain.Start()
+			defer chain.Halt()
+
+			select {
+			case <-chain.startChan:
+				logger.Debug("startChan is closed as it should be")
+			case <-time.After(shortTimeout):
+				t.Fatal("startChan should have been closed by now")
+			}
+
+			// We don't need to create a legit envelope here as it's not inspected during this test
+			assert.EqualError(t, chain.Order(&cb.Envelope{}, uint64(0)), "cannot enqueue, consensus-type migration to etcdraft is committed")
+			assert.EqualError(t, chain.Configure(&cb.Envelope{}, uint64(0)), "cannot enqueue, consensus-type migration to etcdraft is committed")
+		})
 	})
 
 	t.Run("Configure", func(t *testing.T) {
@@ -3289,6 +3312,130 @@ func TestResubmission(t *testing.T) {
 	})
 }
 
+// registrarSupport validates the config messages of a channel against the consensus-type
+// migration status of the system channel reported by the registrar of its orderer.
+type registrarSupport struct {
+	*mockmultichannel.ConsenterSupport
+	sysStatus migration.Status
+}
+
+func (rs *registrarSupport) SystemChannelStatus() (migration.Status, error) {
+	return rs.sysStatus, nil
+}
+
+func (rs *registrarSupport) StandardChannelsStatus() map[string]migration.Status {
+	return nil
+}
+
+func (rs *registrarSupport) ProcessConfigMsg(env *cb.Envelope) (*cb.Envelope, uint64, error) {
+	next, err := migration.ConsensusTypeFromConfigEnvelope(env)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := migration.ValidateStep(false, rs.SharedConfig(), next, rs); err != nil {
+		return nil, 0, err
+	}
+	return rs.ConsenterSupport.ProcessConfigMsg(env)
+}
+
+// This ensures that the orderers consuming a consensus-type migration step from the same
+// partition write the same blocks, whatever the status of the other channels they have
+// processed so far.
+func TestMigrationStepDeterminism(t *testing.T) {
+	context, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, "foo", nil, &cb.ConfigEnvelope{
+		Config: &cb.Config{
+			ChannelGroup: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{
+					channelconfig.OrdererGroupKey: {
+						Values: map[string]*cb.ConfigValue{
+							channelconfig.ConsensusTypeKey: {Value: utils.MarshalOrPanic(&ab.ConsensusType{
+								Type:             migration.ToType,
+								MigrationState:   ab.ConsensusType_MIG_STATE_CONTEXT,
+								MigrationContext: 4,
+							})},
+						},
+					},
+				},
+			},
+		},
+	}, 0, 0)
+	require.NoError(t, err)
+
+	mockChannel := newChannel(channelNameForTest(t), defaultPartition)
+	mockBrokerConfigCopy := *mockBrokerConfig
+	mockBrokerConfigCopy.ChannelBufferSize = 0
+
+	// The first orderer has processed the start of the migration on the system channel, the
+	// second one has not yet
+	for _, sysStatus := range []migration.Status{
+		{State: ab.ConsensusType_MIG_STATE_START, Context: 4},
+		{State: ab.ConsensusType_MIG_STATE_NONE},
+	} {
+		mockParentConsumer := mocks.NewConsumer(t, &mockBrokerConfigCopy)
+		mpc := mockParentConsumer.ExpectConsumePartition(mockChannel.topic(), mockChannel.partition(), int64(0))
+		mockChannelConsumer, err := mockParentConsumer.ConsumePartition(mockChannel.topic(), mockChannel.partition(), int64(0))
+		require.NoError(t, err)
+
+		errorChan := make(chan struct{})
+		close(errorChan)
+		haltChan := make(chan struct{})
+
+		lastCutBlockNumber := uint64(3)
+		mockSupport := &registrarSupport{
+			ConsenterSupport: &mockmultichannel.ConsenterSupport{
+				Blocks:         make(chan *cb.Block), // WriteBlock will post here
+				BlockCutterVal: mockblockcutter.NewReceiver(),
+				ChainIDVal:     mockChannel.topic(),
+				HeightVal:      lastCutBlockNumber, // Incremented during the WriteBlock call
+				SharedConfigVal: &mockconfig.Orderer{
+					ConsensusTypeVal: migration.FromType,
+					BatchTimeoutVal:  longTimeout,
+					CapabilitiesVal: &mockconfig.OrdererCapabilities{
+						ResubmissionVal: true,
+					},
+				},
+				SequenceVal: uint64(1),
+			},
+			sysStatus: sysStatus,
+		}
+
+		bareMinimumChain := &chainImpl{
+			parentConsumer:  mockParentConsumer,
+			channelConsumer: mockChannelConsumer,
+
+			channel:            mockChannel,
+			ConsenterSupport:   mockSupport,
+			lastCutBlockNumber: lastCutBlockNumber,
+
+			errorChan:                      errorChan,
+			haltChan:                       haltChan,
+			doneProcessingMessagesToBlocks: make(chan struct{}),
+		}
+
+		var counts []uint64
+		done := make(chan struct{})
+		go func() {
+			counts, err = bareMinimumChain.processMessagesToBlocks()
+			done <- struct{}{}
+		}()
+
+		mpc.YieldMessage(newMockConsumerMessage(newConfigMessage(utils.MarshalOrPanic(context), uint64(1), int64(0))))
+
+		select {
+		case block := <-mockSupport.Blocks:
+			assert.Equal(t, [][]byte{utils.MarshalOrPanic(context)}, block.Data.Data)
+		case <-time.After(shortTimeout):
+			t.Fatalf("Expected the migration step to be written to a block with system channel status %s", sysStatus.State)
+		}
+
+		close(haltChan) // Identical to chain.Halt()
+		<-done
+
+		assert.NoError(t, err, "Expected the processMessagesToBlocks call to return without errors")
+		assert.Equal(t, uint64(1), counts[indexProcessRegularPass], "Expected 1 REGULAR message processed")
+	}
+}
+
 // Test helper functions here.
 
 func newRegularMessage(payload []byte) *ab.KafkaMessage {
@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package migration provides the building blocks of the in-place consensus-type migration
+// from Kafka to Raft, which is driven by the MigrationState and MigrationContext of the
+// ConsensusType in the orderer config.
+//
+// The migration proceeds as follows:
+//   - The system channel starts the migration (kafka, START), which puts it in maintenance.
+//   - Every standard channel prepares the migration (etcdraft, CONTEXT), with the context set
+//     to the number of the system channel block that started the migration.
+//   - The system channel commits the migration (etcdraft, COMMIT), with the same context.
+//   - The orderers are restarted, and each migrated chain is handed to the etcdraft consenter.
+//
+// The migration can be aborted on the system channel (kafka, ABORT) as long as it is not
+// committed, after which the standard channels revert to (kafka, NONE).
+package migration
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/flogging"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("orderer.consensus.migration")
+
+const (
+	// FromType is the consensus type that migration is supported from.
+	FromType = "kafka"
+	// ToType is the consensus type that migration is supported to.
+	ToType = "etcdraft"
+)
+
+// Status is the consensus-type migration state and context of a channel.
+type Status struct {
+	State   ab.ConsensusType_MigrationState
+	Context uint64
+}
+
+// Controller provides the consensus-type migration status of the channels, which is needed to
+// coordinate the migration of the system channel with the migration of the standard channels.
+// It is implemented by the Registrar.
+type Controller interface {
+	// SystemChannelStatus returns the migration status of the system channel. While the migration
+	// is started, the context is the number of the system channel block that started it.
+	SystemChannelStatus() (Status, error)
+
+	// StandardChannelsStatus returns the migration status of every standard channel, by channel ID.
+	StandardChannelsStatus() map[string]Status
+}
+
+// InProgress returns whether the migration state of a channel rejects the normal transactions,
+// that is whether the migration is started or committed on the system channel, or prepared on a
+// standard channel.
+func InProgress(state ab.ConsensusType_MigrationState) bool {
+	switch state {
+	case ab.ConsensusType_MIG_STATE_START, ab.ConsensusType_MIG_STATE_COMMIT, ab.ConsensusType_MIG_STATE_CONTEXT:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsMigrated returns whether the orderer config of a channel is the last config written by Kafka
+// in a migration to Raft, that is the commit of the migration on the system channel, or its
+// preparation on a standard channel.
+func IsMigrated(oc channelconfig.Orderer) bool {
+	if oc.ConsensusType() != ToType {
+		return false
+	}
+	state := oc.ConsensusMigrationState()
+	return state == ab.ConsensusType_MIG_STATE_COMMIT || state == ab.ConsensusType_MIG_STATE_CONTEXT
+}
+
+// IsStep returns whether the next consensus type changes the type or the migration state of the
+// current orderer config.
+func IsStep(current channelconfig.Orderer, next *ab.ConsensusType) bool {
+	return next != nil && (current.ConsensusType() != next.Type || current.ConsensusMigrationState() != next.MigrationState)
+}
+
+// ConsensusTypeFromConfigEnvelope returns the consensus type of the orderer config carried by an
+// envelope of type CONFIG, or nil if the envelope is of another type.
+func ConsensusTypeFromConfigEnvelope(env *cb.Envelope) (*ab.ConsensusType, error) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("missing payload header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		return nil, nil
+	}
+
+	configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	if configEnvelope.Config == nil || configEnvelope.Config.ChannelGroup == nil {
+		return nil, errors.New("config envelope does not contain a channel group")
+	}
+	ordererGroup, ok := configEnvelope.Config.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	if !ok {
+		return nil, nil
+	}
+	consensusTypeValue, ok := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+	if !ok {
+		return nil, nil
+	}
+
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(consensusTypeValue.Value, consensusType); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consensus type")
+	}
+	return consensusType, nil
+}
+
+// ValidatePermittedStep checks that the next consensus type is a migration step permitted on a
+// channel of its kind. The transitions of the type and state permitted on any channel are checked
+// by the channelconfig bundle; this enforces the ones permitted on the system channel and on the
+// standard channels. As it only depends on the config of the channel, every orderer reaches the
+// same verdict whenever the step is validated, including when a consenter re-validates it.
+func ValidatePermittedStep(isSystemChannel bool, current channelconfig.Orderer, next *ab.ConsensusType) error {
+	if !IsStep(current, next) {
+		return nil
+	}
+
+	switch {
+	case isSystemChannel && next.MigrationState == ab.ConsensusType_MIG_STATE_CONTEXT:
+		return errors.Errorf("consensus-type migration state %s is not permitted on the system channel", next.MigrationState)
+	case !isSystemChannel && (next.MigrationState == ab.ConsensusType_MIG_STATE_START ||
+		next.MigrationState == ab.ConsensusType_MIG_STATE_COMMIT || next.MigrationState == ab.ConsensusType_MIG_STATE_ABORT):
+		return errors.Errorf("consensus-type migration state %s is not permitted on a standard channel", next.MigrationState)
+	}
+	return nil
+}
+
+// ValidateStep checks that the next consensus type is a valid migration step of a channel, given
+// the migration status of the other channels. The status of the other channels is the one this
+// orderer has processed so far, which differs between the orderers, so this must only be checked
+// when a config update is broadcast, and never when a consenter re-validates the resulting config.
+func ValidateStep(isSystemChannel bool, current channelconfig.Orderer, next *ab.ConsensusType, controller Controller) error {
+	if err := ValidatePermittedStep(isSystemChannel, current, next); err != nil {
+		return err
+	}
+	if !IsStep(current, next) {
+		return nil
+	}
+
+	if isSystemChannel {
+		return validateSystemChannelStep(next, controller)
+	}
+	return validateStandardChannelStep(next, controller)
+}
+
+func validateSystemChannelStep(next *ab.ConsensusType, controller Controller) error {
+	switch next.MigrationState {
+	case ab.ConsensusType_MIG_STATE_COMMIT:
+		sysStatus, err := controller.SystemChannelStatus()
+		if err != nil {
+			return err
+		}
+		if sysStatus.State != ab.ConsensusType_MIG_STATE_START || sysStatus.Context != next.MigrationContext {
+			return errors.Errorf("cannot commit consensus-type migration with context %d, system channel status is %s/%d",
+				next.MigrationContext, sysStatus.State, sysStatus.Context)
+		}
+		for channelID, status := range controller.StandardChannelsStatus() {
+			if status.State != ab.ConsensusType_MIG_STATE_CONTEXT || status.Context != next.MigrationContext {
+				return errors.Errorf("cannot commit consensus-type migration with context %d, channel %s status is %s/%d",
+					next.MigrationContext, channelID, status.State, status.Context)
+			}
+		}
+		logger.Infof("Committing consensus-type migration to %s with context %d", next.Type, next.MigrationContext)
+	}
+
+	return nil
+}
+
+func validateStandardChannelStep(next *ab.ConsensusType, controller Controller) error {
+	sysStatus, err := controller.SystemChannelStatus()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case next.MigrationState == ab.ConsensusType_MIG_STATE_CONTEXT:
+		// The standard channel prepares the migration started on the system channel
+		if sysStatus.State != ab.ConsensusType_MIG_STATE_START || sysStatus.Context != next.MigrationContext {
+			return errors.Errorf("cannot prepare consensus-type migration with context %d, system channel status is %s/%d",
+				next.MigrationContext, sysStatus.State, sysStatus.Context)
+		}
+	case next.Type == FromType:
+		// The standard channel reverts a migration aborted on the system channel
+		if sysStatus.State != ab.ConsensusType_MIG_STATE_ABORT && sysStatus.State != ab.ConsensusType_MIG_STATE_NONE {
+			return errors.Errorf("cannot revert consensus-type migration, system channel status is %s/%d",
+				sysStatus.State, sysStatus.Context)
+		}
+	default:
+		// The standard channel completes a migration committed on the system channel
+		if sysStatus.State != ab.ConsensusType_MIG_STATE_COMMIT && sysStatus.State != ab.ConsensusType_MIG_STATE_NONE {
+			return errors.Errorf("cannot complete consensus-type migration, system channel status is %s/%d",
+				sysStatus.State, sysStatus.Context)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,224 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package migration
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/mocks/config"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockController struct {
+	sysStatus Status
+	sysErr    error
+	statuses  map[string]Status
+}
+
+func (mc *mockController) SystemChannelStatus() (Status, error) {
+	return mc.sysStatus, mc.sysErr
+}
+
+func (mc *mockController) StandardChannelsStatus() map[string]Status {
+	return mc.statuses
+}
+
+func ordererConfig(consensusType string, state ab.ConsensusType_MigrationState, context uint64) *config.Orderer {
+	return &config.Orderer{
+		ConsensusTypeVal:                 consensusType,
+		ConsensusTypeMigrationStateVal:   state,
+		ConsensusTypeMigrationContextVal: context,
+	}
+}
+
+func TestInProgress(t *testing.T) {
+	assert.False(t, InProgress(ab.ConsensusType_MIG_STATE_NONE))
+	assert.False(t, InProgress(ab.ConsensusType_MIG_STATE_ABORT))
+	assert.True(t, InProgress(ab.ConsensusType_MIG_STATE_START))
+	assert.True(t, InProgress(ab.ConsensusType_MIG_STATE_CONTEXT))
+	assert.True(t, InProgress(ab.ConsensusType_MIG_STATE_COMMIT))
+}
+
+func TestIsMigrated(t *testing.T) {
+	assert.False(t, IsMigrated(ordererConfig(FromType, ab.ConsensusType_MIG_STATE_START, 0)))
+	assert.False(t, IsMigrated(ordererConfig(ToType, ab.ConsensusType_MIG_STATE_NONE, 0)))
+	assert.True(t, IsMigrated(ordererConfig(ToType, ab.ConsensusType_MIG_STATE_CONTEXT, 4)))
+	assert.True(t, IsMigrated(ordererConfig(ToType, ab.ConsensusType_MIG_STATE_COMMIT, 4)))
+}
+
+func TestConsensusTypeFromConfigEnvelope(t *testing.T) {
+	consensusType := &ab.ConsensusType{Type: ToType, MigrationState: ab.ConsensusType_MIG_STATE_CONTEXT, MigrationContext: 4}
+	configEnv := &cb.ConfigEnvelope{
+		Config: &cb.Config{
+			ChannelGroup: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{
+					channelconfig.OrdererGroupKey: {
+						Values: map[string]*cb.ConfigValue{
+							channelconfig.ConsensusTypeKey: {Value: utils.MarshalOrPanic(consensusType)},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("Config", func(t *testing.T) {
+		env, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, "foo", nil, configEnv, 0, 0)
+		assert.NoError(t, err)
+		next, err := ConsensusTypeFromConfigEnvelope(env)
+		assert.NoError(t, err)
+		assert.True(t, proto.Equal(consensusType, next))
+	})
+
+	t.Run("NotConfig", func(t *testing.T) {
+		env, err := utils.CreateSignedEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, "foo", nil, &cb.ConfigEnvelope{}, 0, 0)
+		assert.NoError(t, err)
+		next, err := ConsensusTypeFromConfigEnvelope(env)
+		assert.NoError(t, err)
+		assert.Nil(t, next)
+	})
+
+	t.Run("NoOrdererGroup", func(t *testing.T) {
+		env, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG, "foo", nil,
+			&cb.ConfigEnvelope{Config: &cb.Config{ChannelGroup: &cb.ConfigGroup{}}}, 0, 0)
+		assert.NoError(t, err)
+		next, err := ConsensusTypeFromConfigEnvelope(env)
+		assert.NoError(t, err)
+		assert.Nil(t, next)
+	})
+
+	t.Run("BadPayload", func(t *testing.T) {
+		_, err := ConsensusTypeFromConfigEnvelope(&cb.Envelope{Payload: []byte("garbage")})
+		assert.Error(t, err)
+	})
+}
+
+func TestValidatePermittedStep(t *testing.T) {
+	current := ordererConfig(FromType, ab.ConsensusType_MIG_STATE_NONE, 0)
+	context := &ab.ConsensusType{Type: ToType, MigrationState: ab.ConsensusType_MIG_STATE_CONTEXT, MigrationContext: 4}
+	start := &ab.ConsensusType{Type: FromType, MigrationState: ab.ConsensusType_MIG_STATE_START}
+
+	assert.NoError(t, ValidatePermittedStep(true, current, start))
+	assert.NoError(t, ValidatePermittedStep(false, current, context))
+	assert.NoError(t, ValidatePermittedStep(true, current, nil))
+
+	err := ValidatePermittedStep(true, current, context)
+	assert.EqualError(t, err, "consensus-type migration state MIG_STATE_CONTEXT is not permitted on the system channel")
+	err = ValidatePermittedStep(false, current, start)
+	assert.EqualError(t, err, "consensus-type migration state MIG_STATE_START is not permitted on a standard channel")
+}
+
+func TestValidateStepSystemChannel(t *testing.T) {
+	started := ordererConfig(FromType, ab.ConsensusType_MIG_STATE_START, 0)
+	commit := &ab.ConsensusType{Type: ToType, MigrationState: ab.ConsensusType_MIG_STATE_COMMIT, MigrationContext: 4}
+
+	t.Run("NotAStep", func(t *testing.T) {
+		next := &ab.ConsensusType{Type: FromType, MigrationState: ab.ConsensusType_MIG_STATE_START}
+		assert.NoError(t, ValidateStep(true, started, next, nil))
+		assert.NoError(t, ValidateStep(true, started, nil, nil))
+	})
+
+	t.Run("Start", func(t *testing.T) {
+		next := &ab.ConsensusType{Type: FromType, MigrationState: ab.ConsensusType_MIG_STATE_START}
+		assert.NoError(t, ValidateStep(true, ordererConfig(FromType, ab.ConsensusType_MIG_STATE_NONE, 0), next, nil))
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		next := &ab.ConsensusType{Type: ToType, MigrationState: ab.ConsensusType_MIG_STATE_CONTEXT, MigrationContext: 4}
+		err := ValidateStep(true, started, next, &mockController{})
+		assert.EqualError(t, err, "consensus-type migration state MIG_STATE_CONTEXT is not permitted on the system channel")
+	})
+
+	t.Run("Commit", func(t *testing.T) {
+		controller := &mockController{
+			sysStatus: Status{State: ab.ConsensusType_MIG_STATE_START, Context: 4},
+			statuses: map[string]Status{
+				"foo": {State: ab.ConsensusType_MIG_STATE_CONTEXT, Context: 4},
+				"bar": {State: ab.ConsensusType_MIG_STATE_CONTEXT, Context: 4},
+			},
+		}
+		assert.NoError(t, ValidateStep(true, started, commit, controller))
+	})
+
+	t.Run("CommitWrongContext", func(t *testing.T) {
+		controller := &mockController{sysStatus: Status{State: ab.ConsensusType_MIG_STATE_START, Context: 3}}
+		err := ValidateStep(true, started, commit, controller)
+		assert.EqualError(t, err, "cannot commit consensus-type migration with context 4, system channel status is MIG_STATE_START/3")
+	})
+
+	t.Run("CommitChannelNotPrepared", func(t *testing.T) {
+		controller := &mockController{
+			sysStatus: Status{State: ab.ConsensusType_MIG_STATE_START, Context: 4},
+			statuses: map[string]Status{
+				"foo": {State: ab.ConsensusType_MIG_STATE_NONE},
+			},
+		}
+		err := ValidateStep(true, started, commit, controller)
+		assert.EqualError(t, err, "cannot commit consensus-type migration with context 4, channel foo status is MIG_STATE_NONE/0")
+	})
+
+	t.Run("CommitControllerError", func(t *testing.T) {
+		controller := &mockController{sysErr: errors.New("system channel does not exist")}
+		err := ValidateStep(true, started, commit, controller)
+		assert.EqualError(t, err, "system channel does not exist")
+	})
+}
+
+func TestValidateStepStandardChannel(t *testing.T) {
+	current := ordererConfig(FromType, ab.ConsensusType_MIG_STATE_NONE, 0)
+	prepared := ordererConfig(ToType, ab.ConsensusType_MIG_STATE_CONTEXT, 4)
+	context := &ab.ConsensusType{Type: ToType, MigrationState: ab.ConsensusType_MIG_STATE_CONTEXT, MigrationContext: 4}
+
+	t.Run("NotPermitted", func(t *testing.T) {
+		for _, state := range []ab.ConsensusType_MigrationState{
+			ab.ConsensusType_MIG_STATE_START,
+			ab.ConsensusType_MIG_STATE_COMMIT,
+			ab.ConsensusType_MIG_STATE_ABORT,
+		} {
+			next := &ab.ConsensusType{Type: ToType, MigrationState: state}
+			err := ValidateStep(false, current, next, &mockController{})
+			assert.EqualError(t, err, "consensus-type migration state "+state.String()+" is not permitted on a standard channel")
+		}
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		controller := &mockController{sysStatus: Status{State: ab.ConsensusType_MIG_STATE_START, Context: 4}}
+		assert.NoError(t, ValidateStep(false, current, context, controller))
+	})
+
+	t.Run("ContextNotStarted", func(t *testing.T) {
+		controller := &mockController{sysStatus: Status{State: ab.ConsensusType_MIG_STATE_NONE}}
+		err := ValidateStep(false, current, context, controller)
+		assert.EqualError(t, err, "cannot prepare consensus-type migration with context 4, system channel status is MIG_STATE_NONE/0")
+	})
+
+	t.Run("Revert", func(t *testing.T) {
+		next := &ab.ConsensusType{Type: FromType, MigrationState: ab.ConsensusType_MIG_STATE_NONE}
+		controller := &mockController{sysStatus: Status{State: ab.ConsensusType_MIG_STATE_ABORT}}
+		assert.NoError(t, ValidateStep(false, prepared, next, controller))
+
+		controller = &mockController{sysStatus: Status{State: ab.ConsensusType_MIG_STATE_START, Context: 4}}
+		err := ValidateStep(false, prepared, next, controller)
+		assert.EqualError(t, err, "cannot revert consensus-type migration, system channel status is MIG_STATE_START/4")
+	})
+
+	t.Run("Complete", func(t *testing.T) {
+		next := &ab.ConsensusType{Type: ToType, MigrationState: ab.ConsensusType_MIG_STATE_NONE}
+		controller := &mockController{sysStatus: Status{State: ab.ConsensusType_MIG_STATE_COMMIT, Context: 4}}
+		assert.NoError(t, ValidateStep(false, prepared, next, controller))
+
+		controller = &mockController{sysStatus: Status{State: ab.ConsensusType_MIG_STATE_START, Context: 4}}
+		err := ValidateStep(false, prepared, next, controller)
+		assert.EqualError(t, err, "cannot complete consensus-type migration, system channel status is MIG_STATE_START/4")
+	})
+}
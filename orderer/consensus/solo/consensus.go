@@ -147,8 +147,7 @@ func (ch *chain) main() {
 						continue
 					}
 				}
-				batch := ch.support.BlockCutter().Cut()
-				if batch != nil {
+				for batch := ch.support.BlockCutter().Cut(); len(batch) > 0; batch = ch.support.BlockCutter().Cut() {
 					block := ch.support.CreateNextBlock(batch)
 					ch.support.WriteBlock(block, nil)
 				}
@@ -167,8 +166,10 @@ func (ch *chain) main() {
 				continue
 			}
 			logger.Debugf("Batch timer expired, creating block")
-			block := ch.support.CreateNextBlock(batch)
-			ch.support.WriteBlock(block, nil)
+			for ; len(batch) > 0; batch = ch.support.BlockCutter().Cut() {
+				block := ch.support.CreateNextBlock(batch)
+				ch.support.WriteBlock(block, nil)
+			}
 		case <-ch.exitChan:
 			logger.Debugf("Exiting")
 			return
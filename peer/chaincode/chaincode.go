@@ -39,6 +39,12 @@ var platformRegistry = platforms.NewRegistry(
 	&node.Platform{},
 )
 
+// PlatformRegistry returns the registry of the chaincode platforms supported
+// by the chaincode commands
+func PlatformRegistry() *platforms.Registry {
+	return platformRegistry
+}
+
 func addFlags(cmd *cobra.Command) {
 	common.AddOrdererFlags(cmd)
 	flags := cmd.PersistentFlags()
@@ -132,7 +132,9 @@ func chaincodeInvokeOrQuery(cmd *cobra.Command, invoke bool, cf *ChaincodeCmdFac
 		if proposalResp == nil {
 			return errors.New("error during query: received nil proposal response")
 		}
-		if proposalResp.Endorsement == nil {
+		// queries are evaluated by the peers without being endorsed, so the
+		// response status tells whether they failed
+		if proposalResp.Response == nil || proposalResp.Response.Status >= shim.ERRORTHRESHOLD {
 			return errors.Errorf("endorsement failure during query. response: %v", proposalResp.Response)
 		}
 
@@ -161,10 +163,10 @@ type collectionConfigJson struct {
 	MemberOnlyRead bool   `json:"memberOnlyRead"`
 }
 
-// getCollectionConfig retrieves the collection configuration
+// GetCollectionConfigFromFile retrieves the collection configuration
 // from the supplied file; the supplied file must contain a
 // json-formatted array of collectionConfigJson elements
-func getCollectionConfigFromFile(ccFile string) ([]byte, error) {
+func GetCollectionConfigFromFile(ccFile string) ([]byte, error) {
 	fileBytes, err := ioutil.ReadFile(ccFile)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not read file '%s'", ccFile)
@@ -252,7 +254,7 @@ func checkChaincodeCmdParams(cmd *cobra.Command) error {
 
 		if collectionsConfigFile != common.UndefinedParamValue {
 			var err error
-			collectionConfigBytes, err = getCollectionConfigFromFile(collectionsConfigFile)
+			collectionConfigBytes, err = GetCollectionConfigFromFile(collectionsConfigFile)
 			if err != nil {
 				return errors.WithMessage(err, fmt.Sprintf("invalid collection configuration in file %s", collectionsConfigFile))
 			}
@@ -289,43 +291,56 @@ func checkChaincodeCmdParams(cmd *cobra.Command) error {
 	return nil
 }
 
-func validatePeerConnectionParameters(cmdName string) error {
-	if connectionProfile != common.UndefinedParamValue {
-		networkConfig, err := common.GetConfig(connectionProfile)
+// PeerConnectionParams identifies the peers a command sends its proposals to
+type PeerConnectionParams struct {
+	ChannelID         string
+	ConnectionProfile string
+	PeerAddresses     []string
+	TLSRootCertFiles  []string
+	// MultiplePeers is set for the commands which collect endorsements from
+	// more than one peer
+	MultiplePeers bool
+}
+
+// Validate resolves the peers from the connection profile, if one was
+// supplied, and checks that the peer addresses and TLS root cert files are
+// consistent with each other and with the command
+func (p *PeerConnectionParams) Validate(cmdName string) error {
+	if p.ConnectionProfile != common.UndefinedParamValue {
+		networkConfig, err := common.GetConfig(p.ConnectionProfile)
 		if err != nil {
 			return err
 		}
-		if len(networkConfig.Channels[channelID].Peers) != 0 {
-			peerAddresses = []string{}
-			tlsRootCertFiles = []string{}
-			for peer, peerChannelConfig := range networkConfig.Channels[channelID].Peers {
+		if len(networkConfig.Channels[p.ChannelID].Peers) != 0 {
+			p.PeerAddresses = []string{}
+			p.TLSRootCertFiles = []string{}
+			for peer, peerChannelConfig := range networkConfig.Channels[p.ChannelID].Peers {
 				if peerChannelConfig.EndorsingPeer {
 					peerConfig, ok := networkConfig.Peers[peer]
 					if !ok {
 						return errors.Errorf("peer '%s' is defined in the channel config but doesn't have associated peer config", peer)
 					}
-					peerAddresses = append(peerAddresses, peerConfig.URL)
-					tlsRootCertFiles = append(tlsRootCertFiles, peerConfig.TLSCACerts.Path)
+					p.PeerAddresses = append(p.PeerAddresses, peerConfig.URL)
+					p.TLSRootCertFiles = append(p.TLSRootCertFiles, peerConfig.TLSCACerts.Path)
 				}
 			}
 		}
 	}
 
-	// currently only support multiple peer addresses for invoke
-	if cmdName != "invoke" && len(peerAddresses) > 1 {
-		return errors.Errorf("'%s' command can only be executed against one peer. received %d", cmdName, len(peerAddresses))
+	if !p.MultiplePeers && len(p.PeerAddresses) > 1 {
+		return errors.Errorf("'%s' command can only be executed against one peer. received %d", cmdName, len(p.PeerAddresses))
 	}
 
-	if len(tlsRootCertFiles) > len(peerAddresses) {
-		logger.Warningf("received more TLS root cert files (%d) than peer addresses (%d)", len(tlsRootCertFiles), len(peerAddresses))
+	if len(p.TLSRootCertFiles) > len(p.PeerAddresses) {
+		logger.Warningf("received more TLS root cert files (%d) than peer addresses (%d)", len(p.TLSRootCertFiles), len(p.PeerAddresses))
 	}
 
 	if viper.GetBool("peer.tls.enabled") {
-		if len(tlsRootCertFiles) != len(peerAddresses) {
-			return errors.Errorf("number of peer addresses (%d) does not match the number of TLS root cert files (%d)", len(peerAddresses), len(tlsRootCertFiles))
+		if len(p.TLSRootCertFiles) != len(p.PeerAddresses) {
+			return errors.Errorf("number of peer addresses (%d) does not match the number of TLS root cert files (%d)", len(p.PeerAddresses), len(p.TLSRootCertFiles))
 		}
 	} else {
-		tlsRootCertFiles = nil
+		p.TLSRootCertFiles = nil
 	}
 
 	return nil
@@ -342,17 +357,35 @@ type ChaincodeCmdFactory struct {
 
 // InitCmdFactory init the ChaincodeCmdFactory with default clients
 func InitCmdFactory(cmdName string, isEndorserRequired, isOrdererRequired bool) (*ChaincodeCmdFactory, error) {
+	params := &PeerConnectionParams{
+		ChannelID:         channelID,
+		ConnectionProfile: connectionProfile,
+		PeerAddresses:     peerAddresses,
+		TLSRootCertFiles:  tlsRootCertFiles,
+		// currently only support multiple peer addresses for invoke
+		MultiplePeers: cmdName == "invoke",
+	}
+	cf, err := NewChaincodeCmdFactory(cmdName, params, isEndorserRequired, isOrdererRequired)
+	// the peers resolved from the connection profile are also the ones
+	// awaited for the transaction to be committed
+	peerAddresses, tlsRootCertFiles = params.PeerAddresses, params.TLSRootCertFiles
+	return cf, err
+}
+
+// NewChaincodeCmdFactory creates the clients of the peers identified by params
+// and, if required, of the orderer of their channel
+func NewChaincodeCmdFactory(cmdName string, params *PeerConnectionParams, isEndorserRequired, isOrdererRequired bool) (*ChaincodeCmdFactory, error) {
 	var err error
 	var endorserClients []pb.EndorserClient
 	var deliverClients []api.PeerDeliverClient
 	if isEndorserRequired {
-		if err = validatePeerConnectionParameters(cmdName); err != nil {
+		if err = params.Validate(cmdName); err != nil {
 			return nil, errors.WithMessage(err, "error validating peer connection parameters")
 		}
-		for i, address := range peerAddresses {
+		for i, address := range params.PeerAddresses {
 			var tlsRootCertFile string
-			if tlsRootCertFiles != nil {
-				tlsRootCertFile = tlsRootCertFiles[i]
+			if params.TLSRootCertFiles != nil {
+				tlsRootCertFile = params.TLSRootCertFiles[i]
 			}
 			endorserClient, err := common.GetEndorserClientFnc(address, tlsRootCertFile)
 			if err != nil {
@@ -387,14 +420,14 @@ func InitCmdFactory(cmdName string, isEndorserRequired, isOrdererRequired bool)
 			}
 			endorserClient := endorserClients[0]
 
-			orderingEndpoints, err := common.GetOrdererEndpointOfChainFnc(channelID, signer, endorserClient)
+			orderingEndpoints, err := common.GetOrdererEndpointOfChainFnc(params.ChannelID, signer, endorserClient)
 			if err != nil {
-				return nil, errors.WithMessage(err, fmt.Sprintf("error getting channel (%s) orderer endpoint", channelID))
+				return nil, errors.WithMessage(err, fmt.Sprintf("error getting channel (%s) orderer endpoint", params.ChannelID))
 			}
 			if len(orderingEndpoints) == 0 {
-				return nil, errors.Errorf("no orderer endpoints retrieved for channel %s", channelID)
+				return nil, errors.Errorf("no orderer endpoints retrieved for channel %s", params.ChannelID)
 			}
-			logger.Infof("Retrieved channel (%s) orderer endpoint: %s", channelID, orderingEndpoints[0])
+			logger.Infof("Retrieved channel (%s) orderer endpoint: %s", params.ChannelID, orderingEndpoints[0])
 			// override viper env
 			viper.Set("orderer.address", orderingEndpoints[0])
 		}
@@ -455,7 +488,13 @@ func ChaincodeInvokeOrQuery(
 		}
 	}
 
-	prop, txid, err := putils.CreateChaincodeProposalWithTxIDAndTransient(pcommon.HeaderType_ENDORSER_TRANSACTION, cID, invocation, creator, txID, tMap)
+	var prop *pb.Proposal
+	var txid string
+	if invoke {
+		prop, txid, err = putils.CreateChaincodeProposalWithTxIDAndTransient(pcommon.HeaderType_ENDORSER_TRANSACTION, cID, invocation, creator, txID, tMap)
+	} else {
+		prop, txid, err = putils.CreateChaincodeQueryProposalWithTxIDAndTransient(cID, invocation, creator, txID, tMap)
+	}
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("error creating proposal for %s", funcName))
 	}
@@ -12,7 +12,6 @@ import (
 	"crypto/tls"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"testing"
 	"time"
 
@@ -232,80 +231,103 @@ func TestValidatePeerConnectionParams(t *testing.T) {
 	viper.Set("peer.tls.enabled", false)
 
 	// failure - more than one peer and TLS root cert - not invoke
-	resetFlags()
-	peerAddresses = []string{"peer0", "peer1"}
-	tlsRootCertFiles = []string{"cert0", "cert1"}
-	err := validatePeerConnectionParameters("query")
+	params := &PeerConnectionParams{
+		ConnectionProfile: common.UndefinedParamValue,
+		PeerAddresses:     []string{"peer0", "peer1"},
+		TLSRootCertFiles:  []string{"cert0", "cert1"},
+	}
+	err := params.Validate("query")
 	assert.Error(err)
 	assert.Contains(err.Error(), "command can only be executed against one peer")
 
 	// success - peer provided and no TLS root certs
 	// TLS disabled
-	resetFlags()
-	peerAddresses = []string{"peer0"}
-	err = validatePeerConnectionParameters("query")
+	params = &PeerConnectionParams{
+		ConnectionProfile: common.UndefinedParamValue,
+		PeerAddresses:     []string{"peer0"},
+	}
+	err = params.Validate("query")
 	assert.NoError(err)
-	assert.Nil(tlsRootCertFiles)
+	assert.Nil(params.TLSRootCertFiles)
 
 	// success - more TLS root certs than peers
 	// TLS disabled
-	resetFlags()
-	peerAddresses = []string{"peer0"}
-	tlsRootCertFiles = []string{"cert0", "cert1"}
-	err = validatePeerConnectionParameters("invoke")
+	params = &PeerConnectionParams{
+		ConnectionProfile: common.UndefinedParamValue,
+		PeerAddresses:     []string{"peer0"},
+		TLSRootCertFiles:  []string{"cert0", "cert1"},
+		MultiplePeers:     true,
+	}
+	err = params.Validate("invoke")
 	assert.NoError(err)
-	assert.Nil(tlsRootCertFiles)
+	assert.Nil(params.TLSRootCertFiles)
 
 	// success - multiple peers and no TLS root certs - invoke
 	// TLS disabled
-	resetFlags()
-	peerAddresses = []string{"peer0", "peer1"}
-	err = validatePeerConnectionParameters("invoke")
+	params = &PeerConnectionParams{
+		ConnectionProfile: common.UndefinedParamValue,
+		PeerAddresses:     []string{"peer0", "peer1"},
+		MultiplePeers:     true,
+	}
+	err = params.Validate("invoke")
 	assert.NoError(err)
-	assert.Nil(tlsRootCertFiles)
+	assert.Nil(params.TLSRootCertFiles)
 
 	// TLS enabled
 	viper.Set("peer.tls.enabled", true)
 
 	// failure - uneven number of peers and TLS root certs - invoke
 	// TLS enabled
-	resetFlags()
-	peerAddresses = []string{"peer0", "peer1"}
-	tlsRootCertFiles = []string{"cert0"}
-	err = validatePeerConnectionParameters("invoke")
+	params = &PeerConnectionParams{
+		ConnectionProfile: common.UndefinedParamValue,
+		PeerAddresses:     []string{"peer0", "peer1"},
+		TLSRootCertFiles:  []string{"cert0"},
+		MultiplePeers:     true,
+	}
+	err = params.Validate("invoke")
 	assert.Error(err)
-	assert.Contains(err.Error(), fmt.Sprintf("number of peer addresses (%d) does not match the number of TLS root cert files (%d)", len(peerAddresses), len(tlsRootCertFiles)))
+	assert.Contains(err.Error(), "number of peer addresses (2) does not match the number of TLS root cert files (1)")
 
 	// success - more than one peer and TLS root certs - invoke
 	// TLS enabled
-	resetFlags()
-	peerAddresses = []string{"peer0", "peer1"}
-	tlsRootCertFiles = []string{"cert0", "cert1"}
-	err = validatePeerConnectionParameters("invoke")
+	params = &PeerConnectionParams{
+		ConnectionProfile: common.UndefinedParamValue,
+		PeerAddresses:     []string{"peer0", "peer1"},
+		TLSRootCertFiles:  []string{"cert0", "cert1"},
+		MultiplePeers:     true,
+	}
+	err = params.Validate("invoke")
 	assert.NoError(err)
 
 	// failure - connection profile doesn't exist
-	resetFlags()
-	connectionProfile = "blah"
-	err = validatePeerConnectionParameters("invoke")
+	params = &PeerConnectionParams{
+		ConnectionProfile: "blah",
+		MultiplePeers:     true,
+	}
+	err = params.Validate("invoke")
 	assert.Error(err)
 	assert.Contains(err.Error(), "error reading connection profile")
 
 	// failure - connection profile has peer defined in channel config but
 	// not in peer config
-	resetFlags()
-	channelID = "mychannel"
-	connectionProfile = "../common/testdata/connectionprofile-uneven.yaml"
-	err = validatePeerConnectionParameters("invoke")
+	params = &PeerConnectionParams{
+		ChannelID:         "mychannel",
+		ConnectionProfile: "../common/testdata/connectionprofile-uneven.yaml",
+		MultiplePeers:     true,
+	}
+	err = params.Validate("invoke")
 	assert.Error(err)
 	assert.Contains(err.Error(), "defined in the channel config but doesn't have associated peer config")
 
 	// success - connection profile exists
-	resetFlags()
-	channelID = "mychannel"
-	connectionProfile = "../common/testdata/connectionprofile.yaml"
-	err = validatePeerConnectionParameters("invoke")
+	params = &PeerConnectionParams{
+		ChannelID:         "mychannel",
+		ConnectionProfile: "../common/testdata/connectionprofile.yaml",
+		MultiplePeers:     true,
+	}
+	err = params.Validate("invoke")
 	assert.NoError(err)
+	assert.Len(params.PeerAddresses, 2)
 }
 
 func TestInitCmdFactoryFailures(t *testing.T) {
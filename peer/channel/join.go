@@ -91,6 +91,15 @@ func executeJoin(cf *ChannelCmdFactory) (err error) {
 		return err
 	}
 
+	if err := submitJoinProposal(cf, spec); err != nil {
+		return err
+	}
+	logger.Info("Successfully submitted proposal to join channel")
+	return nil
+}
+
+// submitJoinProposal sends the proposal invoking cscc with the given spec to the peer
+func submitJoinProposal(cf *ChannelCmdFactory, spec *pb.ChaincodeSpec) (err error) {
 	// Build the ChaincodeInvocationSpec message
 	invocation := &pb.ChaincodeInvocationSpec{ChaincodeSpec: spec}
 
@@ -124,7 +133,6 @@ func executeJoin(cf *ChannelCmdFactory) (err error) {
 	if proposalResp.Response.Status != 0 && proposalResp.Response.Status != 200 {
 		return ProposalFailedErr(fmt.Sprintf("bad proposal response %d: %s", proposalResp.Response.Status, proposalResp.Response.Message))
 	}
-	logger.Info("Successfully submitted proposal to join channel")
 	return nil
 }
 
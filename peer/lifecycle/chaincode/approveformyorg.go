@@ -0,0 +1,73 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	approveForMyOrgCmdName  = "approveformyorg"
+	approveForMyOrgDesc     = "Approve the chaincode definition for my org."
+	approveForMyOrgFuncName = "ApproveChaincodeDefinitionForMyOrg"
+)
+
+// approveForMyOrgCmd returns the cobra command for approving a chaincode
+// definition on behalf of the org of the peer
+func approveForMyOrgCmd(cf *CmdFactory) *cobra.Command {
+	chaincodeApproveForMyOrgCmd := &cobra.Command{
+		Use:   approveForMyOrgCmdName,
+		Short: approveForMyOrgDesc,
+		Long:  approveForMyOrgDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return approveForMyOrg(cmd, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"name",
+		"version",
+		"sequence",
+		"signature-policy",
+		"escc",
+		"vscc",
+		"collections-config",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+	}
+	attachFlags(chaincodeApproveForMyOrgCmd, flagList)
+
+	return chaincodeApproveForMyOrgCmd
+}
+
+// approveForMyOrg endorses the approval of the chaincode definition and
+// sends the transaction to the orderer
+func approveForMyOrg(cmd *cobra.Command, cf *CmdFactory) error {
+	cd, err := getChaincodeDefinition()
+	if err != nil {
+		return err
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), true, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = invoke(approveForMyOrgFuncName, cd.approveArgs(), cf)
+	if err != nil {
+		return errors.WithMessage(err, "error approving chaincode definition")
+	}
+
+	return nil
+}
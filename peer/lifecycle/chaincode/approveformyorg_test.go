@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApproveForMyOrg(t *testing.T) {
+	cf := getMockCmdFactory(t, nil, nil, nil)
+	cmd := newTestCmd(approveForMyOrgCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1",
+		"--signature-policy", "OR('Org1MSP.member','Org2MSP.member')")
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	cmd = newTestCmd(approveForMyOrgCmd, cf, "-n", "mycc", "-v", "1.0", "--sequence", "1")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "The required parameter 'channelID' is empty. Rerun the command with -C flag")
+
+	cmd = newTestCmd(approveForMyOrgCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "must supply a positive value for the sequence parameter")
+
+	cmd = newTestCmd(approveForMyOrgCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1",
+		"--signature-policy", "notapolicy")
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid signature policy: notapolicy")
+
+	cmd = newTestCmd(approveForMyOrgCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1",
+		"--collections-config", "/does/not/exist")
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid collection configuration in file /does/not/exist")
+
+	cf = getMockCmdFactory(t, nil, nil, errors.New("broadcast-error"))
+	cmd = newTestCmd(approveForMyOrgCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "error approving chaincode definition: error sending transaction for ApproveChaincodeDefinitionForMyOrg: broadcast-error")
+}
+
+func TestApproveForMyOrgMultiplePeers(t *testing.T) {
+	cmd := newTestCmd(approveForMyOrgCmd, nil, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1",
+		"--peerAddresses", "peer0", "--peerAddresses", "peer1")
+	err := cmd.Execute()
+	assert.EqualError(t, err, "error validating peer connection parameters: 'approveformyorg' command can only be executed against one peer. received 2")
+}
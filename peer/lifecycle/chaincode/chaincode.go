@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	lifecycleName = "+lifecycle"
+	chainFuncName = "chaincode"
+	chainCmdDes   = "Perform chaincode operations: package|install|queryinstalled|approveformyorg|checkcommitreadiness|commit|querycommitted"
+)
+
+var logger = flogging.MustGetLogger("cli.lifecycle.chaincode")
+
+func addFlags(cmd *cobra.Command) {
+	common.AddOrdererFlags(cmd)
+}
+
+// Cmd returns the cobra command for Chaincode
+func Cmd(cf *CmdFactory) *cobra.Command {
+	addFlags(chaincodeCmd)
+
+	chaincodeCmd.AddCommand(packageCmd(cf))
+	chaincodeCmd.AddCommand(installCmd(cf))
+	chaincodeCmd.AddCommand(queryInstalledCmd(cf))
+	chaincodeCmd.AddCommand(approveForMyOrgCmd(cf))
+	chaincodeCmd.AddCommand(checkCommitReadinessCmd(cf))
+	chaincodeCmd.AddCommand(commitCmd(cf))
+	chaincodeCmd.AddCommand(queryCommittedCmd(cf))
+
+	return chaincodeCmd
+}
+
+// Chaincode-related variables.
+var (
+	chaincodeLang         string
+	chaincodePath         string
+	chaincodeName         string
+	chaincodeVersion      string
+	sequence              int64
+	channelID             string
+	signaturePolicy       string
+	escc                  string
+	vscc                  string
+	collectionsConfigFile string
+	peerAddresses         []string
+	tlsRootCertFiles      []string
+	connectionProfile     string
+)
+
+var chaincodeCmd = &cobra.Command{
+	Use:   chainFuncName,
+	Short: fmt.Sprint(chainCmdDes),
+	Long:  fmt.Sprint(chainCmdDes),
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		common.InitCmd(cmd, args)
+		common.SetOrdererEnv(cmd, args)
+	},
+}
+
+var flags *pflag.FlagSet
+
+func init() {
+	resetFlags()
+}
+
+// Explicitly define a method to facilitate tests
+func resetFlags() {
+	flags = &pflag.FlagSet{}
+
+	flags.StringVarP(&chaincodeLang, "lang", "l", "golang",
+		fmt.Sprintf("Language the %s is written in", chainFuncName))
+	flags.StringVarP(&chaincodePath, "path", "p", "",
+		fmt.Sprintf("Path to the %s", chainFuncName))
+	flags.StringVarP(&chaincodeName, "name", "n", "",
+		fmt.Sprint("Name of the chaincode"))
+	flags.StringVarP(&chaincodeVersion, "version", "v", "",
+		fmt.Sprint("Version of the chaincode"))
+	flags.Int64VarP(&sequence, "sequence", "", 0,
+		fmt.Sprint("The sequence number of the chaincode definition for the channel"))
+	flags.StringVarP(&channelID, "channelID", "C", "",
+		fmt.Sprint("The channel on which this command should be executed"))
+	flags.StringVarP(&signaturePolicy, "signature-policy", "", "",
+		fmt.Sprint("The endorsement policy associated to this chaincode specified as a signature policy"))
+	flags.StringVarP(&escc, "escc", "E", "",
+		fmt.Sprint("The name of the endorsement plugin to be used for this chaincode"))
+	flags.StringVarP(&vscc, "vscc", "V", "",
+		fmt.Sprint("The name of the validation plugin to be used for this chaincode"))
+	flags.StringVar(&collectionsConfigFile, "collections-config", "",
+		fmt.Sprint("The fully qualified path to the collection JSON file including the file name"))
+	flags.StringArrayVarP(&peerAddresses, "peerAddresses", "", []string{common.UndefinedParamValue},
+		fmt.Sprint("The addresses of the peers to connect to"))
+	flags.StringArrayVarP(&tlsRootCertFiles, "tlsRootCertFiles", "", []string{common.UndefinedParamValue},
+		fmt.Sprint("If TLS is enabled, the paths to the TLS root cert files of the peers to connect to. The order and number of certs specified should match the --peerAddresses flag"))
+	flags.StringVarP(&connectionProfile, "connectionProfile", "", common.UndefinedParamValue,
+		fmt.Sprint("Connection profile that provides the necessary connection information for the network. Note: currently only supported for providing peer connection information"))
+}
+
+func attachFlags(cmd *cobra.Command, names []string) {
+	cmdFlags := cmd.Flags()
+	for _, name := range names {
+		if flag := flags.Lookup(name); flag != nil {
+			cmdFlags.AddFlag(flag)
+		} else {
+			logger.Fatalf("Could not find flag '%s' to attach to command '%s'", name, cmd.Name())
+		}
+	}
+}
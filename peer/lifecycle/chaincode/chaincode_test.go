@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/spf13/cobra"
+)
+
+func TestMain(m *testing.M) {
+	err := msptesttools.LoadMSPSetupForTesting()
+	if err != nil {
+		panic(fmt.Sprintf("Fatal error when reading MSP config: %s", err))
+	}
+
+	os.Exit(m.Run())
+}
+
+// getMockCmdFactory returns a CmdFactory whose endorsers respond with the
+// given payload and whose broadcast client returns the given error
+func getMockCmdFactory(t *testing.T, payload proto.Message, endorserErr, broadcastErr error) *CmdFactory {
+	signer, err := common.GetDefaultSigner()
+	if err != nil {
+		t.Fatalf("Get default signer error: %v", err)
+	}
+
+	var payloadBytes []byte
+	if payload != nil {
+		payloadBytes, err = proto.Marshal(payload)
+		if err != nil {
+			t.Fatalf("Marshal payload error: %v", err)
+		}
+	}
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200, Payload: payloadBytes},
+		Endorsement: &pb.Endorsement{},
+	}
+
+	return &CmdFactory{
+		EndorserClients: []pb.EndorserClient{common.GetMockEndorserClient(mockResponse, endorserErr)},
+		Signer:          signer,
+		BroadcastClient: common.GetMockBroadcastClient(broadcastErr),
+	}
+}
+
+// newTestCmd resets the flags and returns the command created by cmdFn
+// with its args set
+func newTestCmd(cmdFn func(*CmdFactory) *cobra.Command, cf *CmdFactory, args ...string) *cobra.Command {
+	resetFlags()
+	cmd := cmdFn(cf)
+	addFlags(cmd)
+	cmd.SetArgs(args)
+	return cmd
+}
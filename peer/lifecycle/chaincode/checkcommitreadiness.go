@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	checkCommitReadinessCmdName  = "checkcommitreadiness"
+	checkCommitReadinessDesc     = "Check which orgs have approved the chaincode definition."
+	checkCommitReadinessFuncName = "QueryApprovalStatus"
+)
+
+// checkCommitReadinessCmd returns the cobra command for checking the
+// approval status of a chaincode definition
+func checkCommitReadinessCmd(cf *CmdFactory) *cobra.Command {
+	chaincodeCheckCommitReadinessCmd := &cobra.Command{
+		Use:   checkCommitReadinessCmdName,
+		Short: checkCommitReadinessDesc,
+		Long:  checkCommitReadinessDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkCommitReadiness(cmd, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"name",
+		"version",
+		"sequence",
+		"signature-policy",
+		"escc",
+		"vscc",
+		"collections-config",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+	}
+	attachFlags(chaincodeCheckCommitReadinessCmd, flagList)
+
+	return chaincodeCheckCommitReadinessCmd
+}
+
+// checkCommitReadiness prints whether each org of the channel has approved
+// the chaincode definition
+func checkCommitReadiness(cmd *cobra.Command, cf *CmdFactory) error {
+	cd, err := getChaincodeDefinition()
+	if err != nil {
+		return err
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), true, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	payload, err := query(channelID, checkCommitReadinessFuncName, cd.queryApprovalStatusArgs(), cf)
+	if err != nil {
+		return errors.WithMessage(err, "error checking commit readiness")
+	}
+
+	result := &lb.QueryApprovalStatusResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return errors.Wrap(err, "failed to unmarshal query approval status result")
+	}
+
+	orgs := make([]string, 0, len(result.Approved))
+	for org := range result.Approved {
+		orgs = append(orgs, org)
+	}
+	sort.Strings(orgs)
+
+	fmt.Printf("Chaincode definition for chaincode '%s', version '%s', sequence '%d' on channel '%s' approval status by org:\n", cd.name, cd.version, cd.sequence, channelID)
+	for _, org := range orgs {
+		fmt.Printf("%s: %t\n", org, result.Approved[org])
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckCommitReadiness(t *testing.T) {
+	cf := getMockCmdFactory(t, &lb.QueryApprovalStatusResult{Approved: map[string]bool{"Org1MSP": true, "Org2MSP": false}}, nil, nil)
+	cmd := newTestCmd(checkCommitReadinessCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1",
+		"--signature-policy", "OR('Org1MSP.member','Org2MSP.member')")
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	cmd = newTestCmd(checkCommitReadinessCmd, cf, "-n", "mycc", "-v", "1.0", "--sequence", "1")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "The required parameter 'channelID' is empty. Rerun the command with -C flag")
+
+	cmd = newTestCmd(checkCommitReadinessCmd, cf, "-C", "mychannel", "-v", "1.0", "--sequence", "1")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "must supply value for chaincode name and version parameters")
+
+	cmd = newTestCmd(checkCommitReadinessCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "must supply a positive value for the sequence parameter")
+
+	cmd = newTestCmd(checkCommitReadinessCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1",
+		"--signature-policy", "notapolicy")
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid signature policy: notapolicy")
+
+	cf = getMockCmdFactory(t, nil, errors.New("endorser-error"), nil)
+	cmd = newTestCmd(checkCommitReadinessCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "error checking commit readiness: error endorsing proposal: endorser-error")
+
+	failedResponse := &pb.ProposalResponse{
+		Response: &pb.Response{Status: 500, Message: "unknown chaincode"},
+	}
+	cf.EndorserClients[0] = common.GetMockEndorserClient(failedResponse, nil)
+	cmd = newTestCmd(checkCommitReadinessCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "error checking commit readiness: proposal failed with status: 500 - unknown chaincode")
+}
+
+func TestCheckCommitReadinessBadPayload(t *testing.T) {
+	cf := getMockCmdFactory(t, nil, nil, nil)
+	cf.EndorserClients[0] = common.GetMockEndorserClient(&pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200, Payload: []byte("garbage")},
+		Endorsement: &pb.Endorsement{},
+	}, nil)
+	cmd := newTestCmd(checkCommitReadinessCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1")
+	err := cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to unmarshal query approval status result")
+}
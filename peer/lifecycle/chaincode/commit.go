@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	commitCmdName  = "commit"
+	commitDesc     = "Commit the chaincode definition on the channel."
+	commitFuncName = "CommitChaincodeDefinition"
+)
+
+// commitCmd returns the cobra command for committing a chaincode definition
+func commitCmd(cf *CmdFactory) *cobra.Command {
+	chaincodeCommitCmd := &cobra.Command{
+		Use:   commitCmdName,
+		Short: commitDesc,
+		Long:  commitDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commit(cmd, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"name",
+		"version",
+		"sequence",
+		"signature-policy",
+		"escc",
+		"vscc",
+		"collections-config",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+	}
+	attachFlags(chaincodeCommitCmd, flagList)
+
+	return chaincodeCommitCmd
+}
+
+// commit collects the endorsements of the commit of the chaincode
+// definition from the peers and sends the transaction to the orderer
+func commit(cmd *cobra.Command, cf *CmdFactory) error {
+	cd, err := getChaincodeDefinition()
+	if err != nil {
+		return err
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), true, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = invoke(commitFuncName, cd.commitArgs(), cf)
+	if err != nil {
+		return errors.WithMessage(err, "error committing chaincode definition")
+	}
+
+	return nil
+}
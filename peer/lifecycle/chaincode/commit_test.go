@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommit(t *testing.T) {
+	cf := getMockCmdFactory(t, nil, nil, nil)
+	cf.EndorserClients = append(cf.EndorserClients, cf.EndorserClients[0])
+	cmd := newTestCmd(commitCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1")
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	cmd = newTestCmd(commitCmd, cf, "-C", "mychannel", "-v", "1.0", "--sequence", "1")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "must supply value for chaincode name and version parameters")
+
+	failedResponse := &pb.ProposalResponse{
+		Response: &pb.Response{Status: 500, Message: "not approved"},
+	}
+	cf.EndorserClients[1] = common.GetMockEndorserClient(failedResponse, nil)
+	cmd = newTestCmd(commitCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "error committing chaincode definition: proposal failed with status: 500 - not approved")
+
+	cf = getMockCmdFactory(t, nil, nil, errors.New("broadcast-error"))
+	cmd = newTestCmd(commitCmd, cf, "-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "error committing chaincode definition: error sending transaction for CommitChaincodeDefinition: broadcast-error")
+}
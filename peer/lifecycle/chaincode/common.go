@@ -0,0 +1,257 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/msp"
+	ccpeer "github.com/hyperledger/fabric/peer/chaincode"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// CmdFactory holds the clients used by the lifecycle chaincode commands
+type CmdFactory struct {
+	EndorserClients []pb.EndorserClient
+	Signer          msp.SigningIdentity
+	BroadcastClient common.BroadcastClient
+}
+
+// InitCmdFactory init the CmdFactory with default clients
+func InitCmdFactory(cmdName string, isEndorserRequired, isOrdererRequired bool) (*CmdFactory, error) {
+	cf, err := ccpeer.NewChaincodeCmdFactory(cmdName, &ccpeer.PeerConnectionParams{
+		ChannelID:         channelID,
+		ConnectionProfile: connectionProfile,
+		PeerAddresses:     peerAddresses,
+		TLSRootCertFiles:  tlsRootCertFiles,
+		// only commit collects endorsements from the peers of several orgs
+		MultiplePeers: cmdName == commitCmdName,
+	}, isEndorserRequired, isOrdererRequired)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CmdFactory{
+		EndorserClients: cf.EndorserClients,
+		Signer:          cf.Signer,
+		BroadcastClient: cf.BroadcastClient,
+	}, nil
+}
+
+// createProposal creates a signed proposal invoking the given function of the
+// lifecycle system chaincode with the marshaled args as its single argument.
+func createProposal(cID, funcName string, args proto.Message, signer msp.SigningIdentity) (*pb.Proposal, *pb.SignedProposal, error) {
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error marshaling args")
+	}
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: lifecycleName},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(funcName), argsBytes}},
+		},
+	}
+
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, fmt.Sprintf("error serializing identity for %s", signer.GetIdentifier()))
+	}
+
+	prop, _, err := utils.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, cID, cis, creator)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, fmt.Sprintf("error creating proposal for %s", funcName))
+	}
+
+	signedProp, err := utils.GetSignedProposal(prop, signer)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, fmt.Sprintf("error creating signed proposal for %s", funcName))
+	}
+
+	return prop, signedProp, nil
+}
+
+// processProposal sends the signed proposal to each of the endorsers and
+// returns their responses, failing if any endorser did not succeed.
+func processProposal(signedProp *pb.SignedProposal, endorserClients []pb.EndorserClient) ([]*pb.ProposalResponse, error) {
+	var responses []*pb.ProposalResponse
+	for _, endorser := range endorserClients {
+		proposalResponse, err := endorser.ProcessProposal(context.Background(), signedProp)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error endorsing proposal")
+		}
+
+		if proposalResponse == nil {
+			return nil, errors.New("received nil proposal response")
+		}
+
+		if proposalResponse.Response == nil {
+			return nil, errors.New("received proposal response with nil response")
+		}
+
+		if proposalResponse.Response.Status != int32(cb.Status_SUCCESS) {
+			return nil, errors.Errorf("proposal failed with status: %d - %s", proposalResponse.Response.Status, proposalResponse.Response.Message)
+		}
+
+		responses = append(responses, proposalResponse)
+	}
+
+	if len(responses) == 0 {
+		// this should only happen if some new code has introduced a bug
+		return nil, errors.New("no proposal responses received - this might indicate a bug")
+	}
+
+	return responses, nil
+}
+
+// invoke endorses the proposal and, if it was endorsed successfully, sends
+// the resulting transaction to the orderer.
+func invoke(funcName string, args proto.Message, cf *CmdFactory) error {
+	prop, signedProp, err := createProposal(channelID, funcName, args, cf.Signer)
+	if err != nil {
+		return err
+	}
+
+	responses, err := processProposal(signedProp, cf.EndorserClients)
+	if err != nil {
+		return err
+	}
+
+	env, err := utils.CreateSignedTx(prop, cf.Signer, responses...)
+	if err != nil {
+		return errors.WithMessage(err, "could not assemble transaction")
+	}
+
+	if err := cf.BroadcastClient.Send(env); err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error sending transaction for %s", funcName))
+	}
+
+	return nil
+}
+
+// query endorses the proposal on the first endorser and returns the
+// payload of its response.
+func query(cID, funcName string, args proto.Message, cf *CmdFactory) ([]byte, error) {
+	_, signedProp, err := createProposal(cID, funcName, args, cf.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := processProposal(signedProp, cf.EndorserClients[:1])
+	if err != nil {
+		return nil, err
+	}
+
+	return responses[0].Response.Payload, nil
+}
+
+// chaincodeDefinition holds the definition related flags in the form
+// expected by the lifecycle system chaincode.
+type chaincodeDefinition struct {
+	sequence          int64
+	name              string
+	version           string
+	endorsementPlugin string
+	validationPlugin  string
+	endorsementPolicy []byte
+	collections       *cb.CollectionConfigPackage
+}
+
+// getChaincodeDefinition validates the definition related flags and
+// converts them to a chaincodeDefinition.
+func getChaincodeDefinition() (*chaincodeDefinition, error) {
+	if channelID == "" {
+		return nil, errors.New("The required parameter 'channelID' is empty. Rerun the command with -C flag")
+	}
+
+	if chaincodeName == "" || chaincodeVersion == "" {
+		return nil, errors.Errorf("must supply value for %s name and version parameters", chainFuncName)
+	}
+
+	if sequence <= 0 {
+		return nil, errors.New("must supply a positive value for the sequence parameter")
+	}
+
+	var policyBytes []byte
+	if signaturePolicy != "" {
+		policy, err := cauthdsl.FromString(signaturePolicy)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("invalid signature policy: %s", signaturePolicy))
+		}
+		policyBytes, err = proto.Marshal(policy)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not marshal signature policy")
+		}
+	}
+
+	var collections *cb.CollectionConfigPackage
+	if collectionsConfigFile != "" {
+		collectionsBytes, err := ccpeer.GetCollectionConfigFromFile(collectionsConfigFile)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("invalid collection configuration in file %s", collectionsConfigFile))
+		}
+		collections = &cb.CollectionConfigPackage{}
+		err = proto.Unmarshal(collectionsBytes, collections)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal collection configuration")
+		}
+	}
+
+	return &chaincodeDefinition{
+		sequence:          sequence,
+		name:              chaincodeName,
+		version:           chaincodeVersion,
+		endorsementPlugin: escc,
+		validationPlugin:  vscc,
+		endorsementPolicy: policyBytes,
+		collections:       collections,
+	}, nil
+}
+
+func (cd *chaincodeDefinition) approveArgs() *lb.ApproveChaincodeDefinitionForMyOrgArgs {
+	return &lb.ApproveChaincodeDefinitionForMyOrgArgs{
+		Sequence:          cd.sequence,
+		Name:              cd.name,
+		Version:           cd.version,
+		EndorsementPlugin: cd.endorsementPlugin,
+		ValidationPlugin:  cd.validationPlugin,
+		EndorsementPolicy: cd.endorsementPolicy,
+		Collections:       cd.collections,
+	}
+}
+
+func (cd *chaincodeDefinition) commitArgs() *lb.CommitChaincodeDefinitionArgs {
+	return &lb.CommitChaincodeDefinitionArgs{
+		Sequence:          cd.sequence,
+		Name:              cd.name,
+		Version:           cd.version,
+		EndorsementPlugin: cd.endorsementPlugin,
+		ValidationPlugin:  cd.validationPlugin,
+		EndorsementPolicy: cd.endorsementPolicy,
+		Collections:       cd.collections,
+	}
+}
+
+func (cd *chaincodeDefinition) queryApprovalStatusArgs() *lb.QueryApprovalStatusArgs {
+	return &lb.QueryApprovalStatusArgs{
+		Sequence:          cd.sequence,
+		Name:              cd.name,
+		Version:           cd.version,
+		EndorsementPlugin: cd.endorsementPlugin,
+		ValidationPlugin:  cd.validationPlugin,
+		EndorsementPolicy: cd.endorsementPolicy,
+		Collections:       cd.collections,
+	}
+}
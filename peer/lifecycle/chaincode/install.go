@@ -0,0 +1,92 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	installCmdName  = "install"
+	installDesc     = "Install a chaincode package on a peer for the chaincode lifecycle."
+	installFuncName = "InstallChaincode"
+)
+
+// installCmd returns the cobra command for installing a chaincode package
+func installCmd(cf *CmdFactory) *cobra.Command {
+	chaincodeInstallCmd := &cobra.Command{
+		Use:       installCmdName,
+		Short:     installDesc,
+		Long:      installDesc,
+		ValidArgs: []string{"1"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("chaincode install package not specified or invalid number of args (filename should be the only arg)")
+			}
+			return install(cmd, args[0], cf)
+		},
+	}
+	flagList := []string{
+		"name",
+		"version",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+	}
+	attachFlags(chaincodeInstallCmd, flagList)
+
+	return chaincodeInstallCmd
+}
+
+// install sends the chaincode install package to the peer
+func install(cmd *cobra.Command, pkgFile string, cf *CmdFactory) error {
+	if chaincodeName == "" || chaincodeVersion == "" {
+		return errors.Errorf("must supply value for %s name and version parameters", chainFuncName)
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	pkgBytes, err := ioutil.ReadFile(pkgFile)
+	if err != nil {
+		return errors.Wrapf(err, "error reading chaincode install package %s", pkgFile)
+	}
+
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), true, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	args := &lb.InstallChaincodeArgs{
+		Name:                    chaincodeName,
+		Version:                 chaincodeVersion,
+		ChaincodeInstallPackage: pkgBytes,
+	}
+
+	payload, err := query("", installFuncName, args, cf)
+	if err != nil {
+		return errors.WithMessage(err, "error installing chaincode")
+	}
+
+	result := &lb.InstallChaincodeResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return errors.Wrap(err, "failed to unmarshal install chaincode result")
+	}
+
+	logger.Infof("Installed remotely: %s:%s", chaincodeName, chaincodeVersion)
+	fmt.Printf("Chaincode hash: %x\n", result.Hash)
+
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstall(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "lifecycle-install-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	pkgFile := filepath.Join(tempDir, "pkg.tar.gz")
+	err = ioutil.WriteFile(pkgFile, []byte("package"), 0600)
+	assert.NoError(t, err)
+
+	cf := getMockCmdFactory(t, &lb.InstallChaincodeResult{Hash: []byte("hash")}, nil, nil)
+	cmd := newTestCmd(installCmd, cf, "-n", "mycc", "-v", "1.0", pkgFile)
+	err = cmd.Execute()
+	assert.NoError(t, err)
+
+	cmd = newTestCmd(installCmd, cf, "-n", "mycc", pkgFile)
+	err = cmd.Execute()
+	assert.EqualError(t, err, "must supply value for chaincode name and version parameters")
+
+	cmd = newTestCmd(installCmd, cf, "-n", "mycc", "-v", "1.0")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "chaincode install package not specified or invalid number of args (filename should be the only arg)")
+
+	cmd = newTestCmd(installCmd, cf, "-n", "mycc", "-v", "1.0", filepath.Join(tempDir, "missing"))
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error reading chaincode install package")
+
+	cf = getMockCmdFactory(t, nil, errors.New("endorser-error"), nil)
+	cmd = newTestCmd(installCmd, cf, "-n", "mycc", "-v", "1.0", pkgFile)
+	err = cmd.Execute()
+	assert.EqualError(t, err, "error installing chaincode: error endorsing proposal: endorser-error")
+}
@@ -0,0 +1,144 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/core/container"
+	ccpeer "github.com/hyperledger/fabric/peer/chaincode"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	packageCmdName = "package"
+	packageDesc    = "Package the specified chaincode into an install package for the chaincode lifecycle."
+
+	// codePackageFile is the name of the code package within the install package
+	codePackageFile = "Code-Package.tar.gz"
+)
+
+// packageCmd returns the cobra command for packaging a chaincode
+func packageCmd(cf *CmdFactory) *cobra.Command {
+	chaincodePackageCmd := &cobra.Command{
+		Use:       packageCmdName,
+		Short:     packageDesc,
+		Long:      packageDesc,
+		ValidArgs: []string{"1"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return errors.New("output file not specified or invalid number of args (filename should be the only arg)")
+			}
+			return chaincodePackage(cmd, args[0])
+		},
+	}
+	flagList := []string{
+		"lang",
+		"path",
+	}
+	attachFlags(chaincodePackageCmd, flagList)
+
+	return chaincodePackageCmd
+}
+
+// chaincodePackage writes an install package containing the chaincode
+// found at the path to the output file
+func chaincodePackage(cmd *cobra.Command, outputFile string) error {
+	if chaincodePath == "" {
+		return errors.Errorf("must supply value for %s path parameter", chainFuncName)
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	ccType := strings.ToUpper(chaincodeLang)
+	spec := &pb.ChaincodeSpec{
+		Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value[ccType]),
+		ChaincodeId: &pb.ChaincodeID{Path: chaincodePath},
+	}
+
+	platformRegistry := ccpeer.PlatformRegistry()
+	if err := platformRegistry.ValidateSpec(spec.CCType(), spec.Path()); err != nil {
+		return errors.WithMessage(err, "invalid chaincode spec")
+	}
+
+	codePackage, err := container.GetChaincodePackageBytes(platformRegistry, spec)
+	if err != nil {
+		return errors.WithMessage(err, "error getting chaincode code package bytes")
+	}
+
+	pkgBytes, err := writeInstallPackage(ccType, chaincodePath, codePackage)
+	if err != nil {
+		return err
+	}
+
+	err = ioutil.WriteFile(outputFile, pkgBytes, 0600)
+	if err != nil {
+		return errors.Wrapf(err, "error writing chaincode package to %s", outputFile)
+	}
+
+	return nil
+}
+
+// writeInstallPackage creates the .tar.gz install package understood by
+// persistence.ChaincodePackageParser from the metadata and code package
+func writeInstallPackage(ccType, path string, codePackage []byte) ([]byte, error) {
+	metadataBytes, err := json.Marshal(&persistence.ChaincodePackageMetadata{
+		Type: ccType,
+		Path: path,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal chaincode package metadata into JSON")
+	}
+
+	payload := bytes.NewBuffer(nil)
+	gw := gzip.NewWriter(payload)
+	tw := tar.NewWriter(gw)
+
+	files := []struct {
+		name     string
+		contents []byte
+	}{
+		{name: persistence.ChaincodePackageMetadataFile, contents: metadataBytes},
+		{name: codePackageFile, contents: codePackage},
+	}
+	for _, file := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     file.name,
+			Size:     int64(len(file.contents)),
+			Mode:     0100644,
+			ModTime:  time.Unix(0, 0),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to write header for %s", file.name)
+		}
+
+		if _, err := tw.Write(file.contents); err != nil {
+			return nil, errors.Wrapf(err, "failed to write %s to tar", file.name)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close tar writer")
+	}
+
+	if err := gw.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close gzip writer")
+	}
+
+	return payload.Bytes(), nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackage(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "lifecycle-package-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	ccDir := filepath.Join(tempDir, "chaincode")
+	err = os.Mkdir(ccDir, 0755)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(ccDir, "package.json"), []byte(`{"name": "mycc"}`), 0644)
+	assert.NoError(t, err)
+
+	outputFile := filepath.Join(tempDir, "mycc.tar.gz")
+	cmd := newTestCmd(packageCmd, nil, "-l", "node", "-p", ccDir, outputFile)
+	err = cmd.Execute()
+	assert.NoError(t, err)
+
+	pkgBytes, err := ioutil.ReadFile(outputFile)
+	assert.NoError(t, err)
+
+	ccPackage, err := persistence.ChaincodePackageParser{}.Parse(pkgBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, &persistence.ChaincodePackageMetadata{
+		Type: "NODE",
+		Path: ccDir,
+	}, ccPackage.Metadata)
+	assert.NotEmpty(t, ccPackage.CodePackage)
+}
+
+func TestPackageErrors(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "lifecycle-package-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	outputFile := filepath.Join(tempDir, "out.tar.gz")
+
+	cmd := newTestCmd(packageCmd, nil, outputFile)
+	err = cmd.Execute()
+	assert.EqualError(t, err, "must supply value for chaincode path parameter")
+
+	cmd = newTestCmd(packageCmd, nil, "-p", "github.com/hyperledger/fabric/examples/chaincode/go/example02/cmd")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "output file not specified or invalid number of args (filename should be the only arg)")
+
+	cmd = newTestCmd(packageCmd, nil, "-p", "github.com/hyperledger/fabric/examples/chaincode/go/bad_example02", outputFile)
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid chaincode spec")
+}
@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	queryCommittedCmdName  = "querycommitted"
+	queryCommittedDesc     = "Query the committed chaincode definition on the channel."
+	queryCommittedFuncName = "QueryChaincodeDefinition"
+)
+
+// queryCommittedCmd returns the cobra command for querying a committed
+// chaincode definition
+func queryCommittedCmd(cf *CmdFactory) *cobra.Command {
+	chaincodeQueryCommittedCmd := &cobra.Command{
+		Use:   queryCommittedCmdName,
+		Short: queryCommittedDesc,
+		Long:  queryCommittedDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return queryCommitted(cmd, cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"name",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+	}
+	attachFlags(chaincodeQueryCommittedCmd, flagList)
+
+	return chaincodeQueryCommittedCmd
+}
+
+// queryCommitted prints the definition of the chaincode committed to the
+// channel
+func queryCommitted(cmd *cobra.Command, cf *CmdFactory) error {
+	if channelID == "" {
+		return errors.New("The required parameter 'channelID' is empty. Rerun the command with -C flag")
+	}
+
+	if chaincodeName == "" {
+		return errors.Errorf("must supply value for %s name parameter", chainFuncName)
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), true, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	args := &lb.QueryChaincodeDefinitionArgs{
+		Name: chaincodeName,
+	}
+
+	payload, err := query(channelID, queryCommittedFuncName, args, cf)
+	if err != nil {
+		return errors.WithMessage(err, "error querying committed chaincode definition")
+	}
+
+	result := &lb.QueryChaincodeDefinitionResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return errors.Wrap(err, "failed to unmarshal query chaincode definition result")
+	}
+
+	fmt.Printf("Committed chaincode definition for chaincode '%s' on channel '%s':\n", chaincodeName, channelID)
+	fmt.Printf("Version: %s, Sequence: %d, Endorsement Plugin: %s, Validation Plugin: %s\n", result.Version, result.Sequence, result.EndorsementPlugin, result.ValidationPlugin)
+
+	return nil
+}
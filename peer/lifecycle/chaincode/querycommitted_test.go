@@ -0,0 +1,35 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"errors"
+	"testing"
+
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryCommitted(t *testing.T) {
+	cf := getMockCmdFactory(t, &lb.QueryChaincodeDefinitionResult{Sequence: 1, Version: "1.0"}, nil, nil)
+	cmd := newTestCmd(queryCommittedCmd, cf, "-C", "mychannel", "-n", "mycc")
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	cmd = newTestCmd(queryCommittedCmd, cf, "-C", "mychannel")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "must supply value for chaincode name parameter")
+
+	cmd = newTestCmd(queryCommittedCmd, cf, "-n", "mycc")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "The required parameter 'channelID' is empty. Rerun the command with -C flag")
+
+	cf = getMockCmdFactory(t, nil, errors.New("endorser-error"), nil)
+	cmd = newTestCmd(queryCommittedCmd, cf, "-C", "mychannel", "-n", "mycc")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "error querying committed chaincode definition: error endorsing proposal: endorser-error")
+}
@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const (
+	queryInstalledCmdName  = "queryinstalled"
+	queryInstalledDesc     = "Query the hash of a chaincode installed on a peer."
+	queryInstalledFuncName = "QueryInstalledChaincode"
+)
+
+// queryInstalledCmd returns the cobra command for querying an installed chaincode
+func queryInstalledCmd(cf *CmdFactory) *cobra.Command {
+	chaincodeQueryInstalledCmd := &cobra.Command{
+		Use:   queryInstalledCmdName,
+		Short: queryInstalledDesc,
+		Long:  queryInstalledDesc,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return queryInstalled(cmd, cf)
+		},
+	}
+	flagList := []string{
+		"name",
+		"version",
+		"peerAddresses",
+		"tlsRootCertFiles",
+		"connectionProfile",
+	}
+	attachFlags(chaincodeQueryInstalledCmd, flagList)
+
+	return chaincodeQueryInstalledCmd
+}
+
+// queryInstalled prints the hash of the chaincode installed under the
+// given name and version
+func queryInstalled(cmd *cobra.Command, cf *CmdFactory) error {
+	if chaincodeName == "" || chaincodeVersion == "" {
+		return errors.Errorf("must supply value for %s name and version parameters", chainFuncName)
+	}
+
+	// Parsing of the command line is done so silence cmd usage
+	cmd.SilenceUsage = true
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(cmd.Name(), true, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	args := &lb.QueryInstalledChaincodeArgs{
+		Name:    chaincodeName,
+		Version: chaincodeVersion,
+	}
+
+	payload, err := query("", queryInstalledFuncName, args, cf)
+	if err != nil {
+		return errors.WithMessage(err, "error querying installed chaincode")
+	}
+
+	result := &lb.QueryInstalledChaincodeResult{}
+	if err := proto.Unmarshal(payload, result); err != nil {
+		return errors.Wrap(err, "failed to unmarshal query installed chaincode result")
+	}
+
+	fmt.Printf("Chaincode hash: %x\n", result.Hash)
+
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryInstalled(t *testing.T) {
+	cf := getMockCmdFactory(t, &lb.QueryInstalledChaincodeResult{Hash: []byte("hash")}, nil, nil)
+	cmd := newTestCmd(queryInstalledCmd, cf, "-n", "mycc", "-v", "1.0")
+	err := cmd.Execute()
+	assert.NoError(t, err)
+
+	cmd = newTestCmd(queryInstalledCmd, cf, "-n", "mycc")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "must supply value for chaincode name and version parameters")
+
+	cmd = newTestCmd(queryInstalledCmd, cf, "-v", "1.0")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "must supply value for chaincode name and version parameters")
+
+	cf = getMockCmdFactory(t, nil, errors.New("endorser-error"), nil)
+	cmd = newTestCmd(queryInstalledCmd, cf, "-n", "mycc", "-v", "1.0")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "error querying installed chaincode: error endorsing proposal: endorser-error")
+
+	failedResponse := &pb.ProposalResponse{
+		Response: &pb.Response{Status: 500, Message: "chaincode not installed"},
+	}
+	cf.EndorserClients[0] = common.GetMockEndorserClient(failedResponse, nil)
+	cmd = newTestCmd(queryInstalledCmd, cf, "-n", "mycc", "-v", "1.0")
+	err = cmd.Execute()
+	assert.EqualError(t, err, "error querying installed chaincode: proposal failed with status: 500 - chaincode not installed")
+}
+
+func TestQueryInstalledBadPayload(t *testing.T) {
+	cf := getMockCmdFactory(t, nil, nil, nil)
+	cf.EndorserClients[0] = common.GetMockEndorserClient(&pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200, Payload: []byte("garbage")},
+		Endorsement: &pb.Endorsement{},
+	}, nil)
+	cmd := newTestCmd(queryInstalledCmd, cf, "-n", "mycc", "-v", "1.0")
+	err := cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to unmarshal query installed chaincode result")
+}
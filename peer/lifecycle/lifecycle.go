@@ -0,0 +1,25 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"github.com/hyperledger/fabric/peer/lifecycle/chaincode"
+	"github.com/spf13/cobra"
+)
+
+// Cmd returns the cobra command for lifecycle
+func Cmd() *cobra.Command {
+	lifecycleCmd.AddCommand(chaincode.Cmd(nil))
+
+	return lifecycleCmd
+}
+
+var lifecycleCmd = &cobra.Command{
+	Use:   "lifecycle",
+	Short: "Perform chaincode lifecycle operations",
+	Long:  "Perform chaincode lifecycle operations",
+}
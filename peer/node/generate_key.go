@@ -0,0 +1,39 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/crypto/encryption"
+	"github.com/spf13/cobra"
+)
+
+func generateKeyCmd() *cobra.Command {
+	return nodeGenerateKeyCmd
+}
+
+var nodeGenerateKeyCmd = &cobra.Command{
+	Use:   "generate-key",
+	Short: "Generates a key to encrypt the ledger data at rest.",
+	Long: `Generates an AES key in the keystore of the peer BCCSP and prints its subject key identifier, ` +
+		`which is the value to set in ledger.encryption.key to encrypt the ledger data at rest with the key.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		ski, err := encryption.GenerateKey(factory.GetDefault())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%x\n", ski)
+		return nil
+	},
+}
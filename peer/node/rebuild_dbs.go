@@ -0,0 +1,35 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+func rebuildDBsCmd() *cobra.Command {
+	return nodeRebuildCmd
+}
+
+var nodeRebuildCmd = &cobra.Command{
+	Use:   "rebuild-dbs",
+	Short: "Rebuilds databases.",
+	Long: `Drops the state, history and config history databases of all the channels and rebuilds them ` +
+		`from the blocks when the peer starts. When the command is executed, the peer must be offline. ` +
+		`The databases are rebuilt as per the current configuration of the peer, so the command can ` +
+		`also be used to switch the state database between goleveldb and CouchDB.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("trailing args detected: %s", args)
+		}
+		// Parsing of the command line is done so silence cmd usage
+		cmd.SilenceUsage = true
+		return kvledger.RebuildDBs()
+	},
+}
@@ -0,0 +1,32 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebuildDBsCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "rebuilddbs-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Reset()
+
+	cmd := rebuildDBsCmd()
+	cmd.SetArgs([]string{})
+	assert.NoError(t, cmd.Execute())
+
+	cmd.SetArgs([]string{"extra"})
+	err = cmd.Execute()
+	assert.EqualError(t, err, "trailing args detected: [extra]")
+}
@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollbackCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "rollback-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Reset()
+
+	cmd := rollbackCmd()
+
+	cmd.SetArgs([]string{"-b", "10"})
+	err = cmd.Execute()
+	assert.EqualError(t, err, "Must supply channel ID")
+
+	cmd.SetArgs([]string{"-c", "ch1", "-b", "10"})
+	err = cmd.Execute()
+	assert.EqualError(t, err, "ledgerID [ch1] does not exist")
+
+	cmd.SetArgs([]string{"-c", "ch1", "extra"})
+	err = cmd.Execute()
+	assert.EqualError(t, err, "trailing args detected: [extra]")
+}
+
+func TestResetCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "reset-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	viper.Set("peer.fileSystemPath", testPath)
+	defer viper.Reset()
+
+	cmd := resetCmd()
+	cmd.SetArgs([]string{})
+	assert.NoError(t, cmd.Execute())
+
+	cmd.SetArgs([]string{"extra"})
+	err = cmd.Execute()
+	assert.EqualError(t, err, "trailing args detected: [extra]")
+}
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
@@ -46,8 +47,10 @@ import (
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
+	coreconfig "github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/container"
 	"github.com/hyperledger/fabric/core/container/dockercontroller"
+	"github.com/hyperledger/fabric/core/container/externalbuilder"
 	"github.com/hyperledger/fabric/core/container/inproccontroller"
 	"github.com/hyperledger/fabric/core/endorser"
 	authHandler "github.com/hyperledger/fabric/core/handlers/auth"
@@ -59,6 +62,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/policyprovider"
 	"github.com/hyperledger/fabric/core/scc"
 	"github.com/hyperledger/fabric/core/scc/cscc"
 	"github.com/hyperledger/fabric/core/scc/lscc"
@@ -309,6 +313,9 @@ func serve(args []string) error {
 	})
 	endorserSupport.PluginEndorser = pluginEndorser
 	serverEndorser := endorser.NewEndorserServer(privDataDist, endorserSupport, pr, metricsProvider)
+	if viper.GetBool("peer.queryCache.enabled") {
+		serverEndorser.QueryCache = endorser.NewQueryCache(viper.GetDuration("peer.queryCache.ttl"), viper.GetInt("peer.queryCache.size"))
+	}
 	auth := authHandler.ChainFilters(serverEndorser, authFilters...)
 	// Register the Endorser server
 	pb.RegisterEndorserServer(peerServer.Server(), auth)
@@ -632,7 +639,8 @@ func registerChaincodeSupport(
 	packageProvider *persistence.PackageProvider,
 	aclProvider aclmgmt.ACLProvider,
 	pr *platforms.Registry,
-	lifecycleSCC *lifecycle.SCC,
+	ccStore *persistence.Store,
+	ccPackageParser *persistence.ChaincodePackageParser,
 	ops *operations.System,
 ) (*chaincode.ChaincodeSupport, ccprovider.ChaincodeProvider, *scc.Provider) {
 	//get user mode
@@ -645,20 +653,60 @@ func registerChaincodeSupport(
 	sccp := scc.NewProvider(peer.Default, peer.DefaultSupport, ipRegistry)
 	lsccInst := lscc.New(sccp, aclProvider, pr)
 
-	dockerProvider := dockercontroller.NewProvider(
-		viper.GetString("peer.id"),
-		viper.GetString("peer.networkId"),
-		ops.Provider,
-	)
-	dockerVM := dockercontroller.NewDockerVM(
-		dockerProvider.PeerID,
-		dockerProvider.NetworkID,
-		dockerProvider.BuildMetrics,
-	)
+	// chaincodes are resolved from the definitions committed through the
+	// new lifecycle, falling back to lscc for those it instantiated
+	lifecycleImpl := &lifecycle.Lifecycle{
+		PackageParser:       ccPackageParser,
+		ChaincodeStore:      ccStore,
+		ChannelMembership:   peer.Default,
+		ChannelConfigSource: peer.Default,
+		LegacyLifecycle:     lsccInst,
+		OrgMSPID:            viper.GetString("peer.localMspId"),
+	}
+	lifecycleSCC := &lifecycle.SCC{
+		Protobuf:      &lifecycle.ProtobufImpl{},
+		Functions:     lifecycleImpl,
+		ACLProvider:   aclProvider,
+		PolicyChecker: policyprovider.GetPolicyChecker(),
+	}
 
-	err := ops.RegisterChecker("docker", dockerVM)
-	if err != nil {
-		logger.Panicf("failed to register docker health check: %s", err)
+	vmProviders := map[string]container.VMProvider{
+		inproccontroller.ContainerType: ipRegistry,
+	}
+
+	// Docker is disabled when the endpoint of the vm management system is not
+	// set, in which case only the chaincodes detected by the external builders run
+	if viper.GetString("vm.endpoint") != "" {
+		dockerProvider := dockercontroller.NewProvider(
+			viper.GetString("peer.id"),
+			viper.GetString("peer.networkId"),
+			ops.Provider,
+		)
+		dockerVM := dockercontroller.NewDockerVM(
+			dockerProvider.PeerID,
+			dockerProvider.NetworkID,
+			dockerProvider.BuildMetrics,
+		)
+
+		err := ops.RegisterChecker("docker", dockerVM)
+		if err != nil {
+			logger.Panicf("failed to register docker health check: %s", err)
+		}
+		vmProviders[dockercontroller.ContainerType] = dockerProvider
+	}
+
+	var externalBuilderConfigs []externalbuilder.Config
+	if err := viperutil.EnhancedExactUnmarshalKey("chaincode.externalBuilders", &externalBuilderConfigs); err != nil {
+		logger.Panicf("Failed to load the external builders configuration: %s", err)
+	}
+	var externalBuilder chaincode.ExternalBuilder
+	if len(externalBuilderConfigs) > 0 {
+		externalBuilderProvider := externalbuilder.NewProvider(
+			externalBuilderConfigs,
+			filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "externalbuilds"),
+		)
+		vmProviders[externalbuilder.ContainerType] = externalBuilderProvider
+		externalBuilder = externalBuilderProvider
 	}
 
 	chaincodeSupport := chaincode.NewChaincodeSupport(
@@ -668,14 +716,10 @@ func registerChaincodeSupport(
 		ca.CertBytes(),
 		authenticator,
 		packageProvider,
-		lsccInst,
+		lifecycleImpl,
 		aclProvider,
-		container.NewVMController(
-			map[string]container.VMProvider{
-				dockercontroller.ContainerType: dockerProvider,
-				inproccontroller.ContainerType: ipRegistry,
-			},
-		),
+		container.NewVMController(vmProviders),
+		externalBuilder,
 		sccp,
 		pr,
 		peer.DefaultSupport,
@@ -725,14 +769,7 @@ func startChaincodeServer(
 	packageProvider := &persistence.PackageProvider{
 		LegacyPP: &ccprovider.CCInfoFSImpl{},
 		Store:    ccStore,
-	}
-
-	lifecycleSCC := &lifecycle.SCC{
-		Protobuf: &lifecycle.ProtobufImpl{},
-		Functions: &lifecycle.Lifecycle{
-			PackageParser:  ccPackageParser,
-			ChaincodeStore: ccStore,
-		},
+		Parser:   ccPackageParser,
 	}
 
 	// Create a self-signed CA for chaincode service
@@ -751,7 +788,8 @@ func startChaincodeServer(
 		packageProvider,
 		aclProvider,
 		pr,
-		lifecycleSCC,
+		ccStore,
+		ccPackageParser,
 		ops,
 	)
 	go ccSrv.Start()
@@ -33,7 +33,7 @@ func (m *KV) Reset()         { *m = KV{} }
 func (m *KV) String() string { return proto.CompactTextString(m) }
 func (*KV) ProtoMessage()    {}
 func (*KV) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_query_result_8f59f813f4fe5e5a, []int{0}
+	return fileDescriptor_kv_query_result_2ee764c002c1c6ad, []int{0}
 }
 func (m *KV) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KV.Unmarshal(m, b)
@@ -90,7 +90,7 @@ func (m *KeyModification) Reset()         { *m = KeyModification{} }
 func (m *KeyModification) String() string { return proto.CompactTextString(m) }
 func (*KeyModification) ProtoMessage()    {}
 func (*KeyModification) Descriptor() ([]byte, []int) {
-	return fileDescriptor_kv_query_result_8f59f813f4fe5e5a, []int{1}
+	return fileDescriptor_kv_query_result_2ee764c002c1c6ad, []int{1}
 }
 func (m *KeyModification) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KeyModification.Unmarshal(m, b)
@@ -138,33 +138,111 @@ func (m *KeyModification) GetIsDelete() bool {
 	return false
 }
 
+// PrivateDataKeyModification -- QueryResult for private data history query. Holds a
+// transaction ID, value hash, timestamp, and delete marker which resulted from a private
+// data history query. The value is set only if the peer still holds the private data
+// of the transaction.
+type PrivateDataKeyModification struct {
+	TxId                 string               `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	ValueHash            []byte               `protobuf:"bytes,2,opt,name=value_hash,json=valueHash,proto3" json:"value_hash,omitempty"`
+	Value                []byte               `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Timestamp            *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	IsDelete             bool                 `protobuf:"varint,5,opt,name=is_delete,json=isDelete,proto3" json:"is_delete,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *PrivateDataKeyModification) Reset()         { *m = PrivateDataKeyModification{} }
+func (m *PrivateDataKeyModification) String() string { return proto.CompactTextString(m) }
+func (*PrivateDataKeyModification) ProtoMessage()    {}
+func (*PrivateDataKeyModification) Descriptor() ([]byte, []int) {
+	return fileDescriptor_kv_query_result_2ee764c002c1c6ad, []int{2}
+}
+func (m *PrivateDataKeyModification) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PrivateDataKeyModification.Unmarshal(m, b)
+}
+func (m *PrivateDataKeyModification) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PrivateDataKeyModification.Marshal(b, m, deterministic)
+}
+func (dst *PrivateDataKeyModification) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PrivateDataKeyModification.Merge(dst, src)
+}
+func (m *PrivateDataKeyModification) XXX_Size() int {
+	return xxx_messageInfo_PrivateDataKeyModification.Size(m)
+}
+func (m *PrivateDataKeyModification) XXX_DiscardUnknown() {
+	xxx_messageInfo_PrivateDataKeyModification.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PrivateDataKeyModification proto.InternalMessageInfo
+
+func (m *PrivateDataKeyModification) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *PrivateDataKeyModification) GetValueHash() []byte {
+	if m != nil {
+		return m.ValueHash
+	}
+	return nil
+}
+
+func (m *PrivateDataKeyModification) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+func (m *PrivateDataKeyModification) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+func (m *PrivateDataKeyModification) GetIsDelete() bool {
+	if m != nil {
+		return m.IsDelete
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*KV)(nil), "queryresult.KV")
 	proto.RegisterType((*KeyModification)(nil), "queryresult.KeyModification")
+	proto.RegisterType((*PrivateDataKeyModification)(nil), "queryresult.PrivateDataKeyModification")
 }
 
 func init() {
-	proto.RegisterFile("ledger/queryresult/kv_query_result.proto", fileDescriptor_kv_query_result_8f59f813f4fe5e5a)
-}
-
-var fileDescriptor_kv_query_result_8f59f813f4fe5e5a = []byte{
-	// 286 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x51, 0x4f, 0x4b, 0xc3, 0x30,
-	0x1c, 0xa5, 0xdd, 0x26, 0x6b, 0x26, 0x28, 0xd1, 0x43, 0x99, 0x82, 0x65, 0xa7, 0x9e, 0x12, 0xd1,
-	0x83, 0x9e, 0xc5, 0x8b, 0x0e, 0x2f, 0x45, 0x3c, 0x78, 0x29, 0x69, 0xfb, 0x6b, 0x17, 0xda, 0x2e,
-	0x35, 0x7f, 0xc6, 0xfa, 0x39, 0xfc, 0xc2, 0x62, 0xb2, 0xd9, 0x82, 0xb7, 0xbc, 0xf7, 0x7b, 0xef,
-	0xf1, 0x78, 0x41, 0x71, 0x03, 0x45, 0x05, 0x92, 0x7e, 0x19, 0x90, 0xbd, 0x04, 0x65, 0x1a, 0x4d,
-	0xeb, 0x5d, 0x6a, 0x61, 0xea, 0x30, 0xe9, 0xa4, 0xd0, 0x02, 0x2f, 0x46, 0x92, 0xe5, 0x4d, 0x25,
-	0x44, 0xd5, 0x00, 0xb5, 0xa7, 0xcc, 0x94, 0x54, 0xf3, 0x16, 0x94, 0x66, 0x6d, 0xe7, 0xd4, 0xab,
-	0x57, 0xe4, 0xaf, 0x3f, 0xf0, 0x35, 0x0a, 0xb6, 0xac, 0x05, 0xd5, 0xb1, 0x1c, 0x42, 0x2f, 0xf2,
-	0xe2, 0x20, 0x19, 0x08, 0x7c, 0x8e, 0x26, 0x35, 0xf4, 0xa1, 0x6f, 0xf9, 0xdf, 0x27, 0xbe, 0x44,
-	0xb3, 0x1d, 0x6b, 0x0c, 0x84, 0x93, 0xc8, 0x8b, 0x4f, 0x13, 0x07, 0x56, 0xdf, 0x1e, 0x3a, 0x5b,
-	0x43, 0xff, 0x26, 0x0a, 0x5e, 0xf2, 0x9c, 0x69, 0x2e, 0xb6, 0xf8, 0x02, 0xcd, 0xf4, 0x3e, 0xe5,
-	0xc5, 0x21, 0x75, 0xaa, 0xf7, 0x2f, 0xc5, 0x60, 0xf7, 0x47, 0x76, 0xfc, 0x88, 0x82, 0xbf, 0x76,
-	0x36, 0x78, 0x71, 0xb7, 0x24, 0xae, 0x3f, 0x39, 0xf6, 0x27, 0xef, 0x47, 0x45, 0x32, 0x88, 0xf1,
-	0x15, 0x0a, 0xb8, 0x4a, 0x0b, 0x68, 0x40, 0x43, 0x38, 0x8d, 0xbc, 0x78, 0x9e, 0xcc, 0xb9, 0x7a,
-	0xb6, 0xf8, 0xa9, 0x46, 0xb7, 0x42, 0x56, 0x64, 0xd3, 0x77, 0x20, 0xdd, 0x88, 0xa4, 0x64, 0x99,
-	0xe4, 0xb9, 0x0b, 0x55, 0xe4, 0x40, 0x8e, 0x66, 0xfb, 0x7c, 0xa8, 0xb8, 0xde, 0x98, 0x8c, 0xe4,
-	0xa2, 0xa5, 0x23, 0x23, 0x75, 0x46, 0xb7, 0xa6, 0xa2, 0xff, 0xbf, 0x24, 0x3b, 0xb1, 0xa7, 0xfb,
-	0x9f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa2, 0xb7, 0x3e, 0x86, 0xaf, 0x01, 0x00, 0x00,
+	proto.RegisterFile("ledger/queryresult/kv_query_result.proto", fileDescriptor_kv_query_result_2ee764c002c1c6ad)
+}
+
+var fileDescriptor_kv_query_result_2ee764c002c1c6ad = []byte{
+	// 331 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0x4f, 0x4b, 0xc3, 0x30,
+	0x18, 0xc6, 0xe9, 0xfe, 0xc8, 0x9a, 0x09, 0x4a, 0xf4, 0x50, 0xa6, 0xe2, 0xd8, 0xa9, 0xa7, 0x44,
+	0xf4, 0xa0, 0x67, 0xd9, 0x41, 0x1d, 0x82, 0x14, 0xf1, 0xe0, 0xa5, 0xa4, 0xed, 0xbb, 0x36, 0xac,
+	0x5d, 0x6a, 0x92, 0x8e, 0xf5, 0x73, 0xf8, 0x79, 0xfc, 0x6e, 0x62, 0xb2, 0xd9, 0x0e, 0x3d, 0x88,
+	0xb7, 0x3e, 0xcf, 0xfb, 0x3c, 0x2f, 0xbf, 0x92, 0x17, 0xf9, 0x39, 0x24, 0x29, 0x48, 0xfa, 0x56,
+	0x81, 0xac, 0x25, 0xa8, 0x2a, 0xd7, 0x74, 0xb1, 0x0a, 0x8d, 0x0c, 0xad, 0x26, 0xa5, 0x14, 0x5a,
+	0xe0, 0x61, 0x2b, 0x32, 0x3a, 0x4f, 0x85, 0x48, 0x73, 0xa0, 0x66, 0x14, 0x55, 0x73, 0xaa, 0x79,
+	0x01, 0x4a, 0xb3, 0xa2, 0xb4, 0xe9, 0xc9, 0x03, 0xea, 0xcc, 0x5e, 0xf0, 0x29, 0x72, 0x97, 0xac,
+	0x00, 0x55, 0xb2, 0x18, 0x3c, 0x67, 0xec, 0xf8, 0x6e, 0xd0, 0x18, 0xf8, 0x10, 0x75, 0x17, 0x50,
+	0x7b, 0x1d, 0xe3, 0x7f, 0x7d, 0xe2, 0x63, 0xd4, 0x5f, 0xb1, 0xbc, 0x02, 0xaf, 0x3b, 0x76, 0xfc,
+	0xfd, 0xc0, 0x8a, 0xc9, 0xbb, 0x83, 0x0e, 0x66, 0x50, 0x3f, 0x8a, 0x84, 0xcf, 0x79, 0xcc, 0x34,
+	0x17, 0x4b, 0x7c, 0x84, 0xfa, 0x7a, 0x1d, 0xf2, 0x64, 0xb3, 0xb5, 0xa7, 0xd7, 0xf7, 0x49, 0x53,
+	0xef, 0xb4, 0xea, 0xf8, 0x06, 0xb9, 0xdf, 0x74, 0x66, 0xf1, 0xf0, 0x72, 0x44, 0x2c, 0x3f, 0xd9,
+	0xf2, 0x93, 0xe7, 0x6d, 0x22, 0x68, 0xc2, 0xf8, 0x04, 0xb9, 0x5c, 0x85, 0x09, 0xe4, 0xa0, 0xc1,
+	0xeb, 0x8d, 0x1d, 0x7f, 0x10, 0x0c, 0xb8, 0x9a, 0x1a, 0x3d, 0xf9, 0x70, 0xd0, 0xe8, 0x49, 0xf2,
+	0x15, 0xd3, 0x30, 0x65, 0x9a, 0xfd, 0x09, 0xf0, 0x0c, 0x21, 0xc3, 0x14, 0x66, 0x4c, 0x65, 0x1b,
+	0x4a, 0xd7, 0x38, 0x77, 0x4c, 0x65, 0xbf, 0xff, 0xfe, 0x2e, 0x7f, 0xef, 0xdf, 0xfc, 0xfd, 0x5d,
+	0xfe, 0xdb, 0x05, 0xba, 0x10, 0x32, 0x25, 0x59, 0x5d, 0x82, 0xb4, 0x47, 0x40, 0xe6, 0x2c, 0x92,
+	0x3c, 0xb6, 0x4b, 0x15, 0xd9, 0x98, 0xad, 0x67, 0x7f, 0xbd, 0x4e, 0xb9, 0xce, 0xaa, 0x88, 0xc4,
+	0xa2, 0xa0, 0xad, 0x22, 0xb5, 0x45, 0x7b, 0x0d, 0x8a, 0xfe, 0x3c, 0xa9, 0x68, 0xcf, 0x8c, 0xae,
+	0x3e, 0x07, 0x00, 0xfc, 0x37, 0x12, 0x46, 0x6f, 0x02, 0x00, 0x00,
 }
@@ -44,7 +44,7 @@ func (x SeekInfo_SeekBehavior) String() string {
 	return proto.EnumName(SeekInfo_SeekBehavior_name, int32(x))
 }
 func (SeekInfo_SeekBehavior) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{5, 0}
+	return fileDescriptor_ab_9aae76838678c6c5, []int{5, 0}
 }
 
 type BroadcastResponse struct {
@@ -61,7 +61,7 @@ func (m *BroadcastResponse) Reset()         { *m = BroadcastResponse{} }
 func (m *BroadcastResponse) String() string { return proto.CompactTextString(m) }
 func (*BroadcastResponse) ProtoMessage()    {}
 func (*BroadcastResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{0}
+	return fileDescriptor_ab_9aae76838678c6c5, []int{0}
 }
 func (m *BroadcastResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BroadcastResponse.Unmarshal(m, b)
@@ -105,7 +105,7 @@ func (m *SeekNewest) Reset()         { *m = SeekNewest{} }
 func (m *SeekNewest) String() string { return proto.CompactTextString(m) }
 func (*SeekNewest) ProtoMessage()    {}
 func (*SeekNewest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{1}
+	return fileDescriptor_ab_9aae76838678c6c5, []int{1}
 }
 func (m *SeekNewest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SeekNewest.Unmarshal(m, b)
@@ -135,7 +135,7 @@ func (m *SeekOldest) Reset()         { *m = SeekOldest{} }
 func (m *SeekOldest) String() string { return proto.CompactTextString(m) }
 func (*SeekOldest) ProtoMessage()    {}
 func (*SeekOldest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{2}
+	return fileDescriptor_ab_9aae76838678c6c5, []int{2}
 }
 func (m *SeekOldest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SeekOldest.Unmarshal(m, b)
@@ -166,7 +166,7 @@ func (m *SeekSpecified) Reset()         { *m = SeekSpecified{} }
 func (m *SeekSpecified) String() string { return proto.CompactTextString(m) }
 func (*SeekSpecified) ProtoMessage()    {}
 func (*SeekSpecified) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{3}
+	return fileDescriptor_ab_9aae76838678c6c5, []int{3}
 }
 func (m *SeekSpecified) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SeekSpecified.Unmarshal(m, b)
@@ -208,7 +208,7 @@ func (m *SeekPosition) Reset()         { *m = SeekPosition{} }
 func (m *SeekPosition) String() string { return proto.CompactTextString(m) }
 func (*SeekPosition) ProtoMessage()    {}
 func (*SeekPosition) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{4}
+	return fileDescriptor_ab_9aae76838678c6c5, []int{4}
 }
 func (m *SeekPosition) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SeekPosition.Unmarshal(m, b)
@@ -383,6 +383,7 @@ type SeekInfo struct {
 	Start                *SeekPosition         `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
 	Stop                 *SeekPosition         `protobuf:"bytes,2,opt,name=stop,proto3" json:"stop,omitempty"`
 	Behavior             SeekInfo_SeekBehavior `protobuf:"varint,3,opt,name=behavior,proto3,enum=orderer.SeekInfo_SeekBehavior" json:"behavior,omitempty"`
+	Filter               *TransactionFilter    `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
 	XXX_unrecognized     []byte                `json:"-"`
 	XXX_sizecache        int32                 `json:"-"`
@@ -392,7 +393,7 @@ func (m *SeekInfo) Reset()         { *m = SeekInfo{} }
 func (m *SeekInfo) String() string { return proto.CompactTextString(m) }
 func (*SeekInfo) ProtoMessage()    {}
 func (*SeekInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{5}
+	return fileDescriptor_ab_9aae76838678c6c5, []int{5}
 }
 func (m *SeekInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SeekInfo.Unmarshal(m, b)
@@ -433,6 +434,78 @@ func (m *SeekInfo) GetBehavior() SeekInfo_SeekBehavior {
 	return SeekInfo_BLOCK_UNTIL_READY
 }
 
+func (m *SeekInfo) GetFilter() *TransactionFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+// TransactionFilter restricts the transactions of the blocks delivered to the ones matching
+// all of its non empty criteria. The transactions which do not match are replaced by empty
+// entries in the block data, so that the header and the metadata of the block are unchanged.
+type TransactionFilter struct {
+	ChaincodeName        string   `protobuf:"bytes,1,opt,name=chaincode_name,json=chaincodeName,proto3" json:"chaincode_name,omitempty"`
+	ChaincodeEventName   string   `protobuf:"bytes,2,opt,name=chaincode_event_name,json=chaincodeEventName,proto3" json:"chaincode_event_name,omitempty"`
+	ValidationCodes      []int32  `protobuf:"varint,3,rep,packed,name=validation_codes,json=validationCodes,proto3" json:"validation_codes,omitempty"`
+	CreatorMspId         string   `protobuf:"bytes,4,opt,name=creator_msp_id,json=creatorMspId,proto3" json:"creator_msp_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionFilter) Reset()         { *m = TransactionFilter{} }
+func (m *TransactionFilter) String() string { return proto.CompactTextString(m) }
+func (*TransactionFilter) ProtoMessage()    {}
+func (*TransactionFilter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ab_9aae76838678c6c5, []int{6}
+}
+func (m *TransactionFilter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionFilter.Unmarshal(m, b)
+}
+func (m *TransactionFilter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionFilter.Marshal(b, m, deterministic)
+}
+func (dst *TransactionFilter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionFilter.Merge(dst, src)
+}
+func (m *TransactionFilter) XXX_Size() int {
+	return xxx_messageInfo_TransactionFilter.Size(m)
+}
+func (m *TransactionFilter) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionFilter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionFilter proto.InternalMessageInfo
+
+func (m *TransactionFilter) GetChaincodeName() string {
+	if m != nil {
+		return m.ChaincodeName
+	}
+	return ""
+}
+
+func (m *TransactionFilter) GetChaincodeEventName() string {
+	if m != nil {
+		return m.ChaincodeEventName
+	}
+	return ""
+}
+
+func (m *TransactionFilter) GetValidationCodes() []int32 {
+	if m != nil {
+		return m.ValidationCodes
+	}
+	return nil
+}
+
+func (m *TransactionFilter) GetCreatorMspId() string {
+	if m != nil {
+		return m.CreatorMspId
+	}
+	return ""
+}
+
 type DeliverResponse struct {
 	// Types that are valid to be assigned to Type:
 	//	*DeliverResponse_Status
@@ -447,7 +520,7 @@ func (m *DeliverResponse) Reset()         { *m = DeliverResponse{} }
 func (m *DeliverResponse) String() string { return proto.CompactTextString(m) }
 func (*DeliverResponse) ProtoMessage()    {}
 func (*DeliverResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ab_86effae0ebc2388c, []int{6}
+	return fileDescriptor_ab_9aae76838678c6c5, []int{7}
 }
 func (m *DeliverResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeliverResponse.Unmarshal(m, b)
@@ -580,6 +653,7 @@ func init() {
 	proto.RegisterType((*SeekSpecified)(nil), "orderer.SeekSpecified")
 	proto.RegisterType((*SeekPosition)(nil), "orderer.SeekPosition")
 	proto.RegisterType((*SeekInfo)(nil), "orderer.SeekInfo")
+	proto.RegisterType((*TransactionFilter)(nil), "orderer.TransactionFilter")
 	proto.RegisterType((*DeliverResponse)(nil), "orderer.DeliverResponse")
 	proto.RegisterEnum("orderer.SeekInfo_SeekBehavior", SeekInfo_SeekBehavior_name, SeekInfo_SeekBehavior_value)
 }
@@ -757,40 +831,48 @@ var _AtomicBroadcast_serviceDesc = grpc.ServiceDesc{
 	Metadata: "orderer/ab.proto",
 }
 
-func init() { proto.RegisterFile("orderer/ab.proto", fileDescriptor_ab_86effae0ebc2388c) }
-
-var fileDescriptor_ab_86effae0ebc2388c = []byte{
-	// 505 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x93, 0xdf, 0x6e, 0x12, 0x51,
-	0x10, 0xc6, 0x59, 0xa5, 0xb4, 0x8c, 0x2d, 0xa5, 0xa7, 0x69, 0x43, 0xb8, 0x30, 0xcd, 0x26, 0x55,
-	0x8c, 0xba, 0x6b, 0x30, 0xf1, 0x42, 0x4d, 0x0c, 0x6b, 0xdb, 0x40, 0x24, 0x60, 0x16, 0x7a, 0xa1,
-	0x37, 0x64, 0x77, 0x19, 0x60, 0xed, 0xb2, 0x67, 0x73, 0xce, 0x01, 0xd3, 0xa7, 0xf0, 0x45, 0x7c,
-	0x24, 0x1f, 0xc6, 0x9c, 0x3f, 0xbb, 0x48, 0xd5, 0x5e, 0xb1, 0x33, 0xf3, 0xfb, 0xce, 0x7c, 0x33,
-	0x19, 0xa0, 0x4e, 0xd9, 0x14, 0x19, 0x32, 0x37, 0x08, 0x9d, 0x8c, 0x51, 0x41, 0xc9, 0xae, 0xc9,
-	0x34, 0x8f, 0x23, 0xba, 0x5c, 0xd2, 0xd4, 0xd5, 0x3f, 0xba, 0x6a, 0x0f, 0xe1, 0xc8, 0x63, 0x34,
-	0x98, 0x46, 0x01, 0x17, 0x3e, 0xf2, 0x8c, 0xa6, 0x1c, 0xc9, 0x13, 0xa8, 0x70, 0x11, 0x88, 0x15,
-	0x6f, 0x58, 0x67, 0x56, 0xab, 0xd6, 0xae, 0x39, 0x46, 0x33, 0x52, 0x59, 0xdf, 0x54, 0x09, 0x81,
-	0x72, 0x9c, 0xce, 0x68, 0xe3, 0xc1, 0x99, 0xd5, 0xaa, 0xfa, 0xea, 0xdb, 0xde, 0x07, 0x18, 0x21,
-	0xde, 0x0c, 0xf0, 0x3b, 0x72, 0x91, 0x47, 0xc3, 0x64, 0x2a, 0xa3, 0xa7, 0x70, 0x20, 0xa3, 0x51,
-	0x86, 0x51, 0x3c, 0x8b, 0x71, 0x4a, 0x4e, 0xa1, 0x92, 0xae, 0x96, 0x21, 0x32, 0xd5, 0xa8, 0xec,
-	0x9b, 0xc8, 0xfe, 0x69, 0xc1, 0xbe, 0x24, 0x3f, 0x53, 0x1e, 0x8b, 0x98, 0xa6, 0xe4, 0x25, 0x54,
-	0x52, 0xf5, 0xa2, 0x02, 0x1f, 0xb5, 0x8f, 0x1d, 0x33, 0x95, 0xb3, 0x69, 0xd6, 0x2d, 0xf9, 0x06,
-	0x92, 0x38, 0x55, 0x2d, 0x95, 0xb5, 0xbb, 0xb8, 0x76, 0x23, 0x71, 0x0d, 0x91, 0x37, 0x50, 0xe5,
-	0xb9, 0xa7, 0xc6, 0x43, 0xa5, 0x38, 0xdd, 0x52, 0x14, 0x8e, 0xbb, 0x25, 0x7f, 0x83, 0x7a, 0x15,
-	0x28, 0x8f, 0x6f, 0x33, 0xb4, 0x7f, 0x59, 0xb0, 0x27, 0xb1, 0x5e, 0x3a, 0xa3, 0xe4, 0x39, 0xec,
-	0x70, 0x11, 0xb0, 0xdc, 0xe9, 0xc9, 0xd6, 0x43, 0xf9, 0x40, 0xbe, 0x66, 0xc8, 0x33, 0x28, 0x73,
-	0x41, 0x33, 0x63, 0xf3, 0x3f, 0xac, 0x42, 0xc8, 0x5b, 0xd8, 0x0b, 0x71, 0x11, 0xac, 0x63, 0xca,
-	0x94, 0xc7, 0x5a, 0xfb, 0xf1, 0x16, 0x2e, 0x9b, 0xab, 0x0f, 0xcf, 0x50, 0x7e, 0xc1, 0xdb, 0xef,
-	0xf5, 0x3a, 0xf3, 0x0a, 0x39, 0x81, 0x23, 0xaf, 0x3f, 0xfc, 0xf8, 0x69, 0x72, 0x3d, 0x18, 0xf7,
-	0xfa, 0x13, 0xff, 0xb2, 0x73, 0xf1, 0xa5, 0x5e, 0x92, 0xe9, 0xab, 0x4e, 0xaf, 0x3f, 0xe9, 0x5d,
-	0x4d, 0x06, 0xc3, 0xb1, 0x49, 0x5b, 0xf6, 0x37, 0x38, 0xbc, 0xc0, 0x24, 0x5e, 0x23, 0x2b, 0x2e,
-	0xa4, 0x75, 0xff, 0x85, 0xc8, 0xdd, 0x9a, 0x1b, 0x39, 0x87, 0x9d, 0x30, 0xa1, 0xd1, 0x8d, 0x19,
-	0xf1, 0x20, 0x07, 0x3d, 0x99, 0xec, 0x96, 0x7c, 0x5d, 0xcd, 0x57, 0xd9, 0xfe, 0x61, 0xc1, 0x61,
-	0x47, 0xd0, 0x65, 0x1c, 0x15, 0x67, 0x49, 0x3e, 0x40, 0x75, 0x13, 0xd4, 0xf3, 0x07, 0x2e, 0xd3,
-	0x35, 0x26, 0x34, 0xc3, 0x66, 0xb3, 0x58, 0xc3, 0x5f, 0x97, 0x6c, 0x97, 0x5a, 0xd6, 0x2b, 0x8b,
-	0xbc, 0x83, 0x5d, 0x33, 0xc0, 0x3f, 0xe4, 0x8d, 0x42, 0x7e, 0x67, 0x48, 0x2d, 0xf6, 0xae, 0xe1,
-	0x9c, 0xb2, 0xb9, 0xb3, 0xb8, 0xcd, 0x90, 0x25, 0x38, 0x9d, 0x23, 0x73, 0x66, 0x41, 0xc8, 0xe2,
-	0x48, 0xff, 0x83, 0x78, 0x2e, 0xff, 0xfa, 0x62, 0x1e, 0x8b, 0xc5, 0x2a, 0x94, 0x0d, 0xdc, 0x3f,
-	0x68, 0x57, 0xd3, 0xae, 0xa6, 0x5d, 0x43, 0x87, 0x15, 0x15, 0xbf, 0xfe, 0x1d, 0x00, 0x00, 0xff,
-	0xff, 0x4b, 0x88, 0xa4, 0x39, 0xb1, 0x03, 0x00, 0x00,
+func init() { proto.RegisterFile("orderer/ab.proto", fileDescriptor_ab_9aae76838678c6c5) }
+
+var fileDescriptor_ab_9aae76838678c6c5 = []byte{
+	// 625 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x94, 0xdf, 0x4e, 0xdb, 0x4a,
+	0x10, 0xc6, 0xe3, 0x10, 0x02, 0x99, 0x13, 0x42, 0x58, 0x0e, 0xc8, 0xe2, 0xe2, 0x08, 0x59, 0x87,
+	0x36, 0xa8, 0x6d, 0x82, 0x52, 0xa9, 0x17, 0x6d, 0xa5, 0x8a, 0xf0, 0x47, 0x44, 0xa5, 0x49, 0xb5,
+	0x84, 0x8b, 0xf6, 0xc6, 0xda, 0xd8, 0x13, 0xb2, 0x25, 0xf6, 0x5a, 0xbb, 0x26, 0x15, 0x4f, 0xd1,
+	0x3e, 0x48, 0x5f, 0xa1, 0xef, 0x56, 0xed, 0x7a, 0xed, 0x40, 0x41, 0x5c, 0xc5, 0xf3, 0xcd, 0xef,
+	0x9b, 0x99, 0xb5, 0x77, 0x02, 0x4d, 0x21, 0x43, 0x94, 0x28, 0x3b, 0x6c, 0xdc, 0x4e, 0xa4, 0x48,
+	0x05, 0x59, 0xb1, 0xca, 0xce, 0x66, 0x20, 0xa2, 0x48, 0xc4, 0x9d, 0xec, 0x27, 0xcb, 0x7a, 0x43,
+	0xd8, 0xe8, 0x49, 0xc1, 0xc2, 0x80, 0xa9, 0x94, 0xa2, 0x4a, 0x44, 0xac, 0x90, 0x3c, 0x83, 0xaa,
+	0x4a, 0x59, 0x7a, 0xa3, 0x5c, 0x67, 0xd7, 0x69, 0x35, 0xba, 0x8d, 0xb6, 0xf5, 0x5c, 0x18, 0x95,
+	0xda, 0x2c, 0x21, 0x50, 0xe1, 0xf1, 0x44, 0xb8, 0xe5, 0x5d, 0xa7, 0x55, 0xa3, 0xe6, 0xd9, 0xab,
+	0x03, 0x5c, 0x20, 0x5e, 0x0f, 0xf0, 0x3b, 0xaa, 0x34, 0x8f, 0x86, 0xb3, 0x50, 0x47, 0xcf, 0x61,
+	0x4d, 0x47, 0x17, 0x09, 0x06, 0x7c, 0xc2, 0x31, 0x24, 0xdb, 0x50, 0x8d, 0x6f, 0xa2, 0x31, 0x4a,
+	0xd3, 0xa8, 0x42, 0x6d, 0xe4, 0xfd, 0x72, 0xa0, 0xae, 0xc9, 0xcf, 0x42, 0xf1, 0x94, 0x8b, 0x98,
+	0xbc, 0x82, 0x6a, 0x6c, 0x2a, 0x1a, 0xf0, 0x9f, 0xee, 0x66, 0xdb, 0x9e, 0xaa, 0xbd, 0x68, 0x76,
+	0x56, 0xa2, 0x16, 0xd2, 0xb8, 0x30, 0x2d, 0xdd, 0xf2, 0x23, 0x78, 0x36, 0x8d, 0xc6, 0x33, 0x88,
+	0xbc, 0x81, 0x9a, 0xca, 0x67, 0x72, 0x97, 0x8c, 0x63, 0xfb, 0x9e, 0xa3, 0x98, 0xf8, 0xac, 0x44,
+	0x17, 0x68, 0xaf, 0x0a, 0x95, 0xd1, 0x6d, 0x82, 0xde, 0xcf, 0x32, 0xac, 0x6a, 0xac, 0x1f, 0x4f,
+	0x04, 0x79, 0x01, 0xcb, 0x2a, 0x65, 0x32, 0x9f, 0x74, 0xeb, 0x5e, 0xa1, 0xfc, 0x40, 0x34, 0x63,
+	0xc8, 0x3e, 0x54, 0x54, 0x2a, 0x12, 0xb7, 0xfc, 0x14, 0x6b, 0x10, 0xf2, 0x16, 0x56, 0xc7, 0x38,
+	0x65, 0x73, 0x2e, 0xa4, 0x99, 0xb1, 0xd1, 0xfd, 0xef, 0x1e, 0xae, 0x9b, 0x9b, 0x87, 0x9e, 0xa5,
+	0x68, 0xc1, 0x93, 0x2e, 0x54, 0x27, 0x7c, 0x96, 0xa2, 0x74, 0x2b, 0xa6, 0xd1, 0x4e, 0xe1, 0x1c,
+	0x49, 0x16, 0x2b, 0x16, 0xe8, 0x3e, 0xa7, 0x86, 0xa0, 0x96, 0xf4, 0xde, 0x43, 0xfd, 0x6e, 0x35,
+	0xb2, 0x05, 0x1b, 0xbd, 0xf3, 0xe1, 0xd1, 0x47, 0xff, 0x72, 0x30, 0xea, 0x9f, 0xfb, 0xf4, 0xe4,
+	0xf0, 0xf8, 0x4b, 0xb3, 0xa4, 0xe5, 0xd3, 0xc3, 0xfe, 0xb9, 0xdf, 0x3f, 0xf5, 0x07, 0xc3, 0x91,
+	0x95, 0x1d, 0xef, 0xb7, 0x03, 0x1b, 0x0f, 0x6a, 0x93, 0x3d, 0x68, 0x04, 0x53, 0xc6, 0xe3, 0x40,
+	0x84, 0xe8, 0xc7, 0x2c, 0x42, 0xf3, 0x92, 0x6a, 0x74, 0xad, 0x50, 0x07, 0x2c, 0x42, 0x72, 0x00,
+	0xff, 0x2e, 0x30, 0x9c, 0x63, 0x9c, 0x66, 0x70, 0x76, 0xcf, 0x48, 0x91, 0x3b, 0xd1, 0x29, 0xe3,
+	0xd8, 0x87, 0xe6, 0x9c, 0xcd, 0x78, 0xc8, 0x74, 0x33, 0x5f, 0xe7, 0x94, 0xbb, 0xb4, 0xbb, 0xd4,
+	0x5a, 0xa6, 0xeb, 0x0b, 0xfd, 0x48, 0xcb, 0xe4, 0x7f, 0x68, 0x04, 0x12, 0x59, 0x2a, 0xa4, 0x1f,
+	0xa9, 0xc4, 0xe7, 0xa1, 0x79, 0x27, 0x35, 0x5a, 0xb7, 0xea, 0x27, 0x95, 0xf4, 0x43, 0xef, 0x1b,
+	0xac, 0x1f, 0xe3, 0x8c, 0xcf, 0x51, 0x16, 0x5b, 0xd1, 0x7a, 0x7a, 0x2b, 0xf4, 0x7d, 0xb2, 0x7b,
+	0xb1, 0x07, 0xcb, 0xe3, 0x99, 0x08, 0xae, 0xed, 0x67, 0x5d, 0xcb, 0xc1, 0x9e, 0x16, 0xcf, 0x4a,
+	0x34, 0xcb, 0xe6, 0xd7, 0xa7, 0xfb, 0xc3, 0x81, 0xf5, 0xc3, 0x54, 0x44, 0x3c, 0x28, 0x56, 0x91,
+	0x7c, 0x80, 0xda, 0x22, 0x68, 0xe6, 0x05, 0x4e, 0xe2, 0x39, 0xce, 0x44, 0x82, 0x3b, 0x8b, 0x0f,
+	0xf8, 0x60, 0x7b, 0xbd, 0x52, 0xcb, 0x39, 0x70, 0xc8, 0x3b, 0x58, 0xb1, 0x07, 0x78, 0xc4, 0xee,
+	0x16, 0xf6, 0xbf, 0x0e, 0x99, 0x99, 0x7b, 0x97, 0xb0, 0x27, 0xe4, 0x55, 0x7b, 0x7a, 0x9b, 0xa0,
+	0x9c, 0x61, 0x78, 0x85, 0xb2, 0x3d, 0x61, 0x63, 0xc9, 0x83, 0xec, 0x5f, 0x43, 0xe5, 0xf6, 0xaf,
+	0x2f, 0xaf, 0x78, 0x3a, 0xbd, 0x19, 0xeb, 0x06, 0x9d, 0x3b, 0x74, 0x27, 0xa3, 0x3b, 0x19, 0xdd,
+	0xb1, 0xf4, 0xb8, 0x6a, 0xe2, 0xd7, 0x7f, 0x06, 0x00, 0x65, 0xae, 0xf0, 0xc3, 0xa5, 0x04, 0x00,
+	0x00,
 }
@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer"
+)
+
+// TypeKey is the string with which this consensus implementation is identified across Fabric.
+const TypeKey = "bft"
+
+func init() {
+	orderer.ConsensusTypeMetadataMap[TypeKey] = ConsensusTypeMetadataFactory{}
+}
+
+// ConsensusTypeMetadataFactory allows this implementation's proto messages to register
+// their type with the orderer's proto messages. This is needed for protolator to work.
+type ConsensusTypeMetadataFactory struct{}
+
+// NewMessage implements the Orderer.ConsensusTypeMetadataFactory interface.
+func (dogf ConsensusTypeMetadataFactory) NewMessage() proto.Message {
+	return &Metadata{}
+}
+
+// Quorum returns the number of nodes out of n which must agree for a decision to be taken,
+// such that any two quorums intersect in at least one correct node while tolerating
+// f = (n-1)/3 faulty nodes. For n = 3f+1 it is 2f+1.
+func Quorum(n int) int {
+	f := (n - 1) / 3
+	return (n + f + 2) / 2
+}
+
+// MaxFaulty returns the number of faulty nodes tolerated out of n.
+func MaxFaulty(n int) int {
+	return (n - 1) / 3
+}
+
+// BlockValidationPolicy returns the policy which requires the blocks to be signed by a
+// quorum of the given consenters, so that the peers do not trust a block attested by the
+// faulty consenters only. It is derived from the consenter set, hence the BlockValidation
+// policy of a channel must be replaced by it whenever the consenters change.
+func BlockValidationPolicy(consenters []*Consenter) (*common.SignaturePolicyEnvelope, error) {
+	var signedBy []*common.SignaturePolicy
+	var identities []*msp.MSPPrincipal
+	for i, c := range consenters {
+		principal, err := proto.Marshal(&msp.SerializedIdentity{Mspid: c.MspId, IdBytes: c.Identity})
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal identity of consenter %s:%d: %s", c.GetHost(), c.GetPort(), err)
+		}
+		signedBy = append(signedBy, &common.SignaturePolicy{
+			Type: &common.SignaturePolicy_SignedBy{SignedBy: int32(i)},
+		})
+		identities = append(identities, &msp.MSPPrincipal{
+			PrincipalClassification: msp.MSPPrincipal_IDENTITY,
+			Principal:               principal,
+		})
+	}
+
+	return &common.SignaturePolicyEnvelope{
+		Version: 0,
+		Rule: &common.SignaturePolicy{
+			Type: &common.SignaturePolicy_NOutOf_{
+				NOutOf: &common.SignaturePolicy_NOutOf{
+					N:     int32(Quorum(len(consenters))),
+					Rules: signedBy,
+				},
+			},
+		},
+		Identities: identities,
+	}, nil
+}
+
+// Marshal serializes this implementation's proto messages. It is called by the encoder package
+// during the creation of the Orderer ConfigGroup.
+func Marshal(md *Metadata) ([]byte, error) {
+	for _, c := range md.Consenters {
+		// Expect the user to set the config value for the identity and the client/server
+		// certs to the path where they are persisted locally, then load these files to memory.
+		identity, err := ioutil.ReadFile(string(c.GetIdentity()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot load identity for consenter %s:%d: %s", c.GetHost(), c.GetPort(), err)
+		}
+		// The identity is compared to the serialized identity of the signers of the
+		// blocks, hence it is re-encoded the same way the MSP serializes certificates
+		bl, _ := pem.Decode(identity)
+		if bl == nil {
+			return nil, fmt.Errorf("identity of consenter %s:%d is not PEM encoded", c.GetHost(), c.GetPort())
+		}
+		c.Identity = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: bl.Bytes})
+
+		clientCert, err := ioutil.ReadFile(string(c.GetClientTlsCert()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot load client cert for consenter %s:%d: %s", c.GetHost(), c.GetPort(), err)
+		}
+		c.ClientTlsCert = clientCert
+
+		serverCert, err := ioutil.ReadFile(string(c.GetServerTlsCert()))
+		if err != nil {
+			return nil, fmt.Errorf("cannot load server cert for consenter %s:%d: %s", c.GetHost(), c.GetPort(), err)
+		}
+		c.ServerTlsCert = serverCert
+	}
+	return proto.Marshal(md)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package bft_test
+
+import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/orderer/bft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	md := &bft.Metadata{
+		Consenters: []*bft.Consenter{
+			{
+				ConsenterId:   1,
+				Host:          "node-1.example.com",
+				Port:          7050,
+				MspId:         "OrdererMSP",
+				Identity:      []byte("testdata/tls-server-1.pem"),
+				ClientTlsCert: []byte("testdata/tls-client-1.pem"),
+				ServerTlsCert: []byte("testdata/tls-server-1.pem"),
+			},
+		},
+	}
+	packed, err := bft.Marshal(md)
+	require.Nil(t, err, "marshalling should succeed")
+
+	unpacked := &bft.Metadata{}
+	require.Nil(t, proto.Unmarshal(packed, unpacked), "unmarshalling should succeed")
+
+	serverCert, err := ioutil.ReadFile("testdata/tls-server-1.pem")
+	require.NoError(t, err)
+	bl, _ := pem.Decode(serverCert)
+	require.NotNil(t, bl)
+	require.Equal(t, pem.EncodeToMemory(bl), unpacked.Consenters[0].Identity)
+	require.Equal(t, serverCert, unpacked.Consenters[0].ServerTlsCert)
+
+	md.Consenters[0].Identity = []byte("testdata/tls-client-1.pem")
+	md.Consenters[0].ClientTlsCert = []byte("testdata/missing.pem")
+	_, err = bft.Marshal(md)
+	require.EqualError(t, err, "cannot load client cert for consenter node-1.example.com:7050: open testdata/missing.pem: no such file or directory")
+}
+
+func TestQuorum(t *testing.T) {
+	for _, tc := range []struct {
+		n, f, quorum int
+	}{
+		{n: 1, f: 0, quorum: 1},
+		{n: 2, f: 0, quorum: 2},
+		{n: 3, f: 0, quorum: 2},
+		{n: 4, f: 1, quorum: 3},
+		{n: 5, f: 1, quorum: 4},
+		{n: 6, f: 1, quorum: 4},
+		{n: 7, f: 2, quorum: 5},
+		{n: 10, f: 3, quorum: 7},
+	} {
+		require.Equal(t, tc.f, bft.MaxFaulty(tc.n), "n = %d", tc.n)
+		require.Equal(t, tc.quorum, bft.Quorum(tc.n), "n = %d", tc.n)
+	}
+}
+
+func TestBlockValidationPolicy(t *testing.T) {
+	var consenters []*bft.Consenter
+	for i := 1; i <= 4; i++ {
+		consenters = append(consenters, &bft.Consenter{
+			ConsenterId: uint64(i),
+			MspId:       "OrdererMSP",
+			Identity:    []byte(fmt.Sprintf("identity-%d", i)),
+		})
+	}
+
+	policy, err := bft.BlockValidationPolicy(consenters)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), policy.Rule.GetNOutOf().N)
+	require.Len(t, policy.Rule.GetNOutOf().Rules, 4)
+	require.Len(t, policy.Identities, 4)
+	for i, principal := range policy.Identities {
+		require.Equal(t, msp.MSPPrincipal_IDENTITY, principal.PrincipalClassification)
+		sID := &msp.SerializedIdentity{}
+		require.NoError(t, proto.Unmarshal(principal.Principal, sID))
+		require.Equal(t, "OrdererMSP", sID.Mspid)
+		require.Equal(t, []byte(fmt.Sprintf("identity-%d", i+1)), sID.IdBytes)
+		require.Equal(t, int32(i), policy.Rule.GetNOutOf().Rules[i].GetSignedBy())
+	}
+
+	// Adding a consenter raises the quorum
+	consenters = append(consenters, &bft.Consenter{ConsenterId: 5, MspId: "OrdererMSP", Identity: []byte("identity-5")})
+	policy, err = bft.BlockValidationPolicy(consenters)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), policy.Rule.GetNOutOf().N)
+	require.Len(t, policy.Identities, 5)
+}
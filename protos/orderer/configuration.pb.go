@@ -49,7 +49,30 @@ func (x ConsensusType_MigrationState) String() string {
 	return proto.EnumName(ConsensusType_MigrationState_name, int32(x))
 }
 func (ConsensusType_MigrationState) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_acd4b7f3e02dac02, []int{0, 0}
+	return fileDescriptor_configuration_64adb92a33b31eca, []int{0, 0}
+}
+
+type BlockCutting_Mode int32
+
+const (
+	BlockCutting_FIFO BlockCutting_Mode = 0
+	BlockCutting_FAIR BlockCutting_Mode = 1
+)
+
+var BlockCutting_Mode_name = map[int32]string{
+	0: "FIFO",
+	1: "FAIR",
+}
+var BlockCutting_Mode_value = map[string]int32{
+	"FIFO": 0,
+	"FAIR": 1,
+}
+
+func (x BlockCutting_Mode) String() string {
+	return proto.EnumName(BlockCutting_Mode_name, int32(x))
+}
+func (BlockCutting_Mode) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_64adb92a33b31eca, []int{5, 0}
 }
 
 type ConsensusType struct {
@@ -75,7 +98,7 @@ func (m *ConsensusType) Reset()         { *m = ConsensusType{} }
 func (m *ConsensusType) String() string { return proto.CompactTextString(m) }
 func (*ConsensusType) ProtoMessage()    {}
 func (*ConsensusType) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_acd4b7f3e02dac02, []int{0}
+	return fileDescriptor_configuration_64adb92a33b31eca, []int{0}
 }
 func (m *ConsensusType) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ConsensusType.Unmarshal(m, b)
@@ -142,7 +165,7 @@ func (m *BatchSize) Reset()         { *m = BatchSize{} }
 func (m *BatchSize) String() string { return proto.CompactTextString(m) }
 func (*BatchSize) ProtoMessage()    {}
 func (*BatchSize) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_acd4b7f3e02dac02, []int{1}
+	return fileDescriptor_configuration_64adb92a33b31eca, []int{1}
 }
 func (m *BatchSize) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchSize.Unmarshal(m, b)
@@ -196,7 +219,7 @@ func (m *BatchTimeout) Reset()         { *m = BatchTimeout{} }
 func (m *BatchTimeout) String() string { return proto.CompactTextString(m) }
 func (*BatchTimeout) ProtoMessage()    {}
 func (*BatchTimeout) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_acd4b7f3e02dac02, []int{2}
+	return fileDescriptor_configuration_64adb92a33b31eca, []int{2}
 }
 func (m *BatchTimeout) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BatchTimeout.Unmarshal(m, b)
@@ -238,7 +261,7 @@ func (m *KafkaBrokers) Reset()         { *m = KafkaBrokers{} }
 func (m *KafkaBrokers) String() string { return proto.CompactTextString(m) }
 func (*KafkaBrokers) ProtoMessage()    {}
 func (*KafkaBrokers) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_acd4b7f3e02dac02, []int{3}
+	return fileDescriptor_configuration_64adb92a33b31eca, []int{3}
 }
 func (m *KafkaBrokers) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_KafkaBrokers.Unmarshal(m, b)
@@ -277,7 +300,7 @@ func (m *ChannelRestrictions) Reset()         { *m = ChannelRestrictions{} }
 func (m *ChannelRestrictions) String() string { return proto.CompactTextString(m) }
 func (*ChannelRestrictions) ProtoMessage()    {}
 func (*ChannelRestrictions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_configuration_acd4b7f3e02dac02, []int{4}
+	return fileDescriptor_configuration_64adb92a33b31eca, []int{4}
 }
 func (m *ChannelRestrictions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChannelRestrictions.Unmarshal(m, b)
@@ -304,48 +327,119 @@ func (m *ChannelRestrictions) GetMaxCount() uint64 {
 	return 0
 }
 
+// BlockCutting selects how the orderers of a channel cut the pending transactions into blocks
+type BlockCutting struct {
+	Mode BlockCutting_Mode `protobuf:"varint,1,opt,name=mode,proto3,enum=orderer.BlockCutting_Mode" json:"mode,omitempty"`
+	// The number of transactions taken in each round from the organizations, keyed by MSP ID,
+	// when filling a block in FAIR mode. Organizations without a weight have a weight of 1.
+	OrgWeights map[string]uint32 `protobuf:"bytes,2,rep,name=org_weights,json=orgWeights,proto3" json:"org_weights,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	// The number of batches worth of transactions kept pending in FAIR mode, from which blocks are
+	// filled. A value of 0 indicates the default of 2.
+	PendingBatches       uint32   `protobuf:"varint,3,opt,name=pending_batches,json=pendingBatches,proto3" json:"pending_batches,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockCutting) Reset()         { *m = BlockCutting{} }
+func (m *BlockCutting) String() string { return proto.CompactTextString(m) }
+func (*BlockCutting) ProtoMessage()    {}
+func (*BlockCutting) Descriptor() ([]byte, []int) {
+	return fileDescriptor_configuration_64adb92a33b31eca, []int{5}
+}
+func (m *BlockCutting) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockCutting.Unmarshal(m, b)
+}
+func (m *BlockCutting) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockCutting.Marshal(b, m, deterministic)
+}
+func (dst *BlockCutting) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockCutting.Merge(dst, src)
+}
+func (m *BlockCutting) XXX_Size() int {
+	return xxx_messageInfo_BlockCutting.Size(m)
+}
+func (m *BlockCutting) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockCutting.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockCutting proto.InternalMessageInfo
+
+func (m *BlockCutting) GetMode() BlockCutting_Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return BlockCutting_FIFO
+}
+
+func (m *BlockCutting) GetOrgWeights() map[string]uint32 {
+	if m != nil {
+		return m.OrgWeights
+	}
+	return nil
+}
+
+func (m *BlockCutting) GetPendingBatches() uint32 {
+	if m != nil {
+		return m.PendingBatches
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*ConsensusType)(nil), "orderer.ConsensusType")
 	proto.RegisterType((*BatchSize)(nil), "orderer.BatchSize")
 	proto.RegisterType((*BatchTimeout)(nil), "orderer.BatchTimeout")
 	proto.RegisterType((*KafkaBrokers)(nil), "orderer.KafkaBrokers")
 	proto.RegisterType((*ChannelRestrictions)(nil), "orderer.ChannelRestrictions")
+	proto.RegisterType((*BlockCutting)(nil), "orderer.BlockCutting")
+	proto.RegisterMapType((map[string]uint32)(nil), "orderer.BlockCutting.OrgWeightsEntry")
 	proto.RegisterEnum("orderer.ConsensusType_MigrationState", ConsensusType_MigrationState_name, ConsensusType_MigrationState_value)
+	proto.RegisterEnum("orderer.BlockCutting_Mode", BlockCutting_Mode_name, BlockCutting_Mode_value)
 }
 
 func init() {
-	proto.RegisterFile("orderer/configuration.proto", fileDescriptor_configuration_acd4b7f3e02dac02)
-}
-
-var fileDescriptor_configuration_acd4b7f3e02dac02 = []byte{
-	// 460 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x54, 0x92, 0xdf, 0x8a, 0xda, 0x40,
-	0x14, 0xc6, 0x1b, 0x95, 0xee, 0x7a, 0x58, 0x35, 0x8e, 0x2d, 0x84, 0xee, 0x8d, 0x08, 0x0b, 0xd2,
-	0x2e, 0x11, 0xb6, 0x4f, 0x60, 0x82, 0x94, 0xa5, 0x44, 0x61, 0x9c, 0x42, 0xe9, 0x4d, 0x98, 0xc4,
-	0x63, 0x0c, 0x6b, 0x32, 0x61, 0x66, 0x02, 0xb1, 0x7d, 0x8e, 0x3e, 0x4c, 0xdf, 0xae, 0xe4, 0x9f,
-	0x7f, 0xee, 0xce, 0xf7, 0x7d, 0xbf, 0x9c, 0x99, 0x39, 0x39, 0xf0, 0x28, 0xe4, 0x0e, 0x25, 0xca,
-	0x45, 0x28, 0xd2, 0x7d, 0x1c, 0xe5, 0x92, 0xeb, 0x58, 0xa4, 0x76, 0x26, 0x85, 0x16, 0xe4, 0xae,
-	0x09, 0x67, 0xff, 0x3a, 0x30, 0x70, 0x45, 0xaa, 0x30, 0x55, 0xb9, 0x62, 0xa7, 0x0c, 0x09, 0x81,
-	0x9e, 0x3e, 0x65, 0x68, 0x19, 0x53, 0x63, 0xde, 0xa7, 0x55, 0x4d, 0x3e, 0xc1, 0x7d, 0x82, 0x9a,
-	0xef, 0xb8, 0xe6, 0x56, 0x67, 0x6a, 0xcc, 0x1f, 0xe8, 0x59, 0x93, 0x35, 0x8c, 0x92, 0x38, 0xaa,
-	0xbb, 0xfb, 0x4a, 0x73, 0x8d, 0x56, 0x77, 0x6a, 0xcc, 0x87, 0x2f, 0x4f, 0x76, 0x73, 0x88, 0x7d,
-	0x73, 0x80, 0xed, 0xb5, 0xf4, 0xb6, 0x84, 0xe9, 0x30, 0xb9, 0xd1, 0xe4, 0x0b, 0x8c, 0x2f, 0xfd,
-	0x42, 0x91, 0x6a, 0x2c, 0xb4, 0xd5, 0x9b, 0x1a, 0xf3, 0x1e, 0x35, 0xcf, 0x81, 0x5b, 0xfb, 0xb3,
-	0x3f, 0x30, 0xbc, 0x6d, 0x47, 0x08, 0x0c, 0xbd, 0xd7, 0x6f, 0xfe, 0x96, 0x2d, 0xd9, 0xca, 0x5f,
-	0x6f, 0xd6, 0x2b, 0xf3, 0x1d, 0x99, 0xc0, 0xe8, 0xe2, 0x6d, 0xd9, 0x92, 0x32, 0xd3, 0x20, 0x1f,
-	0xc0, 0xbc, 0x98, 0xee, 0xc6, 0xf3, 0x5e, 0x99, 0xd9, 0xb9, 0x45, 0x97, 0xce, 0x86, 0x32, 0xb3,
-	0x4b, 0x3e, 0xc2, 0xf8, 0x1a, 0x5d, 0xb3, 0xd5, 0x4f, 0x66, 0xf6, 0x66, 0x7f, 0x0d, 0xe8, 0x3b,
-	0x5c, 0x87, 0x87, 0x6d, 0xfc, 0x1b, 0xc9, 0x67, 0x18, 0x27, 0xbc, 0xf0, 0x13, 0x54, 0x8a, 0x47,
-	0xe8, 0x87, 0x22, 0x4f, 0x75, 0x35, 0xc4, 0x01, 0x1d, 0x25, 0xbc, 0xf0, 0x6a, 0xdf, 0x2d, 0x6d,
-	0xf2, 0x0c, 0x84, 0x07, 0x4a, 0x1c, 0x73, 0x8d, 0x7e, 0xf9, 0x51, 0x70, 0xd2, 0xa8, 0xaa, 0xc9,
-	0x0e, 0xa8, 0xd9, 0x26, 0x1e, 0x2f, 0x9c, 0xd2, 0x27, 0x36, 0x4c, 0x32, 0x89, 0x7b, 0x94, 0x12,
-	0x77, 0x57, 0x78, 0xb7, 0xc2, 0xc7, 0xe7, 0xa8, 0xe5, 0x67, 0x73, 0x78, 0xa8, 0xae, 0xc5, 0xe2,
-	0x04, 0x45, 0xae, 0x89, 0x05, 0x77, 0xba, 0x2e, 0x9b, 0x9f, 0xda, 0xca, 0x92, 0xfc, 0xce, 0xf7,
-	0x6f, 0xdc, 0x91, 0xe2, 0x0d, 0xa5, 0x2a, 0xc9, 0xa0, 0x2e, 0x2d, 0x63, 0xda, 0x2d, 0xc9, 0x46,
-	0xce, 0x5e, 0x60, 0xe2, 0x1e, 0x78, 0x9a, 0xe2, 0x91, 0xa2, 0xd2, 0x32, 0x0e, 0xcb, 0x89, 0x2b,
-	0xf2, 0x08, 0xfd, 0xf2, 0x42, 0x97, 0xc7, 0xf6, 0xe8, 0x7d, 0xc2, 0x8b, 0xea, 0x95, 0xce, 0x0f,
-	0x78, 0x12, 0x32, 0xb2, 0x0f, 0xa7, 0x0c, 0xe5, 0x11, 0x77, 0x11, 0x4a, 0x7b, 0xcf, 0x03, 0x19,
-	0x87, 0xf5, 0x12, 0xaa, 0x76, 0x3f, 0x7e, 0x3d, 0x47, 0xb1, 0x3e, 0xe4, 0x81, 0x1d, 0x8a, 0x64,
-	0x71, 0x45, 0x2f, 0x6a, 0x7a, 0x51, 0xd3, 0x8b, 0x86, 0x0e, 0xde, 0x57, 0xfa, 0xeb, 0xff, 0x00,
-	0x00, 0x00, 0xff, 0xff, 0x9f, 0x9f, 0xe4, 0x13, 0xe1, 0x02, 0x00, 0x00,
+	proto.RegisterFile("orderer/configuration.proto", fileDescriptor_configuration_64adb92a33b31eca)
+}
+
+var fileDescriptor_configuration_64adb92a33b31eca = []byte{
+	// 597 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x93, 0xdf, 0x8a, 0xda, 0x40,
+	0x14, 0xc6, 0x37, 0x9a, 0x76, 0x77, 0xcf, 0xee, 0x6a, 0x9c, 0xdd, 0x82, 0xb8, 0x37, 0x22, 0x2c,
+	0x95, 0x76, 0x89, 0x60, 0x6f, 0x4a, 0xa1, 0x17, 0x46, 0xdc, 0x22, 0x25, 0x0a, 0x63, 0x4a, 0x4b,
+	0x6f, 0xc2, 0x24, 0x39, 0xc6, 0xa0, 0xc9, 0xc8, 0x64, 0xd2, 0x6a, 0xfb, 0x06, 0xbd, 0xef, 0xc3,
+	0xf4, 0xed, 0xca, 0x24, 0xf1, 0x5f, 0xe9, 0xdd, 0xf9, 0xbe, 0xf3, 0xcb, 0x99, 0x99, 0x6f, 0x26,
+	0x70, 0xcf, 0x45, 0x80, 0x02, 0x45, 0xcf, 0xe7, 0xc9, 0x3c, 0x0a, 0x33, 0xc1, 0x64, 0xc4, 0x13,
+	0x73, 0x2d, 0xb8, 0xe4, 0xe4, 0xbc, 0x6c, 0x76, 0xfe, 0x54, 0xe0, 0x66, 0xc8, 0x93, 0x14, 0x93,
+	0x34, 0x4b, 0x9d, 0xed, 0x1a, 0x09, 0x01, 0x5d, 0x6e, 0xd7, 0xd8, 0xd4, 0xda, 0x5a, 0xf7, 0x92,
+	0xe6, 0x35, 0x69, 0xc1, 0x45, 0x8c, 0x92, 0x05, 0x4c, 0xb2, 0x66, 0xa5, 0xad, 0x75, 0xaf, 0xe9,
+	0x5e, 0x93, 0x09, 0xd4, 0xe3, 0x28, 0x2c, 0xa6, 0xbb, 0xa9, 0x64, 0x12, 0x9b, 0xd5, 0xb6, 0xd6,
+	0xad, 0xf5, 0x1f, 0xcc, 0x72, 0x11, 0xf3, 0x64, 0x01, 0xd3, 0xde, 0xd1, 0x33, 0x05, 0xd3, 0x5a,
+	0x7c, 0xa2, 0xc9, 0x6b, 0x68, 0x1c, 0xe6, 0xf9, 0x3c, 0x91, 0xb8, 0x91, 0x4d, 0xbd, 0xad, 0x75,
+	0x75, 0x6a, 0xec, 0x1b, 0xc3, 0xc2, 0xef, 0xfc, 0x84, 0xda, 0xe9, 0x38, 0x42, 0xa0, 0x66, 0x8f,
+	0x3f, 0xb8, 0x33, 0x67, 0xe0, 0x8c, 0xdc, 0xc9, 0x74, 0x32, 0x32, 0xce, 0xc8, 0x2d, 0xd4, 0x0f,
+	0xde, 0xcc, 0x19, 0x50, 0xc7, 0xd0, 0xc8, 0x1d, 0x18, 0x07, 0x73, 0x38, 0xb5, 0xed, 0xb1, 0x63,
+	0x54, 0x4e, 0xd1, 0x81, 0x35, 0xa5, 0x8e, 0x51, 0x25, 0x2f, 0xa0, 0x71, 0x8c, 0x4e, 0x9c, 0xd1,
+	0x17, 0xc7, 0xd0, 0x3b, 0xbf, 0x35, 0xb8, 0xb4, 0x98, 0xf4, 0x17, 0xb3, 0xe8, 0x07, 0x92, 0x57,
+	0xd0, 0x88, 0xd9, 0xc6, 0x8d, 0x31, 0x4d, 0x59, 0x88, 0xae, 0xcf, 0xb3, 0x44, 0xe6, 0x21, 0xde,
+	0xd0, 0x7a, 0xcc, 0x36, 0x76, 0xe1, 0x0f, 0x95, 0x4d, 0x1e, 0x81, 0x30, 0x2f, 0xe5, 0xab, 0x4c,
+	0xa2, 0xab, 0x3e, 0xf2, 0xb6, 0x12, 0xd3, 0x3c, 0xd9, 0x1b, 0x6a, 0xec, 0x3a, 0x36, 0xdb, 0x58,
+	0xca, 0x27, 0x26, 0xdc, 0xae, 0x05, 0xce, 0x51, 0x08, 0x0c, 0x8e, 0xf0, 0x6a, 0x8e, 0x37, 0xf6,
+	0xad, 0x1d, 0xdf, 0xe9, 0xc2, 0x75, 0xbe, 0x2d, 0x27, 0x8a, 0x91, 0x67, 0x92, 0x34, 0xe1, 0x5c,
+	0x16, 0x65, 0x79, 0xa9, 0x3b, 0xa9, 0xc8, 0x8f, 0x6c, 0xbe, 0x64, 0x96, 0xe0, 0x4b, 0x14, 0xa9,
+	0x22, 0xbd, 0xa2, 0x6c, 0x6a, 0xed, 0xaa, 0x22, 0x4b, 0xd9, 0xe9, 0xc3, 0xed, 0x70, 0xc1, 0x92,
+	0x04, 0x57, 0x14, 0x53, 0x29, 0x22, 0x5f, 0x25, 0x9e, 0x92, 0x7b, 0xb8, 0x54, 0x1b, 0x3a, 0x1c,
+	0x56, 0xa7, 0x17, 0x31, 0xdb, 0xe4, 0xa7, 0xec, 0xfc, 0xaa, 0xc0, 0xb5, 0xb5, 0xe2, 0xfe, 0x72,
+	0x98, 0x49, 0x19, 0x25, 0x21, 0x31, 0x41, 0x8f, 0x79, 0x50, 0x3c, 0xad, 0x5a, 0xbf, 0xb5, 0x7f,
+	0x1f, 0xc7, 0x90, 0x69, 0xf3, 0x00, 0x69, 0xce, 0x91, 0x27, 0xb8, 0xe2, 0x22, 0x74, 0xbf, 0x63,
+	0x14, 0x2e, 0xa4, 0xca, 0xa7, 0xda, 0xbd, 0xea, 0x3f, 0xfc, 0xff, 0xb3, 0xa9, 0x08, 0x3f, 0x17,
+	0xdc, 0x28, 0x91, 0x62, 0x4b, 0x81, 0xef, 0x0d, 0xf2, 0x12, 0xea, 0x6b, 0x4c, 0x82, 0x28, 0x09,
+	0x5d, 0x4f, 0x05, 0xb3, 0x0f, 0xaf, 0x56, 0xda, 0x56, 0xe1, 0xb6, 0xde, 0x43, 0xfd, 0x9f, 0x39,
+	0xc4, 0x80, 0xea, 0x12, 0xb7, 0x65, 0x70, 0xaa, 0x24, 0x77, 0xf0, 0xec, 0x1b, 0x5b, 0x65, 0x58,
+	0xde, 0x57, 0x21, 0xde, 0x55, 0xde, 0x6a, 0x9d, 0x16, 0xe8, 0x6a, 0xf7, 0xe4, 0x02, 0xf4, 0xa7,
+	0xf1, 0xd3, 0xd4, 0x38, 0xcb, 0xab, 0xc1, 0x98, 0x1a, 0x9a, 0xf5, 0x09, 0x1e, 0xb8, 0x08, 0xcd,
+	0xc5, 0x76, 0x8d, 0x62, 0x85, 0x41, 0x88, 0xc2, 0x9c, 0x33, 0x4f, 0x44, 0x7e, 0xf1, 0x47, 0xa6,
+	0xbb, 0x53, 0x7d, 0x7d, 0x0c, 0x23, 0xb9, 0xc8, 0x3c, 0xd3, 0xe7, 0x71, 0xef, 0x88, 0xee, 0x15,
+	0x74, 0xaf, 0xa0, 0x7b, 0x25, 0xed, 0x3d, 0xcf, 0xf5, 0x9b, 0xbf, 0x03, 0x00, 0x23, 0x81, 0x5b,
+	0xc9, 0xee, 0x03, 0x00, 0x00,
 }
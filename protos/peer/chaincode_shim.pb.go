@@ -101,7 +101,7 @@ func (x ChaincodeMessage_Type) String() string {
 	return proto.EnumName(ChaincodeMessage_Type_name, int32(x))
 }
 func (ChaincodeMessage_Type) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{0, 0}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{0, 0}
 }
 
 type ChaincodeMessage struct {
@@ -125,7 +125,7 @@ func (m *ChaincodeMessage) Reset()         { *m = ChaincodeMessage{} }
 func (m *ChaincodeMessage) String() string { return proto.CompactTextString(m) }
 func (*ChaincodeMessage) ProtoMessage()    {}
 func (*ChaincodeMessage) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{0}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{0}
 }
 func (m *ChaincodeMessage) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ChaincodeMessage.Unmarshal(m, b)
@@ -209,7 +209,7 @@ func (m *GetState) Reset()         { *m = GetState{} }
 func (m *GetState) String() string { return proto.CompactTextString(m) }
 func (*GetState) ProtoMessage()    {}
 func (*GetState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{1}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{1}
 }
 func (m *GetState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetState.Unmarshal(m, b)
@@ -255,7 +255,7 @@ func (m *GetStateMetadata) Reset()         { *m = GetStateMetadata{} }
 func (m *GetStateMetadata) String() string { return proto.CompactTextString(m) }
 func (*GetStateMetadata) ProtoMessage()    {}
 func (*GetStateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{2}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{2}
 }
 func (m *GetStateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetStateMetadata.Unmarshal(m, b)
@@ -306,7 +306,7 @@ func (m *PutState) Reset()         { *m = PutState{} }
 func (m *PutState) String() string { return proto.CompactTextString(m) }
 func (*PutState) ProtoMessage()    {}
 func (*PutState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{3}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{3}
 }
 func (m *PutState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutState.Unmarshal(m, b)
@@ -360,7 +360,7 @@ func (m *PutStateMetadata) Reset()         { *m = PutStateMetadata{} }
 func (m *PutStateMetadata) String() string { return proto.CompactTextString(m) }
 func (*PutStateMetadata) ProtoMessage()    {}
 func (*PutStateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{4}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{4}
 }
 func (m *PutStateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutStateMetadata.Unmarshal(m, b)
@@ -417,7 +417,7 @@ func (m *DelState) Reset()         { *m = DelState{} }
 func (m *DelState) String() string { return proto.CompactTextString(m) }
 func (*DelState) ProtoMessage()    {}
 func (*DelState) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{5}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{5}
 }
 func (m *DelState) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DelState.Unmarshal(m, b)
@@ -469,7 +469,7 @@ func (m *GetStateByRange) Reset()         { *m = GetStateByRange{} }
 func (m *GetStateByRange) String() string { return proto.CompactTextString(m) }
 func (*GetStateByRange) ProtoMessage()    {}
 func (*GetStateByRange) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{6}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{6}
 }
 func (m *GetStateByRange) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetStateByRange.Unmarshal(m, b)
@@ -534,7 +534,7 @@ func (m *GetQueryResult) Reset()         { *m = GetQueryResult{} }
 func (m *GetQueryResult) String() string { return proto.CompactTextString(m) }
 func (*GetQueryResult) ProtoMessage()    {}
 func (*GetQueryResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{7}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{7}
 }
 func (m *GetQueryResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetQueryResult.Unmarshal(m, b)
@@ -590,7 +590,7 @@ func (m *QueryMetadata) Reset()         { *m = QueryMetadata{} }
 func (m *QueryMetadata) String() string { return proto.CompactTextString(m) }
 func (*QueryMetadata) ProtoMessage()    {}
 func (*QueryMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{8}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{8}
 }
 func (m *QueryMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryMetadata.Unmarshal(m, b)
@@ -625,9 +625,13 @@ func (m *QueryMetadata) GetBookmark() string {
 }
 
 // GetHistoryForKey is the payload of a ChaincodeMessage. It contains a key
-// for which the historical values need to be retrieved.
+// for which the historical values need to be retrieved. If the collection is
+// specified, the history of the hashes of the private data is retrieved. The
+// metadata hold the byte representation of HistoryQueryMetadata.
 type GetHistoryForKey struct {
 	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Metadata             []byte   `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Collection           string   `protobuf:"bytes,3,opt,name=collection,proto3" json:"collection,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -637,7 +641,7 @@ func (m *GetHistoryForKey) Reset()         { *m = GetHistoryForKey{} }
 func (m *GetHistoryForKey) String() string { return proto.CompactTextString(m) }
 func (*GetHistoryForKey) ProtoMessage()    {}
 func (*GetHistoryForKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{9}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{9}
 }
 func (m *GetHistoryForKey) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetHistoryForKey.Unmarshal(m, b)
@@ -664,6 +668,113 @@ func (m *GetHistoryForKey) GetKey() string {
 	return ""
 }
 
+func (m *GetHistoryForKey) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *GetHistoryForKey) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+// HistoryQueryMetadata is the metadata of a GetHistoryForKey. It restricts the
+// history to the transactions of the blocks from startBlock to endBlock, and
+// with a timestamp from startTime to endTime, all bounds included. An endBlock
+// of zero and unset times do not restrict the history. The history is returned
+// from the oldest to the newest modification, unless newestFirst is set. It
+// contains a pageSize which denotes the number of records to be fetched and a
+// bookmark.
+type HistoryQueryMetadata struct {
+	StartBlock           uint64               `protobuf:"varint,1,opt,name=startBlock,proto3" json:"startBlock,omitempty"`
+	EndBlock             uint64               `protobuf:"varint,2,opt,name=endBlock,proto3" json:"endBlock,omitempty"`
+	StartTime            *timestamp.Timestamp `protobuf:"bytes,3,opt,name=startTime,proto3" json:"startTime,omitempty"`
+	EndTime              *timestamp.Timestamp `protobuf:"bytes,4,opt,name=endTime,proto3" json:"endTime,omitempty"`
+	NewestFirst          bool                 `protobuf:"varint,5,opt,name=newestFirst,proto3" json:"newestFirst,omitempty"`
+	PageSize             int32                `protobuf:"varint,6,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	Bookmark             string               `protobuf:"bytes,7,opt,name=bookmark,proto3" json:"bookmark,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *HistoryQueryMetadata) Reset()         { *m = HistoryQueryMetadata{} }
+func (m *HistoryQueryMetadata) String() string { return proto.CompactTextString(m) }
+func (*HistoryQueryMetadata) ProtoMessage()    {}
+func (*HistoryQueryMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{10}
+}
+func (m *HistoryQueryMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HistoryQueryMetadata.Unmarshal(m, b)
+}
+func (m *HistoryQueryMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HistoryQueryMetadata.Marshal(b, m, deterministic)
+}
+func (dst *HistoryQueryMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HistoryQueryMetadata.Merge(dst, src)
+}
+func (m *HistoryQueryMetadata) XXX_Size() int {
+	return xxx_messageInfo_HistoryQueryMetadata.Size(m)
+}
+func (m *HistoryQueryMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_HistoryQueryMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HistoryQueryMetadata proto.InternalMessageInfo
+
+func (m *HistoryQueryMetadata) GetStartBlock() uint64 {
+	if m != nil {
+		return m.StartBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetEndBlock() uint64 {
+	if m != nil {
+		return m.EndBlock
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetStartTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.StartTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryMetadata) GetEndTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.EndTime
+	}
+	return nil
+}
+
+func (m *HistoryQueryMetadata) GetNewestFirst() bool {
+	if m != nil {
+		return m.NewestFirst
+	}
+	return false
+}
+
+func (m *HistoryQueryMetadata) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *HistoryQueryMetadata) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
 type QueryStateNext struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -675,7 +786,7 @@ func (m *QueryStateNext) Reset()         { *m = QueryStateNext{} }
 func (m *QueryStateNext) String() string { return proto.CompactTextString(m) }
 func (*QueryStateNext) ProtoMessage()    {}
 func (*QueryStateNext) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{10}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{11}
 }
 func (m *QueryStateNext) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryStateNext.Unmarshal(m, b)
@@ -713,7 +824,7 @@ func (m *QueryStateClose) Reset()         { *m = QueryStateClose{} }
 func (m *QueryStateClose) String() string { return proto.CompactTextString(m) }
 func (*QueryStateClose) ProtoMessage()    {}
 func (*QueryStateClose) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{11}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{12}
 }
 func (m *QueryStateClose) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryStateClose.Unmarshal(m, b)
@@ -752,7 +863,7 @@ func (m *QueryResultBytes) Reset()         { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()    {}
 func (*QueryResultBytes) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{12}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{13}
 }
 func (m *QueryResultBytes) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResultBytes.Unmarshal(m, b)
@@ -798,7 +909,7 @@ func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
 func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()    {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{13}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{14}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponse.Unmarshal(m, b)
@@ -860,7 +971,7 @@ func (m *QueryResponseMetadata) Reset()         { *m = QueryResponseMetadata{} }
 func (m *QueryResponseMetadata) String() string { return proto.CompactTextString(m) }
 func (*QueryResponseMetadata) ProtoMessage()    {}
 func (*QueryResponseMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{14}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{15}
 }
 func (m *QueryResponseMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponseMetadata.Unmarshal(m, b)
@@ -906,7 +1017,7 @@ func (m *StateMetadata) Reset()         { *m = StateMetadata{} }
 func (m *StateMetadata) String() string { return proto.CompactTextString(m) }
 func (*StateMetadata) ProtoMessage()    {}
 func (*StateMetadata) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{15}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{16}
 }
 func (m *StateMetadata) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateMetadata.Unmarshal(m, b)
@@ -951,7 +1062,7 @@ func (m *StateMetadataResult) Reset()         { *m = StateMetadataResult{} }
 func (m *StateMetadataResult) String() string { return proto.CompactTextString(m) }
 func (*StateMetadataResult) ProtoMessage()    {}
 func (*StateMetadataResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_chaincode_shim_3c6cdf1ba834f673, []int{16}
+	return fileDescriptor_chaincode_shim_54b19da8bfd3bf5c, []int{17}
 }
 func (m *StateMetadataResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StateMetadataResult.Unmarshal(m, b)
@@ -989,6 +1100,7 @@ func init() {
 	proto.RegisterType((*GetQueryResult)(nil), "protos.GetQueryResult")
 	proto.RegisterType((*QueryMetadata)(nil), "protos.QueryMetadata")
 	proto.RegisterType((*GetHistoryForKey)(nil), "protos.GetHistoryForKey")
+	proto.RegisterType((*HistoryQueryMetadata)(nil), "protos.HistoryQueryMetadata")
 	proto.RegisterType((*QueryStateNext)(nil), "protos.QueryStateNext")
 	proto.RegisterType((*QueryStateClose)(nil), "protos.QueryStateClose")
 	proto.RegisterType((*QueryResultBytes)(nil), "protos.QueryResultBytes")
@@ -1103,74 +1215,176 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 	Metadata: "peer/chaincode_shim.proto",
 }
 
+// ChaincodeClient is the client API for Chaincode service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ChaincodeClient interface {
+	Connect(ctx context.Context, opts ...grpc.CallOption) (Chaincode_ConnectClient, error)
+}
+
+type chaincodeClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewChaincodeClient(cc *grpc.ClientConn) ChaincodeClient {
+	return &chaincodeClient{cc}
+}
+
+func (c *chaincodeClient) Connect(ctx context.Context, opts ...grpc.CallOption) (Chaincode_ConnectClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Chaincode_serviceDesc.Streams[0], "/protos.Chaincode/Connect", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chaincodeConnectClient{stream}
+	return x, nil
+}
+
+type Chaincode_ConnectClient interface {
+	Send(*ChaincodeMessage) error
+	Recv() (*ChaincodeMessage, error)
+	grpc.ClientStream
+}
+
+type chaincodeConnectClient struct {
+	grpc.ClientStream
+}
+
+func (x *chaincodeConnectClient) Send(m *ChaincodeMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *chaincodeConnectClient) Recv() (*ChaincodeMessage, error) {
+	m := new(ChaincodeMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ChaincodeServer is the server API for Chaincode service.
+type ChaincodeServer interface {
+	Connect(Chaincode_ConnectServer) error
+}
+
+func RegisterChaincodeServer(s *grpc.Server, srv ChaincodeServer) {
+	s.RegisterService(&_Chaincode_serviceDesc, srv)
+}
+
+func _Chaincode_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChaincodeServer).Connect(&chaincodeConnectServer{stream})
+}
+
+type Chaincode_ConnectServer interface {
+	Send(*ChaincodeMessage) error
+	Recv() (*ChaincodeMessage, error)
+	grpc.ServerStream
+}
+
+type chaincodeConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *chaincodeConnectServer) Send(m *ChaincodeMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *chaincodeConnectServer) Recv() (*ChaincodeMessage, error) {
+	m := new(ChaincodeMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Chaincode_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protos.Chaincode",
+	HandlerType: (*ChaincodeServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _Chaincode_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "peer/chaincode_shim.proto",
+}
+
 func init() {
-	proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor_chaincode_shim_3c6cdf1ba834f673)
-}
-
-var fileDescriptor_chaincode_shim_3c6cdf1ba834f673 = []byte{
-	// 1010 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcf, 0x73, 0xda, 0x46,
-	0x14, 0x0e, 0x06, 0x8c, 0x78, 0xd8, 0x78, 0xb3, 0x8e, 0x5d, 0xc2, 0x4c, 0x5a, 0xca, 0xf4, 0x40,
-	0x2f, 0xd0, 0xd0, 0x1e, 0x7a, 0xe8, 0x4c, 0x06, 0xc3, 0x1a, 0x33, 0xb6, 0x05, 0x59, 0xc9, 0x99,
-	0xb8, 0x17, 0x8d, 0x90, 0xd6, 0x42, 0x63, 0xa1, 0x55, 0xa5, 0x25, 0x0d, 0xbd, 0xf5, 0xda, 0x7f,
-	0xa9, 0x7f, 0x58, 0xaf, 0x9d, 0xd5, 0x2f, 0x03, 0xae, 0x93, 0x4e, 0x4e, 0xe8, 0x7b, 0xef, 0xdb,
-	0xef, 0xfd, 0xda, 0x87, 0x04, 0x2f, 0x03, 0xc6, 0xc2, 0x9e, 0xb5, 0x30, 0x5d, 0xdf, 0xe2, 0x36,
-	0x33, 0xa2, 0x85, 0xbb, 0xec, 0x06, 0x21, 0x17, 0x1c, 0xef, 0xc7, 0x3f, 0x51, 0xb3, 0xb9, 0x43,
-	0x61, 0x1f, 0x98, 0x2f, 0x12, 0x4e, 0xf3, 0x38, 0xf6, 0x05, 0x21, 0x0f, 0x78, 0x64, 0x7a, 0xa9,
-	0xf1, 0x1b, 0x87, 0x73, 0xc7, 0x63, 0xbd, 0x18, 0xcd, 0x57, 0x77, 0x3d, 0xe1, 0x2e, 0x59, 0x24,
-	0xcc, 0x65, 0x90, 0x10, 0xda, 0x7f, 0x97, 0x01, 0x0d, 0x33, 0xbd, 0x6b, 0x16, 0x45, 0xa6, 0xc3,
-	0xf0, 0x6b, 0x28, 0x89, 0x75, 0xc0, 0x1a, 0x85, 0x56, 0xa1, 0x53, 0xef, 0xbf, 0x4a, 0xa8, 0x51,
-	0x77, 0x97, 0xd7, 0xd5, 0xd7, 0x01, 0xa3, 0x31, 0x15, 0xff, 0x0c, 0xd5, 0x5c, 0xba, 0xb1, 0xd7,
-	0x2a, 0x74, 0x6a, 0xfd, 0x66, 0x37, 0x09, 0xde, 0xcd, 0x82, 0x77, 0xf5, 0x8c, 0x41, 0x1f, 0xc8,
-	0xb8, 0x01, 0x95, 0xc0, 0x5c, 0x7b, 0xdc, 0xb4, 0x1b, 0xc5, 0x56, 0xa1, 0x73, 0x40, 0x33, 0x88,
-	0x31, 0x94, 0xc4, 0x47, 0xd7, 0x6e, 0x94, 0x5a, 0x85, 0x4e, 0x95, 0xc6, 0xcf, 0xb8, 0x0f, 0x4a,
-	0x56, 0x62, 0xa3, 0x1c, 0x87, 0x39, 0xcd, 0xd2, 0xd3, 0x5c, 0xc7, 0x67, 0xf6, 0x2c, 0xf5, 0xd2,
-	0x9c, 0x87, 0xdf, 0xc0, 0xd1, 0x4e, 0xcb, 0x1a, 0xfb, 0xdb, 0x47, 0xf3, 0xca, 0x88, 0xf4, 0xd2,
-	0xba, 0xb5, 0x85, 0xf1, 0x2b, 0x00, 0x6b, 0x61, 0xfa, 0x3e, 0xf3, 0x0c, 0xd7, 0x6e, 0x54, 0xe2,
-	0x74, 0xaa, 0xa9, 0x65, 0x62, 0xb7, 0xff, 0xd9, 0x83, 0x92, 0x6c, 0x05, 0x3e, 0x84, 0xea, 0x8d,
-	0x3a, 0x22, 0xe7, 0x13, 0x95, 0x8c, 0xd0, 0x33, 0x7c, 0x00, 0x0a, 0x25, 0xe3, 0x89, 0xa6, 0x13,
-	0x8a, 0x0a, 0xb8, 0x0e, 0x90, 0x21, 0x32, 0x42, 0x7b, 0x58, 0x81, 0xd2, 0x44, 0x9d, 0xe8, 0xa8,
-	0x88, 0xab, 0x50, 0xa6, 0x64, 0x30, 0xba, 0x45, 0x25, 0x7c, 0x04, 0x35, 0x9d, 0x0e, 0x54, 0x6d,
-	0x30, 0xd4, 0x27, 0x53, 0x15, 0x95, 0xa5, 0xe4, 0x70, 0x7a, 0x3d, 0xbb, 0x22, 0x3a, 0x19, 0xa1,
-	0x7d, 0x49, 0x25, 0x94, 0x4e, 0x29, 0xaa, 0x48, 0xcf, 0x98, 0xe8, 0x86, 0xa6, 0x0f, 0x74, 0x82,
-	0x14, 0x09, 0x67, 0x37, 0x19, 0xac, 0x4a, 0x38, 0x22, 0x57, 0x29, 0x04, 0xfc, 0x02, 0xd0, 0x44,
-	0x7d, 0x37, 0xbd, 0x24, 0xc6, 0xf0, 0x62, 0x30, 0x51, 0x87, 0xd3, 0x11, 0x41, 0xb5, 0x24, 0x41,
-	0x6d, 0x36, 0x55, 0x35, 0x82, 0x0e, 0xf1, 0x29, 0xe0, 0x5c, 0xd0, 0x38, 0xbb, 0x35, 0xe8, 0x40,
-	0x1d, 0x13, 0x54, 0x97, 0x67, 0xa5, 0xfd, 0xed, 0x0d, 0xa1, 0xb7, 0x06, 0x25, 0xda, 0xcd, 0x95,
-	0x8e, 0x8e, 0xa4, 0x35, 0xb1, 0x24, 0x7c, 0x95, 0xbc, 0xd7, 0x11, 0xc2, 0x27, 0xf0, 0x7c, 0xd3,
-	0x3a, 0xbc, 0x9a, 0x6a, 0x04, 0x3d, 0x97, 0xd9, 0x5c, 0x12, 0x32, 0x1b, 0x5c, 0x4d, 0xde, 0x11,
-	0x84, 0xf1, 0x57, 0x70, 0x2c, 0x15, 0x2f, 0x26, 0x9a, 0x3e, 0xa5, 0xb7, 0xc6, 0xf9, 0x94, 0x1a,
-	0x97, 0xe4, 0x16, 0x1d, 0x6f, 0xa7, 0x70, 0x4d, 0xf4, 0xc1, 0x68, 0xa0, 0x0f, 0xd0, 0x0b, 0x69,
-	0xcf, 0x8b, 0x7b, 0xb0, 0x9f, 0xb4, 0x7f, 0x01, 0x65, 0xcc, 0x84, 0x26, 0x4c, 0xc1, 0x30, 0x82,
-	0xe2, 0x3d, 0x5b, 0xc7, 0x77, 0xb6, 0x4a, 0xe5, 0x23, 0xfe, 0x1a, 0xc0, 0xe2, 0x9e, 0xc7, 0x2c,
-	0xe1, 0x72, 0x3f, 0xbe, 0x94, 0x55, 0xba, 0x61, 0x69, 0x8f, 0x00, 0x65, 0xa7, 0xaf, 0x99, 0x30,
-	0x6d, 0x53, 0x98, 0x5f, 0xa0, 0x42, 0x41, 0x99, 0xad, 0x9e, 0xcc, 0xe1, 0x05, 0x94, 0x3f, 0x98,
-	0xde, 0x8a, 0xc5, 0x07, 0x0f, 0x68, 0x02, 0x76, 0x34, 0x8b, 0x8f, 0x34, 0x7f, 0x07, 0x94, 0x69,
-	0xfe, 0xef, 0xcc, 0x1e, 0xa9, 0xe0, 0xd7, 0xa0, 0x2c, 0xd3, 0xd3, 0xf1, 0x0e, 0xd5, 0xfa, 0x27,
-	0xf9, 0xae, 0x6c, 0x4a, 0xd3, 0x9c, 0x26, 0x1b, 0x3a, 0x62, 0xde, 0x97, 0x36, 0xf4, 0xcf, 0x02,
-	0x1c, 0x65, 0x1d, 0x3d, 0x5b, 0x53, 0xd3, 0x77, 0x18, 0x6e, 0x82, 0x12, 0x09, 0x33, 0x14, 0x97,
-	0xb9, 0x54, 0x8e, 0xf1, 0x29, 0xec, 0x33, 0xdf, 0x96, 0x9e, 0x44, 0x2b, 0x45, 0x9f, 0x2d, 0xac,
-	0xb9, 0x53, 0xd8, 0xc1, 0x46, 0x05, 0x73, 0xa8, 0x8f, 0x99, 0x78, 0xbb, 0x62, 0xe1, 0x9a, 0xb2,
-	0x68, 0xe5, 0x09, 0x39, 0x82, 0xdf, 0x24, 0x4c, 0xc3, 0x27, 0xe0, 0x73, 0xb5, 0x6c, 0xc5, 0x28,
-	0xee, 0xc4, 0x18, 0xc3, 0x61, 0x1c, 0x20, 0x9f, 0x4d, 0x13, 0x94, 0xc0, 0x74, 0x98, 0xe6, 0xfe,
-	0x91, 0xfc, 0x69, 0x96, 0x69, 0x8e, 0xa5, 0x6f, 0xce, 0xf9, 0xfd, 0xd2, 0x0c, 0xef, 0xd3, 0x30,
-	0x39, 0x6e, 0x7f, 0x17, 0xdf, 0xc0, 0x0b, 0x37, 0x12, 0x3c, 0x5c, 0x9f, 0xf3, 0x50, 0x16, 0xff,
-	0xa8, 0xed, 0xed, 0x16, 0xd4, 0xe3, 0x70, 0x71, 0x5f, 0x55, 0xf6, 0x51, 0xe0, 0x3a, 0xec, 0xb9,
-	0x76, 0x4a, 0xd9, 0x73, 0xed, 0xf6, 0xb7, 0x70, 0xf4, 0xc0, 0x18, 0x7a, 0x3c, 0x62, 0x8f, 0x28,
-	0x3f, 0x01, 0xda, 0x68, 0xca, 0xd9, 0x5a, 0xb0, 0x08, 0xb7, 0xa0, 0x16, 0x3e, 0xc0, 0x98, 0x7c,
-	0x40, 0x37, 0x4d, 0xed, 0xbf, 0x0a, 0x69, 0xa9, 0x94, 0x45, 0x01, 0xf7, 0x23, 0x86, 0xfb, 0x50,
-	0x49, 0x08, 0x92, 0x5f, 0xec, 0xd4, 0xfa, 0x8d, 0xec, 0x4e, 0xed, 0xca, 0xd3, 0x8c, 0x88, 0x5f,
-	0x82, 0xb2, 0x30, 0x23, 0x63, 0xc9, 0xc3, 0x64, 0x0f, 0x14, 0x5a, 0x59, 0x98, 0xd1, 0x35, 0x0f,
-	0xb3, 0x34, 0x8b, 0x59, 0x9a, 0x9f, 0x1c, 0xad, 0x03, 0x27, 0x5b, 0xb9, 0xe4, 0xed, 0xef, 0xc3,
-	0xc9, 0x1d, 0x13, 0xd6, 0x82, 0xd9, 0x46, 0xc8, 0x2c, 0x1e, 0xda, 0x91, 0x61, 0xf1, 0x95, 0x2f,
-	0xd2, 0x59, 0x1c, 0xa7, 0x4e, 0x9a, 0xf8, 0x86, 0xd2, 0xf5, 0xc9, 0xb1, 0xbc, 0x81, 0xc3, 0xed,
-	0xdd, 0x6b, 0x40, 0x45, 0x66, 0xf1, 0x30, 0x97, 0x0c, 0xfe, 0xf7, 0x7e, 0xb7, 0xcf, 0xe1, 0x78,
-	0x7b, 0xc3, 0x92, 0x9b, 0xd8, 0x83, 0x0a, 0xf3, 0x45, 0xe8, 0xb2, 0xac, 0x77, 0x4f, 0xec, 0x63,
-	0xc6, 0xea, 0xbf, 0xdf, 0x78, 0x39, 0x6b, 0xab, 0x20, 0xe0, 0xa1, 0xc0, 0x23, 0x50, 0x28, 0x73,
-	0xdc, 0x48, 0xb0, 0x10, 0x37, 0x9e, 0x7a, 0x35, 0x37, 0x9f, 0xf4, 0xb4, 0x9f, 0x75, 0x0a, 0x3f,
-	0x14, 0xce, 0xa6, 0xd0, 0xe6, 0xa1, 0xd3, 0x5d, 0xac, 0x03, 0x16, 0x7a, 0xcc, 0x76, 0x58, 0xd8,
-	0xbd, 0x33, 0xe7, 0xa1, 0x6b, 0x65, 0xe7, 0xe4, 0xd7, 0xc4, 0xaf, 0xdf, 0x3b, 0xae, 0x58, 0xac,
-	0xe6, 0x5d, 0x8b, 0x2f, 0x7b, 0x1b, 0xd4, 0x5e, 0x42, 0x4d, 0xbe, 0x2a, 0xa2, 0x9e, 0xa4, 0xce,
-	0x93, 0x4f, 0x94, 0x1f, 0xff, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x41, 0xbd, 0x1a, 0x01, 0xc6, 0x08,
-	0x00, 0x00,
+	proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor_chaincode_shim_54b19da8bfd3bf5c)
+}
+
+var fileDescriptor_chaincode_shim_54b19da8bfd3bf5c = []byte{
+	// 1115 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcd, 0x72, 0x1a, 0x47,
+	0x10, 0x36, 0x7f, 0x62, 0x69, 0x24, 0x34, 0x1e, 0x49, 0xce, 0x9a, 0x2a, 0x3b, 0x84, 0x13, 0xb9,
+	0x40, 0x4c, 0x7c, 0xc8, 0x21, 0x55, 0x2e, 0x04, 0x23, 0x4c, 0x49, 0x02, 0x3c, 0xbb, 0x72, 0x59,
+	0xb9, 0x6c, 0x96, 0xdd, 0x31, 0x6c, 0x69, 0xd9, 0xd9, 0xec, 0x0e, 0xb6, 0xc9, 0x2d, 0xd7, 0x3c,
+	0x43, 0xde, 0x24, 0x0f, 0x96, 0x6b, 0x6a, 0xf6, 0x4f, 0x80, 0x22, 0xab, 0xa2, 0x13, 0x7c, 0xdd,
+	0x5f, 0x77, 0x7f, 0xd3, 0xd3, 0xdb, 0x35, 0xf0, 0xdc, 0x67, 0x2c, 0xe8, 0x58, 0x0b, 0xd3, 0xf1,
+	0x2c, 0x6e, 0x33, 0x23, 0x5c, 0x38, 0xcb, 0xb6, 0x1f, 0x70, 0xc1, 0xf1, 0x5e, 0xf4, 0x13, 0xd6,
+	0xeb, 0x3b, 0x14, 0xf6, 0x89, 0x79, 0x22, 0xe6, 0xd4, 0x8f, 0x22, 0x9f, 0x1f, 0x70, 0x9f, 0x87,
+	0xa6, 0x9b, 0x18, 0xbf, 0x9d, 0x73, 0x3e, 0x77, 0x59, 0x27, 0x42, 0xb3, 0xd5, 0xc7, 0x8e, 0x70,
+	0x96, 0x2c, 0x14, 0xe6, 0xd2, 0x8f, 0x09, 0xcd, 0xbf, 0x4b, 0x80, 0xfa, 0x69, 0xbe, 0x4b, 0x16,
+	0x86, 0xe6, 0x9c, 0xe1, 0x57, 0x50, 0x14, 0x6b, 0x9f, 0xa9, 0xb9, 0x46, 0xae, 0x55, 0xeb, 0xbe,
+	0x88, 0xa9, 0x61, 0x7b, 0x97, 0xd7, 0xd6, 0xd7, 0x3e, 0xa3, 0x11, 0x15, 0xff, 0x04, 0x95, 0x2c,
+	0xb5, 0x9a, 0x6f, 0xe4, 0x5a, 0xd5, 0x6e, 0xbd, 0x1d, 0x17, 0x6f, 0xa7, 0xc5, 0xdb, 0x7a, 0xca,
+	0xa0, 0xb7, 0x64, 0xac, 0x42, 0xd9, 0x37, 0xd7, 0x2e, 0x37, 0x6d, 0xb5, 0xd0, 0xc8, 0xb5, 0xf6,
+	0x69, 0x0a, 0x31, 0x86, 0xa2, 0xf8, 0xe2, 0xd8, 0x6a, 0xb1, 0x91, 0x6b, 0x55, 0x68, 0xf4, 0x1f,
+	0x77, 0x41, 0x49, 0x8f, 0xa8, 0x96, 0xa2, 0x32, 0xcf, 0x52, 0x79, 0x9a, 0x33, 0xf7, 0x98, 0x3d,
+	0x4d, 0xbc, 0x34, 0xe3, 0xe1, 0x37, 0x70, 0xb8, 0xd3, 0x32, 0x75, 0x6f, 0x3b, 0x34, 0x3b, 0x19,
+	0x91, 0x5e, 0x5a, 0xb3, 0xb6, 0x30, 0x7e, 0x01, 0x60, 0x2d, 0x4c, 0xcf, 0x63, 0xae, 0xe1, 0xd8,
+	0x6a, 0x39, 0x92, 0x53, 0x49, 0x2c, 0x23, 0xbb, 0xf9, 0x4f, 0x1e, 0x8a, 0xb2, 0x15, 0xf8, 0x00,
+	0x2a, 0x57, 0xe3, 0x01, 0x39, 0x1b, 0x8d, 0xc9, 0x00, 0x3d, 0xc1, 0xfb, 0xa0, 0x50, 0x32, 0x1c,
+	0x69, 0x3a, 0xa1, 0x28, 0x87, 0x6b, 0x00, 0x29, 0x22, 0x03, 0x94, 0xc7, 0x0a, 0x14, 0x47, 0xe3,
+	0x91, 0x8e, 0x0a, 0xb8, 0x02, 0x25, 0x4a, 0x7a, 0x83, 0x6b, 0x54, 0xc4, 0x87, 0x50, 0xd5, 0x69,
+	0x6f, 0xac, 0xf5, 0xfa, 0xfa, 0x68, 0x32, 0x46, 0x25, 0x99, 0xb2, 0x3f, 0xb9, 0x9c, 0x5e, 0x10,
+	0x9d, 0x0c, 0xd0, 0x9e, 0xa4, 0x12, 0x4a, 0x27, 0x14, 0x95, 0xa5, 0x67, 0x48, 0x74, 0x43, 0xd3,
+	0x7b, 0x3a, 0x41, 0x8a, 0x84, 0xd3, 0xab, 0x14, 0x56, 0x24, 0x1c, 0x90, 0x8b, 0x04, 0x02, 0x3e,
+	0x06, 0x34, 0x1a, 0xbf, 0x9f, 0x9c, 0x13, 0xa3, 0xff, 0xb6, 0x37, 0x1a, 0xf7, 0x27, 0x03, 0x82,
+	0xaa, 0xb1, 0x40, 0x6d, 0x3a, 0x19, 0x6b, 0x04, 0x1d, 0xe0, 0x67, 0x80, 0xb3, 0x84, 0xc6, 0xe9,
+	0xb5, 0x41, 0x7b, 0xe3, 0x21, 0x41, 0x35, 0x19, 0x2b, 0xed, 0xef, 0xae, 0x08, 0xbd, 0x36, 0x28,
+	0xd1, 0xae, 0x2e, 0x74, 0x74, 0x28, 0xad, 0xb1, 0x25, 0xe6, 0x8f, 0xc9, 0x07, 0x1d, 0x21, 0x7c,
+	0x02, 0x4f, 0x37, 0xad, 0xfd, 0x8b, 0x89, 0x46, 0xd0, 0x53, 0xa9, 0xe6, 0x9c, 0x90, 0x69, 0xef,
+	0x62, 0xf4, 0x9e, 0x20, 0x8c, 0xbf, 0x81, 0x23, 0x99, 0xf1, 0xed, 0x48, 0xd3, 0x27, 0xf4, 0xda,
+	0x38, 0x9b, 0x50, 0xe3, 0x9c, 0x5c, 0xa3, 0xa3, 0x6d, 0x09, 0x97, 0x44, 0xef, 0x0d, 0x7a, 0x7a,
+	0x0f, 0x1d, 0x4b, 0xfb, 0xf4, 0xea, 0x8e, 0xfd, 0xa4, 0xf9, 0x33, 0x28, 0x43, 0x26, 0x34, 0x61,
+	0x0a, 0x86, 0x11, 0x14, 0x6e, 0xd8, 0x3a, 0x9a, 0xd9, 0x0a, 0x95, 0x7f, 0xf1, 0x4b, 0x00, 0x8b,
+	0xbb, 0x2e, 0xb3, 0x84, 0xc3, 0xbd, 0x68, 0x28, 0x2b, 0x74, 0xc3, 0xd2, 0x1c, 0x00, 0x4a, 0xa3,
+	0x2f, 0x99, 0x30, 0x6d, 0x53, 0x98, 0x8f, 0xc8, 0x42, 0x41, 0x99, 0xae, 0xee, 0xd5, 0x70, 0x0c,
+	0xa5, 0x4f, 0xa6, 0xbb, 0x62, 0x51, 0xe0, 0x3e, 0x8d, 0xc1, 0x4e, 0xce, 0xc2, 0x9d, 0x9c, 0x9f,
+	0x01, 0x4d, 0x57, 0xff, 0x53, 0xd9, 0x9d, 0x2c, 0xf8, 0x15, 0x28, 0xcb, 0x24, 0x3a, 0xfa, 0x86,
+	0xaa, 0xdd, 0x93, 0xec, 0x5b, 0xd9, 0x4c, 0x4d, 0x33, 0x9a, 0x6c, 0xe8, 0x80, 0xb9, 0x8f, 0x6d,
+	0xe8, 0x1f, 0x39, 0x38, 0x4c, 0x3b, 0x7a, 0xba, 0xa6, 0xa6, 0x37, 0x67, 0xb8, 0x0e, 0x4a, 0x28,
+	0xcc, 0x40, 0x9c, 0x67, 0xa9, 0x32, 0x8c, 0x9f, 0xc1, 0x1e, 0xf3, 0x6c, 0xe9, 0x89, 0x73, 0x25,
+	0xe8, 0xc1, 0x83, 0xd5, 0x77, 0x0e, 0xb6, 0xbf, 0x71, 0x82, 0x19, 0xd4, 0x86, 0x4c, 0xbc, 0x5b,
+	0xb1, 0x60, 0x4d, 0x59, 0xb8, 0x72, 0x85, 0xbc, 0x82, 0xdf, 0x24, 0x4c, 0xca, 0xc7, 0xe0, 0xa1,
+	0xb3, 0x6c, 0xd5, 0x28, 0xec, 0xd4, 0x18, 0xc2, 0x41, 0x54, 0x20, 0xbb, 0x9b, 0x3a, 0x28, 0xbe,
+	0x39, 0x67, 0x9a, 0xf3, 0x7b, 0xbc, 0x34, 0x4b, 0x34, 0xc3, 0xd2, 0x37, 0xe3, 0xfc, 0x66, 0x69,
+	0x06, 0x37, 0x49, 0x99, 0x0c, 0x37, 0x7f, 0x8d, 0x26, 0xf0, 0xad, 0x13, 0x0a, 0x1e, 0xac, 0xcf,
+	0x78, 0x20, 0x0f, 0x7f, 0xb7, 0xed, 0x9b, 0x52, 0xf2, 0xdb, 0x52, 0x1e, 0x9c, 0xa4, 0xbf, 0xf2,
+	0x70, 0x9c, 0xe4, 0xdf, 0x96, 0xfc, 0x12, 0x20, 0xba, 0x87, 0x53, 0x97, 0x5b, 0x37, 0x51, 0xb5,
+	0x22, 0xdd, 0xb0, 0xc8, 0xa2, 0xcc, 0xb3, 0x63, 0x6f, 0x3e, 0xf2, 0x66, 0x58, 0x2e, 0xfb, 0x88,
+	0x29, 0xf7, 0xb9, 0x5a, 0x78, 0x78, 0xd9, 0x67, 0x64, 0xfc, 0x1a, 0xca, 0xcc, 0xb3, 0xa3, 0xb8,
+	0xe2, 0x83, 0x71, 0x29, 0x15, 0x37, 0xa0, 0xea, 0xb1, 0xcf, 0x2c, 0x14, 0x67, 0x4e, 0x10, 0x8a,
+	0x68, 0xef, 0x2b, 0x74, 0xd3, 0xb4, 0x75, 0x01, 0x7b, 0x5f, 0xb9, 0x80, 0xf2, 0xce, 0x05, 0x34,
+	0xa0, 0x16, 0xb5, 0x25, 0x1a, 0xd9, 0x31, 0xfb, 0x22, 0x70, 0x0d, 0xf2, 0x8e, 0x9d, 0x74, 0x3f,
+	0xef, 0xd8, 0xcd, 0xef, 0xe0, 0xf0, 0x96, 0xd1, 0x77, 0x79, 0xc8, 0xee, 0x50, 0x5e, 0x03, 0xda,
+	0x98, 0xb7, 0xd3, 0xb5, 0x60, 0xa1, 0x94, 0x1c, 0xdc, 0xc2, 0x88, 0xbc, 0x4f, 0x37, 0x4d, 0xcd,
+	0x3f, 0x73, 0xc9, 0x14, 0x51, 0x16, 0xfa, 0xdc, 0x0b, 0x19, 0xee, 0x42, 0x39, 0x26, 0x48, 0x7e,
+	0xa1, 0x55, 0xed, 0xaa, 0xe9, 0xe7, 0xba, 0x9b, 0x9e, 0xa6, 0x44, 0xfc, 0x1c, 0x94, 0x85, 0x19,
+	0x1a, 0x4b, 0x1e, 0xc4, 0x2b, 0x46, 0xa1, 0xe5, 0x85, 0x19, 0x5e, 0xf2, 0x20, 0x95, 0x59, 0x48,
+	0x65, 0x7e, 0xf5, 0xab, 0x99, 0xc3, 0xc9, 0x96, 0x96, 0x6c, 0x4c, 0xba, 0x70, 0xf2, 0x91, 0x09,
+	0x6b, 0xc1, 0x6c, 0x23, 0x60, 0x16, 0x0f, 0xec, 0xd0, 0xb0, 0xf8, 0xca, 0x13, 0xc9, 0x98, 0x1f,
+	0x25, 0x4e, 0x1a, 0xfb, 0xfa, 0xd2, 0xf5, 0xd5, 0x89, 0x7f, 0x03, 0x07, 0xdb, 0x6b, 0x4d, 0x85,
+	0xb2, 0x54, 0x71, 0x3b, 0xf2, 0x29, 0xfc, 0xef, 0xd5, 0xd9, 0x3c, 0x83, 0xa3, 0xed, 0xe5, 0x15,
+	0x7f, 0xe4, 0x1d, 0x39, 0x58, 0x22, 0x70, 0x58, 0xda, 0xbb, 0x7b, 0x56, 0x5d, 0xca, 0xea, 0x7e,
+	0xd8, 0x78, 0xf7, 0x68, 0x2b, 0xdf, 0xe7, 0x81, 0xc0, 0x03, 0x50, 0x28, 0x9b, 0x3b, 0xa1, 0x60,
+	0x01, 0x56, 0xef, 0x7b, 0xf5, 0xd4, 0xef, 0xf5, 0x34, 0x9f, 0xb4, 0x72, 0x3f, 0xe4, 0xba, 0x53,
+	0xa8, 0x64, 0x1e, 0xdc, 0x87, 0x72, 0x9f, 0x7b, 0x1e, 0xb3, 0xc4, 0xe3, 0x33, 0x9e, 0x4e, 0xa0,
+	0xc9, 0x83, 0x79, 0x7b, 0xb1, 0xf6, 0x59, 0xe0, 0x32, 0x7b, 0xce, 0x82, 0xf6, 0x47, 0x73, 0x16,
+	0x38, 0x56, 0x1a, 0x27, 0x9f, 0x7e, 0xbf, 0x7c, 0x3f, 0x77, 0xc4, 0x62, 0x35, 0x6b, 0x5b, 0x7c,
+	0xd9, 0xd9, 0xa0, 0x76, 0x62, 0x6a, 0xfc, 0x04, 0x0c, 0x3b, 0x92, 0x3a, 0x8b, 0xdf, 0x93, 0x3f,
+	0xfe, 0x3b, 0x00, 0x7b, 0x5a, 0xbf, 0xee, 0x73, 0x0a, 0x00, 0x00,
 }
@@ -6,6 +6,7 @@ package lifecycle // import "github.com/hyperledger/fabric/protos/peer/lifecycle
 import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
+import common "github.com/hyperledger/fabric/protos/common"
 
 // Reference imports to suppress errors if they are not otherwise used.
 var _ = proto.Marshal
@@ -33,7 +34,7 @@ func (m *InstallChaincodeArgs) Reset()         { *m = InstallChaincodeArgs{} }
 func (m *InstallChaincodeArgs) String() string { return proto.CompactTextString(m) }
 func (*InstallChaincodeArgs) ProtoMessage()    {}
 func (*InstallChaincodeArgs) Descriptor() ([]byte, []int) {
-	return fileDescriptor_lifecycle_f98901bea638af10, []int{0}
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{0}
 }
 func (m *InstallChaincodeArgs) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallChaincodeArgs.Unmarshal(m, b)
@@ -87,7 +88,7 @@ func (m *InstallChaincodeResult) Reset()         { *m = InstallChaincodeResult{}
 func (m *InstallChaincodeResult) String() string { return proto.CompactTextString(m) }
 func (*InstallChaincodeResult) ProtoMessage()    {}
 func (*InstallChaincodeResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_lifecycle_f98901bea638af10, []int{1}
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{1}
 }
 func (m *InstallChaincodeResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_InstallChaincodeResult.Unmarshal(m, b)
@@ -128,7 +129,7 @@ func (m *QueryInstalledChaincodeArgs) Reset()         { *m = QueryInstalledChain
 func (m *QueryInstalledChaincodeArgs) String() string { return proto.CompactTextString(m) }
 func (*QueryInstalledChaincodeArgs) ProtoMessage()    {}
 func (*QueryInstalledChaincodeArgs) Descriptor() ([]byte, []int) {
-	return fileDescriptor_lifecycle_f98901bea638af10, []int{2}
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{2}
 }
 func (m *QueryInstalledChaincodeArgs) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryInstalledChaincodeArgs.Unmarshal(m, b)
@@ -175,7 +176,7 @@ func (m *QueryInstalledChaincodeResult) Reset()         { *m = QueryInstalledCha
 func (m *QueryInstalledChaincodeResult) String() string { return proto.CompactTextString(m) }
 func (*QueryInstalledChaincodeResult) ProtoMessage()    {}
 func (*QueryInstalledChaincodeResult) Descriptor() ([]byte, []int) {
-	return fileDescriptor_lifecycle_f98901bea638af10, []int{3}
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{3}
 }
 func (m *QueryInstalledChaincodeResult) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryInstalledChaincodeResult.Unmarshal(m, b)
@@ -202,33 +203,557 @@ func (m *QueryInstalledChaincodeResult) GetHash() []byte {
 	return nil
 }
 
+// ApproveChaincodeDefinitionForMyOrgArgs is the message used as arguments to
+// '+lifecycle.ApproveChaincodeDefinitionForMyOrg'
+type ApproveChaincodeDefinitionForMyOrgArgs struct {
+	Sequence             int64                           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Name                 string                          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version              string                          `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	EndorsementPolicy    []byte                          `protobuf:"bytes,6,opt,name=endorsement_policy,json=endorsementPolicy,proto3" json:"endorsement_policy,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections,proto3" json:"collections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) Reset() {
+	*m = ApproveChaincodeDefinitionForMyOrgArgs{}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) String() string { return proto.CompactTextString(m) }
+func (*ApproveChaincodeDefinitionForMyOrgArgs) ProtoMessage()    {}
+func (*ApproveChaincodeDefinitionForMyOrgArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{4}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Unmarshal(m, b)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Marshal(b, m, deterministic)
+}
+func (dst *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Merge(dst, src)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_Size() int {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.Size(m)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgArgs proto.InternalMessageInfo
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetEndorsementPolicy() []byte {
+	if m != nil {
+		return m.EndorsementPolicy
+	}
+	return nil
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgArgs) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+// ApproveChaincodeDefinitionForMyOrgResult is the message returned by
+// '+lifecycle.ApproveChaincodeDefinitionForMyOrg'
+type ApproveChaincodeDefinitionForMyOrgResult struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ApproveChaincodeDefinitionForMyOrgResult) Reset() {
+	*m = ApproveChaincodeDefinitionForMyOrgResult{}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) String() string { return proto.CompactTextString(m) }
+func (*ApproveChaincodeDefinitionForMyOrgResult) ProtoMessage()    {}
+func (*ApproveChaincodeDefinitionForMyOrgResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{5}
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Unmarshal(m, b)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Marshal(b, m, deterministic)
+}
+func (dst *ApproveChaincodeDefinitionForMyOrgResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Merge(dst, src)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_Size() int {
+	return xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.Size(m)
+}
+func (m *ApproveChaincodeDefinitionForMyOrgResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApproveChaincodeDefinitionForMyOrgResult proto.InternalMessageInfo
+
+// CommitChaincodeDefinitionArgs is the message used as arguments to
+// '+lifecycle.CommitChaincodeDefinition'
+type CommitChaincodeDefinitionArgs struct {
+	Sequence             int64                           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Name                 string                          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version              string                          `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	EndorsementPolicy    []byte                          `protobuf:"bytes,6,opt,name=endorsement_policy,json=endorsementPolicy,proto3" json:"endorsement_policy,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections,proto3" json:"collections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *CommitChaincodeDefinitionArgs) Reset()         { *m = CommitChaincodeDefinitionArgs{} }
+func (m *CommitChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*CommitChaincodeDefinitionArgs) ProtoMessage()    {}
+func (*CommitChaincodeDefinitionArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{6}
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitChaincodeDefinitionArgs.Unmarshal(m, b)
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitChaincodeDefinitionArgs.Marshal(b, m, deterministic)
+}
+func (dst *CommitChaincodeDefinitionArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitChaincodeDefinitionArgs.Merge(dst, src)
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_Size() int {
+	return xxx_messageInfo_CommitChaincodeDefinitionArgs.Size(m)
+}
+func (m *CommitChaincodeDefinitionArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitChaincodeDefinitionArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitChaincodeDefinitionArgs proto.InternalMessageInfo
+
+func (m *CommitChaincodeDefinitionArgs) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetEndorsementPolicy() []byte {
+	if m != nil {
+		return m.EndorsementPolicy
+	}
+	return nil
+}
+
+func (m *CommitChaincodeDefinitionArgs) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+// CommitChaincodeDefinitionResult is the message returned by
+// '+lifecycle.CommitChaincodeDefinition'
+type CommitChaincodeDefinitionResult struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CommitChaincodeDefinitionResult) Reset()         { *m = CommitChaincodeDefinitionResult{} }
+func (m *CommitChaincodeDefinitionResult) String() string { return proto.CompactTextString(m) }
+func (*CommitChaincodeDefinitionResult) ProtoMessage()    {}
+func (*CommitChaincodeDefinitionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{7}
+}
+func (m *CommitChaincodeDefinitionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CommitChaincodeDefinitionResult.Unmarshal(m, b)
+}
+func (m *CommitChaincodeDefinitionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CommitChaincodeDefinitionResult.Marshal(b, m, deterministic)
+}
+func (dst *CommitChaincodeDefinitionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CommitChaincodeDefinitionResult.Merge(dst, src)
+}
+func (m *CommitChaincodeDefinitionResult) XXX_Size() int {
+	return xxx_messageInfo_CommitChaincodeDefinitionResult.Size(m)
+}
+func (m *CommitChaincodeDefinitionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_CommitChaincodeDefinitionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CommitChaincodeDefinitionResult proto.InternalMessageInfo
+
+// QueryApprovalStatusArgs is the message used as arguments to
+// '+lifecycle.QueryApprovalStatus'
+type QueryApprovalStatusArgs struct {
+	Sequence             int64                           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Name                 string                          `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Version              string                          `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,4,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,5,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	EndorsementPolicy    []byte                          `protobuf:"bytes,6,opt,name=endorsement_policy,json=endorsementPolicy,proto3" json:"endorsement_policy,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,7,opt,name=collections,proto3" json:"collections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *QueryApprovalStatusArgs) Reset()         { *m = QueryApprovalStatusArgs{} }
+func (m *QueryApprovalStatusArgs) String() string { return proto.CompactTextString(m) }
+func (*QueryApprovalStatusArgs) ProtoMessage()    {}
+func (*QueryApprovalStatusArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{8}
+}
+func (m *QueryApprovalStatusArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryApprovalStatusArgs.Unmarshal(m, b)
+}
+func (m *QueryApprovalStatusArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryApprovalStatusArgs.Marshal(b, m, deterministic)
+}
+func (dst *QueryApprovalStatusArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryApprovalStatusArgs.Merge(dst, src)
+}
+func (m *QueryApprovalStatusArgs) XXX_Size() int {
+	return xxx_messageInfo_QueryApprovalStatusArgs.Size(m)
+}
+func (m *QueryApprovalStatusArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryApprovalStatusArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryApprovalStatusArgs proto.InternalMessageInfo
+
+func (m *QueryApprovalStatusArgs) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *QueryApprovalStatusArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *QueryApprovalStatusArgs) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *QueryApprovalStatusArgs) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *QueryApprovalStatusArgs) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *QueryApprovalStatusArgs) GetEndorsementPolicy() []byte {
+	if m != nil {
+		return m.EndorsementPolicy
+	}
+	return nil
+}
+
+func (m *QueryApprovalStatusArgs) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
+// QueryApprovalStatusResult is the message returned by
+// '+lifecycle.QueryApprovalStatus'. It maps the MSP ID of each
+// application org of the channel to whether it has approved the
+// definition.
+type QueryApprovalStatusResult struct {
+	Approved             map[string]bool `protobuf:"bytes,1,rep,name=approved,proto3" json:"approved,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *QueryApprovalStatusResult) Reset()         { *m = QueryApprovalStatusResult{} }
+func (m *QueryApprovalStatusResult) String() string { return proto.CompactTextString(m) }
+func (*QueryApprovalStatusResult) ProtoMessage()    {}
+func (*QueryApprovalStatusResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{9}
+}
+func (m *QueryApprovalStatusResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryApprovalStatusResult.Unmarshal(m, b)
+}
+func (m *QueryApprovalStatusResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryApprovalStatusResult.Marshal(b, m, deterministic)
+}
+func (dst *QueryApprovalStatusResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryApprovalStatusResult.Merge(dst, src)
+}
+func (m *QueryApprovalStatusResult) XXX_Size() int {
+	return xxx_messageInfo_QueryApprovalStatusResult.Size(m)
+}
+func (m *QueryApprovalStatusResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryApprovalStatusResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryApprovalStatusResult proto.InternalMessageInfo
+
+func (m *QueryApprovalStatusResult) GetApproved() map[string]bool {
+	if m != nil {
+		return m.Approved
+	}
+	return nil
+}
+
+// QueryChaincodeDefinitionArgs is the message used as arguments to
+// '+lifecycle.QueryChaincodeDefinition'
+type QueryChaincodeDefinitionArgs struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *QueryChaincodeDefinitionArgs) Reset()         { *m = QueryChaincodeDefinitionArgs{} }
+func (m *QueryChaincodeDefinitionArgs) String() string { return proto.CompactTextString(m) }
+func (*QueryChaincodeDefinitionArgs) ProtoMessage()    {}
+func (*QueryChaincodeDefinitionArgs) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{10}
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryChaincodeDefinitionArgs.Unmarshal(m, b)
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryChaincodeDefinitionArgs.Marshal(b, m, deterministic)
+}
+func (dst *QueryChaincodeDefinitionArgs) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryChaincodeDefinitionArgs.Merge(dst, src)
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_Size() int {
+	return xxx_messageInfo_QueryChaincodeDefinitionArgs.Size(m)
+}
+func (m *QueryChaincodeDefinitionArgs) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryChaincodeDefinitionArgs.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryChaincodeDefinitionArgs proto.InternalMessageInfo
+
+func (m *QueryChaincodeDefinitionArgs) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// QueryChaincodeDefinitionResult is the message returned by
+// '+lifecycle.QueryChaincodeDefinition'
+type QueryChaincodeDefinitionResult struct {
+	Sequence             int64                           `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Version              string                          `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	EndorsementPlugin    string                          `protobuf:"bytes,3,opt,name=endorsement_plugin,json=endorsementPlugin,proto3" json:"endorsement_plugin,omitempty"`
+	ValidationPlugin     string                          `protobuf:"bytes,4,opt,name=validation_plugin,json=validationPlugin,proto3" json:"validation_plugin,omitempty"`
+	EndorsementPolicy    []byte                          `protobuf:"bytes,5,opt,name=endorsement_policy,json=endorsementPolicy,proto3" json:"endorsement_policy,omitempty"`
+	Collections          *common.CollectionConfigPackage `protobuf:"bytes,6,opt,name=collections,proto3" json:"collections,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
+}
+
+func (m *QueryChaincodeDefinitionResult) Reset()         { *m = QueryChaincodeDefinitionResult{} }
+func (m *QueryChaincodeDefinitionResult) String() string { return proto.CompactTextString(m) }
+func (*QueryChaincodeDefinitionResult) ProtoMessage()    {}
+func (*QueryChaincodeDefinitionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_lifecycle_f1b1d853f6980f61, []int{11}
+}
+func (m *QueryChaincodeDefinitionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_QueryChaincodeDefinitionResult.Unmarshal(m, b)
+}
+func (m *QueryChaincodeDefinitionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_QueryChaincodeDefinitionResult.Marshal(b, m, deterministic)
+}
+func (dst *QueryChaincodeDefinitionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_QueryChaincodeDefinitionResult.Merge(dst, src)
+}
+func (m *QueryChaincodeDefinitionResult) XXX_Size() int {
+	return xxx_messageInfo_QueryChaincodeDefinitionResult.Size(m)
+}
+func (m *QueryChaincodeDefinitionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_QueryChaincodeDefinitionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_QueryChaincodeDefinitionResult proto.InternalMessageInfo
+
+func (m *QueryChaincodeDefinitionResult) GetSequence() int64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *QueryChaincodeDefinitionResult) GetVersion() string {
+	if m != nil {
+		return m.Version
+	}
+	return ""
+}
+
+func (m *QueryChaincodeDefinitionResult) GetEndorsementPlugin() string {
+	if m != nil {
+		return m.EndorsementPlugin
+	}
+	return ""
+}
+
+func (m *QueryChaincodeDefinitionResult) GetValidationPlugin() string {
+	if m != nil {
+		return m.ValidationPlugin
+	}
+	return ""
+}
+
+func (m *QueryChaincodeDefinitionResult) GetEndorsementPolicy() []byte {
+	if m != nil {
+		return m.EndorsementPolicy
+	}
+	return nil
+}
+
+func (m *QueryChaincodeDefinitionResult) GetCollections() *common.CollectionConfigPackage {
+	if m != nil {
+		return m.Collections
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*InstallChaincodeArgs)(nil), "lifecycle.InstallChaincodeArgs")
 	proto.RegisterType((*InstallChaincodeResult)(nil), "lifecycle.InstallChaincodeResult")
 	proto.RegisterType((*QueryInstalledChaincodeArgs)(nil), "lifecycle.QueryInstalledChaincodeArgs")
 	proto.RegisterType((*QueryInstalledChaincodeResult)(nil), "lifecycle.QueryInstalledChaincodeResult")
+	proto.RegisterType((*ApproveChaincodeDefinitionForMyOrgArgs)(nil), "lifecycle.ApproveChaincodeDefinitionForMyOrgArgs")
+	proto.RegisterType((*ApproveChaincodeDefinitionForMyOrgResult)(nil), "lifecycle.ApproveChaincodeDefinitionForMyOrgResult")
+	proto.RegisterType((*CommitChaincodeDefinitionArgs)(nil), "lifecycle.CommitChaincodeDefinitionArgs")
+	proto.RegisterType((*CommitChaincodeDefinitionResult)(nil), "lifecycle.CommitChaincodeDefinitionResult")
+	proto.RegisterType((*QueryApprovalStatusArgs)(nil), "lifecycle.QueryApprovalStatusArgs")
+	proto.RegisterType((*QueryApprovalStatusResult)(nil), "lifecycle.QueryApprovalStatusResult")
+	proto.RegisterMapType((map[string]bool)(nil), "lifecycle.QueryApprovalStatusResult.ApprovedEntry")
+	proto.RegisterType((*QueryChaincodeDefinitionArgs)(nil), "lifecycle.QueryChaincodeDefinitionArgs")
+	proto.RegisterType((*QueryChaincodeDefinitionResult)(nil), "lifecycle.QueryChaincodeDefinitionResult")
 }
 
 func init() {
-	proto.RegisterFile("peer/lifecycle/lifecycle.proto", fileDescriptor_lifecycle_f98901bea638af10)
-}
-
-var fileDescriptor_lifecycle_f98901bea638af10 = []byte{
-	// 255 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x91, 0x3f, 0x4f, 0xc3, 0x30,
-	0x10, 0xc5, 0x15, 0x40, 0xa0, 0x9e, 0x3a, 0x59, 0x08, 0x82, 0x10, 0xa8, 0xca, 0xd4, 0xa1, 0xb2,
-	0x87, 0x6e, 0x6c, 0xc0, 0x84, 0x58, 0x20, 0x23, 0x4b, 0xe5, 0x38, 0x57, 0xdb, 0xc2, 0x8d, 0xa3,
-	0x73, 0x82, 0x94, 0x8d, 0x8f, 0x8e, 0x6a, 0xb7, 0xe1, 0x8f, 0xd4, 0x89, 0xed, 0xf9, 0xd9, 0xef,
-	0xfc, 0xd3, 0x3b, 0xb8, 0x6d, 0x11, 0x49, 0x38, 0xbb, 0x46, 0x35, 0x28, 0x87, 0xdf, 0x8a, 0xb7,
-	0xe4, 0x3b, 0xcf, 0x26, 0xa3, 0x51, 0x7c, 0x66, 0x70, 0xfe, 0xd4, 0x84, 0x4e, 0x3a, 0xf7, 0x68,
-	0xa4, 0x6d, 0x94, 0xaf, 0xf1, 0x9e, 0x74, 0x60, 0x0c, 0x4e, 0x1a, 0xb9, 0xc1, 0x3c, 0x9b, 0x65,
-	0xf3, 0x49, 0x19, 0x35, 0xcb, 0xe1, 0xec, 0x03, 0x29, 0x58, 0xdf, 0xe4, 0x47, 0xd1, 0xde, 0x1f,
-	0xd9, 0x1d, 0x5c, 0xa9, 0x7d, 0x7c, 0x65, 0xd3, 0xbc, 0x55, 0x2b, 0xd5, 0xbb, 0xd4, 0x98, 0x1f,
-	0xcf, 0xb2, 0xf9, 0xb4, 0xbc, 0x1c, 0x1f, 0xec, 0xfe, 0x7b, 0x49, 0xd7, 0xc5, 0x02, 0x2e, 0xfe,
-	0x12, 0x94, 0x18, 0x7a, 0xd7, 0x6d, 0x19, 0x8c, 0x0c, 0x26, 0x32, 0x4c, 0xcb, 0xa8, 0x8b, 0x67,
-	0xb8, 0x7e, 0xed, 0x91, 0x86, 0x5d, 0x04, 0xeb, 0x7f, 0x60, 0x17, 0x4b, 0xb8, 0x39, 0x30, 0xec,
-	0x30, 0xc1, 0x83, 0x82, 0x85, 0x27, 0xcd, 0xcd, 0xd0, 0x22, 0x39, 0xac, 0x35, 0x12, 0x5f, 0xcb,
-	0x8a, 0xac, 0x4a, 0xed, 0x06, 0xbe, 0x6d, 0x9f, 0x8f, 0x15, 0xbf, 0x2d, 0xb5, 0xed, 0x4c, 0x5f,
-	0x71, 0xe5, 0x37, 0xe2, 0x47, 0x48, 0xa4, 0x90, 0x48, 0x21, 0xf1, 0x7b, 0x65, 0xd5, 0x69, 0xb4,
-	0x97, 0x5f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd3, 0x6e, 0xec, 0xb6, 0xcb, 0x01, 0x00, 0x00,
+	proto.RegisterFile("peer/lifecycle/lifecycle.proto", fileDescriptor_lifecycle_f1b1d853f6980f61)
+}
+
+var fileDescriptor_lifecycle_f1b1d853f6980f61 = []byte{
+	// 577 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x55, 0x4d, 0x6f, 0xd4, 0x30,
+	0x10, 0x55, 0xb2, 0xfd, 0x9c, 0x16, 0xa9, 0x8d, 0x2a, 0x9a, 0x16, 0xda, 0x2e, 0x39, 0xa0, 0x15,
+	0x94, 0x44, 0xda, 0x5e, 0x50, 0x39, 0x2d, 0x0b, 0x48, 0x08, 0x01, 0x25, 0xdc, 0xb8, 0x54, 0xae,
+	0x33, 0x9b, 0xb5, 0xea, 0xd8, 0xc1, 0x49, 0x56, 0xca, 0x8d, 0x1f, 0xc3, 0x81, 0x2b, 0x27, 0xae,
+	0xfc, 0x34, 0x14, 0x3b, 0xcd, 0x76, 0xab, 0xcd, 0x42, 0xc5, 0xb5, 0x37, 0xdb, 0x33, 0x6f, 0xfc,
+	0xf2, 0xe6, 0xc5, 0x03, 0x87, 0x29, 0xa2, 0x0a, 0x38, 0x1b, 0x21, 0x2d, 0x29, 0xc7, 0xe9, 0xca,
+	0x4f, 0x95, 0xcc, 0xa5, 0xb3, 0xde, 0x1c, 0xec, 0xef, 0x52, 0x99, 0x24, 0x52, 0x04, 0x54, 0x72,
+	0x8e, 0x34, 0x67, 0x52, 0x98, 0x1c, 0xef, 0x9b, 0x05, 0x3b, 0x6f, 0x45, 0x96, 0x13, 0xce, 0x87,
+	0x63, 0xc2, 0x04, 0x95, 0x11, 0x0e, 0x54, 0x9c, 0x39, 0x0e, 0x2c, 0x09, 0x92, 0xa0, 0x6b, 0x75,
+	0xad, 0xde, 0x7a, 0xa8, 0xd7, 0x8e, 0x0b, 0xab, 0x13, 0x54, 0x19, 0x93, 0xc2, 0xb5, 0xf5, 0xf1,
+	0xd5, 0xd6, 0x39, 0x85, 0x3d, 0x7a, 0x05, 0x3f, 0x67, 0xa6, 0xde, 0x79, 0x4a, 0xe8, 0x25, 0x89,
+	0xd1, 0xed, 0x74, 0xad, 0xde, 0x66, 0xb8, 0xdb, 0x24, 0xd4, 0xf7, 0x9d, 0x99, 0xb0, 0x77, 0x0c,
+	0xf7, 0x6f, 0x32, 0x08, 0x31, 0x2b, 0x78, 0x5e, 0x71, 0x18, 0x93, 0x6c, 0xac, 0x39, 0x6c, 0x86,
+	0x7a, 0xed, 0xbd, 0x83, 0x07, 0x9f, 0x0a, 0x54, 0x65, 0x0d, 0xc1, 0xe8, 0x3f, 0x68, 0x7b, 0x27,
+	0x70, 0xd0, 0x52, 0x6c, 0x01, 0x83, 0xdf, 0x36, 0x3c, 0x1e, 0xa4, 0xa9, 0x92, 0x13, 0x6c, 0xd2,
+	0x5f, 0xe1, 0x88, 0x09, 0x56, 0xe9, 0xfa, 0x46, 0xaa, 0xf7, 0xe5, 0x47, 0x15, 0x6b, 0x36, 0xfb,
+	0xb0, 0x96, 0xe1, 0xd7, 0x02, 0x05, 0x35, 0x8c, 0x3a, 0x61, 0xb3, 0x6f, 0x98, 0xda, 0xf3, 0x99,
+	0x76, 0x66, 0x05, 0x7e, 0x06, 0x0e, 0x8a, 0x48, 0xaa, 0x0c, 0x13, 0x14, 0xf9, 0x79, 0xca, 0x8b,
+	0x98, 0x09, 0x77, 0x49, 0x27, 0x6d, 0x5f, 0x8b, 0x9c, 0xe9, 0x80, 0xf3, 0x14, 0xb6, 0x27, 0x84,
+	0xb3, 0x88, 0x54, 0x94, 0xae, 0xb2, 0x97, 0x75, 0xf6, 0xd6, 0x34, 0x50, 0x27, 0xdf, 0xac, 0x2d,
+	0x39, 0xa3, 0xa5, 0xbb, 0xa2, 0x3f, 0x79, 0xa6, 0xb6, 0x0e, 0x38, 0x03, 0xd8, 0x98, 0xda, 0x28,
+	0x73, 0x57, 0xbb, 0x56, 0x6f, 0xa3, 0x7f, 0xe4, 0x1b, 0x87, 0xf9, 0xc3, 0x26, 0x34, 0x94, 0x62,
+	0xc4, 0xe2, 0xba, 0xcb, 0xe1, 0x75, 0x8c, 0xf7, 0x04, 0x7a, 0x7f, 0x57, 0xd0, 0xb4, 0xc0, 0xfb,
+	0x65, 0xc3, 0xc1, 0x50, 0x26, 0x09, 0xcb, 0xe7, 0xe4, 0xde, 0xa9, 0xbc, 0x40, 0xe5, 0x47, 0x70,
+	0xd4, 0x2a, 0x5c, 0x2d, 0xee, 0x4f, 0x1b, 0x76, 0xf5, 0x1f, 0x60, 0xda, 0x41, 0xf8, 0xe7, 0x9c,
+	0xe4, 0x45, 0x76, 0x27, 0xeb, 0x02, 0x59, 0x7f, 0x58, 0xb0, 0x37, 0x47, 0xb3, 0xfa, 0xc5, 0xf8,
+	0x00, 0x6b, 0x44, 0x9f, 0x63, 0xe4, 0x5a, 0xdd, 0x4e, 0x6f, 0xa3, 0xdf, 0xf7, 0xa7, 0x0f, 0x73,
+	0x2b, 0xce, 0x1f, 0xd4, 0xa0, 0xd7, 0x22, 0x57, 0x65, 0xd8, 0xd4, 0xd8, 0x7f, 0x01, 0xf7, 0x66,
+	0x42, 0xce, 0x16, 0x74, 0x2e, 0xb1, 0xac, 0x1f, 0xb8, 0x6a, 0xe9, 0xec, 0xc0, 0xf2, 0x84, 0xf0,
+	0xc2, 0x74, 0x63, 0x2d, 0x34, 0x9b, 0x53, 0xfb, 0xb9, 0xe5, 0xf5, 0xe1, 0xa1, 0xbe, 0xb1, 0xed,
+	0xcf, 0x99, 0xf3, 0x5a, 0x7a, 0xdf, 0x6d, 0x38, 0x6c, 0x03, 0xd5, 0xdf, 0xb8, 0xc8, 0x19, 0xed,
+	0x33, 0x62, 0xbe, 0x0b, 0x3a, 0xb7, 0x72, 0xc1, 0xd2, 0xad, 0x5c, 0xb0, 0xfc, 0x8f, 0x2e, 0x58,
+	0xb9, 0xbd, 0x0b, 0x5e, 0x52, 0x38, 0x96, 0x2a, 0xf6, 0xc7, 0x65, 0x8a, 0x8a, 0x63, 0x14, 0xa3,
+	0xf2, 0x47, 0xe4, 0x42, 0x31, 0x6a, 0x06, 0x6b, 0xe6, 0x57, 0xc3, 0x79, 0xda, 0xf9, 0x2f, 0x27,
+	0x31, 0xcb, 0xc7, 0xc5, 0x45, 0x75, 0x47, 0x70, 0x0d, 0x14, 0x18, 0x50, 0x60, 0x40, 0xc1, 0xec,
+	0x44, 0xbf, 0x58, 0xd1, 0xc7, 0x27, 0x7f, 0x06, 0x00, 0xbf, 0x8c, 0x28, 0xba, 0xea, 0x07, 0x00,
+	0x00,
 }
@@ -277,10 +277,37 @@ func CreateChaincodeProposalWithTxIDAndTransient(typ common.HeaderType, chainID
 	return CreateChaincodeProposalWithTxIDNonceAndTransient(txid, typ, chainID, cis, nonce, creator, transientMap)
 }
 
+// CreateChaincodeQueryProposalWithTxIDAndTransient creates a proposal from
+// given input, which is flagged as a query so that its response is not
+// endorsed. It returns the proposal and the transaction id associated with
+// the proposal
+func CreateChaincodeQueryProposalWithTxIDAndTransient(chainID string, cis *peer.ChaincodeInvocationSpec, creator []byte, txid string, transientMap map[string][]byte) (*peer.Proposal, string, error) {
+	// generate a random nonce
+	nonce, err := crypto.GetRandomNonce()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// compute txid unless provided by tests
+	if txid == "" {
+		txid, err = ComputeTxID(nonce, creator)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	ccHdrExt := &peer.ChaincodeHeaderExtension{ChaincodeId: cis.ChaincodeSpec.ChaincodeId, Query: true}
+	return createChaincodeProposal(txid, common.HeaderType_ENDORSER_TRANSACTION, chainID, cis, nonce, creator, transientMap, ccHdrExt)
+}
+
 // CreateChaincodeProposalWithTxIDNonceAndTransient creates a proposal from
 // given input
 func CreateChaincodeProposalWithTxIDNonceAndTransient(txid string, typ common.HeaderType, chainID string, cis *peer.ChaincodeInvocationSpec, nonce, creator []byte, transientMap map[string][]byte) (*peer.Proposal, string, error) {
 	ccHdrExt := &peer.ChaincodeHeaderExtension{ChaincodeId: cis.ChaincodeSpec.ChaincodeId}
+	return createChaincodeProposal(txid, typ, chainID, cis, nonce, creator, transientMap, ccHdrExt)
+}
+
+func createChaincodeProposal(txid string, typ common.HeaderType, chainID string, cis *peer.ChaincodeInvocationSpec, nonce, creator []byte, transientMap map[string][]byte, ccHdrExt *peer.ChaincodeHeaderExtension) (*peer.Proposal, string, error) {
 	ccHdrExtBytes, err := proto.Marshal(ccHdrExt)
 	if err != nil {
 		return nil, "", errors.Wrap(err, "error marshaling ChaincodeHeaderExtension")
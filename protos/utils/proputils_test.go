@@ -386,6 +386,37 @@ func TestProposalWithTxID(t *testing.T) {
 	assert.NotEmpty(t, txid)
 }
 
+func TestQueryProposal(t *testing.T) {
+	prop, txid, err := utils.CreateChaincodeQueryProposalWithTxIDAndTransient(
+		util.GetTestChainID(),
+		createCIS(),
+		[]byte("creator"),
+		"",
+		nil,
+	)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, txid)
+
+	hdr, err := utils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	chdr, err := utils.UnmarshalChannelHeader(hdr.ChannelHeader)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(common.HeaderType_ENDORSER_TRANSACTION), chdr.Type)
+	assert.Equal(t, txid, chdr.TxId)
+	hdrExt, err := utils.GetChaincodeHeaderExtension(hdr)
+	assert.NoError(t, err)
+	assert.True(t, hdrExt.Query)
+
+	// regular proposals are not flagged as queries
+	prop, _, err = utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, util.GetTestChainID(), createCIS(), []byte("creator"))
+	assert.NoError(t, err)
+	hdr, err = utils.GetHeader(prop.Header)
+	assert.NoError(t, err)
+	hdrExt, err = utils.GetChaincodeHeaderExtension(hdr)
+	assert.NoError(t, err)
+	assert.False(t, hdrExt.Query)
+}
+
 func TestProposalResponse(t *testing.T) {
 	events := &pb.ChaincodeEvent{
 		ChaincodeId: "ccid",